@@ -0,0 +1,166 @@
+package analyzer
+
+import (
+	"sort"
+)
+
+// API change kinds reported in APIChange.Change.
+const (
+	APIAdded   = "added"
+	APIRemoved = "removed"
+	APIChanged = "changed"
+)
+
+// APIChange is one difference between two ExportedAPI snapshots.
+type APIChange struct {
+	Package string `json:"package"`
+	// Symbol is empty for whole packages and qualified by its type for
+	// fields and methods, e.g. "DB.Get".
+	Symbol string `json:"symbol,omitempty"`
+	// Kind is "package", "func", "type", "const", "var", "field" or "method".
+	Kind     string `json:"kind"`
+	Change   string `json:"change"`
+	Old      string `json:"old,omitempty"`
+	New      string `json:"new,omitempty"`
+	Breaking bool   `json:"breaking"`
+}
+
+// DiffAPI compares two ExportedAPI snapshots. Removals and signature changes
+// break callers; additions do not, except methods added to an interface,
+// which break its implementations. Signatures hold types only, so renamed
+// parameters are not changes. Changes are sorted by package, symbol and kind.
+func DiffAPI(oldAPI, newAPI []APIPackage) []APIChange {
+	var changes []APIChange
+	oldPackages := indexBy(oldAPI, func(pkg APIPackage) string { return pkg.Package })
+	newPackages := indexBy(newAPI, func(pkg APIPackage) string { return pkg.Package })
+
+	for path, oldPackage := range oldPackages {
+		newPackage, found := newPackages[path]
+		if !found {
+			changes = append(changes, APIChange{Package: path, Kind: "package", Change: APIRemoved, Breaking: true})
+			continue
+		}
+		changes = append(changes, diffPackage(oldPackage, newPackage)...)
+	}
+	for path := range newPackages {
+		if _, found := oldPackages[path]; !found {
+			changes = append(changes, APIChange{Package: path, Kind: "package", Change: APIAdded})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Package != changes[j].Package {
+			return changes[i].Package < changes[j].Package
+		}
+		if changes[i].Symbol != changes[j].Symbol {
+			return changes[i].Symbol < changes[j].Symbol
+		}
+		return changes[i].Kind < changes[j].Kind
+	})
+	return changes
+}
+
+func diffPackage(oldPackage, newPackage APIPackage) []APIChange {
+	path := oldPackage.Package
+	changes := diffSymbols(path, "", "func", oldPackage.Funcs, newPackage.Funcs)
+	changes = append(changes, diffSymbols(path, "", "const", oldPackage.Consts, newPackage.Consts)...)
+	changes = append(changes, diffSymbols(path, "", "var", oldPackage.Vars, newPackage.Vars)...)
+
+	oldTypes := indexBy(oldPackage.Types, func(apiType APIType) string { return apiType.Name })
+	newTypes := indexBy(newPackage.Types, func(apiType APIType) string { return apiType.Name })
+	for name, oldType := range oldTypes {
+		newType, found := newTypes[name]
+		if !found {
+			changes = append(changes, APIChange{Package: path, Symbol: name, Kind: "type", Change: APIRemoved,
+				Old: oldType.Underlying, Breaking: true})
+			continue
+		}
+		if oldType.Underlying != newType.Underlying {
+			changes = append(changes, APIChange{Package: path, Symbol: name, Kind: "type", Change: APIChanged,
+				Old: oldType.Underlying, New: newType.Underlying, Breaking: true})
+		}
+		changes = append(changes, diffSymbols(path, name+".", "field", oldType.Fields, newType.Fields)...)
+		changes = append(changes, diffMethods(path, oldType, newType)...)
+	}
+	for name, newType := range newTypes {
+		if _, found := oldTypes[name]; !found {
+			changes = append(changes, APIChange{Package: path, Symbol: name, Kind: "type", Change: APIAdded, New: newType.Underlying})
+		}
+	}
+	return changes
+}
+
+// diffSymbols compares same-kind symbols by name: removals and changes break,
+// additions do not.
+func diffSymbols(path, prefix, kind string, oldSymbols, newSymbols []APISymbol) []APIChange {
+	var changes []APIChange
+	oldByName := indexBy(oldSymbols, func(symbol APISymbol) string { return symbol.Name })
+	newByName := indexBy(newSymbols, func(symbol APISymbol) string { return symbol.Name })
+	for name, oldSymbol := range oldByName {
+		change := APIChange{Package: path, Symbol: prefix + name, Kind: kind, Old: oldSymbol.Signature, Breaking: true}
+		newSymbol, found := newByName[name]
+		switch {
+		case !found:
+			change.Change = APIRemoved
+		case newSymbol.Signature != oldSymbol.Signature:
+			change.Change = APIChanged
+			change.New = newSymbol.Signature
+		default:
+			continue
+		}
+		changes = append(changes, change)
+	}
+	for name, newSymbol := range newByName {
+		if _, found := oldByName[name]; !found {
+			changes = append(changes, APIChange{Package: path, Symbol: prefix + name, Kind: kind, Change: APIAdded, New: newSymbol.Signature})
+		}
+	}
+	return changes
+}
+
+// diffMethods compares the methods of a type. Adding a method to an interface
+// breaks its implementations, and a method leaving the value method set
+// breaks callers holding values.
+func diffMethods(path string, oldType, newType APIType) []APIChange {
+	oldSymbols := make([]APISymbol, 0, len(oldType.Methods))
+	for _, method := range oldType.Methods {
+		oldSymbols = append(oldSymbols, method.APISymbol)
+	}
+	newSymbols := make([]APISymbol, 0, len(newType.Methods))
+	for _, method := range newType.Methods {
+		newSymbols = append(newSymbols, method.APISymbol)
+	}
+	changes := diffSymbols(path, oldType.Name+".", "method", oldSymbols, newSymbols)
+	for i := range changes {
+		if changes[i].Change == APIAdded && newType.Kind == "interface" {
+			changes[i].Breaking = true
+		}
+	}
+
+	newMethods := indexBy(newType.Methods, func(method APIMethod) string { return method.Name })
+	for _, oldMethod := range oldType.Methods {
+		newMethod, found := newMethods[oldMethod.Name]
+		if !found || newMethod.Signature != oldMethod.Signature || newMethod.PointerOnly == oldMethod.PointerOnly {
+			continue
+		}
+		change := APIChange{Package: path, Symbol: oldType.Name + "." + oldMethod.Name, Kind: "method", Change: APIChanged,
+			Old: receiverDescription(oldMethod), New: receiverDescription(newMethod), Breaking: newMethod.PointerOnly}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+func receiverDescription(method APIMethod) string {
+	if method.PointerOnly {
+		return "pointer receiver " + method.Signature
+	}
+	return "value receiver " + method.Signature
+}
+
+func indexBy[T any](items []T, key func(T) string) map[string]T {
+	index := make(map[string]T, len(items))
+	for _, item := range items {
+		index[key(item)] = item
+	}
+	return index
+}
@@ -0,0 +1,74 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffAPI(t *testing.T) {
+	oldAPI := []APIPackage{
+		{Package: "mod/gone"},
+		{
+			Package: "mod/store",
+			Funcs:   []APISymbol{{Name: "Open", Signature: "(string)(*mod/store.DB)"}, {Name: "Close", Signature: "()()"}},
+			Types: []APIType{
+				{Name: "DB", Kind: "struct", Underlying: "struct",
+					Fields: []APISymbol{{Name: "Path", Signature: "string"}, {Name: "Size", Signature: "int"}},
+					Methods: []APIMethod{
+						{APISymbol: APISymbol{Name: "Get", Signature: "(string)(int)"}},
+						{APISymbol: APISymbol{Name: "Len", Signature: "()(int)"}},
+					}},
+				{Name: "Reader", Kind: "interface", Underlying: "interface",
+					Methods: []APIMethod{{APISymbol: APISymbol{Name: "Read", Signature: "(string)(int)"}}}},
+				{Name: "Mode", Kind: "other", Underlying: "int"},
+			},
+			Consts: []APISymbol{{Name: "Limit", Signature: "untyped int"}},
+		},
+	}
+	newAPI := []APIPackage{
+		{Package: "mod/fresh"},
+		{
+			Package: "mod/store",
+			// Open is unchanged: parameter names are not part of signatures.
+			Funcs: []APISymbol{{Name: "Open", Signature: "(string)(*mod/store.DB)"}, {Name: "New", Signature: "()(*mod/store.DB)"}},
+			Types: []APIType{
+				{Name: "DB", Kind: "struct", Underlying: "struct",
+					Fields: []APISymbol{{Name: "Path", Signature: "string"}, {Name: "Owner", Signature: "string"}},
+					Methods: []APIMethod{
+						{APISymbol: APISymbol{Name: "Get", Signature: "(string)(int, error)"}},
+						{APISymbol: APISymbol{Name: "Len", Signature: "()(int)"}, PointerOnly: true},
+						{APISymbol: APISymbol{Name: "Reset", Signature: "()()"}},
+					}},
+				{Name: "Reader", Kind: "interface", Underlying: "interface",
+					Methods: []APIMethod{
+						{APISymbol: APISymbol{Name: "Read", Signature: "(string)(int)"}},
+						{APISymbol: APISymbol{Name: "Seek", Signature: "(int)()"}},
+					}},
+				{Name: "Mode", Kind: "other", Underlying: "string"},
+			},
+			Consts: []APISymbol{{Name: "Limit", Signature: "untyped int"}},
+		},
+	}
+
+	want := []APIChange{
+		{Package: "mod/fresh", Kind: "package", Change: APIAdded},
+		{Package: "mod/gone", Kind: "package", Change: APIRemoved, Breaking: true},
+		{Package: "mod/store", Symbol: "Close", Kind: "func", Change: APIRemoved, Old: "()()", Breaking: true},
+		{Package: "mod/store", Symbol: "DB.Get", Kind: "method", Change: APIChanged, Old: "(string)(int)", New: "(string)(int, error)", Breaking: true},
+		{Package: "mod/store", Symbol: "DB.Len", Kind: "method", Change: APIChanged,
+			Old: "value receiver ()(int)", New: "pointer receiver ()(int)", Breaking: true},
+		{Package: "mod/store", Symbol: "DB.Owner", Kind: "field", Change: APIAdded, New: "string"},
+		{Package: "mod/store", Symbol: "DB.Reset", Kind: "method", Change: APIAdded, New: "()()"},
+		{Package: "mod/store", Symbol: "DB.Size", Kind: "field", Change: APIRemoved, Old: "int", Breaking: true},
+		{Package: "mod/store", Symbol: "Mode", Kind: "type", Change: APIChanged, Old: "int", New: "string", Breaking: true},
+		{Package: "mod/store", Symbol: "New", Kind: "func", Change: APIAdded, New: "()(*mod/store.DB)"},
+		{Package: "mod/store", Symbol: "Reader.Seek", Kind: "method", Change: APIAdded, New: "(int)()", Breaking: true},
+	}
+	if got := DiffAPI(oldAPI, newAPI); !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffAPI() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if got := DiffAPI(oldAPI, oldAPI); got != nil {
+		t.Errorf("expected no changes between identical snapshots, got %+v", got)
+	}
+}
@@ -0,0 +1,228 @@
+package analyzer
+
+import (
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// APIPackage is the exported API of one package. Every list is sorted by name.
+type APIPackage struct {
+	Package string      `json:"package"`
+	Funcs   []APISymbol `json:"funcs,omitempty"`
+	Types   []APIType   `json:"types,omitempty"`
+	Consts  []APISymbol `json:"consts,omitempty"`
+	Vars    []APISymbol `json:"vars,omitempty"`
+}
+
+// APISymbol is an exported identifier and its normalized type. Function and
+// method signatures list parameter and result types only, so renaming a
+// parameter does not change them.
+type APISymbol struct {
+	Name      string `json:"name"`
+	Signature string `json:"signature"`
+}
+
+// APIType is an exported named type with its exported fields and methods,
+// including those promoted from embedded fields.
+type APIType struct {
+	Name string `json:"name"`
+	// Kind is "struct", "interface", "alias" or "other".
+	Kind string `json:"kind"`
+	// Underlying is the type definition, e.g. "[T any] struct", "int" or
+	// "= example.com/mod/store.DB" for aliases.
+	Underlying string      `json:"underlying"`
+	Fields     []APISymbol `json:"fields,omitempty"`
+	Methods    []APIMethod `json:"methods,omitempty"`
+}
+
+// APIMethod is an exported method of an APIType.
+type APIMethod struct {
+	APISymbol
+	// PointerOnly marks methods only in the pointer type's method set.
+	PointerOnly bool `json:"pointer_only,omitempty"`
+}
+
+// ExportedAPI returns the exported API of every non-main package matching
+// patterns, as in LayerDef.Packages, or of every package when patterns is
+// empty. Types are written with full package paths, so the result only
+// depends on declarations, not formatting or import names. Packages are
+// sorted by path. Requires NeedTypes.
+func ExportedAPI(pkgs []*packages.Package, patterns []string) []APIPackage {
+	var api []APIPackage
+	for _, pkg := range pkgs {
+		if pkg.Types == nil || pkg.Name == "main" || strings.HasSuffix(pkg.PkgPath, "_test") {
+			continue
+		}
+		if len(patterns) > 0 && !MatchesAnyPackagePattern(patterns, pkg.PkgPath) {
+			continue
+		}
+		api = append(api, packageAPI(pkg.Types))
+	}
+	sort.Slice(api, func(i, j int) bool { return api[i].Package < api[j].Package })
+	return api
+}
+
+func packageAPI(pkg *types.Package) APIPackage {
+	api := APIPackage{Package: pkg.Path()}
+	scope := pkg.Scope()
+	// Scope names are sorted, so every list is too.
+	for _, name := range scope.Names() {
+		object := scope.Lookup(name)
+		if !object.Exported() {
+			continue
+		}
+		switch object := object.(type) {
+		case *types.Func:
+			api.Funcs = append(api.Funcs, APISymbol{Name: name, Signature: funcSignature(object)})
+		case *types.TypeName:
+			api.Types = append(api.Types, typeAPI(object))
+		case *types.Const:
+			api.Consts = append(api.Consts, APISymbol{Name: name, Signature: apiTypeString(object.Type())})
+		case *types.Var:
+			api.Vars = append(api.Vars, APISymbol{Name: name, Signature: apiTypeString(object.Type())})
+		}
+	}
+	return api
+}
+
+func typeAPI(typeName *types.TypeName) APIType {
+	api := APIType{Name: typeName.Name(), Kind: "other"}
+	if typeName.IsAlias() {
+		api.Kind = "alias"
+		api.Underlying = "= " + apiTypeString(types.Unalias(typeName.Type()))
+		return api
+	}
+
+	named, isNamed := typeName.Type().(*types.Named)
+	if !isNamed {
+		return api
+	}
+	typeParams := typeParamsString(named.TypeParams())
+	if typeParams != "" {
+		typeParams += " "
+	}
+	switch underlying := named.Underlying().(type) {
+	case *types.Struct:
+		api.Kind = "struct"
+		api.Underlying = typeParams + "struct"
+		api.Fields = exportedFields(underlying)
+	case *types.Interface:
+		api.Kind = "interface"
+		api.Underlying = typeParams + "interface"
+	default:
+		api.Underlying = typeParams + apiTypeString(underlying)
+	}
+	api.Methods = exportedMethods(named)
+	return api
+}
+
+// exportedMethods lists the exported methods of named's pointer method set,
+// which includes the value method set and methods promoted from embedded
+// fields. For interfaces it lists the interface's methods, embedded ones included.
+func exportedMethods(named *types.Named) []APIMethod {
+	var methods []APIMethod
+	if iface, isInterface := named.Underlying().(*types.Interface); isInterface {
+		for i := range iface.NumMethods() {
+			if method := iface.Method(i); method.Exported() {
+				methods = append(methods, APIMethod{APISymbol: APISymbol{Name: method.Name(), Signature: funcSignature(method)}})
+			}
+		}
+	} else {
+		valueMethods := types.NewMethodSet(named)
+		pointerMethods := types.NewMethodSet(types.NewPointer(named))
+		for i := range pointerMethods.Len() {
+			method := pointerMethods.At(i).Obj().(*types.Func)
+			if !method.Exported() {
+				continue
+			}
+			methods = append(methods, APIMethod{
+				APISymbol:   APISymbol{Name: method.Name(), Signature: funcSignature(method)},
+				PointerOnly: valueMethods.Lookup(method.Pkg(), method.Name()) == nil,
+			})
+		}
+	}
+	sort.Slice(methods, func(i, j int) bool { return methods[i].Name < methods[j].Name })
+	return methods
+}
+
+// exportedFields lists the exported fields of structType, including fields
+// promoted from embedded structs. As in Go's selector rules, a shallower field
+// hides deeper ones of the same name, and names found more than once at the
+// same depth are ambiguous and left out.
+func exportedFields(structType *types.Struct) []APISymbol {
+	var fields []APISymbol
+	seen := make(map[string]bool)
+	visited := make(map[*types.Named]bool)
+
+	level := []*types.Struct{structType}
+	for len(level) > 0 {
+		found := make(map[string][]*types.Var)
+		var next []*types.Struct
+		for _, current := range level {
+			for i := range current.NumFields() {
+				field := current.Field(i)
+				if !seen[field.Name()] {
+					found[field.Name()] = append(found[field.Name()], field)
+				}
+				if !field.Embedded() {
+					continue
+				}
+				if embedded, named := embeddedStruct(field.Type()); embedded != nil && (named == nil || !visited[named]) {
+					if named != nil {
+						visited[named] = true
+					}
+					next = append(next, embedded)
+				}
+			}
+		}
+		for name, candidates := range found {
+			seen[name] = true
+			if len(candidates) == 1 && candidates[0].Exported() {
+				fields = append(fields, APISymbol{Name: name, Signature: apiTypeString(candidates[0].Type())})
+			}
+		}
+		level = next
+	}
+
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Name < fields[j].Name })
+	return fields
+}
+
+// embeddedStruct returns the struct an embedded field of type fieldType
+// promotes fields from, and its named type when it has one.
+func embeddedStruct(fieldType types.Type) (*types.Struct, *types.Named) {
+	if pointer, isPointer := fieldType.(*types.Pointer); isPointer {
+		fieldType = pointer.Elem()
+	}
+	fieldType = types.Unalias(fieldType)
+	named, _ := fieldType.(*types.Named)
+	structType, _ := fieldType.Underlying().(*types.Struct)
+	return structType, named
+}
+
+// funcSignature returns a function's type parameters followed by its
+// parameter and result types, e.g. "[T any](string, ...int)(T, error)".
+func funcSignature(function *types.Func) string {
+	signature := function.Type().(*types.Signature)
+	return typeParamsString(signature.TypeParams()) + signatureString(signature)
+}
+
+func typeParamsString(typeParams *types.TypeParamList) string {
+	if typeParams.Len() == 0 {
+		return ""
+	}
+	parts := make([]string, typeParams.Len())
+	for i := range typeParams.Len() {
+		typeParam := typeParams.At(i)
+		parts[i] = typeParam.Obj().Name() + " " + apiTypeString(typeParam.Constraint())
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// apiTypeString writes a type qualified by full package paths.
+func apiTypeString(typ types.Type) string {
+	return types.TypeString(typ, func(pkg *types.Package) string { return pkg.Path() })
+}
@@ -0,0 +1,92 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExportedAPI(t *testing.T) {
+	pkgs := loadDeadCodeModule(t, map[string]string{
+		"store/store.go": `package store
+
+import "io"
+
+const Version = "1.0"
+
+var Default *DB
+
+type base struct {
+	ID   int
+	Name string
+}
+
+func (base) Describe() string { return "" }
+
+type audit struct{ Name string }
+
+// DB embeds two structs whose Name fields are ambiguous, so only ID is promoted.
+type DB struct {
+	base
+	*audit
+	io.Closer
+	Path  string
+	cache map[string]int
+}
+
+func (db *DB) Get(key string, fallback ...int) (int, error) { return 0, nil }
+
+func (db *DB) internal() {}
+
+type Reader interface {
+	io.Reader
+	Lookup(key string) bool
+}
+
+type Set[T comparable] struct{ items map[T]bool }
+
+func (s Set[T]) Has(item T) bool { return s.items[item] }
+
+type Path = string
+
+type Mode int
+
+func Open[T any](path string, options ...T) (*DB, error) { return nil, nil }
+
+func helper() {}
+`,
+		"cmd/main.go":    "package main\n\nfunc main() {}\n",
+		"other/other.go": "package other\n\nfunc Other() {}\n",
+	}, false)
+
+	want := []APIPackage{{
+		Package: "deadmod/store",
+		Funcs:   []APISymbol{{Name: "Open", Signature: "[T any](string, ...T)(*deadmod/store.DB, error)"}},
+		Types: []APIType{
+			{Name: "DB", Kind: "struct", Underlying: "struct",
+				Fields: []APISymbol{{Name: "Closer", Signature: "io.Closer"}, {Name: "ID", Signature: "int"}, {Name: "Path", Signature: "string"}},
+				Methods: []APIMethod{
+					{APISymbol: APISymbol{Name: "Close", Signature: "()(error)"}},
+					{APISymbol: APISymbol{Name: "Describe", Signature: "()(string)"}},
+					{APISymbol: APISymbol{Name: "Get", Signature: "(string, ...int)(int, error)"}, PointerOnly: true},
+				}},
+			{Name: "Mode", Kind: "other", Underlying: "int"},
+			{Name: "Path", Kind: "alias", Underlying: "= string"},
+			{Name: "Reader", Kind: "interface", Underlying: "interface",
+				Methods: []APIMethod{
+					{APISymbol: APISymbol{Name: "Lookup", Signature: "(string)(bool)"}},
+					{APISymbol: APISymbol{Name: "Read", Signature: "([]byte)(int, error)"}},
+				}},
+			{Name: "Set", Kind: "struct", Underlying: "[T comparable] struct",
+				Methods: []APIMethod{{APISymbol: APISymbol{Name: "Has", Signature: "(T)(bool)"}}}},
+		},
+		Consts: []APISymbol{{Name: "Version", Signature: "untyped string"}},
+		Vars:   []APISymbol{{Name: "Default", Signature: "*deadmod/store.DB"}},
+	}}
+
+	if got := ExportedAPI(pkgs, []string{"deadmod/store"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("ExportedAPI() =\n%+v\nwant\n%+v", got, want)
+	}
+	if got := ExportedAPI(pkgs, nil); len(got) != 2 || got[0].Package != "deadmod/other" {
+		t.Errorf("expected every non-main package without patterns, got %+v", got)
+	}
+}
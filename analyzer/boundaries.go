@@ -0,0 +1,147 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Desgue/codegraph/graph"
+	"golang.org/x/tools/go/packages"
+)
+
+// Boundary suggestion kinds reported in BoundarySuggestion.Kind.
+const (
+	// SuggestNarrowInternal: an exported symbol of an internal package is used
+	// by a single package outside the internal package's subtree, so it may
+	// belong in that consumer instead.
+	SuggestNarrowInternal = "internal-single-consumer"
+	// SuggestMoveToInternal: a package outside every internal directory is
+	// imported by a single other package, so it could move under internal/.
+	SuggestMoveToInternal = "single-importer"
+)
+
+// BoundarySuggestion is a hint that a package boundary may be drawn in the wrong place.
+type BoundarySuggestion struct {
+	Kind    string `json:"kind"`
+	Package string `json:"package"`
+	// Symbol is the exported identifier, qualified by its receiver type for
+	// methods. Empty for SuggestMoveToInternal.
+	Symbol string `json:"symbol,omitempty"`
+	// Consumer is the only package using Symbol or importing Package.
+	Consumer string `json:"consumer"`
+	// Score ranks suggestions from 0 to 1. For SuggestNarrowInternal it is the
+	// share of the package's externally used symbols whose only consumer is
+	// Consumer; a SuggestMoveToInternal suggestion always scores 1.
+	Score float64 `json:"score"`
+}
+
+// SuggestBoundaries reports exported symbols of internal packages that only
+// one package outside their subtree uses, and non-main packages outside any
+// internal directory that only one other package imports. Import edges come
+// from g, symbol references from pkgs; references in test files are ignored.
+// Suggestions are sorted by descending score, then package and symbol.
+// Requires NeedSyntax, NeedTypes and NeedTypesInfo.
+func SuggestBoundaries(g *graph.Graph, pkgs []*packages.Package) []BoundarySuggestion {
+	var suggestions []BoundarySuggestion
+
+	consumers := internalSymbolConsumers(pkgs)
+	for pkgPath, symbols := range consumers {
+		soleConsumers := make(map[string]int)
+		for _, users := range symbols {
+			if len(users) == 1 {
+				soleConsumers[soleKey(users)]++
+			}
+		}
+		for symbol, users := range symbols {
+			if len(users) != 1 {
+				continue
+			}
+			consumer := soleKey(users)
+			suggestions = append(suggestions, BoundarySuggestion{
+				Kind:     SuggestNarrowInternal,
+				Package:  pkgPath,
+				Symbol:   symbol,
+				Consumer: consumer,
+				Score:    float64(soleConsumers[consumer]) / float64(len(symbols)),
+			})
+		}
+	}
+
+	importKinds := []graph.EdgeKind{graph.EdgeImport}
+	for _, node := range g.Nodes() {
+		if node.Kind != graph.KindPackage || node.HasMainFunc || isInternalPackage(node.ID) {
+			continue
+		}
+		if importers := g.Neighbors(node.ID, graph.Incoming, importKinds); len(importers) == 1 {
+			suggestions = append(suggestions, BoundarySuggestion{
+				Kind:     SuggestMoveToInternal,
+				Package:  node.ID,
+				Consumer: importers[0],
+				Score:    1,
+			})
+		}
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		if suggestions[i].Package != suggestions[j].Package {
+			return suggestions[i].Package < suggestions[j].Package
+		}
+		return suggestions[i].Symbol < suggestions[j].Symbol
+	})
+	return suggestions
+}
+
+// internalSymbolConsumers maps each internal package path to its exported
+// symbols used outside the package's subtree, and each symbol to the set of
+// packages using it.
+func internalSymbolConsumers(pkgs []*packages.Package) map[string]map[string]map[string]bool {
+	consumers := make(map[string]map[string]map[string]bool)
+	for _, pkg := range pkgs {
+		if strings.HasSuffix(pkg.PkgPath, "_test") || pkg.TypesInfo == nil {
+			continue
+		}
+		for ident, object := range pkg.TypesInfo.Uses {
+			if object.Pkg() == nil || !object.Exported() || !isInternalPackage(object.Pkg().Path()) {
+				continue
+			}
+			target := object.Pkg().Path()
+			if pkg.PkgPath == target || strings.HasPrefix(pkg.PkgPath, target+"/") {
+				continue
+			}
+			if strings.HasSuffix(pkg.Fset.Position(ident.Pos()).Filename, "_test.go") {
+				continue
+			}
+
+			symbol := strings.TrimPrefix(objectKey(object), target+".")
+			if consumers[target] == nil {
+				consumers[target] = make(map[string]map[string]bool)
+			}
+			if consumers[target][symbol] == nil {
+				consumers[target][symbol] = make(map[string]bool)
+			}
+			consumers[target][symbol][pkg.PkgPath] = true
+		}
+	}
+	return consumers
+}
+
+// soleKey returns the only key of a single-element set.
+func soleKey(set map[string]bool) string {
+	for key := range set {
+		return key
+	}
+	return ""
+}
+
+// isInternalPackage reports whether pkgPath has an "internal" path element,
+// which makes the compiler restrict who may import it.
+func isInternalPackage(pkgPath string) bool {
+	for _, element := range strings.Split(pkgPath, "/") {
+		if element == "internal" {
+			return true
+		}
+	}
+	return false
+}
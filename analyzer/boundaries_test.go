@@ -0,0 +1,53 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+	"github.com/Desgue/codegraph/parser"
+)
+
+func TestSuggestBoundaries(t *testing.T) {
+	pkgs := loadDeadCodeModule(t, map[string]string{
+		"internal/store/store.go":   "package store\n\ntype DB struct{}\n\nfunc Open() *DB { return nil }\n\nfunc Close() {}\n\nfunc Shared() {}\n",
+		"internal/store/sub/sub.go": "package sub\n\nimport \"deadmod/internal/store\"\n\nfunc Run() { store.Shared() }\n",
+		"api/api.go": "package api\n\nimport (\n\t\"deadmod/internal/store\"\n\t\"deadmod/util\"\n)\n\n" +
+			"var db *store.DB = store.Open()\n\nfunc Serve() { store.Shared(); util.Help() }\n",
+		"util/util.go": "package util\n\nfunc Help() {}\n",
+		"cmd/main.go": "package main\n\nimport (\n\t\"deadmod/api\"\n\t\"deadmod/internal/store\"\n)\n\n" +
+			"func main() { api.Serve(); store.Shared(); store.Close() }\n",
+		// Test references never count as consumers.
+		"cmd/main_test.go": "package main\n\nimport (\n\t\"testing\"\n\n\t\"deadmod/internal/store\"\n)\n\nfunc TestOpen(t *testing.T) { store.Open() }\n",
+	}, true)
+
+	g := graph.New()
+	for _, pkg := range pkgs {
+		node := &graph.Node{ID: pkg.PkgPath, Kind: graph.KindPackage, HasMainFunc: parser.HasMainFunc(pkg)}
+		if err := g.AddNode(node); err != nil {
+			t.Fatalf("AddNode(%q) failed: %v", pkg.PkgPath, err)
+		}
+	}
+	for _, pkg := range pkgs {
+		for importPath := range pkg.Imports {
+			if _, found := g.Node(importPath); found {
+				if err := g.AddEdge(&graph.Edge{From: pkg.PkgPath, To: importPath, Kind: graph.EdgeImport}); err != nil {
+					t.Fatalf("AddEdge() failed: %v", err)
+				}
+			}
+		}
+	}
+
+	// store's externally used symbols are DB, Open, Shared and Close; api is
+	// the only consumer of two of them, cmd of one, and both use Shared.
+	want := []BoundarySuggestion{
+		{Kind: SuggestMoveToInternal, Package: "deadmod/api", Consumer: "deadmod/cmd", Score: 1},
+		{Kind: SuggestMoveToInternal, Package: "deadmod/util", Consumer: "deadmod/api", Score: 1},
+		{Kind: SuggestNarrowInternal, Package: "deadmod/internal/store", Symbol: "DB", Consumer: "deadmod/api", Score: 0.5},
+		{Kind: SuggestNarrowInternal, Package: "deadmod/internal/store", Symbol: "Open", Consumer: "deadmod/api", Score: 0.5},
+		{Kind: SuggestNarrowInternal, Package: "deadmod/internal/store", Symbol: "Close", Consumer: "deadmod/cmd", Score: 0.25},
+	}
+	if got := SuggestBoundaries(g, pkgs); !reflect.DeepEqual(got, want) {
+		t.Errorf("SuggestBoundaries() =\n%+v\nwant\n%+v", got, want)
+	}
+}
@@ -0,0 +1,141 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"github.com/Desgue/codegraph/graph"
+	"golang.org/x/tools/go/packages"
+)
+
+// NoConstructorDirective suppresses a missing constructor finding when it
+// appears in the type's doc or line comment.
+const NoConstructorDirective = "//codegraph:no-constructor"
+
+// AttributeMissingConstructors lists, sorted and comma-separated, the types
+// of a package node that FindMissingConstructors reports. The graph has no
+// type nodes, so the finding is recorded on the declaring package.
+const AttributeMissingConstructors = "missing_constructors"
+
+// MissingConstructor is an exported struct type with unexported fields and no
+// constructor, so other packages can only build it as a zero value.
+type MissingConstructor struct {
+	Package string `json:"package"`
+	Type    string `json:"type"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+}
+
+// FindMissingConstructors reports the exported struct types with at least one
+// unexported field for which their package declares no function named New...
+// or Must... returning the type or a pointer to it. Types in test files,
+// external test packages and main packages, and types marked with
+// NoConstructorDirective, are never reported. Findings are sorted by file and
+// line. Requires NeedSyntax, NeedTypes and NeedTypesInfo.
+func FindMissingConstructors(pkgs []*packages.Package) []MissingConstructor {
+	var missing []MissingConstructor
+	for _, pkg := range pkgs {
+		if pkg.Name == "main" || strings.HasSuffix(pkg.Name, "_test") {
+			continue
+		}
+		constructed := make(map[*types.TypeName]bool)
+		var candidates []*ast.TypeSpec
+		for _, file := range pkg.Syntax {
+			if strings.HasSuffix(pkg.Fset.Position(file.Pos()).Filename, "_test.go") {
+				continue
+			}
+			for _, declaration := range file.Decls {
+				switch declaration := declaration.(type) {
+				case *ast.FuncDecl:
+					markConstructed(pkg, declaration, constructed)
+				case *ast.GenDecl:
+					if declaration.Tok != token.TYPE || hasDirective(declaration.Doc, NoConstructorDirective) {
+						continue
+					}
+					for _, spec := range declaration.Specs {
+						typeSpec := spec.(*ast.TypeSpec)
+						if typeSpec.Name.IsExported() && !hasDirective(typeSpec.Doc, NoConstructorDirective) &&
+							!hasDirective(typeSpec.Comment, NoConstructorDirective) {
+							candidates = append(candidates, typeSpec)
+						}
+					}
+				}
+			}
+		}
+
+		for _, typeSpec := range candidates {
+			typeName, isTypeName := pkg.TypesInfo.Defs[typeSpec.Name].(*types.TypeName)
+			if !isTypeName || typeName.IsAlias() || constructed[typeName] || !hasUnexportedField(typeName) {
+				continue
+			}
+			position := pkg.Fset.Position(typeSpec.Name.Pos())
+			missing = append(missing, MissingConstructor{Package: pkg.PkgPath, Type: typeSpec.Name.Name,
+				File: position.Filename, Line: position.Line})
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool {
+		if missing[i].File != missing[j].File {
+			return missing[i].File < missing[j].File
+		}
+		return missing[i].Line < missing[j].Line
+	})
+	return missing
+}
+
+// markConstructed records the package's named types that declaration, when it
+// is a New... or Must... function, returns directly or through a pointer.
+func markConstructed(pkg *packages.Package, declaration *ast.FuncDecl, constructed map[*types.TypeName]bool) {
+	name := declaration.Name.Name
+	if declaration.Recv != nil || !(strings.HasPrefix(name, "New") || strings.HasPrefix(name, "Must")) {
+		return
+	}
+	function, isFunction := pkg.TypesInfo.Defs[declaration.Name].(*types.Func)
+	if !isFunction {
+		return
+	}
+	results := function.Type().(*types.Signature).Results()
+	for i := range results.Len() {
+		resultType := results.At(i).Type()
+		if pointer, isPointer := resultType.(*types.Pointer); isPointer {
+			resultType = pointer.Elem()
+		}
+		if named, isNamed := resultType.(*types.Named); isNamed {
+			constructed[named.Origin().Obj()] = true
+		}
+	}
+}
+
+// hasUnexportedField reports whether typeName is a struct type with an
+// unexported field, embedded fields included.
+func hasUnexportedField(typeName *types.TypeName) bool {
+	structType, isStruct := typeName.Type().Underlying().(*types.Struct)
+	if !isStruct {
+		return false
+	}
+	for i := range structType.NumFields() {
+		if !structType.Field(i).Exported() {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyMissingConstructors sets AttributeMissingConstructors on the package
+// nodes of g declaring a type in missing.
+func ApplyMissingConstructors(g *graph.Graph, missing []MissingConstructor) {
+	typeNames := make(map[string][]string)
+	for _, finding := range missing {
+		typeNames[finding.Package] = append(typeNames[finding.Package], finding.Type)
+	}
+	for id, names := range typeNames {
+		node, found := g.Node(id)
+		if !found || node.Kind != graph.KindPackage {
+			continue
+		}
+		sort.Strings(names)
+		node.SetAttribute(AttributeMissingConstructors, strings.Join(names, ","))
+	}
+}
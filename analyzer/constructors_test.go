@@ -0,0 +1,70 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+func TestFindMissingConstructors(t *testing.T) {
+	pkgs := loadDeadCodeModule(t, map[string]string{
+		"store/store.go": `package store
+
+type Store struct{ path string }
+
+type Cache struct{ entries map[string]string }
+
+func NewCache() *Cache { return &Cache{} }
+
+type Pool[T any] struct{ items []T }
+
+func MustPool[T any]() Pool[T] { return Pool[T]{} }
+
+type Options struct{ Path string }
+
+type Handle struct {
+	Options
+	id int
+}
+
+// Legacy is built by hand.
+//
+//codegraph:no-constructor
+type Legacy struct{ state int }
+
+type client struct{ conn string }
+
+type Alias = Store
+
+func Open() *Handle { return &Handle{} }
+`,
+		"store/store_test.go": `package store
+
+type Fixture struct{ name string }
+`,
+	}, true)
+
+	missing := FindMissingConstructors(pkgs)
+
+	var got []string
+	for _, finding := range missing {
+		got = append(got, finding.Package+"."+finding.Type)
+	}
+	want := []string{"deadmod/store.Store", "deadmod/store.Handle"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("missing constructors = %v, want %v", got, want)
+	}
+	if missing[0].Line != 3 || filepath.Base(missing[0].File) != "store.go" {
+		t.Errorf("first finding at %s:%d, want store.go:3", missing[0].File, missing[0].Line)
+	}
+
+	g := graph.New()
+	g.AddNode(&graph.Node{ID: "deadmod/store", Kind: graph.KindPackage})
+	ApplyMissingConstructors(g, missing)
+	store, _ := g.Node("deadmod/store")
+	if value := store.Attributes[AttributeMissingConstructors]; value != "Handle,Store" {
+		t.Errorf("store %s = %q", AttributeMissingConstructors, value)
+	}
+}
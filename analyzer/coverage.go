@@ -0,0 +1,124 @@
+package analyzer
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/Desgue/codegraph/extract"
+	"github.com/Desgue/codegraph/graph"
+	"golang.org/x/tools/cover"
+)
+
+// Attribute names under which ApplyCoverage records statement coverage.
+// AttributeCoverage is the percentage of statements executed, with one
+// decimal; AttributeNotCovered is "true" on nodes the profile never mentions.
+const (
+	AttributeCoverage   = "coverage_pct"
+	AttributeNotCovered = "not_covered"
+)
+
+// ApplyCoverage sets AttributeCoverage on every package node from the blocks
+// of go test -coverprofile profiles. A profile file belongs to a package when
+// it is named import/path/file.go or by the absolute path of one of the
+// package's files. Function nodes carrying extract.AttributeStartLine and
+// extract.AttributeEndLine, as extract.CallExtractor emits them, get the
+// coverage of the blocks of their file starting within those lines. Packages
+// and functions without a block in the profile get 0 and AttributeNotCovered.
+// It returns the sorted profile file names matching no package, typically
+// generated code or packages outside the load.
+func ApplyCoverage(g *graph.Graph, profiles []*cover.Profile) (unmatched []string) {
+	packageOf := make(map[string]string)
+	functionsOf := make(map[string][]functionLines)
+	for _, node := range g.Nodes() {
+		switch node.Kind {
+		case graph.KindPackage:
+			for _, file := range node.Files {
+				packageOf[path.Join(node.ID, filepath.Base(file))] = node.ID
+				packageOf[file] = node.ID
+			}
+		case graph.KindFunction:
+			function, found := functionLinesOf(node)
+			if !found {
+				continue
+			}
+			_, parts, _ := graph.ParseID(node.ID)
+			for _, key := range []string{path.Join(parts[0], filepath.Base(node.Files[0])), node.Files[0]} {
+				functionsOf[key] = append(functionsOf[key], function)
+			}
+		}
+	}
+
+	statements := make(map[string]int)
+	covered := make(map[string]int)
+	add := func(id string, block cover.ProfileBlock) {
+		statements[id] += block.NumStmt
+		if block.Count > 0 {
+			covered[id] += block.NumStmt
+		}
+	}
+	for _, profile := range profiles {
+		id, found := packageOf[profile.FileName]
+		if !found {
+			unmatched = append(unmatched, profile.FileName)
+			continue
+		}
+		if _, seen := statements[id]; !seen {
+			statements[id] = 0
+		}
+		functions := functionsOf[profile.FileName]
+		for _, block := range profile.Blocks {
+			add(id, block)
+			for _, function := range functions {
+				if function.start <= block.StartLine && block.StartLine <= function.end {
+					add(function.node.ID, block)
+					break
+				}
+			}
+		}
+	}
+
+	for _, node := range g.Nodes() {
+		measured := node.Kind == graph.KindPackage
+		if !measured {
+			_, measured = functionLinesOf(node)
+		}
+		if !measured {
+			continue
+		}
+		total, found := statements[node.ID]
+		if !found {
+			node.SetAttribute(AttributeCoverage, "0")
+			node.SetAttribute(AttributeNotCovered, "true")
+			continue
+		}
+		percent := 0.0
+		if total > 0 {
+			percent = 100 * float64(covered[node.ID]) / float64(total)
+		}
+		node.SetAttribute(AttributeCoverage, strconv.FormatFloat(percent, 'f', 1, 64))
+	}
+
+	sort.Strings(unmatched)
+	return unmatched
+}
+
+// functionLines is the line range of a function node's declaration.
+type functionLines struct {
+	node       *graph.Node
+	start, end int
+}
+
+// functionLinesOf returns the line range recorded on a function node, if any.
+func functionLinesOf(node *graph.Node) (functionLines, bool) {
+	if node.Kind != graph.KindFunction || len(node.Files) == 0 {
+		return functionLines{}, false
+	}
+	start, startErr := strconv.Atoi(node.Attributes[extract.AttributeStartLine])
+	end, endErr := strconv.Atoi(node.Attributes[extract.AttributeEndLine])
+	if startErr != nil || endErr != nil {
+		return functionLines{}, false
+	}
+	return functionLines{node: node, start: start, end: end}, true
+}
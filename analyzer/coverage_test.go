@@ -0,0 +1,117 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Desgue/codegraph/extract"
+	"github.com/Desgue/codegraph/graph"
+	"golang.org/x/tools/cover"
+)
+
+func TestApplyCoverage(t *testing.T) {
+	g := graph.New()
+	nodes := []*graph.Node{
+		{ID: "example.com/mod/api", Kind: graph.KindPackage, Files: []string{"/src/api/api.go", "/src/api/routes.go"}},
+		{ID: "example.com/mod/store", Kind: graph.KindPackage, Files: []string{"/src/store/store.go"}},
+		{ID: "example.com/mod/cmd", Kind: graph.KindPackage, Files: []string{"/src/cmd/main.go"}},
+	}
+	for _, node := range nodes {
+		if err := g.AddNode(node); err != nil {
+			t.Fatalf("AddNode(%q) failed: %v", node.ID, err)
+		}
+	}
+
+	profiles, err := cover.ParseProfilesFromReader(strings.NewReader(`mode: set
+example.com/mod/api/api.go:3.14,5.2 3 1
+example.com/mod/api/api.go:7.14,9.2 2 0
+example.com/mod/api/routes.go:3.14,5.2 3 1
+/src/store/store.go:3.14,5.2 4 0
+example.com/mod/gen/gen.go:3.14,5.2 1 1
+`))
+	if err != nil {
+		t.Fatalf("ParseProfilesFromReader() error = %v", err)
+	}
+
+	unmatched := ApplyCoverage(g, profiles)
+
+	if want := []string{"example.com/mod/gen/gen.go"}; !reflect.DeepEqual(unmatched, want) {
+		t.Errorf("unmatched = %v, want %v", unmatched, want)
+	}
+	want := map[string]map[string]string{
+		"example.com/mod/api":   {AttributeCoverage: "75.0"},
+		"example.com/mod/store": {AttributeCoverage: "0.0"},
+		"example.com/mod/cmd":   {AttributeCoverage: "0", AttributeNotCovered: "true"},
+	}
+	for id, wantAttributes := range want {
+		node, _ := g.Node(id)
+		if !reflect.DeepEqual(node.Attributes, wantAttributes) {
+			t.Errorf("%s attributes = %v, want %v", id, node.Attributes, wantAttributes)
+		}
+	}
+}
+
+func TestApplyCoverage_Functions(t *testing.T) {
+	function := func(pkgPath, name, file, start, end string) *graph.Node {
+		node := &graph.Node{ID: graph.FuncID(pkgPath, "", name), Kind: graph.KindFunction, Name: name, Files: []string{file}}
+		node.SetAttribute(extract.AttributeStartLine, start)
+		node.SetAttribute(extract.AttributeEndLine, end)
+		return node
+	}
+	g := graph.New()
+	nodes := []*graph.Node{
+		{ID: "example.com/mod/api", Kind: graph.KindPackage, Files: []string{"/src/api/api.go", "/src/api/routes.go"}},
+		{ID: "example.com/mod/store", Kind: graph.KindPackage, Files: []string{"/src/store/store.go"}},
+		function("example.com/mod/api", "Get", "/src/api/api.go", "3", "6"),
+		function("example.com/mod/api", "Put", "/src/api/api.go", "8", "10"),
+		function("example.com/mod/api", "Route", "/src/api/routes.go", "3", "5"),
+		function("example.com/mod/store", "Open", "/src/store/store.go", "3", "5"),
+		// A function node without a line range, as SymbolExtractor emits.
+		{ID: graph.FuncID("example.com/mod/api", "", "Delete"), Kind: graph.KindFunction, Files: []string{"/src/api/api.go"}},
+	}
+	for _, node := range nodes {
+		if err := g.AddNode(node); err != nil {
+			t.Fatalf("AddNode(%q) failed: %v", node.ID, err)
+		}
+	}
+
+	// Get has two blocks, one executed; Route is missing from the profile,
+	// and store.go is named by its absolute path.
+	profiles, err := cover.ParseProfilesFromReader(strings.NewReader(`mode: set
+example.com/mod/api/api.go:3.14,4.10 3 1
+example.com/mod/api/api.go:4.10,6.2 1 0
+example.com/mod/api/api.go:8.14,10.2 2 1
+/src/store/store.go:3.14,5.2 4 0
+`))
+	if err != nil {
+		t.Fatalf("ParseProfilesFromReader() error = %v", err)
+	}
+	if unmatched := ApplyCoverage(g, profiles); len(unmatched) != 0 {
+		t.Errorf("unmatched = %v, want none", unmatched)
+	}
+
+	want := map[string]map[string]string{
+		"example.com/mod/api":                             {AttributeCoverage: "83.3"},
+		graph.FuncID("example.com/mod/api", "", "Get"):    {AttributeCoverage: "75.0"},
+		graph.FuncID("example.com/mod/api", "", "Put"):    {AttributeCoverage: "100.0"},
+		graph.FuncID("example.com/mod/api", "", "Route"):  {AttributeCoverage: "0", AttributeNotCovered: "true"},
+		graph.FuncID("example.com/mod/store", "", "Open"): {AttributeCoverage: "0.0"},
+		graph.FuncID("example.com/mod/api", "", "Delete"): nil,
+	}
+	for id, wantAttributes := range want {
+		node, _ := g.Node(id)
+		got := make(map[string]string)
+		for _, key := range []string{AttributeCoverage, AttributeNotCovered} {
+			if value, found := node.Attributes[key]; found {
+				got[key] = value
+			}
+		}
+		if len(got) == 0 {
+			got = nil
+		}
+		if !reflect.DeepEqual(got, wantAttributes) {
+			t.Errorf("%s coverage attributes = %v, want %v", id, got, wantAttributes)
+		}
+	}
+}
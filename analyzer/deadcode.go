@@ -0,0 +1,324 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// KeepDirective suppresses a dead export finding when it appears in the
+// declaration's doc or line comment.
+const KeepDirective = "//codegraph:keep"
+
+// DeadCodeConfidenceNote explains what FindDeadExports cannot see.
+const DeadCodeConfidenceNote = "Findings are candidates, not proof: uses through reflection (including fmt's " +
+	"String and Format methods), go:linkname, cgo, generated code that was not loaded, and modules " +
+	"outside the loaded set are invisible. Review before deleting, and mark intentional API with " +
+	KeepDirective + "."
+
+// DeadExport is an exported identifier with no reference outside its package.
+type DeadExport struct {
+	Package string `json:"package"`
+	// Name is the identifier, qualified by its receiver type for methods (e.g. "Store.Get").
+	Name string `json:"name"`
+	// Kind is one of "func", "method", "type", "const" or "var".
+	Kind string `json:"kind"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// FindDeadExports reports exported package-level identifiers and exported
+// methods of exported types that no other loaded package references. A method
+// also counts as used when its type implements an interface that is referenced
+// in any loaded package and the method belongs to that interface. Declarations
+// in test files, in main packages, in packages matching an exclude pattern (as
+// in LayerDef.Packages) or marked with KeepDirective are never reported.
+// Findings are sorted by file and line. Requires NeedSyntax, NeedTypes and
+// NeedTypesInfo.
+func FindDeadExports(pkgs []*packages.Package, exclude []string) []DeadExport {
+	candidates := make(map[string]deadCandidate)
+	for _, pkg := range pkgs {
+		if pkg.Name == "main" || strings.HasSuffix(pkg.PkgPath, "_test") || MatchesAnyPackagePattern(exclude, pkg.PkgPath) {
+			continue
+		}
+		collectExportedDeclarations(pkg, candidates)
+	}
+
+	for _, pkg := range pkgs {
+		markExternalReferences(pkg, candidates)
+	}
+	markInterfaceImplementations(pkgs, candidates)
+
+	var dead []DeadExport
+	for _, candidate := range candidates {
+		if !candidate.used {
+			dead = append(dead, candidate.export)
+		}
+	}
+	sort.Slice(dead, func(i, j int) bool {
+		if dead[i].File != dead[j].File {
+			return dead[i].File < dead[j].File
+		}
+		if dead[i].Line != dead[j].Line {
+			return dead[i].Line < dead[j].Line
+		}
+		return dead[i].Name < dead[j].Name
+	})
+	return dead
+}
+
+type deadCandidate struct {
+	export DeadExport
+	// receiver is the declaring package's type for methods, used to check
+	// interface implementations.
+	receiver *types.Named
+	used     bool
+}
+
+// MatchesAnyPackagePattern reports whether pkgPath matches one of patterns,
+// each an import path glob optionally ending in /... to include the packages
+// below it, as in LayerDef.Packages.
+func MatchesAnyPackagePattern(patterns []string, pkgPath string) bool {
+	for _, pattern := range patterns {
+		if matchesPackagePattern(pattern, pkgPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// collectExportedDeclarations adds the exported declarations of pkg's non-test
+// files to candidates, keyed by objectKey.
+func collectExportedDeclarations(pkg *packages.Package, candidates map[string]deadCandidate) {
+	for _, file := range pkg.Syntax {
+		if strings.HasSuffix(pkg.Fset.Position(file.Pos()).Filename, "_test.go") {
+			continue
+		}
+		for _, declaration := range file.Decls {
+			switch declaration := declaration.(type) {
+			case *ast.FuncDecl:
+				collectFunc(pkg, declaration, candidates)
+			case *ast.GenDecl:
+				collectGenDecl(pkg, declaration, candidates)
+			}
+		}
+	}
+}
+
+func collectFunc(pkg *packages.Package, declaration *ast.FuncDecl, candidates map[string]deadCandidate) {
+	if !declaration.Name.IsExported() || hasKeepDirective(declaration.Doc) {
+		return
+	}
+	function, isFunction := pkg.TypesInfo.Defs[declaration.Name].(*types.Func)
+	if !isFunction {
+		return
+	}
+	if declaration.Recv == nil {
+		addCandidate(pkg, declaration.Name, "func", declaration.Name.Name, nil, candidates)
+		return
+	}
+	receiver := receiverNamed(function)
+	if receiver == nil || !receiver.Obj().Exported() {
+		return
+	}
+	addCandidate(pkg, declaration.Name, "method", receiver.Obj().Name()+"."+declaration.Name.Name, receiver, candidates)
+}
+
+func collectGenDecl(pkg *packages.Package, declaration *ast.GenDecl, candidates map[string]deadCandidate) {
+	if hasKeepDirective(declaration.Doc) {
+		return
+	}
+	kind := map[token.Token]string{token.TYPE: "type", token.CONST: "const", token.VAR: "var"}[declaration.Tok]
+	for _, spec := range declaration.Specs {
+		switch spec := spec.(type) {
+		case *ast.TypeSpec:
+			if spec.Name.IsExported() && !hasKeepDirective(spec.Doc) && !hasKeepDirective(spec.Comment) {
+				addCandidate(pkg, spec.Name, kind, spec.Name.Name, nil, candidates)
+			}
+		case *ast.ValueSpec:
+			if hasKeepDirective(spec.Doc) || hasKeepDirective(spec.Comment) {
+				continue
+			}
+			for _, name := range spec.Names {
+				if name.IsExported() {
+					addCandidate(pkg, name, kind, name.Name, nil, candidates)
+				}
+			}
+		}
+	}
+}
+
+func addCandidate(pkg *packages.Package, name *ast.Ident, kind, qualifiedName string, receiver *types.Named, candidates map[string]deadCandidate) {
+	position := pkg.Fset.Position(name.Pos())
+	candidates[pkg.PkgPath+"."+qualifiedName] = deadCandidate{
+		export:   DeadExport{Package: pkg.PkgPath, Name: qualifiedName, Kind: kind, File: position.Filename, Line: position.Line},
+		receiver: receiver,
+	}
+}
+
+func hasKeepDirective(comments *ast.CommentGroup) bool {
+	return hasDirective(comments, KeepDirective)
+}
+
+// hasDirective reports whether a comment in comments starts with directive.
+func hasDirective(comments *ast.CommentGroup, directive string) bool {
+	if comments == nil {
+		return false
+	}
+	for _, comment := range comments.List {
+		if strings.HasPrefix(comment.Text, directive) {
+			return true
+		}
+	}
+	return false
+}
+
+// markExternalReferences marks the candidates pkg refers to from outside their
+// declaring package. Objects are matched by key rather than identity because
+// each package sees its dependencies through separately loaded type data.
+func markExternalReferences(pkg *packages.Package, candidates map[string]deadCandidate) {
+	for _, object := range pkg.TypesInfo.Uses {
+		if object.Pkg() == nil || object.Pkg().Path() == pkg.PkgPath {
+			continue
+		}
+		markUsed(candidates, objectKey(object))
+	}
+}
+
+func markUsed(candidates map[string]deadCandidate, key string) {
+	if candidate, found := candidates[key]; found {
+		candidate.used = true
+		candidates[key] = candidate
+	}
+}
+
+// objectKey identifies a package-level object or method across separately
+// loaded type data, e.g. "example.com/mod/store.Store.Get".
+func objectKey(object types.Object) string {
+	if function, isFunction := object.(*types.Func); isFunction {
+		if receiver := receiverNamed(function); receiver != nil {
+			return object.Pkg().Path() + "." + receiver.Obj().Name() + "." + object.Name()
+		}
+	}
+	return object.Pkg().Path() + "." + object.Name()
+}
+
+// receiverNamed returns the named type a method is declared on, or nil for functions.
+func receiverNamed(function *types.Func) *types.Named {
+	receiver := function.Type().(*types.Signature).Recv()
+	if receiver == nil {
+		return nil
+	}
+	receiverType := receiver.Type()
+	if pointer, isPointer := receiverType.(*types.Pointer); isPointer {
+		receiverType = pointer.Elem()
+	}
+	named, isNamed := receiverType.(*types.Named)
+	if !isNamed {
+		return nil
+	}
+	return named.Origin()
+}
+
+// markInterfaceImplementations marks methods that let their type satisfy an
+// interface used somewhere in the loaded packages.
+func markInterfaceImplementations(pkgs []*packages.Package, candidates map[string]deadCandidate) {
+	interfaces := usedInterfaces(pkgs)
+
+	checked := make(map[*types.Named]bool)
+	for _, candidate := range candidates {
+		receiver := candidate.receiver
+		if candidate.used || receiver == nil || checked[receiver] {
+			continue
+		}
+		checked[receiver] = true
+
+		methodSet := types.NewMethodSet(types.NewPointer(receiver))
+		for _, iface := range interfaces {
+			if !implementsBySignature(methodSet, receiver.Obj().Pkg(), iface) {
+				continue
+			}
+			for i := range iface.NumMethods() {
+				if selection := methodSet.Lookup(receiver.Obj().Pkg(), iface.Method(i).Name()); selection != nil {
+					markUsed(candidates, objectKey(selection.Obj()))
+				}
+			}
+		}
+	}
+}
+
+// usedInterfaces returns the non-empty interfaces referenced in the loaded
+// packages: named interfaces used by name anywhere, interface literals outside
+// type declarations, and the predeclared error.
+func usedInterfaces(pkgs []*packages.Package) []*types.Interface {
+	interfaces := []*types.Interface{types.Universe.Lookup("error").Type().Underlying().(*types.Interface)}
+	seen := make(map[types.Type]bool)
+	add := func(candidate types.Type) {
+		iface, isInterface := candidate.Underlying().(*types.Interface)
+		if isInterface && iface.NumMethods() > 0 && !seen[candidate] {
+			seen[candidate] = true
+			interfaces = append(interfaces, iface)
+		}
+	}
+
+	for _, pkg := range pkgs {
+		for _, object := range pkg.TypesInfo.Uses {
+			if typeName, isTypeName := object.(*types.TypeName); isTypeName {
+				add(typeName.Type())
+			}
+		}
+
+		declared := make(map[ast.Expr]bool)
+		for _, file := range pkg.Syntax {
+			ast.Inspect(file, func(node ast.Node) bool {
+				if spec, isTypeSpec := node.(*ast.TypeSpec); isTypeSpec {
+					declared[spec.Type] = true
+				}
+				return true
+			})
+		}
+		for expression, typeAndValue := range pkg.TypesInfo.Types {
+			if _, isLiteral := expression.(*ast.InterfaceType); isLiteral && !declared[expression] {
+				add(typeAndValue.Type)
+			}
+		}
+	}
+	return interfaces
+}
+
+// implementsBySignature reports whether methodSet has every method of iface
+// with the same signature, comparing signatures as strings qualified by
+// package path so types loaded separately for different packages still match.
+func implementsBySignature(methodSet *types.MethodSet, receiverPackage *types.Package, iface *types.Interface) bool {
+	for i := range iface.NumMethods() {
+		method := iface.Method(i)
+		if !method.Exported() && (method.Pkg() == nil || method.Pkg().Path() != receiverPackage.Path()) {
+			return false
+		}
+		selection := methodSet.Lookup(receiverPackage, method.Name())
+		if selection == nil || signatureString(selection.Obj().Type()) != signatureString(method.Type()) {
+			return false
+		}
+	}
+	return true
+}
+
+func signatureString(signatureType types.Type) string {
+	signature := signatureType.(*types.Signature)
+	qualifier := func(pkg *types.Package) string { return pkg.Path() }
+	tupleString := func(tuple *types.Tuple, variadic bool) string {
+		parts := make([]string, tuple.Len())
+		for i := range tuple.Len() {
+			parts[i] = types.TypeString(tuple.At(i).Type(), qualifier)
+		}
+		if variadic && len(parts) > 0 {
+			parts[len(parts)-1] = "..." + strings.TrimPrefix(parts[len(parts)-1], "[]")
+		}
+		return "(" + strings.Join(parts, ", ") + ")"
+	}
+	return tupleString(signature.Params(), signature.Variadic()) + tupleString(signature.Results(), false)
+}
@@ -0,0 +1,139 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Desgue/codegraph/parser"
+	"golang.org/x/tools/go/packages"
+)
+
+// loadDeadCodeModule writes files into a temporary module "deadmod" and loads it.
+func loadDeadCodeModule(t *testing.T, files map[string]string, includeTests bool) []*packages.Package {
+	t.Helper()
+
+	testDir := t.TempDir()
+	files["go.mod"] = "module deadmod\n\ngo 1.24\n"
+	for name, content := range files {
+		filePath := filepath.Join(testDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	pkgs, errorCount, err := parser.Load(testDir, includeTests)
+	if err != nil || errorCount > 0 {
+		t.Fatalf("Load() = %d errors, %v", errorCount, err)
+	}
+	return pkgs
+}
+
+func deadNames(findings []DeadExport) []string {
+	names := []string{}
+	for _, finding := range findings {
+		names = append(names, finding.Kind+" "+finding.Package+"."+finding.Name)
+	}
+	return names
+}
+
+func TestFindDeadExports(t *testing.T) {
+	pkgs := loadDeadCodeModule(t, map[string]string{
+		"main.go": `package main
+
+import (
+	"fmt"
+
+	"deadmod/store"
+)
+
+func main() {
+	var shape store.Shape = store.NewSquare()
+	fmt.Println(shape.Area(), store.Limit)
+}
+
+// Exported is never reported: main packages cannot be imported.
+func Exported() {}
+`,
+		"store/store.go": `package store
+
+const Limit = 10
+
+// Unused is exported but nothing outside the package refers to it.
+const Unused = 1
+
+type Shape interface{ Area() int }
+
+type Square struct{ side int }
+
+func NewSquare() *Square { return &Square{side: 2} }
+
+// Area satisfies Shape, which main uses.
+func (s *Square) Area() int { return s.side * s.side }
+
+func (s *Square) Perimeter() int { return 4 * s.side }
+
+func (s *Square) String() string { return "square" }
+
+func (s *Square) Error() string { return "square" }
+
+type Orphan struct{}
+
+//codegraph:keep
+func Public() {}
+
+var (
+	Registry = map[string]int{} //codegraph:keep
+	Counter  int
+)
+
+func helper() int { return Unused + Counter }
+
+func TestedOnly() {}
+`,
+		"store/store_test.go": `package store_test
+
+import (
+	"testing"
+
+	"deadmod/store"
+)
+
+func TestTestedOnly(t *testing.T) { store.TestedOnly() }
+
+func ExportedInTest() {}
+`,
+	}, true)
+
+	findings := FindDeadExports(pkgs, nil)
+	want := []string{
+		"const deadmod/store.Unused",
+		"type deadmod/store.Square",
+		"method deadmod/store.Square.Perimeter",
+		"method deadmod/store.Square.String",
+		"type deadmod/store.Orphan",
+		"var deadmod/store.Counter",
+	}
+	if got := deadNames(findings); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindDeadExports() = %v, want %v", got, want)
+	}
+	if findings[0].Line != 6 || filepath.Base(findings[0].File) != "store.go" {
+		t.Errorf("first finding at %s:%d, want store.go:6", findings[0].File, findings[0].Line)
+	}
+}
+
+func TestFindDeadExports_Exclude(t *testing.T) {
+	pkgs := loadDeadCodeModule(t, map[string]string{
+		"api/api.go":   "package api\n\nfunc Handler() {}\n",
+		"util/util.go": "package util\n\nfunc Helper() {}\n",
+	}, false)
+
+	got := deadNames(FindDeadExports(pkgs, []string{"deadmod/api/..."}))
+	if want := []string{"func deadmod/util.Helper"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FindDeadExports() = %v, want %v", got, want)
+	}
+}
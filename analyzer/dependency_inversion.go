@@ -0,0 +1,58 @@
+package analyzer
+
+import (
+	"github.com/Desgue/codegraph/graph"
+)
+
+// Dependency inversion rules reported in DIViolation.Rule.
+const (
+	// RuleConcreteDependency: a higher layer imports a lower, non-abstract layer directly.
+	RuleConcreteDependency = "high-level package depends on concrete lower layer"
+	// RuleImpureAbstraction: a higher layer imports a package of an abstract layer
+	// that declares concrete types, so it is not interface-only.
+	RuleImpureAbstraction = "abstract layer package declares concrete types"
+)
+
+// DIViolation is an import edge that breaks the Dependency Inversion Principle.
+type DIViolation struct {
+	From string
+	To   string
+	Rule string
+}
+
+// CheckDependencyInversion reports import edges from a higher layer to a lower
+// layer that do not go through an abstraction. Such an edge is allowed only when
+// the target belongs to a layer marked Abstract and declares no concrete types,
+// which requires Node.InterfaceCount and Node.ConcreteTypeCount to be populated.
+// Edges within a layer, edges pointing upwards, and packages outside every
+// layer are ignored. Violations follow the graph's edge order.
+func CheckDependencyInversion(g *graph.Graph, layers []LayerDef) []DIViolation {
+	var violations []DIViolation
+
+	for _, edge := range g.Edges() {
+		if edge.Kind != graph.EdgeImport {
+			continue
+		}
+		fromLayer := layerIndex(layers, edge.From)
+		toLayer := layerIndex(layers, edge.To)
+		if fromLayer < 0 || toLayer < 0 || toLayer <= fromLayer {
+			continue
+		}
+
+		if rule, violated := dependencyInversionRule(g, layers[toLayer], edge.To); violated {
+			violations = append(violations, DIViolation{From: edge.From, To: edge.To, Rule: rule})
+		}
+	}
+
+	return violations
+}
+
+func dependencyInversionRule(g *graph.Graph, targetLayer LayerDef, targetID string) (string, bool) {
+	if !targetLayer.Abstract {
+		return RuleConcreteDependency, true
+	}
+	if target, found := g.Node(targetID); found && target.ConcreteTypeCount > 0 {
+		return RuleImpureAbstraction, true
+	}
+	return "", false
+}
@@ -0,0 +1,78 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+// newLayeredGraph builds app -> ports, app -> store, store -> ports, ports -> model,
+// adapters -> ports with per-node type counts.
+func newLayeredGraph(t *testing.T) *graph.Graph {
+	t.Helper()
+
+	g := graph.New()
+	nodes := []*graph.Node{
+		{ID: "example.com/mod/app", ConcreteTypeCount: 2},
+		{ID: "example.com/mod/ports", InterfaceCount: 3},
+		{ID: "example.com/mod/ports/model", InterfaceCount: 1, ConcreteTypeCount: 1},
+		{ID: "example.com/mod/store", ConcreteTypeCount: 4},
+		{ID: "example.com/mod/unlayered"},
+	}
+	for _, node := range nodes {
+		node.Kind = graph.KindPackage
+		if err := g.AddNode(node); err != nil {
+			t.Fatalf("AddNode() error = %v", err)
+		}
+	}
+
+	edges := [][2]string{
+		{"example.com/mod/app", "example.com/mod/ports"},
+		{"example.com/mod/app", "example.com/mod/ports/model"},
+		{"example.com/mod/app", "example.com/mod/store"},
+		{"example.com/mod/app", "example.com/mod/unlayered"},
+		{"example.com/mod/store", "example.com/mod/ports"},
+		{"example.com/mod/store", "example.com/mod/app"},
+	}
+	for _, edge := range edges {
+		if err := g.AddEdge(&graph.Edge{From: edge[0], To: edge[1], Kind: graph.EdgeImport}); err != nil {
+			t.Fatalf("AddEdge() error = %v", err)
+		}
+	}
+	return g
+}
+
+func TestCheckDependencyInversion(t *testing.T) {
+	layers := []LayerDef{
+		{Name: "application", Packages: []string{"example.com/mod/app"}},
+		{Name: "ports", Packages: []string{"example.com/mod/ports/..."}, Abstract: true},
+		{Name: "infrastructure", Packages: []string{"example.com/mod/store"}},
+	}
+
+	got := CheckDependencyInversion(newLayeredGraph(t), layers)
+
+	want := []DIViolation{
+		{From: "example.com/mod/app", To: "example.com/mod/ports/model", Rule: RuleImpureAbstraction},
+		{From: "example.com/mod/app", To: "example.com/mod/store", Rule: RuleConcreteDependency},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckDependencyInversion() =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestCheckDependencyInversion_IgnoresNonImportEdges(t *testing.T) {
+	g := graph.New()
+	g.AddNode(&graph.Node{ID: "high"})
+	g.AddNode(&graph.Node{ID: "low", ConcreteTypeCount: 1})
+	g.AddEdge(&graph.Edge{From: "high", To: "low", Kind: "call"})
+
+	layers := []LayerDef{
+		{Name: "high", Packages: []string{"high"}},
+		{Name: "low", Packages: []string{"low"}},
+	}
+
+	if got := CheckDependencyInversion(g, layers); len(got) != 0 {
+		t.Errorf("expected no violations for non-import edges, got %v", got)
+	}
+}
@@ -0,0 +1,44 @@
+package analyzer
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/Desgue/codegraph/extract"
+	"github.com/Desgue/codegraph/graph"
+)
+
+// DocCoverage returns the extract.AttributeDocCoverage percentage of every
+// node carrying it, keyed by node ID. It reads the graph rather than the
+// source, so it works on decoded graph files too.
+func DocCoverage(g *graph.Graph) map[string]int {
+	coverage := make(map[string]int)
+	for _, node := range g.Nodes() {
+		if value, found := node.Attributes[extract.AttributeDocCoverage]; found {
+			if percent, err := strconv.Atoi(value); err == nil {
+				coverage[node.ID] = percent
+			}
+		}
+	}
+	return coverage
+}
+
+// UndocumentedFunctions returns the sorted IDs of the function nodes marked
+// undocumented by extract.SymbolExtractor whose package matches one of
+// patterns (as in LayerDef.Packages). The graph must have been extracted with
+// that extractor.
+func UndocumentedFunctions(g *graph.Graph, patterns []string) []string {
+	var ids []string
+	for _, node := range g.Nodes() {
+		if node.Kind != graph.KindFunction || node.Attributes[extract.AttributeDocumented] != "false" {
+			continue
+		}
+		_, parts, err := graph.ParseID(node.ID)
+		if err != nil || !MatchesAnyPackagePattern(patterns, parts[0]) {
+			continue
+		}
+		ids = append(ids, node.ID)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,47 @@
+package analyzer
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/Desgue/codegraph/extract"
+	"github.com/Desgue/codegraph/graph"
+)
+
+func TestDocCoverage_ReadsGraph(t *testing.T) {
+	g := graph.New()
+	documented := &graph.Node{ID: "example.com/mod/api", Kind: graph.KindPackage}
+	documented.SetAttribute(extract.AttributeDocCoverage, "75")
+	nodes := []*graph.Node{
+		documented,
+		{ID: "example.com/mod/empty", Kind: graph.KindPackage},
+		newDocumentedFunction("example.com/mod/api", "", "Serve", false),
+		newDocumentedFunction("example.com/mod/api", "Server", "Close", false),
+		newDocumentedFunction("example.com/mod/api", "", "Listen", true),
+		newDocumentedFunction("example.com/mod/store", "", "Get", false),
+	}
+	for _, node := range nodes {
+		if err := g.AddNode(node); err != nil {
+			t.Fatalf("AddNode() error = %v", err)
+		}
+	}
+
+	if got, want := DocCoverage(g), map[string]int{"example.com/mod/api": 75}; !reflect.DeepEqual(got, want) {
+		t.Errorf("DocCoverage() = %v, want %v", got, want)
+	}
+
+	want := []string{
+		graph.FuncID("example.com/mod/api", "", "Serve"),
+		graph.FuncID("example.com/mod/api", "Server", "Close"),
+	}
+	if got := UndocumentedFunctions(g, []string{"example.com/mod/api"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("UndocumentedFunctions() = %v, want %v", got, want)
+	}
+}
+
+func newDocumentedFunction(pkgPath, receiver, name string, documented bool) *graph.Node {
+	node := &graph.Node{ID: graph.FuncID(pkgPath, receiver, name), Kind: graph.KindFunction, Name: name}
+	node.SetAttribute(extract.AttributeDocumented, strconv.FormatBool(documented))
+	return node
+}
@@ -0,0 +1,375 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// How a call site consumes the error result of its callee.
+const (
+	// ErrorPropagated errors are returned, directly or inside another
+	// expression such as fmt.Errorf("...: %w", err).
+	ErrorPropagated = "propagated"
+	// ErrorHandled errors are inspected or passed on without being returned,
+	// e.g. logged or compared against nil.
+	ErrorHandled = "handled"
+	// ErrorDropped errors are discarded: the call is a statement of its own,
+	// run by go or defer, or its error is assigned to _ or never read.
+	ErrorDropped = "dropped"
+)
+
+// ErrorFlowNote states what AnalyzeErrorFlow cannot see.
+const ErrorFlowNote = "static analysis of call sites: calls through function values are not seen, " +
+	"an error stored in a field or passed to another function counts as handled, " +
+	"and an error variable is judged by the statements between its assignment and the next in source order, whatever the branch"
+
+// ErrorCallSite is a call of an error-returning function.
+type ErrorCallSite struct {
+	// Callee and Caller are named "pkgpath.Func" or "pkgpath.Type.Method".
+	Callee      string `json:"callee"`
+	Caller      string `json:"caller"`
+	File        string `json:"file"`
+	Line        int    `json:"line"`
+	Consumption string `json:"consumption"`
+}
+
+// ErrorFunction counts how the callers of an error-returning function
+// consume its error.
+type ErrorFunction struct {
+	Function   string `json:"function"`
+	Propagated int    `json:"propagated"`
+	Handled    int    `json:"handled"`
+	Dropped    int    `json:"dropped"`
+}
+
+// ErrorFlow is the result of AnalyzeErrorFlow.
+type ErrorFlow struct {
+	// Functions lists every loaded function or method whose last result is an
+	// error and that is called somewhere, sorted by name.
+	Functions []ErrorFunction `json:"functions"`
+	// Dropped lists the call sites dropping an error, by file and line.
+	Dropped []ErrorCallSite `json:"dropped"`
+}
+
+// AnalyzeErrorFlow classifies every call, inside a function body of pkgs, of
+// a function or method declared in pkgs whose last result is an error, as
+// ErrorPropagated, ErrorHandled or ErrorDropped. Callees are resolved the way
+// BuildCallGraph resolves them: statically, with interface method calls
+// attributed to the interface method. See ErrorFlowNote for the limits.
+// Requires NeedSyntax and NeedTypesInfo.
+func AnalyzeErrorFlow(pkgs []*packages.Package) ErrorFlow {
+	loaded := make(map[string]bool, len(pkgs))
+	for _, pkg := range pkgs {
+		loaded[pkg.PkgPath] = true
+	}
+
+	var sites []ErrorCallSite
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			for _, declaration := range file.Decls {
+				funcDecl, isFunc := declaration.(*ast.FuncDecl)
+				if !isFunc || funcDecl.Body == nil {
+					continue
+				}
+				caller, isFunction := pkg.TypesInfo.Defs[funcDecl.Name].(*types.Func)
+				if !isFunction {
+					continue
+				}
+				sites = append(sites, errorCallSites(pkg, loaded, caller, funcDecl)...)
+			}
+		}
+	}
+
+	counts := make(map[string]*ErrorFunction)
+	var flow ErrorFlow
+	for _, site := range sites {
+		function, found := counts[site.Callee]
+		if !found {
+			function = &ErrorFunction{Function: site.Callee}
+			counts[site.Callee] = function
+		}
+		switch site.Consumption {
+		case ErrorPropagated:
+			function.Propagated++
+		case ErrorHandled:
+			function.Handled++
+		case ErrorDropped:
+			function.Dropped++
+			flow.Dropped = append(flow.Dropped, site)
+		}
+	}
+	for _, name := range sortedKeys(counts) {
+		flow.Functions = append(flow.Functions, *counts[name])
+	}
+	sort.Slice(flow.Dropped, func(i, j int) bool {
+		if flow.Dropped[i].File != flow.Dropped[j].File {
+			return flow.Dropped[i].File < flow.Dropped[j].File
+		}
+		return flow.Dropped[i].Line < flow.Dropped[j].Line
+	})
+	return flow
+}
+
+// errorVarEvent is a read or write of a variable, or a bare return, in
+// source order within a function body.
+type errorVarEvent struct {
+	position token.Pos
+	object   types.Object
+	// assigned marks writes, inReturn reads returned (see insideReturn) and
+	// bareReturn returns without results, which return named results.
+	assigned, inReturn, bareReturn bool
+}
+
+// pendingErrorSite is a call whose error lands in a variable, classified once
+// every event of the body is known.
+type pendingErrorSite struct {
+	site     ErrorCallSite
+	variable types.Object
+	position token.Pos
+}
+
+// errorCallSites classifies the error-returning calls in funcDecl's body.
+func errorCallSites(pkg *packages.Package, loaded map[string]bool, caller *types.Func, funcDecl *ast.FuncDecl) []ErrorCallSite {
+	callerName := objectKey(caller)
+	var sites []ErrorCallSite
+	var pending []pendingErrorSite
+	var events []errorVarEvent
+	var stack []ast.Node
+
+	ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+		if node == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		stack = append(stack, node)
+
+		switch node := node.(type) {
+		case *ast.ReturnStmt:
+			if len(node.Results) == 0 {
+				events = append(events, errorVarEvent{position: node.Pos(), bareReturn: true})
+			}
+		case *ast.Ident:
+			if object, isVar := pkg.TypesInfo.ObjectOf(node).(*types.Var); isVar {
+				events = append(events, errorVarEvent{position: node.Pos(), object: object,
+					assigned: isAssignTarget(stack), inReturn: insideReturn(stack)})
+			}
+		case *ast.CallExpr:
+			callee := calledFunction(pkg, node)
+			if callee == nil || !loaded[callee.Pkg().Path()] || !returnsError(callee) {
+				return true
+			}
+			position := pkg.Fset.Position(node.Pos())
+			site := ErrorCallSite{Callee: objectKey(callee), Caller: callerName, File: position.Filename, Line: position.Line}
+			consumption, variable := errorConsumption(pkg, stack, callee)
+			if variable != nil {
+				pending = append(pending, pendingErrorSite{site: site, variable: variable, position: node.End()})
+				return true
+			}
+			site.Consumption = consumption
+			sites = append(sites, site)
+		}
+		return true
+	})
+
+	sort.Slice(events, func(i, j int) bool { return events[i].position < events[j].position })
+	namedResults := namedResultVars(pkg, funcDecl)
+	for _, call := range pending {
+		call.site.Consumption = variableConsumption(events, call.variable, call.position, namedResults[call.variable])
+		sites = append(sites, call.site)
+	}
+	return sites
+}
+
+// calledFunction returns the declared function or method call invokes, or
+// nil for calls of function values, conversions and builtins.
+func calledFunction(pkg *packages.Package, call *ast.CallExpr) *types.Func {
+	var ident *ast.Ident
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	case *ast.IndexExpr:
+		ident = genericFuncIdent(fun.X)
+	case *ast.IndexListExpr:
+		ident = genericFuncIdent(fun.X)
+	}
+	if ident == nil {
+		return nil
+	}
+	function, isFunction := pkg.TypesInfo.Uses[ident].(*types.Func)
+	if !isFunction || function.Pkg() == nil {
+		return nil
+	}
+	return function.Origin()
+}
+
+// genericFuncIdent returns the name of an explicitly instantiated function.
+func genericFuncIdent(expression ast.Expr) *ast.Ident {
+	switch expression := expression.(type) {
+	case *ast.Ident:
+		return expression
+	case *ast.SelectorExpr:
+		return expression.Sel
+	}
+	return nil
+}
+
+// returnsError reports whether function's last result is the error type.
+func returnsError(function *types.Func) bool {
+	results := function.Type().(*types.Signature).Results()
+	return results.Len() > 0 && types.Identical(results.At(results.Len()-1).Type(), types.Universe.Lookup("error").Type())
+}
+
+// errorConsumption classifies the call at the top of stack from where it
+// appears. When the error is stored in a local variable it returns that
+// variable instead, to be classified by the variable's uses.
+func errorConsumption(pkg *packages.Package, stack []ast.Node, callee *types.Func) (string, types.Object) {
+	call := stack[len(stack)-1]
+	parentIndex := len(stack) - 2
+	for parentIndex >= 0 {
+		if _, isParen := stack[parentIndex].(*ast.ParenExpr); !isParen {
+			break
+		}
+		parentIndex--
+	}
+	if parentIndex < 0 {
+		return ErrorDropped, nil
+	}
+
+	results := callee.Type().(*types.Signature).Results().Len()
+	switch parent := stack[parentIndex].(type) {
+	case *ast.ExprStmt, *ast.GoStmt, *ast.DeferStmt:
+		return ErrorDropped, nil
+	case *ast.ReturnStmt:
+		return ErrorPropagated, nil
+	case *ast.AssignStmt:
+		if target := errorTarget(parent.Lhs, parent.Rhs, call, results); target != nil {
+			return targetConsumption(pkg, target)
+		}
+	case *ast.ValueSpec:
+		names := make([]ast.Expr, len(parent.Names))
+		for i, name := range parent.Names {
+			names[i] = name
+		}
+		if target := errorTarget(names, parent.Values, call, results); target != nil {
+			return targetConsumption(pkg, target)
+		}
+	}
+	if insideReturn(stack[:parentIndex+1]) {
+		return ErrorPropagated, nil
+	}
+	return ErrorHandled, nil
+}
+
+// errorTarget returns the left-hand side receiving the error of call, a
+// right-hand side of an assignment or declaration.
+func errorTarget(lhs, rhs []ast.Expr, call ast.Node, results int) ast.Expr {
+	if len(rhs) == 1 && len(lhs) == results {
+		return lhs[results-1]
+	}
+	for i, value := range rhs {
+		if ast.Unparen(value) == call && i < len(lhs) {
+			return lhs[i]
+		}
+	}
+	return nil
+}
+
+// targetConsumption classifies storing an error in target: assigning it to
+// _ drops it, a local variable decides by its uses and anything else, such
+// as a field, keeps it around to be handled.
+func targetConsumption(pkg *packages.Package, target ast.Expr) (string, types.Object) {
+	ident, isIdent := ast.Unparen(target).(*ast.Ident)
+	if !isIdent {
+		return ErrorHandled, nil
+	}
+	if ident.Name == "_" {
+		return ErrorDropped, nil
+	}
+	variable, isVar := pkg.TypesInfo.ObjectOf(ident).(*types.Var)
+	if !isVar || variable.Parent() == variable.Pkg().Scope() {
+		return ErrorHandled, nil
+	}
+	return "", variable
+}
+
+// variableConsumption classifies an error assigned to variable at position
+// from the variable's events up to its next assignment: returning it (see
+// insideReturn), or a bare return of a named result, propagates it, any other
+// read handles it and no read drops it.
+func variableConsumption(events []errorVarEvent, variable types.Object, position token.Pos, namedResult bool) string {
+	consumption := ErrorDropped
+	for _, event := range events {
+		if event.position < position {
+			continue
+		}
+		switch {
+		case event.bareReturn && namedResult:
+			return ErrorPropagated
+		case event.object != variable:
+			continue
+		case event.assigned:
+			return consumption
+		case event.inReturn:
+			return ErrorPropagated
+		default:
+			consumption = ErrorHandled
+		}
+	}
+	return consumption
+}
+
+// namedResultVars returns the named results of funcDecl.
+func namedResultVars(pkg *packages.Package, funcDecl *ast.FuncDecl) map[types.Object]bool {
+	named := make(map[types.Object]bool)
+	if funcDecl.Type.Results == nil {
+		return named
+	}
+	for _, field := range funcDecl.Type.Results.List {
+		for _, name := range field.Names {
+			if object := pkg.TypesInfo.Defs[name]; object != nil {
+				named[object] = true
+			}
+		}
+	}
+	return named
+}
+
+// isAssignTarget reports whether the identifier at the top of stack is
+// written by a plain assignment.
+func isAssignTarget(stack []ast.Node) bool {
+	if len(stack) < 2 {
+		return false
+	}
+	assign, isAssign := stack[len(stack)-2].(*ast.AssignStmt)
+	if !isAssign || (assign.Tok != token.ASSIGN && assign.Tok != token.DEFINE) {
+		return false
+	}
+	for _, target := range assign.Lhs {
+		if target == stack[len(stack)-1] {
+			return true
+		}
+	}
+	return false
+}
+
+// insideReturn reports whether the top of stack is returned, as a result of
+// a return statement or wrapped in one, e.g. by fmt.Errorf. Comparisons such
+// as return err == nil return a verdict on the error, not the error, and
+// function literals have returns of their own.
+func insideReturn(stack []ast.Node) bool {
+	for i := len(stack) - 2; i >= 0; i-- {
+		switch stack[i].(type) {
+		case *ast.ReturnStmt:
+			return true
+		case *ast.FuncLit, *ast.BinaryExpr, ast.Stmt:
+			return false
+		}
+	}
+	return false
+}
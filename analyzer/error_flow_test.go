@@ -0,0 +1,72 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnalyzeErrorFlow(t *testing.T) {
+	pkgs := loadDeadCodeModule(t, map[string]string{
+		"store/store.go": `package store
+
+import "fmt"
+
+type Store struct{}
+
+func Open() (*Store, error) { return &Store{}, nil }
+
+func (s *Store) Close() error { return nil }
+
+func Save() error { return nil }
+
+func Load() (result int, err error) {
+	err = Save()
+	return
+}
+
+func Wrap() error {
+	if _, err := Open(); err != nil {
+		return fmt.Errorf("open: %w", err)
+	}
+	return Save()
+}
+
+func Check() bool {
+	err := Save()
+	return err == nil
+}
+
+func Ignore() {
+	s, _ := Open()
+	defer s.Close()
+	Save()
+	err := Save()
+	err = Save()
+	_ = err
+}
+`,
+	}, false)
+
+	flow := AnalyzeErrorFlow(pkgs)
+
+	wantFunctions := []ErrorFunction{
+		{Function: "deadmod/store.Open", Propagated: 1, Dropped: 1},
+		{Function: "deadmod/store.Save", Propagated: 2, Handled: 2, Dropped: 2},
+		{Function: "deadmod/store.Store.Close", Dropped: 1},
+	}
+	if !reflect.DeepEqual(flow.Functions, wantFunctions) {
+		t.Errorf("Functions = %+v, want %+v", flow.Functions, wantFunctions)
+	}
+
+	var dropped []string
+	for _, site := range flow.Dropped {
+		if site.Caller != "deadmod/store.Ignore" {
+			t.Errorf("unexpected dropped call site %+v", site)
+		}
+		dropped = append(dropped, site.Callee)
+	}
+	wantDropped := []string{"deadmod/store.Open", "deadmod/store.Store.Close", "deadmod/store.Save", "deadmod/store.Save"}
+	if !reflect.DeepEqual(dropped, wantDropped) {
+		t.Errorf("dropped callees = %v, want %v", dropped, wantDropped)
+	}
+}
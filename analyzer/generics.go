@@ -0,0 +1,55 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Desgue/codegraph/parser"
+	"golang.org/x/tools/go/packages"
+)
+
+// InstantiatedSymbol is a generic function or type with how often the loaded
+// packages instantiate it.
+type InstantiatedSymbol struct {
+	Symbol string `json:"symbol"`
+	// Instantiations sums, over packages, the distinct concrete type argument
+	// lists each package instantiates the symbol with.
+	Instantiations int `json:"instantiations"`
+	// TypeArgs counts the packages using each type argument list, written
+	// comma separated, e.g. "string, int".
+	TypeArgs map[string]int `json:"type_args"`
+}
+
+// MostInstantiated returns the generic symbols instantiated in pkgs, most
+// instantiated first and then by symbol. Deferred instantiations inside other
+// generic code are left out: their type arguments are type parameters, not
+// the types the code is finally used with.
+func MostInstantiated(pkgs []*packages.Package) []InstantiatedSymbol {
+	symbols := make(map[string]*InstantiatedSymbol)
+	for _, pkg := range pkgs {
+		for _, instance := range parser.GenericInstances(pkg) {
+			if instance.Deferred {
+				continue
+			}
+			symbol, found := symbols[instance.Symbol]
+			if !found {
+				symbol = &InstantiatedSymbol{Symbol: instance.Symbol, TypeArgs: make(map[string]int)}
+				symbols[instance.Symbol] = symbol
+			}
+			symbol.Instantiations++
+			symbol.TypeArgs[strings.Join(instance.TypeArgs, ", ")]++
+		}
+	}
+
+	ranked := make([]InstantiatedSymbol, 0, len(symbols))
+	for _, symbol := range symbols {
+		ranked = append(ranked, *symbol)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Instantiations != ranked[j].Instantiations {
+			return ranked[i].Instantiations > ranked[j].Instantiations
+		}
+		return ranked[i].Symbol < ranked[j].Symbol
+	})
+	return ranked
+}
@@ -0,0 +1,23 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMostInstantiated(t *testing.T) {
+	pkgs := loadDeadCodeModule(t, map[string]string{
+		"coll/coll.go": "package coll\n\nfunc Map[T, U any](items []T, f func(T) U) []U { return nil }\n\nfunc Apply[T any](items []T) { Map(items, func(item T) T { return item }) }\n",
+		"api/api.go":   "package api\n\nimport \"deadmod/coll\"\n\nvar _ = coll.Map([]int{}, func(int) string { return \"\" })\n\nvar _ = coll.Map([]int{}, func(int) string { return \"x\" })\n",
+		"store/store.go": "package store\n\nimport (\n\t\"slices\"\n\n\t\"deadmod/coll\"\n)\n\nvar _ = coll.Map([]string{}, func(string) int { return 0 })\n\n" +
+			"var _ = coll.Map([]int{}, func(int) string { return \"\" })\n\nvar _ = slices.Contains([]int{}, 1)\n",
+	}, false)
+
+	want := []InstantiatedSymbol{
+		{Symbol: "deadmod/coll.Map", Instantiations: 3, TypeArgs: map[string]int{"int, string": 2, "string, int": 1}},
+		{Symbol: "slices.Contains", Instantiations: 1, TypeArgs: map[string]int{"[]int, int": 1}},
+	}
+	if got := MostInstantiated(pkgs); !reflect.DeepEqual(got, want) {
+		t.Errorf("MostInstantiated() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,97 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/Desgue/codegraph/extract"
+	"github.com/Desgue/codegraph/graph"
+	"github.com/Desgue/codegraph/graph/metrics"
+)
+
+// GodPackageThresholds are the limits above which a package is a god package.
+// A zero limit disables that check.
+type GodPackageThresholds struct {
+	Files       int
+	FanIn       int
+	FanOut      int
+	LinesOfCode int
+}
+
+// DefaultGodPackageThresholds returns limits that only very large packages exceed.
+func DefaultGodPackageThresholds() GodPackageThresholds {
+	return GodPackageThresholds{Files: 30, FanIn: 20, FanOut: 20, LinesOfCode: 5000}
+}
+
+// godPackageNeighbors is how many importers and imports a GodPackage lists.
+const godPackageNeighbors = 3
+
+// GodPackage is a package exceeding at least one GodPackageThresholds limit.
+type GodPackage struct {
+	Package string
+	// Exceeded describes each limit that tripped, e.g. "files 42 > 30".
+	Exceeded []string
+	// TopImporters and TopImports are the most connected packages importing
+	// and imported by Package, hinting at where it could be split.
+	TopImporters []string
+	TopImports   []string
+}
+
+// FindGodPackages returns the package nodes of g exceeding any of thresholds,
+// sorted by ID. Lines of code come from the extract.AttributeLinesOfCode
+// attribute, so packages loaded without syntax are never flagged for size.
+func FindGodPackages(g *graph.Graph, thresholds GodPackageThresholds) []GodPackage {
+	fanIn := metrics.FanIn(g)
+	fanOut := metrics.FanOut(g)
+
+	var godPackages []GodPackage
+	for _, node := range g.Nodes() {
+		if node.Kind != graph.KindPackage {
+			continue
+		}
+		linesOfCode, _ := strconv.Atoi(node.Attributes[extract.AttributeLinesOfCode])
+
+		var exceeded []string
+		exceed := func(metric string, value, limit int) {
+			if limit > 0 && value > limit {
+				exceeded = append(exceeded, fmt.Sprintf("%s %d > %d", metric, value, limit))
+			}
+		}
+		exceed("files", len(node.Files), thresholds.Files)
+		exceed("fan-in", fanIn[node.ID], thresholds.FanIn)
+		exceed("fan-out", fanOut[node.ID], thresholds.FanOut)
+		exceed("loc", linesOfCode, thresholds.LinesOfCode)
+		if len(exceeded) == 0 {
+			continue
+		}
+
+		importKinds := []graph.EdgeKind{graph.EdgeImport}
+		godPackages = append(godPackages, GodPackage{
+			Package:      node.ID,
+			Exceeded:     exceeded,
+			TopImporters: mostConnected(g, g.Neighbors(node.ID, graph.Incoming, importKinds)),
+			TopImports:   mostConnected(g, g.Neighbors(node.ID, graph.Outgoing, importKinds)),
+		})
+	}
+
+	sort.Slice(godPackages, func(i, j int) bool { return godPackages[i].Package < godPackages[j].Package })
+	return godPackages
+}
+
+// mostConnected returns up to godPackageNeighbors of ids, highest total degree
+// first and then by ID.
+func mostConnected(g *graph.Graph, ids []string) []string {
+	degree := func(id string) int {
+		in, out := g.Degree(id)
+		return in + out
+	}
+	ranked := append([]string(nil), ids...)
+	sort.Slice(ranked, func(i, j int) bool {
+		if degree(ranked[i]) != degree(ranked[j]) {
+			return degree(ranked[i]) > degree(ranked[j])
+		}
+		return ranked[i] < ranked[j]
+	})
+	return ranked[:min(len(ranked), godPackageNeighbors)]
+}
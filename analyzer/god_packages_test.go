@@ -0,0 +1,78 @@
+package analyzer
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/Desgue/codegraph/extract"
+	"github.com/Desgue/codegraph/graph"
+)
+
+// newOversizedPackageGraph generates a graph around "mod/god", which has the
+// given number of files, lines of code, importers (mod/user00...) and imports
+// (mod/dep00...). Importers with a lower index also import every later
+// importer, so they are more connected. A small package "mod/ok" imports god.
+func newOversizedPackageGraph(t *testing.T, files, linesOfCode, importers, imports int) *graph.Graph {
+	t.Helper()
+
+	g := graph.New()
+	addNode := func(node *graph.Node) {
+		if err := g.AddNode(node); err != nil {
+			t.Fatalf("AddNode(%q) failed: %v", node.ID, err)
+		}
+	}
+	addEdge := func(from, to string) {
+		if err := g.AddEdge(&graph.Edge{From: from, To: to, Kind: graph.EdgeImport}); err != nil {
+			t.Fatalf("AddEdge(%s, %s) failed: %v", from, to, err)
+		}
+	}
+
+	god := &graph.Node{ID: "mod/god", Kind: graph.KindPackage}
+	for i := range files {
+		god.Files = append(god.Files, fmt.Sprintf("/src/god/file%02d.go", i))
+	}
+	god.SetAttribute(extract.AttributeLinesOfCode, strconv.Itoa(linesOfCode))
+	addNode(god)
+	addNode(&graph.Node{ID: "mod/ok", Kind: graph.KindPackage, Files: []string{"/src/ok/ok.go"}})
+	addEdge("mod/ok", "mod/god")
+
+	for i := range importers {
+		id := fmt.Sprintf("mod/user%02d", i)
+		addNode(&graph.Node{ID: id, Kind: graph.KindPackage})
+		addEdge(id, "mod/god")
+		for j := range i {
+			addEdge(fmt.Sprintf("mod/user%02d", j), id)
+		}
+	}
+	for i := range imports {
+		id := fmt.Sprintf("mod/dep%02d", i)
+		addNode(&graph.Node{ID: id, Kind: graph.KindPackage})
+		addEdge("mod/god", id)
+	}
+	return g
+}
+
+func TestFindGodPackages(t *testing.T) {
+	g := newOversizedPackageGraph(t, 12, 4000, 6, 2)
+	// user00 imports god and the five other users, so its fan-out is exactly 6.
+	thresholds := GodPackageThresholds{Files: 10, FanIn: 5, FanOut: 6, LinesOfCode: 5000}
+
+	want := []GodPackage{{
+		Package:      "mod/god",
+		Exceeded:     []string{"files 12 > 10", "fan-in 7 > 5"},
+		TopImporters: []string{"mod/user00", "mod/user01", "mod/user02"},
+		TopImports:   []string{"mod/dep00", "mod/dep01"},
+	}}
+	if got := FindGodPackages(g, thresholds); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindGodPackages() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if got := FindGodPackages(g, GodPackageThresholds{}); got != nil {
+		t.Errorf("expected zero thresholds to disable every check, got %+v", got)
+	}
+	if got := FindGodPackages(g, DefaultGodPackageThresholds()); got != nil {
+		t.Errorf("expected the fixture to pass the default thresholds, got %+v", got)
+	}
+}
@@ -0,0 +1,141 @@
+package analyzer
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// ImportName is one name an import path is used under, with the import
+// declarations using it as "file:line".
+type ImportName struct {
+	Name string `json:"name"`
+	// Renamed is false for declarations relying on the package's own name.
+	Renamed   bool     `json:"renamed"`
+	Positions []string `json:"positions"`
+}
+
+// InconsistentImport is an import path used under more than one name.
+type InconsistentImport struct {
+	Path  string       `json:"path"`
+	Names []ImportName `json:"names"`
+}
+
+// ShadowingAlias is an import alias equal to the name of another imported
+// package, so readers mistake one for the other.
+type ShadowingAlias struct {
+	Path     string `json:"path"`
+	Alias    string `json:"alias"`
+	Shadows  string `json:"shadows"`
+	Position string `json:"position"`
+}
+
+// ImportAliasReport is the result of CheckImportAliases.
+type ImportAliasReport struct {
+	Inconsistent []InconsistentImport `json:"inconsistent"`
+	Shadowing    []ShadowingAlias     `json:"shadowing"`
+}
+
+// CheckImportAliases collects the name every import declaration of the loaded
+// packages uses, its alias or the imported package's name, and reports the
+// paths used under more than one name and the aliases equal to another
+// imported package's name. Blank and dot imports are ignored, as are import
+// paths matching allow (as in LayerDef.Packages), e.g. versioned module paths
+// whose package name differs from the last path element. Results are sorted
+// by path. Requires NeedSyntax and NeedImports.
+func CheckImportAliases(pkgs []*packages.Package, allow []string) ImportAliasReport {
+	names := make(map[string]map[string]*ImportName)
+	type aliasUse struct{ path, alias, position string }
+	var aliasUses []aliasUse
+	// packageNames maps each imported package's own name to its paths.
+	packageNames := make(map[string]map[string]bool)
+
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			for _, importSpec := range file.Imports {
+				importPath, err := strconv.Unquote(importSpec.Path.Value)
+				if err != nil || MatchesAnyPackagePattern(allow, importPath) {
+					continue
+				}
+				packageName := importedPackageName(pkg, importPath)
+				if packageNames[packageName] == nil {
+					packageNames[packageName] = make(map[string]bool)
+				}
+				packageNames[packageName][importPath] = true
+
+				name := ImportName{Name: packageName}
+				if importSpec.Name != nil {
+					if importSpec.Name.Name == "_" || importSpec.Name.Name == "." {
+						continue
+					}
+					name = ImportName{Name: importSpec.Name.Name, Renamed: true}
+				}
+				position := pkg.Fset.Position(importSpec.Pos())
+				location := fmt.Sprintf("%s:%d", position.Filename, position.Line)
+				if name.Renamed {
+					aliasUses = append(aliasUses, aliasUse{path: importPath, alias: name.Name, position: location})
+				}
+
+				if names[importPath] == nil {
+					names[importPath] = make(map[string]*ImportName)
+				}
+				key := name.Name + "|" + strconv.FormatBool(name.Renamed)
+				if names[importPath][key] == nil {
+					names[importPath][key] = &name
+				}
+				names[importPath][key].Positions = append(names[importPath][key].Positions, location)
+			}
+		}
+	}
+
+	var report ImportAliasReport
+	for importPath, byName := range names {
+		distinct := make(map[string]bool)
+		for _, name := range byName {
+			distinct[name.Name] = true
+		}
+		if len(distinct) < 2 {
+			continue
+		}
+		inconsistent := InconsistentImport{Path: importPath}
+		for _, name := range byName {
+			sort.Strings(name.Positions)
+			inconsistent.Names = append(inconsistent.Names, *name)
+		}
+		sort.Slice(inconsistent.Names, func(i, j int) bool {
+			if inconsistent.Names[i].Name != inconsistent.Names[j].Name {
+				return inconsistent.Names[i].Name < inconsistent.Names[j].Name
+			}
+			return !inconsistent.Names[i].Renamed
+		})
+		report.Inconsistent = append(report.Inconsistent, inconsistent)
+	}
+	sort.Slice(report.Inconsistent, func(i, j int) bool { return report.Inconsistent[i].Path < report.Inconsistent[j].Path })
+
+	for _, use := range aliasUses {
+		for _, other := range sortedKeys(packageNames[use.alias]) {
+			if other != use.path {
+				report.Shadowing = append(report.Shadowing, ShadowingAlias{Path: use.path, Alias: use.alias, Shadows: other, Position: use.position})
+			}
+		}
+	}
+	sort.Slice(report.Shadowing, func(i, j int) bool {
+		if report.Shadowing[i].Position != report.Shadowing[j].Position {
+			return report.Shadowing[i].Position < report.Shadowing[j].Position
+		}
+		return report.Shadowing[i].Shadows < report.Shadowing[j].Shadows
+	})
+	return report
+}
+
+// importedPackageName returns the declared name of an imported package, or
+// the last element of its path when it was not loaded.
+func importedPackageName(pkg *packages.Package, importPath string) string {
+	if imported, found := pkg.Imports[importPath]; found && imported.Name != "" {
+		return imported.Name
+	}
+	return path.Base(importPath)
+}
@@ -0,0 +1,48 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestCheckImportAliases(t *testing.T) {
+	files := map[string]string{
+		"ctxutil/ctxutil.go": "package ctxutil\n\nfunc Value() {}\n",
+		"web/web.go":         "package web\n\nfunc Serve() {}\n",
+		"app/a.go":           "package app\n\nimport cu \"deadmod/ctxutil\"\n\nvar _ = cu.Value\n",
+		"app/b.go":           "package app\n\nimport (\n\t\"deadmod/ctxutil\"\n\t_ \"deadmod/web\"\n)\n\nvar _ = ctxutil.Value\n",
+		"app/c.go":           "package app\n\nimport (\n\tcontextutil \"deadmod/ctxutil\"\n\thttp \"deadmod/web\"\n)\n\nvar _, _ = contextutil.Value, http.Serve\n",
+		"server/server.go":   "package server\n\nimport \"net/http\"\n\nvar _ = http.StatusOK\n",
+	}
+	pkgs := loadDeadCodeModule(t, files, false)
+	position := func(file string, line int) string {
+		for _, pkg := range pkgs {
+			for _, goFile := range pkg.GoFiles {
+				if strings.HasSuffix(filepath.ToSlash(goFile), "/"+file) {
+					return goFile + ":" + strconv.Itoa(line)
+				}
+			}
+		}
+		t.Fatalf("no loaded file %s", file)
+		return ""
+	}
+
+	want := ImportAliasReport{
+		Inconsistent: []InconsistentImport{{Path: "deadmod/ctxutil", Names: []ImportName{
+			{Name: "contextutil", Renamed: true, Positions: []string{position("app/c.go", 4)}},
+			{Name: "ctxutil", Positions: []string{position("app/b.go", 4)}},
+			{Name: "cu", Renamed: true, Positions: []string{position("app/a.go", 3)}},
+		}}},
+		Shadowing: []ShadowingAlias{{Path: "deadmod/web", Alias: "http", Shadows: "net/http", Position: position("app/c.go", 5)}},
+	}
+	if got := CheckImportAliases(pkgs, nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckImportAliases() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if got := CheckImportAliases(pkgs, []string{"deadmod/..."}); got.Inconsistent != nil || got.Shadowing != nil {
+		t.Errorf("expected allowlisted paths to be skipped, got %+v", got)
+	}
+}
@@ -0,0 +1,264 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Desgue/codegraph/graph"
+	"golang.org/x/tools/go/packages"
+)
+
+// AttributeInitSideEffects lists, sorted and comma-separated, the kinds of
+// side effect a package node's initialization causes.
+const AttributeInitSideEffects = "init_side_effects"
+
+// Kinds of init-time side effect.
+const (
+	InitEffectFileIO       = "file_io"
+	InitEffectNetwork      = "network"
+	InitEffectDatabase     = "database"
+	InitEffectProcess      = "process"
+	InitEffectEnv          = "env"
+	InitEffectGoroutine    = "goroutine"
+	InitEffectRegistration = "registration"
+)
+
+// InitEffectKinds lists every init side effect kind.
+var InitEffectKinds = []string{InitEffectFileIO, InitEffectNetwork, InitEffectDatabase, InitEffectProcess,
+	InitEffectEnv, InitEffectGoroutine, InitEffectRegistration}
+
+// DefaultInitEffectDepth is the number of calls FindInitSideEffects follows
+// from a package's initialization by default.
+const DefaultInitEffectDepth = 4
+
+// InitEffectCallee classifies calls of the functions and methods whose
+// symbol, as "import/path.Name" or "import/path.Type.Method", is Prefix or
+// lies under it: "os" covers "os.Open", "os.File.Write" and "os/exec.Command".
+type InitEffectCallee struct {
+	Prefix string
+	// Kind is the side effect, or "" for callees under a broader prefix that
+	// have none.
+	Kind string
+}
+
+// InitEffectCallees is the table calls are classified by; the longest
+// matching prefix wins. Calls it does not match are followed into the
+// loaded packages.
+var InitEffectCallees = []InitEffectCallee{
+	{Prefix: "os", Kind: InitEffectFileIO},
+	{Prefix: "os.Getenv", Kind: InitEffectEnv},
+	{Prefix: "os.LookupEnv", Kind: InitEffectEnv},
+	{Prefix: "os.Environ", Kind: InitEffectEnv},
+	{Prefix: "os.ExpandEnv", Kind: InitEffectEnv},
+	{Prefix: "os.IsExist"},
+	{Prefix: "os.IsNotExist"},
+	{Prefix: "os.IsPermission"},
+	{Prefix: "os/exec", Kind: InitEffectProcess},
+	{Prefix: "os/signal"},
+	{Prefix: "os/user"},
+	{Prefix: "io/ioutil", Kind: InitEffectFileIO},
+	{Prefix: "syscall.Getenv", Kind: InitEffectEnv},
+	{Prefix: "net", Kind: InitEffectNetwork},
+	{Prefix: "net/url"},
+	{Prefix: "net/netip"},
+	{Prefix: "net/http.Handle", Kind: InitEffectRegistration},
+	{Prefix: "net/http.HandleFunc", Kind: InitEffectRegistration},
+	{Prefix: "database/sql", Kind: InitEffectDatabase},
+	{Prefix: "database/sql.Register", Kind: InitEffectRegistration},
+	{Prefix: "encoding/gob.Register", Kind: InitEffectRegistration},
+	{Prefix: "encoding/gob.RegisterName", Kind: InitEffectRegistration},
+	{Prefix: "expvar.Publish", Kind: InitEffectRegistration},
+	{Prefix: "image.RegisterFormat", Kind: InitEffectRegistration},
+}
+
+// InitSideEffect is a side effect reached from a package's initialization.
+type InitSideEffect struct {
+	Package string `json:"package"`
+	Kind    string `json:"kind"`
+	// Chain runs from the package's init function or initialized variable,
+	// through the loaded functions called, to the effect: the effectful
+	// callee, the global map written, or "go" for a goroutine.
+	Chain []string `json:"chain"`
+	// Position is the file:line of the effect.
+	Position string `json:"position"`
+}
+
+// initBody is a declared function body to follow calls into.
+type initBody struct {
+	pkg  *packages.Package
+	body *ast.BlockStmt
+}
+
+// initFrame is a body reached from a package's initialization.
+type initFrame struct {
+	pkg   *packages.Package
+	node  ast.Node
+	chain []string
+}
+
+// FindInitSideEffects returns the side effects of the packages' init
+// functions and package-level variable initializers: calls InitEffectCallees
+// classifies, goroutines started, and writes to package-level maps of another
+// package, the usual registration pattern. Calls of functions declared in the
+// loaded packages are followed breadth-first, at most maxDepth deep, so each
+// effect comes with its shortest chain. Calls through interfaces and function
+// values are not followed. Results are sorted by package, in discovery order
+// within a package. Requires NeedSyntax, NeedTypes and NeedTypesInfo.
+func FindInitSideEffects(pkgs []*packages.Package, maxDepth int) []InitSideEffect {
+	bodies := make(map[*types.Func]initBody)
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			for _, declaration := range file.Decls {
+				funcDecl, isFunc := declaration.(*ast.FuncDecl)
+				if !isFunc || funcDecl.Body == nil {
+					continue
+				}
+				if function, isFunction := pkg.TypesInfo.Defs[funcDecl.Name].(*types.Func); isFunction {
+					bodies[function] = initBody{pkg: pkg, body: funcDecl.Body}
+				}
+			}
+		}
+	}
+
+	var effects []InitSideEffect
+	for _, pkg := range pkgs {
+		effects = append(effects, packageInitSideEffects(pkg, bodies, maxDepth)...)
+	}
+	sort.SliceStable(effects, func(i, j int) bool { return effects[i].Package < effects[j].Package })
+	return effects
+}
+
+func packageInitSideEffects(pkg *packages.Package, bodies map[*types.Func]initBody, maxDepth int) []InitSideEffect {
+	var queue []initFrame
+	for _, file := range pkg.Syntax {
+		for _, declaration := range file.Decls {
+			switch declaration := declaration.(type) {
+			case *ast.FuncDecl:
+				if declaration.Name.Name == "init" && declaration.Recv == nil && declaration.Body != nil {
+					queue = append(queue, initFrame{pkg: pkg, node: declaration.Body, chain: []string{pkg.PkgPath + ".init"}})
+				}
+			case *ast.GenDecl:
+				for _, spec := range declaration.Specs {
+					valueSpec, isValue := spec.(*ast.ValueSpec)
+					if !isValue || len(valueSpec.Values) == 0 {
+						continue
+					}
+					queue = append(queue, initFrame{pkg: pkg, node: valueSpec, chain: []string{pkg.PkgPath + "." + valueSpec.Names[0].Name}})
+				}
+			}
+		}
+	}
+
+	var effects []InitSideEffect
+	seen := make(map[string]bool)
+	followed := make(map[*types.Func]bool)
+	record := func(frame initFrame, kind, last string, node ast.Node) {
+		position := frame.pkg.Fset.Position(node.Pos())
+		location := fmt.Sprintf("%s:%d", position.Filename, position.Line)
+		if seen[kind+" "+location] {
+			return
+		}
+		seen[kind+" "+location] = true
+		effects = append(effects, InitSideEffect{Package: pkg.PkgPath, Kind: kind,
+			Chain: append(slices.Clip(frame.chain), last), Position: location})
+	}
+
+	for len(queue) > 0 {
+		frame := queue[0]
+		queue = queue[1:]
+		ast.Inspect(frame.node, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.GoStmt:
+				record(frame, InitEffectGoroutine, "go", node)
+			case *ast.AssignStmt:
+				for _, lhs := range node.Lhs {
+					if variable := foreignMapVar(frame.pkg, pkg.PkgPath, lhs); variable != nil {
+						record(frame, InitEffectRegistration, objectKey(variable), node)
+					}
+				}
+			case *ast.CallExpr:
+				callee := calledFunction(frame.pkg, node)
+				if callee == nil {
+					return true
+				}
+				symbol := objectKey(callee)
+				if kind := classifyInitCallee(symbol); kind != "" {
+					record(frame, kind, symbol, node)
+					return true
+				}
+				body, declared := bodies[callee]
+				if declared && !followed[callee] && len(frame.chain) <= maxDepth {
+					followed[callee] = true
+					queue = append(queue, initFrame{pkg: body.pkg, node: body.body, chain: append(slices.Clip(frame.chain), symbol)})
+				}
+			}
+			return true
+		})
+	}
+	return effects
+}
+
+// classifyInitCallee returns the kind of the longest InitEffectCallees prefix
+// matching symbol, or "".
+func classifyInitCallee(symbol string) string {
+	kind, longest := "", -1
+	for _, callee := range InitEffectCallees {
+		if len(callee.Prefix) <= longest {
+			continue
+		}
+		if symbol == callee.Prefix || strings.HasPrefix(symbol, callee.Prefix+".") || strings.HasPrefix(symbol, callee.Prefix+"/") {
+			kind, longest = callee.Kind, len(callee.Prefix)
+		}
+	}
+	return kind
+}
+
+// foreignMapVar returns the package-level map variable written by the
+// assignment target lhs, such as registry[name] in package registry, when it
+// belongs to a package other than initialized.
+func foreignMapVar(pkg *packages.Package, initialized string, lhs ast.Expr) *types.Var {
+	index, isIndex := ast.Unparen(lhs).(*ast.IndexExpr)
+	if !isIndex {
+		return nil
+	}
+	var ident *ast.Ident
+	switch target := ast.Unparen(index.X).(type) {
+	case *ast.Ident:
+		ident = target
+	case *ast.SelectorExpr:
+		ident = target.Sel
+	default:
+		return nil
+	}
+	variable, isVar := pkg.TypesInfo.Uses[ident].(*types.Var)
+	if !isVar || variable.Pkg() == nil || variable.Parent() != variable.Pkg().Scope() || variable.Pkg().Path() == initialized {
+		return nil
+	}
+	if _, isMap := variable.Type().Underlying().(*types.Map); !isMap {
+		return nil
+	}
+	return variable
+}
+
+// ApplyInitSideEffects sets AttributeInitSideEffects on the package nodes of g
+// whose initialization has effects.
+func ApplyInitSideEffects(g *graph.Graph, effects []InitSideEffect) {
+	kinds := make(map[string][]string)
+	for _, effect := range effects {
+		if !slices.Contains(kinds[effect.Package], effect.Kind) {
+			kinds[effect.Package] = append(kinds[effect.Package], effect.Kind)
+		}
+	}
+	for id, packageKinds := range kinds {
+		node, found := g.Node(id)
+		if !found || node.Kind != graph.KindPackage {
+			continue
+		}
+		sort.Strings(packageKinds)
+		node.SetAttribute(AttributeInitSideEffects, strings.Join(packageKinds, ","))
+	}
+}
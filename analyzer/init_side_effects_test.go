@@ -0,0 +1,109 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+func TestFindInitSideEffects(t *testing.T) {
+	pkgs := loadDeadCodeModule(t, map[string]string{
+		"registry/registry.go": `package registry
+
+var drivers = map[string]string{}
+
+func Register(name string) { drivers[name] = name }
+`,
+		"config/config.go": `package config
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+
+	"deadmod/registry"
+)
+
+var home = os.Getenv("HOME")
+
+var endpoint, _ = url.Parse("https://example.com")
+
+func init() {
+	registry.Register("config")
+	setup()
+	go func() {}()
+}
+
+func setup() { fetch() }
+
+func fetch() { http.Get("https://example.com") }
+`,
+		"pure/pure.go": `package pure
+
+var answer = compute()
+
+func compute() int { return 42 }
+`,
+	}, false)
+
+	effects := FindInitSideEffects(pkgs, DefaultInitEffectDepth)
+
+	var got []string
+	for _, effect := range effects {
+		if effect.Package != "deadmod/config" {
+			t.Errorf("unexpected effect %+v", effect)
+		}
+		got = append(got, effect.Kind+" "+strings.Join(effect.Chain, " -> "))
+	}
+	want := []string{
+		"env deadmod/config.home -> os.Getenv",
+		"goroutine deadmod/config.init -> go",
+		"registration deadmod/config.init -> deadmod/registry.Register -> deadmod/registry.drivers",
+		"network deadmod/config.init -> deadmod/config.setup -> deadmod/config.fetch -> net/http.Get",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("effects = %v, want %v", got, want)
+	}
+
+	shallow := FindInitSideEffects(pkgs, 1)
+	for _, effect := range shallow {
+		if effect.Kind == InitEffectNetwork {
+			t.Errorf("depth 1 reached %v", effect.Chain)
+		}
+	}
+
+	g := graph.New()
+	g.AddNode(&graph.Node{ID: "deadmod/config", Kind: graph.KindPackage})
+	g.AddNode(&graph.Node{ID: "deadmod/pure", Kind: graph.KindPackage})
+	ApplyInitSideEffects(g, effects)
+	config, _ := g.Node("deadmod/config")
+	if value := config.Attributes[AttributeInitSideEffects]; value != "env,goroutine,network,registration" {
+		t.Errorf("config %s = %q", AttributeInitSideEffects, value)
+	}
+	pure, _ := g.Node("deadmod/pure")
+	if _, found := pure.Attributes[AttributeInitSideEffects]; found {
+		t.Errorf("pure has %s", AttributeInitSideEffects)
+	}
+}
+
+func TestClassifyInitCallee(t *testing.T) {
+	tests := map[string]string{
+		"os.Open":             InitEffectFileIO,
+		"os.File.Write":       InitEffectFileIO,
+		"os.Getenv":           InitEffectEnv,
+		"os.IsNotExist":       "",
+		"os/exec.Command":     InitEffectProcess,
+		"net/http.Get":        InitEffectNetwork,
+		"net/http.HandleFunc": InitEffectRegistration,
+		"net/url.Parse":       "",
+		"netx.Dial":           "",
+		"fmt.Println":         "",
+	}
+	for symbol, want := range tests {
+		if got := classifyInitCallee(symbol); got != want {
+			t.Errorf("classifyInitCallee(%q) = %q, want %q", symbol, got, want)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package analyzer
+
+import (
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Satisfaction values of a MatrixCell.
+const (
+	// SatisfiedByValue means both T and *T implement the interface.
+	SatisfiedByValue = "value"
+	// SatisfiedByPointer means only *T implements the interface, because some
+	// of the methods have pointer receivers.
+	SatisfiedByPointer = "pointer"
+	NotSatisfied       = "no"
+)
+
+// MatrixCell records whether one concrete type satisfies one interface.
+type MatrixCell struct {
+	Interface string `json:"interface"`
+	Type      string `json:"type"`
+	Satisfies string `json:"satisfies"`
+	// Missing lists the interface methods *T lacks, or has with a different
+	// signature. It is only filled in for types within the near limit.
+	Missing []string `json:"missing,omitempty"`
+}
+
+// InterfaceMatrix is the satisfaction of every selected interface by every
+// selected concrete type. Interfaces, Types and Cells are sorted, Cells by
+// interface and then type.
+type InterfaceMatrix struct {
+	Interfaces []string     `json:"interfaces"`
+	Types      []string     `json:"types"`
+	Cells      []MatrixCell `json:"cells"`
+}
+
+// Cell returns the cell for an interface and type, and whether both are in the
+// matrix.
+func (m InterfaceMatrix) Cell(iface, typeName string) (MatrixCell, bool) {
+	i := sort.SearchStrings(m.Interfaces, iface)
+	j := sort.SearchStrings(m.Types, typeName)
+	if i == len(m.Interfaces) || m.Interfaces[i] != iface || j == len(m.Types) || m.Types[j] != typeName {
+		return MatrixCell{}, false
+	}
+	return m.Cells[i*len(m.Types)+j], true
+}
+
+// SatisfactionMatrix checks every package-level interface declared in a
+// package matching interfacePatterns against every package-level concrete type
+// declared in a package matching typePatterns, using types.Implements. Empty
+// patterns select every package. Names are qualified by package path, e.g.
+// "example.com/mod/store.DB". Generic types and interfaces, and constraint
+// interfaces with type terms, are skipped: they cannot be checked without
+// instantiation. Types missing at most near methods have them listed in
+// MatrixCell.Missing. Requires NeedTypes.
+func SatisfactionMatrix(pkgs []*packages.Package, interfacePatterns, typePatterns []string, near int) InterfaceMatrix {
+	interfaces := make(map[string]*types.Interface)
+	concreteTypes := make(map[string]*types.Named)
+	for _, pkg := range pkgs {
+		if pkg.Types == nil || strings.HasSuffix(pkg.PkgPath, "_test") {
+			continue
+		}
+		selectInterfaces := len(interfacePatterns) == 0 || MatchesAnyPackagePattern(interfacePatterns, pkg.PkgPath)
+		selectTypes := len(typePatterns) == 0 || MatchesAnyPackagePattern(typePatterns, pkg.PkgPath)
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			typeName, isTypeName := scope.Lookup(name).(*types.TypeName)
+			if !isTypeName || typeName.IsAlias() {
+				continue
+			}
+			named, isNamed := typeName.Type().(*types.Named)
+			if !isNamed || named.TypeParams().Len() > 0 {
+				continue
+			}
+			qualifiedName := pkg.PkgPath + "." + name
+			if iface, isInterface := named.Underlying().(*types.Interface); isInterface {
+				if selectInterfaces && iface.IsMethodSet() {
+					interfaces[qualifiedName] = iface
+				}
+			} else if selectTypes {
+				concreteTypes[qualifiedName] = named
+			}
+		}
+	}
+
+	matrix := InterfaceMatrix{Interfaces: sortedKeys(interfaces), Types: sortedKeys(concreteTypes)}
+	matrix.Cells = make([]MatrixCell, 0, len(matrix.Interfaces)*len(matrix.Types))
+	for _, ifaceName := range matrix.Interfaces {
+		iface := interfaces[ifaceName]
+		for _, typeName := range matrix.Types {
+			named := concreteTypes[typeName]
+			cell := MatrixCell{Interface: ifaceName, Type: typeName, Satisfies: NotSatisfied}
+			switch {
+			case types.Implements(named, iface):
+				cell.Satisfies = SatisfiedByValue
+			case types.Implements(types.NewPointer(named), iface):
+				cell.Satisfies = SatisfiedByPointer
+			default:
+				if missing := missingMethods(named, iface); len(missing) <= near {
+					cell.Missing = missing
+				}
+			}
+			matrix.Cells = append(matrix.Cells, cell)
+		}
+	}
+	return matrix
+}
+
+// missingMethods lists, sorted, the methods of iface that the method set of
+// *named lacks or declares with a different signature.
+func missingMethods(named *types.Named, iface *types.Interface) []string {
+	var missing []string
+	pointer := types.NewPointer(named)
+	for i := range iface.NumMethods() {
+		method := iface.Method(i)
+		object, _, _ := types.LookupFieldOrMethod(pointer, false, method.Pkg(), method.Name())
+		// Identical ignores receivers, so this compares parameters and results.
+		if function, isFunction := object.(*types.Func); !isFunction || !types.Identical(function.Type(), method.Type()) {
+			missing = append(missing, method.Name())
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+func sortedKeys[T any](index map[string]T) []string {
+	keys := make([]string, 0, len(index))
+	for key := range index {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,80 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSatisfactionMatrix(t *testing.T) {
+	pkgs := loadDeadCodeModule(t, map[string]string{
+		"plugin/plugin.go": `package plugin
+
+type Plugin interface {
+	Name() string
+	Start() error
+	Stop() error
+}
+
+type Named interface{ Name() string }
+
+type Number interface{ ~int | ~float64 }
+
+type Registry[T any] interface{ Add(T) }
+`,
+		"impl/impl.go": `package impl
+
+type Value struct{}
+
+func (Value) Name() string { return "" }
+func (Value) Start() error  { return nil }
+func (Value) Stop() error   { return nil }
+
+type Pointer struct{}
+
+func (*Pointer) Name() string { return "" }
+func (*Pointer) Start() error  { return nil }
+func (*Pointer) Stop() error   { return nil }
+
+// Partial has Stop with the wrong signature.
+type Partial struct{}
+
+func (Partial) Name() string { return "" }
+func (Partial) Stop()        {}
+
+type Empty int
+
+type Box[T any] struct{ item T }
+`,
+	}, false)
+
+	matrix := SatisfactionMatrix(pkgs, []string{"deadmod/plugin"}, []string{"deadmod/impl"}, 0)
+	if want := []string{"deadmod/plugin.Named", "deadmod/plugin.Plugin"}; !reflect.DeepEqual(matrix.Interfaces, want) {
+		t.Errorf("Interfaces = %v, want %v", matrix.Interfaces, want)
+	}
+	wantTypes := []string{"deadmod/impl.Empty", "deadmod/impl.Partial", "deadmod/impl.Pointer", "deadmod/impl.Value"}
+	if !reflect.DeepEqual(matrix.Types, wantTypes) {
+		t.Errorf("Types = %v, want %v", matrix.Types, wantTypes)
+	}
+
+	wantCells := map[[2]string]string{
+		{"deadmod/plugin.Plugin", "deadmod/impl.Value"}:   SatisfiedByValue,
+		{"deadmod/plugin.Plugin", "deadmod/impl.Pointer"}: SatisfiedByPointer,
+		{"deadmod/plugin.Plugin", "deadmod/impl.Partial"}: NotSatisfied,
+		{"deadmod/plugin.Named", "deadmod/impl.Partial"}:  SatisfiedByValue,
+		{"deadmod/plugin.Named", "deadmod/impl.Empty"}:    NotSatisfied,
+	}
+	for key, want := range wantCells {
+		cell, found := matrix.Cell(key[0], key[1])
+		if !found || cell.Satisfies != want || cell.Missing != nil {
+			t.Errorf("Cell(%s, %s) = %+v, %v, want %q without missing methods", key[0], key[1], cell, found, want)
+		}
+	}
+
+	near := SatisfactionMatrix(pkgs, []string{"deadmod/plugin"}, []string{"deadmod/impl"}, 2)
+	if cell, _ := near.Cell("deadmod/plugin.Plugin", "deadmod/impl.Partial"); !reflect.DeepEqual(cell.Missing, []string{"Start", "Stop"}) {
+		t.Errorf("expected Partial to miss Start and Stop, got %+v", cell)
+	}
+	if cell, _ := near.Cell("deadmod/plugin.Plugin", "deadmod/impl.Empty"); cell.Missing != nil {
+		t.Errorf("expected Empty, missing 3 methods, to be beyond the near limit, got %+v", cell)
+	}
+}
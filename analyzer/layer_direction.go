@@ -0,0 +1,47 @@
+package analyzer
+
+import (
+	"sort"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+// LayerViolation is an import edge pointing from a lower layer up to a higher one.
+type LayerViolation struct {
+	From      string
+	To        string
+	FromLayer string
+	ToLayer   string
+}
+
+// CheckLayerDirection reports import edges that point upward through layers,
+// which are ordered from highest to lowest: a layer may import itself and the
+// layers after it, never the layers before it. It also returns the sorted IDs
+// of package nodes that belong to no layer. Edges involving such packages are
+// not checked. Violations follow the graph's edge order.
+func CheckLayerDirection(g *graph.Graph, layers []LayerDef) (violations []LayerViolation, unmapped []string) {
+	for _, edge := range g.Edges() {
+		if edge.Kind != graph.EdgeImport {
+			continue
+		}
+		fromLayer := layerIndex(layers, edge.From)
+		toLayer := layerIndex(layers, edge.To)
+		if fromLayer < 0 || toLayer < 0 || toLayer >= fromLayer {
+			continue
+		}
+		violations = append(violations, LayerViolation{
+			From:      edge.From,
+			To:        edge.To,
+			FromLayer: layers[fromLayer].Name,
+			ToLayer:   layers[toLayer].Name,
+		})
+	}
+
+	for _, node := range g.Nodes() {
+		if node.Kind == graph.KindPackage && layerIndex(layers, node.ID) < 0 {
+			unmapped = append(unmapped, node.ID)
+		}
+	}
+	sort.Strings(unmapped)
+	return violations, unmapped
+}
@@ -0,0 +1,63 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+func TestCheckLayerDirection(t *testing.T) {
+	g := graph.New()
+	ids := []string{
+		"example.com/mod/transport/http", "example.com/mod/transport/grpc",
+		"example.com/mod/service", "example.com/mod/domain", "example.com/mod/domain/events",
+		"example.com/mod/tools",
+	}
+	for _, id := range ids {
+		if err := g.AddNode(&graph.Node{ID: id, Kind: graph.KindPackage}); err != nil {
+			t.Fatalf("AddNode(%q) failed: %v", id, err)
+		}
+	}
+	edges := []graph.Edge{
+		// Downward, including skipping a layer, and within a layer: allowed.
+		{From: "example.com/mod/transport/http", To: "example.com/mod/service", Kind: graph.EdgeImport},
+		{From: "example.com/mod/transport/grpc", To: "example.com/mod/domain", Kind: graph.EdgeImport},
+		{From: "example.com/mod/transport/http", To: "example.com/mod/transport/grpc", Kind: graph.EdgeImport},
+		// Upward: violations.
+		{From: "example.com/mod/domain", To: "example.com/mod/service", Kind: graph.EdgeImport},
+		{From: "example.com/mod/domain/events", To: "example.com/mod/transport/http", Kind: graph.EdgeImport},
+		{From: "example.com/mod/service", To: "example.com/mod/transport/grpc", Kind: graph.EdgeImport},
+		// Upward but test-only, or from an unmapped package: ignored.
+		{From: "example.com/mod/domain", To: "example.com/mod/transport/http", Kind: graph.EdgeTestImport},
+		{From: "example.com/mod/tools", To: "example.com/mod/transport/http", Kind: graph.EdgeImport},
+	}
+	for _, edge := range edges {
+		if err := g.AddEdge(&edge); err != nil {
+			t.Fatalf("AddEdge(%v) failed: %v", edge, err)
+		}
+	}
+
+	// domain/events matches both the overlapping service glob and domain;
+	// service comes first, so it is in service and its upward import to
+	// transport is still caught.
+	layers := []LayerDef{
+		{Name: "transport", Packages: []string{"example.com/mod/transport/..."}},
+		{Name: "service", Packages: []string{"example.com/mod/service", "example.com/mod/*/events"}},
+		{Name: "domain", Packages: []string{"example.com/mod/domain/..."}},
+	}
+
+	violations, unmapped := CheckLayerDirection(g, layers)
+
+	want := []LayerViolation{
+		{From: "example.com/mod/domain", To: "example.com/mod/service", FromLayer: "domain", ToLayer: "service"},
+		{From: "example.com/mod/domain/events", To: "example.com/mod/transport/http", FromLayer: "service", ToLayer: "transport"},
+		{From: "example.com/mod/service", To: "example.com/mod/transport/grpc", FromLayer: "service", ToLayer: "transport"},
+	}
+	if !reflect.DeepEqual(violations, want) {
+		t.Errorf("violations = %+v, want %+v", violations, want)
+	}
+	if want := []string{"example.com/mod/tools"}; !reflect.DeepEqual(unmapped, want) {
+		t.Errorf("unmapped = %v, want %v", unmapped, want)
+	}
+}
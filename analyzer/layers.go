@@ -0,0 +1,68 @@
+// Package analyzer implements architectural checks and metrics over dependency graphs.
+package analyzer
+
+import (
+	"path"
+	"strings"
+)
+
+// LayerDef assigns packages to an architectural layer.
+// Layers are passed ordered from the highest level (e.g. application logic)
+// to the lowest level (e.g. infrastructure).
+type LayerDef struct {
+	Name string
+	// Packages holds import path patterns. A pattern ending in "/..." matches
+	// the path itself and everything below it; other patterns match the whole
+	// path. Patterns may use path.Match wildcards within segments, e.g.
+	// "example.com/mod/*/handlers/...". A package matched by several layers
+	// belongs to the first of them in declaration order.
+	Packages []string
+	// Abstract marks layers that must only declare interfaces, so that
+	// higher layers can depend on them without depending on implementations.
+	Abstract bool
+}
+
+// Contains reports whether the package with import path pkgPath belongs to the layer.
+func (l LayerDef) Contains(pkgPath string) bool {
+	for _, pattern := range l.Packages {
+		if matchesPackagePattern(pattern, pkgPath) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesPackagePattern(pattern, pkgPath string) bool {
+	prefix, isWildcard := strings.CutSuffix(pattern, "/...")
+	if !isWildcard {
+		return matchesPathGlob(pattern, pkgPath)
+	}
+	// Match the prefix against the same number of leading segments.
+	segments := strings.Split(pkgPath, "/")
+	prefixLength := strings.Count(prefix, "/") + 1
+	if len(segments) < prefixLength {
+		return false
+	}
+	return matchesPathGlob(prefix, strings.Join(segments[:prefixLength], "/"))
+}
+
+// matchesPathGlob reports whether pkgPath matches pattern with path.Match
+// semantics. A malformed pattern only matches itself.
+func matchesPathGlob(pattern, pkgPath string) bool {
+	matched, err := path.Match(pattern, pkgPath)
+	if err != nil {
+		return pattern == pkgPath
+	}
+	return matched
+}
+
+// layerIndex returns the position of the first layer containing pkgPath, or -1.
+// Declaration order is the precedence between overlapping patterns.
+func layerIndex(layers []LayerDef, pkgPath string) int {
+	for i, layer := range layers {
+		if layer.Contains(pkgPath) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,65 @@
+package analyzer
+
+import "testing"
+
+func TestLayerDef_Contains(t *testing.T) {
+	layer := LayerDef{Name: "domain", Packages: []string{"example.com/mod/domain/...", "example.com/mod/model"}}
+
+	tests := []struct {
+		pkgPath string
+		want    bool
+	}{
+		{pkgPath: "example.com/mod/domain", want: true},
+		{pkgPath: "example.com/mod/domain/orders", want: true},
+		{pkgPath: "example.com/mod/domainx", want: false},
+		{pkgPath: "example.com/mod/model", want: true},
+		{pkgPath: "example.com/mod/model/sub", want: false},
+		{pkgPath: "example.com/mod/app", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := layer.Contains(tt.pkgPath); got != tt.want {
+			t.Errorf("Contains(%q) = %v, want %v", tt.pkgPath, got, tt.want)
+		}
+	}
+}
+
+func TestMatchesPackagePattern_Globs(t *testing.T) {
+	tests := []struct {
+		pattern string
+		pkgPath string
+		want    bool
+	}{
+		{pattern: "example.com/mod/*/handlers", pkgPath: "example.com/mod/api/handlers", want: true},
+		{pattern: "example.com/mod/*/handlers", pkgPath: "example.com/mod/api/v1/handlers", want: false},
+		{pattern: "example.com/mod/*/handlers/...", pkgPath: "example.com/mod/api/handlers/users", want: true},
+		{pattern: "example.com/mod/*/handlers/...", pkgPath: "example.com/mod/api/handlers", want: true},
+		{pattern: "example.com/mod/*/handlers/...", pkgPath: "example.com/mod/api", want: false},
+		{pattern: "example.com/mod/store*", pkgPath: "example.com/mod/stores", want: true},
+		{pattern: "example.com/mod/store*", pkgPath: "example.com/mod/store/sql", want: false},
+		{pattern: "example.com/mod/[", pkgPath: "example.com/mod/[", want: true},
+	}
+
+	for _, tt := range tests {
+		if got := matchesPackagePattern(tt.pattern, tt.pkgPath); got != tt.want {
+			t.Errorf("matchesPackagePattern(%q, %q) = %v, want %v", tt.pattern, tt.pkgPath, got, tt.want)
+		}
+	}
+}
+
+func TestLayerIndex_FirstMatchWins(t *testing.T) {
+	layers := []LayerDef{
+		{Name: "api", Packages: []string{"example.com/mod/api"}},
+		{Name: "everything", Packages: []string{"example.com/mod/..."}},
+	}
+
+	if got := layerIndex(layers, "example.com/mod/api"); got != 0 {
+		t.Errorf("layerIndex(api) = %d, want 0", got)
+	}
+	if got := layerIndex(layers, "example.com/mod/store"); got != 1 {
+		t.Errorf("layerIndex(store) = %d, want 1", got)
+	}
+	if got := layerIndex(layers, "example.com/other"); got != -1 {
+		t.Errorf("layerIndex(other) = %d, want -1", got)
+	}
+}
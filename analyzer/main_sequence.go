@@ -0,0 +1,44 @@
+package analyzer
+
+import (
+	"math"
+
+	"github.com/Desgue/codegraph/graph"
+	"github.com/Desgue/codegraph/graph/metrics"
+	"github.com/Desgue/codegraph/parser"
+	"golang.org/x/tools/go/packages"
+)
+
+// ComputeAbstractness returns, keyed by package path, the share of
+// package-level named types that are interfaces: 0 for a fully concrete
+// package, 1 for a fully abstract one. Packages declaring no types score 0.
+func ComputeAbstractness(pkgs []*packages.Package) map[string]float64 {
+	scores := make(map[string]float64, len(pkgs))
+	for _, pkg := range pkgs {
+		scores[pkg.PkgPath] = abstractness(parser.CountTypes(pkg))
+	}
+	return scores
+}
+
+// DistanceFromMainSequence returns the normalised distance D = |A + I - 1| of
+// every node from the main sequence, where A is its abstractness, taken from
+// Node.InterfaceCount and Node.ConcreteTypeCount, and I its instability. A
+// score near 1 marks the zone of pain (stable and concrete, hard to change)
+// or the zone of uselessness (unstable and abstract, depended on by nothing).
+func DistanceFromMainSequence(g *graph.Graph) map[string]float64 {
+	instability := metrics.Instability(g)
+
+	distance := make(map[string]float64, len(g.Nodes()))
+	for _, node := range g.Nodes() {
+		a := abstractness(node.InterfaceCount, node.ConcreteTypeCount)
+		distance[node.ID] = math.Abs(a + instability[node.ID] - 1)
+	}
+	return distance
+}
+
+func abstractness(interfaces, concrete int) float64 {
+	if interfaces+concrete == 0 {
+		return 0
+	}
+	return float64(interfaces) / float64(interfaces+concrete)
+}
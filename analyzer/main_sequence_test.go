@@ -0,0 +1,48 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+func TestComputeAbstractness(t *testing.T) {
+	pkgs := loadDeadCodeModule(t, map[string]string{
+		"ports/ports.go": "package ports\n\ntype Reader interface{ Read() }\n\ntype Writer interface{ Write() }\n",
+		"store/store.go": "package store\n\ntype Reader interface{ Read() }\n\ntype DB struct{}\n\ntype Row struct{}\n\ntype ID int\n",
+		"util/util.go":   "package util\n\nfunc Helper() {}\n",
+	}, false)
+
+	want := map[string]float64{"deadmod/ports": 1, "deadmod/store": 0.25, "deadmod/util": 0}
+	if got := ComputeAbstractness(pkgs); !reflect.DeepEqual(got, want) {
+		t.Errorf("ComputeAbstractness() = %v, want %v", got, want)
+	}
+}
+
+func TestDistanceFromMainSequence(t *testing.T) {
+	g := graph.New()
+	nodes := []*graph.Node{
+		// Only imported and fully concrete: the zone of pain.
+		{ID: "core", Kind: graph.KindPackage, ConcreteTypeCount: 4},
+		// Only importing and fully abstract: the zone of uselessness.
+		{ID: "api", Kind: graph.KindPackage, InterfaceCount: 2},
+		// Balanced instability and abstractness: on the main sequence.
+		{ID: "service", Kind: graph.KindPackage, InterfaceCount: 1, ConcreteTypeCount: 1},
+	}
+	for _, node := range nodes {
+		if err := g.AddNode(node); err != nil {
+			t.Fatalf("AddNode(%q) failed: %v", node.ID, err)
+		}
+	}
+	for _, edge := range [][2]string{{"api", "service"}, {"service", "core"}} {
+		if err := g.AddEdge(&graph.Edge{From: edge[0], To: edge[1], Kind: graph.EdgeImport}); err != nil {
+			t.Fatalf("AddEdge(%v) failed: %v", edge, err)
+		}
+	}
+
+	want := map[string]float64{"core": 1, "api": 1, "service": 0}
+	if got := DistanceFromMainSequence(g); !reflect.DeepEqual(got, want) {
+		t.Errorf("DistanceFromMainSequence() = %v, want %v", got, want)
+	}
+}
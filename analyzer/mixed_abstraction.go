@@ -0,0 +1,71 @@
+package analyzer
+
+import (
+	"sort"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+// DefaultMixedAbstractionThreshold is the widest span of layers a package's
+// imports may cover before DetectMixedAbstractionLevels reports it.
+const DefaultMixedAbstractionThreshold = 3
+
+// MixedAbstraction is a package importing both high-level packages, close to
+// the entry points, and low-level ones, close to the leaves.
+type MixedAbstraction struct {
+	Package string
+	// HighestImport and LowestImport are the imports with the lowest and the
+	// highest graph.LayeredLayout layer, and HighestLayer and LowestLayer
+	// their layers. Span is their difference.
+	HighestImport, LowestImport string
+	HighestLayer, LowestLayer   int
+	Span                        int
+}
+
+// DetectMixedAbstractionLevels returns the packages whose production imports
+// span more than threshold layers of graph.LayeredLayout, sorted by ID. Only
+// EdgeImport edges are laid out, since test imports routinely point back up.
+// It fails with graph.ErrCyclic when the production imports form a cycle,
+// which leaves the layers undefined.
+func DetectMixedAbstractionLevels(g *graph.Graph, threshold int) ([]MixedAbstraction, error) {
+	imports := graph.New()
+	for _, node := range g.Nodes() {
+		if node.Kind == graph.KindPackage {
+			imports.AddNode(node)
+		}
+	}
+	for _, edge := range g.Edges() {
+		if edge.Kind == graph.EdgeImport {
+			imports.AddEdge(edge)
+		}
+	}
+	layers, err := graph.LayeredLayout(imports)
+	if err != nil {
+		return nil, err
+	}
+
+	var mixed []MixedAbstraction
+	for _, node := range imports.Nodes() {
+		imported := imports.Neighbors(node.ID, graph.Outgoing, nil)
+		if len(imported) < 2 {
+			continue
+		}
+		finding := MixedAbstraction{Package: node.ID, HighestImport: imported[0], LowestImport: imported[0]}
+		for _, id := range imported[1:] {
+			if layers[id] < layers[finding.HighestImport] {
+				finding.HighestImport = id
+			}
+			if layers[id] > layers[finding.LowestImport] {
+				finding.LowestImport = id
+			}
+		}
+		finding.HighestLayer, finding.LowestLayer = layers[finding.HighestImport], layers[finding.LowestImport]
+		finding.Span = finding.LowestLayer - finding.HighestLayer
+		if finding.Span > threshold {
+			mixed = append(mixed, finding)
+		}
+	}
+
+	sort.Slice(mixed, func(i, j int) bool { return mixed[i].Package < mixed[j].Package })
+	return mixed, nil
+}
@@ -0,0 +1,56 @@
+package analyzer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+func TestDetectMixedAbstractionLevels(t *testing.T) {
+	// cmd -> app -> service -> repo -> db is a five-layer chain; handler
+	// imports both service (layer 2) and db (layer 4), a span of 2.
+	g := graph.New()
+	for _, id := range []string{"cmd", "app", "service", "repo", "db", "handler"} {
+		if err := g.AddNode(&graph.Node{ID: id, Kind: graph.KindPackage}); err != nil {
+			t.Fatalf("AddNode() error = %v", err)
+		}
+	}
+	edges := []*graph.Edge{
+		{From: "cmd", To: "app", Kind: graph.EdgeImport},
+		{From: "cmd", To: "handler", Kind: graph.EdgeImport},
+		{From: "app", To: "service", Kind: graph.EdgeImport},
+		{From: "service", To: "repo", Kind: graph.EdgeImport},
+		{From: "repo", To: "db", Kind: graph.EdgeImport},
+		{From: "handler", To: "service", Kind: graph.EdgeImport},
+		{From: "handler", To: "db", Kind: graph.EdgeImport},
+		// A test import pointing back up must not make the layout cyclic.
+		{From: "db", To: "cmd", Kind: graph.EdgeTestImport, IsTestOnly: true},
+	}
+	for _, edge := range edges {
+		if err := g.AddEdge(edge); err != nil {
+			t.Fatalf("AddEdge() error = %v", err)
+		}
+	}
+
+	got, err := DetectMixedAbstractionLevels(g, 1)
+	if err != nil {
+		t.Fatalf("DetectMixedAbstractionLevels() error = %v", err)
+	}
+	want := []MixedAbstraction{{Package: "handler", HighestImport: "service", LowestImport: "db", HighestLayer: 2, LowestLayer: 4, Span: 2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DetectMixedAbstractionLevels() = %+v, want %+v", got, want)
+	}
+
+	if got, _ := DetectMixedAbstractionLevels(g, DefaultMixedAbstractionThreshold); len(got) != 0 {
+		t.Errorf("expected no findings at the default threshold, got %+v", got)
+	}
+
+	if err := g.AddEdge(&graph.Edge{From: "db", To: "repo", Kind: graph.EdgeImport}); err != nil {
+		t.Fatalf("AddEdge() error = %v", err)
+	}
+	if _, err := DetectMixedAbstractionLevels(g, 1); !errors.Is(err, graph.ErrCyclic) {
+		t.Errorf("expected graph.ErrCyclic, got %v", err)
+	}
+}
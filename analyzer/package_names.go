@@ -0,0 +1,96 @@
+package analyzer
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// PackageNameImport is how one package imports a package with a colliding
+// name: under Alias, or under the package's own name when Alias is empty.
+type PackageNameImport struct {
+	Importer string `json:"importer"`
+	Alias    string `json:"alias,omitempty"`
+}
+
+// CollidingPackageName is a loaded package whose name is also the name of a
+// standard library package or of another loaded package, so files needing
+// both must rename one of them.
+type CollidingPackageName struct {
+	Package string `json:"package"`
+	Name    string `json:"name"`
+	// Stdlib and Loaded list the other packages with the same name.
+	Stdlib  []string            `json:"stdlib,omitempty"`
+	Loaded  []string            `json:"loaded,omitempty"`
+	Imports []PackageNameImport `json:"imports,omitempty"`
+}
+
+// FindCollidingPackageNames returns the loaded packages whose name, not path,
+// is also the name of a standard library package, per stdlib as returned by
+// parser.StdlibPackageNames, or of another loaded package, with how the loaded
+// packages import them. Main packages and external test packages are skipped,
+// as are packages matching allow (as in LayerDef.Packages). Results are sorted
+// by path, imports by importer then alias. Requires NeedName, NeedSyntax and
+// NeedImports.
+func FindCollidingPackageNames(pkgs []*packages.Package, stdlib map[string][]string, allow []string) []CollidingPackageName {
+	loadedNames := make(map[string][]string)
+	for _, pkg := range pkgs {
+		if pkg.Name == "main" || strings.HasSuffix(pkg.Name, "_test") {
+			continue
+		}
+		loadedNames[pkg.Name] = append(loadedNames[pkg.Name], pkg.PkgPath)
+	}
+
+	imports := make(map[string]map[PackageNameImport]bool)
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			for _, importSpec := range file.Imports {
+				importPath, err := strconv.Unquote(importSpec.Path.Value)
+				if err != nil {
+					continue
+				}
+				use := PackageNameImport{Importer: pkg.PkgPath}
+				if importSpec.Name != nil {
+					use.Alias = importSpec.Name.Name
+				}
+				if imports[importPath] == nil {
+					imports[importPath] = make(map[PackageNameImport]bool)
+				}
+				imports[importPath][use] = true
+			}
+		}
+	}
+
+	var collisions []CollidingPackageName
+	for name, paths := range loadedNames {
+		for _, pkgPath := range paths {
+			if MatchesAnyPackagePattern(allow, pkgPath) {
+				continue
+			}
+			collision := CollidingPackageName{Package: pkgPath, Name: name, Stdlib: stdlib[name]}
+			for _, other := range paths {
+				if other != pkgPath {
+					collision.Loaded = append(collision.Loaded, other)
+				}
+			}
+			if len(collision.Stdlib) == 0 && len(collision.Loaded) == 0 {
+				continue
+			}
+			sort.Strings(collision.Loaded)
+			for use := range imports[pkgPath] {
+				collision.Imports = append(collision.Imports, use)
+			}
+			sort.Slice(collision.Imports, func(i, j int) bool {
+				if collision.Imports[i].Importer != collision.Imports[j].Importer {
+					return collision.Imports[i].Importer < collision.Imports[j].Importer
+				}
+				return collision.Imports[i].Alias < collision.Imports[j].Alias
+			})
+			collisions = append(collisions, collision)
+		}
+	}
+	sort.Slice(collisions, func(i, j int) bool { return collisions[i].Package < collisions[j].Package })
+	return collisions
+}
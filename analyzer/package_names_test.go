@@ -0,0 +1,37 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindCollidingPackageNames(t *testing.T) {
+	pkgs := loadDeadCodeModule(t, map[string]string{
+		"errors/errors.go":    "package errors\n\nfunc New() {}\n",
+		"api/store/store.go":  "package store\n\nconst Value = 1\n",
+		"db/store/store.go":   "package store\n",
+		"unique/unique.go":    "package unique\n",
+		"legacy/log/log.go":   "package log\n",
+		"cmd/tool/main.go":    "package main\n\nimport (\n\tapperrors \"deadmod/errors\"\n\t_ \"deadmod/db/store\"\n)\n\nvar _ = apperrors.New\n\nfunc main() {}\n",
+		"service/service.go":  "package service\n\nimport (\n\t\"errors\"\n\n\tapperrors \"deadmod/errors\"\n\t\"deadmod/api/store\"\n)\n\nvar _ = errors.New\n\nvar _ = apperrors.New\n\nvar _ = store.Value\n",
+		"service/service2.go": "package service\n\nimport \"deadmod/errors\"\n\nvar _ = errors.New\n",
+	}, false)
+	stdlib := map[string][]string{"errors": {"errors"}, "log": {"log", "log/slog"}}
+
+	got := FindCollidingPackageNames(pkgs, stdlib, []string{"deadmod/legacy/..."})
+	want := []CollidingPackageName{
+		{Package: "deadmod/api/store", Name: "store", Loaded: []string{"deadmod/db/store"},
+			Imports: []PackageNameImport{{Importer: "deadmod/service"}}},
+		{Package: "deadmod/db/store", Name: "store", Loaded: []string{"deadmod/api/store"},
+			Imports: []PackageNameImport{{Importer: "deadmod/cmd/tool", Alias: "_"}}},
+		{Package: "deadmod/errors", Name: "errors", Stdlib: []string{"errors"},
+			Imports: []PackageNameImport{
+				{Importer: "deadmod/cmd/tool", Alias: "apperrors"},
+				{Importer: "deadmod/service"},
+				{Importer: "deadmod/service", Alias: "apperrors"},
+			}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindCollidingPackageNames() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,456 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Desgue/codegraph/graph"
+	"golang.org/x/tools/go/packages"
+)
+
+// AttributeEntrypoint marks function nodes of a call graph that run without
+// being called from loaded code, with one of the Entrypoint kinds as value.
+const AttributeEntrypoint = "entrypoint"
+
+// Entry point kinds recorded in AttributeEntrypoint.
+const (
+	EntrypointMain        = "main"
+	EntrypointInit        = "init"
+	EntrypointTestMain    = "test_main"
+	EntrypointTest        = "test"
+	EntrypointHTTPHandler = "http_handler"
+)
+
+// EntrypointKinds lists every entry point kind, for --from style selections.
+var EntrypointKinds = []string{EntrypointMain, EntrypointInit, EntrypointTestMain, EntrypointTest, EntrypointHTTPHandler}
+
+// ReachabilityNote explains what BuildCallGraph cannot see.
+const ReachabilityNote = "Calls through interfaces reach every loaded type implementing the interface, " +
+	"but functions only invoked by code outside the load (fmt calling String or Error, sort calling Less, " +
+	"handlers passed to an unrecognised router, callbacks stored in third-party types), reflection, " +
+	"go:linkname and cgo are invisible, so unreachable functions are candidates, not proof."
+
+// httpHandlerSignature is the signature of an http.HandlerFunc, as
+// signatureString renders it.
+const httpHandlerSignature = "(net/http.ResponseWriter, *net/http.Request)()"
+
+// httpRegistrationMethods are the method names through which routers such as
+// http.ServeMux, chi and gorilla/mux register handlers.
+var httpRegistrationMethods = []string{"Handle", "HandleFunc", "Get", "Post", "Put", "Patch", "Delete", "Head",
+	"Options", "Connect", "Trace", "Method", "MethodFunc", "Any"}
+
+// BuildCallGraph returns a graph with one function node per function and
+// method declared in the loaded packages, identified by graph.FuncID, and
+// graph.EdgeCall edges for every static reference, whether a call or a
+// function value. A reference to an interface method links to that method on
+// every loaded type implementing the interface. Each package also gets an
+// "init" node standing for its initialization, which covers its init
+// functions and package-level variable initializers: every function links to
+// its package's init node and every init node to those of the loaded packages
+// it imports, so linking a package in runs its initialization. Entry points
+// carry AttributeEntrypoint: func main of main packages, init functions,
+// TestMain and Test, Benchmark, Fuzz and Example functions of test files, and
+// functions or http.Handler types passed to http.Handle, http.HandleFunc or a
+// router method such as ServeMux.HandleFunc or Get. Requires NeedSyntax,
+// NeedTypes, NeedTypesInfo and NeedImports.
+func BuildCallGraph(pkgs []*packages.Package) *graph.Graph {
+	builder := &callGraphBuilder{graph: graph.New(), edges: make(map[[2]string]bool), implementations: make(map[*types.Interface][]*types.Named)}
+	for _, pkg := range pkgs {
+		builder.addFunctionNodes(pkg)
+	}
+	builder.concreteTypes = declaredConcreteTypes(pkgs)
+	for _, pkg := range pkgs {
+		builder.addReferences(pkg)
+		for _, imported := range sortedImports(pkg) {
+			builder.addEdge(initID(pkg.PkgPath), initID(imported))
+		}
+	}
+	return builder.graph
+}
+
+type callGraphBuilder struct {
+	graph *graph.Graph
+	edges map[[2]string]bool
+	// concreteTypes are the named non-interface types of the loaded packages,
+	// candidates for interface dispatch.
+	concreteTypes   []*types.Named
+	implementations map[*types.Interface][]*types.Named
+}
+
+func initID(pkgPath string) string {
+	return graph.FuncID(pkgPath, "", "init")
+}
+
+// functionID returns the node ID of a declared function or method, or "" for
+// methods whose receiver is not a named type.
+func functionID(function *types.Func) string {
+	if function.Pkg() == nil {
+		return ""
+	}
+	signature := function.Type().(*types.Signature)
+	if signature.Recv() == nil {
+		if function.Name() == "init" {
+			return initID(function.Pkg().Path())
+		}
+		return graph.FuncID(function.Pkg().Path(), "", function.Name())
+	}
+	receiver := receiverNamed(function)
+	if receiver == nil {
+		return ""
+	}
+	return graph.FuncID(function.Pkg().Path(), receiver.Obj().Name(), function.Name())
+}
+
+func (b *callGraphBuilder) addFunctionNodes(pkg *packages.Package) {
+	modulePath := ""
+	if pkg.Module != nil {
+		modulePath = pkg.Module.Path
+	}
+	b.graph.AddNode(&graph.Node{ID: initID(pkg.PkgPath), Kind: graph.KindFunction, Name: "init", ModulePath: modulePath})
+
+	for _, file := range pkg.Syntax {
+		fileName := pkg.Fset.Position(file.Pos()).Filename
+		testFile := strings.HasSuffix(fileName, "_test.go")
+		for _, declaration := range file.Decls {
+			funcDecl, isFunc := declaration.(*ast.FuncDecl)
+			if !isFunc || funcDecl.Name.Name == "_" {
+				continue
+			}
+			function, isFunction := pkg.TypesInfo.Defs[funcDecl.Name].(*types.Func)
+			if !isFunction {
+				continue
+			}
+			id := functionID(function)
+			if id == "" {
+				continue
+			}
+			if existing, found := b.graph.Node(id); found {
+				// Several init functions share the package's init node.
+				existing.Files = appendMissing(existing.Files, fileName)
+			} else {
+				b.graph.AddNode(&graph.Node{ID: id, Kind: graph.KindFunction, Name: funcDecl.Name.Name, ModulePath: modulePath,
+					Files: []string{fileName}})
+			}
+			if kind := entrypointKind(pkg, funcDecl, testFile); kind != "" {
+				b.markEntrypoint(id, kind)
+			}
+		}
+	}
+}
+
+func appendMissing(values []string, value string) []string {
+	if slices.Contains(values, value) {
+		return values
+	}
+	return append(values, value)
+}
+
+// entrypointKind classifies functions the runtime or the go tool calls.
+func entrypointKind(pkg *packages.Package, funcDecl *ast.FuncDecl, testFile bool) string {
+	if funcDecl.Recv != nil {
+		return ""
+	}
+	name := funcDecl.Name.Name
+	switch {
+	case name == "init":
+		return EntrypointInit
+	case name == "main" && pkg.Name == "main" && !testFile:
+		return EntrypointMain
+	case !testFile:
+		return ""
+	case name == "TestMain":
+		return EntrypointTestMain
+	}
+	for _, prefix := range []string{"Test", "Benchmark", "Fuzz", "Example"} {
+		if strings.HasPrefix(name, prefix) {
+			return EntrypointTest
+		}
+	}
+	return ""
+}
+
+// markEntrypoint records kind on the node, keeping an earlier kind.
+func (b *callGraphBuilder) markEntrypoint(id, kind string) {
+	node, found := b.graph.Node(id)
+	if !found || node.Attributes[AttributeEntrypoint] != "" {
+		return
+	}
+	node.SetAttribute(AttributeEntrypoint, kind)
+}
+
+func (b *callGraphBuilder) addEdge(from, to string) {
+	if from == to || b.edges[[2]string{from, to}] {
+		return
+	}
+	if _, found := b.graph.Node(to); !found {
+		return
+	}
+	if _, found := b.graph.Node(from); !found {
+		return
+	}
+	b.edges[[2]string{from, to}] = true
+	b.graph.AddEdge(&graph.Edge{From: from, To: to, Kind: graph.EdgeCall})
+}
+
+// addReferences adds the edges of every function body in pkg, and of the
+// package-level variable initializers, which run as part of the init node.
+func (b *callGraphBuilder) addReferences(pkg *packages.Package) {
+	for _, file := range pkg.Syntax {
+		for _, declaration := range file.Decls {
+			switch declaration := declaration.(type) {
+			case *ast.FuncDecl:
+				function, isFunction := pkg.TypesInfo.Defs[declaration.Name].(*types.Func)
+				if !isFunction || declaration.Body == nil {
+					continue
+				}
+				from := functionID(function)
+				if from == "" {
+					continue
+				}
+				b.addEdge(from, initID(pkg.PkgPath))
+				b.walk(pkg, from, declaration.Body)
+			case *ast.GenDecl:
+				b.walk(pkg, initID(pkg.PkgPath), declaration)
+			}
+		}
+	}
+}
+
+// walk adds an edge from the function from to every function referenced in
+// root, and marks the handlers root registers.
+func (b *callGraphBuilder) walk(pkg *packages.Package, from string, root ast.Node) {
+	ast.Inspect(root, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.Ident:
+			function, isFunction := pkg.TypesInfo.Uses[node].(*types.Func)
+			if !isFunction {
+				return true
+			}
+			if receiver := function.Type().(*types.Signature).Recv(); receiver != nil && types.IsInterface(receiver.Type()) {
+				b.addDispatchEdges(from, receiver.Type().Underlying().(*types.Interface), function.Name())
+				return true
+			}
+			if to := functionID(function); to != "" {
+				b.addEdge(from, to)
+			}
+		case *ast.CallExpr:
+			if isHTTPRegistration(pkg, node) {
+				for _, argument := range node.Args {
+					b.markHandler(pkg, argument)
+				}
+			}
+		}
+		return true
+	})
+}
+
+// addDispatchEdges links from to the method name of every loaded concrete
+// type implementing iface.
+func (b *callGraphBuilder) addDispatchEdges(from string, iface *types.Interface, name string) {
+	implementations, found := b.implementations[iface]
+	if !found {
+		for _, named := range b.concreteTypes {
+			if implementsBySignature(types.NewMethodSet(types.NewPointer(named)), named.Obj().Pkg(), iface) {
+				implementations = append(implementations, named)
+			}
+		}
+		b.implementations[iface] = implementations
+	}
+	for _, named := range implementations {
+		selection := types.NewMethodSet(types.NewPointer(named)).Lookup(named.Obj().Pkg(), name)
+		if selection == nil {
+			continue
+		}
+		if method, isMethod := selection.Obj().(*types.Func); isMethod {
+			if to := functionID(method); to != "" {
+				b.addEdge(from, to)
+			}
+		}
+	}
+}
+
+// isHTTPRegistration reports whether call registers handlers: a call of
+// net/http's Handle or HandleFunc, or of a method named like a router's
+// registration method. Arguments are still checked against the handler
+// signature, so unrelated methods named Get register nothing.
+func isHTTPRegistration(pkg *packages.Package, call *ast.CallExpr) bool {
+	var name *ast.Ident
+	switch fun := call.Fun.(type) {
+	case *ast.SelectorExpr:
+		name = fun.Sel
+	case *ast.Ident:
+		name = fun
+	default:
+		return false
+	}
+	function, isFunction := pkg.TypesInfo.Uses[name].(*types.Func)
+	if !isFunction || function.Pkg() == nil {
+		return false
+	}
+	if function.Type().(*types.Signature).Recv() == nil {
+		return function.Pkg().Path() == "net/http" && (function.Name() == "Handle" || function.Name() == "HandleFunc")
+	}
+	return slices.Contains(httpRegistrationMethods, function.Name())
+}
+
+// markHandler marks the function an argument refers to when it has the
+// handler signature, or the ServeHTTP method of an http.Handler value.
+// Conversions such as http.HandlerFunc(serve) are looked through.
+func (b *callGraphBuilder) markHandler(pkg *packages.Package, argument ast.Expr) {
+	argument = ast.Unparen(argument)
+	if conversion, isCall := argument.(*ast.CallExpr); isCall && len(conversion.Args) == 1 {
+		if typeAndValue, found := pkg.TypesInfo.Types[conversion.Fun]; found && typeAndValue.IsType() {
+			b.markHandler(pkg, conversion.Args[0])
+			return
+		}
+	}
+
+	var name *ast.Ident
+	switch expression := argument.(type) {
+	case *ast.Ident:
+		name = expression
+	case *ast.SelectorExpr:
+		name = expression.Sel
+	}
+	if name != nil {
+		if function, isFunction := pkg.TypesInfo.Uses[name].(*types.Func); isFunction {
+			if signatureString(function.Type()) == httpHandlerSignature {
+				b.markEntrypoint(functionID(function), EntrypointHTTPHandler)
+			}
+			return
+		}
+	}
+
+	argumentType := pkg.TypesInfo.TypeOf(argument)
+	if argumentType == nil {
+		return
+	}
+	if pointer, isPointer := argumentType.(*types.Pointer); isPointer {
+		argumentType = pointer.Elem()
+	}
+	named, isNamed := argumentType.(*types.Named)
+	if !isNamed || types.IsInterface(named) {
+		return
+	}
+	selection := types.NewMethodSet(types.NewPointer(named)).Lookup(named.Obj().Pkg(), "ServeHTTP")
+	if selection == nil || signatureString(selection.Obj().Type()) != httpHandlerSignature {
+		return
+	}
+	if method, isMethod := selection.Obj().(*types.Func); isMethod {
+		b.markEntrypoint(functionID(method), EntrypointHTTPHandler)
+	}
+}
+
+// declaredConcreteTypes returns the package-level named non-interface types
+// of the loaded packages.
+func declaredConcreteTypes(pkgs []*packages.Package) []*types.Named {
+	var concrete []*types.Named
+	for _, pkg := range pkgs {
+		if pkg.Types == nil {
+			continue
+		}
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			typeName, isTypeName := scope.Lookup(name).(*types.TypeName)
+			if !isTypeName || typeName.IsAlias() {
+				continue
+			}
+			if named, isNamed := typeName.Type().(*types.Named); isNamed && !types.IsInterface(named) {
+				concrete = append(concrete, named)
+			}
+		}
+	}
+	return concrete
+}
+
+// sortedImports returns the sorted import paths of pkg's resolved imports.
+func sortedImports(pkg *packages.Package) []string {
+	importPaths := make([]string, 0, len(pkg.Imports))
+	for importPath := range pkg.Imports {
+		importPaths = append(importPaths, importPath)
+	}
+	sort.Strings(importPaths)
+	return importPaths
+}
+
+// Reachability splits a call graph's functions and packages into those
+// reachable from the selected entry points and the rest. Functions are named
+// "pkgpath.Func" or "pkgpath.Type.Method"; init nodes are left out of the
+// function lists, since whether a package's initialization runs is what the
+// package lists say.
+type Reachability struct {
+	EntryPoints          []EntryPoint `json:"entry_points"`
+	ReachableFunctions   []string     `json:"reachable_functions"`
+	UnreachableFunctions []string     `json:"unreachable_functions"`
+	// ReachablePackages are the packages whose initialization is reachable,
+	// i.e. that are linked into some entry point's program.
+	ReachablePackages   []string `json:"reachable_packages"`
+	UnreachablePackages []string `json:"unreachable_packages"`
+}
+
+// EntryPoint is a root of the reachability search.
+type EntryPoint struct {
+	Function string `json:"function"`
+	Kind     string `json:"kind"`
+}
+
+// FindReachable walks callGraph, built by BuildCallGraph, from the entry
+// points whose kind is in kinds, or from all of them when kinds is empty.
+// All lists are sorted.
+func FindReachable(callGraph *graph.Graph, kinds []string) Reachability {
+	var result Reachability
+	var roots []string
+	for _, node := range callGraph.Nodes() {
+		kind := node.Attributes[AttributeEntrypoint]
+		if kind == "" || (len(kinds) > 0 && !slices.Contains(kinds, kind)) {
+			continue
+		}
+		roots = append(roots, node.ID)
+		result.EntryPoints = append(result.EntryPoints, EntryPoint{Function: functionName(node.ID), Kind: kind})
+	}
+	sort.Slice(result.EntryPoints, func(i, j int) bool { return result.EntryPoints[i].Function < result.EntryPoints[j].Function })
+
+	reachable := make(map[string]bool)
+	for _, id := range callGraph.Reachable(roots, graph.Outgoing, -1) {
+		reachable[id] = true
+	}
+	for _, node := range callGraph.Nodes() {
+		_, parts, err := graph.ParseID(node.ID)
+		if err != nil {
+			continue
+		}
+		if parts[1] == "" && parts[2] == "init" {
+			if reachable[node.ID] {
+				result.ReachablePackages = append(result.ReachablePackages, parts[0])
+			} else {
+				result.UnreachablePackages = append(result.UnreachablePackages, parts[0])
+			}
+			continue
+		}
+		if reachable[node.ID] {
+			result.ReachableFunctions = append(result.ReachableFunctions, functionName(node.ID))
+		} else {
+			result.UnreachableFunctions = append(result.UnreachableFunctions, functionName(node.ID))
+		}
+	}
+	for _, list := range [][]string{result.ReachableFunctions, result.UnreachableFunctions, result.ReachablePackages, result.UnreachablePackages} {
+		sort.Strings(list)
+	}
+	return result
+}
+
+// functionName renders a function node ID as "pkgpath.Func" or
+// "pkgpath.Type.Method".
+func functionName(id string) string {
+	_, parts, err := graph.ParseID(id)
+	if err != nil || len(parts) != 3 {
+		return id
+	}
+	if parts[1] == "" {
+		return parts[0] + "." + parts[2]
+	}
+	return parts[0] + "." + parts[1] + "." + parts[2]
+}
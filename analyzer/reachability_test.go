@@ -0,0 +1,83 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindReachable(t *testing.T) {
+	files := map[string]string{
+		"cmd/app/main.go": "package main\n\nimport \"deadmod/api\"\n\nfunc main() { api.Serve() }\n",
+		"api/api.go": `package api
+
+import (
+	"net/http"
+
+	"deadmod/store"
+)
+
+func Serve() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", list)
+	http.Handle("/health", &Health{})
+	var s store.Store = store.New()
+	s.Get()
+}
+
+func list(w http.ResponseWriter, r *http.Request) { helper() }
+
+func helper() {}
+
+type Health struct{}
+
+func (h *Health) ServeHTTP(w http.ResponseWriter, r *http.Request) {}
+
+func Unused() {}
+`,
+		"store/store.go": `package store
+
+type Store interface{ Get() string }
+
+type mem struct{}
+
+func (mem) Get() string { return "" }
+
+type disk struct{}
+
+func (*disk) Get() string { return "" }
+
+func New() Store { return mem{} }
+`,
+		"orphan/orphan.go": "package orphan\n\nfunc init() { setup() }\n\nfunc setup() {}\n\nfunc Lonely() {}\n",
+	}
+	callGraph := BuildCallGraph(loadDeadCodeModule(t, files, false))
+
+	fromMain := FindReachable(callGraph, []string{EntrypointMain})
+	want := Reachability{
+		EntryPoints: []EntryPoint{{Function: "deadmod/cmd/app.main", Kind: EntrypointMain}},
+		// Both Store implementations are reachable through the interface call.
+		ReachableFunctions: []string{"deadmod/api.Serve", "deadmod/api.helper", "deadmod/api.list", "deadmod/cmd/app.main",
+			"deadmod/store.New", "deadmod/store.disk.Get", "deadmod/store.mem.Get"},
+		UnreachableFunctions: []string{"deadmod/api.Health.ServeHTTP", "deadmod/api.Unused", "deadmod/orphan.Lonely", "deadmod/orphan.setup"},
+		ReachablePackages:    []string{"deadmod/api", "deadmod/cmd/app", "deadmod/store"},
+		UnreachablePackages:  []string{"deadmod/orphan"},
+	}
+	if !reflect.DeepEqual(fromMain, want) {
+		t.Errorf("FindReachable(main) =\n%+v\nwant\n%+v", fromMain, want)
+	}
+
+	fromAll := FindReachable(callGraph, nil)
+	wantEntryPoints := []EntryPoint{
+		{Function: "deadmod/api.Health.ServeHTTP", Kind: EntrypointHTTPHandler},
+		{Function: "deadmod/api.list", Kind: EntrypointHTTPHandler},
+		{Function: "deadmod/cmd/app.main", Kind: EntrypointMain},
+		{Function: "deadmod/orphan.init", Kind: EntrypointInit},
+	}
+	if !reflect.DeepEqual(fromAll.EntryPoints, wantEntryPoints) {
+		t.Errorf("EntryPoints = %+v, want %+v", fromAll.EntryPoints, wantEntryPoints)
+	}
+	wantUnreachable := []string{"deadmod/api.Unused", "deadmod/orphan.Lonely"}
+	if !reflect.DeepEqual(fromAll.UnreachableFunctions, wantUnreachable) || fromAll.UnreachablePackages != nil {
+		t.Errorf("unreachable = %v, packages %v, want %v and none", fromAll.UnreachableFunctions, fromAll.UnreachablePackages, wantUnreachable)
+	}
+}
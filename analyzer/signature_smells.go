@@ -0,0 +1,90 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/Desgue/codegraph/extract"
+	"github.com/Desgue/codegraph/graph"
+)
+
+// SignatureThresholds are the limits above which an exported function's
+// signature is a smell. A zero limit disables that check.
+type SignatureThresholds struct {
+	Params     int
+	Results    int
+	BoolParams int
+}
+
+// DefaultSignatureThresholds flags more than 5 parameters, more than 3
+// results and 2 or more bool parameters.
+func DefaultSignatureThresholds() SignatureThresholds {
+	return SignatureThresholds{Params: 5, Results: 3, BoolParams: 1}
+}
+
+// FunctionSignature is the signature counts of a function node.
+type FunctionSignature struct {
+	// Function is "pkgpath.Func" or "pkgpath.Type.Method".
+	Function   string
+	Params     int
+	Results    int
+	BoolParams int
+	// Exceeded describes each limit that tripped, e.g. "params 7 > 5".
+	Exceeded []string
+}
+
+// FunctionSignatures returns the signature counts extract.SymbolExtractor
+// recorded on the function nodes of g, ranked by parameters, then results,
+// then bool parameters, most first, and then by name. The graph must have been
+// extracted with that extractor.
+func FunctionSignatures(g *graph.Graph) []FunctionSignature {
+	var signatures []FunctionSignature
+	for _, node := range g.Nodes() {
+		if node.Kind != graph.KindFunction {
+			continue
+		}
+		params, err := strconv.Atoi(node.Attributes[extract.AttributeParamCount])
+		if err != nil {
+			continue
+		}
+		results, _ := strconv.Atoi(node.Attributes[extract.AttributeResultCount])
+		boolParams, _ := strconv.Atoi(node.Attributes[extract.AttributeBoolParamCount])
+		signatures = append(signatures, FunctionSignature{Function: functionName(node.ID), Params: params, Results: results, BoolParams: boolParams})
+	}
+	sort.Slice(signatures, func(i, j int) bool {
+		a, b := signatures[i], signatures[j]
+		if a.Params != b.Params {
+			return a.Params > b.Params
+		}
+		if a.Results != b.Results {
+			return a.Results > b.Results
+		}
+		if a.BoolParams != b.BoolParams {
+			return a.BoolParams > b.BoolParams
+		}
+		return a.Function < b.Function
+	})
+	return signatures
+}
+
+// FindSignatureSmells returns the FunctionSignatures exceeding any of
+// thresholds, sorted by name, with the limits they exceed.
+func FindSignatureSmells(g *graph.Graph, thresholds SignatureThresholds) []FunctionSignature {
+	var smells []FunctionSignature
+	for _, signature := range FunctionSignatures(g) {
+		exceed := func(metric string, value, limit int) {
+			if limit > 0 && value > limit {
+				signature.Exceeded = append(signature.Exceeded, fmt.Sprintf("%s %d > %d", metric, value, limit))
+			}
+		}
+		exceed("params", signature.Params, thresholds.Params)
+		exceed("results", signature.Results, thresholds.Results)
+		exceed("bool params", signature.BoolParams, thresholds.BoolParams)
+		if len(signature.Exceeded) > 0 {
+			smells = append(smells, signature)
+		}
+	}
+	sort.Slice(smells, func(i, j int) bool { return smells[i].Function < smells[j].Function })
+	return smells
+}
@@ -0,0 +1,74 @@
+package analyzer
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/Desgue/codegraph/extract"
+	"github.com/Desgue/codegraph/graph"
+)
+
+func newSignatureTestGraph(t *testing.T) *graph.Graph {
+	t.Helper()
+	g := graph.New()
+	nodes := []*graph.Node{
+		{ID: "example.com/mod/api", Kind: graph.KindPackage},
+		newSignatureFunction("Serve", 2, 1, 0),
+		newSignatureFunction("Open", 7, 1, 0),
+		newSignatureFunction("Split", 1, 4, 0),
+		newSignatureFunction("Copy", 3, 1, 2),
+		newSignatureFunction("Move", 3, 1, 2),
+		// Nodes without signature counts, e.g. from the call graph, are skipped.
+		{ID: graph.FuncID("example.com/mod/api", "", "helper"), Kind: graph.KindFunction},
+	}
+	for _, node := range nodes {
+		if err := g.AddNode(node); err != nil {
+			t.Fatalf("AddNode() error = %v", err)
+		}
+	}
+	return g
+}
+
+func newSignatureFunction(name string, params, results, boolParams int) *graph.Node {
+	node := &graph.Node{ID: graph.FuncID("example.com/mod/api", "", name), Kind: graph.KindFunction, Name: name}
+	node.SetAttribute(extract.AttributeParamCount, strconv.Itoa(params))
+	node.SetAttribute(extract.AttributeResultCount, strconv.Itoa(results))
+	node.SetAttribute(extract.AttributeBoolParamCount, strconv.Itoa(boolParams))
+	return node
+}
+
+func TestFunctionSignatures(t *testing.T) {
+	var ranked []string
+	for _, signature := range FunctionSignatures(newSignatureTestGraph(t)) {
+		ranked = append(ranked, signature.Function)
+	}
+	want := []string{
+		"example.com/mod/api.Open",
+		"example.com/mod/api.Copy",
+		"example.com/mod/api.Move",
+		"example.com/mod/api.Serve",
+		"example.com/mod/api.Split",
+	}
+	if !reflect.DeepEqual(ranked, want) {
+		t.Errorf("FunctionSignatures() order = %v, want %v", ranked, want)
+	}
+}
+
+func TestFindSignatureSmells(t *testing.T) {
+	got := FindSignatureSmells(newSignatureTestGraph(t), DefaultSignatureThresholds())
+
+	want := []FunctionSignature{
+		{Function: "example.com/mod/api.Copy", Params: 3, Results: 1, BoolParams: 2, Exceeded: []string{"bool params 2 > 1"}},
+		{Function: "example.com/mod/api.Move", Params: 3, Results: 1, BoolParams: 2, Exceeded: []string{"bool params 2 > 1"}},
+		{Function: "example.com/mod/api.Open", Params: 7, Results: 1, Exceeded: []string{"params 7 > 5"}},
+		{Function: "example.com/mod/api.Split", Params: 1, Results: 4, Exceeded: []string{"results 4 > 3"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindSignatureSmells() = %+v, want %+v", got, want)
+	}
+
+	if smells := FindSignatureSmells(newSignatureTestGraph(t), SignatureThresholds{}); len(smells) != 0 {
+		t.Errorf("zero thresholds disable every check, got %+v", smells)
+	}
+}
@@ -0,0 +1,119 @@
+package analyzer
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Desgue/codegraph/graph"
+	"golang.org/x/tools/go/packages"
+)
+
+// TestCycleEdge is an import that only test files declare and that closes a
+// TestCycle.
+type TestCycleEdge struct {
+	// From is the package under test; imports of its external test package
+	// are attributed to it.
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Files are the test files declaring the import.
+	Files []string `json:"files"`
+	// External is true when only the external test package (package x_test)
+	// declares the import. Otherwise the package's own test files do, and go
+	// test rejects the cycle.
+	External bool `json:"external"`
+}
+
+// TestCycle is a group of packages that import each other in a cycle only
+// once test files are counted.
+type TestCycle struct {
+	Packages  []string        `json:"packages"`
+	BackEdges []TestCycleEdge `json:"back_edges"`
+}
+
+// FindTestOnlyCycles returns the import cycles between the loaded packages
+// that exist only through imports declared in test files, such as a importing
+// b while b's tests import a, with the test-only imports within each cycle.
+// An external test package counts as part of the package it tests. Strongly
+// connected components that are already import cycles without test files are
+// production cycles and are left out. Cycles are ordered by their first
+// package, back edges by source then target. Requires NeedName, NeedSyntax
+// and NeedImports; pkgs must be loaded with tests to see test files.
+func FindTestOnlyCycles(pkgs []*packages.Package) []TestCycle {
+	g := graph.New()
+	for _, pkg := range pkgs {
+		if _, found := g.Node(testedPackagePath(pkg)); !found {
+			g.AddNode(&graph.Node{ID: testedPackagePath(pkg), Kind: graph.KindPackage})
+		}
+	}
+
+	production := make(map[[2]string]bool)
+	testFiles := make(map[[2]string][]string)
+	internal := make(map[[2]string]bool)
+	for _, pkg := range pkgs {
+		from := testedPackagePath(pkg)
+		for _, file := range pkg.Syntax {
+			filename := pkg.Fset.Position(file.Pos()).Filename
+			isTest := strings.HasSuffix(filename, "_test.go")
+			for _, importSpec := range file.Imports {
+				to, err := strconv.Unquote(importSpec.Path.Value)
+				if _, loaded := g.Node(to); err != nil || !loaded || to == from {
+					continue
+				}
+				key := [2]string{from, to}
+				switch {
+				case !isTest:
+					production[key] = true
+				case !slices.Contains(testFiles[key], filename):
+					testFiles[key] = append(testFiles[key], filename)
+					if pkg.PkgPath == from {
+						internal[key] = true
+					}
+				}
+			}
+		}
+	}
+	for key := range production {
+		g.AddEdge(&graph.Edge{From: key[0], To: key[1], Kind: graph.EdgeImport})
+	}
+	for key := range testFiles {
+		if !production[key] {
+			g.AddEdge(&graph.Edge{From: key[0], To: key[1], Kind: graph.EdgeTestImport, IsTestOnly: true})
+		}
+	}
+
+	productionCycles := make(map[string]bool)
+	for _, cycle := range g.FindCycles([]graph.EdgeKind{graph.EdgeImport}) {
+		productionCycles[strings.Join(cycle, "\x00")] = true
+	}
+	var cycles []TestCycle
+	for _, component := range g.FindCycles(nil) {
+		if productionCycles[strings.Join(component, "\x00")] {
+			continue
+		}
+		cycle := TestCycle{Packages: component}
+		for _, from := range component {
+			for _, to := range g.Neighbors(from, graph.Outgoing, []graph.EdgeKind{graph.EdgeTestImport}) {
+				if !slices.Contains(component, to) {
+					continue
+				}
+				key := [2]string{from, to}
+				files := testFiles[key]
+				sort.Strings(files)
+				cycle.BackEdges = append(cycle.BackEdges, TestCycleEdge{From: from, To: to, Files: files, External: !internal[key]})
+			}
+		}
+		cycles = append(cycles, cycle)
+	}
+	return cycles
+}
+
+// testedPackagePath returns the import path of the package pkg tests, which
+// is its own path unless pkg is an external test package.
+func testedPackagePath(pkg *packages.Package) string {
+	if strings.HasSuffix(pkg.Name, "_test") {
+		return strings.TrimSuffix(pkg.PkgPath, "_test")
+	}
+	return pkg.PkgPath
+}
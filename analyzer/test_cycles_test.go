@@ -0,0 +1,45 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestFindTestOnlyCycles(t *testing.T) {
+	pkgs := loadDeadCodeModule(t, map[string]string{
+		"a/a.go": "package a\n\nimport \"deadmod/b\"\n\nvar Value = b.Value\n",
+		"b/b.go": "package b\n\nconst Value = 1\n",
+		"b/b_test.go": "package b_test\n\nimport (\n\t\"testing\"\n\n\t\"deadmod/a\"\n\t\"deadmod/b\"\n)\n\n" +
+			"func TestValue(t *testing.T) {\n\tif a.Value != b.Value {\n\t\tt.Fail()\n\t}\n}\n",
+		"c/c.go":      "package c\n",
+		"c/c_test.go": "package c\n\nimport _ \"deadmod/a\"\n",
+	}, true)
+
+	cycles := FindTestOnlyCycles(pkgs)
+	if len(cycles) != 1 {
+		t.Fatalf("expected 1 test-only cycle, got %+v", cycles)
+	}
+	cycle := cycles[0]
+	if len(cycle.Packages) != 2 || cycle.Packages[0] != "deadmod/a" || cycle.Packages[1] != "deadmod/b" {
+		t.Errorf("cycle packages = %v, want [deadmod/a deadmod/b]", cycle.Packages)
+	}
+	if len(cycle.BackEdges) != 1 {
+		t.Fatalf("expected 1 back edge, got %+v", cycle.BackEdges)
+	}
+	backEdge := cycle.BackEdges[0]
+	if backEdge.From != "deadmod/b" || backEdge.To != "deadmod/a" || !backEdge.External {
+		t.Errorf("back edge = %+v, want an external deadmod/b -> deadmod/a", backEdge)
+	}
+	if len(backEdge.Files) != 1 || filepath.Base(backEdge.Files[0]) != "b_test.go" {
+		t.Errorf("back edge files = %v, want b/b_test.go", backEdge.Files)
+	}
+
+	production := loadDeadCodeModule(t, map[string]string{
+		"a/a.go":      "package a\n\nimport _ \"deadmod/b\"\n",
+		"b/b.go":      "package b\n",
+		"b/b_test.go": "package b_test\n\nimport _ \"deadmod/b\"\n",
+	}, true)
+	if cycles := FindTestOnlyCycles(production); len(cycles) != 0 {
+		t.Errorf("expected no test-only cycles, got %+v", cycles)
+	}
+}
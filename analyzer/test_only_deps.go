@@ -0,0 +1,63 @@
+package analyzer
+
+import (
+	"sort"
+
+	"github.com/Desgue/codegraph/extract"
+	"github.com/Desgue/codegraph/graph"
+	"github.com/Desgue/codegraph/parser"
+)
+
+// ImportUsage returns the extract.AttributeImportUsage classification of an
+// import edge. Edges decoded from files written before the attribute existed
+// fall back to their kind, so they read as test-only or production, never
+// mixed.
+func ImportUsage(edge *graph.Edge) string {
+	if usage := edge.Attributes[extract.AttributeImportUsage]; usage != "" {
+		return usage
+	}
+	if edge.Kind == graph.EdgeTestImport {
+		return parser.ImportTestOnly
+	}
+	return parser.ImportProduction
+}
+
+// ImportUsageCounts counts the graph's import edges per ImportUsage.
+func ImportUsageCounts(g *graph.Graph) map[string]int {
+	counts := make(map[string]int)
+	for _, edge := range g.Edges() {
+		if isImportEdge(edge) {
+			counts[ImportUsage(edge)]++
+		}
+	}
+	return counts
+}
+
+// TestOnlyPackages returns the sorted IDs of the packages imported within the
+// graph only by test files: test infrastructure that could move under an
+// internal/testutil tree. Packages nothing imports are not included.
+func TestOnlyPackages(g *graph.Graph) []string {
+	var ids []string
+	for _, node := range g.Nodes() {
+		imported, testOnly := false, true
+		for _, edge := range g.InEdges(node.ID) {
+			if !isImportEdge(edge) {
+				continue
+			}
+			imported = true
+			if ImportUsage(edge) != parser.ImportTestOnly {
+				testOnly = false
+				break
+			}
+		}
+		if imported && testOnly {
+			ids = append(ids, node.ID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func isImportEdge(edge *graph.Edge) bool {
+	return edge.Kind == graph.EdgeImport || edge.Kind == graph.EdgeTestImport
+}
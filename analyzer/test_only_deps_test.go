@@ -0,0 +1,49 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Desgue/codegraph/extract"
+	"github.com/Desgue/codegraph/graph"
+)
+
+func TestTestOnlyPackages(t *testing.T) {
+	g := graph.New()
+	for _, id := range []string{"example.com/mod/api", "example.com/mod/store", "example.com/mod/mocks", "example.com/mod/fixtures", "example.com/mod/cmd"} {
+		if err := g.AddNode(&graph.Node{ID: id, Kind: graph.KindPackage}); err != nil {
+			t.Fatalf("AddNode() error = %v", err)
+		}
+	}
+	edges := []struct {
+		from, to string
+		kind     graph.EdgeKind
+		usage    string
+	}{
+		{"example.com/mod/cmd", "example.com/mod/api", graph.EdgeImport, "production"},
+		{"example.com/mod/api", "example.com/mod/store", graph.EdgeImport, "mixed"},
+		{"example.com/mod/api", "example.com/mod/mocks", graph.EdgeTestImport, "test_only"},
+		{"example.com/mod/store", "example.com/mod/mocks", graph.EdgeTestImport, "test_only"},
+		// Decoded from an older file: no usage attribute, the kind decides.
+		{"example.com/mod/store", "example.com/mod/fixtures", graph.EdgeTestImport, ""},
+	}
+	for _, e := range edges {
+		edge := &graph.Edge{From: e.from, To: e.to, Kind: e.kind, IsTestOnly: e.kind == graph.EdgeTestImport}
+		if e.usage != "" {
+			edge.SetAttribute(extract.AttributeImportUsage, e.usage)
+		}
+		if err := g.AddEdge(edge); err != nil {
+			t.Fatalf("AddEdge() error = %v", err)
+		}
+	}
+
+	want := []string{"example.com/mod/fixtures", "example.com/mod/mocks"}
+	if got := TestOnlyPackages(g); !reflect.DeepEqual(got, want) {
+		t.Errorf("TestOnlyPackages() = %v, want %v", got, want)
+	}
+
+	wantCounts := map[string]int{"production": 1, "mixed": 1, "test_only": 3}
+	if got := ImportUsageCounts(g); !reflect.DeepEqual(got, wantCounts) {
+		t.Errorf("ImportUsageCounts() = %v, want %v", got, wantCounts)
+	}
+}
@@ -0,0 +1,267 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"github.com/Desgue/codegraph/graph"
+	"golang.org/x/tools/go/packages"
+)
+
+// Edge kinds of the type reference graph built by FindTypeCycles.
+const (
+	typeValueReference    graph.EdgeKind = "type_value"
+	typeIndirectReference graph.EdgeKind = "type_indirect"
+)
+
+// typeReferenceField is the edge attribute holding TypeReference.Field.
+const typeReferenceField = "field"
+
+// TypeReference is a field of one named type whose type mentions another.
+type TypeReference struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Field is the path of field names from From to the reference, e.g.
+	// "Config.Parent" for a field of an anonymous struct field.
+	Field string `json:"field"`
+	// Indirect is true when the reference passes through a pointer, slice,
+	// map or channel, which breaks the size recursion.
+	Indirect bool `json:"indirect"`
+}
+
+// TypeCycle is a group of mutually recursive named types.
+type TypeCycle struct {
+	Types []string `json:"types"`
+	// References lists the fields between members of the cycle, sorted by
+	// type and field.
+	References []TypeReference `json:"references"`
+	// PointerBroken is true when every loop passes through an indirection, the
+	// legal recursion of linked lists and trees. Otherwise some loop holds
+	// the types by value, an "invalid recursive type" compile error.
+	PointerBroken bool `json:"pointer_broken"`
+}
+
+// FindTypeCycles returns the cycles among package-level named types of the
+// loaded packages, following struct fields, array elements, pointers, slices,
+// maps and channels. Function and interface types are not followed, and type
+// arguments count as indirect references since the generic type decides how
+// it stores them. Type names are qualified by package path and cycles are
+// sorted by their first type. Requires NeedTypes.
+func FindTypeCycles(pkgs []*packages.Package) []TypeCycle {
+	references := buildTypeReferenceGraph(pkgs)
+
+	inValueCycle := make(map[string]bool)
+	for _, component := range references.FindCycles([]graph.EdgeKind{typeValueReference}) {
+		for _, id := range component {
+			inValueCycle[id] = true
+		}
+	}
+
+	var cycles []TypeCycle
+	for _, component := range references.FindCycles(nil) {
+		cycle := TypeCycle{Types: component, PointerBroken: true}
+		members := make(map[string]bool, len(component))
+		for _, id := range component {
+			members[id] = true
+			if inValueCycle[id] {
+				cycle.PointerBroken = false
+			}
+		}
+		for _, id := range component {
+			for _, edge := range references.OutEdges(id) {
+				if members[edge.To] {
+					cycle.References = append(cycle.References, TypeReference{From: edge.From, To: edge.To,
+						Field: edge.Attributes[typeReferenceField], Indirect: edge.Kind == typeIndirectReference})
+				}
+			}
+		}
+		sort.Slice(cycle.References, func(i, j int) bool {
+			if cycle.References[i].From != cycle.References[j].From {
+				return cycle.References[i].From < cycle.References[j].From
+			}
+			return cycle.References[i].Field < cycle.References[j].Field
+		})
+		cycles = append(cycles, cycle)
+	}
+	return cycles
+}
+
+// buildTypeReferenceGraph has a node per package-level named type and an edge
+// per field reference between them. Declarations are walked in syntax, with
+// identifiers resolved by the type checker: go/types gives invalid recursive
+// types an invalid underlying type, hiding the very cycles worth reporting.
+func buildTypeReferenceGraph(pkgs []*packages.Package) *graph.Graph {
+	references := graph.New()
+	named := make(map[*types.TypeName]string)
+	for _, pkg := range pkgs {
+		if pkg.Types == nil {
+			continue
+		}
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			if typeName, isTypeName := scope.Lookup(name).(*types.TypeName); isTypeName && !typeName.IsAlias() {
+				id := pkg.PkgPath + "." + name
+				named[typeName] = id
+				// Scope names are unique and loaded packages are deduplicated.
+				_ = references.AddNode(&graph.Node{ID: id, Kind: "type", Name: name})
+			}
+		}
+	}
+
+	for _, pkg := range pkgs {
+		if pkg.TypesInfo == nil {
+			continue
+		}
+		for _, file := range pkg.Syntax {
+			for _, decl := range file.Decls {
+				genDecl, isGenDecl := decl.(*ast.GenDecl)
+				if !isGenDecl || genDecl.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range genDecl.Specs {
+					typeSpec := spec.(*ast.TypeSpec)
+					typeName, isTypeName := pkg.TypesInfo.Defs[typeSpec.Name].(*types.TypeName)
+					if !isTypeName || typeSpec.Assign.IsValid() || named[typeName] == "" {
+						continue
+					}
+					walker := &typeReferenceWalker{from: named[typeName], info: pkg.TypesInfo, named: named,
+						graph: references, seen: make(map[string]bool)}
+					walker.walkExpr(typeSpec.Type, nil, false)
+				}
+			}
+		}
+	}
+	return references
+}
+
+type typeReferenceWalker struct {
+	from  string
+	info  *types.Info
+	named map[*types.TypeName]string
+	graph *graph.Graph
+	// seen deduplicates references by target, field and indirection.
+	seen map[string]bool
+}
+
+// walkExpr follows a type expression of the declaration being walked.
+func (w *typeReferenceWalker) walkExpr(expr ast.Expr, fieldPath []string, indirect bool) {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		w.walkObject(w.info.Uses[expr], fieldPath, indirect)
+	case *ast.SelectorExpr:
+		w.walkObject(w.info.Uses[expr.Sel], fieldPath, indirect)
+	case *ast.ParenExpr:
+		w.walkExpr(expr.X, fieldPath, indirect)
+	case *ast.IndexExpr:
+		w.walkExpr(expr.X, fieldPath, indirect)
+		w.walkExpr(expr.Index, fieldPath, true)
+	case *ast.IndexListExpr:
+		w.walkExpr(expr.X, fieldPath, indirect)
+		for _, index := range expr.Indices {
+			w.walkExpr(index, fieldPath, true)
+		}
+	case *ast.StructType:
+		for _, field := range expr.Fields.List {
+			names := make([]string, 0, len(field.Names))
+			for _, name := range field.Names {
+				names = append(names, name.Name)
+			}
+			if len(names) == 0 {
+				names = append(names, embeddedFieldName(field.Type))
+			}
+			for _, name := range names {
+				w.walkExpr(field.Type, append(fieldPath[:len(fieldPath):len(fieldPath)], name), indirect)
+			}
+		}
+	case *ast.StarExpr:
+		w.walkExpr(expr.X, fieldPath, true)
+	case *ast.ArrayType:
+		// Slices have no length; arrays store their elements inline.
+		w.walkExpr(expr.Elt, fieldPath, indirect || expr.Len == nil)
+	case *ast.MapType:
+		w.walkExpr(expr.Key, fieldPath, true)
+		w.walkExpr(expr.Value, fieldPath, true)
+	case *ast.ChanType:
+		w.walkExpr(expr.Value, fieldPath, true)
+	}
+}
+
+// walkObject records a reference to a named type, or follows an alias to the
+// type it stands for.
+func (w *typeReferenceWalker) walkObject(object types.Object, fieldPath []string, indirect bool) {
+	typeName, isTypeName := object.(*types.TypeName)
+	if !isTypeName {
+		return
+	}
+	if typeName.IsAlias() {
+		w.walkType(typeName.Type(), fieldPath, indirect)
+		return
+	}
+	if id, found := w.named[typeName]; found {
+		w.add(id, strings.Join(fieldPath, "."), indirect)
+	}
+}
+
+// walkType is walkExpr for types reached through an alias, which have no
+// syntax in the declaration.
+func (w *typeReferenceWalker) walkType(typ types.Type, fieldPath []string, indirect bool) {
+	switch typ := types.Unalias(typ).(type) {
+	case *types.Named:
+		w.walkObject(typ.Origin().Obj(), fieldPath, indirect)
+		for i := range typ.TypeArgs().Len() {
+			w.walkType(typ.TypeArgs().At(i), fieldPath, true)
+		}
+	case *types.Struct:
+		for i := range typ.NumFields() {
+			field := typ.Field(i)
+			w.walkType(field.Type(), append(fieldPath[:len(fieldPath):len(fieldPath)], field.Name()), indirect)
+		}
+	case *types.Array:
+		w.walkType(typ.Elem(), fieldPath, indirect)
+	case *types.Pointer:
+		w.walkType(typ.Elem(), fieldPath, true)
+	case *types.Slice:
+		w.walkType(typ.Elem(), fieldPath, true)
+	case *types.Map:
+		w.walkType(typ.Key(), fieldPath, true)
+		w.walkType(typ.Elem(), fieldPath, true)
+	case *types.Chan:
+		w.walkType(typ.Elem(), fieldPath, true)
+	}
+}
+
+func (w *typeReferenceWalker) add(to, field string, indirect bool) {
+	kind := typeValueReference
+	if indirect {
+		kind = typeIndirectReference
+	}
+	key := to + "|" + field + "|" + string(kind)
+	if w.seen[key] {
+		return
+	}
+	w.seen[key] = true
+	edge := &graph.Edge{From: w.from, To: to, Kind: kind}
+	edge.SetAttribute(typeReferenceField, field)
+	_ = w.graph.AddEdge(edge)
+}
+
+// embeddedFieldName returns the implicit name of an embedded field: its type
+// name without pointer, package qualifier or type arguments.
+func embeddedFieldName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return expr.Name
+	case *ast.SelectorExpr:
+		return expr.Sel.Name
+	case *ast.StarExpr:
+		return embeddedFieldName(expr.X)
+	case *ast.IndexExpr:
+		return embeddedFieldName(expr.X)
+	case *ast.IndexListExpr:
+		return embeddedFieldName(expr.X)
+	}
+	return ""
+}
@@ -0,0 +1,92 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Desgue/codegraph/parser"
+)
+
+func TestFindTypeCycles(t *testing.T) {
+	pkgs := loadDeadCodeModule(t, map[string]string{
+		"tree/tree.go": `package tree
+
+import "deadmod/store"
+
+type List struct {
+	Value int
+	Next  *List
+}
+
+type Node struct {
+	Children []*Node
+	Meta     struct{ Parent *Tree }
+}
+
+type Tree struct {
+	Root   Node
+	Lookup map[string]store.Entry
+}
+
+type Leaf struct{ Count int }
+
+type Nodes = []Node
+
+type Forest struct{ Trees Nodes }
+`,
+		"store/store.go": `package store
+
+type Entry struct{ Owner *Registry }
+
+type Registry struct{ Entries [4]Entry }
+`,
+	}, false)
+
+	want := []TypeCycle{
+		{Types: []string{"deadmod/store.Entry", "deadmod/store.Registry"}, PointerBroken: true,
+			References: []TypeReference{
+				{From: "deadmod/store.Entry", To: "deadmod/store.Registry", Field: "Owner", Indirect: true},
+				{From: "deadmod/store.Registry", To: "deadmod/store.Entry", Field: "Entries"},
+			}},
+		{Types: []string{"deadmod/tree.List"}, PointerBroken: true,
+			References: []TypeReference{{From: "deadmod/tree.List", To: "deadmod/tree.List", Field: "Next", Indirect: true}}},
+		{Types: []string{"deadmod/tree.Node", "deadmod/tree.Tree"}, PointerBroken: true,
+			References: []TypeReference{
+				{From: "deadmod/tree.Node", To: "deadmod/tree.Node", Field: "Children", Indirect: true},
+				{From: "deadmod/tree.Node", To: "deadmod/tree.Tree", Field: "Meta.Parent", Indirect: true},
+				{From: "deadmod/tree.Tree", To: "deadmod/tree.Node", Field: "Root"},
+			}},
+	}
+	if got := FindTypeCycles(pkgs); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindTypeCycles() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestFindTypeCycles_ValueCycle(t *testing.T) {
+	testDir := t.TempDir()
+	for name, content := range map[string]string{
+		"go.mod": "module broken\n\ngo 1.24\n",
+		"a.go":   "package broken\n\ntype A struct{ B B }\n\ntype B struct {\n\tAs [2]A\n\tNext *A\n}\n",
+	} {
+		if err := os.WriteFile(filepath.Join(testDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+	// The tree does not compile: that is the point of the check.
+	pkgs, errorCount, err := parser.Load(testDir, false)
+	if err != nil || errorCount == 0 {
+		t.Fatalf("Load() = %d errors, %v, want an invalid recursive type error", errorCount, err)
+	}
+
+	want := []TypeCycle{{Types: []string{"broken.A", "broken.B"},
+		References: []TypeReference{
+			{From: "broken.A", To: "broken.B", Field: "B"},
+			{From: "broken.B", To: "broken.A", Field: "As"},
+			{From: "broken.B", To: "broken.A", Field: "Next", Indirect: true},
+		}}}
+	if got := FindTypeCycles(pkgs); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindTypeCycles() =\n%+v\nwant\n%+v", got, want)
+	}
+}
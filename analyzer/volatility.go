@@ -0,0 +1,41 @@
+package analyzer
+
+import (
+	"sort"
+
+	"github.com/Desgue/codegraph/graph"
+	"github.com/Desgue/codegraph/graph/metrics"
+)
+
+// VolatilePackage is a package that both changes often and is imported by
+// many packages, so its changes ripple the furthest.
+type VolatilePackage struct {
+	Package         string `json:"package"`
+	ChangeFrequency int    `json:"change_frequency"`
+	FanIn           int    `json:"fan_in"`
+	// Score is ChangeFrequency times FanIn.
+	Score int `json:"score"`
+}
+
+// VolatilePackages ranks the package nodes of g with both a
+// Node.ChangeFrequency, see graph.EnrichWithGitFrequency, and importers by
+// their product, highest first, then by ID. Packages that never change or
+// that nothing imports carry no such risk and are left out.
+func VolatilePackages(g *graph.Graph) []VolatilePackage {
+	fanIn := metrics.FanIn(g)
+	var volatile []VolatilePackage
+	for _, node := range g.Nodes() {
+		if node.Kind != graph.KindPackage || node.ChangeFrequency == 0 || fanIn[node.ID] == 0 {
+			continue
+		}
+		volatile = append(volatile, VolatilePackage{Package: node.ID, ChangeFrequency: node.ChangeFrequency,
+			FanIn: fanIn[node.ID], Score: node.ChangeFrequency * fanIn[node.ID]})
+	}
+	sort.Slice(volatile, func(i, j int) bool {
+		if volatile[i].Score != volatile[j].Score {
+			return volatile[i].Score > volatile[j].Score
+		}
+		return volatile[i].Package < volatile[j].Package
+	})
+	return volatile
+}
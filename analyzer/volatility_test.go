@@ -0,0 +1,31 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+func TestVolatilePackages(t *testing.T) {
+	g := graph.New()
+	for _, node := range []*graph.Node{
+		{ID: "app", Kind: graph.KindPackage, ChangeFrequency: 40},
+		{ID: "api", Kind: graph.KindPackage, ChangeFrequency: 6},
+		{ID: "model", Kind: graph.KindPackage, ChangeFrequency: 5},
+		{ID: "log", Kind: graph.KindPackage},
+	} {
+		g.AddNode(node)
+	}
+	for _, edge := range [][2]string{{"app", "api"}, {"app", "model"}, {"api", "model"}, {"app", "log"}, {"model", "log"}} {
+		g.AddEdge(&graph.Edge{From: edge[0], To: edge[1], Kind: graph.EdgeImport})
+	}
+
+	want := []VolatilePackage{
+		{Package: "model", ChangeFrequency: 5, FanIn: 2, Score: 10},
+		{Package: "api", ChangeFrequency: 6, FanIn: 1, Score: 6},
+	}
+	if got := VolatilePackages(g); !reflect.DeepEqual(got, want) {
+		t.Errorf("VolatilePackages() = %+v, want %+v", got, want)
+	}
+}
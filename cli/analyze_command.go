@@ -0,0 +1,720 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"go/version"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Desgue/codegraph/analyzer"
+	"github.com/Desgue/codegraph/extract"
+	"github.com/Desgue/codegraph/graph"
+	"github.com/Desgue/codegraph/graph/metrics"
+	"github.com/Desgue/codegraph/parser"
+	"github.com/Desgue/codegraph/path"
+	"golang.org/x/tools/go/packages"
+)
+
+// nodeMetrics maps --metrics names to metrics that score every node.
+var nodeMetrics = map[string]func(g *graph.Graph) map[string]float64{
+	"closeness":     metrics.ClosenessCentrality,
+	"katz":          func(g *graph.Graph) map[string]float64 { return metrics.KatzCentrality(g, metrics.DefaultKatzAlpha) },
+	"main-sequence": analyzer.DistanceFromMainSequence,
+}
+
+// hitsMetric is the --metrics name printing separate hub and authority
+// rankings from metrics.HITS.
+const hitsMetric = "hits"
+
+// embedMetric is the --metrics name listing packages by Node.EmbedCount.
+const embedMetric = "embed"
+
+// docCoverageMetric is the --metrics name listing packages by their
+// extract.AttributeDocCoverage percentage.
+const docCoverageMetric = "doc-coverage"
+
+// testabilityMetric is the --metrics name listing the packages without test
+// functions (Node.Testable), most imported first.
+const testabilityMetric = "testability"
+
+// bipartiteMetric is the --metrics name splitting packages into two groups
+// with graph.BipartiteCheck.
+const bipartiteMetric = "bipartite"
+
+// embedCountWarning is the embedded file count above which a package is
+// flagged as a likely contributor to binary size.
+const embedCountWarning = 100
+
+type AnalyzeCommand struct {
+	TargetDirectory *path.TargetDirectory
+	IncludeTests    bool
+	Metrics         []string
+	ListSources     bool
+	ListSinks       bool
+	ListCGO         bool
+	ListUnsafe      bool
+	ListConstrained bool
+	ListIsolated    bool
+	LongestChain    bool
+	ListModules     bool
+	ListOldGo       bool
+	TestFrameworks  bool
+	TestOnlyDeps    bool
+	Generics        bool
+	RebuildImpact   bool
+	Signatures      bool
+	// Volatile ranks packages by git change frequency times fan-in, counting
+	// commits after Since when it is set.
+	Volatile bool
+	Since    string
+	// SignatureExclude lists types left out of the --signatures counts.
+	SignatureExclude []string
+	// ListUndocumented holds package patterns whose undocumented exported
+	// functions are listed.
+	ListUndocumented []string
+
+	output io.Writer
+}
+
+func NewAnalyzeCommand(args []string) (*AnalyzeCommand, error) {
+	flagSet := flag.NewFlagSet("analyze", flag.ContinueOnError)
+
+	analyzeCommand := &AnalyzeCommand{output: os.Stdout}
+	metricList := ""
+	undocumentedList := ""
+	signatureExclude := ""
+
+	flagSet.BoolVar(&analyzeCommand.IncludeTests, "include-tests", false, "Include test files in parsing")
+	flagSet.StringVar(&metricList, "metrics", "",
+		fmt.Sprintf("Comma-separated metrics to compute (%s)", strings.Join(nodeMetricNames(), ", ")))
+	flagSet.BoolVar(&analyzeCommand.ListSources, "list-sources", false, "List packages nothing else imports (entry points or dead code)")
+	flagSet.BoolVar(&analyzeCommand.ListSinks, "list-sinks", false, "List packages that import nothing else in the module")
+	flagSet.BoolVar(&analyzeCommand.ListCGO, "list-cgo", false, "List packages that use cgo, a portability risk")
+	flagSet.BoolVar(&analyzeCommand.ListUnsafe, "list-unsafe", false, "List packages that import unsafe, for security review")
+	flagSet.BoolVar(&analyzeCommand.ListIsolated, "list-isolated", false, "List packages that neither import nor are imported by another package")
+	flagSet.BoolVar(&analyzeCommand.LongestChain, "longest-chain", false, "Print the longest chain of imports, with import cycles condensed")
+	flagSet.BoolVar(&analyzeCommand.ListConstrained, "list-constrained", false, "List packages with build-constrained files and their constraints")
+	flagSet.BoolVar(&analyzeCommand.ListOldGo, "list-old-go", false, "List packages whose module requires an older Go version than the main module")
+	flagSet.BoolVar(&analyzeCommand.ListModules, "list-modules", false, "Print a table of the modules providing packages and their versions")
+	flagSet.BoolVar(&analyzeCommand.TestFrameworks, "test-frameworks", false,
+		"Print how many packages use each test framework (needs --include-tests to see test imports)")
+	flagSet.BoolVar(&analyzeCommand.TestOnlyDeps, "test-only-deps", false,
+		"Count import edges as production, test-only or mixed and list packages only tests import (needs --include-tests)")
+	flagSet.BoolVar(&analyzeCommand.Generics, "generics", false,
+		"Print per-package generics counts and the most instantiated generic functions and types")
+	flagSet.BoolVar(&analyzeCommand.RebuildImpact, "rebuild-impact", false,
+		"Rank packages by how many packages, and lines of code, transitively import them and rebuild when they change")
+	flagSet.BoolVar(&analyzeCommand.Signatures, "signatures", false,
+		"Rank exported functions by parameter, result and bool parameter counts")
+	flagSet.BoolVar(&analyzeCommand.Volatile, "volatile", false,
+		"Rank packages by git commits touching them times their importers: frequently changing, widely imported packages")
+	flagSet.StringVar(&analyzeCommand.Since, "since", "", "Count only commits after this git ref for --volatile")
+	flagSet.StringVar(&signatureExclude, "signature-exclude", "",
+		"Comma-separated types left out of --signatures counts, as written in the source, e.g. context.Context,error")
+	flagSet.StringVar(&undocumentedList, "list-undocumented", "",
+		"Comma-separated package patterns (e.g. ./api or example.com/mod/...) whose undocumented exported functions to list")
+
+	if err := flagSet.Parse(args); err != nil {
+		return nil, newUsageError(err)
+	}
+	if metricList != "" {
+		analyzeCommand.Metrics = strings.Split(metricList, ",")
+	}
+	if signatureExclude != "" {
+		analyzeCommand.SignatureExclude = strings.Split(signatureExclude, ",")
+	}
+	if undocumentedList != "" {
+		analyzeCommand.ListUndocumented = strings.Split(undocumentedList, ",")
+	}
+
+	directoryArgument := ""
+	if flagSet.NArg() > 0 {
+		directoryArgument = flagSet.Arg(0)
+	}
+
+	targetDirectory, err := path.NewTargetDirectory(directoryArgument)
+	if err != nil {
+		return nil, err
+	}
+	analyzeCommand.TargetDirectory = targetDirectory
+
+	if err := analyzeCommand.Validate(); err != nil {
+		return nil, err
+	}
+
+	return analyzeCommand, nil
+}
+
+func (ac *AnalyzeCommand) Validate() error {
+	if len(ac.Metrics) == 0 && !ac.ListSources && !ac.ListSinks && !ac.ListCGO && !ac.ListUnsafe && !ac.ListConstrained && !ac.ListIsolated && !ac.LongestChain && !ac.ListModules && !ac.ListOldGo && !ac.TestFrameworks && !ac.TestOnlyDeps && !ac.Generics && !ac.RebuildImpact && !ac.Signatures && !ac.Volatile && len(ac.ListUndocumented) == 0 {
+		return usageErrorf("nothing to analyze (use --metrics %s, --list-sources, --list-sinks, --list-isolated, --list-cgo, --list-unsafe, --list-constrained, --list-modules, --list-old-go, --list-undocumented, --test-frameworks, --test-only-deps, --generics, --rebuild-impact, --signatures, --volatile or --longest-chain)",
+			strings.Join(nodeMetricNames(), ","))
+	}
+	for _, name := range ac.Metrics {
+		if _, found := nodeMetrics[name]; !found && name != embedMetric && name != docCoverageMetric && name != hitsMetric && name != testabilityMetric && name != bipartiteMetric {
+			return usageErrorf("unknown metric '%s' (available: %s)", name, strings.Join(nodeMetricNames(), ", "))
+		}
+		// Without test files every package would be listed.
+		if name == testabilityMetric && !ac.IncludeTests {
+			return usageErrorf("--metrics %s needs --include-tests", testabilityMetric)
+		}
+	}
+	if ac.Volatile {
+		if _, inRepo := ac.TargetDirectory.IsInGitRepo(); !inRepo {
+			return usageErrorf("--volatile counts git commits, but %s is not in a git repository", ac.TargetDirectory.Path)
+		}
+	}
+	return nil
+}
+
+func (ac *AnalyzeCommand) Execute() error {
+	pkgs, _, err := parser.Load(ac.TargetDirectory.Path, ac.IncludeTests)
+	if err != nil {
+		return err
+	}
+	extractors := extract.Extractors()
+	if len(ac.ListUndocumented) > 0 || ac.Signatures {
+		extractors = append(extractors, &extract.SymbolExtractor{SignatureExclude: ac.SignatureExclude})
+	}
+	dependencyGraph, err := extractGraphWith(context.Background(), pkgs, extractors)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range ac.Metrics {
+		switch name {
+		case embedMetric:
+			ac.printEmbedCounts(dependencyGraph)
+			continue
+		case docCoverageMetric:
+			ac.printDocCoverage(dependencyGraph)
+			continue
+		case testabilityMetric:
+			ac.printUntested(dependencyGraph)
+			continue
+		case bipartiteMetric:
+			ac.printBipartite(dependencyGraph)
+			continue
+		case hitsMetric:
+			hub, authority := metrics.HITS(dependencyGraph, metrics.DefaultHITSIterations)
+			ac.printScores(dependencyGraph, "hits hubs", hub)
+			ac.printScores(dependencyGraph, "hits authorities", authority)
+			continue
+		}
+		ac.printScores(dependencyGraph, name, nodeMetrics[name](dependencyGraph))
+	}
+	if ac.ListSources {
+		ac.printNodes(dependencyGraph, "sources", graph.SourceNodes(dependencyGraph))
+	}
+	if ac.ListSinks {
+		ac.printNodes(dependencyGraph, "sinks", graph.SinkNodes(dependencyGraph))
+	}
+	if ac.ListIsolated {
+		ac.printNodes(dependencyGraph, "isolated packages (no edges in either direction)", isolatedNodes(dependencyGraph))
+	}
+	if ac.ListCGO {
+		ac.printNodes(dependencyGraph, "cgo packages (portability risk: need a C toolchain and CGO_ENABLED=1)", cgoNodes(dependencyGraph))
+	}
+	if ac.ListUnsafe {
+		ac.printNodes(dependencyGraph, "unsafe packages (bypass type and memory safety: review in security audits)", unsafeNodes(dependencyGraph))
+	}
+	if ac.ListConstrained {
+		ac.printConstrainedNodes(dependencyGraph)
+	}
+	if ac.LongestChain {
+		ac.printLongestChain(dependencyGraph)
+	}
+	if ac.ListModules {
+		ac.printModuleVersions(dependencyGraph)
+	}
+	if ac.ListOldGo {
+		ac.printOldGoVersions(dependencyGraph, mainGoVersion(pkgs))
+	}
+	if ac.TestFrameworks {
+		ac.printTestFrameworks(dependencyGraph)
+	}
+	if ac.TestOnlyDeps {
+		ac.printTestOnlyDeps(dependencyGraph)
+	}
+	if ac.Generics {
+		ac.printGenerics(dependencyGraph, analyzer.MostInstantiated(pkgs))
+	}
+	if ac.RebuildImpact {
+		ac.printRebuildImpact(dependencyGraph)
+	}
+	if ac.Signatures {
+		ac.printSignatures(dependencyGraph)
+	}
+	if ac.Volatile {
+		repoRoot, _ := ac.TargetDirectory.IsInGitRepo()
+		if err := graph.EnrichWithGitFrequency(dependencyGraph, repoRoot, ac.Since); err != nil {
+			return err
+		}
+		ac.printVolatile(dependencyGraph)
+	}
+	if len(ac.ListUndocumented) > 0 {
+		ac.printUndocumented(dependencyGraph)
+	}
+	return nil
+}
+
+// isolatedNodes returns the sorted IDs of nodes alone in their weakly connected
+// component. A package that imports nothing but is imported is a sink, not
+// isolated; one with no edges at all is likely dead or a standalone tool.
+func isolatedNodes(g *graph.Graph) []string {
+	var ids []string
+	for _, component := range graph.WeakComponents(g) {
+		if len(component) == 1 {
+			ids = append(ids, component[0])
+		}
+	}
+	return ids
+}
+
+// cgoNodes returns the IDs of nodes that use cgo, sorted.
+func cgoNodes(g *graph.Graph) []string {
+	var ids []string
+	for _, node := range g.Nodes() {
+		if node.GoCGO {
+			ids = append(ids, node.ID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// unsafeNodes returns the IDs of nodes that import unsafe, sorted.
+func unsafeNodes(g *graph.Graph) []string {
+	var ids []string
+	for _, node := range g.Nodes() {
+		if node.UnsafeUsage {
+			ids = append(ids, node.ID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// printLongestChain lists the packages on the longest import chain, top-down.
+// An import cycle on the chain is one step, listed with all its members.
+func (ac *AnalyzeCommand) printLongestChain(g *graph.Graph) {
+	chain := metrics.LongestChain(g)
+	depth := metrics.Depth(g)
+	fmt.Fprintf(ac.output, "longest dependency chain (%d imports):\n", max(len(chain)-1, 0))
+	for _, component := range chain {
+		labels := make([]string, len(component))
+		for i, id := range component {
+			node, _ := g.Node(id)
+			labels[i] = node.Label()
+		}
+		if depth[component[0]] == metrics.DepthCyclic {
+			fmt.Fprintf(ac.output, "  cycle: %s (depth infinite/cyclic)\n", strings.Join(labels, ", "))
+			continue
+		}
+		fmt.Fprintf(ac.output, "  %s\n", labels[0])
+	}
+}
+
+// printConstrainedNodes lists packages guarded by build constraints, sorted by
+// ID, each followed by its constraint expressions. Only files matching the
+// current build context are loaded, so a package whose other files are
+// constrained away appears with just the constraints that matched.
+func (ac *AnalyzeCommand) printConstrainedNodes(g *graph.Graph) {
+	nodes := append([]*graph.Node(nil), g.Nodes()...)
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+
+	fmt.Fprintf(ac.output, "build-constrained packages (compiled only for matching tags):\n")
+	for _, node := range nodes {
+		if len(node.BuildConstraints) > 0 {
+			fmt.Fprintf(ac.output, "  %s: %s\n", node.Label(), strings.Join(node.BuildConstraints, "; "))
+		}
+	}
+}
+
+// printModuleVersions lists each module providing a package in the graph with
+// its version, sorted by module path. The main module and replaced modules
+// have no version and are shown as "(none)"; packages outside any module are
+// skipped.
+func (ac *AnalyzeCommand) printModuleVersions(g *graph.Graph) {
+	versions := make(map[string]string)
+	for _, node := range g.Nodes() {
+		if node.ModulePath != "" {
+			versions[node.ModulePath] = node.ModuleVersion
+		}
+	}
+	modulePaths := make([]string, 0, len(versions))
+	width := 0
+	for modulePath := range versions {
+		modulePaths = append(modulePaths, modulePath)
+		width = max(width, len(modulePath))
+	}
+	sort.Strings(modulePaths)
+
+	fmt.Fprintf(ac.output, "module versions:\n")
+	for _, modulePath := range modulePaths {
+		version := versions[modulePath]
+		if version == "" {
+			version = "(none)"
+		}
+		fmt.Fprintf(ac.output, "  %-*s  %s\n", width, modulePath, version)
+	}
+}
+
+// printTestFrameworks prints how many packages use each Node.TestFramework,
+// most used first, with packages without tests counted as "(no tests)".
+func (ac *AnalyzeCommand) printTestFrameworks(g *graph.Graph) {
+	counts := make(map[string]int)
+	for _, node := range g.Nodes() {
+		if node.Kind != graph.KindPackage {
+			continue
+		}
+		framework := node.TestFramework
+		if framework == "" {
+			framework = "(no tests)"
+		}
+		counts[framework]++
+	}
+	frameworks := make([]string, 0, len(counts))
+	width := 0
+	for framework := range counts {
+		frameworks = append(frameworks, framework)
+		width = max(width, len(framework))
+	}
+	sort.Slice(frameworks, func(i, j int) bool {
+		if counts[frameworks[i]] != counts[frameworks[j]] {
+			return counts[frameworks[i]] > counts[frameworks[j]]
+		}
+		return frameworks[i] < frameworks[j]
+	})
+
+	fmt.Fprintf(ac.output, "test frameworks:\n")
+	for _, framework := range frameworks {
+		fmt.Fprintf(ac.output, "  %-*s  %d\n", width, framework, counts[framework])
+	}
+}
+
+// mainGoVersion returns the go directive of the main module, the newest one
+// when a workspace has several, or "" when none declares one.
+func mainGoVersion(pkgs []*packages.Package) string {
+	mainVersion := ""
+	for _, pkg := range pkgs {
+		if pkg.Module == nil || !pkg.Module.Main || pkg.Module.GoVersion == "" {
+			continue
+		}
+		if mainVersion == "" || version.Compare("go"+pkg.Module.GoVersion, "go"+mainVersion) > 0 {
+			mainVersion = pkg.Module.GoVersion
+		}
+	}
+	return mainVersion
+}
+
+// printOldGoVersions lists the packages, sorted by ID, whose module requires
+// an older Go version than mainVersion. Such modules cannot rely on newer
+// language features and may behave differently, e.g. loop variable scoping
+// before Go 1.22.
+func (ac *AnalyzeCommand) printOldGoVersions(g *graph.Graph, mainVersion string) {
+	nodes := append([]*graph.Node(nil), g.Nodes()...)
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+
+	fmt.Fprintf(ac.output, "packages requiring an older Go than the main module (go %s):\n", mainVersion)
+	if mainVersion == "" {
+		return
+	}
+	for _, node := range nodes {
+		if node.GoVersion != "" && version.Compare("go"+node.GoVersion, "go"+mainVersion) < 0 {
+			fmt.Fprintf(ac.output, "  %s: go %s (%s)\n", node.Label(), node.GoVersion, node.ModulePath)
+		}
+	}
+}
+
+func (ac *AnalyzeCommand) printNodes(g *graph.Graph, heading string, ids []string) {
+	fmt.Fprintf(ac.output, "%s:\n", heading)
+	for _, id := range ids {
+		node, _ := g.Node(id)
+		fmt.Fprintf(ac.output, "  %s\n", node.Label())
+	}
+}
+
+// printScores lists every node's score for one metric, highest first.
+func (ac *AnalyzeCommand) printScores(g *graph.Graph, metricName string, scores map[string]float64) {
+	nodes := append([]*graph.Node(nil), g.Nodes()...)
+	sort.Slice(nodes, func(i, j int) bool {
+		if scores[nodes[i].ID] != scores[nodes[j].ID] {
+			return scores[nodes[i].ID] > scores[nodes[j].ID]
+		}
+		return nodes[i].ID < nodes[j].ID
+	})
+
+	fmt.Fprintf(ac.output, "%s:\n", metricName)
+	for _, node := range nodes {
+		fmt.Fprintf(ac.output, "  %.3f  %s\n", scores[node.ID], node.Label())
+	}
+}
+
+// printEmbedCounts lists the packages embedding files, most embedded files
+// first, flagging those above embedCountWarning.
+func (ac *AnalyzeCommand) printEmbedCounts(g *graph.Graph) {
+	var nodes []*graph.Node
+	for _, node := range g.Nodes() {
+		if node.EmbedCount > 0 {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].EmbedCount != nodes[j].EmbedCount {
+			return nodes[i].EmbedCount > nodes[j].EmbedCount
+		}
+		return nodes[i].ID < nodes[j].ID
+	})
+
+	fmt.Fprintf(ac.output, "%s:\n", embedMetric)
+	for _, node := range nodes {
+		warning := ""
+		if node.EmbedCount > embedCountWarning {
+			warning = fmt.Sprintf("  (over %d embedded files, may inflate binary size)", embedCountWarning)
+		}
+		fmt.Fprintf(ac.output, "  %5d  %s%s\n", node.EmbedCount, node.Label(), warning)
+	}
+}
+
+// printUntested lists the package nodes without test functions by how many
+// packages import them, descending: an untested package many others depend
+// on is the riskiest. Only packages loaded with their tests can be testable.
+func (ac *AnalyzeCommand) printUntested(g *graph.Graph) {
+	fanIn := metrics.FanIn(g)
+	var nodes []*graph.Node
+	for _, node := range g.Nodes() {
+		if node.Kind == graph.KindPackage && !node.Testable {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if fanIn[nodes[i].ID] != fanIn[nodes[j].ID] {
+			return fanIn[nodes[i].ID] > fanIn[nodes[j].ID]
+		}
+		return nodes[i].ID < nodes[j].ID
+	})
+
+	fmt.Fprintf(ac.output, "%s (packages without test functions, by importer count):\n", testabilityMetric)
+	for _, node := range nodes {
+		fmt.Fprintf(ac.output, "  %5d  %s\n", fanIn[node.ID], node.Label())
+	}
+}
+
+// printBipartite lists the two groups of a bipartite import graph, group 0
+// first. Otherwise it lists the packages on an odd cycle, direction ignored,
+// with the groups the failed 2-colouring gave them.
+func (ac *AnalyzeCommand) printBipartite(g *graph.Graph) {
+	isBipartite, coloring := graph.BipartiteCheck(g)
+	ids := make([]string, 0, len(coloring))
+	for id := range coloring {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if coloring[ids[i]] != coloring[ids[j]] {
+			return coloring[ids[i]] < coloring[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+
+	if isBipartite {
+		fmt.Fprintf(ac.output, "%s (two groups with no imports inside either):\n", bipartiteMetric)
+	} else {
+		fmt.Fprintf(ac.output, "%s: no (odd cycle when import direction is ignored):\n", bipartiteMetric)
+	}
+	for _, id := range ids {
+		node, _ := g.Node(id)
+		fmt.Fprintf(ac.output, "  %d  %s\n", coloring[id], node.Label())
+	}
+}
+
+// printDocCoverage lists the packages exporting declarations by the share of
+// them that is documented, least documented first. The percentages are read
+// off the graph's package nodes.
+func (ac *AnalyzeCommand) printDocCoverage(g *graph.Graph) {
+	coverage := analyzer.DocCoverage(g)
+	ids := make([]string, 0, len(coverage))
+	for id := range coverage {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if coverage[ids[i]] != coverage[ids[j]] {
+			return coverage[ids[i]] < coverage[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+
+	fmt.Fprintf(ac.output, "%s:\n", docCoverageMetric)
+	for _, id := range ids {
+		node, _ := g.Node(id)
+		fmt.Fprintf(ac.output, "  %3d%%  %s\n", coverage[id], node.Label())
+	}
+}
+
+// printUndocumented lists the undocumented exported functions and methods of
+// the packages matching --list-undocumented, as "Func" or "Type.Method".
+// Relative patterns such as ./api are resolved against the module path.
+func (ac *AnalyzeCommand) printUndocumented(g *graph.Graph) {
+	fmt.Fprintf(ac.output, "undocumented exported functions:\n")
+	for _, id := range analyzer.UndocumentedFunctions(g, ac.undocumentedPatterns(g)) {
+		_, parts, _ := graph.ParseID(id)
+		name := parts[2]
+		if parts[1] != "" {
+			name = parts[1] + "." + name
+		}
+		fmt.Fprintf(ac.output, "  %s: %s\n", parts[0], name)
+	}
+}
+
+// undocumentedPatterns turns "./"-relative --list-undocumented patterns into
+// import path patterns under the loaded module.
+func (ac *AnalyzeCommand) undocumentedPatterns(g *graph.Graph) []string {
+	modulePath := ""
+	for _, node := range g.Nodes() {
+		if node.Kind == graph.KindPackage && node.ModulePath != "" && node.ModuleVersion == "" {
+			modulePath = node.ModulePath
+			break
+		}
+	}
+	patterns := make([]string, 0, len(ac.ListUndocumented))
+	for _, pattern := range ac.ListUndocumented {
+		if relative, found := strings.CutPrefix(pattern, "./"); found && modulePath != "" {
+			pattern = modulePath + "/" + relative
+		}
+		if pattern == "." && modulePath != "" {
+			pattern = modulePath
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns
+}
+
+func nodeMetricNames() []string {
+	names := []string{bipartiteMetric, docCoverageMetric, embedMetric, hitsMetric, testabilityMetric}
+	for name := range nodeMetrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// printTestOnlyDeps prints the number of import edges per analyzer.ImportUsage
+// and then the packages only test files import.
+func (ac *AnalyzeCommand) printTestOnlyDeps(g *graph.Graph) {
+	counts := analyzer.ImportUsageCounts(g)
+	fmt.Fprintf(ac.output, "import usage:\n")
+	for _, usage := range []string{parser.ImportProduction, parser.ImportMixed, parser.ImportTestOnly} {
+		fmt.Fprintf(ac.output, "  %-10s  %d\n", usage, counts[usage])
+	}
+	ac.printNodes(g, "test-only packages (imported only from test files; candidates for internal/testutil)", analyzer.TestOnlyPackages(g))
+}
+
+// rebuildImpactLimit caps the packages --rebuild-impact lists.
+const rebuildImpactLimit = 20
+
+// printRebuildImpact prints the packages with the largest reverse import
+// closures, from the rebuild impact attributes, ranked by package count, then
+// lines of code, then ID. Packages nothing imports are left out.
+func (ac *AnalyzeCommand) printRebuildImpact(g *graph.Graph) {
+	type impact struct {
+		node                  *graph.Node
+		packages, linesOfCode int
+	}
+	var impacts []impact
+	for _, node := range g.Nodes() {
+		packages, _ := strconv.Atoi(node.Attributes[extract.AttributeRebuildImpactPackages])
+		linesOfCode, _ := strconv.Atoi(node.Attributes[extract.AttributeRebuildImpactLOC])
+		if packages > 0 {
+			impacts = append(impacts, impact{node: node, packages: packages, linesOfCode: linesOfCode})
+		}
+	}
+	sort.Slice(impacts, func(i, j int) bool {
+		if impacts[i].packages != impacts[j].packages {
+			return impacts[i].packages > impacts[j].packages
+		}
+		if impacts[i].linesOfCode != impacts[j].linesOfCode {
+			return impacts[i].linesOfCode > impacts[j].linesOfCode
+		}
+		return impacts[i].node.ID < impacts[j].node.ID
+	})
+
+	fmt.Fprintf(ac.output, "rebuild impact (packages and lines of code transitively importing each package):\n")
+	for _, entry := range impacts[:min(len(impacts), rebuildImpactLimit)] {
+		fmt.Fprintf(ac.output, "  %-40s  %5d pkgs  %8d loc\n", entry.node.Label(), entry.packages, entry.linesOfCode)
+	}
+}
+
+// signatureLimit caps the functions --signatures lists.
+const signatureLimit = 20
+
+// printSignatures prints the exported functions with the most parameters,
+// then results, then bool parameters, as analyzer.FunctionSignatures ranks
+// them.
+func (ac *AnalyzeCommand) printSignatures(g *graph.Graph) {
+	signatures := analyzer.FunctionSignatures(g)
+	fmt.Fprintf(ac.output, "function signatures (params, results, bool params):\n")
+	for _, signature := range signatures[:min(len(signatures), signatureLimit)] {
+		fmt.Fprintf(ac.output, "  %-50s  %3d params  %3d results  %3d bools\n",
+			signature.Function, signature.Params, signature.Results, signature.BoolParams)
+	}
+}
+
+// volatileLimit caps the packages --volatile lists.
+const volatileLimit = 20
+
+// printVolatile prints the packages analyzer.VolatilePackages ranks highest.
+func (ac *AnalyzeCommand) printVolatile(g *graph.Graph) {
+	volatile := analyzer.VolatilePackages(g)
+	fmt.Fprintf(ac.output, "volatile packages (commits x importers):\n")
+	for _, entry := range volatile[:min(len(volatile), volatileLimit)] {
+		node, _ := g.Node(entry.Package)
+		fmt.Fprintf(ac.output, "  %-40s  %5d commits  %4d importers  %6d score\n",
+			node.Label(), entry.ChangeFrequency, entry.FanIn, entry.Score)
+	}
+}
+
+// genericSymbolLimit caps the most instantiated symbols --generics lists.
+const genericSymbolLimit = 10
+
+// printGenerics prints the generics counts of every package using generics,
+// sorted by ID, and then the most instantiated generic symbols with the type
+// arguments they are instantiated with, most frequent first.
+func (ac *AnalyzeCommand) printGenerics(g *graph.Graph, symbols []analyzer.InstantiatedSymbol) {
+	nodes := append([]*graph.Node(nil), g.Nodes()...)
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+
+	fmt.Fprintf(ac.output, "generics:\n")
+	for _, node := range nodes {
+		if _, found := node.Attributes[extract.AttributeGenericFuncs]; !found {
+			continue
+		}
+		fmt.Fprintf(ac.output, "  %s: %s funcs, %s types, %s constraints, %s instantiations, %s deferred\n", node.Label(),
+			node.Attributes[extract.AttributeGenericFuncs], node.Attributes[extract.AttributeGenericTypes],
+			node.Attributes[extract.AttributeConstraintInterfaces], node.Attributes[extract.AttributeInstantiations],
+			node.Attributes[extract.AttributeDeferredInstantiations])
+	}
+
+	fmt.Fprintf(ac.output, "most instantiated generic symbols:\n")
+	for _, symbol := range symbols[:min(len(symbols), genericSymbolLimit)] {
+		typeArgs := make([]string, 0, len(symbol.TypeArgs))
+		for typeArg := range symbol.TypeArgs {
+			typeArgs = append(typeArgs, typeArg)
+		}
+		sort.Slice(typeArgs, func(i, j int) bool {
+			if symbol.TypeArgs[typeArgs[i]] != symbol.TypeArgs[typeArgs[j]] {
+				return symbol.TypeArgs[typeArgs[i]] > symbol.TypeArgs[typeArgs[j]]
+			}
+			return typeArgs[i] < typeArgs[j]
+		})
+		frequencies := make([]string, len(typeArgs))
+		for i, typeArg := range typeArgs {
+			frequencies[i] = fmt.Sprintf("[%s] %d", typeArg, symbol.TypeArgs[typeArg])
+		}
+		fmt.Fprintf(ac.output, "  %s: %d (%s)\n", symbol.Symbol, symbol.Instantiations, strings.Join(frequencies, ", "))
+	}
+}
@@ -0,0 +1,656 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+func TestNewAnalyzeCommand(t *testing.T) {
+	cmd, err := NewAnalyzeCommand([]string{"--metrics", "closeness", t.TempDir()})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(cmd.Metrics, []string{"closeness"}) {
+		t.Errorf("Metrics = %v, want [closeness]", cmd.Metrics)
+	}
+
+	errorTests := []struct {
+		name string
+		args []string
+	}{
+		{name: "nothing selected", args: []string{}},
+		{name: "unknown metric", args: []string{"--metrics", "closeness,unknown"}},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewAnalyzeCommand(append(tt.args, t.TempDir())); !errors.Is(err, ErrUsage) {
+				t.Fatalf("expected ErrUsage, got %v", err)
+			}
+		})
+	}
+}
+
+func TestAnalyzeCommand_Execute_Closeness(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":     "module testanalyze\n\ngo 1.24\n",
+		"a/a.go":     "package a\n\nimport _ \"testanalyze/b\"\n",
+		"b/b.go":     "package b\n\nimport _ \"testanalyze/c\"\n",
+		"c/c.go":     "package c\n",
+		"cmd/cmd.go": "package main\n\nimport _ \"testanalyze/a\"\n\nfunc main() {}\n",
+	})
+
+	cmd, err := NewAnalyzeCommand([]string{"--metrics", "closeness", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	// Chain cmd -> a -> b -> c: the middle packages score 3/4, the ends 3/6.
+	want := "closeness:\n" +
+		"  0.750  a\n" +
+		"  0.750  b\n" +
+		"  0.500  c\n" +
+		"  0.500  cmd\n"
+	if !strings.Contains(output.String(), want) {
+		t.Errorf("output = %q, want %q", output.String(), want)
+	}
+}
+
+func TestAnalyzeCommand_Execute_SourcesSinksAndIsolated(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":       "module testends\n\ngo 1.24\n",
+		"cmd/main.go":  "package main\n\nimport _ \"testends/api\"\n\nfunc main() {}\n",
+		"api/api.go":   "package api\n\nimport _ \"testends/util\"\n",
+		"util/util.go": "package util\n",
+		"dead/dead.go": "package dead\n",
+	})
+
+	cmd, err := NewAnalyzeCommand([]string{"--list-sources", "--list-sinks", "--list-isolated", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	// util is a sink but still imported; only dead has no edges at all.
+	want := "sources:\n  cmd\n  dead\nsinks:\n  dead\n  util\nisolated packages (no edges in either direction):\n  dead\n"
+	if output.String() != want {
+		t.Errorf("output = %q, want %q", output.String(), want)
+	}
+}
+
+func TestAnalyzeCommand_Execute_ListCGO(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":         "module testcgo\n\ngo 1.24\n",
+		"native/lib.go":  "package native\n\n// #cgo LDFLAGS: -lm\n",
+		"pure/pure.go":   "package pure\n\nimport _ \"testcgo/native\"\n",
+		"other/other.go": "package other\n",
+	})
+
+	cmd, err := NewAnalyzeCommand([]string{"--list-cgo", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if !strings.HasPrefix(output.String(), "cgo packages") || !strings.HasSuffix(output.String(), ":\n  native\n") {
+		t.Errorf("expected only native to be listed, got %q", output.String())
+	}
+}
+
+func TestAnalyzeCommand_Execute_ListUnsafe(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":            "module testunsafe\n\ngo 1.24\n",
+		"bytesconv/conv.go": "package bytesconv\n\nimport \"unsafe\"\n\nvar Size = unsafe.Sizeof(0)\n",
+		"store/store.go":    "package store\n\nimport _ \"testunsafe/bytesconv\"\n",
+	})
+
+	cmd, err := NewAnalyzeCommand([]string{"--list-unsafe", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if !strings.HasPrefix(output.String(), "unsafe packages") || !strings.HasSuffix(output.String(), ":\n  bytesconv\n") {
+		t.Errorf("expected only bytesconv to be listed, got %q", output.String())
+	}
+}
+
+func TestAnalyzeCommand_Execute_ListConstrained(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":            "module testtags\n\ngo 1.24\n",
+		"sys/sys_unix.go":   "//go:build unix\n\npackage sys\n",
+		"sys/sys_all.go":    "package sys\n",
+		"plain/plain.go":    "package plain\n",
+		"legacy/legacy.go":  "// +build !windows\n\npackage legacy\n",
+		"legacy/helpers.go": "//go:build !windows\n\npackage legacy\n",
+	})
+
+	cmd, err := NewAnalyzeCommand([]string{"--list-constrained", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	want := "build-constrained packages (compiled only for matching tags):\n  legacy: !windows\n  sys: unix\n"
+	if output.String() != want {
+		t.Errorf("output = %q, want %q", output.String(), want)
+	}
+}
+
+func TestAnalyzeCommand_Execute_DocCoverage(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":       "module testdocs\n\ngo 1.24\n",
+		"api/api.go":   "package api\n\n// Serve is documented.\nfunc Serve() {}\n\nfunc Listen() {}\n\ntype Server struct{}\n\nfunc (s *Server) Close() {}\n",
+		"util/util.go": "package util\n\n// Limits are documented as a group.\nconst (\n\tMin = 1\n\tMax = 2\n)\n\nfunc Clamp() {}\n",
+		"none/none.go": "package none\n\nfunc helper() {}\n",
+	})
+
+	cmd, err := NewAnalyzeCommand([]string{"--metrics", "doc-coverage", "--list-undocumented", "./api", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	want := "doc-coverage:\n   25%  api\n   66%  util\n" +
+		"undocumented exported functions:\n  testdocs/api: Listen\n  testdocs/api: Server.Close\n"
+	if output.String() != want {
+		t.Errorf("output = %q, want %q", output.String(), want)
+	}
+}
+
+func TestAnalyzeCommand_Execute_LongestChain(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":       "module testchain\n\ngo 1.24\n",
+		"cmd/main.go":  "package main\n\nimport (\n\t_ \"testchain/api\"\n\t_ \"testchain/util\"\n)\n\nfunc main() {}\n",
+		"api/api.go":   "package api\n\nimport _ \"testchain/a\"\n",
+		"a/a.go":       "package a\n\nimport _ \"testchain/b\"\n",
+		"b/b.go":       "package b\n\nimport (\n\t_ \"testchain/a\"\n\t_ \"testchain/util\"\n)\n",
+		"util/util.go": "package util\n",
+	})
+
+	cmd, err := NewAnalyzeCommand([]string{"--longest-chain", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	want := "longest dependency chain (3 imports):\n  cmd\n  api\n  cycle: a, b (depth infinite/cyclic)\n  util\n"
+	if output.String() != want {
+		t.Errorf("output = %q, want %q", output.String(), want)
+	}
+}
+
+func TestAnalyzeCommand_Execute_HITS(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":         "module testhits\n\ngo 1.24\n",
+		"app/app.go":     "package app\n\nimport _ \"testhits/store\"\n",
+		"store/store.go": "package store\n",
+	})
+
+	cmd, err := NewAnalyzeCommand([]string{"--metrics", "hits", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	want := "hits hubs:\n  1.000  app\n  0.000  store\n" +
+		"hits authorities:\n  1.000  store\n  0.000  app\n"
+	if output.String() != want {
+		t.Errorf("output = %q, want %q", output.String(), want)
+	}
+}
+
+func TestAnalyzeCommand_Execute_Katz(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":         "module testkatz\n\ngo 1.24\n",
+		"app/app.go":     "package app\n\nimport _ \"testkatz/store\"\n",
+		"store/store.go": "package store\n",
+	})
+
+	cmd, err := NewAnalyzeCommand([]string{"--metrics", "katz", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if want := "katz:\n  1.010  store\n  1.000  app\n"; output.String() != want {
+		t.Errorf("output = %q, want %q", output.String(), want)
+	}
+}
+
+func TestAnalyzeCommand_Execute_Testability(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":            "module testtestability\n\ngo 1.24\n",
+		"store/store.go":    "package store\n\nfunc Get() int { return 1 }\n",
+		"api/api.go":        "package api\n\nimport \"testtestability/store\"\n\nfunc Get() int { return store.Get() }\n",
+		"api/api_test.go":   "package api\n\nimport \"testing\"\n\nfunc TestGet(t *testing.T) {}\n",
+		"util/util.go":      "package util\n",
+		"util/util_test.go": "package util\n\nimport \"testing\"\n\nfunc helper(t *testing.T) {}\n",
+		"cmd/app/main.go":   "package main\n\nimport (\n\t\"testtestability/api\"\n\t\"testtestability/store\"\n)\n\nfunc main() { _ = api.Get() + store.Get() }\n",
+	})
+
+	if _, err := NewAnalyzeCommand([]string{"--metrics", "testability", testDir}); !errors.Is(err, ErrUsage) {
+		t.Errorf("expected ErrUsage without --include-tests, got %v", err)
+	}
+
+	cmd, err := NewAnalyzeCommand([]string{"--metrics", "testability", "--include-tests", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	want := "testability (packages without test functions, by importer count):\n" +
+		"      2  store\n      0  cmd/app\n      0  util\n"
+	if output.String() != want {
+		t.Errorf("output = %q, want %q", output.String(), want)
+	}
+}
+
+func TestAnalyzeCommand_Execute_Bipartite(t *testing.T) {
+	tests := []struct {
+		name       string
+		storeGo    string
+		wantOutput string
+	}{
+		{
+			name:    "bipartite",
+			storeGo: "package store\n",
+			wantOutput: "bipartite (two groups with no imports inside either):\n" +
+				"  0  app\n  1  store\n  1  util\n",
+		},
+		{
+			// app, store and util import each other in a triangle.
+			name:    "odd cycle",
+			storeGo: "package store\n\nimport _ \"testbipartite/util\"\n",
+			wantOutput: "bipartite: no (odd cycle when import direction is ignored):\n" +
+				"  0  app\n  1  store\n  1  util\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testDir := writeTestModule(t, map[string]string{
+				"go.mod":         "module testbipartite\n\ngo 1.24\n",
+				"app/app.go":     "package app\n\nimport (\n\t_ \"testbipartite/store\"\n\t_ \"testbipartite/util\"\n)\n",
+				"store/store.go": tt.storeGo,
+				"util/util.go":   "package util\n",
+			})
+
+			cmd, err := NewAnalyzeCommand([]string{"--metrics", "bipartite", testDir})
+			if err != nil {
+				t.Fatalf("setup failed: %v", err)
+			}
+			var output bytes.Buffer
+			cmd.output = &output
+
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if output.String() != tt.wantOutput {
+				t.Errorf("output = %q, want %q", output.String(), tt.wantOutput)
+			}
+		})
+	}
+}
+
+func TestAnalyzeCommand_Execute_MainSequence(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":         "module testmain\n\ngo 1.24\n",
+		"app/app.go":     "package app\n\nimport _ \"testmain/store\"\n",
+		"store/store.go": "package store\n\ntype DB struct{}\n",
+	})
+
+	cmd, err := NewAnalyzeCommand([]string{"--metrics", "main-sequence", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	// store is stable and concrete (zone of pain); app is unstable and concrete.
+	want := "main-sequence:\n  1.000  store\n  0.000  app\n"
+	if output.String() != want {
+		t.Errorf("output = %q, want %q", output.String(), want)
+	}
+}
+
+func TestAnalyzeCommand_Execute_Embed(t *testing.T) {
+	files := map[string]string{
+		"go.mod":          "module testembed\n\ngo 1.24\n",
+		"web/web.go":      "package web\n\nimport \"embed\"\n\n//go:embed static\nvar Static embed.FS\n",
+		"docs/docs.go":    "package docs\n\nimport _ \"embed\"\n\n//go:embed README.txt\nvar Readme string\n",
+		"docs/README.txt": "docs\n",
+		"plain/plain.go":  "package plain\n",
+	}
+	for i := range 101 {
+		files[fmt.Sprintf("web/static/%03d.css", i)] = "a{}\n"
+	}
+	testDir := writeTestModule(t, files)
+
+	cmd, err := NewAnalyzeCommand([]string{"--metrics", "embed", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	want := "embed:\n" +
+		"    101  web  (over 100 embedded files, may inflate binary size)\n" +
+		"      1  docs\n"
+	if output.String() != want {
+		t.Errorf("output = %q, want %q", output.String(), want)
+	}
+}
+
+func TestAnalyzeCommand_Execute_ListModules(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":         "module testmodules\n\ngo 1.24\n",
+		"store/store.go": "package store\n",
+	})
+
+	cmd, err := NewAnalyzeCommand([]string{"--list-modules", "--list-old-go", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	want := "module versions:\n  testmodules  (none)\n" +
+		"packages requiring an older Go than the main module (go 1.24):\n"
+	if output.String() != want {
+		t.Errorf("output = %q, want %q", output.String(), want)
+	}
+}
+
+func TestAnalyzeCommand_PrintOldGoVersions(t *testing.T) {
+	g := graph.New()
+	for _, node := range []*graph.Node{
+		{ID: "testgo/app", Kind: graph.KindPackage, ModulePath: "testgo", GoVersion: "1.24"},
+		{ID: "legacy/codec", Kind: graph.KindPackage, ModulePath: "legacy", GoVersion: "1.18"},
+		{ID: "newer/lib", Kind: graph.KindPackage, ModulePath: "newer", GoVersion: "1.24.2"},
+	} {
+		if err := g.AddNode(node); err != nil {
+			t.Fatalf("AddNode() error = %v", err)
+		}
+	}
+
+	var output bytes.Buffer
+	(&AnalyzeCommand{output: &output}).printOldGoVersions(g, "1.24")
+
+	want := "packages requiring an older Go than the main module (go 1.24):\n  codec: go 1.18 (legacy)\n"
+	if output.String() != want {
+		t.Errorf("output = %q, want %q", output.String(), want)
+	}
+}
+
+func TestAnalyzeCommand_Execute_TestFrameworks(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":          "module testfw\n\ngo 1.24\n",
+		"api/api.go":      "package api\n",
+		"api/api_test.go": "package api\n\nimport \"testing\"\n\nfunc TestAPI(t *testing.T) {}\n",
+		"store/store.go":  "package store\n",
+		"store/s_test.go": "package store\n\nimport \"testing\"\n\nfunc TestStore(t *testing.T) {}\n",
+		"plain/plain.go":  "package plain\n",
+	})
+
+	cmd, err := NewAnalyzeCommand([]string{"--test-frameworks", "--include-tests", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	want := "test frameworks:\n  standard    2\n  (no tests)  1\n"
+	if output.String() != want {
+		t.Errorf("output = %q, want %q", output.String(), want)
+	}
+}
+
+func TestAnalyzeCommand_Execute_TestOnlyDeps(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":             "module testdeps\n\ngo 1.24\n",
+		"mocks/mocks.go":     "package mocks\n",
+		"store/store.go":     "package store\n",
+		"api/api.go":         "package api\n\nimport _ \"testdeps/store\"\n",
+		"api/api_test.go":    "package api\n\nimport (\n\t\"testing\"\n\n\t_ \"testdeps/mocks\"\n)\n\nfunc TestAPI(t *testing.T) {}\n",
+		"cmd/main.go":        "package main\n\nimport _ \"testdeps/api\"\n\nfunc main() {}\n",
+		"cmd/main_test.go":   "package main\n\nimport _ \"testdeps/api\"\n",
+		"store/s_test.go":    "package store\n\nimport _ \"testdeps/mocks\"\n",
+		"mocks/mock_test.go": "package mocks\n",
+	})
+
+	cmd, err := NewAnalyzeCommand([]string{"--test-only-deps", "--include-tests", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	want := "import usage:\n  production  1\n  mixed       1\n  test_only   2\n" +
+		"test-only packages (imported only from test files; candidates for internal/testutil):\n  mocks\n"
+	if output.String() != want {
+		t.Errorf("output = %q, want %q", output.String(), want)
+	}
+}
+
+func TestAnalyzeCommand_Execute_Generics(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":       "module testgen\n\ngo 1.24\n",
+		"coll/coll.go": "package coll\n\ntype Number interface{ ~int | ~float64 }\n\nfunc Sum[T Number](items []T) T { return 0 }\n\nfunc Double[T Number](items []T) T { return Sum(items) * 2 }\n",
+		"api/api.go":   "package api\n\nimport \"testgen/coll\"\n\nvar _ = coll.Sum([]int{})\n\nvar _ = coll.Sum([]float64{})\n",
+		"plain/p.go":   "package plain\n",
+	})
+
+	cmd, err := NewAnalyzeCommand([]string{"--generics", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	want := "generics:\n" +
+		"  api: 0 funcs, 0 types, 0 constraints, 2 instantiations, 0 deferred\n" +
+		"  coll: 2 funcs, 0 types, 1 constraints, 0 instantiations, 1 deferred\n" +
+		"most instantiated generic symbols:\n" +
+		"  testgen/coll.Sum: 2 ([float64] 1, [int] 1)\n"
+	if output.String() != want {
+		t.Errorf("output = %q, want %q", output.String(), want)
+	}
+}
+
+func TestAnalyzeCommand_Execute_RebuildImpact(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":         "module testimpact\n\ngo 1.24\n",
+		"base/base.go":   "package base\n",
+		"left/left.go":   "package left\n\nimport _ \"testimpact/base\"\n",
+		"right/right.go": "package right\n\nimport _ \"testimpact/base\"\n",
+		"top/top.go":     "package top\n\nimport (\n\t_ \"testimpact/left\"\n\t_ \"testimpact/right\"\n)\n",
+	})
+
+	cmd, err := NewAnalyzeCommand([]string{"--rebuild-impact", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected a header and three ranked packages, got:\n%s", output.String())
+	}
+	// top reaches base through two paths but counts once.
+	if fields := strings.Fields(lines[1]); fields[0] != "base" || fields[1] != "3" || fields[3] != "12" {
+		t.Errorf("first entry = %q, want base with 3 packages and 12 loc", lines[1])
+	}
+	if !strings.HasPrefix(strings.TrimSpace(lines[2]), "left ") || !strings.HasPrefix(strings.TrimSpace(lines[3]), "right ") {
+		t.Errorf("expected left then right, got:\n%s", output.String())
+	}
+}
+
+func TestAnalyzeCommand_Execute_Signatures(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod": "module testsig\n\ngo 1.24\n",
+		"store/store.go": "package store\n\nimport \"context\"\n\n" +
+			"func Open(ctx context.Context, name string, args ...string) (int, error) { return 0, nil }\n\n" +
+			"func Close(ctx context.Context) error { return nil }\n\n" +
+			"func helper(a, b, c, d int) {}\n",
+	})
+
+	cmd, err := NewAnalyzeCommand([]string{"--signatures", "--signature-exclude", "context.Context,error", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a header and the two exported functions, got:\n%s", output.String())
+	}
+	// The variadic args count once and context.Context and error not at all.
+	if fields := strings.Fields(lines[1]); fields[0] != "testsig/store.Open" || fields[1] != "2" || fields[3] != "1" {
+		t.Errorf("first entry = %q, want testsig/store.Open with 2 params and 1 result", lines[1])
+	}
+	if fields := strings.Fields(lines[2]); fields[0] != "testsig/store.Close" || fields[1] != "0" || fields[3] != "0" {
+		t.Errorf("second entry = %q, want testsig/store.Close with no counted params or results", lines[2])
+	}
+}
+
+func TestAnalyzeCommand_Execute_Volatile(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":         "module testvol\n\ngo 1.24\n",
+		"store/store.go": "package store\n\nfunc Get() int { return 0 }\n",
+		"api/api.go":     "package api\n\nimport \"testvol/store\"\n\nfunc Serve() int { return store.Get() }\n",
+		"cmd/main.go":    "package main\n\nimport (\n\t\"testvol/api\"\n\t\"testvol/store\"\n)\n\nfunc main() { _ = api.Serve() + store.Get() }\n",
+	})
+	git := func(args ...string) {
+		t.Helper()
+		command := exec.Command("git", append([]string{"-C", testDir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if output, err := command.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+	}
+	git("init", "-q")
+	git("add", ".")
+	git("commit", "-q", "-m", "initial")
+	if err := os.WriteFile(filepath.Join(testDir, "store", "store.go"), []byte("package store\n\n// Get returns zero.\nfunc Get() int { return 0 }\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	git("commit", "-q", "-am", "document store")
+
+	cmd, err := NewAnalyzeCommand([]string{"--volatile", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a header, store and api, got:\n%s", output.String())
+	}
+	// store: 2 commits x 2 importers; api: 1 commit x 1 importer.
+	if fields := strings.Fields(lines[1]); !strings.HasSuffix(fields[0], "store") || fields[1] != "2" || fields[5] != "4" {
+		t.Errorf("first entry = %q, want store with 2 commits and score 4", lines[1])
+	}
+	if fields := strings.Fields(lines[2]); !strings.HasSuffix(fields[0], "api") || fields[5] != "1" {
+		t.Errorf("second entry = %q, want api with score 1", lines[2])
+	}
+}
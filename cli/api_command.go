@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Desgue/codegraph/analyzer"
+	"github.com/Desgue/codegraph/parser"
+	"github.com/Desgue/codegraph/path"
+)
+
+type APICommand struct {
+	TargetDirectory *path.TargetDirectory
+	Packages        []string
+	JSON            bool
+	OutputFile      string
+
+	output io.Writer
+}
+
+// apiReport is the --json output of the api command.
+type apiReport struct {
+	Packages []analyzer.APIPackage `json:"packages"`
+}
+
+func NewAPICommand(args []string) (*APICommand, error) {
+	flagSet := flag.NewFlagSet("api", flag.ContinueOnError)
+
+	apiCommand := &APICommand{output: os.Stdout}
+	packageList := ""
+
+	flagSet.StringVar(&packageList, "packages", "",
+		"Comma-separated package patterns to report (pkg or pkg/...; default: every non-main package)")
+	flagSet.BoolVar(&apiCommand.JSON, "json", false, "Print the API surface as JSON")
+	flagSet.StringVar(&apiCommand.OutputFile, "output", "", "Write the report to this file instead of stdout")
+
+	if err := flagSet.Parse(args); err != nil {
+		return nil, newUsageError(err)
+	}
+	if packageList != "" {
+		apiCommand.Packages = strings.Split(packageList, ",")
+	}
+
+	directoryArgument := ""
+	if flagSet.NArg() > 0 {
+		directoryArgument = flagSet.Arg(0)
+	}
+
+	targetDirectory, err := path.NewTargetDirectory(directoryArgument)
+	if err != nil {
+		return nil, err
+	}
+	apiCommand.TargetDirectory = targetDirectory
+
+	return apiCommand, nil
+}
+
+// Execute loads the packages without tests, so test helpers are never part of
+// the reported API.
+func (ac *APICommand) Execute() error {
+	pkgs, _, err := parser.Load(ac.TargetDirectory.Path, false)
+	if err != nil {
+		return err
+	}
+	api := analyzer.ExportedAPI(pkgs, ac.Packages)
+
+	var report bytes.Buffer
+	if ac.JSON {
+		if api == nil {
+			api = []analyzer.APIPackage{}
+		}
+		encoder := json.NewEncoder(&report)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(apiReport{Packages: api}); err != nil {
+			return err
+		}
+	} else {
+		writeAPI(&report, api)
+	}
+
+	if ac.OutputFile == "" {
+		_, err := ac.output.Write(report.Bytes())
+		return err
+	}
+	if err := os.WriteFile(ac.OutputFile, report.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write output file '%s': %w", ac.OutputFile, err)
+	}
+	fmt.Fprintf(ac.output, "Wrote the API of %d packages to %s\n", len(api), ac.OutputFile)
+	return nil
+}
+
+// writeAPI prints one block per package, methods and fields indented under
+// their type.
+func writeAPI(writer io.Writer, api []analyzer.APIPackage) {
+	for _, pkg := range api {
+		fmt.Fprintf(writer, "package %s\n", pkg.Package)
+		for _, function := range pkg.Funcs {
+			fmt.Fprintf(writer, "  func %s%s\n", function.Name, function.Signature)
+		}
+		for _, apiType := range pkg.Types {
+			fmt.Fprintf(writer, "  type %s %s\n", apiType.Name, apiType.Underlying)
+			for _, field := range apiType.Fields {
+				fmt.Fprintf(writer, "    field %s %s\n", field.Name, field.Signature)
+			}
+			for _, method := range apiType.Methods {
+				receiver := ""
+				if method.PointerOnly {
+					receiver = "(*) "
+				}
+				fmt.Fprintf(writer, "    method %s%s%s\n", receiver, method.Name, method.Signature)
+			}
+		}
+		for _, constant := range pkg.Consts {
+			fmt.Fprintf(writer, "  const %s %s\n", constant.Name, constant.Signature)
+		}
+		for _, variable := range pkg.Vars {
+			fmt.Fprintf(writer, "  var %s %s\n", variable.Name, variable.Signature)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newAPITestCommand(t *testing.T, args ...string) (*APICommand, *bytes.Buffer) {
+	t.Helper()
+
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":         "module testapi\n\ngo 1.24\n",
+		"main.go":        "package main\n\nfunc main() {}\n",
+		"store/store.go": "package store\n\nconst Limit = 10\n\ntype DB struct{ Path string }\n\nfunc (db *DB) Get(key string) (int, error) { return 0, nil }\n\nfunc Open(path string) *DB { return nil }\n",
+		"util/util.go":   "package util\n\nvar Verbose bool\n",
+	})
+	cmd, err := NewAPICommand(append(args, testDir))
+	if err != nil {
+		t.Fatalf("NewAPICommand() error = %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+	return cmd, &output
+}
+
+func TestAPICommand_Execute(t *testing.T) {
+	cmd, output := newAPITestCommand(t, "--packages", "testapi/store")
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	want := "package testapi/store\n" +
+		"  func Open(string)(*testapi/store.DB)\n" +
+		"  type DB struct\n" +
+		"    field Path string\n" +
+		"    method (*) Get(string)(int, error)\n" +
+		"  const Limit untyped int\n"
+	if output.String() != want {
+		t.Errorf("output = %q, want %q", output.String(), want)
+	}
+}
+
+func TestAPICommand_Execute_JSONToFile(t *testing.T) {
+	outputFile := filepath.Join(t.TempDir(), "api.json")
+	cmd, output := newAPITestCommand(t, "--json", "--output", outputFile)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if output.String() != "Wrote the API of 2 packages to "+outputFile+"\n" {
+		t.Errorf("unexpected output %q", output.String())
+	}
+
+	content, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("expected output file to be written: %v", err)
+	}
+	var report apiReport
+	if err := json.Unmarshal(content, &report); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, content)
+	}
+	if len(report.Packages) != 2 || report.Packages[1].Package != "testapi/util" || report.Packages[1].Vars[0].Name != "Verbose" {
+		t.Errorf("unexpected report %+v", report)
+	}
+}
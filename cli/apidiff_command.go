@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Desgue/codegraph/analyzer"
+)
+
+type APIDiffCommand struct {
+	OldFile        string
+	NewFile        string
+	JSON           bool
+	FailOnBreaking bool
+
+	output io.Writer
+}
+
+func NewAPIDiffCommand(args []string) (*APIDiffCommand, error) {
+	flagSet := flag.NewFlagSet("apidiff", flag.ContinueOnError)
+
+	jsonOutput := flagSet.Bool("json", false, "Print the changes as JSON")
+	failOnBreaking := flagSet.Bool("fail-on-breaking", false, "Exit non-zero when any change is breaking")
+
+	if err := flagSet.Parse(args); err != nil {
+		return nil, newUsageError(err)
+	}
+
+	if flagSet.NArg() != 2 {
+		return nil, usageErrorf("apidiff requires exactly two API files from 'codegraph api --json': codegraph apidiff [options] <old> <new>")
+	}
+
+	return &APIDiffCommand{
+		OldFile:        flagSet.Arg(0),
+		NewFile:        flagSet.Arg(1),
+		JSON:           *jsonOutput,
+		FailOnBreaking: *failOnBreaking,
+		output:         os.Stdout,
+	}, nil
+}
+
+func (ac *APIDiffCommand) Execute() error {
+	oldAPI, err := readAPIFile(ac.OldFile)
+	if err != nil {
+		return err
+	}
+	newAPI, err := readAPIFile(ac.NewFile)
+	if err != nil {
+		return err
+	}
+
+	changes := analyzer.DiffAPI(oldAPI, newAPI)
+	if ac.JSON {
+		if changes == nil {
+			changes = []analyzer.APIChange{}
+		}
+		encoder := json.NewEncoder(ac.output)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(changes); err != nil {
+			return err
+		}
+	} else {
+		printAPIChanges(ac.output, changes)
+	}
+
+	breaking := 0
+	for _, change := range changes {
+		if change.Breaking {
+			breaking++
+		}
+	}
+	if ac.FailOnBreaking && breaking > 0 {
+		return fmt.Errorf("found %d breaking API change(s)", breaking)
+	}
+	return nil
+}
+
+// readAPIFile decodes an API surface written by the api command with --json.
+func readAPIFile(filePath string) ([]analyzer.APIPackage, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API file '%s': %w", filePath, err)
+	}
+	var report apiReport
+	if err := json.Unmarshal(content, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse API file '%s': %w", filePath, err)
+	}
+	return report.Packages, nil
+}
+
+// printAPIChanges lists breaking changes first, each as "! " for breaking or
+// "+ ", "- ", "~ " for additions, removals and changes.
+func printAPIChanges(writer io.Writer, changes []analyzer.APIChange) {
+	if len(changes) == 0 {
+		fmt.Fprintf(writer, "No API changes\n")
+		return
+	}
+
+	markers := map[string]string{analyzer.APIAdded: "+", analyzer.APIRemoved: "-", analyzer.APIChanged: "~"}
+	for _, breaking := range []bool{true, false} {
+		for _, change := range changes {
+			if change.Breaking != breaking {
+				continue
+			}
+			marker := markers[change.Change]
+			if breaking {
+				marker = "!" + marker
+			}
+			symbol := change.Package
+			if change.Symbol != "" {
+				symbol += "." + change.Symbol
+			}
+			fmt.Fprintf(writer, "%-2s %s %s", marker, change.Kind, symbol)
+			switch change.Change {
+			case analyzer.APIChanged:
+				fmt.Fprintf(writer, ": %s -> %s", change.Old, change.New)
+			case analyzer.APIAdded:
+				if change.New != "" {
+					fmt.Fprintf(writer, " %s", change.New)
+				}
+			}
+			fmt.Fprintf(writer, "\n")
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Desgue/codegraph/analyzer"
+)
+
+func writeAPIFile(t *testing.T, packages []analyzer.APIPackage) string {
+	t.Helper()
+
+	content, err := json.Marshal(apiReport{Packages: packages})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	filePath := filepath.Join(t.TempDir(), "api.json")
+	if err := os.WriteFile(filePath, content, 0644); err != nil {
+		t.Fatalf("Failed to write API file: %v", err)
+	}
+	return filePath
+}
+
+func TestNewAPIDiffCommand(t *testing.T) {
+	if _, err := NewAPIDiffCommand([]string{"old.json"}); !errors.Is(err, ErrUsage) {
+		t.Fatalf("expected ErrUsage, got %v", err)
+	}
+}
+
+func TestAPIDiffCommand_Execute(t *testing.T) {
+	oldFile := writeAPIFile(t, []analyzer.APIPackage{{Package: "mod/store",
+		Funcs: []analyzer.APISymbol{{Name: "Close", Signature: "()()"}},
+		Types: []analyzer.APIType{{Name: "Reader", Kind: "interface", Underlying: "interface"}}}})
+	newFile := writeAPIFile(t, []analyzer.APIPackage{{Package: "mod/store",
+		Funcs: []analyzer.APISymbol{{Name: "Open", Signature: "()()"}},
+		Types: []analyzer.APIType{{Name: "Reader", Kind: "interface", Underlying: "interface",
+			Methods: []analyzer.APIMethod{{APISymbol: analyzer.APISymbol{Name: "Read", Signature: "()(int)"}}}}}}})
+
+	t.Run("reports breaking changes first", func(t *testing.T) {
+		cmd, err := NewAPIDiffCommand([]string{oldFile, newFile})
+		if err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+		var output bytes.Buffer
+		cmd.output = &output
+
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		want := "!- func mod/store.Close\n" +
+			"!+ method mod/store.Reader.Read ()(int)\n" +
+			"+  func mod/store.Open ()()\n"
+		if output.String() != want {
+			t.Errorf("output = %q, want %q", output.String(), want)
+		}
+	})
+
+	t.Run("fails on breaking changes when asked", func(t *testing.T) {
+		cmd, err := NewAPIDiffCommand([]string{"--fail-on-breaking", "--json", oldFile, newFile})
+		if err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+		var output bytes.Buffer
+		cmd.output = &output
+
+		if err := cmd.Execute(); err == nil {
+			t.Fatal("expected breaking changes to fail the run")
+		}
+		var changes []analyzer.APIChange
+		if err := json.Unmarshal(output.Bytes(), &changes); err != nil || len(changes) != 3 {
+			t.Errorf("expected 3 changes as JSON, got %v: %s", err, output.String())
+		}
+	})
+
+	t.Run("identical snapshots", func(t *testing.T) {
+		cmd, err := NewAPIDiffCommand([]string{"--fail-on-breaking", oldFile, oldFile})
+		if err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+		var output bytes.Buffer
+		cmd.output = &output
+
+		if err := cmd.Execute(); err != nil || output.String() != "No API changes\n" {
+			t.Errorf("Execute() = %v, output %q", err, output.String())
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Desgue/codegraph/analyzer"
+	"github.com/Desgue/codegraph/parser"
+	"github.com/Desgue/codegraph/path"
+)
+
+type BoundariesCommand struct {
+	TargetDirectory *path.TargetDirectory
+	JSON            bool
+
+	output io.Writer
+}
+
+func NewBoundariesCommand(args []string) (*BoundariesCommand, error) {
+	flagSet := flag.NewFlagSet("boundaries", flag.ContinueOnError)
+
+	boundariesCommand := &BoundariesCommand{output: os.Stdout}
+
+	flagSet.BoolVar(&boundariesCommand.JSON, "json", false, "Print the suggestions as JSON")
+
+	if err := flagSet.Parse(args); err != nil {
+		return nil, newUsageError(err)
+	}
+
+	directoryArgument := ""
+	if flagSet.NArg() > 0 {
+		directoryArgument = flagSet.Arg(0)
+	}
+
+	targetDirectory, err := path.NewTargetDirectory(directoryArgument)
+	if err != nil {
+		return nil, err
+	}
+	boundariesCommand.TargetDirectory = targetDirectory
+
+	return boundariesCommand, nil
+}
+
+// Execute loads the module with its tests, so test files are type-checked
+// but, as in analyzer.SuggestBoundaries, never count as consumers.
+func (bc *BoundariesCommand) Execute() error {
+	pkgs, _, err := parser.Load(bc.TargetDirectory.Path, true)
+	if err != nil {
+		return err
+	}
+	dependencyGraph, err := extractGraph(context.Background(), pkgs)
+	if err != nil {
+		return err
+	}
+	suggestions := analyzer.SuggestBoundaries(dependencyGraph, pkgs)
+
+	if bc.JSON {
+		if suggestions == nil {
+			suggestions = []analyzer.BoundarySuggestion{}
+		}
+		encoder := json.NewEncoder(bc.output)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(suggestions)
+	}
+
+	if len(suggestions) == 0 {
+		fmt.Fprintf(bc.output, "No boundary suggestions\n")
+		return nil
+	}
+	fmt.Fprintf(bc.output, "boundary suggestions (highest score first):\n")
+	for _, suggestion := range suggestions {
+		switch suggestion.Kind {
+		case analyzer.SuggestNarrowInternal:
+			fmt.Fprintf(bc.output, "  %.2f  %s.%s: only used by %s outside its subtree\n",
+				suggestion.Score, suggestion.Package, suggestion.Symbol, suggestion.Consumer)
+		case analyzer.SuggestMoveToInternal:
+			fmt.Fprintf(bc.output, "  %.2f  %s: only imported by %s, could move under internal/\n",
+				suggestion.Score, suggestion.Package, suggestion.Consumer)
+		}
+	}
+	return nil
+}
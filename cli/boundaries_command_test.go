@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/Desgue/codegraph/analyzer"
+)
+
+func newBoundariesTestCommand(t *testing.T, args ...string) (*BoundariesCommand, *bytes.Buffer) {
+	t.Helper()
+
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":                 "module testbounds\n\ngo 1.24\n",
+		"main.go":                "package main\n\nimport \"testbounds/internal/auth\"\n\nfunc main() { auth.Check() }\n",
+		"internal/auth/auth.go":  "package auth\n\nfunc Check() {}\n",
+		"internal/auth/extra.go": "package auth\n\nfunc Unused() {}\n",
+	})
+	cmd, err := NewBoundariesCommand(append(args, testDir))
+	if err != nil {
+		t.Fatalf("NewBoundariesCommand() error = %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+	return cmd, &output
+}
+
+func TestBoundariesCommand_Execute(t *testing.T) {
+	cmd, output := newBoundariesTestCommand(t)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	want := "boundary suggestions (highest score first):\n" +
+		"  1.00  testbounds/internal/auth.Check: only used by testbounds outside its subtree\n"
+	if output.String() != want {
+		t.Errorf("output = %q, want %q", output.String(), want)
+	}
+}
+
+func TestBoundariesCommand_Execute_JSON(t *testing.T) {
+	cmd, output := newBoundariesTestCommand(t, "--json")
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	var suggestions []analyzer.BoundarySuggestion
+	if err := json.Unmarshal(output.Bytes(), &suggestions); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, output.String())
+	}
+	if len(suggestions) != 1 || suggestions[0].Kind != analyzer.SuggestNarrowInternal || suggestions[0].Symbol != "Check" {
+		t.Errorf("unexpected suggestions %+v", suggestions)
+	}
+}
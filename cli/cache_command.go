@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Desgue/codegraph/extract"
+)
+
+// Actions accepted by the cache command.
+const cacheActionClean = "clean"
+
+// CacheCommand manages the extraction cache parse reuses across runs.
+type CacheCommand struct {
+	Action string
+	// Dir is the cache directory, extract.DefaultCacheDir when empty.
+	Dir string
+
+	output io.Writer
+}
+
+func NewCacheCommand(args []string) (*CacheCommand, error) {
+	flagSet := flag.NewFlagSet("cache", flag.ContinueOnError)
+
+	cacheCommand := &CacheCommand{output: os.Stdout}
+	flagSet.StringVar(&cacheCommand.Dir, "dir", "", "Cache directory (default: $CODEGRAPH_CACHE_DIR, else codegraph/extract in the user cache directory)")
+
+	if err := flagSet.Parse(args); err != nil {
+		return nil, newUsageError(err)
+	}
+	if flagSet.NArg() != 1 {
+		return nil, usageErrorf("expected one action (available: %s)", cacheActionClean)
+	}
+	cacheCommand.Action = flagSet.Arg(0)
+
+	if err := cacheCommand.Validate(); err != nil {
+		return nil, err
+	}
+	return cacheCommand, nil
+}
+
+func (cc *CacheCommand) Validate() error {
+	if cc.Action != cacheActionClean {
+		return usageErrorf("unknown action '%s' (available: %s)", cc.Action, cacheActionClean)
+	}
+	return nil
+}
+
+// Execute removes every cache entry and reports how much space it freed.
+func (cc *CacheCommand) Execute() error {
+	dir := cc.Dir
+	if dir == "" {
+		var err error
+		if dir, err = extract.DefaultCacheDir(); err != nil {
+			return err
+		}
+	}
+	removed, size, err := (&extract.Cache{Dir: dir}).Clean()
+	if err != nil {
+		return fmt.Errorf("failed to clean cache %s: %w", dir, err)
+	}
+	fmt.Fprintf(cc.output, "Removed %d cache entries (%d bytes) from %s\n", removed, size, dir)
+	return nil
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestMain points the extraction cache at a temporary directory so parse
+// tests do not write to the user's cache.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "codegraph-cache")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Setenv("CODEGRAPH_CACHE_DIR", dir)
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func TestNewCacheCommand(t *testing.T) {
+	for _, args := range [][]string{{}, {"purge"}, {"clean", "extra"}} {
+		if _, err := NewCacheCommand(args); !errors.Is(err, ErrUsage) {
+			t.Errorf("NewCacheCommand(%q): expected ErrUsage, got %v", args, err)
+		}
+	}
+}
+
+func TestCacheCommand_Execute(t *testing.T) {
+	cacheDir := t.TempDir()
+	t.Setenv("CODEGRAPH_CACHE_DIR", cacheDir)
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":         "module testcache\n\ngo 1.24\n",
+		"api/api.go":     "package api\n\nimport \"testcache/store\"\n\nfunc Get() int { return store.Get() }\n",
+		"store/store.go": "package store\n\nfunc Get() int { return 1 }\n",
+	})
+	outputFile := filepath.Join(t.TempDir(), "out.json")
+	parse := func(extraArgs ...string) {
+		t.Helper()
+		args := append([]string{"--output", outputFile, "--hide-progress-bar"}, extraArgs...)
+		cmd, err := NewParseCommand(append(args, testDir))
+		if err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+	}
+
+	parse("--no-cache")
+	if entries, _ := filepath.Glob(filepath.Join(cacheDir, "*.json.gz")); len(entries) != 0 {
+		t.Fatalf("--no-cache wrote cache entries %v", entries)
+	}
+	parse()
+	entries, _ := filepath.Glob(filepath.Join(cacheDir, "*.json.gz"))
+	if len(entries) != 2 {
+		t.Fatalf("expected a cache entry per package, got %v", entries)
+	}
+	// Hashing every file finds the same keys.
+	parse("--paranoid-cache")
+	if reparsed, _ := filepath.Glob(filepath.Join(cacheDir, "*.json.gz")); len(reparsed) != 2 {
+		t.Fatalf("--paranoid-cache wrote new cache entries: %v", reparsed)
+	}
+	if _, err := NewParseCommand([]string{"--output", outputFile, "--no-cache", "--paranoid-cache", testDir}); !errors.Is(err, ErrUsage) {
+		t.Errorf("expected a usage error for --paranoid-cache with --no-cache, got %v", err)
+	}
+
+	var output bytes.Buffer
+	cmd, err := NewCacheCommand([]string{"clean"})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	cmd.output = &output
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if want := "Removed 2 cache entries ("; !strings.HasPrefix(output.String(), want) || !strings.Contains(output.String(), cacheDir) {
+		t.Errorf("output = %q, want it to start with %q and name %s", output.String(), want, cacheDir)
+	}
+	if entries, _ := filepath.Glob(filepath.Join(cacheDir, "*.json.gz")); len(entries) != 0 {
+		t.Errorf("clean left cache entries %v", entries)
+	}
+}
@@ -0,0 +1,5 @@
+package cli
+
+// MetadataConcurrency is the graph metadata key recording the --concurrency
+// a graph was extracted with.
+const MetadataConcurrency = "concurrency"
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Desgue/codegraph/analyzer"
+	"github.com/Desgue/codegraph/extract"
+	"github.com/Desgue/codegraph/graph"
+	"github.com/Desgue/codegraph/parser"
+	"github.com/Desgue/codegraph/path"
+	"golang.org/x/tools/cover"
+)
+
+type CoverageCommand struct {
+	TargetDirectory *path.TargetDirectory
+	ProfileFile     string
+	OutputFile      string
+	IncludeTests    bool
+
+	output io.Writer
+}
+
+func NewCoverageCommand(args []string) (*CoverageCommand, error) {
+	flagSet := flag.NewFlagSet("coverage", flag.ContinueOnError)
+
+	coverageCommand := &CoverageCommand{output: os.Stdout}
+
+	flagSet.StringVar(&coverageCommand.ProfileFile, "profile", "", "Coverage profile written by go test -coverprofile (required)")
+	flagSet.StringVar(&coverageCommand.OutputFile, "output", "",
+		"Output file path (required); the format is inferred from its extension")
+	flagSet.BoolVar(&coverageCommand.IncludeTests, "include-tests", true, "Include test files in parsing")
+
+	if err := flagSet.Parse(args); err != nil {
+		return nil, newUsageError(err)
+	}
+
+	directoryArgument := ""
+	if flagSet.NArg() > 0 {
+		directoryArgument = flagSet.Arg(0)
+	}
+
+	targetDirectory, err := path.NewTargetDirectory(directoryArgument)
+	if err != nil {
+		return nil, err
+	}
+	coverageCommand.TargetDirectory = targetDirectory
+
+	if err := coverageCommand.Validate(); err != nil {
+		return nil, err
+	}
+
+	return coverageCommand, nil
+}
+
+func (cc *CoverageCommand) Validate() error {
+	if cc.ProfileFile == "" {
+		return usageErrorf("--profile flag requires a coverage profile")
+	}
+	if cc.OutputFile == "" {
+		return usageErrorf("--output flag requires a file path")
+	}
+	return nil
+}
+
+// Execute extracts the graph with extract.CallExtractor's function nodes,
+// attaches the profile's statement coverage to its package and function nodes
+// and writes it out. Profile files that match no package are
+// listed, since their coverage is silently lost otherwise.
+func (cc *CoverageCommand) Execute() error {
+	profiles, err := cover.ParseProfiles(cc.ProfileFile)
+	if err != nil {
+		return fmt.Errorf("failed to read coverage profile '%s': %w", cc.ProfileFile, err)
+	}
+
+	pkgs, _, err := parser.Load(cc.TargetDirectory.Path, cc.IncludeTests)
+	if err != nil {
+		return err
+	}
+	dependencyGraph, err := extractGraphWith(context.Background(), pkgs, append(extract.Extractors(), &extract.CallExtractor{}))
+	if err != nil {
+		return err
+	}
+
+	unmatched := analyzer.ApplyCoverage(dependencyGraph, profiles)
+	if err := writeGraphFile(cc.OutputFile, dependencyGraph); err != nil {
+		return err
+	}
+
+	counts := make(map[graph.Kind]int)
+	notCovered := 0
+	for _, node := range dependencyGraph.Nodes() {
+		if _, measured := node.Attributes[analyzer.AttributeCoverage]; measured {
+			counts[node.Kind]++
+		}
+		if node.Attributes[analyzer.AttributeNotCovered] == "true" {
+			notCovered++
+		}
+	}
+	fmt.Fprintf(cc.output, "Wrote coverage for %d packages and %d functions to %s (%d not in the profile)\n",
+		counts[graph.KindPackage], counts[graph.KindFunction], cc.OutputFile, notCovered)
+	if len(unmatched) > 0 {
+		fmt.Fprintf(cc.output, "profile files not in the graph:\n")
+		for _, fileName := range unmatched {
+			fmt.Fprintf(cc.output, "  %s\n", fileName)
+		}
+	}
+	return nil
+}
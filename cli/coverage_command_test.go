@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/Desgue/codegraph/analyzer"
+	"github.com/Desgue/codegraph/graph"
+)
+
+func TestNewCoverageCommand(t *testing.T) {
+	errorTests := []struct {
+		name string
+		args []string
+	}{
+		{name: "missing profile", args: []string{"--output", "out.json"}},
+		{name: "missing output", args: []string{"--profile", "cover.out"}},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewCoverageCommand(append(tt.args, t.TempDir())); !errors.Is(err, ErrUsage) {
+				t.Fatalf("expected ErrUsage, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCoverageCommand_Execute(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":     "module testcover\n\ngo 1.24\n",
+		"a/a.go":     "package a\n\nimport _ \"testcover/b\"\n\nfunc A() int { return 1 }\n",
+		"b/b.go":     "package b\n\nfunc B() int { return 2 }\n",
+		"cover.out":  "mode: set\ntestcover/a/a.go:5.16,5.26 1 1\ntestcover/gone/gone.go:3.14,5.2 2 0\n",
+		"c/c_doc.go": "// Package c has no statements.\npackage c\n",
+	})
+	outputFile := filepath.Join(t.TempDir(), "cover.json")
+
+	cmd, err := NewCoverageCommand([]string{"--profile", filepath.Join(testDir, "cover.out"), "--output", outputFile, testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	want := "Wrote coverage for 3 packages and 2 functions to " + outputFile + " (3 not in the profile)\n" +
+		"profile files not in the graph:\n  testcover/gone/gone.go\n"
+	if output.String() != want {
+		t.Errorf("output = %q, want %q", output.String(), want)
+	}
+
+	written, err := readGraphFile(outputFile)
+	if err != nil {
+		t.Fatalf("readGraphFile() error = %v", err)
+	}
+	a, _ := written.Node("testcover/a")
+	b, _ := written.Node("testcover/b")
+	if a.Attributes[analyzer.AttributeCoverage] != "100.0" || b.Attributes[analyzer.AttributeNotCovered] != "true" {
+		t.Errorf("a attributes = %v, b attributes = %v", a.Attributes, b.Attributes)
+	}
+	functionA, _ := written.Node(graph.FuncID("testcover/a", "", "A"))
+	functionB, _ := written.Node(graph.FuncID("testcover/b", "", "B"))
+	if functionA.Attributes[analyzer.AttributeCoverage] != "100.0" || functionB.Attributes[analyzer.AttributeNotCovered] != "true" {
+		t.Errorf("A attributes = %v, B attributes = %v", functionA.Attributes, functionB.Attributes)
+	}
+}
+
+func TestCoverageCommand_Execute_MissingProfile(t *testing.T) {
+	cmd, err := NewCoverageCommand([]string{"--profile", filepath.Join(t.TempDir(), "missing.out"), "--output", "out.json", t.TempDir()})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected an error for a missing profile")
+	}
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+// Graph metadata keys written by summarizeCycles. Cycles are separated by
+// ";" and their package IDs by ",". A near-cycle "a,b" means a imports b and
+// b's tests import a.
+const (
+	MetadataImportCycles = "import_cycles"
+	MetadataNearCycles   = "near_cycles"
+)
+
+// summarizeCycles reports import cycles and near-cycles, printing counts and,
+// when verbose, the packages involved. The findings are also stored as graph
+// metadata so they travel with the exported graph. Near-cycles need test
+// imports: without test files they are reported as n/a.
+func summarizeCycles(writer io.Writer, g *graph.Graph, verbose, withTests bool) {
+	cycles := g.FindCycles(productionEdgeKinds(g))
+	nearCycles := g.NearCycles()
+
+	if withTests {
+		fmt.Fprintf(writer, "Import cycles: %d, near-cycles (test-only): %d\n", len(cycles), len(nearCycles))
+	} else {
+		fmt.Fprintf(writer, "Import cycles: %d, near-cycles (test-only): n/a\n", len(cycles))
+	}
+	if verbose {
+		for _, cycle := range cycles {
+			fmt.Fprintf(writer, "  cycle: %s\n", strings.Join(cycle, " -> "))
+		}
+		for _, pair := range nearCycles {
+			fmt.Fprintf(writer, "  near-cycle: %s imports %s, whose tests import it back\n", pair[0], pair[1])
+		}
+	}
+
+	if len(cycles) > 0 {
+		joined := make([]string, 0, len(cycles))
+		for _, cycle := range cycles {
+			joined = append(joined, strings.Join(cycle, ","))
+		}
+		g.SetMetadata(MetadataImportCycles, strings.Join(joined, ";"))
+	}
+	if len(nearCycles) > 0 {
+		joined := make([]string, 0, len(nearCycles))
+		for _, pair := range nearCycles {
+			joined = append(joined, pair[0]+","+pair[1])
+		}
+		g.SetMetadata(MetadataNearCycles, strings.Join(joined, ";"))
+	}
+}
+
+// productionEdgeKinds returns the edge kinds present in g except test-only
+// imports, so finer-grained edges added by extractors also count toward
+// logical cycles.
+func productionEdgeKinds(g *graph.Graph) []graph.EdgeKind {
+	seen := make(map[graph.EdgeKind]bool)
+	kinds := []graph.EdgeKind{graph.EdgeImport}
+	seen[graph.EdgeImport] = true
+	for _, edge := range g.Edges() {
+		if edge.Kind != graph.EdgeTestImport && !seen[edge.Kind] {
+			seen[edge.Kind] = true
+			kinds = append(kinds, edge.Kind)
+		}
+	}
+	return kinds
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+func TestSummarizeCycles(t *testing.T) {
+	newGraph := func(t *testing.T) *graph.Graph {
+		g := newCLITestGraph(t, []string{"ex/a", "ex/b", "ex/c", "ex/d"},
+			[][2]string{{"ex/a", "ex/b"}, {"ex/b", "ex/a"}, {"ex/c", "ex/d"}})
+		if err := g.AddEdge(&graph.Edge{From: "ex/d", To: "ex/c", Kind: graph.EdgeTestImport}); err != nil {
+			t.Fatalf("AddEdge() error = %v", err)
+		}
+		return g
+	}
+
+	t.Run("counts only by default", func(t *testing.T) {
+		g := newGraph(t)
+		var output bytes.Buffer
+		summarizeCycles(&output, g, false, true)
+
+		if output.String() != "Import cycles: 1, near-cycles (test-only): 1\n" {
+			t.Errorf("unexpected output: %q", output.String())
+		}
+		if g.Metadata[MetadataImportCycles] != "ex/a,ex/b" || g.Metadata[MetadataNearCycles] != "ex/c,ex/d" {
+			t.Errorf("unexpected metadata: %v", g.Metadata)
+		}
+	})
+
+	t.Run("verbose lists packages", func(t *testing.T) {
+		var output bytes.Buffer
+		summarizeCycles(&output, newGraph(t), true, true)
+
+		for _, want := range []string{"  cycle: ex/a -> ex/b\n", "  near-cycle: ex/c imports ex/d"} {
+			if !strings.Contains(output.String(), want) {
+				t.Errorf("expected %q in output, got %q", want, output.String())
+			}
+		}
+	})
+
+	t.Run("acyclic graph has no metadata", func(t *testing.T) {
+		g := newCLITestGraph(t, []string{"ex/a", "ex/b"}, [][2]string{{"ex/a", "ex/b"}})
+		summarizeCycles(&bytes.Buffer{}, g, true, true)
+		if len(g.Metadata) != 0 {
+			t.Errorf("expected no metadata, got %v", g.Metadata)
+		}
+	})
+
+	t.Run("near-cycles need test files", func(t *testing.T) {
+		var output bytes.Buffer
+		summarizeCycles(&output, newGraph(t), false, false)
+		if output.String() != "Import cycles: 1, near-cycles (test-only): n/a\n" {
+			t.Errorf("unexpected output: %q", output.String())
+		}
+	})
+}
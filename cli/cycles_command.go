@@ -0,0 +1,204 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Desgue/codegraph/analyzer"
+	"github.com/Desgue/codegraph/parser"
+	"github.com/Desgue/codegraph/path"
+)
+
+// Levels accepted by the cycles command's --level flag.
+const (
+	cycleLevelPackage = "package"
+	cycleLevelType    = "type"
+)
+
+type CyclesCommand struct {
+	TargetDirectory *path.TargetDirectory
+	Level           string
+	IncludeTests    bool
+	// IncludeTestEdges also reports the package cycles that only test files
+	// create, apart from the production cycles. Implies IncludeTests.
+	IncludeTestEdges bool
+	JSON             bool
+	// Verbose notes on stderr when test files are skipped because the
+	// selected cycles cannot depend on them.
+	Verbose bool
+
+	output    io.Writer
+	errOutput io.Writer
+}
+
+func NewCyclesCommand(args []string) (*CyclesCommand, error) {
+	flagSet := flag.NewFlagSet("cycles", flag.ContinueOnError)
+
+	cyclesCommand := &CyclesCommand{output: os.Stdout, errOutput: os.Stderr}
+
+	flagSet.StringVar(&cyclesCommand.Level, "level", cycleLevelPackage,
+		"Report import cycles between packages (package) or recursive types (type)")
+	flagSet.BoolVar(&cyclesCommand.IncludeTests, "include-tests", false, "Include test files in parsing")
+	flagSet.BoolVar(&cyclesCommand.IncludeTestEdges, "include-test-edges", false,
+		"Also report package cycles created only by imports in test files, naming those files (implies --include-tests)")
+	flagSet.BoolVar(&cyclesCommand.JSON, "json", false, "Print the cycles as JSON")
+	flagSet.BoolVar(&cyclesCommand.Verbose, "verbose", false, "Note on stderr when test files are not loaded because they cannot change the result")
+
+	if err := flagSet.Parse(args); err != nil {
+		return nil, newUsageError(err)
+	}
+	if cyclesCommand.IncludeTestEdges {
+		cyclesCommand.IncludeTests = true
+	}
+
+	directoryArgument := ""
+	if flagSet.NArg() > 0 {
+		directoryArgument = flagSet.Arg(0)
+	}
+
+	targetDirectory, err := path.NewTargetDirectory(directoryArgument)
+	if err != nil {
+		return nil, err
+	}
+	cyclesCommand.TargetDirectory = targetDirectory
+
+	if err := cyclesCommand.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cyclesCommand, nil
+}
+
+func (cc *CyclesCommand) Validate() error {
+	if cc.Level != cycleLevelPackage && cc.Level != cycleLevelType {
+		return usageErrorf("unknown level '%s' (available: %s, %s)", cc.Level, cycleLevelPackage, cycleLevelType)
+	}
+	if cc.IncludeTestEdges && cc.Level != cycleLevelPackage {
+		return usageErrorf("--include-test-edges requires --level %s", cycleLevelPackage)
+	}
+	return nil
+}
+
+// Execute reports the cycles at the selected level. Type cycles held by value
+// are compile errors, so packages are analyzed even when they fail to load.
+func (cc *CyclesCommand) Execute() error {
+	includeTests := loadTestFiles(cc.IncludeTests, testFileUses{
+		TestEdges:    cc.IncludeTestEdges,
+		Declarations: cc.Level == cycleLevelType,
+	})
+	if cc.IncludeTests && !includeTests && cc.Verbose {
+		fmt.Fprintln(cc.errOutput, "Skipping test files: package cycles follow production imports only (use --include-test-edges for test cycles)")
+	}
+	pkgs, _, err := parser.Load(cc.TargetDirectory.Path, includeTests)
+	if err != nil {
+		return err
+	}
+
+	if cc.Level == cycleLevelType {
+		cycles := analyzer.FindTypeCycles(pkgs)
+		if cc.JSON {
+			if cycles == nil {
+				cycles = []analyzer.TypeCycle{}
+			}
+			return cc.writeJSON(cycles)
+		}
+		printTypeCycles(cc.output, cycles)
+		return nil
+	}
+
+	dependencyGraph, err := extractGraph(context.Background(), pkgs)
+	if err != nil {
+		return err
+	}
+	cycles := dependencyGraph.FindCycles(productionEdgeKinds(dependencyGraph))
+	if cycles == nil {
+		cycles = [][]string{}
+	}
+	var testCycles []analyzer.TestCycle
+	if cc.IncludeTestEdges {
+		testCycles = analyzer.FindTestOnlyCycles(pkgs)
+	}
+	if cc.JSON {
+		if !cc.IncludeTestEdges {
+			return cc.writeJSON(cycles)
+		}
+		if testCycles == nil {
+			testCycles = []analyzer.TestCycle{}
+		}
+		return cc.writeJSON(struct {
+			Cycles     [][]string           `json:"cycles"`
+			TestCycles []analyzer.TestCycle `json:"test_cycles"`
+		}{cycles, testCycles})
+	}
+	fmt.Fprintf(cc.output, "Import cycles: %d\n", len(cycles))
+	for _, cycle := range cycles {
+		fmt.Fprintf(cc.output, "  cycle: %s\n", strings.Join(cycle, " -> "))
+	}
+	if cc.IncludeTestEdges {
+		cc.printTestCycles(testCycles)
+	}
+	return nil
+}
+
+// printTestCycles lists the cycles only test files create, each with the
+// test-only imports closing it and the files declaring them, relative to the
+// target directory.
+func (cc *CyclesCommand) printTestCycles(cycles []analyzer.TestCycle) {
+	fmt.Fprintf(cc.output, "Test-only import cycles: %d\n", len(cycles))
+	for _, cycle := range cycles {
+		fmt.Fprintf(cc.output, "  cycle: %s\n", strings.Join(cycle.Packages, ", "))
+		for _, backEdge := range cycle.BackEdges {
+			files := make([]string, len(backEdge.Files))
+			for i, file := range backEdge.Files {
+				files[i] = file
+				if relative, err := filepath.Rel(cc.TargetDirectory.Path, file); err == nil {
+					files[i] = filepath.ToSlash(relative)
+				}
+			}
+			origin := "external test package"
+			if !backEdge.External {
+				origin = "in-package tests, rejected by go test"
+			}
+			fmt.Fprintf(cc.output, "    %s -> %s via %s (%s)\n", backEdge.From, backEdge.To, strings.Join(files, ", "), origin)
+		}
+	}
+}
+
+func (cc *CyclesCommand) writeJSON(value any) error {
+	encoder := json.NewEncoder(cc.output)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// printTypeCycles lists each cycle with the fields closing it, value cycles
+// marked as compile errors and pointer-broken ones as legal recursion.
+func printTypeCycles(writer io.Writer, cycles []analyzer.TypeCycle) {
+	valueCycles := 0
+	for _, cycle := range cycles {
+		if !cycle.PointerBroken {
+			valueCycles++
+		}
+	}
+	fmt.Fprintf(writer, "Type cycles: %d, held by value (invalid recursive types): %d\n", len(cycles), valueCycles)
+
+	for _, cycle := range cycles {
+		kind := "pointer-broken"
+		if !cycle.PointerBroken {
+			kind = "value cycle"
+		}
+		fmt.Fprintf(writer, "  %s: %s\n", kind, strings.Join(cycle.Types, ", "))
+		for _, reference := range cycle.References {
+			via := "by value"
+			if reference.Indirect {
+				via = "indirect"
+			}
+			fmt.Fprintf(writer, "    %s.%s -> %s (%s)\n", reference.From, reference.Field, reference.To, via)
+		}
+	}
+}
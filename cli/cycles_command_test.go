@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestNewCyclesCommand(t *testing.T) {
+	if _, err := NewCyclesCommand([]string{"--level", "function", t.TempDir()}); !errors.Is(err, ErrUsage) {
+		t.Fatalf("expected ErrUsage, got %v", err)
+	}
+}
+
+func TestCyclesCommand_Execute(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":     "module testcycles\n\ngo 1.24\n",
+		"a/a.go":     "package a\n\nimport _ \"testcycles/b\"\n\ntype List struct{ Next *List }\n",
+		"b/b.go":     "package b\n\nimport _ \"testcycles/a\"\n",
+		"bad/bad.go": "package bad\n\ntype Loop struct{ Self [1]Loop }\n",
+	})
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "package level",
+			args: []string{},
+			want: "Import cycles: 1\n  cycle: testcycles/a -> testcycles/b\n",
+		},
+		{
+			name: "type level",
+			args: []string{"--level", "type"},
+			want: "Type cycles: 2, held by value (invalid recursive types): 1\n" +
+				"  pointer-broken: testcycles/a.List\n" +
+				"    testcycles/a.List.Next -> testcycles/a.List (indirect)\n" +
+				"  value cycle: testcycles/bad.Loop\n" +
+				"    testcycles/bad.Loop.Self -> testcycles/bad.Loop (by value)\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := NewCyclesCommand(append(tt.args, testDir))
+			if err != nil {
+				t.Fatalf("setup failed: %v", err)
+			}
+			var output bytes.Buffer
+			cmd.output = &output
+
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if output.String() != tt.want {
+				t.Errorf("output = %q, want %q", output.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestCyclesCommand_Execute_IncludeTestEdges(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":      "module testcycles\n\ngo 1.24\n",
+		"a/a.go":      "package a\n\nimport _ \"testcycles/b\"\n",
+		"b/b.go":      "package b\n",
+		"b/b_test.go": "package b_test\n\nimport _ \"testcycles/a\"\n",
+	})
+
+	cmd, err := NewCyclesCommand([]string{"--include-test-edges", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	want := "Import cycles: 0\n" +
+		"Test-only import cycles: 1\n" +
+		"  cycle: testcycles/a, testcycles/b\n" +
+		"    testcycles/b -> testcycles/a via b/b_test.go (external test package)\n"
+	if output.String() != want {
+		t.Errorf("output = %q, want %q", output.String(), want)
+	}
+
+	if _, err := NewCyclesCommand([]string{"--include-test-edges", "--level", "type", testDir}); !errors.Is(err, ErrUsage) {
+		t.Errorf("expected ErrUsage for --include-test-edges at type level, got %v", err)
+	}
+}
+
+func TestCyclesCommand_Execute_SkipsUnusedTestFiles(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":      "module testcycles\n\ngo 1.24\n",
+		"a/a.go":      "package a\n\nimport _ \"testcycles/b\"\n",
+		"b/b.go":      "package b\n\nimport _ \"testcycles/a\"\n",
+		"b/b_test.go": "package b\n\ntype Loop struct{ Self [1]Loop }\n",
+	})
+	const note = "Skipping test files: package cycles follow production imports only (use --include-test-edges for test cycles)\n"
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantNote string
+	}{
+		{name: "verbose", args: []string{"--include-tests", "--verbose"}, wantNote: note},
+		{name: "quiet", args: []string{"--include-tests"}},
+		{name: "tests not requested", args: []string{"--verbose"}},
+		{name: "type level needs test files", args: []string{"--include-tests", "--verbose", "--level", "type"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := NewCyclesCommand(append(tt.args, testDir))
+			if err != nil {
+				t.Fatalf("setup failed: %v", err)
+			}
+			var output, errOutput bytes.Buffer
+			cmd.output = &output
+			cmd.errOutput = &errOutput
+
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if errOutput.String() != tt.wantNote {
+				t.Errorf("stderr = %q, want %q", errOutput.String(), tt.wantNote)
+			}
+			if cmd.Level == cycleLevelType {
+				// The test file's value cycle is only found when it is loaded.
+				if !bytes.Contains(output.Bytes(), []byte("value cycle: testcycles/b.Loop")) {
+					t.Errorf("output = %q, want the test file's type cycle", output.String())
+				}
+			} else if want := "Import cycles: 1\n  cycle: testcycles/a -> testcycles/b\n"; output.String() != want {
+				t.Errorf("output = %q, want %q", output.String(), want)
+			}
+		})
+	}
+}
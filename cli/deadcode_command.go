@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Desgue/codegraph/analyzer"
+	"github.com/Desgue/codegraph/parser"
+	"github.com/Desgue/codegraph/path"
+)
+
+type DeadCodeCommand struct {
+	TargetDirectory *path.TargetDirectory
+	IncludeTests    bool
+	Exclude         []string
+	JSON            bool
+
+	output io.Writer
+}
+
+// deadCodeReport is the --json output of the deadcode command.
+type deadCodeReport struct {
+	ConfidenceNote string                `json:"confidence_note"`
+	Findings       []analyzer.DeadExport `json:"findings"`
+}
+
+func NewDeadCodeCommand(args []string) (*DeadCodeCommand, error) {
+	flagSet := flag.NewFlagSet("deadcode", flag.ContinueOnError)
+
+	deadCodeCommand := &DeadCodeCommand{output: os.Stdout}
+	exclude := ""
+
+	flagSet.BoolVar(&deadCodeCommand.IncludeTests, "include-tests", true,
+		"Count references from test files, so identifiers only tests use are not reported")
+	flagSet.StringVar(&exclude, "exclude", "",
+		"Comma-separated package patterns whose exports are never reported (pkg or pkg/...)")
+	flagSet.BoolVar(&deadCodeCommand.JSON, "json", false, "Print the findings as JSON")
+
+	if err := flagSet.Parse(args); err != nil {
+		return nil, newUsageError(err)
+	}
+	if exclude != "" {
+		deadCodeCommand.Exclude = strings.Split(exclude, ",")
+	}
+
+	directoryArgument := ""
+	if flagSet.NArg() > 0 {
+		directoryArgument = flagSet.Arg(0)
+	}
+
+	targetDirectory, err := path.NewTargetDirectory(directoryArgument)
+	if err != nil {
+		return nil, err
+	}
+	deadCodeCommand.TargetDirectory = targetDirectory
+
+	return deadCodeCommand, nil
+}
+
+func (dc *DeadCodeCommand) Execute() error {
+	pkgs, _, err := parser.Load(dc.TargetDirectory.Path, dc.IncludeTests)
+	if err != nil {
+		return err
+	}
+	findings := analyzer.FindDeadExports(pkgs, dc.Exclude)
+
+	if dc.JSON {
+		if findings == nil {
+			findings = []analyzer.DeadExport{}
+		}
+		encoder := json.NewEncoder(dc.output)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(deadCodeReport{ConfidenceNote: analyzer.DeadCodeConfidenceNote, Findings: findings})
+	}
+	dc.printFindings(findings)
+	return nil
+}
+
+// printFindings lists findings as file:line, relative to the target directory
+// when possible, followed by the confidence note.
+func (dc *DeadCodeCommand) printFindings(findings []analyzer.DeadExport) {
+	if len(findings) == 0 {
+		fmt.Fprintf(dc.output, "No dead exported code found\n")
+	}
+	for _, finding := range findings {
+		file := finding.File
+		if relative, err := filepath.Rel(dc.TargetDirectory.Path, file); err == nil && !strings.HasPrefix(relative, "..") {
+			file = relative
+		}
+		fmt.Fprintf(dc.output, "%s:%d: %s %s.%s is not referenced outside its package\n",
+			file, finding.Line, finding.Kind, finding.Package, finding.Name)
+	}
+	fmt.Fprintf(dc.output, "\nNote: %s\n", analyzer.DeadCodeConfidenceNote)
+}
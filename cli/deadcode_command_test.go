@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Desgue/codegraph/analyzer"
+)
+
+func TestNewDeadCodeCommand(t *testing.T) {
+	cmd, err := NewDeadCodeCommand([]string{"--exclude", "example.com/mod/api/...,example.com/mod/gen", "--json", t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewDeadCodeCommand() error = %v", err)
+	}
+	if want := []string{"example.com/mod/api/...", "example.com/mod/gen"}; !reflect.DeepEqual(cmd.Exclude, want) {
+		t.Errorf("Exclude = %v, want %v", cmd.Exclude, want)
+	}
+	if !cmd.JSON || !cmd.IncludeTests {
+		t.Errorf("JSON = %v, IncludeTests = %v, want both true", cmd.JSON, cmd.IncludeTests)
+	}
+}
+
+func newDeadCodeTestCommand(t *testing.T, args ...string) (*DeadCodeCommand, *bytes.Buffer) {
+	t.Helper()
+
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":         "module deadcli\n\ngo 1.24\n",
+		"main.go":        "package main\n\nimport \"deadcli/store\"\n\nfunc main() { store.Open() }\n",
+		"store/store.go": "package store\n\nfunc Open() {}\n\nfunc Close() {}\n",
+	})
+	cmd, err := NewDeadCodeCommand(append(args, testDir))
+	if err != nil {
+		t.Fatalf("NewDeadCodeCommand() error = %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+	return cmd, &output
+}
+
+func TestDeadCodeCommand_Execute(t *testing.T) {
+	cmd, output := newDeadCodeTestCommand(t)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	want := "store/store.go:5: func deadcli/store.Close is not referenced outside its package\n"
+	if !strings.HasPrefix(output.String(), want) {
+		t.Errorf("output = %q, want prefix %q", output.String(), want)
+	}
+	if !strings.Contains(output.String(), "Note: "+analyzer.DeadCodeConfidenceNote) {
+		t.Errorf("output = %q, want the confidence note", output.String())
+	}
+}
+
+func TestDeadCodeCommand_ExecuteJSON(t *testing.T) {
+	cmd, output := newDeadCodeTestCommand(t, "--json", "--exclude", "deadcli/store")
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	var report deadCodeReport
+	if err := json.Unmarshal(output.Bytes(), &report); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, output.String())
+	}
+	if report.ConfidenceNote == "" || report.Findings == nil || len(report.Findings) != 0 {
+		t.Errorf("report = %+v, want a note and no findings", report)
+	}
+}
@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+type DiffCommand struct {
+	OldFile          string
+	NewFile          string
+	JSON             bool
+	IgnoreAttributes []string
+
+	output io.Writer
+}
+
+func NewDiffCommand(args []string) (*DiffCommand, error) {
+	flagSet := flag.NewFlagSet("diff", flag.ContinueOnError)
+
+	jsonOutput := flagSet.Bool("json", false, "Print the diff as JSON")
+	ignore := flagSet.String("ignore", "", "Comma-separated attributes to ignore when detecting modifications")
+
+	if err := flagSet.Parse(args); err != nil {
+		return nil, newUsageError(err)
+	}
+
+	diffCommand := &DiffCommand{
+		OldFile: flagSet.Arg(0),
+		NewFile: flagSet.Arg(1),
+		JSON:    *jsonOutput,
+		output:  os.Stdout,
+	}
+	if *ignore != "" {
+		diffCommand.IgnoreAttributes = strings.Split(*ignore, ",")
+	}
+
+	if flagSet.NArg() != 2 {
+		return nil, usageErrorf("diff requires exactly two graph files: codegraph diff [options] <old> <new>")
+	}
+
+	return diffCommand, nil
+}
+
+func (dc *DiffCommand) Execute() error {
+	oldGraph, err := readGraphFile(dc.OldFile)
+	if err != nil {
+		return err
+	}
+	newGraph, err := readGraphFile(dc.NewFile)
+	if err != nil {
+		return err
+	}
+
+	result := graph.DiffWithOptions(oldGraph, newGraph, graph.DiffOptions{IgnoreAttributes: dc.IgnoreAttributes})
+	breakages := graph.DetectAPIBreakages(oldGraph, newGraph)
+
+	if dc.JSON {
+		encoder := json.NewEncoder(dc.output)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(struct {
+			*graph.DiffResult
+			APIBreakages []graph.APIBreakage `json:"api_breakages,omitempty"`
+		}{result, breakages})
+	}
+	printDiff(dc.output, result)
+	printAPIBreakages(dc.output, breakages)
+	return nil
+}
+
+// printAPIBreakages lists the packages whose exported API shrank, a heuristic
+// hint at breaking changes (see graph.DetectAPIBreakages).
+func printAPIBreakages(writer io.Writer, breakages []graph.APIBreakage) {
+	for _, breakage := range breakages {
+		fmt.Fprintf(writer, "! possibly breaking %s: %s %d -> %d\n",
+			breakage.Package, breakage.Kind, breakage.BeforeCount, breakage.AfterCount)
+	}
+}
+
+func printDiff(writer io.Writer, result *graph.DiffResult) {
+	if !result.HasChanges() {
+		fmt.Fprintf(writer, "No differences\n")
+		return
+	}
+
+	for _, id := range result.AddedNodes {
+		fmt.Fprintf(writer, "+ node %s\n", id)
+	}
+	for _, id := range result.RemovedNodes {
+		fmt.Fprintf(writer, "- node %s\n", id)
+	}
+	for _, change := range result.ModifiedNodes {
+		fmt.Fprintf(writer, "~ node %s\n", change.ID)
+		printAttributeChanges(writer, change.Changes)
+	}
+	for _, key := range result.AddedEdges {
+		fmt.Fprintf(writer, "+ edge %s -> %s (%s)\n", key.From, key.To, key.Kind)
+	}
+	for _, key := range result.RemovedEdges {
+		fmt.Fprintf(writer, "- edge %s -> %s (%s)\n", key.From, key.To, key.Kind)
+	}
+	for _, change := range result.ModifiedEdges {
+		fmt.Fprintf(writer, "~ edge %s -> %s (%s)\n", change.From, change.To, change.Kind)
+		printAttributeChanges(writer, change.Changes)
+	}
+}
+
+func printAttributeChanges(writer io.Writer, changes []graph.AttributeChange) {
+	for _, change := range changes {
+		fmt.Fprintf(writer, "    %s: %q -> %q\n", change.Attribute, change.Old, change.New)
+	}
+}
@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Desgue/codegraph/formatter"
+	"github.com/Desgue/codegraph/graph"
+)
+
+func writeGraphMLFile(t *testing.T, g *graph.Graph) string {
+	t.Helper()
+
+	filePath := filepath.Join(t.TempDir(), "graph.graphml")
+	graphFile, err := os.Create(filePath)
+	if err != nil {
+		t.Fatalf("Failed to create graph file: %v", err)
+	}
+	defer graphFile.Close()
+
+	if err := (&formatter.GraphMLFormatter{}).Encode(graphFile, g); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	return filePath
+}
+
+func newCLITestGraph(t *testing.T, ids []string, edges [][2]string) *graph.Graph {
+	t.Helper()
+
+	g := graph.New()
+	for _, id := range ids {
+		if err := g.AddNode(&graph.Node{ID: id, Kind: graph.KindPackage, Name: filepath.Base(id)}); err != nil {
+			t.Fatalf("AddNode() error = %v", err)
+		}
+	}
+	for _, edge := range edges {
+		if err := g.AddEdge(&graph.Edge{From: edge[0], To: edge[1], Kind: graph.EdgeImport}); err != nil {
+			t.Fatalf("AddEdge() error = %v", err)
+		}
+	}
+	return g
+}
+
+func TestNewDiffCommand(t *testing.T) {
+	cmd, err := NewDiffCommand([]string{"--json", "--ignore", "fileCount,name", "old.graphml", "new.graphml"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cmd.OldFile != "old.graphml" || cmd.NewFile != "new.graphml" || !cmd.JSON {
+		t.Errorf("unexpected command: %+v", cmd)
+	}
+	if len(cmd.IgnoreAttributes) != 2 {
+		t.Errorf("IgnoreAttributes = %v, want 2 entries", cmd.IgnoreAttributes)
+	}
+
+	for _, args := range [][]string{{}, {"only-one.graphml"}, {"a", "b", "c"}} {
+		if _, err := NewDiffCommand(args); !errors.Is(err, ErrUsage) {
+			t.Errorf("NewDiffCommand(%v) expected ErrUsage, got %v", args, err)
+		}
+	}
+}
+
+func TestDiffCommand_Execute(t *testing.T) {
+	oldFile := writeGraphMLFile(t, newCLITestGraph(t, []string{"ex/a", "ex/b"}, [][2]string{{"ex/a", "ex/b"}}))
+	newFile := writeGraphMLFile(t, newCLITestGraph(t, []string{"ex/a", "ex/c"}, [][2]string{{"ex/a", "ex/c"}}))
+
+	t.Run("text output", func(t *testing.T) {
+		cmd, err := NewDiffCommand([]string{oldFile, newFile})
+		if err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+		var output bytes.Buffer
+		cmd.output = &output
+
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+
+		for _, want := range []string{"+ node ex/c", "- node ex/b", "+ edge ex/a -> ex/c (import)", "- edge ex/a -> ex/b (import)"} {
+			if !strings.Contains(output.String(), want) {
+				t.Errorf("expected output to contain %q, got:\n%s", want, output.String())
+			}
+		}
+	})
+
+	t.Run("json output", func(t *testing.T) {
+		cmd, err := NewDiffCommand([]string{"--json", oldFile, newFile})
+		if err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+		var output bytes.Buffer
+		cmd.output = &output
+
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+
+		var result graph.DiffResult
+		if err := json.Unmarshal(output.Bytes(), &result); err != nil {
+			t.Fatalf("invalid JSON output: %v", err)
+		}
+		if len(result.AddedNodes) != 1 || result.AddedNodes[0] != "ex/c" {
+			t.Errorf("AddedNodes = %v", result.AddedNodes)
+		}
+	})
+
+	t.Run("api breakages", func(t *testing.T) {
+		oldGraph := newCLITestGraph(t, []string{"ex/a"}, nil)
+		oldNode, _ := oldGraph.Node("ex/a")
+		oldNode.ExportedFuncCount, oldNode.ExportedTypeCount = 4, 1
+		newGraph := newCLITestGraph(t, []string{"ex/a"}, nil)
+		newNode, _ := newGraph.Node("ex/a")
+		newNode.ExportedFuncCount, newNode.ExportedTypeCount = 3, 1
+
+		cmd, err := NewDiffCommand([]string{"--json", writeGraphMLFile(t, oldGraph), writeGraphMLFile(t, newGraph)})
+		if err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+		var output bytes.Buffer
+		cmd.output = &output
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+
+		var result struct {
+			APIBreakages []graph.APIBreakage `json:"api_breakages"`
+		}
+		if err := json.Unmarshal(output.Bytes(), &result); err != nil {
+			t.Fatalf("invalid JSON output: %v", err)
+		}
+		want := graph.APIBreakage{Package: "ex/a", BeforeCount: 4, AfterCount: 3, Kind: graph.APIBreakageExportedFuncs}
+		if len(result.APIBreakages) != 1 || result.APIBreakages[0] != want {
+			t.Errorf("APIBreakages = %+v, want [%+v]", result.APIBreakages, want)
+		}
+
+		cmd.JSON = false
+		output.Reset()
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if !strings.Contains(output.String(), "! possibly breaking ex/a: exported_funcs 4 -> 3") {
+			t.Errorf("expected the breakage in text output, got:\n%s", output.String())
+		}
+	})
+
+	t.Run("unreadable format", func(t *testing.T) {
+		cmd, err := NewDiffCommand([]string{oldFile, "graph.dot"})
+		if err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+		if err := cmd.Execute(); err == nil {
+			t.Error("expected error for write-only format")
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ExitCodeCycles is the exit code of lint when import cycles are found.
+// Every other failure, usage errors included, exits with 1.
+const ExitCodeCycles = 2
+
+// ErrUsage marks errors caused by invalid flags or arguments; test with errors.Is.
+var ErrUsage = errors.New("invalid usage")
+
+// usageError wraps an error so it matches ErrUsage while keeping its own message.
+type usageError struct {
+	err error
+}
+
+func (e *usageError) Error() string {
+	return e.err.Error()
+}
+
+func (e *usageError) Unwrap() []error {
+	return []error{ErrUsage, e.err}
+}
+
+func newUsageError(err error) error {
+	return &usageError{err: err}
+}
+
+func usageErrorf(format string, args ...any) error {
+	return newUsageError(fmt.Errorf(format, args...))
+}
+
+// ExitError is a command failure that requests a specific process exit code.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode maps an error returned by a command to the process exit code.
+func ExitCode(err error) int {
+	var exitError *ExitError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &exitError):
+		return exitError.Code
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Desgue/codegraph/analyzer"
+	"github.com/Desgue/codegraph/parser"
+	"github.com/Desgue/codegraph/path"
+)
+
+type ErrorsCommand struct {
+	TargetDirectory *path.TargetDirectory
+	IncludeTests    bool
+	JSON            bool
+
+	output io.Writer
+}
+
+// errorsReport is the --json output: the limits note first, as in text output.
+type errorsReport struct {
+	Note string `json:"note"`
+	analyzer.ErrorFlow
+}
+
+func NewErrorsCommand(args []string) (*ErrorsCommand, error) {
+	flagSet := flag.NewFlagSet("errors", flag.ContinueOnError)
+
+	errorsCommand := &ErrorsCommand{output: os.Stdout}
+
+	flagSet.BoolVar(&errorsCommand.IncludeTests, "include-tests", false, "Include test files, counting the calls made from tests")
+	flagSet.BoolVar(&errorsCommand.JSON, "json", false, "Print the result as JSON")
+
+	if err := flagSet.Parse(args); err != nil {
+		return nil, newUsageError(err)
+	}
+
+	directoryArgument := ""
+	if flagSet.NArg() > 0 {
+		directoryArgument = flagSet.Arg(0)
+	}
+
+	targetDirectory, err := path.NewTargetDirectory(directoryArgument)
+	if err != nil {
+		return nil, err
+	}
+	errorsCommand.TargetDirectory = targetDirectory
+
+	return errorsCommand, nil
+}
+
+func (ec *ErrorsCommand) Execute() error {
+	pkgs, _, err := parser.Load(ec.TargetDirectory.Path, ec.IncludeTests)
+	if err != nil {
+		return err
+	}
+	flow := analyzer.AnalyzeErrorFlow(pkgs)
+
+	if ec.JSON {
+		encoder := json.NewEncoder(ec.output)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(errorsReport{Note: analyzer.ErrorFlowNote, ErrorFlow: flow})
+	}
+	ec.printErrorFlow(flow)
+	return nil
+}
+
+// printErrorFlow writes the limits note, how the callers of every
+// error-returning function consume its error and then each dropped error.
+func (ec *ErrorsCommand) printErrorFlow(flow analyzer.ErrorFlow) {
+	fmt.Fprintf(ec.output, "Note: %s\n\n", analyzer.ErrorFlowNote)
+
+	fmt.Fprintf(ec.output, "Error-returning functions: %d\n", len(flow.Functions))
+	for _, function := range flow.Functions {
+		fmt.Fprintf(ec.output, "  %s: %d propagated, %d handled, %d dropped\n",
+			function.Function, function.Propagated, function.Handled, function.Dropped)
+	}
+	fmt.Fprintf(ec.output, "Dropped errors: %d\n", len(flow.Dropped))
+	for _, site := range flow.Dropped {
+		fmt.Fprintf(ec.output, "  %s:%d: %s (in %s)\n", site.File, site.Line, site.Callee, site.Caller)
+	}
+}
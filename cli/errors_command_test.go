@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/Desgue/codegraph/analyzer"
+)
+
+func TestErrorsCommand_Execute(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":         "module testerrs\n\ngo 1.24\n",
+		"store/store.go": "package store\n\nfunc Save() error { return nil }\n",
+		"api/api.go": "package api\n\nimport \"testerrs/store\"\n\nfunc Handle() error {\n\tif err := store.Save(); err != nil {\n\t\treturn err\n\t}\n" +
+			"\tstore.Save()\n\treturn nil\n}\n",
+	})
+
+	cmd, err := NewErrorsCommand([]string{testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	apiFile, err := filepath.EvalSymlinks(filepath.Join(testDir, "api", "api.go"))
+	if err != nil {
+		t.Fatalf("EvalSymlinks() error = %v", err)
+	}
+	want := "Note: " + analyzer.ErrorFlowNote + "\n\n" +
+		"Error-returning functions: 1\n  testerrs/store.Save: 1 propagated, 0 handled, 1 dropped\n" +
+		"Dropped errors: 1\n  " + apiFile + ":9: testerrs/store.Save (in testerrs/api.Handle)\n"
+	if output.String() != want {
+		t.Errorf("output = %q, want %q", output.String(), want)
+	}
+}
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestUsageError_KeepsMessageAndCause(t *testing.T) {
+	cause := errors.New("flag provided but not defined: -x")
+	err := newUsageError(cause)
+
+	if err.Error() != cause.Error() {
+		t.Errorf("Error() = %q, want %q", err.Error(), cause.Error())
+	}
+	if !errors.Is(err, ErrUsage) || !errors.Is(err, cause) {
+		t.Errorf("expected error to match both ErrUsage and its cause")
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "success", err: nil, want: 0},
+		{name: "generic failure", err: errors.New("boom"), want: 1},
+		{name: "usage error", err: usageErrorf("missing --output"), want: 1},
+		{name: "wrapped usage error", err: fmt.Errorf("parse: %w", usageErrorf("bad flag")), want: 1},
+		{name: "explicit exit code", err: &ExitError{Code: ExitCodeCycles, Err: errors.New("cycles")}, want: ExitCodeCycles},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Desgue/codegraph/extract"
+	"github.com/Desgue/codegraph/graph"
+	"golang.org/x/tools/go/packages"
+)
+
+// warningOutput receives non-fatal extraction warnings.
+var warningOutput io.Writer = os.Stderr
+
+// extractGraph builds the graph with every registered extractor. Extractor
+// failures are reported as warnings so one broken extractor does not fail the command.
+func extractGraph(ctx context.Context, pkgs []*packages.Package) (*graph.Graph, error) {
+	return extractGraphWith(ctx, pkgs, extract.Extractors())
+}
+
+// extractGraphWith is extractGraph with an explicit extractor list, for
+// commands adding opt-in extractors such as extract.SymbolExtractor.
+func extractGraphWith(ctx context.Context, pkgs []*packages.Package, extractors []extract.Extractor) (*graph.Graph, error) {
+	extractedGraph, failures, err := buildGraph(ctx, pkgs, extractors)
+	if err != nil {
+		return nil, err
+	}
+	warnFailures(failures)
+	return extractedGraph, nil
+}
+
+// buildGraph runs extract.Build, leaving the failures for the caller to
+// report once nothing else is drawing on stderr, such as a progress bar.
+func buildGraph(ctx context.Context, pkgs []*packages.Package, extractors []extract.Extractor) (*graph.Graph, []extract.Failure, error) {
+	extractedGraph, failures, err := extract.Build(ctx, pkgs, extractors)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to extract graph: %w", err)
+	}
+	return extractedGraph, failures, nil
+}
+
+// warnFailures reports extractor failures as warnings.
+func warnFailures(failures []extract.Failure) {
+	for _, failure := range failures {
+		fmt.Fprintf(warningOutput, "Warning: %v\n", failure)
+	}
+}
+
+// withExtractionCache attaches the extraction cache in extract.DefaultCacheDir
+// to ctx, hashing every file for its keys when paranoid. A cache that cannot be opened is reported as a warning and
+// extraction runs without it.
+func withExtractionCache(ctx context.Context, paranoid bool) context.Context {
+	dir, err := extract.DefaultCacheDir()
+	if err != nil {
+		fmt.Fprintf(warningOutput, "Warning: extraction cache disabled: %v\n", err)
+		return ctx
+	}
+	cache, err := extract.OpenCache(dir)
+	if err != nil {
+		fmt.Fprintf(warningOutput, "Warning: extraction cache disabled: %v\n", err)
+		return ctx
+	}
+	cache.Paranoid = paranoid
+	return extract.WithCache(ctx, cache)
+}
+
+// withPackageExtractor returns extractors with the built-in package extractor
+// replaced by packageExtractor, to configure what package nodes record.
+func withPackageExtractor(extractors []extract.Extractor, packageExtractor *extract.PackageExtractor) []extract.Extractor {
+	replaced := make([]extract.Extractor, len(extractors))
+	for i, extractor := range extractors {
+		replaced[i] = extractor
+		if extractor.Name() == packageExtractor.Name() {
+			replaced[i] = packageExtractor
+		}
+	}
+	return replaced
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Desgue/codegraph/extract"
+	"github.com/Desgue/codegraph/graph"
+	"github.com/Desgue/codegraph/parser"
+	"golang.org/x/tools/go/packages"
+)
+
+// brokenExtractor fails on the packages of TestExtractGraph_ReportsFailures only,
+// so registering it globally does not affect other tests.
+type brokenExtractor struct{}
+
+func (brokenExtractor) Name() string {
+	return "broken-test"
+}
+
+func (brokenExtractor) Extract(ctx context.Context, pkg *packages.Package, emitter graph.Emitter) error {
+	if strings.HasPrefix(pkg.PkgPath, "testwarn") {
+		return errors.New("cannot parse router")
+	}
+	return nil
+}
+
+func TestExtractGraph_ReportsFailures(t *testing.T) {
+	if err := extract.RegisterExtractor(brokenExtractor{}); err != nil {
+		t.Fatalf("RegisterExtractor() error = %v", err)
+	}
+	var warnings bytes.Buffer
+	warningOutput = &warnings
+	t.Cleanup(func() { warningOutput = os.Stderr })
+
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":     "module testwarn\n\ngo 1.24\n",
+		"api/api.go": "package api\n",
+	})
+	pkgs, _, err := parser.Load(testDir, false)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	extractedGraph, err := extractGraph(context.Background(), pkgs)
+	if err != nil {
+		t.Fatalf("extractGraph() error = %v", err)
+	}
+	if _, found := extractedGraph.Node("testwarn/api"); !found {
+		t.Error("expected built-in extraction to succeed despite the broken extractor")
+	}
+	want := "Warning: extractor broken-test failed on testwarn/api: cannot parse router\n"
+	if warnings.String() != want {
+		t.Errorf("warnings = %q, want %q", warnings.String(), want)
+	}
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"fmt"
+	"go/version"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+// normalizeGoVersion accepts a Go version with or without its "go" prefix,
+// e.g. "go1.21" or "1.21.3", and returns it prefixed, or "" if it is invalid.
+func normalizeGoVersion(value string) string {
+	if !strings.HasPrefix(value, "go") {
+		value = "go" + value
+	}
+	if !version.IsValid(value) {
+		return ""
+	}
+	return value
+}
+
+// checkGoVersions warns about every node, sorted by ID, whose GoVersion is
+// newer than limit, a normalized Go version, and returns how many it found.
+// A language version limit such as go1.21 accepts every 1.21.x release.
+func checkGoVersions(writer io.Writer, g *graph.Graph, limit string) int {
+	nodes := slices.Clone(g.Nodes())
+	slices.SortFunc(nodes, func(a, b *graph.Node) int { return strings.Compare(a.ID, b.ID) })
+
+	count := 0
+	for _, node := range nodes {
+		if node.GoVersion == "" {
+			continue
+		}
+		required := "go" + node.GoVersion
+		if limit == version.Lang(limit) {
+			required = version.Lang(required)
+		}
+		if version.Compare(required, limit) > 0 {
+			fmt.Fprintf(writer, "warning: %s requires go %s, newer than %s (%s)\n",
+				node.Label(), node.GoVersion, limit, node.ModulePath)
+			count++
+		}
+	}
+	return count
+}
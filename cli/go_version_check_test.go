@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+func TestNormalizeGoVersion(t *testing.T) {
+	for value, want := range map[string]string{
+		"go1.21": "go1.21", "1.22.3": "go1.22.3", "go1.23rc1": "go1.23rc1", "1.x": "", "": "",
+	} {
+		if got := normalizeGoVersion(value); got != want {
+			t.Errorf("normalizeGoVersion(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestCheckGoVersions(t *testing.T) {
+	g := graph.New()
+	for _, node := range []*graph.Node{
+		{ID: "ex/new", Kind: graph.KindPackage, GoVersion: "1.22", ModulePath: "ex/new"},
+		{ID: "ex/main", Kind: graph.KindPackage, GoVersion: "1.21", ModulePath: "ex"},
+		{ID: "ex/patch", Kind: graph.KindPackage, GoVersion: "1.21.5", ModulePath: "ex/patch"},
+		{ID: "ex/unknown", Kind: graph.KindPackage},
+	} {
+		if err := g.AddNode(node); err != nil {
+			t.Fatalf("AddNode() error = %v", err)
+		}
+	}
+
+	var output bytes.Buffer
+	if count := checkGoVersions(&output, g, "go1.21"); count != 1 {
+		t.Errorf("checkGoVersions(go1.21) = %d, want 1", count)
+	}
+	if want := "warning: ex/new requires go 1.22, newer than go1.21 (ex/new)\n"; output.String() != want {
+		t.Errorf("output = %q, want %q", output.String(), want)
+	}
+
+	// A release limit compares patch versions too.
+	output.Reset()
+	if count := checkGoVersions(&output, g, "go1.21.2"); count != 2 {
+		t.Errorf("checkGoVersions(go1.21.2) = %d, want 2:\n%s", count, output.String())
+	}
+}
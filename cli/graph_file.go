@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+// readGraphFile decodes a previously exported graph, selecting the decoder by file extension.
+func readGraphFile(filePath string) (*graph.Graph, error) {
+	format, found := graph.FormatForFile(filePath)
+	if !found {
+		return nil, fmt.Errorf("cannot determine graph format of '%s' from its extension", filePath)
+	}
+	if !format.CanDecode() {
+		return nil, fmt.Errorf("format %s does not support reading graphs ('%s')", format.Name, filePath)
+	}
+
+	graphFile, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open graph file '%s': %w", filePath, err)
+	}
+	defer graphFile.Close()
+
+	decoded, err := format.NewDecoder().Decode(graphFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read graph file '%s': %w", filePath, err)
+	}
+	return decoded, nil
+}
+
+// writeGraphFile encodes g to filePath, selecting the encoder by file extension
+// and falling back to GraphML.
+func writeGraphFile(filePath string, g *graph.Graph) error {
+	format, found := graph.FormatForFile(filePath)
+	if !found {
+		format, _ = graph.LookupFormat(defaultFormat)
+	}
+
+	graphFile, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file '%s': %w", filePath, err)
+	}
+	if err := format.NewEncoder().Encode(graphFile, g); err != nil {
+		graphFile.Close()
+		return fmt.Errorf("failed to write output file '%s': %w", filePath, err)
+	}
+	return graphFile.Close()
+}
@@ -0,0 +1,323 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/Desgue/codegraph/analyzer"
+	"github.com/Desgue/codegraph/extract"
+	"github.com/Desgue/codegraph/lint"
+	"github.com/Desgue/codegraph/parser"
+	"github.com/Desgue/codegraph/path"
+	"golang.org/x/tools/go/packages"
+)
+
+type LintCommand struct {
+	TargetDirectory *path.TargetDirectory
+	IncludeTests    bool
+	CheckDIP        bool
+	CheckLayers     bool
+	StrictLayers    bool
+	Layers          []analyzer.LayerDef
+	CheckGodPackage bool
+	GodThresholds   analyzer.GodPackageThresholds
+	CheckAliases    bool
+	AliasAllowlist  []string
+	// CheckMixedAbstraction warns about packages whose imports span more than
+	// MixedAbstractionThreshold layers.
+	CheckMixedAbstraction     bool
+	MixedAbstractionThreshold int
+	// CheckInitEffects warns about side effects of package initialization;
+	// BannedInitEffects lists the kinds that fail the run instead.
+	CheckInitEffects  bool
+	BannedInitEffects []string
+	// CheckPackageNames warns about packages named like a standard library
+	// package or another loaded package, except those PackageNameAllowlist
+	// matches.
+	CheckPackageNames    bool
+	PackageNameAllowlist []string
+	// CheckSignatures fails on exported functions whose signatures exceed
+	// SignatureThresholds, counting parameters and results except those of
+	// the SignatureExclude types.
+	CheckSignatures     bool
+	SignatureThresholds analyzer.SignatureThresholds
+	SignatureExclude    []string
+	// CheckConstructors warns about exported struct types with unexported
+	// fields but no New or Must constructor.
+	CheckConstructors bool
+	// CheckPackageNaming warns about packages named one of
+	// BlockedPackageNames.
+	CheckPackageNaming  bool
+	BlockedPackageNames []string
+	// CheckUnsafe warns about packages importing unsafe.
+	CheckUnsafe bool
+	// CheckProtoImports warns about packages importing protoc-gen-go packages
+	// directly, except those ProtoImportAllowlist matches.
+	CheckProtoImports    bool
+	ProtoImportAllowlist []string
+
+	output io.Writer
+}
+
+func NewLintCommand(args []string) (*LintCommand, error) {
+	flagSet := flag.NewFlagSet("lint", flag.ContinueOnError)
+
+	lintCommand := &LintCommand{
+		GodThresholds:       analyzer.DefaultGodPackageThresholds(),
+		SignatureThresholds: analyzer.DefaultSignatureThresholds(),
+		output:              os.Stdout,
+	}
+	aliasAllowList := ""
+	bannedInitEffects := ""
+	packageNameAllowList := ""
+	signatureExclude := ""
+	blockedPackageNames := ""
+	protoImportAllowList := ""
+
+	flagSet.BoolVar(&lintCommand.IncludeTests, "include-tests", false, "Include test files in parsing")
+	flagSet.BoolVar(&lintCommand.CheckDIP, "check-dip", false, "Check the Dependency Inversion Principle between layers")
+	flagSet.BoolVar(&lintCommand.CheckLayers, "check-layers", false, "Check that imports only point down through the layers")
+	flagSet.BoolVar(&lintCommand.StrictLayers, "strict-layers", false, "With --check-layers, fail on packages that are in no layer instead of warning")
+	flagSet.BoolVar(&lintCommand.CheckGodPackage, "check-god-packages", false,
+		"Fail on packages exceeding the --max-files, --max-fan-in, --max-fan-out or --max-loc limits")
+	flagSet.IntVar(&lintCommand.GodThresholds.Files, "max-files", lintCommand.GodThresholds.Files, "God package limit on Go files (0 disables)")
+	flagSet.IntVar(&lintCommand.GodThresholds.FanIn, "max-fan-in", lintCommand.GodThresholds.FanIn, "God package limit on importing packages (0 disables)")
+	flagSet.IntVar(&lintCommand.GodThresholds.FanOut, "max-fan-out", lintCommand.GodThresholds.FanOut, "God package limit on imported packages (0 disables)")
+	flagSet.IntVar(&lintCommand.GodThresholds.LinesOfCode, "max-loc", lintCommand.GodThresholds.LinesOfCode, "God package limit on lines of code (0 disables)")
+	flagSet.BoolVar(&lintCommand.CheckAliases, "check-import-aliases", false,
+		"Warn about import paths used under several names and aliases shadowing another package's name")
+	flagSet.StringVar(&aliasAllowList, "alias-allow", "",
+		"Comma-separated import path patterns exempt from --check-import-aliases (pkg or pkg/...), e.g. versioned module paths")
+	flagSet.BoolVar(&lintCommand.CheckMixedAbstraction, "check-mixed-abstraction", false,
+		"Warn about packages importing both high-level and low-level packages (see --mixed-abstraction-threshold)")
+	flagSet.IntVar(&lintCommand.MixedAbstractionThreshold, "mixed-abstraction-threshold", analyzer.DefaultMixedAbstractionThreshold,
+		"Number of import layers a package's imports may span before --check-mixed-abstraction warns")
+	flagSet.BoolVar(&lintCommand.CheckInitEffects, "check-init-effects", false,
+		"Warn about init functions and package variable initializers doing I/O, reading the environment, starting goroutines or registering into other packages")
+	flagSet.StringVar(&bannedInitEffects, "ban-init-effects", "",
+		"Comma-separated init side effect kinds that fail the run, e.g. network ("+strings.Join(analyzer.InitEffectKinds, ", ")+"); implies --check-init-effects")
+	flagSet.BoolVar(&lintCommand.CheckPackageNames, "check-package-names", false,
+		"Warn about packages named like a standard library package or another package in the load")
+	flagSet.StringVar(&packageNameAllowList, "package-name-allow", "",
+		"Comma-separated package path patterns exempt from --check-package-names (pkg or pkg/...)")
+	flagSet.BoolVar(&lintCommand.CheckSignatures, "check-signatures", false,
+		"Fail on exported functions exceeding the --max-params, --max-results or --max-bool-params limits")
+	flagSet.IntVar(&lintCommand.SignatureThresholds.Params, "max-params", lintCommand.SignatureThresholds.Params,
+		"Signature limit on parameters, a variadic one counting once (0 disables)")
+	flagSet.IntVar(&lintCommand.SignatureThresholds.Results, "max-results", lintCommand.SignatureThresholds.Results,
+		"Signature limit on results (0 disables)")
+	flagSet.IntVar(&lintCommand.SignatureThresholds.BoolParams, "max-bool-params", lintCommand.SignatureThresholds.BoolParams,
+		"Signature limit on bool parameters (0 disables)")
+	flagSet.StringVar(&signatureExclude, "signature-exclude", "",
+		"Comma-separated types left out of --check-signatures counts, as written in the source, e.g. context.Context,error")
+	flagSet.BoolVar(&lintCommand.CheckConstructors, "check-constructors", false,
+		"Warn about exported struct types with unexported fields but no New or Must constructor (suppress with "+analyzer.NoConstructorDirective+")")
+	flagSet.BoolVar(&lintCommand.CheckPackageNaming, "check-package-naming", false,
+		"Warn about catch-all package names ("+strings.Join(lint.DefaultBlockedPackageNames, ", ")+" unless --blocked-package-names is set)")
+	flagSet.StringVar(&blockedPackageNames, "blocked-package-names", "",
+		"Comma-separated package names --check-package-naming reports, replacing the defaults; implies --check-package-naming")
+	flagSet.BoolVar(&lintCommand.CheckUnsafe, "check-unsafe", false,
+		"Warn about packages importing unsafe, for security review")
+	flagSet.BoolVar(&lintCommand.CheckProtoImports, "check-proto-imports", false,
+		"Warn about packages importing generated protobuf packages directly instead of through an adapter")
+	flagSet.StringVar(&protoImportAllowList, "proto-import-allow", "",
+		"Comma-separated package path patterns of adapters exempt from --check-proto-imports (pkg or pkg/...)")
+	flagSet.Var(&layerFlag{layers: &lintCommand.Layers}, "layer",
+		"Layer definition name=pattern[,pattern...], repeatable, ordered from highest to lowest level")
+	flagSet.Var(&layerFlag{layers: &lintCommand.Layers, abstract: true}, "abstract-layer",
+		"Like --layer, for a layer that must only contain interfaces")
+
+	if err := flagSet.Parse(args); err != nil {
+		return nil, newUsageError(err)
+	}
+	if aliasAllowList != "" {
+		lintCommand.AliasAllowlist = strings.Split(aliasAllowList, ",")
+	}
+	if packageNameAllowList != "" {
+		lintCommand.PackageNameAllowlist = strings.Split(packageNameAllowList, ",")
+	}
+	if protoImportAllowList != "" {
+		lintCommand.ProtoImportAllowlist = strings.Split(protoImportAllowList, ",")
+	}
+	lintCommand.BlockedPackageNames = lint.DefaultBlockedPackageNames
+	if blockedPackageNames != "" {
+		lintCommand.BlockedPackageNames = strings.Split(blockedPackageNames, ",")
+		lintCommand.CheckPackageNaming = true
+	}
+	if signatureExclude != "" {
+		lintCommand.SignatureExclude = strings.Split(signatureExclude, ",")
+	}
+	if bannedInitEffects != "" {
+		lintCommand.BannedInitEffects = strings.Split(bannedInitEffects, ",")
+		lintCommand.CheckInitEffects = true
+	}
+
+	directoryArgument := ""
+	if flagSet.NArg() > 0 {
+		directoryArgument = flagSet.Arg(0)
+	}
+
+	targetDirectory, err := path.NewTargetDirectory(directoryArgument)
+	if err != nil {
+		return nil, err
+	}
+	lintCommand.TargetDirectory = targetDirectory
+
+	if err := lintCommand.Validate(); err != nil {
+		return nil, err
+	}
+
+	return lintCommand, nil
+}
+
+func (lc *LintCommand) Validate() error {
+	if lc.CheckDIP && len(lc.Layers) < 2 {
+		return usageErrorf("--check-dip requires at least two layers (use --layer and --abstract-layer)")
+	}
+	if lc.CheckLayers && len(lc.Layers) < 2 {
+		return usageErrorf("--check-layers requires at least two layers (use --layer)")
+	}
+	if lc.StrictLayers && !lc.CheckLayers {
+		return usageErrorf("--strict-layers requires --check-layers")
+	}
+	thresholds := lc.GodThresholds
+	if thresholds.Files < 0 || thresholds.FanIn < 0 || thresholds.FanOut < 0 || thresholds.LinesOfCode < 0 {
+		return usageErrorf("god package limits must not be negative")
+	}
+	signatureThresholds := lc.SignatureThresholds
+	if signatureThresholds.Params < 0 || signatureThresholds.Results < 0 || signatureThresholds.BoolParams < 0 {
+		return usageErrorf("signature limits must not be negative")
+	}
+	if lc.MixedAbstractionThreshold < 0 {
+		return usageErrorf("--mixed-abstraction-threshold must not be negative")
+	}
+	for _, kind := range lc.BannedInitEffects {
+		if !slices.Contains(analyzer.InitEffectKinds, kind) {
+			return usageErrorf("unknown init side effect %q in --ban-init-effects, expected one of %s", kind, strings.Join(analyzer.InitEffectKinds, ", "))
+		}
+	}
+	return nil
+}
+
+func (lc *LintCommand) Execute() error {
+	pkgs, _, err := parser.Load(lc.TargetDirectory.Path, lc.IncludeTests)
+	if err != nil {
+		return err
+	}
+	extractors := extract.Extractors()
+	if lc.CheckSignatures {
+		extractors = append(extractors, &extract.SymbolExtractor{SignatureExclude: lc.SignatureExclude})
+	}
+	dependencyGraph, err := extractGraphWith(context.Background(), pkgs, extractors)
+	if err != nil {
+		return err
+	}
+
+	rules, err := lc.rules(pkgs)
+	if err != nil {
+		return err
+	}
+	violations := lint.Check(dependencyGraph, rules)
+	hasCycles := false
+	errorCount := 0
+	for _, violation := range violations {
+		fmt.Fprintf(lc.output, "%s: %s: %s\n", violation.Severity, violation.Rule, violation.Message)
+		if violation.Rule == lint.NoCircularDependencyRuleName {
+			hasCycles = true
+		}
+		if violation.Severity == lint.SeverityError {
+			errorCount++
+		}
+	}
+
+	// Warnings are reported but do not fail the run.
+	if hasCycles {
+		return &ExitError{Code: ExitCodeCycles, Err: fmt.Errorf("lint found %d violation(s) including import cycles", errorCount)}
+	}
+	if errorCount > 0 {
+		return fmt.Errorf("lint found %d violation(s)", errorCount)
+	}
+	if len(violations) == 0 {
+		fmt.Fprintf(lc.output, "No lint violations found\n")
+	}
+	return nil
+}
+
+// rules returns the default rule set plus the rules enabled by flags.
+func (lc *LintCommand) rules(pkgs []*packages.Package) ([]lint.Rule, error) {
+	rules := lint.DefaultRuleSet()
+	if lc.CheckDIP {
+		rules = append(rules, &lint.DependencyInversionRule{Layers: lc.Layers})
+	}
+	if lc.CheckLayers {
+		rules = append(rules, &lint.LayerDirectionRule{Layers: lc.Layers, Strict: lc.StrictLayers})
+	}
+	if lc.CheckGodPackage {
+		rules = append(rules, &lint.GodPackageRule{Thresholds: lc.GodThresholds})
+	}
+	if lc.CheckSignatures {
+		rules = append(rules, &lint.SignatureRule{Thresholds: lc.SignatureThresholds})
+	}
+	if lc.CheckMixedAbstraction {
+		rules = append(rules, &lint.MixedAbstractionRule{Threshold: lc.MixedAbstractionThreshold})
+	}
+	if lc.CheckAliases {
+		rules = append(rules, &lint.ImportAliasRule{Report: analyzer.CheckImportAliases(pkgs, lc.AliasAllowlist)})
+	}
+	if lc.CheckInitEffects {
+		rules = append(rules, &lint.InitSideEffectRule{
+			Effects: analyzer.FindInitSideEffects(pkgs, analyzer.DefaultInitEffectDepth),
+			Banned:  lc.BannedInitEffects,
+		})
+	}
+	if lc.CheckConstructors {
+		rules = append(rules, &lint.ConstructorRule{Missing: analyzer.FindMissingConstructors(pkgs)})
+	}
+	if lc.CheckPackageNaming {
+		rules = append(rules, &lint.PackageNamingRule{Blocked: lc.BlockedPackageNames})
+	}
+	if lc.CheckUnsafe {
+		rules = append(rules, &lint.UnsafeImportRule{})
+	}
+	if lc.CheckProtoImports {
+		rules = append(rules, &lint.NoDirectProtoImportRule{Allowed: lc.ProtoImportAllowlist})
+	}
+	if lc.CheckPackageNames {
+		stdlib, err := parser.StdlibPackageNames()
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, &lint.PackageNameRule{Collisions: analyzer.FindCollidingPackageNames(pkgs, stdlib, lc.PackageNameAllowlist)})
+	}
+	return rules, nil
+}
+
+// layerFlag parses repeatable "name=pattern[,pattern...]" layer definitions,
+// appending them in command-line order to a shared slice.
+type layerFlag struct {
+	layers   *[]analyzer.LayerDef
+	abstract bool
+}
+
+func (lf *layerFlag) String() string {
+	return ""
+}
+
+func (lf *layerFlag) Set(value string) error {
+	name, patternList, found := strings.Cut(value, "=")
+	if !found || name == "" || patternList == "" {
+		return fmt.Errorf("invalid layer %q, expected name=pattern[,pattern...]", value)
+	}
+
+	*lf.layers = append(*lf.layers, analyzer.LayerDef{
+		Name:     name,
+		Packages: strings.Split(patternList, ","),
+		Abstract: lf.abstract,
+	})
+	return nil
+}
@@ -0,0 +1,552 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Desgue/codegraph/analyzer"
+)
+
+// writeTestModule writes files, keyed by slash-separated relative path, into a temporary directory.
+func writeTestModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	testDir := t.TempDir()
+	for name, content := range files {
+		filePath := filepath.Join(testDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+	return testDir
+}
+
+func TestNewLintCommand(t *testing.T) {
+	t.Run("parses layers in command-line order", func(t *testing.T) {
+		cmd, err := NewLintCommand([]string{
+			"--check-dip",
+			"--layer", "app=example.com/mod/app/...",
+			"--abstract-layer", "ports=example.com/mod/ports,example.com/mod/events",
+			"--layer", "infra=example.com/mod/store",
+			t.TempDir(),
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		want := []analyzer.LayerDef{
+			{Name: "app", Packages: []string{"example.com/mod/app/..."}},
+			{Name: "ports", Packages: []string{"example.com/mod/ports", "example.com/mod/events"}, Abstract: true},
+			{Name: "infra", Packages: []string{"example.com/mod/store"}},
+		}
+		if !reflect.DeepEqual(cmd.Layers, want) {
+			t.Errorf("Layers = %+v, want %+v", cmd.Layers, want)
+		}
+	})
+
+	errorTests := []struct {
+		name string
+		args []string
+	}{
+		{name: "dip without layers", args: []string{"--check-dip"}},
+		{name: "dip with a single layer", args: []string{"--check-dip", "--layer", "app=example.com/app"}},
+		{name: "malformed layer", args: []string{"--check-dip", "--layer", "app"}},
+		{name: "layer without patterns", args: []string{"--check-dip", "--layer", "app="}},
+		{name: "layer check with a single layer", args: []string{"--check-layers", "--layer", "app=example.com/app"}},
+		{name: "strict layers without the layer check", args: []string{"--strict-layers"}},
+		{name: "negative god package limit", args: []string{"--check-god-packages", "--max-loc", "-1"}},
+		{name: "negative mixed abstraction threshold", args: []string{"--check-mixed-abstraction", "--mixed-abstraction-threshold", "-1"}},
+		{name: "unknown banned init effect", args: []string{"--ban-init-effects", "network,telepathy"}},
+	}
+
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewLintCommand(append(tt.args, t.TempDir())); !errors.Is(err, ErrUsage) {
+				t.Fatalf("expected ErrUsage, got %v", err)
+			}
+		})
+	}
+}
+
+func TestLintCommand_Execute_CheckDIP(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":         "module testdip\n\ngo 1.24\n",
+		"app/app.go":     "package app\n\nimport (\n\t_ \"testdip/ports\"\n\t_ \"testdip/store\"\n)\n",
+		"ports/ports.go": "package ports\n\ntype Repository interface{ Save() error }\n",
+		"store/store.go": "package store\n\nimport _ \"testdip/ports\"\n\ntype DB struct{}\n",
+	})
+
+	cmd, err := NewLintCommand([]string{
+		"--check-dip",
+		"--layer", "app=testdip/app",
+		"--abstract-layer", "ports=testdip/ports",
+		"--layer", "infra=testdip/store",
+		testDir,
+	})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected lint violations to be reported as an error")
+	}
+
+	if !strings.Contains(output.String(), "testdip/app -> testdip/store") {
+		t.Errorf("expected app -> store violation, got:\n%s", output.String())
+	}
+	if strings.Contains(output.String(), "-> testdip/ports") {
+		t.Errorf("dependencies on the abstract layer must not be reported, got:\n%s", output.String())
+	}
+}
+
+func TestLintCommand_Execute_Cycles(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod": "module testcycle\n\ngo 1.24\n",
+		"a/a.go": "package a\n\nimport _ \"testcycle/b\"\n",
+		"b/b.go": "package b\n\nimport _ \"testcycle/a\"\n",
+		"c/c.go": "package c\n",
+	})
+
+	cmd, err := NewLintCommand([]string{testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+
+	err = cmd.Execute()
+	var exitError *ExitError
+	if !errors.As(err, &exitError) || exitError.Code != ExitCodeCycles {
+		t.Fatalf("expected exit code %d for cycles, got %v", ExitCodeCycles, err)
+	}
+
+	want := "error: no-circular-dependency: import cycle between testcycle/a, testcycle/b\n"
+	if output.String() != want {
+		t.Errorf("output = %q, want %q", output.String(), want)
+	}
+}
+
+func TestLintCommand_Execute_Clean(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod": "module testclean\n\ngo 1.24\n",
+		"a/a.go": "package a\n\nimport _ \"testclean/b\"\n",
+		"b/b.go": "package b\n",
+	})
+
+	cmd, err := NewLintCommand([]string{testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if output.String() != "No lint violations found\n" {
+		t.Errorf("unexpected output %q", output.String())
+	}
+}
+
+func TestLintCommand_Execute_CheckLayers(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":                "module testlayers\n\ngo 1.24\n",
+		"transport/http/api.go": "package http\n\nimport _ \"testlayers/service\"\n",
+		"service/service.go":    "package service\n\nimport _ \"testlayers/domain\"\n",
+		"domain/domain.go":      "package domain\n",
+		"tools/gen.go":          "package tools\n",
+	})
+	layerArgs := []string{
+		"--check-layers",
+		"--layer", "transport=testlayers/transport/...",
+		"--layer", "service=testlayers/service",
+		"--layer", "domain=testlayers/domain",
+	}
+
+	t.Run("unmapped packages only warn", func(t *testing.T) {
+		cmd, err := NewLintCommand(append(layerArgs, testDir))
+		if err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+		var output bytes.Buffer
+		cmd.output = &output
+
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		want := "warning: layer-direction: testlayers/tools is not in any layer\n"
+		if output.String() != want {
+			t.Errorf("output = %q, want %q", output.String(), want)
+		}
+	})
+
+	t.Run("strict mode fails on unmapped packages", func(t *testing.T) {
+		cmd, err := NewLintCommand(append(layerArgs, "--strict-layers", testDir))
+		if err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+		cmd.output = &bytes.Buffer{}
+
+		if err := cmd.Execute(); err == nil {
+			t.Fatal("expected the unmapped package to fail strict mode")
+		}
+	})
+
+	t.Run("upward imports are errors", func(t *testing.T) {
+		cmd, err := NewLintCommand([]string{"--check-layers",
+			"--layer", "domain=testlayers/domain,testlayers/tools",
+			"--layer", "service=testlayers/service",
+			"--layer", "transport=testlayers/transport/...",
+			testDir})
+		if err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+		var output bytes.Buffer
+		cmd.output = &output
+
+		if err := cmd.Execute(); err == nil {
+			t.Fatal("expected upward imports to be reported as an error")
+		}
+		want := "error: layer-direction: testlayers/service -> testlayers/domain: layer service imports higher layer domain (imports must point down)\n" +
+			"error: layer-direction: testlayers/transport/http -> testlayers/service: layer transport imports higher layer service (imports must point down)\n"
+		if output.String() != want {
+			t.Errorf("output = %q, want %q", output.String(), want)
+		}
+	})
+}
+
+func TestLintCommand_Execute_CheckGodPackages(t *testing.T) {
+	files := map[string]string{
+		"go.mod":       "module testgod\n\ngo 1.24\n",
+		"app/app.go":   "package app\n\nimport _ \"testgod/core\"\n",
+		"util/util.go": "package util\n",
+	}
+	for i := range 4 {
+		files[fmt.Sprintf("core/file%d.go", i)] = "package core\n\nimport _ \"testgod/util\"\n"
+	}
+	testDir := writeTestModule(t, files)
+
+	cmd, err := NewLintCommand([]string{"--check-god-packages", "--max-files", "3", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected the god package to fail the run")
+	}
+	want := "error: god-package: testgod/core is a god package (files 4 > 3); top importers: testgod/app; top imports: testgod/util\n"
+	if output.String() != want {
+		t.Errorf("output = %q, want %q", output.String(), want)
+	}
+}
+
+func TestLintCommand_Execute_CheckSignatures(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod": "module testsig\n\ngo 1.24\n",
+		"store/store.go": "package store\n\nimport \"context\"\n\n" +
+			"func Open(ctx context.Context, name string, readOnly, create bool) error { return nil }\n\n" +
+			"func Close(ctx context.Context) error { return nil }\n",
+	})
+
+	cmd, err := NewLintCommand([]string{"--check-signatures", "--max-params", "3", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected the signature smell to fail the run")
+	}
+	want := "error: signature: testsig/store.Open has an unwieldy signature (params 4 > 3, bool params 2 > 1)\n"
+	if output.String() != want {
+		t.Errorf("output = %q, want %q", output.String(), want)
+	}
+
+	cmd, err = NewLintCommand([]string{"--check-signatures", "--max-params", "3", "--max-bool-params", "0",
+		"--signature-exclude", "context.Context", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	output.Reset()
+	cmd.output = &output
+	if err := cmd.Execute(); err != nil {
+		t.Errorf("Execute() error = %v with context.Context excluded, output:\n%s", err, output.String())
+	}
+}
+
+func TestNewLintCommand_NegativeSignatureLimit(t *testing.T) {
+	if _, err := NewLintCommand([]string{"--max-results", "-1", t.TempDir()}); err == nil {
+		t.Error("expected a negative signature limit to be rejected")
+	}
+}
+
+func TestLintCommand_Execute_CheckMixedAbstraction(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":             "module testmixed\n\ngo 1.24\n",
+		"cmd/main.go":        "package main\n\nimport (\n\t_ \"testmixed/app\"\n\t_ \"testmixed/handler\"\n)\n\nfunc main() {}\n",
+		"app/app.go":         "package app\n\nimport _ \"testmixed/service\"\n",
+		"service/service.go": "package service\n\nimport _ \"testmixed/db\"\n",
+		"db/db.go":           "package db\n",
+		"handler/handler.go": "package handler\n\nimport (\n\t_ \"testmixed/app\"\n\t_ \"testmixed/db\"\n)\n",
+	})
+
+	cmd, err := NewLintCommand([]string{"--check-mixed-abstraction", "--mixed-abstraction-threshold", "1", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected warnings not to fail the run, got %v", err)
+	}
+	want := "warning: mixed-abstraction: testmixed/handler imports span 2 layers (testmixed/app at layer 2 to testmixed/db at layer 4), more than 1\n"
+	if output.String() != want {
+		t.Errorf("output = %q, want %q", output.String(), want)
+	}
+}
+
+func TestLintCommand_Execute_CheckImportAliases(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":             "module testalias\n\ngo 1.24\n",
+		"ctxutil/ctxutil.go": "package ctxutil\n\nfunc Value() {}\n",
+		"app/a.go":           "package app\n\nimport cu \"testalias/ctxutil\"\n\nvar _ = cu.Value\n",
+		"app/b.go":           "package app\n\nimport \"testalias/ctxutil\"\n\nvar _ = ctxutil.Value\n",
+	})
+
+	t.Run("warns without failing", func(t *testing.T) {
+		cmd, err := NewLintCommand([]string{"--check-import-aliases", testDir})
+		if err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+		var output bytes.Buffer
+		cmd.output = &output
+
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if !strings.HasPrefix(output.String(), "warning: import-alias: testalias/ctxutil is imported under 2 names: ctxutil (") {
+			t.Errorf("unexpected output %q", output.String())
+		}
+	})
+
+	t.Run("allowlisted paths", func(t *testing.T) {
+		cmd, err := NewLintCommand([]string{"--check-import-aliases", "--alias-allow", "testalias/ctxutil", testDir})
+		if err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+		var output bytes.Buffer
+		cmd.output = &output
+
+		if err := cmd.Execute(); err != nil || output.String() != "No lint violations found\n" {
+			t.Errorf("Execute() = %v, output %q", err, output.String())
+		}
+	})
+}
+
+func TestLintCommand_Execute_InitEffects(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod": "module testinit\n\ngo 1.24\n",
+		"client/client.go": "package client\n\nimport (\n\t\"net/http\"\n\t\"os\"\n)\n\n" +
+			"var token = os.Getenv(\"TOKEN\")\n\nfunc init() { warmUp() }\n\nfunc warmUp() { http.Get(\"http://localhost\") }\n",
+	})
+
+	t.Run("warns without failing", func(t *testing.T) {
+		cmd, err := NewLintCommand([]string{"--check-init-effects", testDir})
+		if err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+		var output bytes.Buffer
+		cmd.output = &output
+
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("expected warnings not to fail the run, got %v", err)
+		}
+		if strings.Count(output.String(), "warning: init-side-effects: ") != 2 {
+			t.Errorf("unexpected output %q", output.String())
+		}
+	})
+
+	t.Run("banned kinds fail", func(t *testing.T) {
+		cmd, err := NewLintCommand([]string{"--ban-init-effects", "network", testDir})
+		if err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+		var output bytes.Buffer
+		cmd.output = &output
+
+		if err := cmd.Execute(); err == nil {
+			t.Fatal("expected the banned network call to fail the run")
+		}
+		if !strings.Contains(output.String(), "error: init-side-effects: ") ||
+			!strings.Contains(output.String(), "testinit/client.init -> testinit/client.warmUp -> net/http.Get") {
+			t.Errorf("unexpected output %q", output.String())
+		}
+	})
+}
+
+func TestLintCommand_Execute_CheckPackageNames(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":           "module testnames\n\ngo 1.24\n",
+		"errors/errors.go": "package errors\n\nfunc Wrap() {}\n",
+		"app/app.go":       "package app\n\nimport apperrors \"testnames/errors\"\n\nvar _ = apperrors.Wrap\n",
+	})
+
+	t.Run("warns without failing", func(t *testing.T) {
+		cmd, err := NewLintCommand([]string{"--check-package-names", testDir})
+		if err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+		var output bytes.Buffer
+		cmd.output = &output
+
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("expected warnings not to fail the run, got %v", err)
+		}
+		want := "warning: package-name: testnames/errors is named errors like errors (stdlib); imported by testnames/app as apperrors\n"
+		if output.String() != want {
+			t.Errorf("output = %q, want %q", output.String(), want)
+		}
+	})
+
+	t.Run("allowlisted packages", func(t *testing.T) {
+		cmd, err := NewLintCommand([]string{"--check-package-names", "--package-name-allow", "testnames/errors", testDir})
+		if err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+		var output bytes.Buffer
+		cmd.output = &output
+
+		if err := cmd.Execute(); err != nil || output.String() != "No lint violations found\n" {
+			t.Errorf("Execute() = %v, output %q", err, output.String())
+		}
+	})
+}
+
+func TestLintCommand_Execute_CheckConstructors(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod": "module testctor\n\ngo 1.24\n",
+		"store/store.go": "package store\n\ntype Store struct{ path string }\n\n" +
+			"type Cache struct{ size int }\n\nfunc NewCache() *Cache { return &Cache{} }\n",
+	})
+
+	cmd, err := NewLintCommand([]string{"--check-constructors", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected warnings not to fail the run, got %v", err)
+	}
+	want := "testctor/store.Store has unexported fields but no New or Must constructor\n"
+	if !strings.HasPrefix(output.String(), "warning: missing-constructor: ") || !strings.HasSuffix(output.String(), "store.go:3: "+want) {
+		t.Errorf("output = %q, want a store.go:3 warning %q", output.String(), want)
+	}
+}
+
+func TestLintCommand_Execute_CheckPackageNaming(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":         "module testnaming\n\ngo 1.24\n",
+		"utils/utils.go": "package utils\n",
+		"store/store.go": "package store\n",
+	})
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "default blocklist",
+			args: []string{"--check-package-naming"},
+			want: "warning: package-naming: testnaming/utils uses the blocked package name \"utils\"; name it after what it provides\n",
+		},
+		{
+			name: "custom blocklist",
+			args: []string{"--blocked-package-names", "store"},
+			want: "warning: package-naming: testnaming/store uses the blocked package name \"store\"; name it after what it provides\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := NewLintCommand(append(tt.args, testDir))
+			if err != nil {
+				t.Fatalf("setup failed: %v", err)
+			}
+			var output bytes.Buffer
+			cmd.output = &output
+
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("expected warnings not to fail the run, got %v", err)
+			}
+			if output.String() != tt.want {
+				t.Errorf("output = %q, want %q", output.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestLintCommand_Execute_CheckUnsafe(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":            "module testunsafe\n\ngo 1.24\n",
+		"bytesconv/conv.go": "package bytesconv\n\nimport \"unsafe\"\n\nfunc String(b []byte) string { return unsafe.String(&b[0], len(b)) }\n",
+		"store/store.go":    "package store\n",
+	})
+
+	cmd, err := NewLintCommand([]string{"--check-unsafe", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected warnings not to fail the run, got %v", err)
+	}
+	want := "warning: unsafe-import: testunsafe/bytesconv imports unsafe; review it for memory safety\n"
+	if output.String() != want {
+		t.Errorf("output = %q, want %q", output.String(), want)
+	}
+}
+
+func TestLintCommand_Execute_CheckProtoImports(t *testing.T) {
+	userpb := "// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage userpb\n\n" +
+		"type User struct{}\n\nfunc (*User) Reset()         {}\nfunc (*User) String() string { return \"\" }\nfunc (*User) ProtoMessage()  {}\n"
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":                 "module testproto\n\ngo 1.24\n",
+		"gen/userpb/user.pb.go":  userpb,
+		"billing/billing.go":     "package billing\n\nimport \"testproto/gen/userpb\"\n\nvar _ userpb.User\n",
+		"transport/grpc/grpc.go": "package grpc\n\nimport \"testproto/gen/userpb\"\n\nvar _ userpb.User\n",
+	})
+
+	cmd, err := NewLintCommand([]string{"--check-proto-imports", "--proto-import-allow", "testproto/transport/...", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected warnings not to fail the run, got %v", err)
+	}
+	want := "warning: no-direct-proto-import: testproto/billing imports generated protobuf package testproto/gen/userpb directly; convert messages in an adapter package\n"
+	if output.String() != want {
+		t.Errorf("output = %q, want %q", output.String(), want)
+	}
+}
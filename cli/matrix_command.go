@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Desgue/codegraph/analyzer"
+	"github.com/Desgue/codegraph/parser"
+	"github.com/Desgue/codegraph/path"
+)
+
+type MatrixCommand struct {
+	TargetDirectory *path.TargetDirectory
+	Interfaces      []string
+	Types           []string
+	Near            int
+	JSON            bool
+	OutputFile      string
+
+	output io.Writer
+}
+
+func NewMatrixCommand(args []string) (*MatrixCommand, error) {
+	flagSet := flag.NewFlagSet("matrix", flag.ContinueOnError)
+
+	matrixCommand := &MatrixCommand{output: os.Stdout}
+	interfaceList := ""
+	typeList := ""
+
+	flagSet.StringVar(&interfaceList, "interfaces", "", "Comma-separated package patterns whose interfaces are the rows (pkg or pkg/...)")
+	flagSet.StringVar(&typeList, "types", "", "Comma-separated package patterns whose concrete types are the columns (pkg or pkg/...)")
+	flagSet.IntVar(&matrixCommand.Near, "near", 0, "Also list the missing methods of types missing at most this many")
+	flagSet.BoolVar(&matrixCommand.JSON, "json", false, "Print the matrix as JSON instead of CSV")
+	flagSet.StringVar(&matrixCommand.OutputFile, "output", "", "Write the matrix to this file instead of stdout")
+
+	if err := flagSet.Parse(args); err != nil {
+		return nil, newUsageError(err)
+	}
+	if interfaceList != "" {
+		matrixCommand.Interfaces = strings.Split(interfaceList, ",")
+	}
+	if typeList != "" {
+		matrixCommand.Types = strings.Split(typeList, ",")
+	}
+
+	directoryArgument := ""
+	if flagSet.NArg() > 0 {
+		directoryArgument = flagSet.Arg(0)
+	}
+
+	targetDirectory, err := path.NewTargetDirectory(directoryArgument)
+	if err != nil {
+		return nil, err
+	}
+	matrixCommand.TargetDirectory = targetDirectory
+
+	if err := matrixCommand.Validate(); err != nil {
+		return nil, err
+	}
+
+	return matrixCommand, nil
+}
+
+// Validate requires a filter so the matrix of a large codebase, every
+// interface by every type, stays bounded.
+func (mc *MatrixCommand) Validate() error {
+	if len(mc.Interfaces) == 0 && len(mc.Types) == 0 {
+		return usageErrorf("matrix requires --interfaces or --types to bound its size")
+	}
+	if mc.Near < 0 {
+		return usageErrorf("--near must not be negative, got %d", mc.Near)
+	}
+	return nil
+}
+
+// Execute loads the packages without tests, so test doubles are not columns.
+func (mc *MatrixCommand) Execute() error {
+	pkgs, _, err := parser.Load(mc.TargetDirectory.Path, false)
+	if err != nil {
+		return err
+	}
+	matrix := analyzer.SatisfactionMatrix(pkgs, mc.Interfaces, mc.Types, mc.Near)
+
+	var report bytes.Buffer
+	if mc.JSON {
+		encoder := json.NewEncoder(&report)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(matrix); err != nil {
+			return err
+		}
+	} else if err := writeMatrixCSV(&report, matrix); err != nil {
+		return err
+	}
+
+	if mc.OutputFile == "" {
+		_, err := mc.output.Write(report.Bytes())
+		return err
+	}
+	if err := os.WriteFile(mc.OutputFile, report.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write output file '%s': %w", mc.OutputFile, err)
+	}
+	fmt.Fprintf(mc.output, "Wrote a %d x %d matrix to %s\n", len(matrix.Interfaces), len(matrix.Types), mc.OutputFile)
+	return nil
+}
+
+// writeMatrixCSV writes one row per interface and one column per type. Cells
+// hold the analyzer satisfaction value, or "missing: A; B" for types within
+// the --near limit.
+func writeMatrixCSV(writer io.Writer, matrix analyzer.InterfaceMatrix) error {
+	csvWriter := csv.NewWriter(writer)
+	if err := csvWriter.Write(append([]string{"interface"}, matrix.Types...)); err != nil {
+		return err
+	}
+	for i, iface := range matrix.Interfaces {
+		row := []string{iface}
+		for _, cell := range matrix.Cells[i*len(matrix.Types) : (i+1)*len(matrix.Types)] {
+			value := cell.Satisfies
+			if len(cell.Missing) > 0 {
+				value = "missing: " + strings.Join(cell.Missing, "; ")
+			}
+			row = append(row, value)
+		}
+		if err := csvWriter.Write(row); err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/Desgue/codegraph/analyzer"
+)
+
+func TestNewMatrixCommand(t *testing.T) {
+	errorTests := []struct {
+		name string
+		args []string
+	}{
+		{name: "no filter", args: []string{}},
+		{name: "negative near", args: []string{"--types", "mod/...", "--near", "-1"}},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewMatrixCommand(append(tt.args, t.TempDir())); !errors.Is(err, ErrUsage) {
+				t.Fatalf("expected ErrUsage, got %v", err)
+			}
+		})
+	}
+}
+
+func TestMatrixCommand_Execute(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":           "module testmatrix\n\ngo 1.24\n",
+		"plugin/plugin.go": "package plugin\n\ntype Plugin interface {\n\tStart() error\n\tStop() error\n}\n",
+		"impl/impl.go": "package impl\n\ntype Disk struct{}\n\nfunc (*Disk) Start() error { return nil }\nfunc (*Disk) Stop() error { return nil }\n\n" +
+			"type Memory struct{}\n\nfunc (Memory) Start() error { return nil }\n",
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		cmd, err := NewMatrixCommand([]string{"--interfaces", "testmatrix/plugin", "--types", "testmatrix/impl", "--near", "1", testDir})
+		if err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+		var output bytes.Buffer
+		cmd.output = &output
+
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		want := "interface,testmatrix/impl.Disk,testmatrix/impl.Memory\n" +
+			"testmatrix/plugin.Plugin,pointer,missing: Stop\n"
+		if output.String() != want {
+			t.Errorf("output = %q, want %q", output.String(), want)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		cmd, err := NewMatrixCommand([]string{"--interfaces", "testmatrix/plugin", "--json", testDir})
+		if err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+		var output bytes.Buffer
+		cmd.output = &output
+
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		var matrix analyzer.InterfaceMatrix
+		if err := json.Unmarshal(output.Bytes(), &matrix); err != nil {
+			t.Fatalf("expected JSON output: %v", err)
+		}
+		if len(matrix.Cells) != 2 || matrix.Cells[1].Satisfies != analyzer.NotSatisfied || matrix.Cells[1].Missing != nil {
+			t.Errorf("unexpected cells %+v", matrix.Cells)
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+func TestNewMergeCommand(t *testing.T) {
+	cmd, err := NewMergeCommand([]string{"--output", "out.json", "--on-conflict", "error", "--duplicate-edges", "weight", "a.graphml", "b.graphml"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	wantPolicy := graph.ConflictPolicy{Attributes: graph.ErrorOnConflict, Edges: graph.WeightEdges}
+	if cmd.OutputFile != "out.json" || cmd.Policy != wantPolicy || len(cmd.InputFiles) != 2 {
+		t.Errorf("unexpected command: %+v", cmd)
+	}
+
+	invalidArgs := [][]string{
+		{"a.graphml", "b.graphml"},
+		{"--output", "out.graphml", "a.graphml"},
+		{"--output", "out.graphml", "--on-conflict", "newest", "a.graphml", "b.graphml"},
+		{"--output", "out.graphml", "--duplicate-edges", "keep", "a.graphml", "b.graphml"},
+	}
+	for _, args := range invalidArgs {
+		if _, err := NewMergeCommand(args); !errors.Is(err, ErrUsage) {
+			t.Errorf("NewMergeCommand(%v) expected ErrUsage, got %v", args, err)
+		}
+	}
+}
+
+func TestMergeCommand_Execute(t *testing.T) {
+	first := newCLITestGraph(t, []string{"ex/a", "ex/b"}, [][2]string{{"ex/a", "ex/b"}})
+	first.Title = "first"
+	firstFile := writeGraphMLFile(t, first)
+	secondFile := writeGraphMLFile(t, newCLITestGraph(t, []string{"ex/a", "ex/b", "ex/c"},
+		[][2]string{{"ex/a", "ex/b"}, {"ex/b", "ex/c"}}))
+	outputFile := filepath.Join(t.TempDir(), "merged.graphml")
+
+	cmd, err := NewMergeCommand([]string{"--output", outputFile, "--duplicate-edges", "weight", firstFile, secondFile})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !strings.Contains(output.String(), "Merged 2 graphs") {
+		t.Errorf("unexpected output: %q", output.String())
+	}
+
+	merged, err := readGraphFile(outputFile)
+	if err != nil {
+		t.Fatalf("readGraphFile() error = %v", err)
+	}
+	if len(merged.Nodes()) != 3 || len(merged.Edges()) != 2 {
+		t.Fatalf("expected 3 nodes and 2 edges, got %d and %d", len(merged.Nodes()), len(merged.Edges()))
+	}
+	if weight := merged.Edges()[0].Attributes[graph.EdgeWeightAttribute]; weight != "2" {
+		t.Errorf("expected shared edge weight 2, got %q", weight)
+	}
+	if want := []string{"first", "graph.graphml"}; !slices.Equal(merged.Provenance, want) {
+		t.Errorf("Provenance = %v, want %v", merged.Provenance, want)
+	}
+}
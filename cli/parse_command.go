@@ -1,28 +1,144 @@
 package cli
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/Desgue/codegraph/analyzer"
+	"github.com/Desgue/codegraph/extract"
+	"github.com/Desgue/codegraph/formatter"
+	"github.com/Desgue/codegraph/graph"
 	"github.com/Desgue/codegraph/parser"
 	"github.com/Desgue/codegraph/path"
+	"golang.org/x/term"
+	"golang.org/x/tools/go/packages"
 )
 
+const defaultFormat = "graphml"
+
 type ParseCommand struct {
 	TargetDirectory *path.TargetDirectory
 	OutputFile      string
 	IncludeTests    bool
+	Format          string
+	DOTOmitLabels   bool
+	DOTClusters     bool
+	ClusterStats    bool
+	DOTRankSame     bool
+	ShowEdgeLabels  bool
+	GraphTitle      string
+	JSONIndent      string
+	JSONCompact     bool
+	Verbose         bool
+	HideProgressBar bool
+	HideTestEdges   bool
+	ErrorFormat     string
+	// SortNodes writes GraphML and DOT nodes and edges sorted rather than in
+	// extraction order.
+	SortNodes bool
+	// IncludeTodos records every marker comment on its package node, not just
+	// the count.
+	IncludeTodos bool
+	// TodoMarkers replaces the default TODO, FIXME, HACK and XXX markers.
+	TodoMarkers []string
+	// PackagesFromStdin loads the newline-separated package patterns read
+	// from stdin instead of every package under the target directory.
+	PackagesFromStdin bool
+	// Calls adds extract.CallExtractor's function nodes and call edges.
+	Calls bool
+	// SymbolIndexFile, when set, receives parser.SymbolIndex as JSON.
+	SymbolIndexFile string
+	// ChangeFrequency records on each node how many commits touched its
+	// files, since the ChangeFrequencySince ref when set.
+	ChangeFrequency      bool
+	ChangeFrequencySince string
+	// CheckGoVersion warns about packages whose module requires a Go version
+	// newer than this one, stored with its "go" prefix.
+	CheckGoVersion string
+	// ImportsOnly skips type-checking, and parsing unless tests are included,
+	// producing package nodes and import edges without source metrics.
+	ImportsOnly bool
+	// NoCache extracts every package instead of reusing the output cached
+	// by earlier runs for unchanged packages.
+	NoCache bool
+	// ParanoidCache hashes every file for the cache keys instead of trusting
+	// the sizes and modification times recorded by earlier runs.
+	ParanoidCache bool
+	// Concurrency bounds how many packages go list builds and how many are
+	// extracted at a time, GOMAXPROCS by default. It is recorded as
+	// MetadataConcurrency.
+	Concurrency int
+	// SummaryOnly stops after printing the load summary: no graph is
+	// extracted or written, so no OutputFile is needed. Packages are loaded
+	// without parsing or type-checking unless SymbolIndexFile needs them, so
+	// the package and file counts match a full run but syntax and type
+	// errors go unreported.
+	SummaryOnly bool
+	// CPUProfile and MemProfile, when set, receive pprof CPU and heap
+	// profiles of the run.
+	CPUProfile string
+	MemProfile string
+
+	stdin     *os.File
+	output    io.Writer
+	errOutput io.Writer
 }
 
 func NewParseCommand(args []string) (*ParseCommand, error) {
 	flagSet := flag.NewFlagSet("parse", flag.ContinueOnError)
 
-	outputFile := flagSet.String("output", "", "Output file path (required)")
+	outputFile := flagSet.String("output", "", "Output file path (required unless --summary-only)")
 	includeTests := flagSet.Bool("include-tests", true, "Include test files in parsing")
+	format := flagSet.String("format", "", fmt.Sprintf("Output format: %s (default: inferred from --output extension, else graphml)",
+		strings.Join(graph.FormatNames(), ", ")))
+	graphTitle := flagSet.String("graph-title", "", "Graph title embedded in the output (default: module name, and the SOURCE_DATE_EPOCH time when set)")
+	dotOmitLabels := flagSet.Bool("dot-omit-labels", false, "Emit only node IDs without labels in DOT output")
+	dotClusters := flagSet.Bool("dot-cluster-modules", false, "Group DOT nodes into one cluster per module, labelled with its package count")
+	dotRankSame := flagSet.Bool("dot-rank-same", false, "Keep DOT package nodes sharing a rank, such as import cycle members, on one row")
+	clusterStats := flagSet.Bool("show-cluster-stats", false, "Add total LOC and average coupling to DOT cluster labels (implies --dot-cluster-modules)")
+	showEdgeLabels := flagSet.Bool("show-edge-labels", false, "Label TGF edges with the aliases the imports are declared under")
+	jsonIndent := flagSet.String("json-indent", formatter.DefaultJSONIndent, "Indentation for pretty-printed JSON output")
+	jsonCompact := flagSet.Bool("json-compact", false, "Write JSON output on a single line")
+	hideProgressBar := flagSet.Bool("hide-progress-bar", false, "Never draw the extraction progress bar, even on a terminal")
+	hideTestEdges := flagSet.Bool("hide-test-edges", false, "Leave test-only edges out of the output, keeping only production dependencies")
+	errorFormat := flagSet.String("error-format", errorFormatText,
+		fmt.Sprintf("How package errors are reported on stderr: %s", strings.Join(errorFormats, ", ")))
+	includeTodos := flagSet.Bool("include-todos", false, "Record each TODO/FIXME/HACK/XXX comment (text, author, file:line) on its package node")
+	todoMarkers := flagSet.String("todo-markers", "", "Comma-separated comment markers to look for instead of TODO,FIXME,HACK,XXX")
+	sortNodes := flagSet.Bool("sort-nodes", true, "Sort GraphML and DOT nodes by ID and edges by source and target for byte-stable output")
+	packagesFromStdin := flagSet.Bool("packages-from-stdin", false, "Read newline-separated package patterns from stdin and load only those instead of ./...")
+	calls := flagSet.Bool("calls", false,
+		"Add a node per function and method, with call edges between them and fan_in/fan_out/test_fan_in attributes")
+	symbolIndexFile := flagSet.String("write-symbol-index", "", "Also write a JSON index of every declared symbol to this file path")
+	changeFrequency := flagSet.Bool("change-frequency", false, "Record on each package the number of git commits touching its files")
+	changeFrequencySince := flagSet.String("since", "", "Count only commits after this git ref for --change-frequency (implies --change-frequency)")
+	importsOnly := flagSet.Bool("imports-only", false,
+		"Load only what package import edges need: no type-checking, and no parsing or source metrics unless tests are included")
+	concurrency := flagSet.Int("concurrency", runtime.GOMAXPROCS(0),
+		"Maximum number of packages loaded and extracted in parallel, to bound memory use")
+	noCache := flagSet.Bool("no-cache", false, "Extract every package instead of reusing the extraction cache of earlier runs")
+	paranoidCache := flagSet.Bool("paranoid-cache", false,
+		"Hash every file to find unchanged packages in the extraction cache, even when its size and modification time are unchanged")
+	checkGoVersion := flagSet.String("check-go-version", "", "Warn about packages whose module requires a Go version newer than this one, e.g. go1.21")
+	verbose := flagSet.Bool("verbose", false, "List the packages involved in import cycles and near-cycles")
+	var cpuProfile, memProfile string
+	flagSet.Var(&profileFlag{cpuOut: &cpuProfile, memOut: &memProfile}, "profile",
+		"Write a pprof profile of the run, cpu=file or mem=file (heap, taken at the end); repeatable")
+	summaryOnly := flagSet.Bool("summary-only", false,
+		"Print the package, file, module and error counts after loading and stop, without extracting or writing a graph (--output is not needed); sources are neither parsed nor type-checked, so only go list errors are counted")
 
 	if err := flagSet.Parse(args); err != nil {
-		return nil, err
+		return nil, newUsageError(err)
 	}
 
 	directoryArgument := ""
@@ -39,6 +155,47 @@ func NewParseCommand(args []string) (*ParseCommand, error) {
 		TargetDirectory: targetDirectory,
 		OutputFile:      *outputFile,
 		IncludeTests:    *includeTests,
+		Format:          *format,
+		DOTOmitLabels:   *dotOmitLabels,
+		DOTClusters:     *dotClusters,
+		ClusterStats:    *clusterStats,
+		DOTRankSame:     *dotRankSame,
+		ShowEdgeLabels:  *showEdgeLabels,
+		GraphTitle:      *graphTitle,
+		JSONIndent:      *jsonIndent,
+		JSONCompact:     *jsonCompact,
+		Verbose:         *verbose,
+		HideProgressBar: *hideProgressBar,
+		HideTestEdges:   *hideTestEdges,
+		ErrorFormat:     *errorFormat,
+		SortNodes:       *sortNodes,
+		IncludeTodos:    *includeTodos,
+		SymbolIndexFile: *symbolIndexFile,
+		Calls:           *calls,
+
+		PackagesFromStdin:    *packagesFromStdin,
+		ChangeFrequency:      *changeFrequency || *changeFrequencySince != "",
+		ChangeFrequencySince: *changeFrequencySince,
+		CheckGoVersion:       *checkGoVersion,
+		ImportsOnly:          *importsOnly,
+		NoCache:              *noCache,
+		ParanoidCache:        *paranoidCache,
+		Concurrency:          *concurrency,
+		SummaryOnly:          *summaryOnly,
+		CPUProfile:           cpuProfile,
+		MemProfile:           memProfile,
+		stdin:                os.Stdin,
+		output:               os.Stdout,
+		errOutput:            os.Stderr,
+	}
+	if parseCommand.CheckGoVersion != "" {
+		parseCommand.CheckGoVersion = normalizeGoVersion(parseCommand.CheckGoVersion)
+		if parseCommand.CheckGoVersion == "" {
+			return nil, usageErrorf("invalid --check-go-version %q (want a Go version such as go1.21)", *checkGoVersion)
+		}
+	}
+	if *todoMarkers != "" {
+		parseCommand.TodoMarkers = strings.Split(*todoMarkers, ",")
 	}
 
 	if err := parseCommand.Validate(); err != nil {
@@ -49,31 +206,186 @@ func NewParseCommand(args []string) (*ParseCommand, error) {
 }
 
 func (pc *ParseCommand) Validate() error {
-	if pc.OutputFile == "" {
-		return fmt.Errorf("--output flag requires a file path")
+	if pc.OutputFile == "" && !pc.SummaryOnly {
+		return usageErrorf("--output flag requires a file path")
+	}
+	if _, err := pc.outputFormat(); err != nil {
+		return err
+	}
+	if !slices.Contains(errorFormats, pc.ErrorFormat) {
+		return usageErrorf("unknown error format '%s' (available: %s)", pc.ErrorFormat, strings.Join(errorFormats, ", "))
+	}
+	if pc.Concurrency < 1 {
+		return usageErrorf("--concurrency must be at least 1, got %d", pc.Concurrency)
+	}
+	if pc.ImportsOnly && (pc.IncludeTodos || pc.SymbolIndexFile != "" || pc.Calls) {
+		return usageErrorf("--imports-only does not parse sources, which --include-todos, --write-symbol-index and --calls need")
+	}
+	if pc.ParanoidCache && pc.NoCache {
+		return usageErrorf("--paranoid-cache has no effect with --no-cache")
+	}
+	if pc.ChangeFrequency {
+		if _, inRepo := pc.TargetDirectory.IsInGitRepo(); !inRepo {
+			return usageErrorf("--change-frequency counts git commits, but %s is not in a git repository", pc.TargetDirectory.Path)
+		}
+	}
+	// Reading a terminal would wait for input nobody is going to type.
+	if pc.PackagesFromStdin && pc.stdin != nil && isTerminal(pc.stdin) {
+		return usageErrorf("--packages-from-stdin needs package patterns piped to stdin, not a terminal")
 	}
 	return nil
 }
 
+// outputFormat resolves the registered format from --format, falling back to
+// the output file extension and finally to GraphML.
+func (pc *ParseCommand) outputFormat() (graph.Format, error) {
+	if pc.Format != "" {
+		outputFormat, found := graph.LookupFormat(pc.Format)
+		if !found {
+			return graph.Format{}, usageErrorf("unsupported --format %q (supported: %s)",
+				pc.Format, strings.Join(graph.FormatNames(), ", "))
+		}
+		return outputFormat, nil
+	}
+	if outputFormat, found := graph.FormatForFile(pc.OutputFile); found {
+		return outputFormat, nil
+	}
+	outputFormat, _ := graph.LookupFormat(defaultFormat)
+	return outputFormat, nil
+}
+
+// newEncoder creates the encoder for the resolved format and applies the
+// format-specific flags to the built-in encoders. JSON written to a terminal
+// is always pretty-printed unless --json-compact is set.
+func (pc *ParseCommand) newEncoder(toTerminal bool) (graph.Encoder, error) {
+	outputFormat, err := pc.outputFormat()
+	if err != nil {
+		return nil, err
+	}
+
+	encoder := outputFormat.NewEncoder()
+	switch builtinFormatter := encoder.(type) {
+	case *formatter.GraphMLFormatter:
+		builtinFormatter.SortNodes = pc.SortNodes
+	case *formatter.DOTFormatter:
+		builtinFormatter.SortNodes = pc.SortNodes
+		builtinFormatter.OmitLabels = pc.DOTOmitLabels
+		builtinFormatter.ClusterByModule = pc.DOTClusters
+		builtinFormatter.ShowClusterStats = pc.ClusterStats
+		builtinFormatter.RankSame = pc.DOTRankSame
+	case *formatter.TGFFormatter:
+		builtinFormatter.ShowEdgeLabels = pc.ShowEdgeLabels
+	case *formatter.JSONFormatter:
+		builtinFormatter.Indent = pc.JSONIndent
+		builtinFormatter.Compact = pc.JSONCompact
+		if toTerminal && builtinFormatter.Indent == "" {
+			builtinFormatter.Indent = formatter.DefaultJSONIndent
+		}
+	}
+	return encoder, nil
+}
+
 func (pc *ParseCommand) Execute() error {
-	pkgs, errorCount, err := parser.Load(pc.TargetDirectory.Path, pc.IncludeTests)
+	stopProfiles, err := profileSetup(pc.CPUProfile, pc.MemProfile)
+	if err != nil {
+		return err
+	}
+	defer stopProfiles()
+
+	var patterns []string
+	if pc.PackagesFromStdin {
+		var err error
+		if patterns, err = readPackagePatterns(pc.stdin); err != nil {
+			return err
+		}
+	}
+	includeTests := loadTestFiles(pc.IncludeTests, pc.testFileUses())
+	if pc.IncludeTests && !includeTests && pc.Verbose {
+		fmt.Fprintln(pc.errOutput, "Skipping test files: the output hides test edges and holds nothing else test files add to")
+	}
+	features := pc.loadFeatures(includeTests)
+	features.Concurrency = pc.Concurrency
+	pkgs, err := parser.LoadFeatures(pc.TargetDirectory.Path, includeTests, features, patterns...)
 	if err != nil {
 		return err
 	}
+	errorCount := printErrors(pkgs, pc.ErrorFormat, pc.errOutput)
+	if pc.SymbolIndexFile != "" {
+		if err := writeSymbolIndex(pc.SymbolIndexFile, parser.SymbolIndex(pkgs)); err != nil {
+			return err
+		}
+	}
+
+	modulePath := printLoadSummary(pc.output, pkgs, features.Syntax || features.Types)
+	// Only text output gets a summary line; json and gcc stay machine-readable.
+	if errorCount > 0 && pc.ErrorFormat == errorFormatText {
+		fmt.Fprintf(pc.errOutput, "Encountered %d parse errors\n", errorCount)
+	}
+	if pc.SummaryOnly {
+		return nil
+	}
 
-	totalPackages := len(pkgs)
+	extractContext, finishProgress := pc.progressContext()
+	extractContext = extract.WithConcurrency(extractContext, pc.Concurrency)
+	if !pc.NoCache {
+		extractContext = withExtractionCache(extractContext, pc.ParanoidCache)
+	}
+	extractors := withPackageExtractor(extract.Extractors(),
+		&extract.PackageExtractor{AnnotationMarkers: pc.TodoMarkers, IncludeAnnotations: pc.IncludeTodos})
+	if pc.Calls {
+		extractors = append(extractors, &extract.CallExtractor{})
+	}
+	dependencyGraph, failures, err := buildGraph(extractContext, pkgs, extractors)
+	// End the progress bar's line first so warnings do not land on it.
+	finishProgress()
+	if err != nil {
+		return err
+	}
+	warnFailures(failures)
+	// Both analyses resolve identifiers, which imports-only runs do not load.
+	if !pc.ImportsOnly {
+		analyzer.ApplyInitSideEffects(dependencyGraph, analyzer.FindInitSideEffects(pkgs, analyzer.DefaultInitEffectDepth))
+		analyzer.ApplyMissingConstructors(dependencyGraph, analyzer.FindMissingConstructors(pkgs))
+	}
+	if pc.ChangeFrequency {
+		repoRoot, _ := pc.TargetDirectory.IsInGitRepo()
+		if err := graph.EnrichWithGitFrequency(dependencyGraph, repoRoot, pc.ChangeFrequencySince); err != nil {
+			return err
+		}
+	}
+	summarizeCycles(pc.output, dependencyGraph, pc.Verbose, includeTests)
+	dependencyGraph.SetMetadata(MetadataConcurrency, strconv.Itoa(pc.Concurrency))
+	if pc.CheckGoVersion != "" {
+		checkGoVersions(pc.errOutput, dependencyGraph, pc.CheckGoVersion)
+	}
+	if pc.HideTestEdges {
+		dependencyGraph.RemoveEdges(func(edge *graph.Edge) bool { return edge.IsTestOnly })
+	}
+	dependencyGraph.Title = pc.GraphTitle
+	if dependencyGraph.Title == "" {
+		dependencyGraph.Title = defaultGraphTitle(modulePath, os.Getenv("SOURCE_DATE_EPOCH"))
+	}
+
+	return pc.writeOutput(dependencyGraph)
+}
+
+// printLoadSummary lists the loaded packages with their files and error
+// counts, then the module, package and file totals and the import alias
+// counts, and returns the module path it reports. Import aliases are only
+// counted when the packages were loaded with syntax.
+func printLoadSummary(writer io.Writer, pkgs []*packages.Package, withSyntax bool) string {
 	totalFiles := 0
 	var modulePath string
 
 	for _, pkg := range pkgs {
-		fmt.Printf("\nPackage: %s\n", pkg.PkgPath)
-		fmt.Printf("  Name: %s\n", pkg.Name)
-		fmt.Printf("  Files (%d):\n", len(pkg.GoFiles))
+		fmt.Fprintf(writer, "\nPackage: %s\n", pkg.PkgPath)
+		fmt.Fprintf(writer, "  Name: %s\n", pkg.Name)
+		fmt.Fprintf(writer, "  Files (%d):\n", len(pkg.GoFiles))
 		for _, file := range pkg.GoFiles {
-			fmt.Printf("    - %s\n", file)
+			fmt.Fprintf(writer, "    - %s\n", file)
 		}
 		if len(pkg.Errors) > 0 {
-			fmt.Printf("  Errors: %d\n", len(pkg.Errors))
+			fmt.Fprintf(writer, "  Errors: %d\n", len(pkg.Errors))
 		}
 
 		totalFiles += len(pkg.GoFiles)
@@ -86,14 +398,135 @@ func (pc *ParseCommand) Execute() error {
 		}
 	}
 
-	fmt.Printf("\n")
+	fmt.Fprintf(writer, "\n")
 	if modulePath != "" {
-		fmt.Printf("Module: %s\n", modulePath)
+		fmt.Fprintf(writer, "Module: %s\n", modulePath)
+	}
+	fmt.Fprintf(writer, "Loaded %d packages, parsed %d files\n", len(pkgs), totalFiles)
+	if !withSyntax {
+		fmt.Fprintf(writer, "Import paths under several names: n/a, shadowing aliases: n/a\n")
+		return modulePath
+	}
+	aliases := analyzer.CheckImportAliases(pkgs, nil)
+	fmt.Fprintf(writer, "Import paths under several names: %d, shadowing aliases: %d\n", len(aliases.Inconsistent), len(aliases.Shadowing))
+	return modulePath
+}
+
+// loadFeatures returns what the packages must be loaded with. Imports-only
+// runs still parse when tests are included: telling test-only imports apart
+// needs the import declarations of each file.
+func (pc *ParseCommand) loadFeatures(includeTests bool) parser.Features {
+	if pc.SummaryOnly && pc.SymbolIndexFile == "" {
+		return parser.Features{}
+	}
+	if pc.ImportsOnly {
+		return parser.Features{Syntax: includeTests}
 	}
-	fmt.Printf("Loaded %d packages, parsed %d files\n", totalPackages, totalFiles)
-	if errorCount > 0 {
-		fmt.Fprintf(os.Stderr, "Encountered %d parse errors\n", errorCount)
+	return parser.AllFeatures
+}
+
+// structureOnlyFormats draw nodes by name and edges by kind, so nothing they
+// write comes from test files once test-only edges are hidden. TGF edge
+// labels are the exception: they list import aliases, which test files
+// declare too.
+var structureOnlyFormats = map[string]bool{"tgf": true, "d2": true, "excalidraw": true}
+
+// testFileUses lists what the parse output takes from test files: the
+// --summary-only file lists and change frequencies count them, and every
+// format but the structure-only ones writes file counts or metrics over them.
+// The load summary of a full run lists whatever was loaded.
+func (pc *ParseCommand) testFileUses() testFileUses {
+	outputFormat, _ := pc.outputFormat()
+	structureOnly := structureOnlyFormats[outputFormat.Name] && !(outputFormat.Name == "tgf" && pc.ShowEdgeLabels)
+	return testFileUses{
+		TestEdges:    !pc.HideTestEdges,
+		Declarations: pc.Calls || pc.IncludeTodos || pc.SymbolIndexFile != "",
+		FileData:     pc.SummaryOnly || pc.ChangeFrequency || !structureOnly,
 	}
+}
 
+// writeSymbolIndex writes index to filePath as indented JSON, symbols sorted
+// by name.
+func writeSymbolIndex(filePath string, index map[string][]parser.SymbolInfo) error {
+	content, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filePath, append(content, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write symbol index: %w", err)
+	}
 	return nil
 }
+
+// readPackagePatterns returns the non-blank lines of reader, trimmed. Reading
+// no pattern at all is an error: loading the default ./... instead would
+// silently graph more than the caller asked for.
+func readPackagePatterns(reader io.Reader) ([]string, error) {
+	var patterns []string
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		if pattern := strings.TrimSpace(scanner.Text()); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read package patterns from stdin: %w", err)
+	}
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("no package patterns on stdin")
+	}
+	return patterns, nil
+}
+
+// progressContext attaches a progress bar on stderr to the extraction context,
+// unless --hide-progress-bar is set or stderr is not a terminal, so logs and
+// pipes never receive carriage-return redraws. The returned func ends the bar.
+func (pc *ParseCommand) progressContext() (context.Context, func()) {
+	ctx := context.Background()
+	if pc.HideProgressBar || !isTerminal(os.Stderr) {
+		return ctx, func() {}
+	}
+	bar := newProgressBar(os.Stderr, "packages")
+	return extract.WithProgress(ctx, bar.Update), bar.Finish
+}
+
+// defaultGraphTitle names a graph after its module, adding the time
+// sourceDateEpoch holds in Unix seconds, if any, so that repeated runs write
+// the same title.
+func defaultGraphTitle(modulePath, sourceDateEpoch string) string {
+	if modulePath == "" {
+		modulePath = "codegraph"
+	}
+	seconds, err := strconv.ParseInt(sourceDateEpoch, 10, 64)
+	if err != nil {
+		return modulePath
+	}
+	return fmt.Sprintf("%s %s", modulePath, time.Unix(seconds, 0).UTC().Format(time.RFC3339))
+}
+
+func (pc *ParseCommand) writeOutput(dependencyGraph *graph.Graph) error {
+	outputFile, err := os.Create(pc.OutputFile)
+	if err != nil {
+		return fmt.Errorf("failed to create output file '%s': %w", pc.OutputFile, err)
+	}
+
+	encoder, err := pc.newEncoder(isTerminal(outputFile))
+	if err != nil {
+		outputFile.Close()
+		return err
+	}
+
+	if err := encoder.Encode(outputFile, dependencyGraph); err != nil {
+		outputFile.Close()
+		return fmt.Errorf("failed to write output file '%s': %w", pc.OutputFile, err)
+	}
+
+	return outputFile.Close()
+}
+
+// isTerminal reports whether file is a terminal, e.g. when --output is
+// /dev/stdout on an interactive shell. Other character devices such as
+// /dev/null are not.
+func isTerminal(file *os.File) bool {
+	return term.IsTerminal(int(file.Fd()))
+}
@@ -1,17 +1,33 @@
 package cli
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+
+	"github.com/Desgue/codegraph/extract"
+	"github.com/Desgue/codegraph/formatter"
+	"github.com/Desgue/codegraph/graph"
+	"github.com/Desgue/codegraph/parser"
+	"github.com/Desgue/codegraph/path"
 )
 
 func TestNewParseCommand(t *testing.T) {
 	validTests := []struct {
-		name             string
-		setup            func(t *testing.T) []string
-		wantOutputFile   string
-		wantIncludeTests bool
+		name              string
+		setup             func(t *testing.T) []string
+		wantOutputFile    string
+		wantIncludeTests  bool
+		wantFormat        string
+		wantDOTOmitLabels bool
 	}{
 		{
 			name: "valid minimal args with current directory",
@@ -45,6 +61,16 @@ func TestNewParseCommand(t *testing.T) {
 			wantOutputFile:   "out.graphml",
 			wantIncludeTests: false,
 		},
+		{
+			name: "valid args with dot format and omitted labels",
+			setup: func(t *testing.T) []string {
+				return []string{"--output", "out.dot", "--format", "dot", "--dot-omit-labels"}
+			},
+			wantOutputFile:    "out.dot",
+			wantIncludeTests:  true,
+			wantFormat:        "dot",
+			wantDOTOmitLabels: true,
+		},
 		{
 			name: "valid args with relative directory",
 			setup: func(t *testing.T) []string {
@@ -81,6 +107,12 @@ func TestNewParseCommand(t *testing.T) {
 			if cmd.IncludeTests != tt.wantIncludeTests {
 				t.Errorf("IncludeTests = %v, want %v", cmd.IncludeTests, tt.wantIncludeTests)
 			}
+			if cmd.Format != tt.wantFormat {
+				t.Errorf("Format = %q, want %q", cmd.Format, tt.wantFormat)
+			}
+			if cmd.DOTOmitLabels != tt.wantDOTOmitLabels {
+				t.Errorf("DOTOmitLabels = %v, want %v", cmd.DOTOmitLabels, tt.wantDOTOmitLabels)
+			}
 			if cmd.TargetDirectory == nil {
 				t.Fatal("expected TargetDirectory to be set")
 			}
@@ -88,21 +120,25 @@ func TestNewParseCommand(t *testing.T) {
 	}
 
 	tests := []struct {
-		name string
-		args []string
-		setup func(t *testing.T) []string
+		name    string
+		args    []string
+		setup   func(t *testing.T) []string
+		wantErr error
 	}{
 		{
-			name: "missing output flag returns error",
-			args: []string{},
+			name:    "missing output flag returns error",
+			args:    []string{},
+			wantErr: ErrUsage,
 		},
 		{
-			name: "empty output flag returns error",
-			args: []string{"--output", ""},
+			name:    "empty output flag returns error",
+			args:    []string{"--output", ""},
+			wantErr: ErrUsage,
 		},
 		{
-			name: "non-existent directory returns error",
-			args: []string{"--output", "out.graphml", "/non/existent/path"},
+			name:    "non-existent directory returns error",
+			args:    []string{"--output", "out.graphml", "/non/existent/path"},
+			wantErr: path.ErrNotExist,
 		},
 		{
 			name: "file instead of directory returns error",
@@ -114,14 +150,34 @@ func TestNewParseCommand(t *testing.T) {
 				}
 				return []string{"--output", "out.graphml", tempFile}
 			},
+			wantErr: path.ErrNotDirectory,
+		},
+		{
+			name:    "unknown flag returns error",
+			args:    []string{"--output", "out.graphml", "--unknown-flag"},
+			wantErr: ErrUsage,
 		},
 		{
-			name: "unknown flag returns error",
-			args: []string{"--output", "out.graphml", "--unknown-flag"},
+			name:    "invalid boolean syntax returns error",
+			args:    []string{"--output", "out.graphml", "--include-tests=invalid"},
+			wantErr: ErrUsage,
 		},
 		{
-			name: "invalid boolean syntax returns error",
-			args: []string{"--output", "out.graphml", "--include-tests=invalid"},
+			name:    "unsupported format returns error",
+			args:    []string{"--output", "out.graphml", "--format", "svg"},
+			wantErr: ErrUsage,
+		},
+		{
+			name:    "unknown error format returns error",
+			args:    []string{"--output", "out.graphml", "--error-format", "xml"},
+			wantErr: ErrUsage,
+		},
+		{
+			name: "change frequency outside a git repository returns error",
+			setup: func(t *testing.T) []string {
+				return []string{"--output", "out.graphml", "--change-frequency", t.TempDir()}
+			},
+			wantErr: ErrUsage,
 		},
 	}
 
@@ -133,8 +189,8 @@ func TestNewParseCommand(t *testing.T) {
 			}
 
 			_, err := NewParseCommand(args)
-			if err == nil {
-				t.Fatal("expected error but got none")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error matching %v, got %v", tt.wantErr, err)
 			}
 		})
 	}
@@ -172,6 +228,17 @@ func TestParseCommand_Validate(t *testing.T) {
 			},
 			wantError: true,
 		},
+		{
+			name: "summary only needs no output file",
+			setup: func(t *testing.T) *ParseCommand {
+				cmd, err := NewParseCommand([]string{"--summary-only", t.TempDir()})
+				if err != nil {
+					t.Fatalf("setup failed: %v", err)
+				}
+				return cmd
+			},
+			wantError: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -207,7 +274,8 @@ func TestParseCommand_Execute(t *testing.T) {
 			t.Fatalf("Failed to create main.go: %v", err)
 		}
 
-		cmd, err := NewParseCommand([]string{"--output", "out.graphml", testDir})
+		outputFile := filepath.Join(t.TempDir(), "out.graphml")
+		cmd, err := NewParseCommand([]string{"--output", outputFile, testDir})
 		if err != nil {
 			t.Fatalf("setup failed: %v", err)
 		}
@@ -215,6 +283,107 @@ func TestParseCommand_Execute(t *testing.T) {
 		if err := cmd.Execute(); err != nil {
 			t.Errorf("expected no error from Execute, got %v", err)
 		}
+
+		content, err := os.ReadFile(outputFile)
+		if err != nil {
+			t.Fatalf("expected output file to be written: %v", err)
+		}
+		if !strings.Contains(string(content), `<node id="testexec">`) {
+			t.Errorf("expected GraphML output to contain package node, got:\n%s", content)
+		}
+	})
+
+	t.Run("hide-test-edges drops test-only edges", func(t *testing.T) {
+		testDir := writeTestModule(t, map[string]string{
+			"go.mod":      "module testedges\n\ngo 1.24\n",
+			"a/a.go":      "package a\n\nimport _ \"testedges/b\"\n",
+			"a/a_test.go": "package a\n\nimport _ \"testedges/c\"\n",
+			"b/b.go":      "package b\n",
+			"c/c.go":      "package c\n",
+		})
+
+		for _, hide := range []bool{false, true} {
+			outputFile := filepath.Join(t.TempDir(), "out.dot")
+			cmd, err := NewParseCommand([]string{"--output", outputFile, fmt.Sprintf("--hide-test-edges=%v", hide), testDir})
+			if err != nil {
+				t.Fatalf("setup failed: %v", err)
+			}
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			content, err := os.ReadFile(outputFile)
+			if err != nil {
+				t.Fatalf("expected output file to be written: %v", err)
+			}
+			hasTestEdge := strings.Contains(string(content), `"testedges/a" -> "testedges/c" [style=dashed,color=grey];`)
+			if hasTestEdge == hide || !strings.Contains(string(content), `"testedges/a" -> "testedges/b";`) {
+				t.Errorf("--hide-test-edges=%v: unexpected DOT output:\n%s", hide, content)
+			}
+		}
+	})
+
+	t.Run("writes DOT output without labels", func(t *testing.T) {
+		testDir := t.TempDir()
+
+		goMod := filepath.Join(testDir, "go.mod")
+		modContent := "module testdot\n\ngo 1.24\n"
+		if err := os.WriteFile(goMod, []byte(modContent), 0644); err != nil {
+			t.Fatalf("Failed to create go.mod: %v", err)
+		}
+
+		mainFile := filepath.Join(testDir, "main.go")
+		mainContent := "package main\n\nfunc main() {}\n"
+		if err := os.WriteFile(mainFile, []byte(mainContent), 0644); err != nil {
+			t.Fatalf("Failed to create main.go: %v", err)
+		}
+
+		outputFile := filepath.Join(t.TempDir(), "out.dot")
+		cmd, err := NewParseCommand([]string{"--output", outputFile, "--format", "dot", "--dot-omit-labels",
+			"--graph-title", `My "Service"`, testDir})
+		if err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("expected no error from Execute, got %v", err)
+		}
+
+		content, err := os.ReadFile(outputFile)
+		if err != nil {
+			t.Fatalf("expected output file to be written: %v", err)
+		}
+		if string(content) != "digraph \"My \\\"Service\\\"\" {\n  // codegraph schema 1.0\n  \"testdot\";\n}\n" {
+			t.Errorf("unexpected DOT output:\n%s", content)
+		}
+	})
+
+	t.Run("show-cluster-stats enables DOT module clusters", func(t *testing.T) {
+		cmd, err := NewParseCommand([]string{"--output", "out.dot", "--show-cluster-stats", t.TempDir()})
+		if err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+		encoder, err := cmd.newEncoder(false)
+		if err != nil {
+			t.Fatalf("newEncoder() error = %v", err)
+		}
+		if dotFormatter, isDOT := encoder.(*formatter.DOTFormatter); !isDOT || !dotFormatter.ShowClusterStats {
+			t.Errorf("expected a DOT encoder with cluster stats, got %#v", encoder)
+		}
+	})
+
+	t.Run("show-edge-labels labels TGF edges", func(t *testing.T) {
+		cmd, err := NewParseCommand([]string{"--output", "out.tgf", "--show-edge-labels", t.TempDir()})
+		if err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+		encoder, err := cmd.newEncoder(false)
+		if err != nil {
+			t.Fatalf("newEncoder() error = %v", err)
+		}
+		if tgfFormatter, isTGF := encoder.(*formatter.TGFFormatter); !isTGF || !tgfFormatter.ShowEdgeLabels {
+			t.Errorf("expected a TGF encoder with edge labels, got %#v", encoder)
+		}
 	})
 
 	t.Run("handles syntax errors gracefully", func(t *testing.T) {
@@ -232,7 +401,7 @@ func TestParseCommand_Execute(t *testing.T) {
 			t.Fatalf("Failed to create invalid.go: %v", err)
 		}
 
-		cmd, err := NewParseCommand([]string{"--output", "out.graphml", testDir})
+		cmd, err := NewParseCommand([]string{"--output", filepath.Join(t.TempDir(), "out.graphml"), testDir})
 		if err != nil {
 			t.Fatalf("setup failed: %v", err)
 		}
@@ -243,3 +412,480 @@ func TestParseCommand_Execute(t *testing.T) {
 		}
 	})
 }
+
+func TestParseCommand_OutputFormat(t *testing.T) {
+	tests := []struct {
+		name       string
+		format     string
+		outputFile string
+		want       string
+	}{
+		{name: "explicit format wins over extension", format: "dot", outputFile: "out.graphml", want: "dot"},
+		{name: "format inferred from extension", outputFile: "out.gv", want: "dot"},
+		{name: "extension match is case insensitive", outputFile: "OUT.GRAPHML", want: "graphml"},
+		{name: "unknown extension defaults to graphml", outputFile: "out.txt", want: "graphml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &ParseCommand{Format: tt.format, OutputFile: tt.outputFile}
+			outputFormat, err := cmd.outputFormat()
+			if err != nil {
+				t.Fatalf("outputFormat() error = %v", err)
+			}
+			if outputFormat.Name != tt.want {
+				t.Errorf("outputFormat() = %q, want %q", outputFormat.Name, tt.want)
+			}
+		})
+	}
+}
+
+type lineCountEncoder struct{}
+
+func (lineCountEncoder) Encode(writer io.Writer, g *graph.Graph) error {
+	_, err := fmt.Fprintf(writer, "nodes=%d\n", len(g.Nodes()))
+	return err
+}
+
+func TestParseCommand_Execute_RegisteredFormat(t *testing.T) {
+	err := graph.RegisterFormat(graph.Format{
+		Name:       "linecount-test",
+		Extensions: []string{".linecount"},
+		NewEncoder: func() graph.Encoder { return lineCountEncoder{} },
+	})
+	if err != nil {
+		t.Fatalf("RegisterFormat() error = %v", err)
+	}
+
+	testDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(testDir, "go.mod"), []byte("module testplugin\n\ngo 1.24\n"), 0644); err != nil {
+		t.Fatalf("Failed to create go.mod: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(testDir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatalf("Failed to create main.go: %v", err)
+	}
+
+	outputFile := filepath.Join(t.TempDir(), "graph.linecount")
+	cmd, err := NewParseCommand([]string{"--output", outputFile, testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	content, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("expected output file: %v", err)
+	}
+	if string(content) != "nodes=1\n" {
+		t.Errorf("unexpected output from registered format: %q", content)
+	}
+}
+
+func TestDefaultGraphTitle(t *testing.T) {
+	tests := []struct {
+		modulePath, sourceDateEpoch string
+		want                        string
+	}{
+		{modulePath: "example.com/mod", want: "example.com/mod"},
+		{modulePath: "", want: "codegraph"},
+		{modulePath: "example.com/mod", sourceDateEpoch: "1760877000", want: "example.com/mod 2025-10-19T12:30:00Z"},
+		{modulePath: "", sourceDateEpoch: "1760877000", want: "codegraph 2025-10-19T12:30:00Z"},
+		{modulePath: "example.com/mod", sourceDateEpoch: "yesterday", want: "example.com/mod"},
+	}
+	for _, tt := range tests {
+		if got := defaultGraphTitle(tt.modulePath, tt.sourceDateEpoch); got != tt.want {
+			t.Errorf("defaultGraphTitle(%q, %q) = %q, want %q", tt.modulePath, tt.sourceDateEpoch, got, tt.want)
+		}
+	}
+}
+
+func TestParseCommand_Execute_Reproducible(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "")
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":          "module testrepro\n\ngo 1.24\n",
+		"store/store.go":  "package store\n\n// TODO: shard.\nfunc Get() int { return 0 }\n",
+		"api/api.go":      "package api\n\nimport \"testrepro/store\"\n\nfunc Serve() int { return store.Get() }\n",
+		"api/api_test.go": "package api\n\nimport \"testing\"\n\nfunc TestServe(t *testing.T) { Serve() }\n",
+	})
+
+	for _, format := range graph.FormatNames() {
+		t.Run(format, func(t *testing.T) {
+			var outputs [2][]byte
+			for i := range outputs {
+				outputFile := filepath.Join(t.TempDir(), "graph.out")
+				cmd, err := NewParseCommand([]string{"--output", outputFile, "--format", format, "--hide-progress-bar", "--no-cache", "--calls", "--include-todos", testDir})
+				if err != nil {
+					t.Fatalf("setup failed: %v", err)
+				}
+				cmd.output = io.Discard
+				if err := cmd.Execute(); err != nil {
+					t.Fatalf("Execute() error = %v", err)
+				}
+				if outputs[i], err = os.ReadFile(outputFile); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if !bytes.Equal(outputs[0], outputs[1]) {
+				t.Errorf("two parse runs wrote different files:\n%s\n---\n%s", outputs[0], outputs[1])
+			}
+		})
+	}
+}
+
+func TestParseCommand_NewEncoder_JSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		jsonIndent  string
+		jsonCompact bool
+		toTerminal  bool
+		want        formatter.JSONFormatter
+	}{
+		{name: "default indent", jsonIndent: "  ", want: formatter.JSONFormatter{Indent: "  "}},
+		{name: "compact", jsonIndent: "  ", jsonCompact: true, want: formatter.JSONFormatter{Indent: "  ", Compact: true}},
+		{name: "empty indent to file stays compact", jsonIndent: "", want: formatter.JSONFormatter{}},
+		{name: "empty indent to terminal is pretty", jsonIndent: "", toTerminal: true,
+			want: formatter.JSONFormatter{Indent: formatter.DefaultJSONIndent}},
+		{name: "compact to terminal", jsonCompact: true, toTerminal: true,
+			want: formatter.JSONFormatter{Indent: formatter.DefaultJSONIndent, Compact: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &ParseCommand{OutputFile: "out.json", JSONIndent: tt.jsonIndent, JSONCompact: tt.jsonCompact}
+			encoder, err := cmd.newEncoder(tt.toTerminal)
+			if err != nil {
+				t.Fatalf("newEncoder() error = %v", err)
+			}
+			jsonFormatter, ok := encoder.(*formatter.JSONFormatter)
+			if !ok {
+				t.Fatalf("expected *formatter.JSONFormatter, got %T", encoder)
+			}
+			if *jsonFormatter != tt.want {
+				t.Errorf("JSONFormatter = %+v, want %+v", *jsonFormatter, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCommand_NewEncoder_SortNodes(t *testing.T) {
+	for _, args := range [][]string{{"--output", "out.graphml"}, {"--output", "out.graphml", "--sort-nodes=false"}} {
+		cmd, err := NewParseCommand(append(args, t.TempDir()))
+		if err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+		encoder, err := cmd.newEncoder(false)
+		if err != nil {
+			t.Fatalf("newEncoder() error = %v", err)
+		}
+		graphMLFormatter, ok := encoder.(*formatter.GraphMLFormatter)
+		if !ok {
+			t.Fatalf("expected *formatter.GraphMLFormatter, got %T", encoder)
+		}
+		if graphMLFormatter.SortNodes != cmd.SortNodes {
+			t.Errorf("%v: SortNodes = %v, want %v", args, graphMLFormatter.SortNodes, cmd.SortNodes)
+		}
+	}
+}
+
+func TestParseCommand_HideProgressBar(t *testing.T) {
+	cmd, err := NewParseCommand([]string{"--output", "out.graphml", "--hide-progress-bar", t.TempDir()})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !cmd.HideProgressBar {
+		t.Fatal("expected HideProgressBar to be set")
+	}
+
+	ctx, finish := cmd.progressContext()
+	defer finish()
+	if ctx != context.Background() {
+		t.Error("expected no progress reporting when the bar is hidden")
+	}
+}
+
+func TestParseCommand_Execute_PackagesFromStdin(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":     "module teststdin\n\ngo 1.24\n",
+		"api/api.go": "package api\n",
+		"cmd/cmd.go": "package cmd\n",
+	})
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe() error = %v", err)
+	}
+	defer reader.Close()
+	if _, err := writer.WriteString("\n  ./api  \n\n"); err != nil {
+		t.Fatalf("failed to write patterns: %v", err)
+	}
+	writer.Close()
+
+	// The test binary's stdin may be /dev/null, which is a character device.
+	stdin := os.Stdin
+	os.Stdin = reader
+	defer func() { os.Stdin = stdin }()
+
+	outputFile := filepath.Join(t.TempDir(), "out.graphml")
+	cmd, err := NewParseCommand([]string{"--output", outputFile, "--packages-from-stdin", "--hide-progress-bar", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	content, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("expected output file to be written: %v", err)
+	}
+	if !strings.Contains(string(content), `<node id="teststdin/api">`) || strings.Contains(string(content), "teststdin/cmd") {
+		t.Errorf("expected only the piped package in the output, got:\n%s", content)
+	}
+}
+
+func TestParseCommand_Execute_WriteSymbolIndex(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":     "module testsymbols\n\ngo 1.24\n",
+		"api/api.go": "package api\n\ntype Server struct{}\n\nfunc (s *Server) Start() {}\n",
+	})
+	outputDir := t.TempDir()
+	indexFile := filepath.Join(outputDir, "symbols.json")
+
+	cmd, err := NewParseCommand([]string{"--output", filepath.Join(outputDir, "out.graphml"), "--write-symbol-index", indexFile,
+		"--hide-progress-bar", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	content, err := os.ReadFile(indexFile)
+	if err != nil {
+		t.Fatalf("expected the symbol index to be written: %v", err)
+	}
+	var index map[string][]parser.SymbolInfo
+	if err := json.Unmarshal(content, &index); err != nil {
+		t.Fatalf("symbol index is not JSON: %v\n%s", err, content)
+	}
+	start := index["testsymbols/api.Server.Start"]
+	if len(start) != 1 || start[0].Kind != parser.SymbolMethod || start[0].Line != 5 {
+		t.Errorf("Server.Start = %+v, want one method declared on line 5", start)
+	}
+}
+
+func TestParseCommand_Execute_Calls(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":         "module testcalls\n\ngo 1.24\n",
+		"api/api.go":     "package api\n\nimport \"testcalls/store\"\n\nfunc Get() int { return store.Get() }\n",
+		"store/store.go": "package store\n\nfunc Get() int { return 1 }\n",
+	})
+	outputFile := filepath.Join(t.TempDir(), "out.json")
+
+	cmd, err := NewParseCommand([]string{"--output", outputFile, "--calls", "--hide-progress-bar", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	g, err := readGraphFile(outputFile)
+	if err != nil {
+		t.Fatalf("readGraphFile() error = %v", err)
+	}
+	storeGet := graph.FuncID("testcalls/store", "", "Get")
+	calls := g.Neighbors(graph.FuncID("testcalls/api", "", "Get"), graph.Outgoing, []graph.EdgeKind{graph.EdgeCall})
+	if len(calls) != 1 || calls[0] != storeGet {
+		t.Errorf("api.Get calls %v, want [%s]", calls, storeGet)
+	}
+	if node, found := g.Node(storeGet); !found || node.Attributes[extract.AttributeFanIn] != "1" {
+		t.Errorf("expected store.Get with fan_in 1, got %+v", node)
+	}
+
+	_, err = NewParseCommand([]string{"--output", outputFile, "--imports-only", "--calls", testDir})
+	if !errors.Is(err, ErrUsage) {
+		t.Errorf("expected a usage error for --imports-only with --calls, got %v", err)
+	}
+}
+
+func TestReadPackagePatterns_Empty(t *testing.T) {
+	if _, err := readPackagePatterns(strings.NewReader("\n \n")); err == nil {
+		t.Error("expected an error when stdin holds no patterns")
+	}
+}
+
+func TestNewParseCommand_CheckGoVersion(t *testing.T) {
+	cmd, err := NewParseCommand([]string{"--output", "out.graphml", "--check-go-version", "1.21", t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewParseCommand() error = %v", err)
+	}
+	if cmd.CheckGoVersion != "go1.21" {
+		t.Errorf("CheckGoVersion = %q, want go1.21", cmd.CheckGoVersion)
+	}
+
+	_, err = NewParseCommand([]string{"--output", "out.graphml", "--check-go-version", "latest", t.TempDir()})
+	if !errors.Is(err, ErrUsage) {
+		t.Errorf("expected a usage error for an invalid version, got %v", err)
+	}
+}
+
+func TestParseCommand_Execute_ImportsOnly(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":         "module testimports\n\ngo 1.24\n",
+		"api/api.go":     "package api\n\nimport \"testimports/store\"\n\nfunc Get() int { return store.Get() }\n",
+		"store/store.go": "package store\n\nfunc Get() int { return 1 }\n",
+	})
+	outputFile := filepath.Join(t.TempDir(), "out.json")
+
+	cmd, err := NewParseCommand([]string{"--output", outputFile, "--imports-only", "--include-tests=false", "--hide-progress-bar", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	if features := cmd.loadFeatures(cmd.IncludeTests); features != (parser.Features{}) {
+		t.Errorf("loadFeatures() = %+v, want nothing beyond imports", features)
+	}
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	g, err := readGraphFile(outputFile)
+	if err != nil {
+		t.Fatalf("readGraphFile() error = %v", err)
+	}
+	if edges := g.Edges(); len(edges) != 1 || edges[0].From != "testimports/api" || edges[0].To != "testimports/store" {
+		t.Errorf("expected the api -> store import edge, got %+v", edges)
+	}
+	if node, _ := g.Node("testimports/api"); node.Attributes[extract.AttributeLinesOfCode] != "" {
+		t.Errorf("expected no source metrics without parsing, got %v", node.Attributes)
+	}
+
+	// Test-only imports can only be told apart by parsing the test files.
+	withTests, err := NewParseCommand([]string{"--output", outputFile, "--imports-only", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	if features := withTests.loadFeatures(withTests.IncludeTests); features != (parser.Features{Syntax: true}) {
+		t.Errorf("loadFeatures() with tests = %+v, want syntax only", features)
+	}
+
+	_, err = NewParseCommand([]string{"--output", outputFile, "--imports-only", "--include-todos", testDir})
+	if !errors.Is(err, ErrUsage) {
+		t.Errorf("expected a usage error for --imports-only with --include-todos, got %v", err)
+	}
+}
+
+func TestParseCommand_Execute_Concurrency(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":         "module testconcurrency\n\ngo 1.24\n",
+		"store/store.go": "package store\n",
+	})
+	outputFile := filepath.Join(t.TempDir(), "out.json")
+
+	if _, err := NewParseCommand([]string{"--output", outputFile, "--concurrency", "0", testDir}); !errors.Is(err, ErrUsage) {
+		t.Errorf("expected ErrUsage for --concurrency 0, got %v", err)
+	}
+
+	cmd, err := NewParseCommand([]string{"--output", outputFile, "--hide-progress-bar", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	if cmd.Concurrency != runtime.GOMAXPROCS(0) {
+		t.Errorf("default Concurrency = %d, want GOMAXPROCS %d", cmd.Concurrency, runtime.GOMAXPROCS(0))
+	}
+
+	cmd, err = NewParseCommand([]string{"--output", outputFile, "--concurrency", "2", "--hide-progress-bar", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	g, err := readGraphFile(outputFile)
+	if err != nil {
+		t.Fatalf("readGraphFile() error = %v", err)
+	}
+	if got := g.Metadata[MetadataConcurrency]; got != "2" {
+		t.Errorf("metadata %s = %q, want 2", MetadataConcurrency, got)
+	}
+}
+
+func TestParseCommand_Execute_SummaryOnly(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":              "module testsummary\n\ngo 1.24\n",
+		"api/api.go":          "package api\n\nimport _ \"testsummary/store\"\n",
+		"api/api_test.go":     "package api\n\nimport \"testing\"\n\nfunc TestAPI(t *testing.T) {}\n",
+		"store/store.go":      "package store\n",
+		"store/broken.go":     "package store\n\nfunc Count() int { return \"many\" }\n",
+		"store/store_test.go": "package store_test\n\nimport _ \"testsummary/store\"\n",
+	})
+
+	run := func(t *testing.T, args ...string) (string, string) {
+		t.Helper()
+		cmd, err := NewParseCommand(append(args, "--hide-progress-bar", "--no-cache", testDir))
+		if err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+		var output, errOutput bytes.Buffer
+		cmd.output = &output
+		cmd.errOutput = &errOutput
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		return output.String(), errOutput.String()
+	}
+
+	outputFile := filepath.Join(t.TempDir(), "out.graphml")
+	fullOutput, fullErrors := run(t, "--output", outputFile)
+	summaryOutput, summaryErrors := run(t, "--summary-only")
+
+	// The summary lists the packages and files of a full run. Loading
+	// neither parses nor type-checks, so it counts no import aliases and
+	// misses the type error a full run reports.
+	packageList, _, _ := strings.Cut(fullOutput, "Import paths under several names: 0, shadowing aliases: 0\n")
+	packageList = strings.Replace(packageList, "  Errors: 2\n", "", 1)
+	if want := packageList + "Import paths under several names: n/a, shadowing aliases: n/a\n"; summaryOutput != want {
+		t.Errorf("summary output = %q, want %q", summaryOutput, want)
+	}
+	if !strings.Contains(summaryOutput, "Module: testsummary\nLoaded 3 packages, parsed 5 files\n") {
+		t.Errorf("summary output = %q, want 3 packages and 5 files of module testsummary", summaryOutput)
+	}
+	if summaryErrors != "" || !strings.Contains(fullErrors, "Encountered 2 parse errors") {
+		t.Errorf("summary errors = %q and full run errors = %q, want none and the type error", summaryErrors, fullErrors)
+	}
+
+	// Without tests, imports-only runs load no syntax to count aliases in.
+	importsOnlyOutput, _ := run(t, "--output", outputFile, "--imports-only", "--include-tests=false")
+	if !strings.Contains(importsOnlyOutput, "Loaded 2 packages, parsed 3 files\nImport paths under several names: n/a, shadowing aliases: n/a\n") {
+		t.Errorf("imports-only output = %q, want n/a import alias counts", importsOnlyOutput)
+	}
+}
+
+func TestParseCommand_Execute_Profile(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":     "module testprofile\n\ngo 1.24\n",
+		"api/api.go": "package api\n",
+	})
+	outputDir := t.TempDir()
+	cpuOut, memOut := filepath.Join(outputDir, "cpu.prof"), filepath.Join(outputDir, "mem.prof")
+
+	cmd, err := NewParseCommand([]string{"--summary-only", "--profile", "cpu=" + cpuOut, "--profile", "mem=" + memOut, testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	cmd.output = io.Discard
+	cmd.errOutput = io.Discard
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	for _, file := range []string{cpuOut, memOut} {
+		if info, err := os.Stat(file); err != nil || info.Size() == 0 {
+			t.Errorf("expected a non-empty profile at %s, got %v", file, err)
+		}
+	}
+
+	if _, err := NewParseCommand([]string{"--summary-only", "--profile", "trace=out", testDir}); !errors.Is(err, ErrUsage) {
+		t.Errorf("expected ErrUsage for an unknown profile, got %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/Desgue/codegraph/parser"
+	"golang.org/x/tools/go/packages"
+)
+
+// Formats accepted by the parse command's --error-format flag.
+const (
+	errorFormatText = "text"
+	errorFormatJSON = "json"
+	errorFormatGCC  = "gcc"
+)
+
+var errorFormats = []string{errorFormatText, errorFormatJSON, errorFormatGCC}
+
+// errorKindNames names packages.ErrorKind values in JSON output.
+var errorKindNames = map[packages.ErrorKind]string{
+	packages.UnknownError: "unknown",
+	packages.ListError:    "list",
+	packages.ParseError:   "parse",
+	packages.TypeError:    "type",
+}
+
+// jsonPackageError is one line of --error-format json output.
+type jsonPackageError struct {
+	Package string `json:"package"`
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// printErrors writes the errors of pkgs and their imports, each once, in the
+// given format and returns how many there were. text matches
+// packages.PrintErrors, gcc writes "file:line:col: message" for editors, and
+// json writes one object per line. Errors without a position are attributed
+// to their package in gcc output.
+func printErrors(pkgs []*packages.Package, format string, writer io.Writer) int {
+	packageErrors := parser.ExtractErrors(pkgs)
+	for _, packageError := range packageErrors {
+		switch format {
+		case errorFormatJSON:
+			record := jsonPackageError{Package: packageError.Package, File: packageError.File, Line: packageError.Line,
+				Column: packageError.Col, Kind: errorKindNames[packageError.Kind], Message: packageError.Msg}
+			if content, err := json.Marshal(record); err == nil {
+				fmt.Fprintf(writer, "%s\n", content)
+			}
+		case errorFormatGCC:
+			if packageError.File == "" {
+				fmt.Fprintf(writer, "%s: %s\n", packageError.Package, packageError.Msg)
+				continue
+			}
+			fmt.Fprintf(writer, "%s:%d:%d: %s\n", packageError.File, max(packageError.Line, 1), max(packageError.Col, 1), packageError.Msg)
+		default:
+			fmt.Fprintln(writer, packageError)
+		}
+	}
+	return len(packageErrors)
+}
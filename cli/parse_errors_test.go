@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestPrintErrors(t *testing.T) {
+	dependency := &packages.Package{
+		PkgPath: "example.com/mod/dep",
+		Errors:  []packages.Error{{Pos: "-", Msg: "no Go files", Kind: packages.ListError}},
+	}
+	main := &packages.Package{
+		PkgPath: "example.com/mod/app",
+		Errors:  []packages.Error{{Pos: "/src/app/main.go:3:14", Msg: "expected ')'", Kind: packages.ParseError}},
+		Imports: map[string]*packages.Package{dependency.PkgPath: dependency},
+	}
+	pkgs := []*packages.Package{main, dependency}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: errorFormatText,
+			want:   "-: no Go files\n/src/app/main.go:3:14: expected ')'\n",
+		},
+		{
+			format: errorFormatGCC,
+			want:   "example.com/mod/dep: no Go files\n/src/app/main.go:3:14: expected ')'\n",
+		},
+		{
+			format: errorFormatJSON,
+			want: `{"package":"example.com/mod/dep","kind":"list","message":"no Go files"}` + "\n" +
+				`{"package":"example.com/mod/app","file":"/src/app/main.go","line":3,"column":14,"kind":"parse","message":"expected ')'"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buffer bytes.Buffer
+			if count := printErrors(pkgs, tt.format, &buffer); count != 2 {
+				t.Errorf("printErrors() = %d, want 2", count)
+			}
+			if buffer.String() != tt.want {
+				t.Errorf("printErrors() wrote\n%s\nwant\n%s", buffer.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+)
+
+// Profile kinds accepted by parse's --profile flag.
+const (
+	profileCPU    = "cpu"
+	profileMemory = "mem"
+)
+
+// profileSetup starts a CPU profile written to cpuOut and prepares a heap
+// profile written to memOut, either skipped when empty, and returns a function
+// that finishes both. Both files are created up front so a bad path fails
+// before any work is done. The heap profile is taken after a garbage
+// collection when stop runs, and failures to write it are reported on stderr.
+func profileSetup(cpuOut, memOut string) (stop func(), err error) {
+	var cpuFile, memFile *os.File
+	if cpuOut != "" {
+		if cpuFile, err = os.Create(cpuOut); err != nil {
+			return nil, fmt.Errorf("failed to create CPU profile: %w", err)
+		}
+		if err := pprof.StartCPUProfile(cpuFile); err != nil {
+			cpuFile.Close()
+			return nil, fmt.Errorf("failed to start CPU profile: %w", err)
+		}
+	}
+	if memOut != "" {
+		if memFile, err = os.Create(memOut); err != nil {
+			if cpuFile != nil {
+				pprof.StopCPUProfile()
+				cpuFile.Close()
+			}
+			return nil, fmt.Errorf("failed to create memory profile: %w", err)
+		}
+	}
+
+	return func() {
+		if cpuFile != nil {
+			pprof.StopCPUProfile()
+			if err := cpuFile.Close(); err != nil {
+				fmt.Fprintf(os.Stderr, "failed to write CPU profile: %v\n", err)
+			}
+		}
+		if memFile != nil {
+			runtime.GC()
+			err := pprof.WriteHeapProfile(memFile)
+			if closeErr := memFile.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "failed to write memory profile: %v\n", err)
+			}
+		}
+	}, nil
+}
+
+// profileFlag parses repeatable "kind=file" profile requests, kind being
+// cpu or mem.
+type profileFlag struct {
+	cpuOut, memOut *string
+}
+
+func (pf *profileFlag) String() string {
+	return ""
+}
+
+func (pf *profileFlag) Set(value string) error {
+	kind, file, found := strings.Cut(value, "=")
+	if !found || file == "" {
+		return fmt.Errorf("invalid profile %q, expected %s=file or %s=file", value, profileCPU, profileMemory)
+	}
+	switch kind {
+	case profileCPU:
+		*pf.cpuOut = file
+	case profileMemory:
+		*pf.memOut = file
+	default:
+		return fmt.Errorf("unknown profile %q (available: %s, %s)", kind, profileCPU, profileMemory)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProfileSetup(t *testing.T) {
+	dir := t.TempDir()
+	cpuOut, memOut := filepath.Join(dir, "cpu.prof"), filepath.Join(dir, "mem.prof")
+
+	stop, err := profileSetup(cpuOut, memOut)
+	if err != nil {
+		t.Fatalf("profileSetup() error = %v", err)
+	}
+	stop()
+
+	for _, file := range []string{cpuOut, memOut} {
+		if info, err := os.Stat(file); err != nil || info.Size() == 0 {
+			t.Errorf("expected a non-empty profile at %s, got %v", file, err)
+		}
+	}
+}
+
+func TestProfileSetup_Disabled(t *testing.T) {
+	stop, err := profileSetup("", "")
+	if err != nil {
+		t.Fatalf("profileSetup() error = %v", err)
+	}
+	stop()
+}
+
+func TestProfileSetup_UnwritablePath(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing", "cpu.prof")
+	if _, err := profileSetup(missing, ""); err == nil {
+		t.Error("expected an error for a CPU profile in a missing directory")
+	}
+
+	// A failed memory profile must not leave the CPU profile running.
+	cpuOut := filepath.Join(t.TempDir(), "cpu.prof")
+	if _, err := profileSetup(cpuOut, missing); err == nil {
+		t.Fatal("expected an error for a memory profile in a missing directory")
+	}
+	stop, err := profileSetup(cpuOut, "")
+	if err != nil {
+		t.Fatalf("profileSetup() after a failure error = %v", err)
+	}
+	stop()
+}
+
+func TestProfileFlag(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantCPU string
+		wantMem string
+		wantErr bool
+	}{
+		{value: "cpu=cpu.prof", wantCPU: "cpu.prof"},
+		{value: "mem=heap.prof", wantMem: "heap.prof"},
+		{value: "cpu", wantErr: true},
+		{value: "cpu=", wantErr: true},
+		{value: "block=block.prof", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			var cpuOut, memOut string
+			err := (&profileFlag{cpuOut: &cpuOut, memOut: &memOut}).Set(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set(%q) error = %v, want error %t", tt.value, err, tt.wantErr)
+			}
+			if cpuOut != tt.wantCPU || memOut != tt.wantMem {
+				t.Errorf("Set(%q) = cpu %q, mem %q, want %q, %q", tt.value, cpuOut, memOut, tt.wantCPU, tt.wantMem)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const progressBarWidth = 30
+
+// progressBar draws a single-line progress bar that is redrawn in place with
+// a carriage return, e.g. "[============>        ] 42% (168/400 packages)".
+type progressBar struct {
+	writer io.Writer
+	unit   string
+	drawn  bool
+}
+
+func newProgressBar(writer io.Writer, unit string) *progressBar {
+	return &progressBar{writer: writer, unit: unit}
+}
+
+// Update redraws the bar for done out of total units.
+func (pb *progressBar) Update(done, total int) {
+	if total <= 0 {
+		return
+	}
+	filled := progressBarWidth * done / total
+	bar := strings.Repeat("=", filled)
+	if filled < progressBarWidth {
+		bar += ">" + strings.Repeat(" ", progressBarWidth-filled-1)
+	}
+	fmt.Fprintf(pb.writer, "\r[%s] %3d%% (%d/%d %s)", bar, 100*done/total, done, total, pb.unit)
+	pb.drawn = true
+}
+
+// Finish ends the bar's line so later output starts on a fresh line.
+func (pb *progressBar) Finish() {
+	if pb.drawn {
+		fmt.Fprintf(pb.writer, "\n")
+	}
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProgressBar(t *testing.T) {
+	var output bytes.Buffer
+	bar := newProgressBar(&output, "packages")
+
+	bar.Update(0, 4)
+	bar.Update(1, 4)
+	bar.Update(4, 4)
+	bar.Finish()
+
+	want := "\r[>                             ]   0% (0/4 packages)" +
+		"\r[=======>                      ]  25% (1/4 packages)" +
+		"\r[==============================] 100% (4/4 packages)\n"
+	if output.String() != want {
+		t.Errorf("output = %q, want %q", output.String(), want)
+	}
+}
+
+func TestProgressBar_FinishWithoutUpdates(t *testing.T) {
+	var output bytes.Buffer
+	newProgressBar(&output, "packages").Finish()
+	if output.Len() != 0 {
+		t.Errorf("expected no output, got %q", output.String())
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	// /dev/null is a character device but not a terminal.
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Skipf("no %s: %v", os.DevNull, err)
+	}
+	defer devNull.Close()
+	if isTerminal(devNull) {
+		t.Errorf("isTerminal(%s) = true, want false", os.DevNull)
+	}
+
+	file, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if isTerminal(file) {
+		t.Error("isTerminal(regular file) = true, want false")
+	}
+}
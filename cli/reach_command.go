@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/Desgue/codegraph/analyzer"
+	"github.com/Desgue/codegraph/parser"
+	"github.com/Desgue/codegraph/path"
+)
+
+// reachFromAll selects every entry point kind in --from.
+const reachFromAll = "all"
+
+type ReachCommand struct {
+	TargetDirectory *path.TargetDirectory
+	// From lists the entry point kinds to start from; empty means all.
+	From         []string
+	IncludeTests bool
+	JSON         bool
+
+	output io.Writer
+}
+
+// reachReport is the --json output: the limits note first, as in text output.
+type reachReport struct {
+	Note string `json:"note"`
+	analyzer.Reachability
+}
+
+func NewReachCommand(args []string) (*ReachCommand, error) {
+	flagSet := flag.NewFlagSet("reach", flag.ContinueOnError)
+
+	reachCommand := &ReachCommand{output: os.Stdout}
+	fromList := reachFromAll
+
+	flagSet.StringVar(&fromList, "from", reachFromAll,
+		fmt.Sprintf("Comma-separated entry point kinds to start from (%s) or %s", strings.Join(analyzer.EntrypointKinds, ", "), reachFromAll))
+	flagSet.BoolVar(&reachCommand.IncludeTests, "include-tests", false, "Include test files, whose tests become entry points")
+	flagSet.BoolVar(&reachCommand.JSON, "json", false, "Print the result as JSON")
+
+	if err := flagSet.Parse(args); err != nil {
+		return nil, newUsageError(err)
+	}
+	if fromList != reachFromAll {
+		reachCommand.From = strings.Split(fromList, ",")
+	}
+
+	directoryArgument := ""
+	if flagSet.NArg() > 0 {
+		directoryArgument = flagSet.Arg(0)
+	}
+
+	targetDirectory, err := path.NewTargetDirectory(directoryArgument)
+	if err != nil {
+		return nil, err
+	}
+	reachCommand.TargetDirectory = targetDirectory
+
+	if err := reachCommand.Validate(); err != nil {
+		return nil, err
+	}
+
+	return reachCommand, nil
+}
+
+func (rc *ReachCommand) Validate() error {
+	for _, kind := range rc.From {
+		if !slices.Contains(analyzer.EntrypointKinds, kind) {
+			return usageErrorf("unknown entry point kind '%s' (available: %s, %s)", kind, strings.Join(analyzer.EntrypointKinds, ", "), reachFromAll)
+		}
+	}
+	return nil
+}
+
+func (rc *ReachCommand) Execute() error {
+	pkgs, _, err := parser.Load(rc.TargetDirectory.Path, rc.IncludeTests)
+	if err != nil {
+		return err
+	}
+	reachability := analyzer.FindReachable(analyzer.BuildCallGraph(pkgs), rc.From)
+
+	if rc.JSON {
+		encoder := json.NewEncoder(rc.output)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(reachReport{Note: analyzer.ReachabilityNote, Reachability: reachability})
+	}
+	rc.printReachability(reachability)
+	return nil
+}
+
+// printReachability writes the limits note, the entry points, the totals and
+// then what is unreachable, packages before functions.
+func (rc *ReachCommand) printReachability(reachability analyzer.Reachability) {
+	fmt.Fprintf(rc.output, "Note: %s\n\n", analyzer.ReachabilityNote)
+
+	fmt.Fprintf(rc.output, "Entry points: %d\n", len(reachability.EntryPoints))
+	for _, entryPoint := range reachability.EntryPoints {
+		fmt.Fprintf(rc.output, "  %s: %s\n", entryPoint.Kind, entryPoint.Function)
+	}
+	functions := len(reachability.ReachableFunctions) + len(reachability.UnreachableFunctions)
+	packageCount := len(reachability.ReachablePackages) + len(reachability.UnreachablePackages)
+	fmt.Fprintf(rc.output, "Reachable: %d of %d functions, %d of %d packages\n",
+		len(reachability.ReachableFunctions), functions, len(reachability.ReachablePackages), packageCount)
+
+	if len(reachability.UnreachablePackages) > 0 {
+		fmt.Fprintf(rc.output, "Unreachable packages:\n")
+		for _, pkgPath := range reachability.UnreachablePackages {
+			fmt.Fprintf(rc.output, "  %s\n", pkgPath)
+		}
+	}
+	if len(reachability.UnreachableFunctions) > 0 {
+		fmt.Fprintf(rc.output, "Unreachable functions:\n")
+		for _, function := range reachability.UnreachableFunctions {
+			fmt.Fprintf(rc.output, "  %s\n", function)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/Desgue/codegraph/analyzer"
+)
+
+func TestNewReachCommand(t *testing.T) {
+	if _, err := NewReachCommand([]string{"--from", "main,cron", t.TempDir()}); !errors.Is(err, ErrUsage) {
+		t.Fatalf("expected ErrUsage, got %v", err)
+	}
+}
+
+func TestReachCommand_Execute(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":           "module testreach\n\ngo 1.24\n",
+		"cmd/main.go":      "package main\n\nimport \"testreach/util\"\n\nfunc main() { util.Used() }\n",
+		"util/util.go":     "package util\n\nfunc Used() {}\n\nfunc Unused() {}\n",
+		"orphan/orphan.go": "package orphan\n\nfunc init() {}\n",
+	})
+
+	cmd, err := NewReachCommand([]string{"--from", "main", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output bytes.Buffer
+	cmd.output = &output
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	want := "Note: " + analyzer.ReachabilityNote + "\n\n" +
+		"Entry points: 1\n  main: testreach/cmd.main\n" +
+		"Reachable: 2 of 3 functions, 2 of 3 packages\n" +
+		"Unreachable packages:\n  testreach/orphan\n" +
+		"Unreachable functions:\n  testreach/util.Unused\n"
+	if output.String() != want {
+		t.Errorf("output = %q, want %q", output.String(), want)
+	}
+}
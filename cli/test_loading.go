@@ -0,0 +1,25 @@
+package cli
+
+// testFileUses lists what a command's output takes from test files when they
+// are loaded. Package-level import graphs restricted to production edges take
+// nothing: loading keeps each package's production imports, and external test
+// packages only add test-only edges.
+type testFileUses struct {
+	// TestEdges reports test-only imports or the cycles they close.
+	TestEdges bool
+	// Declarations reports on declared types or functions, which test files
+	// add to.
+	Declarations bool
+	// FileData reports per-package file lists or counts, or what is computed
+	// from the files, such as source metrics, unsafe usage or import aliases,
+	// which test files add to.
+	FileData bool
+}
+
+// loadTestFiles reports whether packages must be loaded with their test
+// files: only when they were requested and the output uses them. Skipping
+// them otherwise avoids parsing and type-checking every test variant for
+// nothing.
+func loadTestFiles(includeTests bool, uses testFileUses) bool {
+	return includeTests && (uses.TestEdges || uses.Declarations || uses.FileData)
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadTestFiles(t *testing.T) {
+	tests := []struct {
+		name         string
+		includeTests bool
+		uses         testFileUses
+		want         bool
+	}{
+		{name: "tests excluded", includeTests: false, uses: testFileUses{}, want: false},
+		{name: "tests excluded, test edges", includeTests: false, uses: testFileUses{TestEdges: true}, want: false},
+		{name: "tests excluded, declarations", includeTests: false, uses: testFileUses{Declarations: true}, want: false},
+		{name: "tests excluded, both", includeTests: false, uses: testFileUses{TestEdges: true, Declarations: true}, want: false},
+		{name: "tests unused", includeTests: true, uses: testFileUses{}, want: false},
+		{name: "test edges", includeTests: true, uses: testFileUses{TestEdges: true}, want: true},
+		{name: "declarations", includeTests: true, uses: testFileUses{Declarations: true}, want: true},
+		{name: "both", includeTests: true, uses: testFileUses{TestEdges: true, Declarations: true}, want: true},
+		{name: "tests excluded, file data", includeTests: false, uses: testFileUses{FileData: true}, want: false},
+		{name: "file data", includeTests: true, uses: testFileUses{FileData: true}, want: true},
+		{name: "all", includeTests: true, uses: testFileUses{TestEdges: true, Declarations: true, FileData: true}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := loadTestFiles(tt.includeTests, tt.uses); got != tt.want {
+				t.Errorf("loadTestFiles(%t, %+v) = %t, want %t", tt.includeTests, tt.uses, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCommand_LoadTestFiles(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want bool
+	}{
+		{name: "defaults", args: []string{"--output", "out.graphml"}, want: true},
+		{name: "tests excluded", args: []string{"--output", "out.tgf", "--include-tests=false"}, want: false},
+		{name: "test edges shown", args: []string{"--output", "out.tgf"}, want: true},
+		{name: "tgf without test edges", args: []string{"--output", "out.tgf", "--hide-test-edges"}, want: false},
+		{name: "d2 without test edges", args: []string{"--output", "out.d2", "--format", "d2", "--hide-test-edges"}, want: false},
+		{name: "excalidraw without test edges", args: []string{"--output", "out.excalidraw", "--format", "excalidraw", "--hide-test-edges"}, want: false},
+		{name: "tgf edge labels", args: []string{"--output", "out.tgf", "--hide-test-edges", "--show-edge-labels"}, want: true},
+		{name: "graphml metrics", args: []string{"--output", "out.graphml", "--hide-test-edges"}, want: true},
+		{name: "json metrics", args: []string{"--output", "out.json", "--hide-test-edges"}, want: true},
+		{name: "dot unsafe usage", args: []string{"--output", "out.dot", "--hide-test-edges"}, want: true},
+		{name: "markdown file counts", args: []string{"--output", "out.md", "--format", "markdown", "--hide-test-edges"}, want: true},
+		{name: "summary file lists", args: []string{"--summary-only", "--format", "tgf", "--hide-test-edges"}, want: true},
+		{name: "change frequency", args: []string{"--output", "out.tgf", "--hide-test-edges", "--change-frequency"}, want: true},
+		{name: "calls", args: []string{"--output", "out.tgf", "--hide-test-edges", "--calls"}, want: true},
+		{name: "todos", args: []string{"--output", "out.tgf", "--hide-test-edges", "--include-todos"}, want: true},
+		{name: "symbol index", args: []string{"--output", "out.tgf", "--hide-test-edges", "--write-symbol-index", "symbols.json"}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The package directory is in the repository, as
+			// --change-frequency requires.
+			cmd, err := NewParseCommand(append(tt.args, "--hide-progress-bar", "."))
+			if err != nil {
+				t.Fatalf("NewParseCommand() error = %v", err)
+			}
+			if got := loadTestFiles(cmd.IncludeTests, cmd.testFileUses()); got != tt.want {
+				t.Errorf("loadTestFiles(%t, %+v) = %t, want %t", cmd.IncludeTests, cmd.testFileUses(), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCommand_Execute_SkipsTestFiles(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":              "module testskip\n\ngo 1.24\n",
+		"store/store.go":      "package store\n",
+		"store/store_test.go": "package store\n\nimport \"testing\"\n\nfunc TestStore(t *testing.T) {}\n",
+	})
+	outputFile := filepath.Join(t.TempDir(), "out.tgf")
+
+	cmd, err := NewParseCommand([]string{"--output", outputFile, "--hide-test-edges", "--verbose", "--hide-progress-bar", "--no-cache", testDir})
+	if err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	var output, errOutput bytes.Buffer
+	cmd.output = &output
+	cmd.errOutput = &errOutput
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if !strings.Contains(errOutput.String(), "Skipping test files") {
+		t.Errorf("errors = %q, want the skipped test files noted", errOutput.String())
+	}
+	if !strings.Contains(output.String(), "parsed 1 files\n") || !strings.Contains(output.String(), "near-cycles (test-only): n/a\n") {
+		t.Errorf("output = %q, want only store.go loaded and no near-cycle count", output.String())
+	}
+}
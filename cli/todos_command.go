@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Desgue/codegraph/extract"
+	"github.com/Desgue/codegraph/graph"
+	"github.com/Desgue/codegraph/parser"
+	"github.com/Desgue/codegraph/path"
+)
+
+// Groupings accepted by the todos command's --group-by flag.
+const (
+	todoGroupByPackage = "package"
+	todoGroupByAuthor  = "author"
+)
+
+// noAuthorGroup names the author group of annotations without "(name)".
+const noAuthorGroup = "(no author)"
+
+type TodosCommand struct {
+	TargetDirectory *path.TargetDirectory
+	GroupBy         string
+	Markers         []string
+	IncludeTests    bool
+	JSON            bool
+
+	output io.Writer
+}
+
+// todoGroup is the annotations of one package or author.
+type todoGroup struct {
+	Name        string              `json:"name"`
+	Annotations []parser.Annotation `json:"annotations"`
+}
+
+func NewTodosCommand(args []string) (*TodosCommand, error) {
+	flagSet := flag.NewFlagSet("todos", flag.ContinueOnError)
+
+	todosCommand := &TodosCommand{output: os.Stdout}
+	markerList := ""
+
+	flagSet.StringVar(&todosCommand.GroupBy, "group-by", todoGroupByPackage,
+		"Group the marker comments by package or by author (the name in TODO(name):)")
+	flagSet.StringVar(&markerList, "markers", "", "Comma-separated comment markers to look for instead of TODO,FIXME,HACK,XXX")
+	flagSet.BoolVar(&todosCommand.IncludeTests, "include-tests", false, "Include test files in parsing")
+	flagSet.BoolVar(&todosCommand.JSON, "json", false, "Print the groups as JSON")
+
+	if err := flagSet.Parse(args); err != nil {
+		return nil, newUsageError(err)
+	}
+	if markerList != "" {
+		todosCommand.Markers = strings.Split(markerList, ",")
+	}
+
+	directoryArgument := ""
+	if flagSet.NArg() > 0 {
+		directoryArgument = flagSet.Arg(0)
+	}
+
+	targetDirectory, err := path.NewTargetDirectory(directoryArgument)
+	if err != nil {
+		return nil, err
+	}
+	todosCommand.TargetDirectory = targetDirectory
+
+	if err := todosCommand.Validate(); err != nil {
+		return nil, err
+	}
+
+	return todosCommand, nil
+}
+
+func (tc *TodosCommand) Validate() error {
+	if tc.GroupBy != todoGroupByPackage && tc.GroupBy != todoGroupByAuthor {
+		return usageErrorf("unknown grouping '%s' (available: %s, %s)", tc.GroupBy, todoGroupByPackage, todoGroupByAuthor)
+	}
+	for _, marker := range tc.Markers {
+		if strings.TrimSpace(marker) == "" {
+			return usageErrorf("--markers must not contain empty markers")
+		}
+	}
+	return nil
+}
+
+// Execute extracts the graph with annotation records on the package nodes and
+// lists them from there, as a graph written by parse --include-todos holds them.
+func (tc *TodosCommand) Execute() error {
+	pkgs, _, err := parser.Load(tc.TargetDirectory.Path, tc.IncludeTests)
+	if err != nil {
+		return err
+	}
+	extractors := withPackageExtractor(extract.Extractors(),
+		&extract.PackageExtractor{AnnotationMarkers: tc.Markers, IncludeAnnotations: true})
+	dependencyGraph, err := extractGraphWith(context.Background(), pkgs, extractors)
+	if err != nil {
+		return err
+	}
+
+	groups, err := groupTodos(dependencyGraph, tc.GroupBy)
+	if err != nil {
+		return err
+	}
+	if tc.JSON {
+		if groups == nil {
+			groups = []todoGroup{}
+		}
+		encoder := json.NewEncoder(tc.output)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(groups)
+	}
+	printTodos(tc.output, groups)
+	return nil
+}
+
+// groupTodos collects the annotation records of the graph's package nodes by
+// package ID or author, groups sorted by name with unattributed annotations
+// last. Annotations keep their package, file and line order within a group.
+func groupTodos(g *graph.Graph, groupBy string) ([]todoGroup, error) {
+	byName := make(map[string][]parser.Annotation)
+	for _, node := range g.Nodes() {
+		annotations, err := extract.NodeAnnotations(node)
+		if err != nil {
+			return nil, err
+		}
+		for _, annotation := range annotations {
+			name := node.ID
+			if groupBy == todoGroupByAuthor {
+				name = annotation.Author
+			}
+			byName[name] = append(byName[name], annotation)
+		}
+	}
+
+	groups := make([]todoGroup, 0, len(byName))
+	for name, annotations := range byName {
+		groups = append(groups, todoGroup{Name: name, Annotations: annotations})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if (groups[i].Name == "") != (groups[j].Name == "") {
+			return groups[j].Name == ""
+		}
+		return groups[i].Name < groups[j].Name
+	})
+	for i := range groups {
+		if groups[i].Name == "" {
+			groups[i].Name = noAuthorGroup
+		}
+	}
+	if len(groups) == 0 {
+		return nil, nil
+	}
+	return groups, nil
+}
+
+// printTodos lists each group with its annotations as file:line lines.
+func printTodos(writer io.Writer, groups []todoGroup) {
+	total := 0
+	for _, group := range groups {
+		total += len(group.Annotations)
+	}
+	fmt.Fprintf(writer, "Marker comments: %d\n", total)
+
+	for _, group := range groups {
+		fmt.Fprintf(writer, "%s (%d):\n", group.Name, len(group.Annotations))
+		for _, annotation := range group.Annotations {
+			marker := annotation.Marker
+			if annotation.Author != "" {
+				marker += "(" + annotation.Author + ")"
+			}
+			if annotation.Text != "" {
+				marker += ": " + annotation.Text
+			}
+			fmt.Fprintf(writer, "  %s:%d: %s\n", annotation.File, annotation.Line, marker)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewTodosCommand(t *testing.T) {
+	if _, err := NewTodosCommand([]string{"--group-by", "file", t.TempDir()}); !errors.Is(err, ErrUsage) {
+		t.Fatalf("expected ErrUsage, got %v", err)
+	}
+	if _, err := NewTodosCommand([]string{"--markers", "TODO,,FIXME", t.TempDir()}); !errors.Is(err, ErrUsage) {
+		t.Fatalf("expected ErrUsage for an empty marker, got %v", err)
+	}
+}
+
+func TestTodosCommand_Execute(t *testing.T) {
+	testDir := writeTestModule(t, map[string]string{
+		"go.mod":       "module testtodos\n\ngo 1.24\n",
+		"jobs/jobs.go": "package jobs\n\n// TODO(bob): batch writes\nfunc Run() {}\n\n// FIXME flaky on CI\nvar s = \"TODO: not a comment\"\n",
+		"api/api.go":   "package api\n\n// TODO(alice): paginate\nfunc List() {}\n",
+	})
+	apiFile := filepath.Join(testDir, "api", "api.go")
+	jobsFile := filepath.Join(testDir, "jobs", "jobs.go")
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "by package",
+			args: []string{},
+			want: "Marker comments: 3\n" +
+				"testtodos/api (1):\n  " + apiFile + ":3: TODO(alice): paginate\n" +
+				"testtodos/jobs (2):\n  " + jobsFile + ":3: TODO(bob): batch writes\n  " + jobsFile + ":6: FIXME: flaky on CI\n",
+		},
+		{
+			name: "by author",
+			args: []string{"--group-by", "author"},
+			want: "Marker comments: 3\n" +
+				"alice (1):\n  " + apiFile + ":3: TODO(alice): paginate\n" +
+				"bob (1):\n  " + jobsFile + ":3: TODO(bob): batch writes\n" +
+				"(no author) (1):\n  " + jobsFile + ":6: FIXME: flaky on CI\n",
+		},
+		{
+			name: "custom markers",
+			args: []string{"--markers", "FIXME"},
+			want: "Marker comments: 1\ntesttodos/jobs (1):\n  " + jobsFile + ":6: FIXME: flaky on CI\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := NewTodosCommand(append(tt.args, testDir))
+			if err != nil {
+				t.Fatalf("setup failed: %v", err)
+			}
+			var output bytes.Buffer
+			cmd.output = &output
+
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if output.String() != tt.want {
+				t.Errorf("output = %q, want %q", output.String(), tt.want)
+			}
+		})
+	}
+}
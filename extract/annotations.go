@@ -0,0 +1,53 @@
+package extract
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/Desgue/codegraph/graph"
+	"github.com/Desgue/codegraph/parser"
+	"golang.org/x/tools/go/packages"
+)
+
+// AttributeTodoCount is the number of marker comments (TODO, FIXME, ...) in a
+// package's files. AttributeTodos holds the comments themselves as a JSON
+// array of parser.Annotation, recorded only when requested with
+// PackageExtractor.IncludeAnnotations.
+const (
+	AttributeTodoCount = "todo_count"
+	AttributeTodos     = "todos"
+)
+
+// applyAnnotations records the package's marker comments. Packages loaded
+// without syntax get none.
+func applyAnnotations(node *graph.Node, pkg *packages.Package, markers []string, includeRecords bool) error {
+	if pkg.Syntax == nil {
+		return nil
+	}
+	annotations := parser.ExtractAnnotations(pkg, markers)
+	node.SetAttribute(AttributeTodoCount, strconv.Itoa(len(annotations)))
+	if !includeRecords || len(annotations) == 0 {
+		return nil
+	}
+	records, err := json.Marshal(annotations)
+	if err != nil {
+		return err
+	}
+	node.SetAttribute(AttributeTodos, string(records))
+	return nil
+}
+
+// NodeAnnotations decodes the AttributeTodos records of a package node, nil
+// when it has none.
+func NodeAnnotations(node *graph.Node) ([]parser.Annotation, error) {
+	records := node.Attributes[AttributeTodos]
+	if records == "" {
+		return nil, nil
+	}
+	var annotations []parser.Annotation
+	if err := json.Unmarshal([]byte(records), &annotations); err != nil {
+		return nil, fmt.Errorf("invalid %s attribute on %s: %w", AttributeTodos, node.ID, err)
+	}
+	return annotations, nil
+}
@@ -0,0 +1,56 @@
+package extract
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"testing"
+
+	codeparser "github.com/Desgue/codegraph/parser"
+	"golang.org/x/tools/go/packages"
+)
+
+func TestPackageExtractor_Annotations(t *testing.T) {
+	fileSet := token.NewFileSet()
+	source := "package jobs\n\n// TODO(bob): batch writes\nfunc Run() {}\n\nvar s = \"FIXME: in a string\"\n\n// NOTE: custom marker\nvar n int\n"
+	file, err := parser.ParseFile(fileSet, "jobs.go", source, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("failed to parse source: %v", err)
+	}
+	jobs := &packages.Package{PkgPath: "example.com/mod/jobs", Name: "jobs", Fset: fileSet, Syntax: []*ast.File{file}}
+
+	countOnly, _, err := Build(context.Background(), []*packages.Package{jobs}, []Extractor{&PackageExtractor{}})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	node, _ := countOnly.Node("example.com/mod/jobs")
+	if node.Attributes[AttributeTodoCount] != "1" {
+		t.Errorf("%s = %q, want \"1\"", AttributeTodoCount, node.Attributes[AttributeTodoCount])
+	}
+	if _, found := node.Attributes[AttributeTodos]; found {
+		t.Errorf("expected no %s without IncludeAnnotations, got %v", AttributeTodos, node.Attributes)
+	}
+
+	withRecords, _, err := Build(context.Background(), []*packages.Package{jobs},
+		[]Extractor{&PackageExtractor{AnnotationMarkers: []string{"TODO", "NOTE"}, IncludeAnnotations: true}})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	node, _ = withRecords.Node("example.com/mod/jobs")
+	if node.Attributes[AttributeTodoCount] != "2" {
+		t.Errorf("%s = %q, want \"2\"", AttributeTodoCount, node.Attributes[AttributeTodoCount])
+	}
+	annotations, err := NodeAnnotations(node)
+	if err != nil {
+		t.Fatalf("NodeAnnotations() error = %v", err)
+	}
+	want := []codeparser.Annotation{
+		{Marker: "TODO", Author: "bob", Text: "batch writes", File: "jobs.go", Line: 3},
+		{Marker: "NOTE", Text: "custom marker", File: "jobs.go", Line: 8},
+	}
+	if !reflect.DeepEqual(annotations, want) {
+		t.Errorf("NodeAnnotations() = %+v, want %+v", annotations, want)
+	}
+}
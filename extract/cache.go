@@ -0,0 +1,391 @@
+package extract
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/Desgue/codegraph/graph"
+	"golang.org/x/tools/go/packages"
+)
+
+// DefaultCacheSize is the total entry size a Cache opened by OpenCache keeps.
+const DefaultCacheSize = 256 << 20
+
+// cacheVersion is part of every key; bump it when the entry encoding or the
+// key contents change.
+const cacheVersion = 2
+
+// cacheEntrySuffix names the files holding cache entries, so Trim and Clean
+// leave anything else in the directory alone.
+const cacheEntrySuffix = ".json.gz"
+
+// Cache stores the extractor output of each package on disk so that later
+// runs skip extractors for unchanged packages. An entry is keyed by a hash
+// of the package's files, load mode, errors and extractor configuration
+// (see Fingerprinter), and of the keys of the packages it imports, loaded or
+// not, so an edit invalidates the edited package and everything importing
+// it, including edits to replaced or vendored dependencies. Entries are gzipped JSON
+// and are evicted least recently used first once MaxSize is exceeded.
+//
+// Cache failures never fail extraction: unreadable entries count as misses
+// and entries that cannot be written are skipped.
+type Cache struct {
+	Dir string
+	// MaxSize bounds the total size of the entries in bytes, enforced by Trim.
+	MaxSize int64
+	// Paranoid hashes every file for the keys instead of reusing the hash
+	// recorded for a file whose size and modification time are unchanged,
+	// catching edits that preserve both.
+	Paranoid bool
+
+	hits, misses atomic.Int64
+}
+
+// DefaultCacheDir returns $CODEGRAPH_CACHE_DIR, or codegraph/extract under
+// the user cache directory when it is unset.
+func DefaultCacheDir() (string, error) {
+	if dir := os.Getenv("CODEGRAPH_CACHE_DIR"); dir != "" {
+		return dir, nil
+	}
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate the cache directory: %w", err)
+	}
+	return filepath.Join(base, "codegraph", "extract"), nil
+}
+
+// OpenCache returns a cache of DefaultCacheSize in dir, creating the directory.
+func OpenCache(dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return &Cache{Dir: dir, MaxSize: DefaultCacheSize}, nil
+}
+
+// Stats returns how many packages were served from the cache and how many
+// were extracted, since the cache was opened.
+func (c *Cache) Stats() (hits, misses int) {
+	return int(c.hits.Load()), int(c.misses.Load())
+}
+
+type cacheKey struct{}
+
+// WithCache returns a context that makes Run read and write extractor output
+// through cache, and trim it when done.
+func WithCache(ctx context.Context, cache *Cache) context.Context {
+	return context.WithValue(ctx, cacheKey{}, cache)
+}
+
+func cacheFrom(ctx context.Context) *Cache {
+	cache, _ := ctx.Value(cacheKey{}).(*Cache)
+	return cache
+}
+
+// cachedOutput is the encoding of one extractorOutput. Outputs of failed
+// extractors are never cached.
+type cachedOutput struct {
+	Extractor string          `json:"extractor"`
+	Elements  []cachedElement `json:"elements"`
+}
+
+type cachedElement struct {
+	Node *graph.Node `json:"node,omitempty"`
+	Edge *graph.Edge `json:"edge,omitempty"`
+}
+
+func (c *Cache) entryPath(key string) string {
+	return filepath.Join(c.Dir, key+cacheEntrySuffix)
+}
+
+// load returns the outputs stored under key, marking the entry as recently used.
+func (c *Cache) load(key string) ([]*extractorOutput, bool) {
+	outputs, err := c.read(key)
+	if err != nil {
+		c.misses.Add(1)
+		return nil, false
+	}
+	now := time.Now()
+	_ = os.Chtimes(c.entryPath(key), now, now)
+	c.hits.Add(1)
+	return outputs, true
+}
+
+func (c *Cache) read(key string) ([]*extractorOutput, error) {
+	file, err := os.Open(c.entryPath(key))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	var cached []cachedOutput
+	if err := json.NewDecoder(reader).Decode(&cached); err != nil {
+		return nil, err
+	}
+
+	outputs := make([]*extractorOutput, len(cached))
+	for i, output := range cached {
+		outputs[i] = &extractorOutput{extractor: output.Extractor}
+		for _, element := range output.Elements {
+			if (element.Node == nil) == (element.Edge == nil) {
+				return nil, fmt.Errorf("malformed cache entry %s", key)
+			}
+			outputs[i].elements = append(outputs[i].elements, outputElement{node: element.Node, edge: element.Edge})
+		}
+	}
+	return outputs, nil
+}
+
+// store writes outputs under key unless an extractor failed. The entry is
+// written to a temporary file and renamed, so concurrent runs never read a
+// partial entry.
+func (c *Cache) store(key string, outputs []*extractorOutput) {
+	cached := make([]cachedOutput, len(outputs))
+	for i, output := range outputs {
+		if output.err != nil {
+			return
+		}
+		cached[i] = cachedOutput{Extractor: output.extractor, Elements: make([]cachedElement, len(output.elements))}
+		for j, element := range output.elements {
+			cached[i].Elements[j] = cachedElement{Node: element.node, Edge: element.edge}
+		}
+	}
+
+	file, err := os.CreateTemp(c.Dir, key+".tmp*")
+	if err != nil {
+		return
+	}
+	writer, _ := gzip.NewWriterLevel(file, gzip.BestSpeed)
+	err = json.NewEncoder(writer).Encode(cached)
+	if closeErr := writer.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), c.entryPath(key))
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+	}
+}
+
+type cacheEntry struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+func (c *Cache) entries() ([]cacheEntry, error) {
+	dirEntries, err := os.ReadDir(c.Dir)
+	if err != nil {
+		return nil, err
+	}
+	var entries []cacheEntry
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() || !strings.HasSuffix(dirEntry.Name(), cacheEntrySuffix) {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			continue
+		}
+		entries = append(entries, cacheEntry{path: filepath.Join(c.Dir, dirEntry.Name()), size: info.Size(), modTime: info.ModTime()})
+	}
+	return entries, nil
+}
+
+// Trim removes the least recently used entries until the rest, and the file
+// index, fit in MaxSize. The index goes too when it does not fit alone.
+func (c *Cache) Trim() error {
+	entries, err := c.entries()
+	if err != nil {
+		return err
+	}
+	var total int64
+	for _, entry := range entries {
+		total += entry.size
+	}
+	indexPath := filepath.Join(c.Dir, fileIndexName)
+	if info, err := os.Stat(indexPath); err == nil {
+		total += info.Size()
+	}
+	slices.SortFunc(entries, func(a, b cacheEntry) int { return a.modTime.Compare(b.modTime) })
+	for _, entry := range entries {
+		if total <= c.MaxSize {
+			break
+		}
+		if err := os.Remove(entry.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		total -= entry.size
+	}
+	if total > c.MaxSize {
+		if err := os.Remove(indexPath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Clean removes every entry, and the file index, and returns how many
+// entries and bytes it removed.
+func (c *Cache) Clean() (int, int64, error) {
+	if err := os.Remove(filepath.Join(c.Dir, fileIndexName)); err != nil && !os.IsNotExist(err) {
+		return 0, 0, err
+	}
+	entries, err := c.entries()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, 0, nil
+		}
+		return 0, 0, err
+	}
+	removed, removedSize := 0, int64(0)
+	for _, entry := range entries {
+		if err := os.Remove(entry.path); err != nil && !os.IsNotExist(err) {
+			return removed, removedSize, err
+		}
+		removed++
+		removedSize += entry.size
+	}
+	return removed, removedSize, nil
+}
+
+// packageKeys returns the cache key of each package, or "" for a package
+// that cannot be cached because one of its files, or of a package it
+// transitively imports, is unreadable. The files of all packages and their
+// imports are hashed up front by hashFiles.
+func (c *Cache) packageKeys(ctx context.Context, pkgs []*packages.Package, extractors []Extractor) []string {
+	var names []string
+	seen := make(map[string]bool)
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		for _, files := range [][]string{pkg.GoFiles, pkg.OtherFiles, pkg.EmbedFiles, pkg.IgnoredFiles} {
+			for _, file := range files {
+				if !seen[file] {
+					seen[file] = true
+					names = append(names, file)
+				}
+			}
+		}
+	})
+	sums := c.hashFiles(ctx, names)
+
+	base := sha256.New()
+	fmt.Fprintf(base, "codegraph cache v%d\n%s\n", cacheVersion, buildIdentity())
+	for _, extractor := range extractors {
+		fmt.Fprintf(base, "extractor %s %T", extractor.Name(), extractor)
+		if fingerprinter, ok := extractor.(Fingerprinter); ok {
+			fmt.Fprintf(base, " %q", fingerprinter.Fingerprint())
+		}
+		base.Write([]byte{'\n'})
+	}
+	baseSum := base.Sum(nil)
+
+	byPath := make(map[string]*packages.Package, len(pkgs))
+	for _, pkg := range pkgs {
+		byPath[pkg.PkgPath] = pkg
+	}
+	keys := make(map[string]string, len(pkgs))
+	inProgress := make(map[string]bool)
+
+	var keyOf func(pkg *packages.Package) string
+	keyOf = func(pkg *packages.Package) string {
+		if key, found := keys[pkg.PkgPath]; found {
+			return key
+		}
+		inProgress[pkg.PkgPath] = true
+		defer delete(inProgress, pkg.PkgPath)
+
+		digest := sha256.New()
+		digest.Write(baseSum)
+		fmt.Fprintf(digest, "package %s %s %s syntax=%t types=%t\n",
+			pkg.ID, pkg.PkgPath, pkg.Name, len(pkg.Syntax) > 0, pkg.Types != nil && pkg.TypesInfo != nil)
+		if pkg.Module != nil {
+			fmt.Fprintf(digest, "module %s %s %s %s\n", pkg.Module.Path, pkg.Module.Version, pkg.Module.GoVersion, pkg.Module.Dir)
+		}
+		for _, files := range [][]string{pkg.GoFiles, pkg.OtherFiles, pkg.EmbedFiles, pkg.IgnoredFiles} {
+			for _, file := range files {
+				sum, found := sums[file]
+				if !found {
+					keys[pkg.PkgPath] = ""
+					return ""
+				}
+				fmt.Fprintf(digest, "file %s %s\n", file, sum)
+			}
+			digest.Write([]byte{0})
+		}
+		for _, packageErr := range pkg.Errors {
+			fmt.Fprintf(digest, "error %s\n", packageErr)
+		}
+
+		importPaths := make([]string, 0, len(pkg.Imports))
+		for importPath := range pkg.Imports {
+			importPaths = append(importPaths, importPath)
+		}
+		slices.Sort(importPaths)
+		for _, importPath := range importPaths {
+			imported := pkg.Imports[importPath]
+			fmt.Fprintf(digest, "import %s %s", importPath, imported.PkgPath)
+			// Packages outside the run are keyed like loaded ones, by the
+			// content of their files and of their own imports.
+			if loaded := byPath[imported.PkgPath]; loaded != nil {
+				imported = loaded
+			}
+			if !inProgress[imported.PkgPath] {
+				importKey := keyOf(imported)
+				if importKey == "" {
+					keys[pkg.PkgPath] = ""
+					return ""
+				}
+				fmt.Fprintf(digest, " %s", importKey)
+			}
+			digest.Write([]byte{'\n'})
+		}
+
+		key := hex.EncodeToString(digest.Sum(nil))
+		keys[pkg.PkgPath] = key
+		return key
+	}
+
+	result := make([]string, len(pkgs))
+	for i, pkg := range pkgs {
+		result[i] = keyOf(pkg)
+	}
+	return result
+}
+
+// buildIdentity identifies the running binary, so entries written by a
+// different build of the extractors are not reused.
+func buildIdentity() string {
+	var identity strings.Builder
+	if info, ok := debug.ReadBuildInfo(); ok {
+		fmt.Fprintf(&identity, "%s %s", info.Main.Path, info.Main.Version)
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" || setting.Key == "vcs.modified" {
+				fmt.Fprintf(&identity, " %s=%s", setting.Key, setting.Value)
+			}
+		}
+	}
+	// Development builds share a version, so the executable itself tells them apart.
+	if executable, err := os.Executable(); err == nil {
+		if info, err := os.Stat(executable); err == nil {
+			fmt.Fprintf(&identity, " %s %d %d", executable, info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return identity.String()
+}
@@ -0,0 +1,246 @@
+package extract
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Desgue/codegraph/formatter"
+	"github.com/Desgue/codegraph/internal/testutil/repogen"
+	codeparser "github.com/Desgue/codegraph/parser"
+)
+
+// buildCached loads root and extracts it through cache, returning the graph
+// encoded as JSON.
+func buildCached(t *testing.T, root string, cache *Cache) []byte {
+	t.Helper()
+
+	pkgs, errorCount, err := codeparser.Load(root, true)
+	if err != nil || errorCount > 0 {
+		t.Fatalf("Load() = %d errors, %v", errorCount, err)
+	}
+	ctx := context.Background()
+	if cache != nil {
+		ctx = WithCache(ctx, cache)
+	}
+	g, failures, err := Build(ctx, pkgs, Extractors())
+	if err != nil || len(failures) > 0 {
+		t.Fatalf("Build() = %v, %v", failures, err)
+	}
+	var encoded bytes.Buffer
+	if err := (&formatter.JSONFormatter{}).Encode(&encoded, g); err != nil {
+		t.Fatal(err)
+	}
+	return encoded.Bytes()
+}
+
+func openTestCache(t *testing.T) *Cache {
+	t.Helper()
+
+	cache, err := OpenCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cache
+}
+
+func TestRun_WarmCacheMatchesColdRun(t *testing.T) {
+	repo := repogen.Generate(t, repogen.Config{
+		Packages: 12, Topology: repogen.RandomDAG, Density: 0.3, Seed: 5, FunctionsPerPackage: 2, TestFiles: true, Generics: true,
+	})
+	uncached := buildCached(t, repo.Root, nil)
+
+	cache := openTestCache(t)
+	cold := buildCached(t, repo.Root, cache)
+	coldHits, coldMisses := cache.Stats()
+	warm := buildCached(t, repo.Root, cache)
+	warmHits, warmMisses := cache.Stats()
+
+	if !bytes.Equal(cold, uncached) {
+		t.Error("cold cached run differs from an uncached run")
+	}
+	if !bytes.Equal(warm, cold) {
+		t.Errorf("warm run differs from cold run:\n%s\nwant:\n%s", warm, cold)
+	}
+	if coldHits != 0 || coldMisses == 0 {
+		t.Errorf("cold run Stats() = %d hits, %d misses, want only misses", coldHits, coldMisses)
+	}
+	if warmHits != coldMisses || warmMisses != coldMisses {
+		t.Errorf("warm run Stats() = %d hits, %d misses, want %d hits and no new misses", warmHits, warmMisses, coldMisses)
+	}
+}
+
+func TestRun_CacheInvalidatesEditedPackageAndImporters(t *testing.T) {
+	repo := repogen.Generate(t, repogen.Config{Packages: 4, Topology: repogen.Chain, FunctionsPerPackage: 2})
+	cache := openTestCache(t)
+	buildCached(t, repo.Root, cache)
+
+	// p0001 is imported by p0002, which is imported by p0003.
+	edited := filepath.Join(repo.Root, "p0001", "p.go")
+	source, err := os.ReadFile(edited)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source = append(source, "\n// Extra is added after the first run.\nfunc Extra() {}\n"...)
+	if err := os.WriteFile(edited, source, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	hitsBefore, missesBefore := cache.Stats()
+	warm := buildCached(t, repo.Root, cache)
+	hits, misses := cache.Stats()
+	if hits-hitsBefore != 1 || misses-missesBefore != 3 {
+		t.Errorf("after editing p0001: %d hits, %d misses, want 1 hit (p0000) and 3 misses", hits-hitsBefore, misses-missesBefore)
+	}
+	if want := buildCached(t, repo.Root, nil); !bytes.Equal(warm, want) {
+		t.Errorf("run after the edit differs from an uncached run:\n%s\nwant:\n%s", warm, want)
+	}
+}
+
+func TestCache_PackageKeysFollowReplacedDependency(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"app/go.mod":     "module example.com/app\n\ngo 1.24\n\nrequire example.com/dep v0.0.0\n\nreplace example.com/dep => ../dep\n",
+		"app/app.go":     "package app\n\nimport \"example.com/dep\"\n\nvar Value = dep.Value\n",
+		"dep/go.mod":     "module example.com/dep\n\ngo 1.24\n",
+		"dep/dep.go":     "package dep\n\nimport \"example.com/dep/inner\"\n\nvar Value = inner.Value\n",
+		"dep/inner/i.go": "package inner\n\nconst Value = 1\n",
+	}
+	for name, content := range files {
+		name = filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pkgs := loadTestPackages(t, filepath.Join(root, "app"))
+	cache := openTestCache(t)
+	before := cache.packageKeys(context.Background(), pkgs, Extractors())
+
+	// The edit is two packages away, in a package outside the run.
+	appendToFile(t, filepath.Join(root, "dep", "inner", "i.go"), "\nconst Other = 2\n")
+	after := cache.packageKeys(context.Background(), pkgs, Extractors())
+	if before[0] == "" || before[0] == after[0] {
+		t.Errorf("key of example.com/app = %q before and %q after editing its dependency, want a change", before[0], after[0])
+	}
+}
+
+func TestCache_PackageKeysFingerprintExtractors(t *testing.T) {
+	repo := repogen.Generate(t, repogen.Config{Packages: 1})
+	pkgs := loadTestPackages(t, repo.Root)
+	cache := openTestCache(t)
+	keyWith := func(extractors ...Extractor) string {
+		return cache.packageKeys(context.Background(), pkgs, extractors)[0]
+	}
+
+	// Equal configurations in distinct values share keys across runs.
+	markers := keyWith(&PackageExtractor{AnnotationMarkers: []string{"TODO"}}, &SymbolExtractor{SignatureExclude: []string{"error"}})
+	if again := keyWith(&PackageExtractor{AnnotationMarkers: []string{"TODO"}}, &SymbolExtractor{SignatureExclude: []string{"error"}}); again != markers {
+		t.Errorf("keys for equal extractor configurations differ: %q and %q", markers, again)
+	}
+	for name, key := range map[string]string{
+		"other markers":         keyWith(&PackageExtractor{AnnotationMarkers: []string{"FIXME"}}, &SymbolExtractor{SignatureExclude: []string{"error"}}),
+		"annotations":           keyWith(&PackageExtractor{AnnotationMarkers: []string{"TODO"}, IncludeAnnotations: true}, &SymbolExtractor{SignatureExclude: []string{"error"}}),
+		"other exclusions":      keyWith(&PackageExtractor{AnnotationMarkers: []string{"TODO"}}, &SymbolExtractor{}),
+		"another extractor set": keyWith(&PackageExtractor{AnnotationMarkers: []string{"TODO"}}, &CallExtractor{}),
+	} {
+		if key == markers {
+			t.Errorf("%s: key unchanged, want a new key", name)
+		}
+	}
+}
+
+func TestCache_TrimEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := openTestCache(t)
+	cache.MaxSize = 250
+	now := time.Now()
+	for i, name := range []string{"old", "middle", "new"} {
+		path := cache.entryPath(name)
+		if err := os.WriteFile(path, make([]byte, 100), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		modTime := now.Add(time.Duration(i) * time.Minute)
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	unrelated := filepath.Join(cache.Dir, "README")
+	if err := os.WriteFile(unrelated, make([]byte, 1000), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cache.Trim(); err != nil {
+		t.Fatalf("Trim() error = %v", err)
+	}
+	for name, wantKept := range map[string]bool{"old": false, "middle": true, "new": true} {
+		if _, err := os.Stat(cache.entryPath(name)); (err == nil) != wantKept {
+			t.Errorf("entry %s kept = %t, want %t", name, err == nil, wantKept)
+		}
+	}
+	if _, err := os.Stat(unrelated); err != nil {
+		t.Errorf("Trim() removed a file that is not an entry: %v", err)
+	}
+}
+
+func TestCache_TrimCountsFileIndex(t *testing.T) {
+	cache := openTestCache(t)
+	cache.MaxSize = 250
+	indexPath := filepath.Join(cache.Dir, fileIndexName)
+	now := time.Now()
+	for i, name := range []string{"old", "new"} {
+		path := cache.entryPath(name)
+		if err := os.WriteFile(path, make([]byte, 100), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		modTime := now.Add(time.Duration(i) * time.Minute)
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(indexPath, make([]byte, 100), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cache.Trim(); err != nil {
+		t.Fatalf("Trim() error = %v", err)
+	}
+	if _, err := os.Stat(cache.entryPath("old")); err == nil {
+		t.Error("Trim() kept the oldest entry although the index takes its room")
+	}
+	if _, err := os.Stat(indexPath); err != nil {
+		t.Errorf("Trim() removed an index that fits: %v", err)
+	}
+
+	cache.MaxSize = 50
+	if err := cache.Trim(); err != nil {
+		t.Fatalf("Trim() error = %v", err)
+	}
+	if _, err := os.Stat(indexPath); !os.IsNotExist(err) {
+		t.Errorf("Trim() kept an index larger than MaxSize: %v", err)
+	}
+}
+
+func TestCache_Clean(t *testing.T) {
+	repo := repogen.Generate(t, repogen.Config{Packages: 3, Topology: repogen.Chain})
+	cache := openTestCache(t)
+	buildCached(t, repo.Root, cache)
+
+	removed, size, err := cache.Clean()
+	if err != nil || removed != 3 || size == 0 {
+		t.Fatalf("Clean() = %d entries, %d bytes, %v, want 3 entries", removed, size, err)
+	}
+	if _, err := os.Stat(filepath.Join(cache.Dir, fileIndexName)); !os.IsNotExist(err) {
+		t.Errorf("Clean() kept the file index: %v", err)
+	}
+	if removed, _, _ := cache.Clean(); removed != 0 {
+		t.Errorf("second Clean() removed %d entries, want 0", removed)
+	}
+	if _, _, err := (&Cache{Dir: filepath.Join(cache.Dir, "missing")}).Clean(); err != nil {
+		t.Errorf("Clean() of a missing directory error = %v", err)
+	}
+}
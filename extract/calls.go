@@ -0,0 +1,131 @@
+package extract
+
+import (
+	"context"
+	"go/ast"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"github.com/Desgue/codegraph/graph"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// Function node attributes locating the declaration in its file, Files[0]:
+// the first and last line it spans, doc comment excluded.
+const (
+	AttributeStartLine = "start_line"
+	AttributeEndLine   = "end_line"
+)
+
+// CallExtractor emits a function node, with AttributeStartLine and
+// AttributeEndLine, for every function and method declared in a package, and
+// an edge for every static call to a function of a loaded package:
+// graph.EdgeTestCall, also IsTestOnly, from functions declared in _test.go
+// files and graph.EdgeCall from the others. Calls to packages outside the
+// run, such as the standard library, calls through interfaces or function
+// values, and init functions, which nothing can call, are left out. The coupling metrics then give each
+// function node AttributeFanIn, AttributeFanOut and AttributeTestFanIn.
+//
+// It is not one of the built-in extractors: function nodes multiply the
+// graph's size, so callers opt in. It emits the same node IDs as
+// SymbolExtractor, so the two cannot run together. Requires NeedSyntax,
+// NeedTypes and NeedTypesInfo; packages loaded without them get no nodes.
+type CallExtractor struct{}
+
+func (e *CallExtractor) Name() string {
+	return "calls"
+}
+
+func (e *CallExtractor) Extract(ctx context.Context, pkg *packages.Package, emitter graph.Emitter) error {
+	if pkg.TypesInfo == nil {
+		return nil
+	}
+	modulePath := ""
+	if pkg.Module != nil {
+		modulePath = pkg.Module.Path
+	}
+
+	for _, file := range pkg.Syntax {
+		fileName := pkg.Fset.Position(file.Pos()).Filename
+		edgeKind := graph.EdgeCall
+		if strings.HasSuffix(fileName, "_test.go") {
+			edgeKind = graph.EdgeTestCall
+		}
+		for _, declaration := range file.Decls {
+			funcDecl, isFunc := declaration.(*ast.FuncDecl)
+			if !isFunc || funcDecl.Name.Name == "_" || (funcDecl.Recv == nil && funcDecl.Name.Name == "init") {
+				continue
+			}
+			function, isFunction := pkg.TypesInfo.Defs[funcDecl.Name].(*types.Func)
+			if !isFunction {
+				continue
+			}
+			id := functionNodeID(function)
+			if id == "" {
+				continue
+			}
+			node := graph.Node{ID: id, Kind: graph.KindFunction, Name: funcDecl.Name.Name, ModulePath: modulePath, Files: []string{fileName}}
+			node.SetAttribute(AttributeStartLine, strconv.Itoa(pkg.Fset.Position(funcDecl.Pos()).Line))
+			node.SetAttribute(AttributeEndLine, strconv.Itoa(pkg.Fset.Position(funcDecl.End()).Line))
+			if err := emitter.EmitNode(node); err != nil {
+				return err
+			}
+			if funcDecl.Body == nil {
+				continue
+			}
+			for _, callee := range staticCallees(ctx, pkg.TypesInfo, funcDecl.Body) {
+				if callee == id {
+					continue
+				}
+				if err := emitter.EmitEdge(graph.Edge{From: id, To: callee, Kind: edgeKind, IsTestOnly: edgeKind == graph.EdgeTestCall}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// staticCallees returns the node IDs of the functions of loaded packages
+// called in body, including from function literals, without duplicates and in
+// call order.
+func staticCallees(ctx context.Context, info *types.Info, body *ast.BlockStmt) []string {
+	var callees []string
+	seen := make(map[string]bool)
+	ast.Inspect(body, func(node ast.Node) bool {
+		call, isCall := node.(*ast.CallExpr)
+		if !isCall {
+			return true
+		}
+		callee := typeutil.StaticCallee(info, call)
+		if callee == nil || callee.Pkg() == nil || !IsLoaded(ctx, callee.Pkg().Path()) {
+			return true
+		}
+		if id := functionNodeID(callee.Origin()); id != "" && !seen[id] {
+			seen[id] = true
+			callees = append(callees, id)
+		}
+		return true
+	})
+	return callees
+}
+
+// functionNodeID returns the graph.FuncID of a declared function or method,
+// or "" for methods whose receiver is not a named type.
+func functionNodeID(function *types.Func) string {
+	signature := function.Type().(*types.Signature)
+	if signature.Recv() == nil {
+		return graph.FuncID(function.Pkg().Path(), "", function.Name())
+	}
+	receiver := types.Unalias(signature.Recv().Type())
+	if pointer, isPointer := receiver.(*types.Pointer); isPointer {
+		receiver = pointer.Elem()
+	}
+	named, isNamed := receiver.(*types.Named)
+	if !isNamed {
+		return ""
+	}
+	return graph.FuncID(function.Pkg().Path(), named.Origin().Obj().Name(), function.Name())
+}
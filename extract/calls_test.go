@@ -0,0 +1,120 @@
+package extract
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+func TestCallExtractor(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/calls\n\ngo 1.24\n",
+		"store/store.go": "package store\n\nimport \"strings\"\n\ntype Store struct{ items []string }\n\nfunc New() *Store { return &Store{} }\n\n" +
+			"func (s *Store) Put(item string) {\n\ts.items = append(s.items, normalize(item))\n}\n\n" +
+			"func normalize(item string) string {\n\treturn strings.TrimSpace(item)\n}\n",
+		"store/store_test.go": "package store\n\nimport \"testing\"\n\nfunc TestPut(t *testing.T) {\n\ts := New()\n\ts.Put(\" a \")\n\ts.Put(\"b\")\n}\n",
+		// Serve is also referenced as a value and called from init and
+		// through an interface, none of which is a static call edge.
+		"api/api.go": "package api\n\nimport \"example.com/calls/store\"\n\nvar sink func()\n\nfunc init() { Serve() }\n\n" +
+			"func Serve() {\n\ts := store.New()\n\tdefer func() { s.Put(\"done\") }()\n\ts.Put(\"x\")\n\tsink = Serve\n" +
+			"\tvar p interface{ Put(string) } = s\n\tp.Put(\"y\")\n}\n",
+	}
+	for name, content := range files {
+		name = filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pkgs := loadTestPackages(t, root)
+
+	build := func(workers int) *graph.Graph {
+		ctx := WithConcurrency(context.Background(), workers)
+		callGraph, failures, err := Build(ctx, pkgs, []Extractor{&PackageExtractor{}, &ImportExtractor{}, &CallExtractor{}})
+		if err != nil || len(failures) > 0 {
+			t.Fatalf("Build() failures = %v, err = %v", failures, err)
+		}
+		return callGraph
+	}
+	callGraph := build(1)
+
+	var (
+		newID       = graph.FuncID("example.com/calls/store", "", "New")
+		putID       = graph.FuncID("example.com/calls/store", "Store", "Put")
+		normalizeID = graph.FuncID("example.com/calls/store", "", "normalize")
+		testPutID   = graph.FuncID("example.com/calls/store", "", "TestPut")
+		serveID     = graph.FuncID("example.com/calls/api", "", "Serve")
+	)
+	wantEdges := map[graph.EdgeKey]bool{
+		{From: putID, To: normalizeID, Kind: graph.EdgeCall}:   true,
+		{From: serveID, To: newID, Kind: graph.EdgeCall}:       true,
+		{From: serveID, To: putID, Kind: graph.EdgeCall}:       true,
+		{From: testPutID, To: newID, Kind: graph.EdgeTestCall}: true,
+		{From: testPutID, To: putID, Kind: graph.EdgeTestCall}: true,
+	}
+	for _, edge := range callGraph.Edges() {
+		if edge.Kind != graph.EdgeCall && edge.Kind != graph.EdgeTestCall {
+			continue
+		}
+		if !wantEdges[edge.Key()] {
+			t.Errorf("unexpected edge %+v", edge.Key())
+		}
+		if edge.IsTestOnly != (edge.Kind == graph.EdgeTestCall) {
+			t.Errorf("edge %+v IsTestOnly = %t", edge.Key(), edge.IsTestOnly)
+		}
+		delete(wantEdges, edge.Key())
+	}
+	for missing := range wantEdges {
+		t.Errorf("missing edge %+v", missing)
+	}
+
+	tests := []struct {
+		id                       string
+		file                     string
+		startLine, endLine       string
+		fanIn, fanOut, testFanIn string
+	}{
+		{id: newID, file: "store/store.go", startLine: "7", endLine: "7", fanIn: "1", fanOut: "0", testFanIn: "1"},
+		{id: putID, file: "store/store.go", startLine: "9", endLine: "11", fanIn: "1", fanOut: "1", testFanIn: "1"},
+		{id: normalizeID, file: "store/store.go", startLine: "13", endLine: "15", fanIn: "1", fanOut: "0", testFanIn: "0"},
+		{id: testPutID, file: "store/store_test.go", startLine: "5", endLine: "9", fanIn: "0", fanOut: "0", testFanIn: "0"},
+		{id: serveID, file: "api/api.go", startLine: "9", endLine: "16", fanIn: "0", fanOut: "2", testFanIn: "0"},
+	}
+	for _, tt := range tests {
+		node, found := callGraph.Node(tt.id)
+		if !found {
+			t.Errorf("no function node %s", tt.id)
+			continue
+		}
+		if len(node.Files) != 1 || node.Files[0] != filepath.Join(root, tt.file) {
+			t.Errorf("%s files = %v, want [%s]", tt.id, node.Files, tt.file)
+		}
+		got := []string{node.Attributes[AttributeStartLine], node.Attributes[AttributeEndLine],
+			node.Attributes[AttributeFanIn], node.Attributes[AttributeFanOut], node.Attributes[AttributeTestFanIn]}
+		want := []string{tt.startLine, tt.endLine, tt.fanIn, tt.fanOut, tt.testFanIn}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s start_line, end_line, fan_in, fan_out, test_fan_in = %q, want %q", tt.id, got, want)
+		}
+	}
+	if _, found := callGraph.Node(graph.FuncID("example.com/calls/api", "", "init")); found {
+		t.Error("init function has a node")
+	}
+
+	// The parallel pipeline yields the same nodes, attributes and edges.
+	for range 5 {
+		parallel := build(8)
+		if !reflect.DeepEqual(parallel.Nodes(), callGraph.Nodes()) {
+			t.Fatal("nodes of a parallel build differ from a sequential one")
+		}
+		if got, want := sortedEdgeKeys(parallel), sortedEdgeKeys(callGraph); !reflect.DeepEqual(got, want) {
+			t.Fatalf("edges of a parallel build = %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package extract
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/Desgue/codegraph/graph"
+	"golang.org/x/tools/go/packages"
+)
+
+// Extractor adds nodes and edges for one package. Extractors run concurrently
+// on different packages, so Extract must not share mutable state across calls.
+// Edges may reference nodes emitted by other extractors or for other packages.
+// Extract must not close emitter.
+type Extractor interface {
+	Name() string
+	Extract(ctx context.Context, pkg *packages.Package, emitter graph.Emitter) error
+}
+
+// Fingerprinter is implemented by extractors whose output depends on their
+// configuration. Fingerprint describes that configuration, stably across runs,
+// for the extraction cache keys: extractors with the same Name, type and
+// fingerprint must emit the same elements for a package. Extractors without
+// it are assumed to have no configuration.
+type Fingerprinter interface {
+	Fingerprint() string
+}
+
+var extractorRegistry = struct {
+	sync.RWMutex
+	extractors []Extractor
+}{
+	extractors: []Extractor{&PackageExtractor{}, &ImportExtractor{}},
+}
+
+// RegisterExtractor adds extractor to the set run by the CLI and returned by
+// Extractors. Register custom extractors before extraction starts. Returns an
+// error if an extractor with the same name is already registered.
+func RegisterExtractor(extractor Extractor) error {
+	extractorRegistry.Lock()
+	defer extractorRegistry.Unlock()
+
+	for _, registered := range extractorRegistry.extractors {
+		if registered.Name() == extractor.Name() {
+			return fmt.Errorf("extractor %q is already registered", extractor.Name())
+		}
+	}
+	extractorRegistry.extractors = append(extractorRegistry.extractors, extractor)
+	return nil
+}
+
+// Extractors returns the built-in extractors followed by registered ones, in
+// registration order.
+func Extractors() []Extractor {
+	extractorRegistry.RLock()
+	defer extractorRegistry.RUnlock()
+
+	return append([]Extractor(nil), extractorRegistry.extractors...)
+}
+
+type loadedPackagesKey struct{}
+
+// IsLoaded reports whether pkgPath is one of the packages in the current
+// extraction run, so extractors can skip edges to packages outside the graph.
+// It returns true when ctx does not come from Run.
+func IsLoaded(ctx context.Context, pkgPath string) bool {
+	loaded, found := ctx.Value(loadedPackagesKey{}).(map[string]bool)
+	return !found || loaded[pkgPath]
+}
+
+func loadedSet(pkgs []*packages.Package) map[string]bool {
+	loaded := make(map[string]bool, len(pkgs))
+	for _, pkg := range pkgs {
+		loaded[pkg.PkgPath] = true
+	}
+	return loaded
+}
@@ -0,0 +1,216 @@
+package extract
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// fileIndexName names the file in the cache directory that records the
+// size, modification time and hash of every file hashed for a key. It does
+// not end in cacheEntrySuffix, so Trim tells it apart from the entries.
+const fileIndexName = "files.json"
+
+// fileIndexMaxAge is how long the index keeps the record of a file no run
+// has hashed, such as one of a tree no longer parsed. Seen times are only
+// refreshed once per fileIndexSeenInterval, so unchanged trees do not
+// rewrite the index on every run.
+const (
+	fileIndexMaxAge       = 30 * 24 * time.Hour
+	fileIndexSeenInterval = 24 * time.Hour
+)
+
+// racyWindow is how long before it was hashed a file must have last been
+// modified for its record to be trusted. A file written again right after
+// being hashed can keep its modification time, since filesystems record it
+// with a granularity of up to two seconds (FAT), and an edit keeping the size
+// would then go unnoticed.
+const racyWindow = 2 * time.Second
+
+// fileRecord is what the file index knows about one file. Times are Unix
+// nanoseconds, Seen being the last run that needed the file; Sum is the hex
+// SHA-256 of the content.
+type fileRecord struct {
+	Size    int64  `json:"size"`
+	ModTime int64  `json:"mod_time"`
+	Hashed  int64  `json:"hashed"`
+	Seen    int64  `json:"seen"`
+	Sum     string `json:"sum"`
+}
+
+// matches reports whether the file described by info is unchanged since the
+// record was taken, as far as its size and modification time tell. Records
+// taken within racyWindow of the modification are ambiguous and never match.
+func (r fileRecord) matches(info os.FileInfo) bool {
+	modTime := info.ModTime().UnixNano()
+	return r.Size == info.Size() && r.ModTime == modTime && r.Hashed-modTime >= int64(racyWindow)
+}
+
+// hashedFile is the outcome of hashing one file: its record, whether the
+// record is new, and whether the file could be read at all.
+type hashedFile struct {
+	record   fileRecord
+	rehashed bool
+	ok       bool
+}
+
+// hashFiles returns the hex SHA-256 of each readable file in names. Unless
+// the cache is Paranoid, files whose size and modification time match the
+// file index reuse the recorded hash instead of being read. Files are hashed
+// by workerCount(ctx) workers, each reusing one read buffer. The index is
+// written back only when a record changed: a file was rehashed or is gone,
+// a seen time is due for a refresh, or a record expired.
+func (c *Cache) hashFiles(ctx context.Context, names []string) map[string]string {
+	index := c.readFileIndex()
+	lookup := index
+	if c.Paranoid {
+		lookup = nil
+	}
+
+	results := make([]hashedFile, len(names))
+	jobs := make(chan int)
+	var workers sync.WaitGroup
+	for range min(workerCount(ctx), len(names)) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			var buffer []byte
+			for i := range jobs {
+				results[i], buffer = hashFileContent(names[i], lookup, buffer)
+			}
+		}()
+	}
+	for i := range names {
+		jobs <- i
+	}
+	close(jobs)
+	workers.Wait()
+
+	if index == nil {
+		index = make(map[string]fileRecord, len(names))
+	}
+	now := time.Now().UnixNano()
+	changed := false
+	sums := make(map[string]string, len(names))
+	for i, result := range results {
+		if !result.ok {
+			if _, found := index[names[i]]; found {
+				delete(index, names[i])
+				changed = true
+			}
+			continue
+		}
+		sums[names[i]] = result.record.Sum
+		if result.rehashed || now-result.record.Seen >= int64(fileIndexSeenInterval) {
+			result.record.Seen = now
+			index[names[i]] = result.record
+			changed = true
+		}
+	}
+	for name, record := range index {
+		if now-record.Seen > int64(fileIndexMaxAge) {
+			delete(index, name)
+			changed = true
+		}
+	}
+	if changed {
+		c.writeFileIndex(index)
+	}
+	return sums
+}
+
+// hashFileContent hashes the file name, or takes its hash from index when
+// its record matches, and returns buffer for reuse.
+func hashFileContent(name string, index map[string]fileRecord, buffer []byte) (hashedFile, []byte) {
+	if record, found := index[name]; found {
+		if info, err := os.Stat(name); err == nil && record.matches(info) {
+			return hashedFile{record: record, ok: true}, buffer
+		}
+	}
+
+	hashed := time.Now()
+	file, err := os.Open(name)
+	if err != nil {
+		return hashedFile{}, buffer
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return hashedFile{}, buffer
+	}
+	buffer, err = readAll(file, buffer[:0], info.Size())
+	if err != nil {
+		return hashedFile{}, buffer
+	}
+	sum := sha256.Sum256(buffer)
+	record := fileRecord{Size: info.Size(), ModTime: info.ModTime().UnixNano(), Hashed: hashed.UnixNano(), Sum: hex.EncodeToString(sum[:])}
+	return hashedFile{record: record, rehashed: true, ok: true}, buffer
+}
+
+// readAll appends the rest of reader to buffer, like os.ReadFile but growing
+// buffer to the expected size up front instead of allocating a new one.
+func readAll(reader io.Reader, buffer []byte, size int64) ([]byte, error) {
+	// One spare byte lets the read returning io.EOF land without growing.
+	buffer = slices.Grow(buffer, int(size)+1)
+	for {
+		if len(buffer) == cap(buffer) {
+			buffer = append(buffer, 0)[:len(buffer)]
+		}
+		n, err := reader.Read(buffer[len(buffer):cap(buffer)])
+		buffer = buffer[:len(buffer)+n]
+		if err == io.EOF {
+			return buffer, nil
+		}
+		if err != nil {
+			return buffer, err
+		}
+	}
+}
+
+// readFileIndex returns the file index, empty when it is missing or
+// unreadable.
+func (c *Cache) readFileIndex() map[string]fileRecord {
+	content, err := os.ReadFile(filepath.Join(c.Dir, fileIndexName))
+	if err != nil {
+		return nil
+	}
+	var index map[string]fileRecord
+	if err := json.Unmarshal(content, &index); err != nil {
+		return nil
+	}
+	return index
+}
+
+// writeFileIndex replaces the file index with index. Records of files this
+// run did not hash stay until they expire, so runs over different trees
+// sharing the cache do not evict each other's records; a concurrent run's
+// updates may be lost, which only costs a rehash. Like store, it writes a
+// temporary file and renames it.
+func (c *Cache) writeFileIndex(index map[string]fileRecord) {
+	content, err := json.Marshal(index)
+	if err != nil {
+		return
+	}
+
+	file, err := os.CreateTemp(c.Dir, fileIndexName+".tmp*")
+	if err != nil {
+		return
+	}
+	_, err = file.Write(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), filepath.Join(c.Dir, fileIndexName))
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+	}
+}
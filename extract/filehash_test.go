@@ -0,0 +1,252 @@
+package extract
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Desgue/codegraph/internal/testutil/repogen"
+	codeparser "github.com/Desgue/codegraph/parser"
+	"golang.org/x/tools/go/packages"
+)
+
+func TestCache_PackageKeysAfterEdits(t *testing.T) {
+	// An edit of p0001 that keeps its size.
+	sameSize := func(source []byte) []byte {
+		return bytes.Replace(source, []byte("// F0 combines"), []byte("// F0 COMBINES"), 1)
+	}
+	unchanged := func(source []byte) []byte { return source }
+
+	tests := []struct {
+		name string
+		// settled backdates the files before the first keys, as in a tree
+		// last edited well before the run.
+		settled bool
+		edit    func(source []byte) []byte
+		// keepModTime restores the modification time after the edit.
+		keepModTime bool
+		paranoid    bool
+		wantChanged bool
+	}{
+		{name: "touch", settled: true, edit: unchanged, wantChanged: false},
+		{name: "same size edit", settled: true, edit: sameSize, wantChanged: true},
+		{
+			// The first keys were computed right after the files were
+			// written, so the recorded size and time are ambiguous.
+			name: "same size edit keeping a recent modification time", edit: sameSize, keepModTime: true, wantChanged: true,
+		},
+		{
+			// The fast path cannot see this edit; only hashing does.
+			name: "same size edit keeping a settled modification time", settled: true, edit: sameSize, keepModTime: true, wantChanged: false,
+		},
+		{name: "paranoid", settled: true, edit: sameSize, keepModTime: true, paranoid: true, wantChanged: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			// p0002 imports p0001, which imports p0000.
+			repo := repogen.Generate(t, repogen.Config{Packages: 3, Topology: repogen.Chain, FunctionsPerPackage: 2})
+			pkgs := loadTestPackages(t, repo.Root)
+			edited := filepath.Join(repo.Root, "p0001", "p.go")
+			if tt.settled {
+				backdate(t, repo.Root, time.Hour)
+			}
+			cache := openTestCache(t)
+			before := cache.packageKeys(ctx, pkgs, Extractors())
+
+			info, err := os.Stat(edited)
+			if err != nil {
+				t.Fatal(err)
+			}
+			source, err := os.ReadFile(edited)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(edited, tt.edit(source), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			modTime := time.Now().Add(time.Minute)
+			if tt.keepModTime {
+				modTime = info.ModTime()
+			}
+			if err := os.Chtimes(edited, modTime, modTime); err != nil {
+				t.Fatal(err)
+			}
+
+			// A new Cache shares only the directory, and so the file index.
+			after := (&Cache{Dir: cache.Dir, Paranoid: tt.paranoid}).packageKeys(ctx, pkgs, Extractors())
+			if before[0] != after[0] {
+				t.Errorf("key of p0000 changed, want it unchanged")
+			}
+			for i := 1; i < len(pkgs); i++ {
+				if changed := before[i] != after[i]; changed != tt.wantChanged {
+					t.Errorf("key of %s changed = %t, want %t", pkgs[i].PkgPath, changed, tt.wantChanged)
+				}
+			}
+		})
+	}
+}
+
+func TestCache_PackageKeysWithoutIndex(t *testing.T) {
+	ctx := context.Background()
+	repo := repogen.Generate(t, repogen.Config{Packages: 3, Topology: repogen.Chain, TestFiles: true})
+	pkgs := loadTestPackages(t, repo.Root)
+	backdate(t, repo.Root, time.Hour)
+
+	cache := openTestCache(t)
+	cold := cache.packageKeys(ctx, pkgs, Extractors())
+	indexed := cache.packageKeys(ctx, pkgs, Extractors())
+	if err := os.WriteFile(filepath.Join(cache.Dir, fileIndexName), []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	corrupt := cache.packageKeys(ctx, pkgs, Extractors())
+	paranoid := (&Cache{Dir: cache.Dir, Paranoid: true}).packageKeys(ctx, pkgs, Extractors())
+	for i, pkg := range pkgs {
+		if cold[i] == "" || indexed[i] != cold[i] || corrupt[i] != cold[i] || paranoid[i] != cold[i] {
+			t.Errorf("keys of %s = %q cold, %q indexed, %q with a corrupt index, %q paranoid, want equal keys",
+				pkg.PkgPath, cold[i], indexed[i], corrupt[i], paranoid[i])
+		}
+	}
+
+	if err := os.Remove(filepath.Join(repo.Root, "p0000", "p.go")); err != nil {
+		t.Fatal(err)
+	}
+	if keys := cache.packageKeys(ctx, pkgs, Extractors()); keys[0] != "" || keys[2] != "" {
+		t.Errorf("keys after removing a file of p0000 = %q, want none for p0000 and its importers", keys)
+	}
+}
+
+func TestCache_FileIndexUpkeep(t *testing.T) {
+	ctx := context.Background()
+	repo := repogen.Generate(t, repogen.Config{Packages: 2, Topology: repogen.Chain})
+	pkgs := loadTestPackages(t, repo.Root)
+	backdate(t, repo.Root, time.Hour)
+	cache := openTestCache(t)
+	indexPath := filepath.Join(cache.Dir, fileIndexName)
+
+	// Records of files from another tree: one seen recently, one expired.
+	now := time.Now()
+	other := map[string]fileRecord{
+		"/other/recent.go":  {Size: 1, Seen: now.Add(-time.Hour).UnixNano(), Sum: "00"},
+		"/other/expired.go": {Size: 1, Seen: now.Add(-fileIndexMaxAge - time.Hour).UnixNano(), Sum: "00"},
+	}
+	cache.writeFileIndex(other)
+	cache.packageKeys(ctx, pkgs, Extractors())
+	index := cache.readFileIndex()
+	if _, found := index["/other/recent.go"]; !found {
+		t.Error("index dropped the record of a file seen recently")
+	}
+	if _, found := index["/other/expired.go"]; found {
+		t.Error("index kept an expired record")
+	}
+	edited := filepath.Join(repo.Root, "p0000", "p.go")
+	if _, found := index[edited]; !found {
+		t.Fatalf("index has no record of %s: %v", edited, index)
+	}
+
+	// Nothing changed, so the index is not rewritten.
+	old := now.Add(-time.Hour)
+	if err := os.Chtimes(indexPath, old, old); err != nil {
+		t.Fatal(err)
+	}
+	cache.packageKeys(ctx, pkgs, Extractors())
+	if info, err := os.Stat(indexPath); err != nil || !info.ModTime().Equal(old) {
+		t.Errorf("index rewritten by a run with nothing to record: %v", err)
+	}
+
+	if err := os.Remove(edited); err != nil {
+		t.Fatal(err)
+	}
+	cache.packageKeys(ctx, pkgs, Extractors())
+	if _, found := cache.readFileIndex()[edited]; found {
+		t.Errorf("index kept the record of removed file %s", edited)
+	}
+}
+
+func TestReadAll(t *testing.T) {
+	var buffer []byte
+	for _, content := range []string{strings.Repeat("long line\n", 100), "short", "", strings.Repeat("x", 5000)} {
+		var err error
+		// A wrong size hint only costs a reallocation.
+		for _, size := range []int64{int64(len(content)), 0, 3} {
+			buffer, err = readAll(strings.NewReader(content), buffer[:0], size)
+			if err != nil || string(buffer) != content {
+				t.Errorf("readAll() with size %d = %q, %v, want %d bytes", size, buffer, err, len(content))
+			}
+		}
+	}
+}
+
+// backdate sets the modification time of every file under root to age ago.
+func backdate(t *testing.T, root string, age time.Duration) {
+	t.Helper()
+
+	modTime := time.Now().Add(-age)
+	err := filepath.WalkDir(root, func(name string, entry os.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		return os.Chtimes(name, modTime, modTime)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// BenchmarkPackageKeys measures computing the cache keys of the largest
+// generated module: hashing every file, with the index from an earlier run
+// trusted, and with the index ignored (Paranoid).
+func BenchmarkPackageKeys(b *testing.B) {
+	size := benchmarkSizes[len(benchmarkSizes)-1]
+	repo := benchmarkRepo(b, size)
+	pkgs, errorCount, err := codeparser.Load(repo.Root, true)
+	if err != nil || errorCount > 0 {
+		b.Fatalf("Load() = %d errors, %v", errorCount, err)
+	}
+	modTime := time.Now().Add(-time.Hour)
+	for _, pkg := range pkgs {
+		for _, file := range pkg.GoFiles {
+			if err := os.Chtimes(file, modTime, modTime); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	for _, mode := range []struct {
+		name     string
+		paranoid bool
+		indexed  bool
+	}{
+		{name: "cold"},
+		{name: "indexed", indexed: true},
+		{name: "paranoid", indexed: true, paranoid: true},
+	} {
+		b.Run(fmt.Sprintf("packages=%d/%s", size, mode.name), func(b *testing.B) {
+			benchmarkPackageKeys(b, pkgs, mode.indexed, mode.paranoid)
+		})
+	}
+}
+
+func benchmarkPackageKeys(b *testing.B, pkgs []*packages.Package, indexed, paranoid bool) {
+	ctx := context.Background()
+	cache := &Cache{Dir: b.TempDir(), Paranoid: paranoid}
+	if indexed {
+		(&Cache{Dir: cache.Dir}).packageKeys(ctx, pkgs, Extractors())
+	}
+	b.ResetTimer()
+	for b.Loop() {
+		if !indexed {
+			b.StopTimer()
+			if err := os.Remove(filepath.Join(cache.Dir, fileIndexName)); err != nil && !os.IsNotExist(err) {
+				b.Fatal(err)
+			}
+			b.StartTimer()
+		}
+		cache.packageKeys(ctx, pkgs, Extractors())
+	}
+}
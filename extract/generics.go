@@ -0,0 +1,33 @@
+package extract
+
+import (
+	"strconv"
+
+	"github.com/Desgue/codegraph/graph"
+	"github.com/Desgue/codegraph/parser"
+	"golang.org/x/tools/go/packages"
+)
+
+// Attribute names under which package nodes record parser.CountGenerics.
+// They are only set on packages that declare or instantiate generics, so
+// graphs of codebases without generics stay unchanged.
+const (
+	AttributeGenericFuncs           = "generic_funcs"
+	AttributeGenericTypes           = "generic_types"
+	AttributeConstraintInterfaces   = "constraint_interfaces"
+	AttributeInstantiations         = "instantiations"
+	AttributeDeferredInstantiations = "deferred_instantiations"
+)
+
+// applyGenerics records the package's generics counts on its node.
+func applyGenerics(node *graph.Node, pkg *packages.Package) {
+	counts := parser.CountGenerics(pkg)
+	if counts == (parser.GenericCounts{}) {
+		return
+	}
+	node.SetAttribute(AttributeGenericFuncs, strconv.Itoa(counts.Functions))
+	node.SetAttribute(AttributeGenericTypes, strconv.Itoa(counts.Types))
+	node.SetAttribute(AttributeConstraintInterfaces, strconv.Itoa(counts.ConstraintInterfaces))
+	node.SetAttribute(AttributeInstantiations, strconv.Itoa(counts.Instantiations))
+	node.SetAttribute(AttributeDeferredInstantiations, strconv.Itoa(counts.DeferredInstantiations))
+}
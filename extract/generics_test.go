@@ -0,0 +1,61 @@
+package extract
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"maps"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestPackageExtractor_Generics(t *testing.T) {
+	fileSet := token.NewFileSet()
+	sources := map[string]string{
+		"coll":  "package coll\n\ntype Set[T comparable] map[T]bool\n\nfunc Keys[K comparable, V any](m map[K]V) []K {\n\t_ = Set[K]{}\n\treturn nil\n}\n\nvar _ = Keys(map[string]int{})\n",
+		"plain": "package plain\n\nfunc Run() {}\n",
+	}
+	var pkgs []*packages.Package
+	for _, name := range []string{"coll", "plain"} {
+		file, err := parser.ParseFile(fileSet, name+".go", sources[name], 0)
+		if err != nil {
+			t.Fatalf("failed to parse %s: %v", name, err)
+		}
+		info := &types.Info{Uses: make(map[*ast.Ident]types.Object), Instances: make(map[*ast.Ident]types.Instance)}
+		typesPackage, err := (&types.Config{}).Check("example.com/mod/"+name, fileSet, []*ast.File{file}, info)
+		if err != nil {
+			t.Fatalf("failed to type-check %s: %v", name, err)
+		}
+		pkgs = append(pkgs, &packages.Package{PkgPath: typesPackage.Path(), Name: name, Fset: fileSet,
+			Syntax: []*ast.File{file}, Types: typesPackage, TypesInfo: info})
+	}
+
+	g, _, err := Build(context.Background(), pkgs, []Extractor{&PackageExtractor{}})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	want := map[string]string{
+		AttributeGenericFuncs:           "1",
+		AttributeGenericTypes:           "1",
+		AttributeConstraintInterfaces:   "0",
+		AttributeInstantiations:         "1",
+		AttributeDeferredInstantiations: "1",
+	}
+	coll, _ := g.Node("example.com/mod/coll")
+	got := make(map[string]string)
+	for name := range want {
+		got[name] = coll.Attributes[name]
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("coll generics attributes = %v, want %v", got, want)
+	}
+
+	plain, _ := g.Node("example.com/mod/plain")
+	if _, found := plain.Attributes[AttributeGenericFuncs]; found {
+		t.Errorf("expected no generics attributes on a package without generics, got %v", plain.Attributes)
+	}
+}
@@ -0,0 +1,197 @@
+package extract
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Desgue/codegraph/graph"
+	"github.com/Desgue/codegraph/parser"
+	"golang.org/x/tools/go/packages"
+)
+
+// BuildImportGraph converts loaded packages into a package-level import graph
+// using only the built-in extractors. Every package becomes a node identified
+// by its import path. Imports are only recorded as edges when the imported
+// package is itself part of pkgs, so the graph describes the loaded codebase
+// rather than its external dependencies. Edges are added in sorted order so the
+// result is deterministic. Package metrics are recorded as node attributes
+// (see the Attribute constants). Packages an extractor failed on are
+// reported as failures, as by Build; the graph is complete otherwise.
+func BuildImportGraph(pkgs []*packages.Package) (*graph.Graph, []Failure) {
+	// Build only fails when its context is cancelled, which the background
+	// context never is.
+	importGraph, failures, _ := Build(context.Background(), pkgs, []Extractor{&PackageExtractor{}, &ImportExtractor{}})
+	return importGraph, failures
+}
+
+// PackageExtractor emits one node per package, carrying its type counts, the
+// metrics computed from its own syntax and the count of its marker comments.
+// The zero value counts parser.DefaultAnnotationMarkers.
+type PackageExtractor struct {
+	// AnnotationMarkers replaces the markers counted in AttributeTodoCount.
+	AnnotationMarkers []string
+	// IncludeAnnotations also records every marker comment in AttributeTodos.
+	IncludeAnnotations bool
+}
+
+func (e *PackageExtractor) Name() string {
+	return "packages"
+}
+
+func (e *PackageExtractor) Fingerprint() string {
+	return fmt.Sprintf("markers=%q annotations=%t", e.AnnotationMarkers, e.IncludeAnnotations)
+}
+
+func (e *PackageExtractor) Extract(ctx context.Context, pkg *packages.Package, emitter graph.Emitter) error {
+	node := newPackageNode(pkg)
+	if err := applyAnnotations(node, pkg, e.AnnotationMarkers, e.IncludeAnnotations); err != nil {
+		return err
+	}
+	return emitter.EmitNode(*node)
+}
+
+// AttributeImportAlias is the import edge attribute listing the names, comma
+// separated and sorted, under which the importing package renames the import,
+// e.g. "ctxutil" for `import ctxutil "example.com/mod/context"`. Blank and
+// dot imports are not renames. It is absent when no file renames the import.
+const AttributeImportAlias = "import_alias"
+
+// AttributeImportUsage is the import edge attribute recording which of the
+// importing package's files declare the import: parser.ImportProduction,
+// parser.ImportTestOnly or parser.ImportMixed. It is absent for packages
+// loaded without syntax.
+const AttributeImportUsage = "import_usage"
+
+// ImportExtractor emits an edge for every import of a loaded package. Imports
+// declared only in test files get the EdgeTestImport kind so production
+// architecture can be analyzed without them.
+type ImportExtractor struct{}
+
+func (e *ImportExtractor) Name() string {
+	return "imports"
+}
+
+func (e *ImportExtractor) Extract(ctx context.Context, pkg *packages.Package, emitter graph.Emitter) error {
+	usage := parser.ClassifyImports(pkg)
+	aliases := importAliases(pkg)
+	uses := parser.CountImportUses(pkg)
+	for _, importPath := range sortedImportPaths(pkg) {
+		if !IsLoaded(ctx, importPath) {
+			continue
+		}
+		kind := graph.EdgeImport
+		if usage[importPath] == parser.ImportTestOnly {
+			kind = graph.EdgeTestImport
+		}
+		edge := graph.Edge{From: graph.PackageID(pkg.PkgPath), To: graph.PackageID(importPath), Kind: kind,
+			IsTestOnly: kind == graph.EdgeTestImport, Multiplicity: uses[importPath]}
+		if usage[importPath] != "" {
+			edge.SetAttribute(AttributeImportUsage, usage[importPath])
+		}
+		if len(aliases[importPath]) > 0 {
+			edge.SetAttribute(AttributeImportAlias, strings.Join(aliases[importPath], ","))
+		}
+		if err := emitter.EmitEdge(edge); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newPackageNode(pkg *packages.Package) *graph.Node {
+	node := &graph.Node{
+		ID:    graph.PackageID(pkg.PkgPath),
+		Kind:  graph.KindPackage,
+		Name:  pkg.Name,
+		Files: pkg.GoFiles,
+	}
+	if len(pkg.GoFiles) > 0 {
+		node.DirPath = filepath.Dir(pkg.GoFiles[0])
+	}
+	if pkg.Module != nil {
+		node.ModulePath = pkg.Module.Path
+		node.ModuleVersion = pkg.Module.Version
+		node.GoVersion = pkg.Module.GoVersion
+	}
+	node.InterfaceCount, node.ConcreteTypeCount = parser.CountTypes(pkg)
+	node.ExportedFuncCount, node.ExportedTypeCount = countExportedDecls(pkg)
+	node.HasMainFunc = parser.HasMainFunc(pkg)
+	node.Testable = parser.HasTests(pkg)
+	node.UnsafeUsage = parser.UsesUnsafe(pkg)
+	node.ProtobufGenerated = parser.IsProtobufGenerated(pkg)
+	node.GoCGO = parser.RequiresCGO(pkg)
+	node.EmbedCount = len(parser.ExtractEmbeds(pkg))
+	node.TestDependencies = parser.TestOnlyImports(pkg)
+	node.TestFramework = parser.DetectTestFramework(pkg)
+	node.BuildConstraints = parser.ExtractBuildConstraints(pkg)
+	applySourceMetrics(node, pkg)
+	applyGenerics(node, pkg)
+	return node
+}
+
+// countExportedDecls counts the exported functions and methods, and the
+// exported types, parser.ExportedDecls finds in pkg.
+func countExportedDecls(pkg *packages.Package) (funcs, types int) {
+	for _, decl := range parser.ExportedDecls(pkg) {
+		switch decl.Kind {
+		case "func":
+			funcs++
+		case "type":
+			types++
+		}
+	}
+	return funcs, types
+}
+
+// importAliases returns the distinct names each import path is renamed to
+// across the package's files, sorted.
+func importAliases(pkg *packages.Package) map[string][]string {
+	aliases := make(map[string][]string)
+	for _, file := range pkg.Syntax {
+		for _, importSpec := range file.Imports {
+			importPath, err := strconv.Unquote(importSpec.Path.Value)
+			if err != nil || importSpec.Name == nil {
+				continue
+			}
+			name := importSpec.Name.Name
+			if name == "_" || name == "." || slices.Contains(aliases[importPath], name) {
+				continue
+			}
+			aliases[importPath] = append(aliases[importPath], name)
+		}
+	}
+	for _, names := range aliases {
+		sort.Strings(names)
+	}
+	return aliases
+}
+
+// sortedImportPaths returns the package's imports from both the resolved
+// Imports and the import declarations in its syntax. go/packages drops the
+// import that closes an import cycle from Imports, so the declarations are
+// needed for cycles to appear in the graph.
+func sortedImportPaths(pkg *packages.Package) []string {
+	seen := make(map[string]bool, len(pkg.Imports))
+	for _, imported := range pkg.Imports {
+		seen[imported.PkgPath] = true
+	}
+	for _, file := range pkg.Syntax {
+		for _, importSpec := range file.Imports {
+			if importPath, err := strconv.Unquote(importSpec.Path.Value); err == nil {
+				seen[importPath] = true
+			}
+		}
+	}
+
+	importPaths := make([]string, 0, len(seen))
+	for importPath := range seen {
+		importPaths = append(importPaths, importPath)
+	}
+	sort.Strings(importPaths)
+	return importPaths
+}
@@ -0,0 +1,257 @@
+package extract
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+	codeparser "github.com/Desgue/codegraph/parser"
+	"golang.org/x/tools/go/packages"
+)
+
+func TestBuildImportGraph(t *testing.T) {
+	module := &packages.Module{Path: "example.com/mod"}
+	store := &packages.Package{PkgPath: "example.com/mod/store", Name: "store", Module: module,
+		GoFiles: []string{"/src/store/store.go"}}
+	api := &packages.Package{PkgPath: "example.com/mod/api", Name: "api", Module: module,
+		GoFiles: []string{"/src/api/api.go", "/src/api/routes.go"}}
+	cmd := &packages.Package{PkgPath: "example.com/mod/cmd", Name: "main", Module: module}
+
+	fmtPackage := &packages.Package{PkgPath: "fmt"}
+	api.Imports = map[string]*packages.Package{"example.com/mod/store": store, "fmt": fmtPackage}
+	cmd.Imports = map[string]*packages.Package{"example.com/mod/store": store, "example.com/mod/api": api}
+
+	importGraph, failures := BuildImportGraph([]*packages.Package{api, cmd, store})
+	if len(failures) > 0 {
+		t.Fatalf("BuildImportGraph() failures = %v", failures)
+	}
+
+	if len(importGraph.Nodes()) != 3 {
+		t.Fatalf("expected 3 nodes, got %d", len(importGraph.Nodes()))
+	}
+
+	apiNode, found := importGraph.Node("example.com/mod/api")
+	if !found {
+		t.Fatal("expected api node")
+	}
+	if apiNode.Kind != graph.KindPackage || apiNode.Name != "api" || apiNode.ModulePath != "example.com/mod" {
+		t.Errorf("unexpected api node: %+v", apiNode)
+	}
+	if len(apiNode.Files) != 2 {
+		t.Errorf("expected 2 files on api node, got %d", len(apiNode.Files))
+	}
+	if apiNode.DirPath != "/src/api" {
+		t.Errorf("api DirPath = %q, want /src/api", apiNode.DirPath)
+	}
+	if cmdNode, _ := importGraph.Node("example.com/mod/cmd"); cmdNode.DirPath != "" {
+		t.Errorf("cmd DirPath = %q, want empty without GoFiles", cmdNode.DirPath)
+	}
+
+	wantEdges := [][2]string{
+		{"example.com/mod/api", "example.com/mod/store"},
+		{"example.com/mod/cmd", "example.com/mod/api"},
+		{"example.com/mod/cmd", "example.com/mod/store"},
+	}
+	edges := importGraph.Edges()
+	if len(edges) != len(wantEdges) {
+		t.Fatalf("expected %d edges (external imports skipped), got %d", len(wantEdges), len(edges))
+	}
+	for i, want := range wantEdges {
+		if edges[i].From != want[0] || edges[i].To != want[1] || edges[i].Kind != graph.EdgeImport {
+			t.Errorf("edge %d = %s -> %s, want %s -> %s", i, edges[i].From, edges[i].To, want[0], want[1])
+		}
+	}
+}
+
+// recordingEmitter records the order of emitter calls.
+type recordingEmitter struct {
+	calls []string
+}
+
+func (e *recordingEmitter) EmitNode(node graph.Node) error {
+	e.calls = append(e.calls, "node "+node.ID)
+	return nil
+}
+
+func (e *recordingEmitter) EmitEdge(edge graph.Edge) error {
+	e.calls = append(e.calls, "edge "+edge.From+" -> "+edge.To)
+	return nil
+}
+
+func (e *recordingEmitter) Close() error {
+	e.calls = append(e.calls, "close")
+	return nil
+}
+
+func TestRun_BuiltinCallOrder(t *testing.T) {
+	store := &packages.Package{PkgPath: "example.com/mod/store", Name: "store"}
+	api := &packages.Package{PkgPath: "example.com/mod/api", Name: "api",
+		Imports: map[string]*packages.Package{"example.com/mod/store": store, "fmt": {PkgPath: "fmt"}}}
+
+	emitter := &recordingEmitter{}
+	failures, err := Run(context.Background(), []*packages.Package{api, store}, Extractors(), emitter)
+	if err != nil || len(failures) != 0 {
+		t.Fatalf("Run() failures = %v, error = %v", failures, err)
+	}
+
+	want := []string{
+		"node example.com/mod/api",
+		"edge example.com/mod/api -> example.com/mod/store",
+		"node example.com/mod/store",
+		"close",
+	}
+	if !reflect.DeepEqual(emitter.calls, want) {
+		t.Errorf("calls = %q, want %q", emitter.calls, want)
+	}
+}
+
+func TestBuildImportGraph_SyntaxImportsRestoreCycleEdges(t *testing.T) {
+	fileSet := token.NewFileSet()
+	file, err := parser.ParseFile(fileSet, "a.go", "package a\n\nimport _ \"example.com/mod/b\"\n", parser.ImportsOnly)
+	if err != nil {
+		t.Fatalf("failed to parse source: %v", err)
+	}
+
+	// go/packages omits the import that closes a cycle from Imports.
+	a := &packages.Package{PkgPath: "example.com/mod/a", Name: "a", Fset: fileSet, Syntax: []*ast.File{file}}
+	b := &packages.Package{PkgPath: "example.com/mod/b", Name: "b"}
+	b.Imports = map[string]*packages.Package{"example.com/mod/a": a}
+
+	importGraph, failures := BuildImportGraph([]*packages.Package{a, b})
+	if len(failures) > 0 {
+		t.Fatalf("BuildImportGraph() failures = %v", failures)
+	}
+
+	if cycles := importGraph.FindCycles(nil); len(cycles) != 1 {
+		t.Errorf("expected the a <-> b cycle, got edges %+v", importGraph.Edges())
+	}
+}
+
+func TestBuildImportGraph_TestOnlyImports(t *testing.T) {
+	fileSet := token.NewFileSet()
+	sources := map[string]string{
+		"/src/a/a.go":      "package a\n\nimport _ \"example.com/mod/b\"\n",
+		"/src/a/a_test.go": "package a\n\nimport (\n\t_ \"example.com/mod/b\"\n\t_ \"example.com/mod/c\"\n\t_ \"testing\"\n)\n",
+	}
+	var files []*ast.File
+	for _, filename := range []string{"/src/a/a.go", "/src/a/a_test.go"} {
+		file, err := parser.ParseFile(fileSet, filename, sources[filename], parser.ImportsOnly)
+		if err != nil {
+			t.Fatalf("failed to parse %s: %v", filename, err)
+		}
+		files = append(files, file)
+	}
+
+	a := &packages.Package{PkgPath: "example.com/mod/a", Name: "a", Fset: fileSet, Syntax: files}
+	b := &packages.Package{PkgPath: "example.com/mod/b", Name: "b"}
+	c := &packages.Package{PkgPath: "example.com/mod/c", Name: "c"}
+
+	importGraph, failures := BuildImportGraph([]*packages.Package{a, b, c})
+	if len(failures) > 0 {
+		t.Fatalf("BuildImportGraph() failures = %v", failures)
+	}
+
+	wantKinds := map[string]graph.EdgeKind{
+		"example.com/mod/b": graph.EdgeImport,
+		"example.com/mod/c": graph.EdgeTestImport,
+	}
+	wantUsage := map[string]string{
+		"example.com/mod/b": "mixed",
+		"example.com/mod/c": "test_only",
+	}
+	edges := importGraph.OutEdges("example.com/mod/a")
+	if len(edges) != len(wantKinds) {
+		t.Fatalf("expected %d edges from a, got %+v", len(wantKinds), edges)
+	}
+	for _, edge := range edges {
+		if edge.Kind != wantKinds[edge.To] {
+			t.Errorf("edge a -> %s kind = %q, want %q", edge.To, edge.Kind, wantKinds[edge.To])
+		}
+		if usage := edge.Attributes[AttributeImportUsage]; usage != wantUsage[edge.To] {
+			t.Errorf("edge a -> %s %s = %q, want %q", edge.To, AttributeImportUsage, usage, wantUsage[edge.To])
+		}
+		if edge.IsTestOnly != (edge.Kind == graph.EdgeTestImport) {
+			t.Errorf("edge a -> %s IsTestOnly = %v, want it set only on test imports", edge.To, edge.IsTestOnly)
+		}
+	}
+
+	aNode, _ := importGraph.Node("example.com/mod/a")
+	if want := []string{"example.com/mod/c", "testing"}; !reflect.DeepEqual(aNode.TestDependencies, want) {
+		t.Errorf("TestDependencies = %v, want %v", aNode.TestDependencies, want)
+	}
+	if fanOut := aNode.Attributes[AttributeFanOut]; fanOut != "1" {
+		t.Errorf("expected test imports to be excluded from fan_out, got %q", fanOut)
+	}
+}
+
+func TestBuildImportGraph_ImportAliases(t *testing.T) {
+	fileSet := token.NewFileSet()
+	var files []*ast.File
+	for _, source := range []string{
+		"package a\n\nimport (\n\tcu \"example.com/mod/b\"\n\t_ \"example.com/mod/c\"\n)\n",
+		"package a\n\nimport (\n\tctxutil \"example.com/mod/b\"\n\tcu \"example.com/mod/b\"\n)\n",
+	} {
+		file, err := parser.ParseFile(fileSet, "a.go", source, parser.ImportsOnly)
+		if err != nil {
+			t.Fatalf("failed to parse source: %v", err)
+		}
+		files = append(files, file)
+	}
+
+	a := &packages.Package{PkgPath: "example.com/mod/a", Name: "a", Fset: fileSet, Syntax: files}
+	b := &packages.Package{PkgPath: "example.com/mod/b", Name: "b"}
+	c := &packages.Package{PkgPath: "example.com/mod/c", Name: "c"}
+
+	importGraph, failures := BuildImportGraph([]*packages.Package{a, b, c})
+	if len(failures) > 0 {
+		t.Fatalf("BuildImportGraph() failures = %v", failures)
+	}
+
+	wantAliases := map[string]string{"example.com/mod/b": "ctxutil,cu", "example.com/mod/c": ""}
+	for _, edge := range importGraph.OutEdges("example.com/mod/a") {
+		if got := edge.Attributes[AttributeImportAlias]; got != wantAliases[edge.To] {
+			t.Errorf("edge a -> %s alias = %q, want %q", edge.To, got, wantAliases[edge.To])
+		}
+	}
+}
+
+func TestBuildImportGraph_Multiplicity(t *testing.T) {
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		"go.mod": "module example.com/mod\n\ngo 1.24\n",
+		"a/a.go": "package a\n\nimport (\n\t\"example.com/mod/b\"\n\t_ \"example.com/mod/c\"\n)\n\nvar _ = b.New().Close\nvar _ = b.New\n",
+		"b/b.go": "package b\n\ntype T struct{}\n\nfunc New() *T { return nil }\n\nfunc (*T) Close() {}\n",
+		"c/c.go": "package c\n",
+	} {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pkgs, errorCount, err := codeparser.Load(root, false)
+	if err != nil || errorCount > 0 {
+		t.Fatalf("Load() = %d errors, %v", errorCount, err)
+	}
+
+	importGraph, failures := BuildImportGraph(pkgs)
+	if len(failures) > 0 {
+		t.Fatalf("BuildImportGraph() failures = %v", failures)
+	}
+
+	// New and Close; the blank import uses nothing.
+	wantMultiplicity := map[string]int{"example.com/mod/b": 2, "example.com/mod/c": 0}
+	for _, edge := range importGraph.OutEdges("example.com/mod/a") {
+		if edge.Multiplicity != wantMultiplicity[edge.To] {
+			t.Errorf("edge a -> %s multiplicity = %d, want %d", edge.To, edge.Multiplicity, wantMultiplicity[edge.To])
+		}
+	}
+}
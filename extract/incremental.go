@@ -0,0 +1,344 @@
+package extract
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/Desgue/codegraph/graph"
+	"golang.org/x/tools/go/packages"
+)
+
+// Incremental is an extracted graph kept up to date as packages change, for
+// long-running callers that would otherwise rebuild the graph after every
+// edit. It indexes the nodes and edges each package's extraction added, so
+// Update can re-extract only the reloaded packages and swap their elements
+// in place, then recompute only the metrics the swap can have changed.
+//
+// After Update the graph equals what Build returns for the same packages,
+// element order included, as long as no package is new: packages new to the
+// graph are added after the others. Edges into a package are extracted with
+// its importers, so when an edit changes what importers see, such as an
+// exported API, reload the importers too.
+type Incremental struct {
+	extractors []Extractor
+	graph      *graph.Graph
+	// owned lists the packages in extraction order with what each added;
+	// positions maps package paths to their index in owned.
+	owned     []ownedElements
+	positions map[string]int
+}
+
+// ownedElements is the run of nodes and edges one package's extraction added
+// to the graph.
+type ownedElements struct {
+	pkgPath   string
+	nodeIDs   []string
+	edgeCount int
+}
+
+// NewIncremental extracts pkgs like Build and keeps the result for Update.
+func NewIncremental(ctx context.Context, pkgs []*packages.Package, extractors []Extractor) (*Incremental, []Failure, error) {
+	builder := graph.NewBuilder()
+	recorder := &packageRecorder{next: builder}
+	origins := &outputOrigins{next: recorder}
+	failures, err := run(ctx, pkgs, loadedSet(pkgs), extractors, origins, recorder.packageDone)
+	failures = append(failures, origins.failures()...)
+	if err != nil {
+		return nil, failures, err
+	}
+
+	incremental := &Incremental{extractors: extractors, graph: builder.Graph(), positions: make(map[string]int, len(pkgs))}
+	for i, pkg := range pkgs {
+		// The builder skipped the dangling edges, which the package does not own.
+		recorded := recorder.packages[i]
+		recorded.edges = slices.DeleteFunc(recorded.edges, func(edge *graph.Edge) bool {
+			_, fromFound := incremental.graph.Node(edge.From)
+			_, toFound := incremental.graph.Node(edge.To)
+			return !fromFound || !toFound
+		})
+		incremental.own(pkg.PkgPath, recorded)
+	}
+	applyCouplingMetrics(incremental.graph)
+	return incremental, failures, nil
+}
+
+// Graph returns the graph, which Update modifies in place.
+func (inc *Incremental) Graph() *graph.Graph {
+	return inc.graph
+}
+
+// Update re-extracts pkgs, which replace the packages with the same paths,
+// and patches their nodes and edges into the graph. Coupling metrics are
+// recomputed for the reloaded nodes and the nodes whose edges to them
+// changed. Depth, rank and rebuild impact depend on the whole import
+// closure, so they are recomputed over the graph, and only when an import,
+// the set of nodes or a package's lines of code changed.
+//
+// The packages are patched in as one change, so a reloaded package may
+// import a package new in the same call. Extraction bypasses any Cache
+// attached to ctx: cache keys cover the keys of imported packages, which a
+// partial run cannot compute. As in Build, reloaded edges to nodes the
+// graph will not have are dropped and reported as failures. Update fails,
+// leaving the graph unchanged, when the new elements do not fit it otherwise,
+// such as a node ID another package already added or another package's edge
+// to a node that is gone.
+func (inc *Incremental) Update(ctx context.Context, pkgs []*packages.Package) ([]Failure, error) {
+	loaded := make(map[string]bool, len(inc.owned)+len(pkgs))
+	for _, owned := range inc.owned {
+		loaded[owned.pkgPath] = true
+	}
+	for _, pkg := range pkgs {
+		loaded[pkg.PkgPath] = true
+	}
+	recorder := &packageRecorder{}
+	origins := &outputOrigins{next: recorder}
+	failures, err := run(WithCache(ctx, nil), pkgs, loaded, inc.extractors, origins, recorder.packageDone)
+	if err != nil {
+		return failures, err
+	}
+	inc.dropDanglingEdges(pkgs, recorder.packages, origins)
+	failures = append(failures, origins.failures()...)
+
+	// Packages new to the graph go after the others, in the order given.
+	patches := make([]packagePatch, len(pkgs))
+	newPackages := 0
+	for i, pkg := range pkgs {
+		position, found := inc.positions[pkg.PkgPath]
+		if !found {
+			position = len(inc.owned) + newPackages
+			newPackages++
+		}
+		patches[i] = packagePatch{position: position, pkgPath: pkg.PkgPath, recorded: recorder.packages[i]}
+	}
+	slices.SortStableFunc(patches, func(a, b packagePatch) int { return a.position - b.position })
+
+	starts := inc.starts()
+	replacements := make([]graph.Replacement, len(patches))
+	for i, patch := range patches {
+		replacement := graph.Replacement{
+			NodeStart: len(inc.graph.Nodes()), Nodes: patch.recorded.nodes,
+			EdgeStart: len(inc.graph.Edges()), Edges: patch.recorded.edges,
+		}
+		if patch.position < len(inc.owned) {
+			replacement.NodeStart, replacement.EdgeStart = starts[patch.position][0], starts[patch.position][1]
+			replacement.NodeCount, replacement.EdgeCount = len(inc.owned[patch.position].nodeIDs), inc.owned[patch.position].edgeCount
+		}
+		patches[i].oldNodes = slices.Clone(inc.graph.Nodes()[replacement.NodeStart : replacement.NodeStart+replacement.NodeCount])
+		patches[i].oldEdges = slices.Clone(inc.graph.Edges()[replacement.EdgeStart : replacement.EdgeStart+replacement.EdgeCount])
+		replacements[i] = replacement
+	}
+	if err := inc.graph.Splice(replacements...); err != nil {
+		return failures, fmt.Errorf("failed to patch the reloaded packages into the graph: %w", err)
+	}
+
+	changes := &patchChanges{touched: make(map[string]bool)}
+	for _, patch := range patches {
+		owned := ownedElements{pkgPath: patch.pkgPath, nodeIDs: nodeIDs(patch.recorded.nodes), edgeCount: len(patch.recorded.edges)}
+		if patch.position < len(inc.owned) {
+			inc.owned[patch.position] = owned
+		} else {
+			inc.own(patch.pkgPath, patch.recorded)
+		}
+		changes.compare(patch.oldNodes, patch.recorded.nodes, patch.oldEdges, patch.recorded.edges)
+	}
+	changes.apply(inc.graph)
+	return failures, nil
+}
+
+// dropDanglingEdges removes from recorded, the elements re-extracted for
+// pkgs, the edges with an endpoint that is neither one of the new nodes nor a
+// node of a package Update keeps, recording them in origins.
+func (inc *Incremental) dropDanglingEdges(pkgs []*packages.Package, recorded []recordedElements, origins *outputOrigins) {
+	replaced := make(map[string]bool)
+	added := make(map[string]bool)
+	for i, pkg := range pkgs {
+		if position, found := inc.positions[pkg.PkgPath]; found {
+			for _, id := range inc.owned[position].nodeIDs {
+				replaced[id] = true
+			}
+		}
+		for _, node := range recorded[i].nodes {
+			added[node.ID] = true
+		}
+	}
+	present := func(id string) bool {
+		if added[id] {
+			return true
+		}
+		_, found := inc.graph.Node(id)
+		return found && !replaced[id]
+	}
+
+	index := 0
+	for i := range recorded {
+		recorded[i].edges = slices.DeleteFunc(recorded[i].edges, func(edge *graph.Edge) bool {
+			defer func() { index++ }()
+			if present(edge.From) && present(edge.To) {
+				return false
+			}
+			origins.dangle(index, fmt.Errorf("edge %s -> %s: an endpoint is not a node in the graph", edge.From, edge.To))
+			return true
+		})
+	}
+}
+
+// packagePatch is what Update swaps in for one package: its position in the
+// ownership index and its old and new elements.
+type packagePatch struct {
+	position int
+	pkgPath  string
+	recorded recordedElements
+	oldNodes []*graph.Node
+	oldEdges []*graph.Edge
+}
+
+// own appends a package to the ownership index and returns its position.
+func (inc *Incremental) own(pkgPath string, recorded recordedElements) int {
+	inc.positions[pkgPath] = len(inc.owned)
+	inc.owned = append(inc.owned, ownedElements{pkgPath: pkgPath, nodeIDs: nodeIDs(recorded.nodes), edgeCount: len(recorded.edges)})
+	return len(inc.owned) - 1
+}
+
+// starts returns where the nodes and edges of each owned package begin in
+// the graph's insertion order, after those of the packages before it.
+func (inc *Incremental) starts() [][2]int {
+	starts := make([][2]int, len(inc.owned))
+	nodeStart, edgeStart := 0, 0
+	for i, owned := range inc.owned {
+		starts[i] = [2]int{nodeStart, edgeStart}
+		nodeStart += len(owned.nodeIDs)
+		edgeStart += owned.edgeCount
+	}
+	return starts
+}
+
+func nodeIDs(nodes []*graph.Node) []string {
+	ids := make([]string, len(nodes))
+	for i, node := range nodes {
+		ids[i] = node.ID
+	}
+	return ids
+}
+
+// patchChanges accumulates what Update's splices changed: the nodes whose
+// own edges may differ, whether any closure metric input changed, and the
+// package nodes that replaced a node with the same ID.
+type patchChanges struct {
+	touched        map[string]bool
+	closureChanged bool
+	replaced       [][2]*graph.Node
+}
+
+// compare records the differences between a package's old and new elements.
+func (c *patchChanges) compare(oldNodes, newNodes []*graph.Node, oldEdges, newEdges []*graph.Edge) {
+	oldByID := make(map[string]*graph.Node, len(oldNodes))
+	for _, node := range oldNodes {
+		oldByID[node.ID] = node
+	}
+	kept := 0
+	for _, node := range newNodes {
+		c.touched[node.ID] = true
+		old, found := oldByID[node.ID]
+		if !found {
+			c.closureChanged = true
+			continue
+		}
+		kept++
+		if node.Kind == graph.KindPackage {
+			c.replaced = append(c.replaced, [2]*graph.Node{node, old})
+			if node.Attributes[AttributeLinesOfCode] != old.Attributes[AttributeLinesOfCode] {
+				c.closureChanged = true
+			}
+		}
+	}
+	if kept < len(oldNodes) {
+		c.closureChanged = true
+	}
+
+	edgeCounts := make(map[graph.EdgeKey]int)
+	for _, edge := range oldEdges {
+		edgeCounts[edge.Key()]--
+	}
+	for _, edge := range newEdges {
+		edgeCounts[edge.Key()]++
+	}
+	for key, count := range edgeCounts {
+		if count == 0 {
+			continue
+		}
+		c.touched[key.From] = true
+		c.touched[key.To] = true
+		if key.Kind == graph.EdgeImport {
+			c.closureChanged = true
+		}
+	}
+}
+
+// apply recomputes the metrics the recorded changes can affect.
+func (c *patchChanges) apply(g *graph.Graph) {
+	for id := range c.touched {
+		if node, found := g.Node(id); found {
+			applyNodeCoupling(g, node)
+		}
+	}
+	if !c.closureChanged {
+		for _, pair := range c.replaced {
+			copyClosureMetrics(pair[0], pair[1])
+		}
+		return
+	}
+	closure := computeClosureMetrics(g)
+	for _, node := range g.Nodes() {
+		if node.Kind == graph.KindPackage {
+			closure.apply(node)
+		}
+	}
+}
+
+// packageRecorder is the Emitter Incremental extracts into. It forwards the
+// elements to next, when set, and groups those accepted by package.
+type packageRecorder struct {
+	next     graph.Emitter
+	current  recordedElements
+	packages []recordedElements
+}
+
+type recordedElements struct {
+	nodes []*graph.Node
+	edges []*graph.Edge
+}
+
+func (r *packageRecorder) EmitNode(node graph.Node) error {
+	if r.next != nil {
+		if err := r.next.EmitNode(node); err != nil {
+			return err
+		}
+	}
+	r.current.nodes = append(r.current.nodes, &node)
+	return nil
+}
+
+func (r *packageRecorder) EmitEdge(edge graph.Edge) error {
+	if r.next != nil {
+		if err := r.next.EmitEdge(edge); err != nil {
+			return err
+		}
+	}
+	r.current.edges = append(r.current.edges, &edge)
+	return nil
+}
+
+func (r *packageRecorder) Close() error {
+	if r.next != nil {
+		return r.next.Close()
+	}
+	return nil
+}
+
+// packageDone ends the elements of the package at index i, which run
+// extracts in order.
+func (r *packageRecorder) packageDone(i int) {
+	r.packages = append(r.packages, r.current)
+	r.current = recordedElements{}
+}
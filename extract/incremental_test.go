@@ -0,0 +1,132 @@
+package extract
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/Desgue/codegraph/internal/testutil/repogen"
+	codeparser "github.com/Desgue/codegraph/parser"
+	"golang.org/x/tools/go/packages"
+)
+
+func TestIncremental_UpdateMatchesRebuild(t *testing.T) {
+	tests := []struct {
+		name string
+		// edit changes packages under root and returns the paths to reload.
+		edit func(t *testing.T, root string) []string
+	}{
+		{
+			// Lines of code and imports are unchanged, so the depth, rank and
+			// rebuild impact attributes carry over.
+			name: "reworded comment",
+			edit: func(t *testing.T, root string) []string {
+				name := filepath.Join(root, "p0002", "p.go")
+				source, err := os.ReadFile(name)
+				if err != nil {
+					t.Fatal(err)
+				}
+				source = bytes.Replace(source, []byte("// F0 combines"), []byte("// F0 sums"), 1)
+				if err := os.WriteFile(name, source, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return []string{"genmod/p0002"}
+			},
+		},
+		{
+			name: "comment",
+			edit: func(t *testing.T, root string) []string {
+				appendToFile(t, filepath.Join(root, "p0002", "p.go"), "\n// A comment added after the first build.\n")
+				return []string{"genmod/p0002"}
+			},
+		},
+		{
+			name: "new function",
+			edit: func(t *testing.T, root string) []string {
+				appendToFile(t, filepath.Join(root, "p0002", "p.go"), "\n// Extra is added after the first build.\nfunc Extra() {}\n")
+				return []string{"genmod/p0002"}
+			},
+		},
+		{
+			name: "new import",
+			edit: func(t *testing.T, root string) []string {
+				appendToFile(t, filepath.Join(root, "p0004", "extra.go"), "package p0004\n\nimport _ \"genmod/p0001\"\n")
+				return []string{"genmod/p0004"}
+			},
+		},
+		{
+			// The importer sorts, and is patched, before the new package.
+			name: "new package and its importer",
+			edit: func(t *testing.T, root string) []string {
+				if err := os.Mkdir(filepath.Join(root, "z9"), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				appendToFile(t, filepath.Join(root, "z9", "z9.go"), "package z9\n\n// Z is added after the first build.\nfunc Z() {}\n")
+				appendToFile(t, filepath.Join(root, "p0000", "extra.go"), "package p0000\n\nimport _ \"genmod/z9\"\n")
+				return []string{"genmod/p0000", "genmod/z9"}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			extractors := append(Extractors(), &SymbolExtractor{})
+			// Each package imports the one before it.
+			repo := repogen.Generate(t, repogen.Config{Packages: 5, Topology: repogen.Chain, FunctionsPerPackage: 2, TestFiles: true})
+			incremental, failures, err := NewIncremental(ctx, loadTestPackages(t, repo.Root), extractors)
+			if err != nil || len(failures) > 0 {
+				t.Fatalf("NewIncremental() = %v, %v", failures, err)
+			}
+
+			edited := tt.edit(t, repo.Root)
+			pkgs := loadTestPackages(t, repo.Root)
+			var reloaded []*packages.Package
+			for _, pkg := range pkgs {
+				if slices.Contains(edited, pkg.PkgPath) {
+					reloaded = append(reloaded, pkg)
+				}
+			}
+			if failures, err := incremental.Update(ctx, reloaded); err != nil || len(failures) > 0 {
+				t.Fatalf("Update() = %v, %v", failures, err)
+			}
+
+			rebuilt, failures, err := Build(ctx, pkgs, extractors)
+			if err != nil || len(failures) > 0 {
+				t.Fatalf("Build() = %v, %v", failures, err)
+			}
+			if !reflect.DeepEqual(incremental.Graph(), rebuilt) {
+				t.Errorf("patched graph differs from a rebuild:\nnodes %+v\nedges %+v\nwant nodes %+v\nedges %+v",
+					incremental.Graph().Nodes(), incremental.Graph().Edges(), rebuilt.Nodes(), rebuilt.Edges())
+			}
+		})
+	}
+}
+
+func loadTestPackages(t *testing.T, root string) []*packages.Package {
+	t.Helper()
+
+	pkgs, errorCount, err := codeparser.Load(root, true)
+	if err != nil || errorCount > 0 {
+		t.Fatalf("Load() = %d errors, %v", errorCount, err)
+	}
+	return pkgs
+}
+
+func appendToFile(t *testing.T, name, text string) {
+	t.Helper()
+
+	file, err := os.OpenFile(name, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.WriteString(text); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,176 @@
+package extract
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/Desgue/codegraph/graph"
+	"github.com/Desgue/codegraph/graph/metrics"
+	"github.com/Desgue/codegraph/parser"
+	"golang.org/x/tools/go/packages"
+)
+
+// Attribute names under which BuildImportGraph records package metrics.
+// AttributeFanIn and AttributeFanOut are the afferent (Ca) and efferent (Ce)
+// coupling of a package: the distinct loaded packages importing it and
+// imported by it. Packages outside the load, such as the standard library or
+// vendored dependencies, are not nodes and so never count. On function nodes
+// the same two attributes count distinct callers and callees over call edges,
+// and AttributeTestFanIn counts the test functions calling them.
+// AttributeDepth is the number of imports on the longest in-graph import path
+// beneath a package, or DepthCyclic for packages in an import cycle.
+// AttributeDocCoverage is the percentage, 0 to 100 rounded down, of a
+// package's exported declarations that have a doc comment; packages
+// exporting nothing have none. AttributeRebuildImpactPackages and
+// AttributeRebuildImpactLOC count the packages transitively importing a
+// package and their lines of code, an estimate of what rebuilds when it
+// changes.
+const (
+	AttributeFanIn         = "fan_in"
+	AttributeFanOut        = "fan_out"
+	AttributeTestFanIn     = "test_fan_in"
+	AttributeInstability   = "instability"
+	AttributeDepth         = "depth"
+	AttributeLinesOfCode   = "loc"
+	AttributeComplexity    = "complexity"
+	AttributeMaxComplexity = "max_complexity"
+	AttributeDocCoverage   = "doc_coverage"
+
+	AttributeRebuildImpactPackages = "rebuild_impact_pkgs"
+	AttributeRebuildImpactLOC      = "rebuild_impact_loc"
+)
+
+// DepthCyclic is the AttributeDepth value of packages in an import cycle.
+const DepthCyclic = "cyclic"
+
+// applyCouplingMetrics copies the metrics package's coupling results into
+// package and function node attributes and sets the Rank of package nodes.
+// They need the complete, deduplicated graph, so this runs after all edges
+// are added. The metrics only read the graph and are independent of each
+// other, so they are computed concurrently; attributes are set afterwards on
+// this goroutine.
+func applyCouplingMetrics(g *graph.Graph) {
+	var fanIn, fanOut, callFanIn, callFanOut, testCallFanIn map[string]int
+	var closure closureMetrics
+	runConcurrently(
+		func() { fanIn = metrics.FanIn(g) },
+		func() { fanOut = metrics.FanOut(g) },
+		func() { callFanIn = metrics.CallFanIn(g) },
+		func() { callFanOut = metrics.CallFanOut(g) },
+		func() { testCallFanIn = metrics.TestCallFanIn(g) },
+		func() { closure = computeClosureMetrics(g) },
+	)
+
+	for _, node := range g.Nodes() {
+		switch node.Kind {
+		case graph.KindPackage:
+			setPackageCoupling(node, fanIn[node.ID], fanOut[node.ID])
+			closure.apply(node)
+		case graph.KindFunction:
+			setFunctionCoupling(node, callFanIn[node.ID], callFanOut[node.ID], testCallFanIn[node.ID])
+		}
+	}
+}
+
+// applyNodeCoupling sets the metrics applyCouplingMetrics derives from the
+// node's own edges, for callers updating a few nodes of a complete graph.
+func applyNodeCoupling(g *graph.Graph, node *graph.Node) {
+	count := func(direction graph.Direction, kind graph.EdgeKind) int {
+		return len(g.Neighbors(node.ID, direction, []graph.EdgeKind{kind}))
+	}
+	switch node.Kind {
+	case graph.KindPackage:
+		setPackageCoupling(node, count(graph.Incoming, graph.EdgeImport), count(graph.Outgoing, graph.EdgeImport))
+	case graph.KindFunction:
+		setFunctionCoupling(node, count(graph.Incoming, graph.EdgeCall), count(graph.Outgoing, graph.EdgeCall), count(graph.Incoming, graph.EdgeTestCall))
+	}
+}
+
+func setPackageCoupling(node *graph.Node, fanIn, fanOut int) {
+	node.SetAttribute(AttributeFanIn, strconv.Itoa(fanIn))
+	node.SetAttribute(AttributeFanOut, strconv.Itoa(fanOut))
+	node.SetAttribute(AttributeInstability, strconv.FormatFloat(metrics.InstabilityOf(fanIn, fanOut), 'f', 2, 64))
+}
+
+func setFunctionCoupling(node *graph.Node, fanIn, fanOut, testFanIn int) {
+	node.SetAttribute(AttributeFanIn, strconv.Itoa(fanIn))
+	node.SetAttribute(AttributeFanOut, strconv.Itoa(fanOut))
+	node.SetAttribute(AttributeTestFanIn, strconv.Itoa(testFanIn))
+}
+
+// closureMetrics are the package metrics that depend on the whole import
+// closure rather than on a package's own edges: depth, rank and rebuild
+// impact. Ranks number every node, so adding or removing any node can
+// change them too.
+type closureMetrics struct {
+	depth, ranks  map[string]int
+	rebuildImpact map[string]metrics.RebuildImpact
+}
+
+func computeClosureMetrics(g *graph.Graph) closureMetrics {
+	linesOfCode := make(map[string]int)
+	for _, node := range g.Nodes() {
+		linesOfCode[node.ID], _ = strconv.Atoi(node.Attributes[AttributeLinesOfCode])
+	}
+
+	var closure closureMetrics
+	runConcurrently(
+		func() { closure.depth = metrics.Depth(g) },
+		func() { closure.rebuildImpact = metrics.RebuildImpacts(g, linesOfCode) },
+		func() { closure.ranks = graph.Ranks(g, []graph.EdgeKind{graph.EdgeImport}) },
+	)
+	return closure
+}
+
+// apply sets the closure metrics of a package node.
+func (c closureMetrics) apply(node *graph.Node) {
+	node.SetAttribute(AttributeDepth, depthAttribute(c.depth[node.ID]))
+	node.Rank = c.ranks[node.ID]
+	node.SetAttribute(AttributeRebuildImpactPackages, strconv.Itoa(c.rebuildImpact[node.ID].Packages))
+	node.SetAttribute(AttributeRebuildImpactLOC, strconv.Itoa(c.rebuildImpact[node.ID].LinesOfCode))
+}
+
+// copyClosureMetrics gives a package node the closure metrics of the node it
+// replaces, for when nothing they depend on changed.
+func copyClosureMetrics(node, replaced *graph.Node) {
+	for _, name := range []string{AttributeDepth, AttributeRebuildImpactPackages, AttributeRebuildImpactLOC} {
+		node.SetAttribute(name, replaced.Attributes[name])
+	}
+	node.Rank = replaced.Rank
+}
+
+// runConcurrently runs tasks on their own goroutines and waits for all of
+// them.
+func runConcurrently(tasks ...func()) {
+	var running sync.WaitGroup
+	for _, task := range tasks {
+		running.Add(1)
+		go func() {
+			defer running.Done()
+			task()
+		}()
+	}
+	running.Wait()
+}
+
+func depthAttribute(depth int) string {
+	if depth == metrics.DepthCyclic {
+		return DepthCyclic
+	}
+	return strconv.Itoa(depth)
+}
+
+// applySourceMetrics records the metrics computed from a package's own syntax.
+// Packages loaded without syntax get none.
+func applySourceMetrics(node *graph.Node, pkg *packages.Package) {
+	if pkg.Syntax == nil {
+		return
+	}
+	complexity := metrics.PackageComplexity(pkg.Syntax)
+	node.SetAttribute(AttributeLinesOfCode, strconv.Itoa(metrics.LinesOfCode(pkg.Fset, pkg.Syntax)))
+	node.SetAttribute(AttributeComplexity, strconv.Itoa(complexity.Total))
+	node.SetAttribute(AttributeMaxComplexity, strconv.Itoa(complexity.Max))
+	if documented, total := parser.DocCoverage(pkg); total > 0 {
+		node.SetAttribute(AttributeDocCoverage, strconv.Itoa(documented*100/total))
+	}
+}
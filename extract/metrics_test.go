@@ -0,0 +1,157 @@
+package extract
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+	"golang.org/x/tools/go/packages"
+)
+
+func TestBuildImportGraph_Metrics(t *testing.T) {
+	fileSet := token.NewFileSet()
+	source := "package store\n\nfunc Get(key string) string {\n\tif key == \"\" {\n\t\treturn \"\"\n\t}\n\treturn key\n}\n"
+	file, err := parser.ParseFile(fileSet, "store.go", source, 0)
+	if err != nil {
+		t.Fatalf("failed to parse source: %v", err)
+	}
+
+	store := &packages.Package{PkgPath: "example.com/mod/store", Name: "store", Fset: fileSet, Syntax: []*ast.File{file}}
+	api := &packages.Package{PkgPath: "example.com/mod/api", Name: "api",
+		Imports: map[string]*packages.Package{"example.com/mod/store": store}}
+
+	importGraph, failures := BuildImportGraph([]*packages.Package{api, store})
+	if len(failures) > 0 {
+		t.Fatalf("BuildImportGraph() failures = %v", failures)
+	}
+
+	storeNode, _ := importGraph.Node("example.com/mod/store")
+	wantStore := map[string]string{
+		AttributeFanIn: "1", AttributeFanOut: "0", AttributeInstability: "0.00",
+		AttributeLinesOfCode: "8", AttributeComplexity: "2", AttributeMaxComplexity: "2", AttributeDocCoverage: "0",
+	}
+	for name, want := range wantStore {
+		if got := storeNode.Attributes[name]; got != want {
+			t.Errorf("store %s = %q, want %q", name, got, want)
+		}
+	}
+
+	apiNode, _ := importGraph.Node("example.com/mod/api")
+	if apiNode.Attributes[AttributeInstability] != "1.00" {
+		t.Errorf("api instability = %q, want \"1.00\"", apiNode.Attributes[AttributeInstability])
+	}
+	if storeNode.Attributes[AttributeRebuildImpactPackages] != "1" || storeNode.Attributes[AttributeRebuildImpactLOC] != "0" {
+		t.Errorf("store rebuild impact = %q packages, %q loc, want 1 and 0 (api has no syntax)",
+			storeNode.Attributes[AttributeRebuildImpactPackages], storeNode.Attributes[AttributeRebuildImpactLOC])
+	}
+	if _, found := apiNode.Attributes[AttributeLinesOfCode]; found {
+		t.Errorf("expected no source metrics for a package without syntax, got %v", apiNode.Attributes)
+	}
+}
+
+func TestBuildImportGraph_CouplingAttributes(t *testing.T) {
+	// cmd -> api -> store, cmd -> store, api -> util, plus imports of fmt and a
+	// vendored package that are not loaded and must not count toward Ce.
+	module := &packages.Module{Path: "example.com/mod"}
+	external := map[string]*packages.Package{
+		"fmt":                         {PkgPath: "fmt"},
+		"example.com/mod/vendor/x/yz": {PkgPath: "example.com/mod/vendor/x/yz"},
+	}
+	util := &packages.Package{PkgPath: "example.com/mod/util", Name: "util", Module: module}
+	store := &packages.Package{PkgPath: "example.com/mod/store", Name: "store", Module: module,
+		Imports: external}
+	api := &packages.Package{PkgPath: "example.com/mod/api", Name: "api", Module: module,
+		Imports: map[string]*packages.Package{"example.com/mod/store": store, "example.com/mod/util": util, "fmt": external["fmt"]}}
+	cmd := &packages.Package{PkgPath: "example.com/mod/cmd", Name: "main", Module: module,
+		Imports: map[string]*packages.Package{"example.com/mod/api": api, "example.com/mod/store": store}}
+
+	importGraph, failures := BuildImportGraph([]*packages.Package{api, cmd, store, util})
+	if len(failures) > 0 {
+		t.Fatalf("BuildImportGraph() failures = %v", failures)
+	}
+
+	// Ca, Ce, I = Ce / (Ca + Ce) and depth, worked out by hand.
+	want := map[string][4]string{
+		"example.com/mod/cmd":   {"0", "2", "1.00", "2"},
+		"example.com/mod/api":   {"1", "2", "0.67", "1"},
+		"example.com/mod/store": {"2", "0", "0.00", "0"},
+		"example.com/mod/util":  {"1", "0", "0.00", "0"},
+	}
+	for id, values := range want {
+		node, _ := importGraph.Node(id)
+		got := [4]string{node.Attributes[AttributeFanIn], node.Attributes[AttributeFanOut],
+			node.Attributes[AttributeInstability], node.Attributes[AttributeDepth]}
+		if got != values {
+			t.Errorf("%s Ca, Ce, I, depth = %v, want %v", id, got, values)
+		}
+	}
+
+	// Dependencies first, ties broken by ID.
+	wantRanks := map[string]int{"example.com/mod/store": 0, "example.com/mod/util": 1, "example.com/mod/api": 2, "example.com/mod/cmd": 3}
+	for id, want := range wantRanks {
+		if node, _ := importGraph.Node(id); node.Rank != want {
+			t.Errorf("%s rank = %d, want %d", id, node.Rank, want)
+		}
+	}
+}
+
+// callExtractor emits functions F and TestF for every package of a
+// newPipelineFixture chain: F calls the previous package's F twice, and TestF
+// calls F.
+func callExtractor() Extractor {
+	return &funcExtractor{name: "calls", extract: func(pkg *packages.Package, emitter graph.Emitter) error {
+		function := graph.FuncID(pkg.PkgPath, "", "F")
+		for _, name := range []string{"F", "TestF"} {
+			if err := emitter.EmitNode(graph.Node{ID: graph.FuncID(pkg.PkgPath, "", name), Kind: graph.KindFunction, Name: name}); err != nil {
+				return err
+			}
+		}
+		if err := emitter.EmitEdge(graph.Edge{From: graph.FuncID(pkg.PkgPath, "", "TestF"), To: function, Kind: graph.EdgeTestCall}); err != nil {
+			return err
+		}
+		for importPath := range pkg.Imports {
+			for range 2 {
+				if err := emitter.EmitEdge(graph.Edge{From: function, To: graph.FuncID(importPath, "", "F"), Kind: graph.EdgeCall}); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}}
+}
+
+func TestBuild_FunctionCouplingAttributes(t *testing.T) {
+	pkgs := newPipelineFixture(30)
+
+	for attempt := range 5 {
+		extractedGraph, failures, err := Build(context.Background(), pkgs, append(Extractors(), callExtractor()))
+		if err != nil || len(failures) > 0 {
+			t.Fatalf("Build() = %v, %v", failures, err)
+		}
+
+		for i, pkg := range pkgs {
+			// p000 is called by p001 only and calls nothing; p029 is called by no one.
+			wantFanIn, wantFanOut := "1", "1"
+			if i == 0 {
+				wantFanOut = "0"
+			}
+			if i == len(pkgs)-1 {
+				wantFanIn = "0"
+			}
+
+			node, _ := extractedGraph.Node(graph.FuncID(pkg.PkgPath, "", "F"))
+			got := [3]string{node.Attributes[AttributeFanIn], node.Attributes[AttributeFanOut], node.Attributes[AttributeTestFanIn]}
+			if want := [3]string{wantFanIn, wantFanOut, "1"}; got != want {
+				t.Fatalf("attempt %d: %s fan_in, fan_out, test_fan_in = %v, want %v", attempt, node.ID, got, want)
+			}
+
+			packageNode, _ := extractedGraph.Node(graph.PackageID(pkg.PkgPath))
+			if _, found := packageNode.Attributes[AttributeTestFanIn]; found {
+				t.Fatalf("package %s has a test_fan_in attribute", pkg.PkgPath)
+			}
+		}
+	}
+}
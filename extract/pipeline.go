@@ -0,0 +1,329 @@
+package extract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"runtime"
+	"slices"
+	"sync"
+
+	"github.com/Desgue/codegraph/graph"
+	"golang.org/x/tools/go/packages"
+)
+
+// Failure records an extractor that failed on one package. The extractor's
+// output for that package is discarded; other extractors are unaffected. In
+// graphs built by Build, an extractor's edges to nodes nobody emitted, such as
+// those of a package whose PackageExtractor failed, are dropped and reported
+// as a Failure of that extractor too.
+type Failure struct {
+	Extractor string
+	Package   string
+	Err       error
+}
+
+func (f Failure) Error() string {
+	return fmt.Sprintf("extractor %s failed on %s: %v", f.Extractor, f.Package, f.Err)
+}
+
+func (f Failure) Unwrap() error {
+	return f.Err
+}
+
+// Run runs every extractor on every package, in parallel across packages, and
+// streams the output to emitter in package order and then extractor order, so
+// the result does not depend on scheduling. It closes emitter when done.
+// A ProgressFunc attached with WithProgress is called after each package.
+// Packages are extracted by GOMAXPROCS workers, or as many as WithConcurrency
+// sets.
+// With a Cache attached with WithCache, packages whose cache key matches are
+// replayed from the cache instead of being extracted.
+//
+// Extractor errors and panics, and elements the emitter rejects, are returned
+// as failures without stopping the run. The error result is reserved for
+// cancellation of ctx and for errors from closing emitter.
+func Run(ctx context.Context, pkgs []*packages.Package, extractors []Extractor, emitter graph.Emitter) ([]Failure, error) {
+	return run(ctx, pkgs, loadedSet(pkgs), extractors, emitter, nil)
+}
+
+// run is Run with the set of packages IsLoaded reports, which Incremental
+// widens to the whole graph when it re-extracts a few packages, and with
+// packageDone, if not nil, called with each package's index once its output
+// has been emitted.
+func run(ctx context.Context, pkgs []*packages.Package, loaded map[string]bool, extractors []Extractor, emitter graph.Emitter, packageDone func(i int)) ([]Failure, error) {
+	var workers sync.WaitGroup
+	defer workers.Wait()
+	runContext, cancel := context.WithCancel(context.WithValue(ctx, loadedPackagesKey{}, loaded))
+	defer cancel()
+
+	results := make([]*packageResult, len(pkgs))
+	for i := range results {
+		results[i] = &packageResult{done: make(chan struct{})}
+	}
+
+	cache := cacheFrom(ctx)
+	var keys []string
+	if cache != nil {
+		keys = cache.packageKeys(ctx, pkgs, extractors)
+	}
+
+	jobs := make(chan int)
+	go func() {
+		defer close(jobs)
+		for i := range pkgs {
+			select {
+			case jobs <- i:
+			case <-runContext.Done():
+				return
+			}
+		}
+	}()
+
+	for range workerCount(ctx) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for i := range jobs {
+				results[i].outputs = extractCachedPackage(runContext, cache, keys, i, pkgs[i], extractors)
+				close(results[i].done)
+			}
+		}()
+	}
+
+	var failures []Failure
+	for i, result := range results {
+		select {
+		case <-result.done:
+		case <-ctx.Done():
+			return failures, ctx.Err()
+		}
+		for _, output := range result.outputs {
+			if origins, tracked := emitter.(*outputOrigins); tracked {
+				origins.startOutput(output.extractor, pkgs[i].PkgPath)
+			}
+			if err := output.replay(emitter); err != nil {
+				failures = append(failures, Failure{Extractor: output.extractor, Package: pkgs[i].PkgPath, Err: err})
+			}
+		}
+		if packageDone != nil {
+			packageDone(i)
+		}
+		reportProgress(ctx, i+1, len(pkgs))
+	}
+
+	if cache != nil {
+		_ = cache.Trim()
+	}
+	return failures, emitter.Close()
+}
+
+// Build runs extractors into an in-memory graph and then records the coupling
+// metrics, which need the complete graph. The graph is returned even when
+// some extractors failed, without the edges left dangling by the failures.
+func Build(ctx context.Context, pkgs []*packages.Package, extractors []Extractor) (*graph.Graph, []Failure, error) {
+	builder := graph.NewBuilder()
+	origins := &outputOrigins{next: builder}
+	failures, err := Run(ctx, pkgs, extractors, origins)
+	failures = append(failures, origins.failures()...)
+	if err != nil {
+		return nil, failures, err
+	}
+
+	extractedGraph := builder.Graph()
+	applyCouplingMetrics(extractedGraph)
+	return extractedGraph, failures, nil
+}
+
+// ProgressFunc receives the number of packages extracted so far and the total.
+type ProgressFunc func(done, total int)
+
+type progressKey struct{}
+
+// WithProgress returns a context that makes Run report its progress to report.
+// Calls happen on Run's goroutine, in order.
+func WithProgress(ctx context.Context, report ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, report)
+}
+
+func reportProgress(ctx context.Context, done, total int) {
+	if report, found := ctx.Value(progressKey{}).(ProgressFunc); found {
+		report(done, total)
+	}
+}
+
+type concurrencyKey struct{}
+
+// WithConcurrency returns a context that makes Run extract at most workers
+// packages at a time. Values below 1 keep the GOMAXPROCS default.
+func WithConcurrency(ctx context.Context, workers int) context.Context {
+	return context.WithValue(ctx, concurrencyKey{}, workers)
+}
+
+func workerCount(ctx context.Context) int {
+	if workers, found := ctx.Value(concurrencyKey{}).(int); found && workers > 0 {
+		return workers
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// outputOrigins is the Emitter in-memory builds extract through. run names
+// the extractor and package of each output before replaying it, so the edges
+// that a graph.Builder behind next skips as dangling on Close become Failures
+// of whoever emitted them instead of failing the build.
+type outputOrigins struct {
+	next    graph.Emitter
+	origins []Failure
+	// edges holds, for each edge next accepted, its index in origins.
+	edges    []int
+	dangling map[int][]error
+}
+
+func (o *outputOrigins) startOutput(extractor, pkgPath string) {
+	o.origins = append(o.origins, Failure{Extractor: extractor, Package: pkgPath})
+}
+
+func (o *outputOrigins) EmitNode(node graph.Node) error {
+	return o.next.EmitNode(node)
+}
+
+func (o *outputOrigins) EmitEdge(edge graph.Edge) error {
+	if err := o.next.EmitEdge(edge); err != nil {
+		return err
+	}
+	o.edges = append(o.edges, len(o.origins)-1)
+	return nil
+}
+
+// Close closes next and moves its dangling edge errors into failures, one
+// per extractor and package, returning only the other errors.
+func (o *outputOrigins) Close() error {
+	err := o.next.Close()
+	if err == nil {
+		return nil
+	}
+	errs := []error{err}
+	if joined, isJoined := err.(interface{ Unwrap() []error }); isJoined {
+		errs = joined.Unwrap()
+	}
+
+	var others []error
+	for _, err := range errs {
+		var dangling *graph.DanglingEdgeError
+		if !errors.As(err, &dangling) || !o.dangle(dangling.Index, err) {
+			others = append(others, err)
+		}
+	}
+	return errors.Join(others...)
+}
+
+// dangle records err against the output that emitted the edge at index,
+// among the edges next accepted. It returns false for an unknown index.
+func (o *outputOrigins) dangle(index int, err error) bool {
+	if index < 0 || index >= len(o.edges) || o.edges[index] < 0 {
+		return false
+	}
+	if o.dangling == nil {
+		o.dangling = make(map[int][]error)
+	}
+	o.dangling[o.edges[index]] = append(o.dangling[o.edges[index]], err)
+	return true
+}
+
+// failures returns one Failure per output with dangling edges, in emission
+// order.
+func (o *outputOrigins) failures() []Failure {
+	var failures []Failure
+	for _, origin := range slices.Sorted(maps.Keys(o.dangling)) {
+		failure := o.origins[origin]
+		failure.Err = errors.Join(o.dangling[origin]...)
+		failures = append(failures, failure)
+	}
+	return failures
+}
+
+type packageResult struct {
+	done    chan struct{}
+	outputs []*extractorOutput
+}
+
+func extractPackage(ctx context.Context, pkg *packages.Package, extractors []Extractor) []*extractorOutput {
+	outputs := make([]*extractorOutput, 0, len(extractors))
+	for _, extractor := range extractors {
+		output := &extractorOutput{extractor: extractor.Name()}
+		output.err = runExtractor(ctx, extractor, pkg, output)
+		outputs = append(outputs, output)
+	}
+	return outputs
+}
+
+// extractCachedPackage is extractPackage reading from and writing to cache,
+// which may be nil; keys holds the cache key of each package.
+func extractCachedPackage(ctx context.Context, cache *Cache, keys []string, i int, pkg *packages.Package, extractors []Extractor) []*extractorOutput {
+	if cache == nil || keys[i] == "" {
+		return extractPackage(ctx, pkg, extractors)
+	}
+	if outputs, found := cache.load(keys[i]); found {
+		return outputs
+	}
+	outputs := extractPackage(ctx, pkg, extractors)
+	cache.store(keys[i], outputs)
+	return outputs
+}
+
+// runExtractor converts a panicking extractor into an error so it cannot take down the run.
+func runExtractor(ctx context.Context, extractor Extractor, pkg *packages.Package, output *extractorOutput) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+	return extractor.Extract(ctx, pkg, output)
+}
+
+// extractorOutput buffers what one extractor emitted for one package, in call order.
+type extractorOutput struct {
+	extractor string
+	elements  []outputElement
+	err       error
+}
+
+type outputElement struct {
+	node *graph.Node
+	edge *graph.Edge
+}
+
+func (o *extractorOutput) EmitNode(node graph.Node) error {
+	o.elements = append(o.elements, outputElement{node: &node})
+	return nil
+}
+
+func (o *extractorOutput) EmitEdge(edge graph.Edge) error {
+	o.elements = append(o.elements, outputElement{edge: &edge})
+	return nil
+}
+
+func (o *extractorOutput) Close() error {
+	return nil
+}
+
+// replay forwards the buffered elements to emitter, stopping at the first
+// rejected element. Output of a failed extractor is not forwarded at all.
+func (o *extractorOutput) replay(emitter graph.Emitter) error {
+	if o.err != nil {
+		return o.err
+	}
+	for _, element := range o.elements {
+		var err error
+		if element.node != nil {
+			err = emitter.EmitNode(*element.node)
+		} else {
+			err = emitter.EmitEdge(*element.edge)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
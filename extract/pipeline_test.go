@@ -0,0 +1,492 @@
+package extract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Desgue/codegraph/graph"
+	"github.com/Desgue/codegraph/internal/testutil/repogen"
+	codeparser "github.com/Desgue/codegraph/parser"
+	"golang.org/x/tools/go/packages"
+)
+
+// funcExtractor adapts a function to the Extractor interface.
+type funcExtractor struct {
+	name    string
+	extract func(pkg *packages.Package, emitter graph.Emitter) error
+}
+
+func (e *funcExtractor) Name() string {
+	return e.name
+}
+
+func (e *funcExtractor) Extract(ctx context.Context, pkg *packages.Package, emitter graph.Emitter) error {
+	return e.extract(pkg, emitter)
+}
+
+func newPipelineFixture(count int) []*packages.Package {
+	pkgs := make([]*packages.Package, count)
+	for i := range pkgs {
+		pkgs[i] = &packages.Package{PkgPath: fmt.Sprintf("example.com/mod/p%03d", i), Name: fmt.Sprintf("p%03d", i)}
+		if i > 0 {
+			pkgs[i].Imports = map[string]*packages.Package{pkgs[i-1].PkgPath: pkgs[i-1]}
+		}
+	}
+	return pkgs
+}
+
+func TestRun_DeterministicOrder(t *testing.T) {
+	pkgs := newPipelineFixture(50)
+
+	var previous []string
+	for attempt := range 5 {
+		emitter := &recordingEmitter{}
+		if _, err := Run(context.Background(), pkgs, Extractors(), emitter); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if attempt > 0 && !reflect.DeepEqual(emitter.calls, previous) {
+			t.Fatalf("attempt %d emitted a different sequence than the previous run", attempt)
+		}
+		previous = emitter.calls
+	}
+	if len(previous) != 50+49+1 {
+		t.Errorf("expected 50 nodes, 49 edges and close, got %d calls", len(previous))
+	}
+}
+
+func TestRun_IsolatesFailingExtractors(t *testing.T) {
+	pkgs := newPipelineFixture(3)
+	routes := &funcExtractor{name: "routes", extract: func(pkg *packages.Package, emitter graph.Emitter) error {
+		routeID := "route:" + pkg.PkgPath
+		if err := emitter.EmitNode(graph.Node{ID: routeID, Kind: "route"}); err != nil {
+			return err
+		}
+		if err := emitter.EmitEdge(graph.Edge{From: graph.PackageID(pkg.PkgPath), To: routeID, Kind: "serves"}); err != nil {
+			return err
+		}
+		if strings.HasSuffix(pkg.PkgPath, "p001") {
+			return errors.New("unparseable router")
+		}
+		return nil
+	}}
+	panicking := &funcExtractor{name: "panicking", extract: func(pkg *packages.Package, emitter graph.Emitter) error {
+		if strings.HasSuffix(pkg.PkgPath, "p002") {
+			panic("boom")
+		}
+		return nil
+	}}
+
+	extractors := append(Extractors(), routes, panicking)
+	extractedGraph, failures, err := Build(context.Background(), pkgs, extractors)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	if len(failures) != 2 {
+		t.Fatalf("expected 2 failures, got %v", failures)
+	}
+	if failures[0].Extractor != "routes" || failures[0].Package != "example.com/mod/p001" {
+		t.Errorf("unexpected first failure: %v", failures[0])
+	}
+	if failures[1].Extractor != "panicking" || !strings.Contains(failures[1].Error(), "panic: boom") {
+		t.Errorf("unexpected second failure: %v", failures[1])
+	}
+
+	if _, found := extractedGraph.Node("route:example.com/mod/p001"); found {
+		t.Error("expected output of the failed extractor run to be discarded")
+	}
+	for _, id := range []string{"example.com/mod/p000", "example.com/mod/p001", "example.com/mod/p002",
+		"route:example.com/mod/p000", "route:example.com/mod/p002"} {
+		if _, found := extractedGraph.Node(id); !found {
+			t.Errorf("expected node %q despite failures", id)
+		}
+	}
+}
+
+func TestBuild_DropsEdgesLeftDanglingByFailures(t *testing.T) {
+	// p002 imports p001, which imports p000.
+	pkgs := newPipelineFixture(3)
+	packageNodes := &funcExtractor{name: "packages", extract: func(pkg *packages.Package, emitter graph.Emitter) error {
+		if strings.HasSuffix(pkg.PkgPath, "p001") {
+			panic("malformed package")
+		}
+		return (&PackageExtractor{}).Extract(context.Background(), pkg, emitter)
+	}}
+	badEdge := &funcExtractor{name: "plugin", extract: func(pkg *packages.Package, emitter graph.Emitter) error {
+		if strings.HasSuffix(pkg.PkgPath, "p000") {
+			return emitter.EmitEdge(graph.Edge{From: graph.PackageID(pkg.PkgPath), To: "nowhere", Kind: "uses"})
+		}
+		return nil
+	}}
+	extractors := []Extractor{packageNodes, &ImportExtractor{}, badEdge}
+
+	extractedGraph, failures, err := Build(context.Background(), pkgs, extractors)
+	if err != nil || extractedGraph == nil {
+		t.Fatalf("Build() = %v, %v, want a graph despite the failures", extractedGraph, err)
+	}
+	var got []string
+	for _, failure := range failures {
+		got = append(got, failure.Extractor+" "+failure.Package)
+	}
+	// Edges from and to p001 dangle; they are failures of the import
+	// extractor on the packages that emitted them.
+	want := []string{"packages example.com/mod/p001", "plugin example.com/mod/p000",
+		"imports example.com/mod/p001", "imports example.com/mod/p002"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("failures = %q, want %q", got, want)
+	}
+	if len(extractedGraph.Nodes()) != 2 || len(extractedGraph.Edges()) != 0 {
+		t.Errorf("graph has %d nodes and edges %v, want p000 and p002 only", len(extractedGraph.Nodes()), sortedEdgeKeys(extractedGraph))
+	}
+
+	incremental, failures, err := NewIncremental(context.Background(), pkgs, extractors)
+	if err != nil || len(failures) != len(want) {
+		t.Fatalf("NewIncremental() = %v failures, %v, want the same failures as Build", failures, err)
+	}
+	// Reloading p002 emits its edge to p001 again.
+	failures, err = incremental.Update(context.Background(), pkgs[2:])
+	if err != nil || len(failures) != 1 || failures[0].Extractor != "imports" || failures[0].Package != "example.com/mod/p002" {
+		t.Errorf("Update() = %v, %v, want the dangling import of p002 as its only failure", failures, err)
+	}
+	if len(incremental.Graph().Edges()) != 0 {
+		t.Errorf("graph after Update has edges %v, want none", sortedEdgeKeys(incremental.Graph()))
+	}
+}
+
+func TestRun_RejectedElementIsAFailure(t *testing.T) {
+	pkgs := newPipelineFixture(1)
+	duplicate := &funcExtractor{name: "duplicate", extract: func(pkg *packages.Package, emitter graph.Emitter) error {
+		return emitter.EmitNode(graph.Node{ID: graph.PackageID(pkg.PkgPath)})
+	}}
+
+	_, failures, err := Build(context.Background(), pkgs, append(Extractors(), duplicate))
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if len(failures) != 1 || failures[0].Extractor != "duplicate" {
+		t.Errorf("expected the duplicate node to fail only its extractor, got %v", failures)
+	}
+}
+
+func TestRun_ReportsProgress(t *testing.T) {
+	pkgs := newPipelineFixture(20)
+
+	var reported []int
+	ctx := WithProgress(context.Background(), func(done, total int) {
+		if total != len(pkgs) {
+			t.Errorf("total = %d, want %d", total, len(pkgs))
+		}
+		reported = append(reported, done)
+	})
+	if _, err := Run(ctx, pkgs, Extractors(), &recordingEmitter{}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if len(reported) != len(pkgs) {
+		t.Fatalf("expected one report per package, got %v", reported)
+	}
+	for i, done := range reported {
+		if done != i+1 {
+			t.Fatalf("expected reports 1..%d in order, got %v", len(pkgs), reported)
+		}
+	}
+}
+
+func TestRun_WithConcurrencyBoundsWorkers(t *testing.T) {
+	const bound = 3
+	var active, peak atomic.Int32
+	instrumented := &funcExtractor{name: "instrumented", extract: func(pkg *packages.Package, emitter graph.Emitter) error {
+		running := active.Add(1)
+		defer active.Add(-1)
+		for {
+			current := peak.Load()
+			if running <= current || peak.CompareAndSwap(current, running) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		return nil
+	}}
+
+	ctx := WithConcurrency(context.Background(), bound)
+	if _, err := Run(ctx, newPipelineFixture(60), []Extractor{instrumented}, &recordingEmitter{}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got := peak.Load(); got < 1 || got > bound {
+		t.Errorf("peak concurrent extractions = %d, want 1..%d", got, bound)
+	}
+	if got := workerCount(WithConcurrency(context.Background(), 0)); got != runtime.GOMAXPROCS(0) {
+		t.Errorf("workerCount() with concurrency 0 = %d, want GOMAXPROCS", got)
+	}
+}
+
+func TestRun_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := Run(ctx, newPipelineFixture(10), Extractors(), &recordingEmitter{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestRegisterExtractor(t *testing.T) {
+	original := Extractors()
+	t.Cleanup(func() {
+		extractorRegistry.Lock()
+		extractorRegistry.extractors = original
+		extractorRegistry.Unlock()
+	})
+
+	custom := &funcExtractor{name: "register-test", extract: func(*packages.Package, graph.Emitter) error { return nil }}
+	if err := RegisterExtractor(custom); err != nil {
+		t.Fatalf("RegisterExtractor() error = %v", err)
+	}
+	if err := RegisterExtractor(custom); err == nil {
+		t.Error("expected duplicate registration to fail")
+	}
+	if err := RegisterExtractor(&ImportExtractor{}); err == nil {
+		t.Error("expected a built-in name to be rejected")
+	}
+
+	var names []string
+	for _, extractor := range Extractors() {
+		names = append(names, extractor.Name())
+	}
+	if want := []string{"packages", "imports", "register-test"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Extractors() = %v, want %v", names, want)
+	}
+}
+
+func TestBuilder_ConcurrentExtractionMatchesSequential(t *testing.T) {
+	pkgs := newPipelineFixture(200)
+	// Give every package a second, non-adjacent import so edges cross workers.
+	for i := 2; i < len(pkgs); i++ {
+		pkgs[i].Imports[pkgs[i/2].PkgPath] = pkgs[i/2]
+	}
+	ctx := context.WithValue(context.Background(), loadedPackagesKey{}, loadedSet(pkgs))
+
+	sequential := graph.NewBuilder()
+	for _, pkg := range pkgs {
+		extractInto(t, ctx, pkg, sequential)
+	}
+
+	concurrent := graph.NewBuilder()
+	var workers sync.WaitGroup
+	for _, pkg := range pkgs {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			extractInto(t, ctx, pkg, concurrent)
+		}()
+	}
+	workers.Wait()
+
+	for _, builder := range []*graph.Builder{sequential, concurrent} {
+		if err := builder.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+	}
+
+	want, got := sequential.Graph(), concurrent.Graph()
+	if len(got.Nodes()) != len(want.Nodes()) {
+		t.Fatalf("concurrent run has %d nodes, sequential %d", len(got.Nodes()), len(want.Nodes()))
+	}
+	for _, wantNode := range want.Nodes() {
+		gotNode, found := got.Node(wantNode.ID)
+		if !found || !reflect.DeepEqual(gotNode, wantNode) {
+			t.Errorf("node %q = %+v, want %+v", wantNode.ID, gotNode, wantNode)
+		}
+	}
+	if gotEdges, wantEdges := sortedEdgeKeys(got), sortedEdgeKeys(want); !reflect.DeepEqual(gotEdges, wantEdges) {
+		t.Errorf("concurrent edges differ from sequential edges:\n got %v\nwant %v", gotEdges, wantEdges)
+	}
+}
+
+func extractInto(t *testing.T, ctx context.Context, pkg *packages.Package, emitter graph.Emitter) {
+	for _, extractor := range []Extractor{&PackageExtractor{}, &ImportExtractor{}} {
+		if err := extractor.Extract(ctx, pkg, emitter); err != nil {
+			t.Errorf("%s failed on %s: %v", extractor.Name(), pkg.PkgPath, err)
+		}
+	}
+}
+
+func sortedEdgeKeys(g *graph.Graph) []graph.EdgeKey {
+	keys := make([]graph.EdgeKey, 0, len(g.Edges()))
+	for _, edge := range g.Edges() {
+		keys = append(keys, edge.Key())
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].From != keys[j].From {
+			return keys[i].From < keys[j].From
+		}
+		return keys[i].To < keys[j].To
+	})
+	return keys
+}
+
+func TestBuild_GeneratedModuleMatchesExpectedGraph(t *testing.T) {
+	repo := repogen.Generate(t, repogen.Config{
+		Packages: 60, Topology: repogen.RandomDAG, Density: 0.1, Seed: 3, FunctionsPerPackage: 2, TestFiles: true, Generics: true,
+	})
+	pkgs, errorCount, err := codeparser.Load(repo.Root, true)
+	if err != nil || errorCount > 0 {
+		t.Fatalf("Load() = %d errors, %v", errorCount, err)
+	}
+
+	g, _, err := Build(context.Background(), pkgs, Extractors())
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	imports := graph.New()
+	for _, node := range g.Nodes() {
+		if node.Kind == graph.KindPackage {
+			_ = imports.AddNode(node)
+		}
+	}
+	for _, edge := range g.Edges() {
+		if edge.Kind == graph.EdgeImport {
+			_ = imports.AddEdge(edge)
+		}
+	}
+	if got, want := len(imports.Nodes()), len(repo.Packages); got != want {
+		t.Errorf("got %d package nodes, want %d", got, want)
+	}
+	if got, want := sortedEdgeKeys(imports), sortedEdgeKeys(repo.Graph()); !reflect.DeepEqual(got, want) {
+		t.Errorf("import edges = %v, want %v", got, want)
+	}
+}
+
+// benchmarkSizes are the package counts of the generated benchmark modules.
+var benchmarkSizes = []int{50, 500, 2000}
+
+// benchmarkRepo generates a module of count packages, each importing about
+// three earlier ones, with a few functions and generics to extract.
+func benchmarkRepo(b *testing.B, count int) *repogen.Repo {
+	b.Helper()
+
+	return repogen.Generate(b, repogen.Config{
+		Packages:            count,
+		Topology:            repogen.RandomDAG,
+		Density:             6 / float64(count-1),
+		Seed:                1,
+		FunctionsPerPackage: 3,
+		Generics:            true,
+	})
+}
+
+// BenchmarkLoadAndExtract measures the whole pipeline, go/packages loading
+// included, on generated modules.
+func BenchmarkLoadAndExtract(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprintf("packages=%d", size), func(b *testing.B) {
+			dir := benchmarkRepo(b, size).Root
+			b.ResetTimer()
+			for b.Loop() {
+				pkgs, errorCount, err := codeparser.Load(dir, false)
+				if err != nil || errorCount > 0 {
+					b.Fatalf("Load() = %d errors, %v", errorCount, err)
+				}
+				if _, _, err := Build(context.Background(), pkgs, Extractors()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkBuild measures the post-load phase alone, extraction and the
+// coupling metrics, which should scale with -cpu.
+func BenchmarkBuild(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprintf("packages=%d", size), func(b *testing.B) {
+			pkgs, errorCount, err := codeparser.Load(benchmarkRepo(b, size).Root, false)
+			if err != nil || errorCount > 0 {
+				b.Fatalf("Load() = %d errors, %v", errorCount, err)
+			}
+			b.ResetTimer()
+			for b.Loop() {
+				if _, _, err := Build(context.Background(), pkgs, Extractors()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkBuildMemory reports, on the largest generated module, the peak heap
+// growth while building and the heap the finished graph retains. Loaded
+// packages are excluded by measuring from a collected baseline after Load.
+func BenchmarkBuildMemory(b *testing.B) {
+	size := benchmarkSizes[len(benchmarkSizes)-1]
+	pkgs, errorCount, err := codeparser.Load(benchmarkRepo(b, size).Root, false)
+	if err != nil || errorCount > 0 {
+		b.Fatalf("Load() = %d errors, %v", errorCount, err)
+	}
+
+	var peak, retained uint64
+	b.ResetTimer()
+	for b.Loop() {
+		runtime.GC()
+		baseline := heapAlloc()
+		stop := samplePeakHeap()
+		g, _, err := Build(context.Background(), pkgs, Extractors())
+		if err != nil {
+			b.Fatal(err)
+		}
+		peak = max(peak, heapGrowth(baseline, stop()))
+		runtime.GC()
+		retained = max(retained, heapGrowth(baseline, heapAlloc()))
+		runtime.KeepAlive(g)
+	}
+	b.ReportMetric(float64(peak), "peak-heap-B")
+	b.ReportMetric(float64(retained), "graph-heap-B")
+}
+
+// heapGrowth returns how far the heap grew from baseline to current, 0 when
+// a collection left it smaller than the baseline.
+func heapGrowth(baseline, current uint64) uint64 {
+	if current < baseline {
+		return 0
+	}
+	return current - baseline
+}
+
+func heapAlloc() uint64 {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
+
+// samplePeakHeap polls HeapAlloc until the returned func is called, which
+// returns the highest value seen.
+func samplePeakHeap() func() uint64 {
+	done := make(chan struct{})
+	result := make(chan uint64)
+	go func() {
+		peak := heapAlloc()
+		ticker := time.NewTicker(time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				peak = max(peak, heapAlloc())
+			case <-done:
+				result <- max(peak, heapAlloc())
+				return
+			}
+		}
+	}()
+	return func() uint64 {
+		close(done)
+		return <-result
+	}
+}
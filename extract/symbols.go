@@ -0,0 +1,67 @@
+package extract
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/Desgue/codegraph/graph"
+	"github.com/Desgue/codegraph/parser"
+	"golang.org/x/tools/go/packages"
+)
+
+// AttributeDocumented is the function node attribute recording, "true" or
+// "false", whether the function has a doc comment.
+const AttributeDocumented = "documented"
+
+// Function node attributes counting the function's parameters, results and
+// bool parameters, as parser.CountSignature does.
+const (
+	AttributeParamCount     = "param_count"
+	AttributeResultCount    = "result_count"
+	AttributeBoolParamCount = "bool_param_count"
+)
+
+// SymbolExtractor emits a function node for every exported function and
+// method of an exported type, carrying AttributeDocumented and the signature
+// counts, so undocumented API and unwieldy signatures can be queried per
+// package. It is not one of the built-in extractors: function nodes multiply
+// the graph's size, so callers opt in.
+type SymbolExtractor struct {
+	// SignatureExclude lists parameter and result types, as written in the
+	// source (e.g. "context.Context", "error"), left out of the counts.
+	SignatureExclude []string
+}
+
+func (e *SymbolExtractor) Name() string {
+	return "symbols"
+}
+
+func (e *SymbolExtractor) Fingerprint() string {
+	return fmt.Sprintf("exclude=%q", e.SignatureExclude)
+}
+
+func (e *SymbolExtractor) Extract(ctx context.Context, pkg *packages.Package, emitter graph.Emitter) error {
+	for _, decl := range parser.ExportedDecls(pkg) {
+		if decl.Kind != "func" {
+			continue
+		}
+		node := graph.Node{
+			ID:   graph.FuncID(pkg.PkgPath, decl.Receiver, decl.Name),
+			Kind: graph.KindFunction,
+			Name: decl.Name,
+		}
+		if pkg.Module != nil {
+			node.ModulePath = pkg.Module.Path
+		}
+		node.SetAttribute(AttributeDocumented, strconv.FormatBool(decl.Documented))
+		counts := parser.CountSignature(decl.Type, e.SignatureExclude)
+		node.SetAttribute(AttributeParamCount, strconv.Itoa(counts.Params))
+		node.SetAttribute(AttributeResultCount, strconv.Itoa(counts.Results))
+		node.SetAttribute(AttributeBoolParamCount, strconv.Itoa(counts.BoolParams))
+		if err := emitter.EmitNode(node); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package extract
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+	"golang.org/x/tools/go/packages"
+)
+
+func TestSymbolExtractor(t *testing.T) {
+	fileSet := token.NewFileSet()
+	source := "package store\n\n// Get is documented.\nfunc Get() {}\n\nfunc Put() {}\n\nfunc helper() {}\n\ntype Store struct{}\n\nfunc (s *Store) Close() {}\n"
+	file, err := parser.ParseFile(fileSet, "store.go", source, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("failed to parse source: %v", err)
+	}
+	store := &packages.Package{PkgPath: "example.com/mod/store", Name: "store", Fset: fileSet, Syntax: []*ast.File{file},
+		Module: &packages.Module{Path: "example.com/mod"}}
+
+	symbolGraph, failures, err := Build(context.Background(), []*packages.Package{store},
+		[]Extractor{&PackageExtractor{}, &SymbolExtractor{}})
+	if err != nil || len(failures) > 0 {
+		t.Fatalf("Build() failures = %v, err = %v", failures, err)
+	}
+
+	want := map[string]string{
+		graph.FuncID("example.com/mod/store", "", "Get"):        "true",
+		graph.FuncID("example.com/mod/store", "", "Put"):        "false",
+		graph.FuncID("example.com/mod/store", "Store", "Close"): "false",
+	}
+	functions := 0
+	for _, node := range symbolGraph.Nodes() {
+		if node.Kind != graph.KindFunction {
+			continue
+		}
+		functions++
+		if got := node.Attributes[AttributeDocumented]; got != want[node.ID] {
+			t.Errorf("%s documented = %q, want %q", node.ID, got, want[node.ID])
+		}
+	}
+	if functions != len(want) {
+		t.Errorf("got %d function nodes, want %d", functions, len(want))
+	}
+
+	storeNode, _ := symbolGraph.Node("example.com/mod/store")
+	// Only Get is documented out of Get, Put, Store and Close.
+	if got := storeNode.Attributes[AttributeDocCoverage]; got != "25" {
+		t.Errorf("store %s = %q, want \"25\"", AttributeDocCoverage, got)
+	}
+}
+
+func TestSymbolExtractor_SignatureCounts(t *testing.T) {
+	fileSet := token.NewFileSet()
+	source := "package store\n\nimport \"context\"\n\nfunc Put(ctx context.Context, key string, sync, force bool) (int, error) { return 0, nil }\n"
+	file, err := parser.ParseFile(fileSet, "store.go", source, 0)
+	if err != nil {
+		t.Fatalf("failed to parse source: %v", err)
+	}
+	store := &packages.Package{PkgPath: "example.com/mod/store", Name: "store", Fset: fileSet, Syntax: []*ast.File{file}}
+
+	for _, tt := range []struct {
+		exclude []string
+		want    [3]string
+	}{
+		{want: [3]string{"4", "2", "2"}},
+		{exclude: []string{"context.Context", "error"}, want: [3]string{"3", "1", "2"}},
+	} {
+		symbolGraph, failures, err := Build(context.Background(), []*packages.Package{store},
+			[]Extractor{&SymbolExtractor{SignatureExclude: tt.exclude}})
+		if err != nil || len(failures) > 0 {
+			t.Fatalf("Build() failures = %v, err = %v", failures, err)
+		}
+		put, _ := symbolGraph.Node(graph.FuncID("example.com/mod/store", "", "Put"))
+		got := [3]string{put.Attributes[AttributeParamCount], put.Attributes[AttributeResultCount], put.Attributes[AttributeBoolParamCount]}
+		if got != tt.want {
+			t.Errorf("exclude %v: params, results, bools = %v, want %v", tt.exclude, got, tt.want)
+		}
+	}
+}
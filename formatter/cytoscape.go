@@ -0,0 +1,73 @@
+package formatter
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+// CytoscapeFormatter writes graphs as the JSON array of elements that
+// Cytoscape.js accepts as its elements option, one element per line: nodes
+// as {"data": {"id": ..., "label": ...}} and edges as {"data": {"source": ...,
+// "target": ...}}. Node data also carries every property of Node.Properties,
+// edge data the edge kind and attributes, so they can drive styles and
+// selectors.
+type CytoscapeFormatter struct{}
+
+type cytoscapeElement struct {
+	Data map[string]any `json:"data"`
+}
+
+func (f *CytoscapeFormatter) Encode(writer io.Writer, g *graph.Graph) error {
+	var elements []cytoscapeElement
+	for _, node := range g.Nodes() {
+		data := make(map[string]any)
+		for name, value := range node.Properties() {
+			data[name] = value
+		}
+		data["id"] = node.ID
+		data["label"] = node.Label()
+		elements = append(elements, cytoscapeElement{Data: data})
+	}
+
+	usedIDs := make(map[string]bool, len(g.Edges()))
+	for _, edge := range g.Edges() {
+		data := make(map[string]any)
+		for name, value := range edge.Attributes {
+			data[name] = value
+		}
+		// Edge IDs follow the GEXF formatter, adding the kind only when
+		// needed to keep them unique.
+		id := edge.From + "|" + edge.To
+		if usedIDs[id] {
+			id += "|" + string(edge.Kind)
+		}
+		usedIDs[id] = true
+		data["id"] = id
+		data["source"] = edge.From
+		data["target"] = edge.To
+		data["kind"] = string(edge.Kind)
+		if edge.IsTestOnly {
+			data["test_only"] = true
+		}
+		elements = append(elements, cytoscapeElement{Data: data})
+	}
+
+	bufferedWriter := bufio.NewWriter(writer)
+	bufferedWriter.WriteString("[")
+	for i, element := range elements {
+		content, err := json.Marshal(element)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			bufferedWriter.WriteString(",")
+		}
+		bufferedWriter.WriteString("\n")
+		bufferedWriter.Write(content)
+	}
+	bufferedWriter.WriteString("\n]\n")
+	return bufferedWriter.Flush()
+}
@@ -0,0 +1,53 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+func TestCytoscapeFormatter_Encode(t *testing.T) {
+	g := newTestGraph(t)
+	if err := g.AddEdge(&graph.Edge{From: "example.com/mod/api", To: "example.com/mod/store", Kind: graph.EdgeTestImport, IsTestOnly: true}); err != nil {
+		t.Fatalf("AddEdge() error = %v", err)
+	}
+
+	var output bytes.Buffer
+	if err := (&CytoscapeFormatter{}).Encode(&output, g); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	var elements []struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(output.Bytes(), &elements); err != nil {
+		t.Fatalf("output is not a JSON array: %v\n%s", err, output.String())
+	}
+	if len(elements) != 7 {
+		t.Fatalf("got %d elements, want 3 nodes and 4 edges:\n%s", len(elements), output.String())
+	}
+
+	store := elements[2].Data
+	if store["id"] != "example.com/mod/store" || store["label"] != "store" {
+		t.Errorf("store id, label = %v, %v", store["id"], store["label"])
+	}
+	if store["loc"] != "120" || store["moduleVersion"] != "v1.4.0" || store["kind"] != "package" {
+		t.Errorf("store data is missing node properties: %v", store)
+	}
+
+	first := elements[3].Data
+	if first["id"] != "example.com/mod/api|example.com/mod/store" || first["source"] != "example.com/mod/api" ||
+		first["target"] != "example.com/mod/store" || first["kind"] != "import" {
+		t.Errorf("unexpected edge data %v", first)
+	}
+	testEdge := elements[6].Data
+	if testEdge["id"] != "example.com/mod/api|example.com/mod/store|test_import" || testEdge["test_only"] != true {
+		t.Errorf("unexpected test edge data %v", testEdge)
+	}
+
+	if format, found := graph.LookupFormat("cytoscape"); !found || format.CanDecode() {
+		t.Errorf("cytoscape format registered = %v, want an encode-only format", found)
+	}
+}
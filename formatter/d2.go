@@ -0,0 +1,83 @@
+package formatter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+// D2Formatter writes graphs in the D2 diagram language (https://d2lang.com).
+// Each module becomes a container keyed by its module path, holding its
+// nodes as "<id>": <name> declarations, so the diagram shows package names
+// while keys stay unique import paths; nodes without a module are declared
+// at the top level. Edges follow as "<from> -> <to>" statements between the
+// nodes' full container paths, test-only ones dashed. Every key is quoted,
+// since D2 reads unquoted dots as nesting.
+type D2Formatter struct{}
+
+func (f *D2Formatter) Encode(writer io.Writer, g *graph.Graph) error {
+	bufferedWriter := bufio.NewWriter(writer)
+	fmt.Fprintf(bufferedWriter, "# codegraph schema %s\n", graph.SchemaVersion)
+
+	nodes, edges := orderedElements(g, true)
+	modules := make(map[string][]*graph.Node)
+	// Each node's quoted container path is built once and reused by every
+	// edge touching it.
+	paths := make(map[string]string, len(nodes))
+	for _, node := range nodes {
+		if node.ModulePath == "" {
+			paths[node.ID] = quoteD2(node.ID)
+			continue
+		}
+		modules[node.ModulePath] = append(modules[node.ModulePath], node)
+		paths[node.ID] = string(appendQuoted(append(appendQuoted(nil, node.ModulePath), '.'), node.ID))
+	}
+	modulePaths := make([]string, 0, len(modules))
+	for modulePath := range modules {
+		modulePaths = append(modulePaths, modulePath)
+	}
+	sort.Strings(modulePaths)
+
+	for _, modulePath := range modulePaths {
+		bufferedWriter.Write(append(appendQuoted(bufferedWriter.AvailableBuffer(), modulePath), ": {\n"...))
+		for _, node := range modules[modulePath] {
+			writeD2Node(bufferedWriter, "  ", node)
+		}
+		bufferedWriter.WriteString("}\n")
+	}
+	for _, node := range nodes {
+		if node.ModulePath == "" {
+			writeD2Node(bufferedWriter, "", node)
+		}
+	}
+	for _, edge := range edges {
+		line := append(bufferedWriter.AvailableBuffer(), paths[edge.From]...)
+		line = append(append(line, " -> "...), paths[edge.To]...)
+		if edge.IsTestOnly {
+			line = append(line, ": {style.stroke-dash: 3}"...)
+		}
+		bufferedWriter.Write(append(line, '\n'))
+	}
+
+	return bufferedWriter.Flush()
+}
+
+// writeD2Node declares node labelled with its package name, or with its label
+// when it has no name.
+func writeD2Node(writer *bufio.Writer, indent string, node *graph.Node) {
+	label := node.Name
+	if label == "" {
+		label = node.Label()
+	}
+	line := appendQuoted(append(writer.AvailableBuffer(), indent...), node.ID)
+	line = appendQuoted(append(line, ": "...), label)
+	writer.Write(append(line, '\n'))
+}
+
+// quoteD2 returns value as a double-quoted D2 string.
+func quoteD2(value string) string {
+	return string(appendQuoted(nil, value))
+}
@@ -0,0 +1,71 @@
+package formatter
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+// d2Line matches the D2 subset D2Formatter writes: comments, quoted-key
+// declarations with a quoted label, container openings and closings, and
+// edges between quoted key paths with an optional style map.
+var d2Line = regexp.MustCompile(`^(` +
+	`# .*` +
+	`|\s*"(?:[^"\\]|\\.)*": "(?:[^"\\]|\\.)*"` +
+	`|"(?:[^"\\]|\\.)*": \{|\}` +
+	`|"(?:[^"\\]|\\.)*"(?:\."(?:[^"\\]|\\.)*")? -> "(?:[^"\\]|\\.)*"(?:\."(?:[^"\\]|\\.)*")?(?:: \{[\w.-]+: \w+\})?` +
+	`)$`)
+
+func TestD2Formatter_Encode(t *testing.T) {
+	g := newTestGraph(t)
+	if err := g.AddNode(&graph.Node{ID: "fmt", Kind: graph.KindPackage, Name: "fmt"}); err != nil {
+		t.Fatalf("AddNode() error = %v", err)
+	}
+	for _, edge := range []*graph.Edge{
+		{From: "example.com/mod/cmd", To: "fmt", Kind: graph.EdgeImport},
+		{From: "example.com/mod/api", To: "example.com/mod/cmd", Kind: graph.EdgeTestImport, IsTestOnly: true},
+	} {
+		if err := g.AddEdge(edge); err != nil {
+			t.Fatalf("AddEdge() error = %v", err)
+		}
+	}
+
+	var output bytes.Buffer
+	if err := (&D2Formatter{}).Encode(&output, g); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	want := "# codegraph schema " + graph.SchemaVersion + "\n" +
+		`"example.com/mod": {` + "\n" +
+		`  "example.com/mod/api": "api"` + "\n" +
+		`  "example.com/mod/cmd": "main"` + "\n" +
+		`  "example.com/mod/store": "store"` + "\n" +
+		"}\n" +
+		`"fmt": "fmt"` + "\n" +
+		`"example.com/mod"."example.com/mod/api" -> "example.com/mod"."example.com/mod/cmd": {style.stroke-dash: 3}` + "\n" +
+		`"example.com/mod"."example.com/mod/api" -> "example.com/mod"."example.com/mod/store"` + "\n" +
+		`"example.com/mod"."example.com/mod/cmd" -> "example.com/mod"."example.com/mod/api"` + "\n" +
+		`"example.com/mod"."example.com/mod/cmd" -> "example.com/mod"."example.com/mod/store"` + "\n" +
+		`"example.com/mod"."example.com/mod/cmd" -> "fmt"` + "\n"
+	if output.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", output.String(), want)
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n") {
+		if !d2Line.MatchString(line) {
+			t.Errorf("line is not valid D2: %q", line)
+		}
+	}
+}
+
+func TestQuoteD2(t *testing.T) {
+	if got, want := quoteD2(`a"b\c`), `"a\"b\\c"`; got != want {
+		t.Errorf("quoteD2() = %s, want %s", got, want)
+	}
+	if !d2Line.MatchString(quoteD2(`a"b`) + ": " + quoteD2(`c\d`)) {
+		t.Error("escaped declaration does not match the D2 grammar")
+	}
+}
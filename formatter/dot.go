@@ -0,0 +1,238 @@
+package formatter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+// DOTFormatter writes graphs in Graphviz DOT format.
+type DOTFormatter struct {
+	// OmitLabels emits bare node IDs without label attributes. Labels add
+	// significant layout time in dot for graphs with hundreds of nodes, so
+	// large graphs render faster and produce smaller files without them.
+	OmitLabels bool
+	// ClusterByModule draws each module's nodes in a subgraph cluster labelled
+	// with the module path and its package count. Nodes without a module stay
+	// outside any cluster.
+	ClusterByModule bool
+	// ShowClusterStats adds the total lines of code and the average coupling
+	// (fan-in plus fan-out) of each cluster's packages to its label, read from
+	// the loc, fan_in and fan_out node attributes. Implies ClusterByModule.
+	ShowClusterStats bool
+	// RankSame keeps package nodes sharing a Node.Rank on one row with
+	// "{ rank=same; ... }" groups. Ranks are unique within a DAG, so on an
+	// extracted graph this lines up the members of import cycles, which all
+	// have graph.RankCyclic; merged graphs can have other ties.
+	RankSame bool
+	// SortNodes writes nodes sorted by ID and edges sorted by source and
+	// target instead of in insertion order, as GraphMLFormatter.SortNodes
+	// does. The registered encoder sets it.
+	SortNodes bool
+}
+
+// Node attributes read for cluster statistics, as recorded by extract.
+const (
+	linesOfCodeAttribute = "loc"
+	fanInAttribute       = "fan_in"
+	fanOutAttribute      = "fan_out"
+)
+
+func (f *DOTFormatter) Encode(writer io.Writer, g *graph.Graph) error {
+	bufferedWriter := bufio.NewWriter(writer)
+
+	fmt.Fprintf(bufferedWriter, "digraph %s {\n", dotGraphName(g))
+	fmt.Fprintf(bufferedWriter, "  // codegraph schema %s\n", graph.SchemaVersion)
+	nodes, edges := orderedElements(g, f.SortNodes)
+	if f.ClusterByModule || f.ShowClusterStats {
+		f.writeClusters(bufferedWriter, nodes)
+	} else {
+		for _, node := range nodes {
+			f.writeNode(bufferedWriter, "  ", node)
+		}
+	}
+	if f.RankSame {
+		writeRankGroups(bufferedWriter, nodes)
+	}
+	for _, edge := range edges {
+		writeEdge(bufferedWriter, edge)
+	}
+	bufferedWriter.WriteString("}\n")
+
+	return bufferedWriter.Flush()
+}
+
+// writeNode writes one node statement. Labelled nodes carry their module
+// version, when known, as a tooltip, and packages importing unsafe are red.
+func (f *DOTFormatter) writeNode(writer *bufio.Writer, indent string, node *graph.Node) {
+	line := append(writer.AvailableBuffer(), indent...)
+	line = appendQuoted(line, node.ID)
+	attributesStart := len(line)
+	if !f.OmitLabels {
+		line = appendQuoted(appendDOTAttribute(line, attributesStart, ", ", "label="), node.Label())
+		if node.ModuleVersion != "" {
+			line = appendQuoted(appendDOTAttribute(line, attributesStart, ", ", "tooltip="), node.ModuleVersion)
+		}
+	}
+	// Packages importing unsafe stand out for security review.
+	if node.UnsafeUsage {
+		line = appendDOTAttribute(line, attributesStart, ", ", "color=red")
+	}
+	writer.Write(closeDOTStatement(line, attributesStart))
+}
+
+// appendDOTAttribute appends attribute, or its name when the caller appends
+// the value, to the attribute list of a statement whose list would start at
+// start: the first attribute opens the list and later ones follow separator.
+func appendDOTAttribute(line []byte, start int, separator, attribute string) []byte {
+	if len(line) == start {
+		line = append(line, " ["...)
+	} else {
+		line = append(line, separator...)
+	}
+	return append(line, attribute...)
+}
+
+// closeDOTStatement ends a statement whose attribute list would start at
+// start, closing the list if any attribute was appended.
+func closeDOTStatement(line []byte, start int) []byte {
+	if len(line) > start {
+		line = append(line, ']')
+	}
+	return append(line, ";\n"...)
+}
+
+// writeRankGroups writes a rank=same group, in rank order, for every rank
+// shared by several package nodes.
+func writeRankGroups(writer *bufio.Writer, nodes []*graph.Node) {
+	byRank := make(map[int][]*graph.Node)
+	for _, node := range nodes {
+		if node.Kind == graph.KindPackage {
+			byRank[node.Rank] = append(byRank[node.Rank], node)
+		}
+	}
+	ranks := make([]int, 0, len(byRank))
+	for rank, members := range byRank {
+		if len(members) > 1 {
+			ranks = append(ranks, rank)
+		}
+	}
+	sort.Ints(ranks)
+
+	for _, rank := range ranks {
+		line := append(writer.AvailableBuffer(), "  { rank=same;"...)
+		for _, node := range byRank[rank] {
+			line = append(appendQuoted(append(line, ' '), node.ID), ';')
+		}
+		writer.Write(append(line, " }\n"...))
+	}
+}
+
+// writeClusters writes one cluster per module, in module path order, followed
+// by the nodes that have no module.
+func (f *DOTFormatter) writeClusters(writer *bufio.Writer, nodes []*graph.Node) {
+	modules := make(map[string][]*graph.Node)
+	var unclustered []*graph.Node
+	for _, node := range nodes {
+		if node.ModulePath == "" {
+			unclustered = append(unclustered, node)
+			continue
+		}
+		modules[node.ModulePath] = append(modules[node.ModulePath], node)
+	}
+
+	modulePaths := make([]string, 0, len(modules))
+	for modulePath := range modules {
+		modulePaths = append(modulePaths, modulePath)
+	}
+	sort.Strings(modulePaths)
+
+	for i, modulePath := range modulePaths {
+		line := strconv.AppendInt(append(writer.AvailableBuffer(), "  subgraph cluster_"...), int64(i), 10)
+		line = append(line, " {\n    label="...)
+		line = appendQuoted(line, f.clusterLabel(modulePath, modules[modulePath]))
+		writer.Write(append(line, ";\n"...))
+		for _, node := range modules[modulePath] {
+			f.writeNode(writer, "    ", node)
+		}
+		writer.WriteString("  }\n")
+	}
+	for _, node := range unclustered {
+		f.writeNode(writer, "  ", node)
+	}
+}
+
+// clusterLabel describes a module, e.g. "example.com/mod (12 packages)", or
+// with ShowClusterStats "example.com/mod (12 packages, 3400 LOC, avg coupling
+// 2.50)". Packages without a metric attribute count as zero.
+func (f *DOTFormatter) clusterLabel(modulePath string, nodes []*graph.Node) string {
+	packageCount, linesOfCode, coupling := 0, 0, 0
+	for _, node := range nodes {
+		if node.Kind != graph.KindPackage {
+			continue
+		}
+		packageCount++
+		linesOfCode += intAttribute(node, linesOfCodeAttribute)
+		coupling += intAttribute(node, fanInAttribute) + intAttribute(node, fanOutAttribute)
+	}
+
+	unit := "packages"
+	if packageCount == 1 {
+		unit = "package"
+	}
+	if !f.ShowClusterStats {
+		return fmt.Sprintf("%s (%d %s)", modulePath, packageCount, unit)
+	}
+
+	averageCoupling := 0.0
+	if packageCount > 0 {
+		averageCoupling = float64(coupling) / float64(packageCount)
+	}
+	return fmt.Sprintf("%s (%d %s, %d LOC, avg coupling %.2f)", modulePath, packageCount, unit, linesOfCode, averageCoupling)
+}
+
+func intAttribute(node *graph.Node, name string) int {
+	value, _ := strconv.Atoi(node.Attributes[name])
+	return value
+}
+
+// writeEdge renders test-only edges dashed and grey so they stand apart from
+// production dependencies, and draws edges thicker the more symbols of the
+// imported package they use.
+func writeEdge(writer *bufio.Writer, edge *graph.Edge) {
+	line := appendQuoted(append(writer.AvailableBuffer(), "  "...), edge.From)
+	line = appendQuoted(append(line, " -> "...), edge.To)
+	attributesStart := len(line)
+	if edge.IsTestOnly {
+		line = appendDOTAttribute(line, attributesStart, ",", "style=dashed")
+		line = appendDOTAttribute(line, attributesStart, ",", "color=grey")
+	}
+	if edge.Multiplicity > 0 {
+		line = appendEdgePenWidth(appendDOTAttribute(line, attributesStart, ",", "penwidth="), edge.Multiplicity)
+	}
+	writer.Write(closeDOTStatement(line, attributesStart))
+}
+
+// appendEdgePenWidth scales an edge with log2(multiplicity+1), so an import
+// used for one symbol keeps the default width of 1 and every doubling of the
+// symbols used adds one.
+func appendEdgePenWidth(dst []byte, multiplicity int) []byte {
+	return strconv.AppendFloat(dst, math.Log2(float64(multiplicity)+1), 'g', 3, 64)
+}
+
+func dotGraphName(g *graph.Graph) string {
+	if g.Title == "" {
+		return "codegraph"
+	}
+	return quoteDOT(g.Title)
+}
+
+// quoteDOT returns value as a double-quoted DOT identifier.
+func quoteDOT(value string) string {
+	return string(appendQuoted(nil, value))
+}
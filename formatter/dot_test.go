@@ -0,0 +1,214 @@
+package formatter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+func TestDOTFormatter_Encode(t *testing.T) {
+	var output bytes.Buffer
+
+	dotFormatter := &DOTFormatter{}
+	if err := dotFormatter.Encode(&output, newTestGraph(t)); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	want := `digraph codegraph {
+  // codegraph schema 1.0
+  "example.com/mod/api" [label="api"];
+  "example.com/mod/cmd" [label="cmd", color=red];
+  "example.com/mod/store" [label="store", tooltip="v1.4.0"];
+  "example.com/mod/api" -> "example.com/mod/store" [penwidth=2];
+  "example.com/mod/cmd" -> "example.com/mod/api";
+  "example.com/mod/cmd" -> "example.com/mod/store";
+}
+`
+	if output.String() != want {
+		t.Errorf("Encode() output mismatch\ngot:\n%s\nwant:\n%s", output.String(), want)
+	}
+}
+
+func TestDOTFormatter_ClusterByModule(t *testing.T) {
+	g := newTestGraph(t)
+	for _, node := range []*graph.Node{
+		{ID: "example.com/lib/log", Kind: graph.KindPackage, ModulePath: "example.com/lib"},
+		{ID: "standalone", Kind: graph.KindPackage},
+	} {
+		if err := g.AddNode(node); err != nil {
+			t.Fatalf("AddNode() error = %v", err)
+		}
+	}
+
+	var output bytes.Buffer
+	if err := (&DOTFormatter{ClusterByModule: true, OmitLabels: true}).Encode(&output, g); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	want := `digraph codegraph {
+  // codegraph schema 1.0
+  subgraph cluster_0 {
+    label="example.com/lib (1 package)";
+    "example.com/lib/log";
+  }
+  subgraph cluster_1 {
+    label="example.com/mod (3 packages)";
+    "example.com/mod/api";
+    "example.com/mod/cmd" [color=red];
+    "example.com/mod/store";
+  }
+  "standalone";
+  "example.com/mod/api" -> "example.com/mod/store" [penwidth=2];
+  "example.com/mod/cmd" -> "example.com/mod/api";
+  "example.com/mod/cmd" -> "example.com/mod/store";
+}
+`
+	if output.String() != want {
+		t.Errorf("Encode() output mismatch\ngot:\n%s\nwant:\n%s", output.String(), want)
+	}
+}
+
+func TestDOTFormatter_RankSame(t *testing.T) {
+	g := newTestGraph(t)
+	for _, node := range []*graph.Node{
+		{ID: "example.com/mod/x", Kind: graph.KindPackage, Rank: graph.RankCyclic},
+		{ID: "example.com/mod/y", Kind: graph.KindPackage, Rank: graph.RankCyclic},
+		{ID: "example.com/mod/x.F", Kind: graph.KindFunction},
+	} {
+		if err := g.AddNode(node); err != nil {
+			t.Fatalf("AddNode() error = %v", err)
+		}
+	}
+
+	var output bytes.Buffer
+	if err := (&DOTFormatter{RankSame: true, OmitLabels: true, SortNodes: true}).Encode(&output, g); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	// store (rank 0 by default) is alone among packages; functions never group.
+	if !strings.Contains(output.String(), "  { rank=same; \"example.com/mod/x\"; \"example.com/mod/y\"; }\n") {
+		t.Errorf("expected the cycle members on one rank, got:\n%s", output.String())
+	}
+	if strings.Count(output.String(), "rank=same") != 1 {
+		t.Errorf("expected a single rank group, got:\n%s", output.String())
+	}
+}
+
+func TestDOTFormatter_ShowClusterStats(t *testing.T) {
+	g := newTestGraph(t)
+	api, _ := g.Node("example.com/mod/api")
+	api.Attributes = map[string]string{"loc": "30", "fan_in": "1", "fan_out": "1"}
+
+	var output bytes.Buffer
+	if err := (&DOTFormatter{ShowClusterStats: true}).Encode(&output, g); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	// store has loc 120 and fan_in 2; cmd has no metrics.
+	want := `    label="example.com/mod (3 packages, 150 LOC, avg coupling 1.33)";`
+	if !strings.Contains(output.String(), want) {
+		t.Errorf("expected cluster label %s, got:\n%s", want, output.String())
+	}
+}
+
+func TestDOTFormatter_OmitLabels(t *testing.T) {
+	var output bytes.Buffer
+
+	dotFormatter := &DOTFormatter{OmitLabels: true}
+	if err := dotFormatter.Encode(&output, newTestGraph(t)); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	if strings.Contains(output.String(), "label=") {
+		t.Errorf("expected no label attributes, got:\n%s", output.String())
+	}
+	if !strings.Contains(output.String(), "  \"example.com/mod/api\";\n") {
+		t.Errorf("expected bare node ID statement, got:\n%s", output.String())
+	}
+	if !strings.Contains(output.String(), `"example.com/mod/cmd" -> "example.com/mod/api";`) {
+		t.Errorf("expected edges to be kept, got:\n%s", output.String())
+	}
+}
+
+func TestDOTFormatter_TestOnlyEdgesAreDashed(t *testing.T) {
+	g := newTestGraph(t)
+	edge := &graph.Edge{From: "example.com/mod/api", To: "example.com/mod/cmd", Kind: graph.EdgeTestImport, IsTestOnly: true}
+	if err := g.AddEdge(edge); err != nil {
+		t.Fatalf("AddEdge() error = %v", err)
+	}
+
+	var output bytes.Buffer
+	if err := (&DOTFormatter{}).Encode(&output, g); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	if !strings.Contains(output.String(), `"example.com/mod/api" -> "example.com/mod/cmd" [style=dashed,color=grey];`) {
+		t.Errorf("expected dashed grey test-only edge, got:\n%s", output.String())
+	}
+	if !strings.Contains(output.String(), `"example.com/mod/cmd" -> "example.com/mod/store";`) {
+		t.Errorf("expected solid import edge, got:\n%s", output.String())
+	}
+}
+
+func TestEdgePenWidth(t *testing.T) {
+	tests := []struct {
+		multiplicity int
+		want         string
+	}{
+		{multiplicity: 1, want: "1"},
+		{multiplicity: 2, want: "1.58"},
+		{multiplicity: 3, want: "2"},
+		{multiplicity: 100, want: "6.66"},
+	}
+	for _, tt := range tests {
+		if got := string(appendEdgePenWidth(nil, tt.multiplicity)); got != tt.want {
+			t.Errorf("appendEdgePenWidth(%d) = %q, want %q", tt.multiplicity, got, tt.want)
+		}
+	}
+}
+
+func TestQuoteDOT(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "example.com/a", want: `"example.com/a"`},
+		{input: `say "hi"`, want: `"say \"hi\""`},
+		{input: `back\slash`, want: `"back\\slash"`},
+		{input: "two\nlines", want: `"two\nlines"`},
+	}
+
+	for _, tt := range tests {
+		if got := quoteDOT(tt.input); got != tt.want {
+			t.Errorf("quoteDOT(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestDOTFormatter_EmptyGraph(t *testing.T) {
+	var output bytes.Buffer
+
+	if err := (&DOTFormatter{}).Encode(&output, graph.New()); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if output.String() != "digraph codegraph {\n  // codegraph schema 1.0\n}\n" {
+		t.Errorf("unexpected output for empty graph: %q", output.String())
+	}
+}
+
+func TestDOTFormatter_GraphTitle(t *testing.T) {
+	var output bytes.Buffer
+
+	g := graph.New()
+	g.Title = `My "Service" \ v2`
+	if err := (&DOTFormatter{}).Encode(&output, g); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	want := `digraph "My \"Service\" \\ v2" {` + "\n  // codegraph schema 1.0\n}\n"
+	if output.String() != want {
+		t.Errorf("Encode() = %q, want %q", output.String(), want)
+	}
+}
@@ -0,0 +1,63 @@
+package formatter
+
+// The text encoders build each output line in the spare capacity of their
+// bufio.Writer (AvailableBuffer) and escape into it with the append functions
+// below, so writing a node or an edge does not allocate per label. They
+// escape byte by byte: the characters they replace are ASCII and never occur
+// inside a multi-byte UTF-8 sequence, so non-ASCII text passes through as is.
+
+// appendQuoted appends value in double quotes, escaping backslashes, double
+// quotes and newlines with a backslash, as DOT and D2 quote identifiers and
+// labels.
+func appendQuoted(dst []byte, value string) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(value); i++ {
+		var escaped string
+		switch value[i] {
+		case '\\':
+			escaped = `\\`
+		case '"':
+			escaped = `\"`
+		case '\n':
+			escaped = `\n`
+		default:
+			continue
+		}
+		dst = append(dst, value[start:i]...)
+		dst = append(dst, escaped...)
+		start = i + 1
+	}
+	dst = append(dst, value[start:]...)
+	return append(dst, '"')
+}
+
+// appendMarkdownEscaped appends value with a backslash before each character
+// that would break a table cell or start emphasis or code in package paths and
+// titles.
+func appendMarkdownEscaped(dst []byte, value string) []byte {
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\', '|', '*', '_', '`':
+			dst = append(dst, value[start:i]...)
+			dst = append(dst, '\\')
+			start = i
+		}
+	}
+	return append(dst, value[start:]...)
+}
+
+// appendMermaidLabel appends value for use inside a double-quoted Mermaid
+// node label, where double quotes are written as the #quot; entity.
+func appendMermaidLabel(dst []byte, value string) []byte {
+	start := 0
+	for i := 0; i < len(value); i++ {
+		if value[i] == '"' {
+			dst = append(dst, value[start:i]...)
+			dst = append(dst, "#quot;"...)
+			start = i + 1
+		}
+	}
+	return append(dst, value[start:]...)
+}
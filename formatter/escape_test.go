@@ -0,0 +1,105 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+)
+
+// escapeSeeds cover the characters the escaping functions replace, alone and
+// mixed with non-ASCII identifiers and invalid UTF-8.
+var escapeSeeds = []string{
+	"",
+	"example.com/a",
+	`say "hi"`,
+	`back\slash`,
+	`trailing\`,
+	`\"`,
+	"multi\nline",
+	"example.com/пакет/данные",
+	"例え.jp/パッケージ",
+	"é\"\\\nü",
+	"|*_`",
+	"x_test | `go` *bold*",
+	"\xff\xfe\"",
+}
+
+func FuzzAppendQuoted(f *testing.F) {
+	for _, seed := range escapeSeeds {
+		f.Add(seed)
+	}
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	f.Fuzz(func(t *testing.T, value string) {
+		got := appendQuoted([]byte("prefix "), value)
+		quoted, found := strings.CutPrefix(string(got), "prefix ")
+		if !found {
+			t.Fatalf("appendQuoted(%q) overwrote the destination: %q", value, got)
+		}
+		if want := `"` + replacer.Replace(value) + `"`; quoted != want {
+			t.Errorf("appendQuoted(%q) = %s, want %s", value, quoted, want)
+		}
+		if unquoted, ok := unquote(quoted); !ok || unquoted != value {
+			t.Errorf("unquote(appendQuoted(%q)) = %q, %v, want the original value", value, unquoted, ok)
+		}
+	})
+}
+
+func FuzzAppendMarkdownEscaped(f *testing.F) {
+	for _, seed := range escapeSeeds {
+		f.Add(seed)
+	}
+	replacer := strings.NewReplacer(`\`, `\\`, "|", `\|`, "*", `\*`, "_", `\_`, "`", "\\`")
+	f.Fuzz(func(t *testing.T, value string) {
+		got := string(appendMarkdownEscaped([]byte("- "), value))
+		if want := "- " + replacer.Replace(value); got != want {
+			t.Errorf("appendMarkdownEscaped(%q) = %q, want %q", value, got, want)
+		}
+	})
+}
+
+func FuzzAppendMermaidLabel(f *testing.F) {
+	for _, seed := range escapeSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, value string) {
+		got := string(appendMermaidLabel([]byte(`n0["`), value))
+		if want := `n0["` + strings.ReplaceAll(value, `"`, "#quot;"); got != want {
+			t.Errorf("appendMermaidLabel(%q) = %q, want %q", value, got, want)
+		}
+		if strings.Contains(got[len(`n0["`):], `"`) {
+			t.Errorf("appendMermaidLabel(%q) = %q, which ends the label early", value, got)
+		}
+	})
+}
+
+// unquote reverses appendQuoted, reporting whether quoted is a well-formed
+// quoted string: no raw newlines or unescaped quotes inside the delimiters
+// and no escapes other than \\, \" and \n.
+func unquote(quoted string) (string, bool) {
+	if len(quoted) < 2 || quoted[0] != '"' || quoted[len(quoted)-1] != '"' {
+		return "", false
+	}
+	body := quoted[1 : len(quoted)-1]
+	var value strings.Builder
+	for i := 0; i < len(body); i++ {
+		switch body[i] {
+		case '"', '\n':
+			return "", false
+		case '\\':
+			i++
+			if i == len(body) {
+				return "", false
+			}
+			switch body[i] {
+			case '\\', '"':
+				value.WriteByte(body[i])
+			case 'n':
+				value.WriteByte('\n')
+			default:
+				return "", false
+			}
+		default:
+			value.WriteByte(body[i])
+		}
+	}
+	return value.String(), true
+}
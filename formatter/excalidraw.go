@@ -0,0 +1,220 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+// Excalidraw grid geometry, in scene pixels. Boxes grow with their label so
+// long package paths stay inside them; every grid cell fits the widest box.
+const (
+	excalidrawFontSize    = 16
+	excalidrawCharWidth   = 9
+	excalidrawPadding     = 24
+	excalidrawMinWidth    = 120
+	excalidrawBoxHeight   = 60
+	excalidrawColumnGap   = 80
+	excalidrawRowGap      = 100
+	excalidrawArrowMargin = 4
+)
+
+// ExcalidrawFormatter writes graphs as an Excalidraw scene
+// (https://excalidraw.com), which can be opened or pasted there for
+// collaborative editing. Nodes are laid out on a square grid in ID order, each
+// drawn as a rectangle with its label bound inside; edges are arrows bound to
+// both rectangles, dashed when test-only. Element IDs are derived from the
+// element order, and the codegraph node ID and edge kind travel in each
+// element's customData. Self-loops are not drawn.
+type ExcalidrawFormatter struct{}
+
+type excalidrawScene struct {
+	Type     string              `json:"type"`
+	Version  int                 `json:"version"`
+	Source   string              `json:"source"`
+	Elements []excalidrawElement `json:"elements"`
+	AppState map[string]any      `json:"appState"`
+	Files    map[string]any      `json:"files"`
+}
+
+// excalidrawElement holds the fields shared by every element type; the
+// type-specific ones are omitted when empty.
+type excalidrawElement struct {
+	ID              string                `json:"id"`
+	Type            string                `json:"type"`
+	X               float64               `json:"x"`
+	Y               float64               `json:"y"`
+	Width           float64               `json:"width"`
+	Height          float64               `json:"height"`
+	Angle           float64               `json:"angle"`
+	StrokeColor     string                `json:"strokeColor"`
+	BackgroundColor string                `json:"backgroundColor"`
+	FillStyle       string                `json:"fillStyle"`
+	StrokeWidth     int                   `json:"strokeWidth"`
+	StrokeStyle     string                `json:"strokeStyle"`
+	Roughness       int                   `json:"roughness"`
+	Opacity         int                   `json:"opacity"`
+	GroupIDs        []string              `json:"groupIds"`
+	FrameID         *string               `json:"frameId"`
+	Roundness       *excalidrawRoundness  `json:"roundness"`
+	Seed            int                   `json:"seed"`
+	Version         int                   `json:"version"`
+	VersionNonce    int                   `json:"versionNonce"`
+	IsDeleted       bool                  `json:"isDeleted"`
+	BoundElements   []excalidrawBinding   `json:"boundElements"`
+	Updated         int                   `json:"updated"`
+	Link            *string               `json:"link"`
+	Locked          bool                  `json:"locked"`
+	CustomData      map[string]string     `json:"customData,omitempty"`
+	Text            string                `json:"text,omitempty"`
+	OriginalText    string                `json:"originalText,omitempty"`
+	FontSize        int                   `json:"fontSize,omitempty"`
+	FontFamily      int                   `json:"fontFamily,omitempty"`
+	TextAlign       string                `json:"textAlign,omitempty"`
+	VerticalAlign   string                `json:"verticalAlign,omitempty"`
+	LineHeight      float64               `json:"lineHeight,omitempty"`
+	ContainerID     string                `json:"containerId,omitempty"`
+	Points          [][2]float64          `json:"points,omitempty"`
+	StartBinding    *excalidrawArrowBound `json:"startBinding,omitempty"`
+	EndBinding      *excalidrawArrowBound `json:"endBinding,omitempty"`
+	EndArrowhead    string                `json:"endArrowhead,omitempty"`
+}
+
+type excalidrawRoundness struct {
+	Type int `json:"type"`
+}
+
+type excalidrawBinding struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+}
+
+type excalidrawArrowBound struct {
+	ElementID string  `json:"elementId"`
+	Focus     float64 `json:"focus"`
+	Gap       float64 `json:"gap"`
+}
+
+func (f *ExcalidrawFormatter) Encode(writer io.Writer, g *graph.Graph) error {
+	nodes, edges := orderedElements(g, true)
+
+	boxWidth := float64(excalidrawMinWidth)
+	for _, node := range nodes {
+		boxWidth = max(boxWidth, excalidrawLabelWidth(node.Label()))
+	}
+	columns := max(1, int(math.Ceil(math.Sqrt(float64(len(nodes))))))
+
+	elements := make([]excalidrawElement, 0, 2*len(nodes)+len(edges))
+	boxes := make(map[string]int, len(nodes))
+	for i, node := range nodes {
+		boxID, labelID := fmt.Sprintf("node-%d", i), fmt.Sprintf("label-%d", i)
+		width := excalidrawLabelWidth(node.Label())
+		box := newExcalidrawElement(boxID, "rectangle", len(elements))
+		// Boxes are centered in their grid cell.
+		box.X = float64(i%columns)*(boxWidth+excalidrawColumnGap) + (boxWidth-width)/2
+		box.Y = float64(i/columns) * (excalidrawBoxHeight + excalidrawRowGap)
+		box.Width, box.Height = width, excalidrawBoxHeight
+		box.BackgroundColor, box.Roundness = "#a5d8ff", &excalidrawRoundness{Type: 3}
+		box.BoundElements = []excalidrawBinding{{ID: labelID, Type: "text"}}
+		box.CustomData = map[string]string{"codegraph_id": node.ID, "kind": string(node.Kind)}
+		boxes[node.ID] = len(elements)
+		elements = append(elements, box)
+
+		label := newExcalidrawElement(labelID, "text", len(elements))
+		label.X, label.Y = box.X+excalidrawPadding/2, box.Y+(excalidrawBoxHeight-excalidrawFontSize*1.25)/2
+		label.Width, label.Height = width-excalidrawPadding, excalidrawFontSize*1.25
+		label.StrokeWidth = 1
+		label.Text, label.OriginalText = node.Label(), node.Label()
+		label.FontSize, label.FontFamily, label.LineHeight = excalidrawFontSize, 1, 1.25
+		label.TextAlign, label.VerticalAlign, label.ContainerID = "center", "middle", boxID
+		elements = append(elements, label)
+	}
+
+	for i, edge := range edges {
+		from, fromFound := boxes[edge.From]
+		to, toFound := boxes[edge.To]
+		if !fromFound || !toFound || from == to {
+			continue
+		}
+		arrowID := fmt.Sprintf("edge-%d", i)
+		startX, startY := excalidrawBorderPoint(elements[from], elements[to])
+		endX, endY := excalidrawBorderPoint(elements[to], elements[from])
+
+		arrow := newExcalidrawElement(arrowID, "arrow", len(elements))
+		arrow.X, arrow.Y = startX, startY
+		arrow.Width, arrow.Height = math.Abs(endX-startX), math.Abs(endY-startY)
+		arrow.Roundness = &excalidrawRoundness{Type: 2}
+		arrow.Points = [][2]float64{{0, 0}, {endX - startX, endY - startY}}
+		arrow.StartBinding = &excalidrawArrowBound{ElementID: elements[from].ID, Gap: excalidrawArrowMargin}
+		arrow.EndBinding = &excalidrawArrowBound{ElementID: elements[to].ID, Gap: excalidrawArrowMargin}
+		arrow.EndArrowhead = "arrow"
+		arrow.CustomData = map[string]string{"kind": string(edge.Kind)}
+		if edge.IsTestOnly {
+			arrow.StrokeStyle, arrow.StrokeColor = "dashed", "#868e96"
+		}
+		elements[from].BoundElements = append(elements[from].BoundElements, excalidrawBinding{ID: arrowID, Type: "arrow"})
+		elements[to].BoundElements = append(elements[to].BoundElements, excalidrawBinding{ID: arrowID, Type: "arrow"})
+		elements = append(elements, arrow)
+	}
+
+	scene := excalidrawScene{
+		Type:     "excalidraw",
+		Version:  2,
+		Source:   "codegraph",
+		Elements: elements,
+		AppState: map[string]any{"viewBackgroundColor": "#ffffff", "gridSize": nil},
+		Files:    map[string]any{},
+	}
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(scene)
+}
+
+// newExcalidrawElement returns an element with Excalidraw's defaults. The
+// seed, which drives the hand-drawn jitter, comes from the element index so
+// output is reproducible.
+func newExcalidrawElement(id, elementType string, index int) excalidrawElement {
+	return excalidrawElement{
+		ID:              id,
+		Type:            elementType,
+		StrokeColor:     "#1e1e1e",
+		BackgroundColor: "transparent",
+		FillStyle:       "solid",
+		StrokeWidth:     2,
+		StrokeStyle:     "solid",
+		Roughness:       1,
+		Opacity:         100,
+		GroupIDs:        []string{},
+		Seed:            index + 1,
+		Version:         1,
+		VersionNonce:    index + 1,
+		BoundElements:   []excalidrawBinding{},
+		Updated:         1,
+	}
+}
+
+// excalidrawLabelWidth estimates the width of a box fitting label.
+func excalidrawLabelWidth(label string) float64 {
+	return max(excalidrawMinWidth, float64(len(label)*excalidrawCharWidth+excalidrawPadding))
+}
+
+// excalidrawBorderPoint returns where the line between the centers of from
+// and to leaves from's rectangle, pushed out by the arrow margin.
+func excalidrawBorderPoint(from, to excalidrawElement) (float64, float64) {
+	centerX, centerY := from.X+from.Width/2, from.Y+from.Height/2
+	deltaX, deltaY := to.X+to.Width/2-centerX, to.Y+to.Height/2-centerY
+	scale := math.Inf(1)
+	if deltaX != 0 {
+		scale = min(scale, (from.Width/2+excalidrawArrowMargin)/math.Abs(deltaX))
+	}
+	if deltaY != 0 {
+		scale = min(scale, (from.Height/2+excalidrawArrowMargin)/math.Abs(deltaY))
+	}
+	if math.IsInf(scale, 1) {
+		return centerX, centerY
+	}
+	return centerX + scale*deltaX, centerY + scale*deltaY
+}
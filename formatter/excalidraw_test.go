@@ -0,0 +1,105 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+func TestExcalidrawFormatter_Encode(t *testing.T) {
+	g := newTestGraph(t)
+	if err := g.AddEdge(&graph.Edge{From: "example.com/mod/api", To: "example.com/mod/cmd", Kind: graph.EdgeTestImport, IsTestOnly: true}); err != nil {
+		t.Fatalf("AddEdge() error = %v", err)
+	}
+
+	var output bytes.Buffer
+	if err := (&ExcalidrawFormatter{}).Encode(&output, g); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	var scene struct {
+		Type     string `json:"type"`
+		Elements []struct {
+			ID            string            `json:"id"`
+			Type          string            `json:"type"`
+			X             float64           `json:"x"`
+			Y             float64           `json:"y"`
+			Width         float64           `json:"width"`
+			Height        float64           `json:"height"`
+			Text          string            `json:"text"`
+			ContainerID   string            `json:"containerId"`
+			StrokeStyle   string            `json:"strokeStyle"`
+			CustomData    map[string]string `json:"customData"`
+			BoundElements []struct {
+				ID string `json:"id"`
+			} `json:"boundElements"`
+			StartBinding *struct {
+				ElementID string `json:"elementId"`
+			} `json:"startBinding"`
+			EndBinding *struct {
+				ElementID string `json:"elementId"`
+			} `json:"endBinding"`
+		} `json:"elements"`
+	}
+	if err := json.Unmarshal(output.Bytes(), &scene); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, output.String())
+	}
+	if scene.Type != "excalidraw" {
+		t.Errorf("type = %q, want excalidraw", scene.Type)
+	}
+
+	byID := make(map[string]int)
+	counts := make(map[string]int)
+	for i, element := range scene.Elements {
+		if _, duplicate := byID[element.ID]; duplicate || element.ID == "" {
+			t.Errorf("element %d has a missing or duplicate id %q", i, element.ID)
+		}
+		byID[element.ID] = i
+		counts[element.Type]++
+		if element.Type != "arrow" && (element.Width <= 0 || element.Height <= 0) {
+			t.Errorf("%s %s has no size", element.Type, element.ID)
+		}
+	}
+	if counts["rectangle"] != 3 || counts["text"] != 3 || counts["arrow"] != 4 {
+		t.Fatalf("element counts = %v, want 3 rectangles, 3 texts and 4 arrows", counts)
+	}
+
+	// Nodes are sorted by ID, so api comes first, on the first grid cell.
+	api := scene.Elements[byID["node-0"]]
+	if api.CustomData["codegraph_id"] != "example.com/mod/api" || api.X != 0 || api.Y != 0 {
+		t.Errorf("first box = %+v, want api at the origin", api)
+	}
+	if label := scene.Elements[byID["label-0"]]; label.ContainerID != "node-0" || label.Text != "api" {
+		t.Errorf("first label = %+v, want the text api inside node-0", label)
+	}
+	cmd := scene.Elements[byID["node-1"]]
+	if cmd.Y != api.Y || cmd.X <= api.X+api.Width {
+		t.Errorf("second box at (%v, %v) should sit right of the first on the same row", cmd.X, cmd.Y)
+	}
+
+	dashed := 0
+	for _, element := range scene.Elements {
+		if element.Type != "arrow" {
+			continue
+		}
+		if element.StartBinding == nil || element.EndBinding == nil {
+			t.Fatalf("arrow %s is not bound to its nodes", element.ID)
+		}
+		start := scene.Elements[byID[element.StartBinding.ElementID]]
+		bound := false
+		for _, binding := range start.BoundElements {
+			bound = bound || binding.ID == element.ID
+		}
+		if !bound {
+			t.Errorf("arrow %s is missing from %s boundElements", element.ID, start.ID)
+		}
+		if element.StrokeStyle == "dashed" {
+			dashed++
+		}
+	}
+	if dashed != 1 {
+		t.Errorf("got %d dashed arrows, want the one test-only edge", dashed)
+	}
+}
@@ -0,0 +1,70 @@
+// Package formatter provides the built-in graph serialization formats.
+// Formats register themselves with the graph format registry on import.
+package formatter
+
+import (
+	"github.com/Desgue/codegraph/graph"
+)
+
+func init() {
+	mustRegister(graph.Format{
+		Name:       "graphml",
+		Extensions: []string{".graphml"},
+		NewEncoder: func() graph.Encoder { return &GraphMLFormatter{SortNodes: true} },
+		NewDecoder: func() graph.Decoder { return &GraphMLFormatter{} },
+	})
+	mustRegister(graph.Format{
+		Name:       "dot",
+		Extensions: []string{".dot", ".gv"},
+		NewEncoder: func() graph.Encoder { return &DOTFormatter{SortNodes: true} },
+	})
+	mustRegister(graph.Format{
+		Name:       "json",
+		Extensions: []string{".json"},
+		NewEncoder: func() graph.Encoder { return &JSONFormatter{Indent: DefaultJSONIndent} },
+		NewDecoder: func() graph.Decoder { return &JSONFormatter{} },
+	})
+	mustRegister(graph.Format{
+		Name:       "ndjson",
+		Extensions: []string{".ndjson", ".jsonl"},
+		NewEncoder: func() graph.Encoder { return &NDJSONFormatter{} },
+	})
+	mustRegister(graph.Format{
+		Name:       "markdown",
+		Extensions: []string{".md", ".markdown"},
+		NewEncoder: func() graph.Encoder { return &MarkdownFormatter{} },
+	})
+	mustRegister(graph.Format{
+		Name:       "gexf",
+		Extensions: []string{".gexf"},
+		NewEncoder: func() graph.Encoder { return &GEXFFormatter{} },
+		NewDecoder: func() graph.Decoder { return &GEXFFormatter{} },
+	})
+	mustRegister(graph.Format{
+		Name:       "tgf",
+		Extensions: []string{".tgf"},
+		NewEncoder: func() graph.Encoder { return &TGFFormatter{} },
+		NewDecoder: func() graph.Decoder { return &TGFFormatter{} },
+	})
+	mustRegister(graph.Format{
+		Name:       "cytoscape",
+		NewEncoder: func() graph.Encoder { return &CytoscapeFormatter{} },
+	})
+	mustRegister(graph.Format{
+		Name:       "d2",
+		Extensions: []string{".d2"},
+		NewEncoder: func() graph.Encoder { return &D2Formatter{} },
+	})
+	mustRegister(graph.Format{
+		Name:       "excalidraw",
+		Extensions: []string{".excalidraw"},
+		NewEncoder: func() graph.Encoder { return &ExcalidrawFormatter{} },
+	})
+}
+
+// mustRegister panics on registration conflicts, which are programming errors.
+func mustRegister(format graph.Format) {
+	if err := graph.RegisterFormat(format); err != nil {
+		panic(err)
+	}
+}
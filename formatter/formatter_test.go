@@ -0,0 +1,77 @@
+package formatter
+
+import (
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+// newTestGraph returns a small module graph: cmd imports api and store, api imports store.
+func newTestGraph(t *testing.T) *graph.Graph {
+	t.Helper()
+
+	g := graph.New()
+	nodes := []*graph.Node{
+		{ID: "example.com/mod/api", Kind: graph.KindPackage, Name: "api", ModulePath: "example.com/mod",
+			Files: []string{"/src/api/api.go"}, DirPath: "/src/api", Rank: 1, TestDependencies: []string{"example.com/mod/cmd", "net/http/httptest"},
+			TestFramework: "testify", Testable: true},
+		{ID: "example.com/mod/cmd", Kind: graph.KindPackage, Name: "main", ModulePath: "example.com/mod",
+			Files: []string{"/src/cmd/main.go"}, Rank: 2, HasMainFunc: true, UnsafeUsage: true},
+		{ID: "example.com/mod/store", Kind: graph.KindPackage, Name: "store", ModulePath: "example.com/mod", ModuleVersion: "v1.4.0", GoVersion: "1.21",
+			Files: []string{"/src/store/store.go", "/src/store/cache.go"}, InterfaceCount: 1, ConcreteTypeCount: 3, GoCGO: true, EmbedCount: 2, ChangeFrequency: 7,
+			ExportedFuncCount: 4, ExportedTypeCount: 2, APIBreaking: true, ProtobufGenerated: true,
+			BuildConstraints: []string{"linux && cgo"},
+			Attributes:       map[string]string{"fan_in": "2", "loc": "120"}},
+	}
+	for _, node := range nodes {
+		if err := g.AddNode(node); err != nil {
+			t.Fatalf("AddNode() error = %v", err)
+		}
+	}
+
+	edges := []*graph.Edge{
+		{From: "example.com/mod/api", To: "example.com/mod/store", Kind: graph.EdgeImport, Multiplicity: 3},
+		{From: "example.com/mod/cmd", To: "example.com/mod/api", Kind: graph.EdgeImport},
+		{From: "example.com/mod/cmd", To: "example.com/mod/store", Kind: graph.EdgeImport},
+	}
+	for _, edge := range edges {
+		if err := g.AddEdge(edge); err != nil {
+			t.Fatalf("AddEdge() error = %v", err)
+		}
+	}
+
+	return g
+}
+
+func TestBuiltinFormatsRegistered(t *testing.T) {
+	tests := []struct {
+		name       string
+		extension  string
+		wantDecode bool
+	}{
+		{name: "graphml", extension: "out.graphml", wantDecode: true},
+		{name: "dot", extension: "out.dot", wantDecode: false},
+		{name: "json", extension: "out.json", wantDecode: true},
+		{name: "ndjson", extension: "out.ndjson", wantDecode: false},
+		{name: "gexf", extension: "out.gexf", wantDecode: true},
+		{name: "tgf", extension: "out.tgf", wantDecode: true},
+		{name: "d2", extension: "out.d2", wantDecode: false},
+		{name: "excalidraw", extension: "out.excalidraw", wantDecode: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, found := graph.LookupFormat(tt.name)
+			if !found {
+				t.Fatalf("format %q not registered", tt.name)
+			}
+			if format.CanDecode() != tt.wantDecode {
+				t.Errorf("CanDecode() = %v, want %v", format.CanDecode(), tt.wantDecode)
+			}
+			byFile, found := graph.FormatForFile(tt.extension)
+			if !found || byFile.Name != tt.name {
+				t.Errorf("FormatForFile(%q) = %q, want %q", tt.extension, byFile.Name, tt.name)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/xml"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+func TestGEXFFormatter_Encode(t *testing.T) {
+	var output bytes.Buffer
+
+	if err := (&GEXFFormatter{}).Encode(&output, newTestGraph(t)); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	var document gexfDocument
+	if err := xml.Unmarshal(output.Bytes(), &document); err != nil {
+		t.Fatalf("output is not valid XML: %v\n%s", err, output.String())
+	}
+	if document.Version != "1.3" || document.Graph.DefaultEdgeType != "directed" {
+		t.Errorf("version, defaultedgetype = %q, %q, want 1.3, directed", document.Version, document.Graph.DefaultEdgeType)
+	}
+	if len(document.Graph.Nodes) != 3 || document.Graph.Nodes[2].ID != "example.com/mod/store" {
+		t.Fatalf("unexpected nodes %+v", document.Graph.Nodes)
+	}
+	if got := document.Graph.Edges[0].ID; got != "example.com/mod/api|example.com/mod/store" {
+		t.Errorf("edge id = %q, want from|to", got)
+	}
+	if !strings.Contains(output.String(), `<attribute id="attr_fan_in" title="fan_in" type="integer"></attribute>`) {
+		t.Errorf("expected integer attribute declaration for fan_in, got:\n%s", output.String())
+	}
+}
+
+func TestGEXFFormatter_RoundTrip(t *testing.T) {
+	original := newTestGraph(t)
+	original.Title = "round trip"
+	original.Edges()[0].SetAttribute("weight", "2")
+	testEdge := &graph.Edge{From: "example.com/mod/cmd", To: "example.com/mod/api", Kind: graph.EdgeTestImport, IsTestOnly: true}
+	if err := original.AddEdge(testEdge); err != nil {
+		t.Fatalf("AddEdge() error = %v", err)
+	}
+
+	var output bytes.Buffer
+	if err := (&GEXFFormatter{}).Encode(&output, original); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if !strings.Contains(output.String(), `id="example.com/mod/cmd|example.com/mod/api|test_import"`) {
+		t.Errorf("expected the kind to disambiguate edges sharing endpoints, got:\n%s", output.String())
+	}
+
+	decoded, err := (&GEXFFormatter{}).Decode(&output)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	if decoded.Title != original.Title {
+		t.Errorf("Title = %q, want %q", decoded.Title, original.Title)
+	}
+	if len(decoded.Nodes()) != len(original.Nodes()) {
+		t.Fatalf("decoded %d nodes, want %d", len(decoded.Nodes()), len(original.Nodes()))
+	}
+	for i, node := range original.Nodes() {
+		got := decoded.Nodes()[i]
+		if got.ID != node.ID || got.Kind != node.Kind || got.Name != node.Name || got.ModulePath != node.ModulePath ||
+			got.ModuleVersion != node.ModuleVersion || got.GoVersion != node.GoVersion || got.TestFramework != node.TestFramework ||
+			got.InterfaceCount != node.InterfaceCount || got.ConcreteTypeCount != node.ConcreteTypeCount ||
+			got.HasMainFunc != node.HasMainFunc || got.GoCGO != node.GoCGO || got.EmbedCount != node.EmbedCount || got.ChangeFrequency != node.ChangeFrequency || got.ExportedFuncCount != node.ExportedFuncCount || got.ExportedTypeCount != node.ExportedTypeCount || got.APIBreaking != node.APIBreaking || got.Testable != node.Testable || got.UnsafeUsage != node.UnsafeUsage || got.ProtobufGenerated != node.ProtobufGenerated ||
+			!slices.Equal(got.TestDependencies, node.TestDependencies) || !slices.Equal(got.BuildConstraints, node.BuildConstraints) ||
+			!maps.Equal(got.Attributes, node.Attributes) {
+			t.Errorf("node %d = %+v, want %+v", i, got, node)
+		}
+	}
+	if len(decoded.Edges()) != len(original.Edges()) {
+		t.Fatalf("decoded %d edges, want %d", len(decoded.Edges()), len(original.Edges()))
+	}
+	for i, edge := range original.Edges() {
+		got := decoded.Edges()[i]
+		if got.Key() != edge.Key() || got.IsTestOnly != edge.IsTestOnly || got.Multiplicity != edge.Multiplicity || !maps.Equal(got.Attributes, edge.Attributes) {
+			t.Errorf("edge %d = %+v, want %+v", i, got, edge)
+		}
+	}
+}
+
+func TestGEXFFormatter_DecodeInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "malformed XML", input: "<gexf><graph>"},
+		{name: "edge to unknown node", input: `<gexf><graph><nodes><node id="a"/></nodes><edges><edge id="a|b" source="a" target="b"/></edges></graph></gexf>`},
+		{name: "invalid integer attribute", input: `<gexf><graph>
+<attributes class="node"><attribute id="ic" title="codegraph:interfaceCount" type="integer"/></attributes>
+<nodes><node id="a"><attvalues><attvalue for="ic" value="many"/></attvalues></node></nodes></graph></gexf>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := (&GEXFFormatter{}).Decode(strings.NewReader(tt.input)); err == nil {
+				t.Error("expected decode error")
+			}
+		})
+	}
+}
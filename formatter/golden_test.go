@@ -0,0 +1,138 @@
+package formatter
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+	"github.com/Desgue/codegraph/internal/testutil/repogen"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// newGoldenGraph extends newTestGraph with graph metadata, edge attributes,
+// a test-only edge and values that need escaping.
+func newGoldenGraph(t *testing.T) *graph.Graph {
+	t.Helper()
+
+	g := newTestGraph(t)
+	g.Title = `example.com/mod <"main">`
+	g.Provenance = []string{"left graph", "right & merged"}
+	g.SetMetadata("generated_by", "codegraph")
+	g.SetMetadata("note", "line one\nline\ttwo")
+	tricky := &graph.Node{ID: "example.com/mod/internal/q&a", Kind: graph.KindPackage, Name: "q'a",
+		Attributes: map[string]string{"html": "<b>bold</b> & 'quoted'", "invalid": "bad\x00byte\xff", "ratio": "0.5", "unicode": "héllo ☃"}}
+	if err := g.AddNode(tricky); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.AddNode(&graph.Node{ID: "bare"}); err != nil {
+		t.Fatal(err)
+	}
+	edges := []*graph.Edge{
+		{From: tricky.ID, To: "example.com/mod/store", Kind: graph.EdgeImport, IsTestOnly: true},
+		{From: "example.com/mod/cmd", To: tricky.ID, Kind: graph.EdgeImport, Attributes: map[string]string{"alias": "q\"a", "weight": "3"}},
+	}
+	for _, edge := range edges {
+		if err := g.AddEdge(edge); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return g
+}
+
+func TestEncoders_Golden(t *testing.T) {
+	tests := []struct {
+		file    string
+		encoder graph.Encoder
+		graph   func(t *testing.T) *graph.Graph
+	}{
+		{file: "golden.graphml", encoder: &GraphMLFormatter{}, graph: newGoldenGraph},
+		{file: "golden_sorted.graphml", encoder: &GraphMLFormatter{SortNodes: true}, graph: newGoldenGraph},
+		{file: "golden_empty.graphml", encoder: &GraphMLFormatter{}, graph: func(*testing.T) *graph.Graph { return graph.New() }},
+		{file: "golden.json", encoder: &JSONFormatter{}, graph: newGoldenGraph},
+		{file: "golden_indent.json", encoder: &JSONFormatter{Indent: DefaultJSONIndent}, graph: newGoldenGraph},
+		{file: "golden_empty_indent.json", encoder: &JSONFormatter{Indent: "\t"}, graph: func(*testing.T) *graph.Graph { return graph.New() }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			var output bytes.Buffer
+			if err := tt.encoder.Encode(&output, tt.graph(t)); err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			path := filepath.Join("testdata", tt.file)
+			if *updateGolden {
+				if err := os.WriteFile(path, output.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(output.Bytes(), want) {
+				t.Errorf("output differs from %s:\n%s", path, output.String())
+			}
+		})
+	}
+}
+
+// newBenchmarkGraph returns the import graph of the 2000-package module the
+// extract benchmarks generate, with a few metric attributes per node.
+func newBenchmarkGraph() *graph.Graph {
+	g := repogen.Plan(repogen.Config{Packages: 2000, Topology: repogen.RandomDAG, Density: 6.0 / 1999}).Graph()
+	for i, node := range g.Nodes() {
+		node.Files = []string{node.ID + "/p.go"}
+		node.SetAttribute("fan_in", strconv.Itoa(i%7))
+		node.SetAttribute("loc", strconv.Itoa(100+i))
+		node.SetAttribute("instability", "0.5")
+	}
+	return g
+}
+
+func benchmarkEncoder(b *testing.B, g *graph.Graph, encoder graph.Encoder) {
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := encoder.Encode(io.Discard, g); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkEncode encodes the benchmark graph with each built-in encoder but
+// DOT, which BenchmarkEncodeDOT covers with and without clusters.
+func BenchmarkEncode(b *testing.B) {
+	g := newBenchmarkGraph()
+	encoders := []struct {
+		name    string
+		encoder graph.Encoder
+	}{
+		{name: "graphml", encoder: &GraphMLFormatter{SortNodes: true}},
+		{name: "json", encoder: &JSONFormatter{}},
+		{name: "json-indent", encoder: &JSONFormatter{Indent: DefaultJSONIndent}},
+		{name: "d2", encoder: &D2Formatter{}},
+		{name: "tgf", encoder: &TGFFormatter{}},
+		{name: "markdown", encoder: &MarkdownFormatter{}},
+	}
+	for _, encoder := range encoders {
+		b.Run(encoder.name, func(b *testing.B) { benchmarkEncoder(b, g, encoder.encoder) })
+	}
+}
+
+func BenchmarkEncodeDOT(b *testing.B) {
+	g := newBenchmarkGraph()
+	encoders := []struct {
+		name    string
+		encoder graph.Encoder
+	}{
+		{name: "plain", encoder: &DOTFormatter{SortNodes: true}},
+		{name: "clusters", encoder: &DOTFormatter{SortNodes: true, ShowClusterStats: true, RankSame: true}},
+	}
+	for _, encoder := range encoders {
+		b.Run(encoder.name, func(b *testing.B) { benchmarkEncoder(b, g, encoder.encoder) })
+	}
+}
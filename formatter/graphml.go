@@ -0,0 +1,590 @@
+package formatter
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+const graphMLNamespace = "http://graphml.graphdrawing.org/xmlns"
+
+// GraphMLFormatter writes graphs as GraphML documents.
+// Node and edge fields are emitted as <data> elements whose keys are
+// declared with a "codegraph:" attribute name prefix. Node attributes are
+// emitted under keys whose attribute name is the attribute's own name.
+type GraphMLFormatter struct {
+	// SortNodes writes nodes sorted by ID and edges sorted by source and
+	// target instead of in insertion order, so the same graph always encodes
+	// to the same bytes and graph files diff cleanly. The registered encoder
+	// sets it.
+	SortNodes bool
+}
+
+type graphMLDocument struct {
+	XMLName   xml.Name     `xml:"graphml"`
+	Namespace string       `xml:"xmlns,attr"`
+	Keys      []graphMLKey `xml:"key"`
+	Graph     graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	Name        string        `xml:"name,attr,omitempty"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Data        []graphMLData `xml:"data"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// graphMLNodeAttribute declares a node key, how its value is read from a node,
+// and how a decoded value is written back. decode is nil for derived values.
+type graphMLNodeAttribute struct {
+	key    graphMLKey
+	value  func(node *graph.Node) string
+	decode func(node *graph.Node, value string) error
+}
+
+var graphMLNodeAttributes = []graphMLNodeAttribute{
+	{
+		key:   graphMLKey{ID: "kind", For: "node", AttrName: "codegraph:kind", AttrType: "string"},
+		value: func(node *graph.Node) string { return string(node.Kind) },
+		decode: func(node *graph.Node, value string) error {
+			node.Kind = graph.Kind(value)
+			return nil
+		},
+	},
+	{
+		key:    graphMLKey{ID: "name", For: "node", AttrName: "codegraph:name", AttrType: "string"},
+		value:  func(node *graph.Node) string { return node.Name },
+		decode: decodeString(func(node *graph.Node) *string { return &node.Name }),
+	},
+	{
+		key:    graphMLKey{ID: "module", For: "node", AttrName: "codegraph:module", AttrType: "string"},
+		value:  func(node *graph.Node) string { return node.ModulePath },
+		decode: decodeString(func(node *graph.Node) *string { return &node.ModulePath }),
+	},
+	{
+		key:    graphMLKey{ID: "moduleVersion", For: "node", AttrName: "codegraph:moduleVersion", AttrType: "string"},
+		value:  func(node *graph.Node) string { return node.ModuleVersion },
+		decode: decodeString(func(node *graph.Node) *string { return &node.ModuleVersion }),
+	},
+	{
+		key:    graphMLKey{ID: "goVersion", For: "node", AttrName: "codegraph:goVersion", AttrType: "string"},
+		value:  func(node *graph.Node) string { return node.GoVersion },
+		decode: decodeString(func(node *graph.Node) *string { return &node.GoVersion }),
+	},
+	{
+		key:   graphMLKey{ID: "fileCount", For: "node", AttrName: "codegraph:fileCount", AttrType: "int"},
+		value: func(node *graph.Node) string { return strconv.Itoa(len(node.Files)) },
+	},
+	{
+		key:    graphMLKey{ID: "interfaceCount", For: "node", AttrName: "codegraph:interfaceCount", AttrType: "int"},
+		value:  func(node *graph.Node) string { return strconv.Itoa(node.InterfaceCount) },
+		decode: decodeInt(func(node *graph.Node) *int { return &node.InterfaceCount }),
+	},
+	{
+		key:    graphMLKey{ID: "concreteTypeCount", For: "node", AttrName: "codegraph:concreteTypeCount", AttrType: "int"},
+		value:  func(node *graph.Node) string { return strconv.Itoa(node.ConcreteTypeCount) },
+		decode: decodeInt(func(node *graph.Node) *int { return &node.ConcreteTypeCount }),
+	},
+	{
+		key:    graphMLKey{ID: "exportedFuncCount", For: "node", AttrName: "codegraph:exportedFuncCount", AttrType: "int"},
+		value:  func(node *graph.Node) string { return strconv.Itoa(node.ExportedFuncCount) },
+		decode: decodeInt(func(node *graph.Node) *int { return &node.ExportedFuncCount }),
+	},
+	{
+		key:    graphMLKey{ID: "exportedTypeCount", For: "node", AttrName: "codegraph:exportedTypeCount", AttrType: "int"},
+		value:  func(node *graph.Node) string { return strconv.Itoa(node.ExportedTypeCount) },
+		decode: decodeInt(func(node *graph.Node) *int { return &node.ExportedTypeCount }),
+	},
+	{
+		key:    graphMLKey{ID: "apiBreaking", For: "node", AttrName: "codegraph:apiBreaking", AttrType: "boolean"},
+		value:  func(node *graph.Node) string { return strconv.FormatBool(node.APIBreaking) },
+		decode: decodeBool(func(node *graph.Node) *bool { return &node.APIBreaking }),
+	},
+	{
+		key:    graphMLKey{ID: "hasMainFunc", For: "node", AttrName: "codegraph:hasMainFunc", AttrType: "boolean"},
+		value:  func(node *graph.Node) string { return strconv.FormatBool(node.HasMainFunc) },
+		decode: decodeBool(func(node *graph.Node) *bool { return &node.HasMainFunc }),
+	},
+	{
+		key:    graphMLKey{ID: "testable", For: "node", AttrName: "codegraph:testable", AttrType: "boolean"},
+		value:  func(node *graph.Node) string { return strconv.FormatBool(node.Testable) },
+		decode: decodeBool(func(node *graph.Node) *bool { return &node.Testable }),
+	},
+	{
+		key:    graphMLKey{ID: "unsafeUsage", For: "node", AttrName: "codegraph:unsafeUsage", AttrType: "boolean"},
+		value:  func(node *graph.Node) string { return strconv.FormatBool(node.UnsafeUsage) },
+		decode: decodeBool(func(node *graph.Node) *bool { return &node.UnsafeUsage }),
+	},
+	{
+		key:    graphMLKey{ID: "protobufGenerated", For: "node", AttrName: "codegraph:protobufGenerated", AttrType: "boolean"},
+		value:  func(node *graph.Node) string { return strconv.FormatBool(node.ProtobufGenerated) },
+		decode: decodeBool(func(node *graph.Node) *bool { return &node.ProtobufGenerated }),
+	},
+	{
+		key:    graphMLKey{ID: "requiresCGO", For: "node", AttrName: "codegraph:requiresCGO", AttrType: "boolean"},
+		value:  func(node *graph.Node) string { return strconv.FormatBool(node.GoCGO) },
+		decode: decodeBool(func(node *graph.Node) *bool { return &node.GoCGO }),
+	},
+	{
+		key:    graphMLKey{ID: "embedCount", For: "node", AttrName: "codegraph:embedCount", AttrType: "int"},
+		value:  func(node *graph.Node) string { return strconv.Itoa(node.EmbedCount) },
+		decode: decodeInt(func(node *graph.Node) *int { return &node.EmbedCount }),
+	},
+	{
+		key:    graphMLKey{ID: "changeFrequency", For: "node", AttrName: "codegraph:changeFrequency", AttrType: "int"},
+		value:  func(node *graph.Node) string { return strconv.Itoa(node.ChangeFrequency) },
+		decode: decodeInt(func(node *graph.Node) *int { return &node.ChangeFrequency }),
+	},
+	{
+		key:   graphMLKey{ID: "testDependencies", For: "node", AttrName: "codegraph:testDependencies", AttrType: "string"},
+		value: func(node *graph.Node) string { return strings.Join(node.TestDependencies, ",") },
+		decode: func(node *graph.Node, value string) error {
+			if value != "" {
+				node.TestDependencies = strings.Split(value, ",")
+			}
+			return nil
+		},
+	},
+	{
+		key:    graphMLKey{ID: "dirPath", For: "node", AttrName: "codegraph:dirPath", AttrType: "string"},
+		value:  func(node *graph.Node) string { return node.DirPath },
+		decode: decodeString(func(node *graph.Node) *string { return &node.DirPath }),
+	},
+	{
+		key:    graphMLKey{ID: "rank", For: "node", AttrName: "codegraph:rank", AttrType: "int"},
+		value:  func(node *graph.Node) string { return strconv.Itoa(node.Rank) },
+		decode: decodeInt(func(node *graph.Node) *int { return &node.Rank }),
+	},
+	{
+		key:    graphMLKey{ID: "testFramework", For: "node", AttrName: "codegraph:testFramework", AttrType: "string"},
+		value:  func(node *graph.Node) string { return node.TestFramework },
+		decode: decodeString(func(node *graph.Node) *string { return &node.TestFramework }),
+	},
+	{
+		key:   graphMLKey{ID: "buildConstraints", For: "node", AttrName: "codegraph:buildConstraints", AttrType: "string"},
+		value: func(node *graph.Node) string { return strings.Join(node.BuildConstraints, ",") },
+		decode: func(node *graph.Node, value string) error {
+			if value != "" {
+				node.BuildConstraints = strings.Split(value, ",")
+			}
+			return nil
+		},
+	},
+}
+
+// decodeString returns a decoder that stores the value in the field selected by field.
+func decodeString(field func(node *graph.Node) *string) func(node *graph.Node, value string) error {
+	return func(node *graph.Node, value string) error {
+		*field(node) = value
+		return nil
+	}
+}
+
+// decodeInt returns a decoder that parses an int value into the field selected by field.
+func decodeInt(field func(node *graph.Node) *int) func(node *graph.Node, value string) error {
+	return func(node *graph.Node, value string) error {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("node %q: invalid integer %q", node.ID, value)
+		}
+		*field(node) = parsed
+		return nil
+	}
+}
+
+// decodeBool returns a decoder that parses a boolean value into the field selected by field.
+func decodeBool(field func(node *graph.Node) *bool) func(node *graph.Node, value string) error {
+	return func(node *graph.Node, value string) error {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("node %q: invalid boolean %q", node.ID, value)
+		}
+		*field(node) = parsed
+		return nil
+	}
+}
+
+var graphMLEdgeKindKey = graphMLKey{ID: "edgeKind", For: "edge", AttrName: "codegraph:kind", AttrType: "string"}
+
+// graphMLEdgeTestOnlyKey is written only on edges with IsTestOnly set.
+var graphMLEdgeTestOnlyKey = graphMLKey{ID: "testOnly", For: "edge", AttrName: "codegraph:testOnly", AttrType: "boolean"}
+
+// graphMLEdgeMultiplicityKey is written only on edges with a known Multiplicity.
+var graphMLEdgeMultiplicityKey = graphMLKey{ID: "multiplicity", For: "edge", AttrName: "codegraph:multiplicity", AttrType: "int"}
+
+var graphMLSchemaVersionKey = graphMLKey{ID: "schemaVersion", For: "graph", AttrName: "codegraph:schemaVersion", AttrType: "string"}
+
+// graphMLProvenanceKey stores Graph.Provenance as a newline-separated list,
+// since merged graph titles commonly contain spaces and commas.
+var graphMLProvenanceKey = graphMLKey{ID: "provenance", For: "graph", AttrName: "codegraph:provenance", AttrType: "string"}
+
+// The attribute key ID prefixes keep graph metadata, node and edge attribute
+// key IDs apart from each other and from the built-in key IDs.
+const (
+	graphMLMetadataKeyPrefix      = "meta_"
+	graphMLAttributeKeyPrefix     = "attr_"
+	graphMLEdgeAttributeKeyPrefix = "edge_attr_"
+)
+
+// Encode streams the document through a buffered writer instead of building
+// an encoding/xml tree, so memory does not grow with the graph. The output is
+// what xml.Encoder with two-space indentation writes for graphMLDocument,
+// which Decode reads.
+func (f *GraphMLFormatter) Encode(writer io.Writer, g *graph.Graph) error {
+	nodes, edges := orderedElements(g, f.SortNodes)
+	w := graphMLWriter{bufio.NewWriter(writer)}
+
+	w.WriteString(xml.Header)
+	w.WriteString(`<graphml xmlns="` + graphMLNamespace + `">`)
+	for _, key := range graphMLKeys(g) {
+		w.WriteString("\n  <key id=\"")
+		w.escape(key.ID)
+		w.WriteString(`" for="`)
+		w.escape(key.For)
+		w.WriteString(`" attr.name="`)
+		w.escape(key.AttrName)
+		w.WriteString(`" attr.type="`)
+		w.escape(key.AttrType)
+		w.WriteString(`"></key>`)
+	}
+
+	w.WriteString("\n  <graph id=\"G\"")
+	if g.Title != "" {
+		w.WriteString(` name="`)
+		w.escape(g.Title)
+		w.WriteByte('"')
+	}
+	w.WriteString(` edgedefault="directed">`)
+	w.data("\n    ", graphMLGraphData(g))
+
+	// One data slice is reused across elements.
+	var data []graphMLData
+	for _, node := range nodes {
+		w.WriteString("\n    <node id=\"")
+		w.escape(node.ID)
+		w.WriteString(`">`)
+		data = appendGraphMLNodeData(data[:0], node)
+		w.data("\n      ", data)
+		w.end("node", len(data) > 0)
+	}
+	for _, edge := range edges {
+		w.WriteString("\n    <edge source=\"")
+		w.escape(edge.From)
+		w.WriteString(`" target="`)
+		w.escape(edge.To)
+		w.WriteString(`">`)
+		data = appendGraphMLEdgeData(data[:0], edge)
+		w.data("\n      ", data)
+		w.end("edge", len(data) > 0)
+	}
+	w.WriteString("\n  </graph>\n</graphml>\n")
+	return w.Flush()
+}
+
+// graphMLWriter writes GraphML markup. Write errors stick to the
+// bufio.Writer and are returned by Flush.
+type graphMLWriter struct {
+	*bufio.Writer
+}
+
+// data writes one data element per entry, each on its own line.
+func (w graphMLWriter) data(indent string, data []graphMLData) {
+	for _, entry := range data {
+		w.WriteString(indent)
+		w.WriteString(`<data key="`)
+		w.escape(entry.Key)
+		w.WriteString(`">`)
+		w.escape(entry.Value)
+		w.WriteString("</data>")
+	}
+}
+
+// end closes a node or edge element on its own line after data elements,
+// and on the opening line otherwise, as xml.Encoder indents.
+func (w graphMLWriter) end(name string, hasData bool) {
+	if hasData {
+		w.WriteString("\n    ")
+	}
+	w.WriteString("</" + name + ">")
+}
+
+// escape writes s escaped like xml.EscapeText, which xml.Encoder uses for
+// attribute values and character data alike. IDs and values rarely need
+// escaping, so those are written without copying them into a byte slice.
+func (w graphMLWriter) escape(s string) {
+	for i := range len(s) {
+		if c := s[i]; c < 0x20 || c >= utf8.RuneSelf || c == '"' || c == '\'' || c == '&' || c == '<' || c == '>' {
+			w.WriteString(s[:i])
+			_ = xml.EscapeText(w, []byte(s[i:]))
+			return
+		}
+	}
+	w.WriteString(s)
+}
+
+func graphMLKeys(g *graph.Graph) []graphMLKey {
+	keys := make([]graphMLKey, 0, len(graphMLNodeAttributes)+3)
+	keys = append(keys, graphMLSchemaVersionKey)
+	if len(g.Provenance) > 0 {
+		keys = append(keys, graphMLProvenanceKey)
+	}
+	for _, name := range sortedAttributeNames(g.Metadata) {
+		keys = append(keys, graphMLKey{ID: graphMLMetadataKeyPrefix + name, For: "graph", AttrName: name, AttrType: "string"})
+	}
+	for _, attribute := range graphMLNodeAttributes {
+		keys = append(keys, attribute.key)
+	}
+	nodeAttributes := make([]map[string]string, 0, len(g.Nodes()))
+	for _, node := range g.Nodes() {
+		nodeAttributes = append(nodeAttributes, node.Attributes)
+	}
+	for _, name := range unionAttributeNames(nodeAttributes) {
+		keys = append(keys, graphMLKey{ID: graphMLAttributeKeyPrefix + name, For: "node", AttrName: name,
+			AttrType: graphMLAttributeType(nodeAttributes, name)})
+	}
+	keys = append(keys, graphMLEdgeKindKey, graphMLEdgeTestOnlyKey, graphMLEdgeMultiplicityKey)
+	edgeAttributes := make([]map[string]string, 0, len(g.Edges()))
+	for _, edge := range g.Edges() {
+		edgeAttributes = append(edgeAttributes, edge.Attributes)
+	}
+	for _, name := range unionAttributeNames(edgeAttributes) {
+		keys = append(keys, graphMLKey{ID: graphMLEdgeAttributeKeyPrefix + name, For: "edge", AttrName: name,
+			AttrType: graphMLAttributeType(edgeAttributes, name)})
+	}
+	return keys
+}
+
+// unionAttributeNames returns the sorted union of names across attribute maps.
+func unionAttributeNames(attributeMaps []map[string]string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, attributes := range attributeMaps {
+		for name := range attributes {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// graphMLAttributeType declares an attribute as "int" or "double" when every
+// non-empty value parses as one, so viewers can size and color by metrics
+// such as fan_in without converting them. Otherwise it is a "string".
+func graphMLAttributeType(attributeMaps []map[string]string, name string) string {
+	attributeType := "int"
+	for _, attributes := range attributeMaps {
+		value := attributes[name]
+		if value == "" {
+			continue
+		}
+		if _, err := strconv.Atoi(value); err == nil {
+			continue
+		}
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return "string"
+		}
+		attributeType = "double"
+	}
+	return attributeType
+}
+
+func graphMLGraphData(g *graph.Graph) []graphMLData {
+	data := []graphMLData{{Key: graphMLSchemaVersionKey.ID, Value: graph.SchemaVersion}}
+	if len(g.Provenance) > 0 {
+		data = append(data, graphMLData{Key: graphMLProvenanceKey.ID, Value: strings.Join(g.Provenance, "\n")})
+	}
+	for _, name := range sortedAttributeNames(g.Metadata) {
+		if value := g.Metadata[name]; value != "" {
+			data = append(data, graphMLData{Key: graphMLMetadataKeyPrefix + name, Value: value})
+		}
+	}
+	return data
+}
+
+func graphMLNodes(graphNodes []*graph.Node) []graphMLNode {
+	nodes := make([]graphMLNode, 0, len(graphNodes))
+	for _, node := range graphNodes {
+		nodes = append(nodes, graphMLNode{ID: node.ID, Data: appendGraphMLNodeData(make([]graphMLData, 0, len(graphMLNodeAttributes)), node)})
+	}
+	return nodes
+}
+
+// appendGraphMLNodeData appends the node's non-empty fields and then its
+// non-empty attributes, sorted by name, to data.
+func appendGraphMLNodeData(data []graphMLData, node *graph.Node) []graphMLData {
+	for _, attribute := range graphMLNodeAttributes {
+		if value := attribute.value(node); value != "" {
+			data = append(data, graphMLData{Key: attribute.key.ID, Value: value})
+		}
+	}
+	for _, name := range sortedAttributeNames(node.Attributes) {
+		if value := node.Attributes[name]; value != "" {
+			data = append(data, graphMLData{Key: graphMLAttributeKeyPrefix + name, Value: value})
+		}
+	}
+	return data
+}
+
+func sortedAttributeNames(attributes map[string]string) []string {
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func graphMLEdges(graphEdges []*graph.Edge) []graphMLEdge {
+	edges := make([]graphMLEdge, 0, len(graphEdges))
+	for _, edge := range graphEdges {
+		edges = append(edges, graphMLEdge{Source: edge.From, Target: edge.To, Data: appendGraphMLEdgeData(nil, edge)})
+	}
+	return edges
+}
+
+// appendGraphMLEdgeData appends the edge's kind, test-only flag, multiplicity
+// and non-empty attributes, sorted by name, to data.
+func appendGraphMLEdgeData(data []graphMLData, edge *graph.Edge) []graphMLData {
+	data = append(data, graphMLData{Key: graphMLEdgeKindKey.ID, Value: string(edge.Kind)})
+	if edge.IsTestOnly {
+		data = append(data, graphMLData{Key: graphMLEdgeTestOnlyKey.ID, Value: "true"})
+	}
+	if edge.Multiplicity > 0 {
+		data = append(data, graphMLData{Key: graphMLEdgeMultiplicityKey.ID, Value: strconv.Itoa(edge.Multiplicity)})
+	}
+	for _, name := range sortedAttributeNames(edge.Attributes) {
+		if value := edge.Attributes[name]; value != "" {
+			data = append(data, graphMLData{Key: graphMLEdgeAttributeKeyPrefix + name, Value: value})
+		}
+	}
+	return data
+}
+
+// Decode reads a GraphML document. Data values are matched to node fields by
+// their declared attr.name, so documents using different key IDs still decode.
+// Graph, node and edge keys that match no built-in field are restored as
+// metadata and attributes, and file lists are not restored because GraphML
+// only stores their count. The decoded graph is upgraded to the current
+// schema version.
+func (f *GraphMLFormatter) Decode(reader io.Reader) (*graph.Graph, error) {
+	var document graphMLDocument
+	if err := xml.NewDecoder(reader).Decode(&document); err != nil {
+		return nil, fmt.Errorf("failed to parse GraphML: %w", err)
+	}
+
+	attributeNames := make(map[string]string, len(document.Keys))
+	for _, key := range document.Keys {
+		attributeNames[key.ID] = key.AttrName
+	}
+
+	decoded := graph.New()
+	decoded.Title = document.Graph.Name
+	for _, data := range document.Graph.Data {
+		switch attributeName := attributeNames[data.Key]; attributeName {
+		case graphMLSchemaVersionKey.AttrName:
+			decoded.SchemaVersion = data.Value
+		case graphMLProvenanceKey.AttrName:
+			if data.Value != "" {
+				decoded.Provenance = strings.Split(data.Value, "\n")
+			}
+		case "":
+		default:
+			decoded.SetMetadata(attributeName, data.Value)
+		}
+	}
+
+	for _, documentNode := range document.Graph.Nodes {
+		node := &graph.Node{ID: documentNode.ID}
+		for _, data := range documentNode.Data {
+			if err := decodeGraphMLNodeData(node, attributeNames[data.Key], data.Value); err != nil {
+				return nil, err
+			}
+		}
+		if err := decoded.AddNode(node); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, documentEdge := range document.Graph.Edges {
+		edge := &graph.Edge{From: documentEdge.Source, To: documentEdge.Target}
+		for _, data := range documentEdge.Data {
+			switch attributeName := attributeNames[data.Key]; attributeName {
+			case graphMLEdgeKindKey.AttrName:
+				edge.Kind = graph.EdgeKind(data.Value)
+			case graphMLEdgeTestOnlyKey.AttrName:
+				testOnly, err := strconv.ParseBool(data.Value)
+				if err != nil {
+					return nil, fmt.Errorf("edge %q -> %q: invalid boolean %q", edge.From, edge.To, data.Value)
+				}
+				edge.IsTestOnly = testOnly
+			case graphMLEdgeMultiplicityKey.AttrName:
+				multiplicity, err := strconv.Atoi(data.Value)
+				if err != nil {
+					return nil, fmt.Errorf("edge %q -> %q: invalid integer %q", edge.From, edge.To, data.Value)
+				}
+				edge.Multiplicity = multiplicity
+			case "":
+			default:
+				edge.SetAttribute(attributeName, data.Value)
+			}
+		}
+		if err := decoded.AddEdge(edge); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := graph.CurrentSchema().Upgrade(decoded); err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
+
+func decodeGraphMLNodeData(node *graph.Node, attributeName, value string) error {
+	for _, attribute := range graphMLNodeAttributes {
+		if attribute.key.AttrName == attributeName {
+			if attribute.decode == nil {
+				return nil
+			}
+			return attribute.decode(node, value)
+		}
+	}
+	if attributeName != "" {
+		node.SetAttribute(attributeName, value)
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/xml"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+func TestGraphMLFormatter_Encode(t *testing.T) {
+	var output bytes.Buffer
+
+	if err := (&GraphMLFormatter{}).Encode(&output, newTestGraph(t)); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	var document graphMLDocument
+	if err := xml.Unmarshal(output.Bytes(), &document); err != nil {
+		t.Fatalf("output is not valid XML: %v\n%s", err, output.String())
+	}
+
+	if document.Graph.EdgeDefault != "directed" {
+		t.Errorf("edgedefault = %q, want directed", document.Graph.EdgeDefault)
+	}
+	if len(document.Graph.Nodes) != 3 {
+		t.Fatalf("expected 3 nodes, got %d", len(document.Graph.Nodes))
+	}
+	if len(document.Graph.Edges) != 3 {
+		t.Fatalf("expected 3 edges, got %d", len(document.Graph.Edges))
+	}
+
+	storeNode := document.Graph.Nodes[2]
+	if storeNode.ID != "example.com/mod/store" {
+		t.Fatalf("unexpected third node %q", storeNode.ID)
+	}
+	wantData := map[string]string{
+		"kind":              "package",
+		"name":              "store",
+		"module":            "example.com/mod",
+		"moduleVersion":     "v1.4.0",
+		"goVersion":         "1.21",
+		"fileCount":         "2",
+		"interfaceCount":    "1",
+		"concreteTypeCount": "3",
+		"embedCount":        "2",
+		"changeFrequency":   "7",
+		"exportedFuncCount": "4",
+		"exportedTypeCount": "2",
+		"apiBreaking":       "true",
+		"attr_fan_in":       "2",
+		"attr_loc":          "120",
+	}
+	gotData := make(map[string]string)
+	for _, data := range storeNode.Data {
+		gotData[data.Key] = data.Value
+	}
+	for key, want := range wantData {
+		if gotData[key] != want {
+			t.Errorf("data %q = %q, want %q", key, gotData[key], want)
+		}
+	}
+
+	declaredKeys := make(map[string]bool)
+	for _, key := range document.Keys {
+		declaredKeys[key.ID] = true
+	}
+	for key := range gotData {
+		if !declaredKeys[key] {
+			t.Errorf("data key %q is not declared", key)
+		}
+	}
+}
+
+func TestGraphMLFormatter_NumericAttributeKeys(t *testing.T) {
+	g := newTestGraph(t)
+	api, _ := g.Node("example.com/mod/api")
+	api.SetAttribute("instability", "0.67")
+	api.SetAttribute("owner", "team-a")
+	store, _ := g.Node("example.com/mod/store")
+	store.SetAttribute("instability", "0")
+	store.SetAttribute("owner", "42")
+
+	var output bytes.Buffer
+	if err := (&GraphMLFormatter{}).Encode(&output, g); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	var document graphMLDocument
+	if err := xml.Unmarshal(output.Bytes(), &document); err != nil {
+		t.Fatalf("output is not valid XML: %v", err)
+	}
+
+	wantTypes := map[string]string{"attr_fan_in": "int", "attr_instability": "double", "attr_owner": "string"}
+	for _, key := range document.Keys {
+		if want, found := wantTypes[key.ID]; found && key.AttrType != want {
+			t.Errorf("key %s attr.type = %q, want %q", key.ID, key.AttrType, want)
+		}
+	}
+}
+
+func TestGraphMLFormatter_GraphTitle(t *testing.T) {
+	var output bytes.Buffer
+
+	g := newTestGraph(t)
+	g.Title = `Billing <"API"> & Workers`
+	if err := (&GraphMLFormatter{}).Encode(&output, g); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	if !strings.Contains(output.String(), `name="Billing &lt;&#34;API&#34;&gt; &amp; Workers"`) {
+		t.Errorf("expected escaped graph name attribute, got:\n%s", output.String())
+	}
+
+	var document graphMLDocument
+	if err := xml.Unmarshal(output.Bytes(), &document); err != nil {
+		t.Fatalf("output is not valid XML: %v", err)
+	}
+	if document.Graph.Name != g.Title {
+		t.Errorf("graph name = %q, want %q", document.Graph.Name, g.Title)
+	}
+}
+
+func TestGraphMLFormatter_RoundTrip(t *testing.T) {
+	original := newTestGraph(t)
+	original.Title = "round trip"
+	original.Provenance = []string{"base, 2024", "head"}
+	original.SetMetadata("import_cycles", "a,b")
+	original.Edges()[0].SetAttribute("weight", "2")
+	testEdge := &graph.Edge{From: "example.com/mod/api", To: "example.com/mod/cmd", Kind: graph.EdgeTestImport, IsTestOnly: true}
+	if err := original.AddEdge(testEdge); err != nil {
+		t.Fatalf("AddEdge() error = %v", err)
+	}
+
+	var output bytes.Buffer
+	if err := (&GraphMLFormatter{}).Encode(&output, original); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if strings.Count(output.String(), `<data key="testOnly">true</data>`) != 1 {
+		t.Errorf("expected codegraph:testOnly on the test-only edge only, got:\n%s", output.String())
+	}
+
+	decoded, err := (&GraphMLFormatter{}).Decode(&output)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	if decoded.Title != original.Title || !slices.Equal(decoded.Provenance, original.Provenance) ||
+		!maps.Equal(decoded.Metadata, original.Metadata) {
+		t.Errorf("Title, Provenance, Metadata = %q, %q, %v, want %q, %q, %v", decoded.Title, decoded.Provenance, decoded.Metadata,
+			original.Title, original.Provenance, original.Metadata)
+	}
+	if len(decoded.Nodes()) != len(original.Nodes()) {
+		t.Fatalf("decoded %d nodes, want %d", len(decoded.Nodes()), len(original.Nodes()))
+	}
+	for i, node := range original.Nodes() {
+		got := decoded.Nodes()[i]
+		if got.ID != node.ID || got.Kind != node.Kind || got.Name != node.Name || got.ModulePath != node.ModulePath ||
+			got.ModuleVersion != node.ModuleVersion || got.GoVersion != node.GoVersion || got.TestFramework != node.TestFramework || got.DirPath != node.DirPath || got.Rank != node.Rank ||
+			got.InterfaceCount != node.InterfaceCount || got.ConcreteTypeCount != node.ConcreteTypeCount ||
+			got.HasMainFunc != node.HasMainFunc || got.GoCGO != node.GoCGO || got.EmbedCount != node.EmbedCount || got.ChangeFrequency != node.ChangeFrequency || got.ExportedFuncCount != node.ExportedFuncCount || got.ExportedTypeCount != node.ExportedTypeCount || got.APIBreaking != node.APIBreaking || got.Testable != node.Testable || got.UnsafeUsage != node.UnsafeUsage || got.ProtobufGenerated != node.ProtobufGenerated ||
+			!slices.Equal(got.TestDependencies, node.TestDependencies) || !slices.Equal(got.BuildConstraints, node.BuildConstraints) ||
+			!maps.Equal(got.Attributes, node.Attributes) {
+			t.Errorf("node %d = %+v, want %+v", i, got, node)
+		}
+	}
+	if len(decoded.Edges()) != len(original.Edges()) {
+		t.Fatalf("decoded %d edges, want %d", len(decoded.Edges()), len(original.Edges()))
+	}
+	for i, edge := range original.Edges() {
+		got := decoded.Edges()[i]
+		if got.Key() != edge.Key() || got.IsTestOnly != edge.IsTestOnly || got.Multiplicity != edge.Multiplicity || !maps.Equal(got.Attributes, edge.Attributes) {
+			t.Errorf("edge %d = %+v, want %+v", i, got, edge)
+		}
+	}
+}
+
+func TestGraphMLFormatter_DecodeInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "malformed XML", input: "<graphml><graph>"},
+		{name: "edge to unknown node", input: `<graphml><graph><node id="a"/><edge source="a" target="b"/></graph></graphml>`},
+		{name: "duplicate node", input: `<graphml><graph><node id="a"/><node id="a"/></graph></graphml>`},
+		{name: "invalid integer attribute", input: `<graphml>
+<key id="ic" for="node" attr.name="codegraph:interfaceCount" attr.type="int"/>
+<graph><node id="a"><data key="ic">many</data></node></graph></graphml>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := (&GraphMLFormatter{}).Decode(strings.NewReader(tt.input)); err == nil {
+				t.Error("expected decode error")
+			}
+		})
+	}
+}
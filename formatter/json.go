@@ -0,0 +1,352 @@
+package formatter
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+// DefaultJSONIndent is the indentation used for pretty-printed JSON.
+const DefaultJSONIndent = "  "
+
+// JSONFormatter writes graphs as a single JSON document with "nodes" and
+// "edges" arrays. The zero value writes compact output; set Indent for
+// pretty-printed output.
+type JSONFormatter struct {
+	// Indent is the per-level indentation of pretty-printed output.
+	// An empty Indent produces compact output.
+	Indent string
+	// Compact forces single-line output regardless of Indent, which is
+	// convenient when piping to tools such as jq.
+	Compact bool
+}
+
+type jsonDocument struct {
+	SchemaVersion string            `json:"schema_version"`
+	Title         string            `json:"title,omitempty"`
+	Provenance    []string          `json:"provenance,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+	Nodes         []jsonNode        `json:"nodes"`
+	Edges         []jsonEdge        `json:"edges"`
+}
+
+type jsonNode struct {
+	ID                string            `json:"id"`
+	Kind              graph.Kind        `json:"kind"`
+	Name              string            `json:"name,omitempty"`
+	Module            string            `json:"module,omitempty"`
+	ModuleVersion     string            `json:"module_version,omitempty"`
+	GoVersion         string            `json:"go_version,omitempty"`
+	Files             []string          `json:"files,omitempty"`
+	DirPath           string            `json:"dir_path,omitempty"`
+	Rank              int               `json:"rank,omitempty"`
+	InterfaceCount    int               `json:"interface_count,omitempty"`
+	ConcreteTypeCount int               `json:"concrete_type_count,omitempty"`
+	ExportedFuncCount int               `json:"exported_func_count,omitempty"`
+	ExportedTypeCount int               `json:"exported_type_count,omitempty"`
+	APIBreaking       bool              `json:"api_breaking,omitempty"`
+	HasMainFunc       bool              `json:"has_main_func,omitempty"`
+	Testable          bool              `json:"testable,omitempty"`
+	UnsafeUsage       bool              `json:"unsafe_usage,omitempty"`
+	ProtobufGenerated bool              `json:"protobuf_generated,omitempty"`
+	RequiresCGO       bool              `json:"requires_cgo,omitempty"`
+	EmbedCount        int               `json:"embed_count,omitempty"`
+	ChangeFrequency   int               `json:"change_frequency,omitempty"`
+	TestDependencies  []string          `json:"test_dependencies,omitempty"`
+	TestFramework     string            `json:"test_framework,omitempty"`
+	BuildConstraints  []string          `json:"build_constraints,omitempty"`
+	Attributes        map[string]string `json:"attributes,omitempty"`
+}
+
+type jsonEdge struct {
+	From         string            `json:"from"`
+	To           string            `json:"to"`
+	Kind         graph.EdgeKind    `json:"kind"`
+	TestOnly     bool              `json:"test_only,omitempty"`
+	Multiplicity int               `json:"multiplicity,omitempty"`
+	Attributes   map[string]string `json:"attributes,omitempty"`
+}
+
+// newJSONNode converts node, replacing invalid UTF-8 in its strings, see
+// validString.
+func newJSONNode(node *graph.Node) jsonNode {
+	return jsonNode{
+		ID:                validString(node.ID),
+		Kind:              graph.Kind(validString(string(node.Kind))),
+		Name:              validString(node.Name),
+		Module:            validString(node.ModulePath),
+		ModuleVersion:     validString(node.ModuleVersion),
+		GoVersion:         validString(node.GoVersion),
+		Files:             validStrings(node.Files),
+		DirPath:           validString(node.DirPath),
+		Rank:              node.Rank,
+		InterfaceCount:    node.InterfaceCount,
+		ConcreteTypeCount: node.ConcreteTypeCount,
+		ExportedFuncCount: node.ExportedFuncCount,
+		ExportedTypeCount: node.ExportedTypeCount,
+		APIBreaking:       node.APIBreaking,
+		HasMainFunc:       node.HasMainFunc,
+		Testable:          node.Testable,
+		UnsafeUsage:       node.UnsafeUsage,
+		ProtobufGenerated: node.ProtobufGenerated,
+		RequiresCGO:       node.GoCGO,
+		EmbedCount:        node.EmbedCount,
+		ChangeFrequency:   node.ChangeFrequency,
+		TestDependencies:  validStrings(node.TestDependencies),
+		TestFramework:     validString(node.TestFramework),
+		BuildConstraints:  validStrings(node.BuildConstraints),
+		Attributes:        validStringMap(node.Attributes),
+	}
+}
+
+func (n jsonNode) graphNode() *graph.Node {
+	return &graph.Node{
+		ID:                n.ID,
+		Kind:              n.Kind,
+		Name:              n.Name,
+		ModulePath:        n.Module,
+		ModuleVersion:     n.ModuleVersion,
+		GoVersion:         n.GoVersion,
+		Files:             n.Files,
+		DirPath:           n.DirPath,
+		Rank:              n.Rank,
+		InterfaceCount:    n.InterfaceCount,
+		ConcreteTypeCount: n.ConcreteTypeCount,
+		ExportedFuncCount: n.ExportedFuncCount,
+		ExportedTypeCount: n.ExportedTypeCount,
+		APIBreaking:       n.APIBreaking,
+		HasMainFunc:       n.HasMainFunc,
+		Testable:          n.Testable,
+		UnsafeUsage:       n.UnsafeUsage,
+		ProtobufGenerated: n.ProtobufGenerated,
+		GoCGO:             n.RequiresCGO,
+		EmbedCount:        n.EmbedCount,
+		ChangeFrequency:   n.ChangeFrequency,
+		TestDependencies:  n.TestDependencies,
+		TestFramework:     n.TestFramework,
+		BuildConstraints:  n.BuildConstraints,
+		Attributes:        n.Attributes,
+	}
+}
+
+// newJSONEdge converts edge, replacing invalid UTF-8 in its strings, see
+// validString.
+func newJSONEdge(edge *graph.Edge) jsonEdge {
+	return jsonEdge{From: validString(edge.From), To: validString(edge.To), Kind: graph.EdgeKind(validString(string(edge.Kind))),
+		TestOnly: edge.IsTestOnly, Multiplicity: edge.Multiplicity, Attributes: validStringMap(edge.Attributes)}
+}
+
+// validString returns s with each byte that is not part of valid UTF-8
+// replaced by U+FFFD. encoding/json replaces them too, but writes the
+// replacement escaped or raw depending on the Go version, so strings are
+// made valid before they are marshaled.
+func validString(s string) string {
+	if utf8.ValidString(s) {
+		return s
+	}
+	var valid strings.Builder
+	valid.Grow(len(s) + 2)
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		valid.WriteRune(r)
+		i += size
+	}
+	return valid.String()
+}
+
+// validStrings applies validString to each element of values, copying the
+// slice only when an element changes.
+func validStrings(values []string) []string {
+	for i, value := range values {
+		if utf8.ValidString(value) {
+			continue
+		}
+		valid := slices.Clone(values)
+		for j := i; j < len(valid); j++ {
+			valid[j] = validString(valid[j])
+		}
+		return valid
+	}
+	return values
+}
+
+// validStringMap applies validString to the keys and values of m, copying
+// the map only when one changes.
+func validStringMap(m map[string]string) map[string]string {
+	for key, value := range m {
+		if utf8.ValidString(key) && utf8.ValidString(value) {
+			continue
+		}
+		valid := make(map[string]string, len(m))
+		for key, value := range m {
+			valid[validString(key)] = validString(value)
+		}
+		return valid
+	}
+	return m
+}
+
+func (e jsonEdge) graphEdge() *graph.Edge {
+	return &graph.Edge{From: e.From, To: e.To, Kind: e.Kind, IsTestOnly: e.TestOnly, Multiplicity: e.Multiplicity, Attributes: e.Attributes}
+}
+
+// Encode streams the document: the header fields and then each node and
+// edge are marshaled and written on their own, so only one element is held
+// in memory at a time. The output is what json.Encoder writes for
+// jsonDocument, which Decode reads, once invalid UTF-8 is replaced.
+func (f *JSONFormatter) Encode(writer io.Writer, g *graph.Graph) error {
+	w := &jsonWriter{Writer: bufio.NewWriter(writer)}
+	w.encoder = json.NewEncoder(&w.encoded)
+	if !f.Compact {
+		w.indent = f.Indent
+	}
+
+	w.WriteByte('{')
+	w.field("schema_version", graph.SchemaVersion, true)
+	if g.Title != "" {
+		w.field("title", validString(g.Title), false)
+	}
+	if len(g.Provenance) > 0 {
+		w.field("provenance", validStrings(g.Provenance), false)
+	}
+	if len(g.Metadata) > 0 {
+		w.field("metadata", validStringMap(g.Metadata), false)
+	}
+
+	w.key("nodes", false)
+	w.WriteByte('[')
+	for i, node := range g.Nodes() {
+		w.node = newJSONNode(node)
+		w.arrayElement(i, &w.node)
+	}
+	w.arrayEnd(len(g.Nodes()))
+
+	w.key("edges", false)
+	w.WriteByte('[')
+	for i, edge := range g.Edges() {
+		w.edge = newJSONEdge(edge)
+		w.arrayElement(i, &w.edge)
+	}
+	w.arrayEnd(len(g.Edges()))
+
+	w.newline(0)
+	w.WriteString("}\n")
+	if w.err != nil {
+		return w.err
+	}
+	return w.Flush()
+}
+
+// jsonWriter writes the top-level document object. An empty indent writes
+// compact output. The first marshaling error is kept in err; write errors
+// stick to the bufio.Writer and are returned by Flush. Elements are
+// converted and encoded into fields reused across elements.
+type jsonWriter struct {
+	*bufio.Writer
+	indent   string
+	err      error
+	encoder  *json.Encoder
+	encoded  bytes.Buffer
+	indented bytes.Buffer
+	node     jsonNode
+	edge     jsonEdge
+}
+
+// newline starts a line at the given depth when indenting.
+func (w *jsonWriter) newline(depth int) {
+	if w.indent == "" {
+		return
+	}
+	w.WriteByte('\n')
+	for range depth {
+		w.WriteString(w.indent)
+	}
+}
+
+func (w *jsonWriter) key(name string, first bool) {
+	if !first {
+		w.WriteByte(',')
+	}
+	w.newline(1)
+	w.WriteString(`"` + name + `":`)
+	if w.indent != "" {
+		w.WriteByte(' ')
+	}
+}
+
+func (w *jsonWriter) field(name string, value any, first bool) {
+	w.key(name, first)
+	w.value(1, value)
+}
+
+func (w *jsonWriter) arrayElement(i int, value any) {
+	if i > 0 {
+		w.WriteByte(',')
+	}
+	w.newline(2)
+	w.value(2, value)
+}
+
+// arrayEnd closes an array; json.Encoder keeps empty arrays on one line.
+func (w *jsonWriter) arrayEnd(length int) {
+	if length > 0 {
+		w.newline(1)
+	}
+	w.WriteByte(']')
+}
+
+// value writes value marshaled as json.Encoder does, indented for depth.
+func (w *jsonWriter) value(depth int, value any) {
+	w.encoded.Reset()
+	if err := w.encoder.Encode(value); err != nil {
+		if w.err == nil {
+			w.err = err
+		}
+		return
+	}
+	// Encode terminates each value with a newline.
+	encoded := bytes.TrimSuffix(w.encoded.Bytes(), []byte{'\n'})
+	if w.indent == "" {
+		w.Write(encoded)
+		return
+	}
+	w.indented.Reset()
+	_ = json.Indent(&w.indented, encoded, strings.Repeat(w.indent, depth), w.indent)
+	w.Write(w.indented.Bytes())
+}
+
+// Decode reads a document written by Encode and upgrades it to the current
+// schema version.
+func (f *JSONFormatter) Decode(reader io.Reader) (*graph.Graph, error) {
+	var document jsonDocument
+	if err := json.NewDecoder(reader).Decode(&document); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	decoded := graph.New()
+	decoded.SchemaVersion = document.SchemaVersion
+	decoded.Title = document.Title
+	decoded.Provenance = document.Provenance
+	decoded.Metadata = document.Metadata
+	for _, node := range document.Nodes {
+		if err := decoded.AddNode(node.graphNode()); err != nil {
+			return nil, err
+		}
+	}
+	for _, edge := range document.Edges {
+		if err := decoded.AddEdge(edge.graphEdge()); err != nil {
+			return nil, err
+		}
+	}
+	if err := graph.CurrentSchema().Upgrade(decoded); err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
@@ -0,0 +1,100 @@
+package formatter
+
+import (
+	"bytes"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestJSONFormatter_Encode(t *testing.T) {
+	tests := []struct {
+		name         string
+		formatter    JSONFormatter
+		wantLines    int
+		wantIndented string
+	}{
+		{name: "pretty with default indent", formatter: JSONFormatter{Indent: DefaultJSONIndent}, wantIndented: "\n  \"nodes\": ["},
+		{name: "pretty with tab indent", formatter: JSONFormatter{Indent: "\t"}, wantIndented: "\n\t\"nodes\": ["},
+		{name: "compact overrides indent", formatter: JSONFormatter{Indent: DefaultJSONIndent, Compact: true}, wantLines: 1},
+		{name: "empty indent is compact", formatter: JSONFormatter{}, wantLines: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			if err := tt.formatter.Encode(&output, newTestGraph(t)); err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+
+			if tt.wantLines > 0 {
+				if lines := strings.Count(output.String(), "\n"); lines != tt.wantLines {
+					t.Errorf("expected %d line(s), got %d:\n%s", tt.wantLines, lines, output.String())
+				}
+			}
+			if tt.wantIndented != "" && !strings.Contains(output.String(), tt.wantIndented) {
+				t.Errorf("expected output to contain %q, got:\n%s", tt.wantIndented, output.String())
+			}
+		})
+	}
+}
+
+func TestJSONFormatter_RoundTrip(t *testing.T) {
+	original := newTestGraph(t)
+	original.Title = "round trip"
+	original.Provenance = []string{"base, 2024", "head"}
+	original.SetMetadata("import_cycles", "a,b")
+	original.Edges()[0].SetAttribute("weight", "2")
+
+	var output bytes.Buffer
+	if err := (&JSONFormatter{Compact: true}).Encode(&output, original); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	decoded, err := (&JSONFormatter{}).Decode(&output)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	if decoded.Title != original.Title || !slices.Equal(decoded.Provenance, original.Provenance) ||
+		!maps.Equal(decoded.Metadata, original.Metadata) {
+		t.Errorf("Title, Provenance, Metadata = %q, %q, %v, want %q, %q, %v", decoded.Title, decoded.Provenance, decoded.Metadata,
+			original.Title, original.Provenance, original.Metadata)
+	}
+	if len(decoded.Nodes()) != len(original.Nodes()) || len(decoded.Edges()) != len(original.Edges()) {
+		t.Fatalf("decoded %d nodes and %d edges, want %d and %d",
+			len(decoded.Nodes()), len(decoded.Edges()), len(original.Nodes()), len(original.Edges()))
+	}
+	for i, node := range original.Nodes() {
+		got := decoded.Nodes()[i]
+		if got.ID != node.ID || got.Kind != node.Kind || !reflect.DeepEqual(got.Files, node.Files) ||
+			got.ConcreteTypeCount != node.ConcreteTypeCount || got.HasMainFunc != node.HasMainFunc || got.GoCGO != node.GoCGO ||
+			got.EmbedCount != node.EmbedCount || got.ChangeFrequency != node.ChangeFrequency || got.ExportedFuncCount != node.ExportedFuncCount || got.ExportedTypeCount != node.ExportedTypeCount || got.APIBreaking != node.APIBreaking || got.Testable != node.Testable || got.UnsafeUsage != node.UnsafeUsage || got.ProtobufGenerated != node.ProtobufGenerated || got.ModuleVersion != node.ModuleVersion || got.GoVersion != node.GoVersion || got.TestFramework != node.TestFramework ||
+			got.DirPath != node.DirPath || got.Rank != node.Rank ||
+			!slices.Equal(got.TestDependencies, node.TestDependencies) || !maps.Equal(got.Attributes, node.Attributes) {
+			t.Errorf("node %d = %+v, want %+v", i, got, node)
+		}
+	}
+	for i, edge := range original.Edges() {
+		got := decoded.Edges()[i]
+		if got.Key() != edge.Key() || got.Multiplicity != edge.Multiplicity || !maps.Equal(got.Attributes, edge.Attributes) {
+			t.Errorf("edge %d = %+v, want %+v", i, got, edge)
+		}
+	}
+}
+
+func TestJSONFormatter_DecodeInvalid(t *testing.T) {
+	inputs := map[string]string{
+		"malformed":      `{"nodes": [`,
+		"duplicate node": `{"nodes": [{"id": "a"}, {"id": "a"}], "edges": []}`,
+		"dangling edge":  `{"nodes": [{"id": "a"}], "edges": [{"from": "a", "to": "b"}]}`,
+	}
+	for name, input := range inputs {
+		t.Run(name, func(t *testing.T) {
+			if _, err := (&JSONFormatter{}).Decode(strings.NewReader(input)); err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package formatter
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+// MarkdownFormatter writes graphs as Markdown documentation: a Mermaid
+// diagram, a summary table with file and import counts, and a section per
+// package listing its imports. Test-only imports are drawn dashed and marked
+// in the lists but not counted.
+type MarkdownFormatter struct{}
+
+func (f *MarkdownFormatter) Encode(writer io.Writer, g *graph.Graph) error {
+	bufferedWriter := bufio.NewWriter(writer)
+
+	title := g.Title
+	if title == "" {
+		title = "codegraph"
+	}
+	bufferedWriter.Write(append(appendMarkdownEscaped(append(bufferedWriter.AvailableBuffer(), "# "...), title), "\n\n"...))
+	writeMermaid(bufferedWriter, g)
+	writeSummaryTable(bufferedWriter, g)
+	for _, node := range g.Nodes() {
+		writePackageSection(bufferedWriter, g, node)
+	}
+
+	return bufferedWriter.Flush()
+}
+
+// writeMermaid draws the graph with node IDs n0, n1, ... in node order.
+func writeMermaid(writer *bufio.Writer, g *graph.Graph) {
+	mermaidIDs := make(map[string]int, len(g.Nodes()))
+	writer.WriteString("```mermaid\ngraph LR\n")
+	for i, node := range g.Nodes() {
+		mermaidIDs[node.ID] = i
+		line := strconv.AppendInt(append(writer.AvailableBuffer(), "  n"...), int64(i), 10)
+		line = appendMermaidLabel(append(line, `["`...), node.Label())
+		writer.Write(append(line, "\"]\n"...))
+	}
+	for _, edge := range g.Edges() {
+		arrow := " --> n"
+		if edge.Kind == graph.EdgeTestImport {
+			arrow = " -.-> n"
+		}
+		line := strconv.AppendInt(append(writer.AvailableBuffer(), "  n"...), int64(mermaidIDs[edge.From]), 10)
+		line = strconv.AppendInt(append(line, arrow...), int64(mermaidIDs[edge.To]), 10)
+		writer.Write(append(line, '\n'))
+	}
+	writer.WriteString("```\n\n")
+}
+
+func writeSummaryTable(writer *bufio.Writer, g *graph.Graph) {
+	writer.WriteString("## Summary\n\n| Package | Files | Imports |\n| --- | ---: | ---: |\n")
+	for _, node := range g.Nodes() {
+		imports := len(g.Neighbors(node.ID, graph.Outgoing, []graph.EdgeKind{graph.EdgeImport}))
+		line := appendMarkdownEscaped(append(writer.AvailableBuffer(), "| "...), node.Label())
+		line = strconv.AppendInt(append(line, " | "...), int64(len(node.Files)), 10)
+		line = strconv.AppendInt(append(line, " | "...), int64(imports), 10)
+		writer.Write(append(line, " |\n"...))
+	}
+	writer.WriteString("\n")
+}
+
+func writePackageSection(writer *bufio.Writer, g *graph.Graph, node *graph.Node) {
+	writer.Write(append(appendMarkdownEscaped(append(writer.AvailableBuffer(), "## "...), node.Label()), "\n\n"...))
+
+	imports := g.Neighbors(node.ID, graph.Outgoing, []graph.EdgeKind{graph.EdgeImport})
+	testImports := g.Neighbors(node.ID, graph.Outgoing, []graph.EdgeKind{graph.EdgeTestImport})
+	if len(imports)+len(testImports) == 0 {
+		writer.WriteString("No imports.\n\n")
+		return
+	}
+	for _, id := range imports {
+		writer.Write(append(appendMarkdownLabel(append(writer.AvailableBuffer(), "- "...), g, id), '\n'))
+	}
+	for _, id := range testImports {
+		writer.Write(append(appendMarkdownLabel(append(writer.AvailableBuffer(), "- "...), g, id), " (tests only)\n"...))
+	}
+	writer.WriteString("\n")
+}
+
+// appendMarkdownLabel appends the escaped label of the node id, or the id
+// itself when the graph has no such node.
+func appendMarkdownLabel(dst []byte, g *graph.Graph, id string) []byte {
+	if node, found := g.Node(id); found {
+		return appendMarkdownEscaped(dst, node.Label())
+	}
+	return appendMarkdownEscaped(dst, id)
+}
@@ -0,0 +1,97 @@
+package formatter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+func TestMarkdownFormatter_Encode(t *testing.T) {
+	g := newTestGraph(t)
+	g.Title = "example_mod"
+	if err := g.AddEdge(&graph.Edge{From: "example.com/mod/api", To: "example.com/mod/cmd", Kind: graph.EdgeTestImport}); err != nil {
+		t.Fatalf("AddEdge() error = %v", err)
+	}
+
+	var output bytes.Buffer
+	if err := (&MarkdownFormatter{}).Encode(&output, g); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	lines := strings.Split(output.String(), "\n")
+
+	if lines[0] != `# example\_mod` {
+		t.Errorf("first line = %q, want the escaped title heading", lines[0])
+	}
+
+	// The Mermaid fence comes first and is closed.
+	if lines[2] != "```mermaid" {
+		t.Fatalf("expected a mermaid fence after the title, got %q", lines[2])
+	}
+	fenceEnd := indexOf(lines, "```")
+	if fenceEnd < 0 {
+		t.Fatal("mermaid fence is not closed")
+	}
+	mermaid := strings.Join(lines[2:fenceEnd], "\n")
+	for _, want := range []string{"graph LR", `n0["api"]`, "n0 --> n2", "n1 --> n0", "n0 -.-> n1"} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("expected %q in mermaid block:\n%s", want, mermaid)
+		}
+	}
+
+	// The summary table has a header, a delimiter row and one row per package.
+	tableStart := indexOf(lines, "| Package | Files | Imports |")
+	if tableStart < 0 || tableStart < fenceEnd {
+		t.Fatalf("expected the summary table after the diagram:\n%s", output.String())
+	}
+	wantRows := []string{
+		"| Package | Files | Imports |",
+		"| --- | ---: | ---: |",
+		"| api | 1 | 1 |",
+		"| cmd | 1 | 2 |",
+		"| store | 2 | 0 |",
+	}
+	for i, want := range wantRows {
+		if lines[tableStart+i] != want {
+			t.Errorf("table row %d = %q, want %q", i, lines[tableStart+i], want)
+		}
+	}
+
+	// One section per package, with imports as a bulleted list.
+	for _, section := range []struct {
+		heading string
+		items   []string
+	}{
+		{heading: "## api", items: []string{"- store", "- cmd (tests only)"}},
+		{heading: "## cmd", items: []string{"- api", "- store"}},
+		{heading: "## store", items: []string{"No imports."}},
+	} {
+		start := indexOf(lines, section.heading)
+		if start < 0 {
+			t.Errorf("missing section %q", section.heading)
+			continue
+		}
+		for i, item := range section.items {
+			if lines[start+2+i] != item {
+				t.Errorf("section %q line %d = %q, want %q", section.heading, i, lines[start+2+i], item)
+			}
+		}
+	}
+}
+
+func TestMarkdownFormatter_Registered(t *testing.T) {
+	format, found := graph.FormatForFile("ARCHITECTURE.md")
+	if !found || format.Name != "markdown" {
+		t.Errorf("expected .md to select the markdown format, got %+v", format)
+	}
+}
+
+func indexOf(lines []string, line string) int {
+	for i, candidate := range lines {
+		if candidate == line {
+			return i
+		}
+	}
+	return -1
+}
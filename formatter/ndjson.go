@@ -0,0 +1,65 @@
+package formatter
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+// NDJSONFormatter writes graphs as newline-delimited JSON: a "meta" object
+// carrying the schema version, then one object per node and one per edge,
+// each tagged with a "type" field.
+type NDJSONFormatter struct{}
+
+func (f *NDJSONFormatter) Encode(writer io.Writer, g *graph.Graph) error {
+	return g.EmitTo(NewNDJSONEmitter(writer))
+}
+
+// NDJSONEmitter is a streaming graph.Emitter that writes each element as soon
+// as it is emitted, keeping memory use constant. It does not check that edge
+// endpoints exist; decode the output into a graph to validate it.
+type NDJSONEmitter struct {
+	bufferedWriter *bufio.Writer
+	encoder        *json.Encoder
+}
+
+// NewNDJSONEmitter returns an emitter writing to writer. Close flushes the
+// output but does not close writer.
+func NewNDJSONEmitter(writer io.Writer) *NDJSONEmitter {
+	bufferedWriter := bufio.NewWriter(writer)
+	encoder := json.NewEncoder(bufferedWriter)
+	// A write error is kept by the buffered writer and returned by later calls.
+	_ = encoder.Encode(ndjsonMeta{Type: "meta", SchemaVersion: graph.SchemaVersion})
+	return &NDJSONEmitter{bufferedWriter: bufferedWriter, encoder: encoder}
+}
+
+// ndjsonMeta is the first record of every stream.
+type ndjsonMeta struct {
+	Type          string `json:"type"`
+	SchemaVersion string `json:"schema_version"`
+}
+
+// ndjsonNode and ndjsonEdge tag the JSON formatter's records with their type.
+type ndjsonNode struct {
+	Type string `json:"type"`
+	jsonNode
+}
+
+type ndjsonEdge struct {
+	Type string `json:"type"`
+	jsonEdge
+}
+
+func (e *NDJSONEmitter) EmitNode(node graph.Node) error {
+	return e.encoder.Encode(ndjsonNode{Type: "node", jsonNode: newJSONNode(&node)})
+}
+
+func (e *NDJSONEmitter) EmitEdge(edge graph.Edge) error {
+	return e.encoder.Encode(ndjsonEdge{Type: "edge", jsonEdge: newJSONEdge(&edge)})
+}
+
+func (e *NDJSONEmitter) Close() error {
+	return e.bufferedWriter.Flush()
+}
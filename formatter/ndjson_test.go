@@ -0,0 +1,62 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+func TestNDJSONFormatter_Encode(t *testing.T) {
+	var output bytes.Buffer
+	if err := (&NDJSONFormatter{}).Encode(&output, newTestGraph(t)); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	if len(lines) != 7 {
+		t.Fatalf("expected a meta line, 3 node and 3 edge lines, got %d:\n%s", len(lines), output.String())
+	}
+	if wantMeta := `{"type":"meta","schema_version":"1.0"}`; lines[0] != wantMeta {
+		t.Errorf("meta line = %s, want %s", lines[0], wantMeta)
+	}
+
+	var store map[string]any
+	if err := json.Unmarshal([]byte(lines[3]), &store); err != nil {
+		t.Fatalf("line 4 is not valid JSON: %v", err)
+	}
+	if store["type"] != "node" || store["id"] != "example.com/mod/store" || store["concrete_type_count"] != 3.0 {
+		t.Errorf("unexpected store record: %v", store)
+	}
+
+	wantEdge := `{"type":"edge","from":"example.com/mod/api","to":"example.com/mod/store","kind":"import","multiplicity":3}`
+	if lines[4] != wantEdge {
+		t.Errorf("edge line = %s, want %s", lines[4], wantEdge)
+	}
+}
+
+func TestNDJSONEmitter_StreamsDanglingEdges(t *testing.T) {
+	var output bytes.Buffer
+	emitter := NewNDJSONEmitter(&output)
+
+	if err := emitter.EmitEdge(graph.Edge{From: "a", To: "b", Kind: graph.EdgeImport}); err != nil {
+		t.Fatalf("EmitEdge() error = %v", err)
+	}
+	if err := emitter.EmitNode(graph.Node{ID: "a", Kind: graph.KindPackage}); err != nil {
+		t.Fatalf("EmitNode() error = %v", err)
+	}
+	if output.Len() != 0 {
+		t.Errorf("expected output to be buffered until Close, got %q", output.String())
+	}
+	if err := emitter.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	want := `{"type":"meta","schema_version":"1.0"}` + "\n" +
+		`{"type":"edge","from":"a","to":"b","kind":"import"}` + "\n" + `{"type":"node","id":"a","kind":"package"}` + "\n"
+	if output.String() != want {
+		t.Errorf("output = %q, want %q", output.String(), want)
+	}
+}
@@ -0,0 +1,35 @@
+package formatter
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+// sortedNodes returns the graph's nodes sorted by ID, leaving the graph's
+// insertion order untouched.
+func sortedNodes(g *graph.Graph) []*graph.Node {
+	nodes := slices.Clone(g.Nodes())
+	slices.SortFunc(nodes, func(a, b *graph.Node) int { return cmp.Compare(a.ID, b.ID) })
+	return nodes
+}
+
+// sortedEdges returns the graph's edges sorted by source, then target, then
+// kind. Parallel edges of the same kind keep their insertion order.
+func sortedEdges(g *graph.Graph) []*graph.Edge {
+	edges := slices.Clone(g.Edges())
+	slices.SortStableFunc(edges, func(a, b *graph.Edge) int {
+		return cmp.Or(cmp.Compare(a.From, b.From), cmp.Compare(a.To, b.To), cmp.Compare(a.Kind, b.Kind))
+	})
+	return edges
+}
+
+// orderedElements returns the graph's nodes and edges, sorted when sorted is
+// true and in insertion order otherwise.
+func orderedElements(g *graph.Graph, sorted bool) ([]*graph.Node, []*graph.Edge) {
+	if sorted {
+		return sortedNodes(g), sortedEdges(g)
+	}
+	return g.Nodes(), g.Edges()
+}
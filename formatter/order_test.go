@@ -0,0 +1,98 @@
+package formatter
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+// newOrderedTestGraph builds the same graph with nodes and edges inserted in
+// forward or reverse order.
+func newOrderedTestGraph(t *testing.T, reverse bool) *graph.Graph {
+	t.Helper()
+	ids := []string{"example.com/mod/api", "example.com/mod/cmd", "example.com/mod/store"}
+	edges := []graph.Edge{
+		{From: "example.com/mod/api", To: "example.com/mod/store", Kind: graph.EdgeImport},
+		{From: "example.com/mod/cmd", To: "example.com/mod/api", Kind: graph.EdgeImport},
+		{From: "example.com/mod/cmd", To: "example.com/mod/store", Kind: graph.EdgeTestImport, IsTestOnly: true},
+		{From: "example.com/mod/cmd", To: "example.com/mod/store", Kind: graph.EdgeImport},
+	}
+	if reverse {
+		slices.Reverse(ids)
+		slices.Reverse(edges)
+	}
+
+	g := graph.New()
+	for _, id := range ids {
+		if err := g.AddNode(&graph.Node{ID: id, Kind: graph.KindPackage, ModulePath: "example.com/mod"}); err != nil {
+			t.Fatalf("AddNode() error = %v", err)
+		}
+	}
+	for _, edge := range edges {
+		if err := g.AddEdge(&edge); err != nil {
+			t.Fatalf("AddEdge() error = %v", err)
+		}
+	}
+	return g
+}
+
+func TestRegisteredEncoders_SortNodes(t *testing.T) {
+	for _, formatName := range []string{"graphml", "dot"} {
+		t.Run(formatName, func(t *testing.T) {
+			format, found := graph.LookupFormat(formatName)
+			if !found {
+				t.Fatalf("format %q is not registered", formatName)
+			}
+
+			var outputs []string
+			for _, reverse := range []bool{false, true, false} {
+				var buffer bytes.Buffer
+				if err := format.NewEncoder().Encode(&buffer, newOrderedTestGraph(t, reverse)); err != nil {
+					t.Fatalf("Encode() error = %v", err)
+				}
+				outputs = append(outputs, buffer.String())
+			}
+			for i, output := range outputs[1:] {
+				if output != outputs[0] {
+					t.Errorf("run %d differs from run 0:\n%s\nwant\n%s", i+1, output, outputs[0])
+				}
+			}
+
+			api := strings.Index(outputs[0], `"example.com/mod/api"`)
+			store := strings.Index(outputs[0], `"example.com/mod/store"`)
+			if api < 0 || store < 0 || api > store {
+				t.Errorf("expected nodes sorted by ID, got\n%s", outputs[0])
+			}
+		})
+	}
+}
+
+func TestSortedEdges(t *testing.T) {
+	var got []string
+	for _, edge := range sortedEdges(newOrderedTestGraph(t, true)) {
+		got = append(got, edge.From+" "+edge.To+" "+string(edge.Kind))
+	}
+	want := []string{
+		"example.com/mod/api example.com/mod/store import",
+		"example.com/mod/cmd example.com/mod/api import",
+		"example.com/mod/cmd example.com/mod/store import",
+		"example.com/mod/cmd example.com/mod/store test_import",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("sortedEdges() = %v, want %v", got, want)
+	}
+}
+
+func TestGraphMLFormatter_InsertionOrderWithoutSortNodes(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := (&GraphMLFormatter{}).Encode(&buffer, newOrderedTestGraph(t, true)); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	output := buffer.String()
+	if strings.Index(output, `"example.com/mod/store"`) > strings.Index(output, `"example.com/mod/api"`) {
+		t.Errorf("expected insertion order without SortNodes, got\n%s", output)
+	}
+}
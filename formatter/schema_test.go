@@ -0,0 +1,90 @@
+package formatter
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+// TestDecode_SchemaV1Fixture decodes a checked-in schema 1.0 file, then
+// simulates a 1.1 release that renames the "loc" attribute to check that old
+// files are migrated rather than rejected.
+func TestDecode_SchemaV1Fixture(t *testing.T) {
+	fixture, err := os.Open("testdata/schema_v1.graphml")
+	if err != nil {
+		t.Fatalf("failed to open fixture: %v", err)
+	}
+	defer fixture.Close()
+
+	decoded, err := (&GraphMLFormatter{}).Decode(fixture)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if decoded.SchemaVersion != "1.0" || len(decoded.Nodes()) != 3 || len(decoded.Edges()) != 3 {
+		t.Fatalf("unexpected decoded fixture: schema %q, %d nodes, %d edges",
+			decoded.SchemaVersion, len(decoded.Nodes()), len(decoded.Edges()))
+	}
+
+	simulated := graph.Schema{
+		Version: graph.Version{Major: 1, Minor: 1},
+		Migrations: map[graph.Version]graph.Migration{
+			{Major: 1, Minor: 0}: func(g *graph.Graph) error {
+				for _, node := range g.Nodes() {
+					if loc, found := node.Attributes["loc"]; found {
+						delete(node.Attributes, "loc")
+						node.SetAttribute("lines_of_code", loc)
+					}
+				}
+				return nil
+			},
+		},
+	}
+	if err := simulated.Upgrade(decoded); err != nil {
+		t.Fatalf("Upgrade() error = %v", err)
+	}
+
+	store, _ := decoded.Node("example.com/mod/store")
+	if decoded.SchemaVersion != "1.1" || store.Attributes["lines_of_code"] != "120" || store.Attributes["loc"] != "" {
+		t.Errorf("expected the 1.0 -> 1.1 migration to run, got schema %q and attributes %v",
+			decoded.SchemaVersion, store.Attributes)
+	}
+
+	newerMajor := graph.Schema{Version: graph.Version{Major: 0, Minor: 9}}
+	if err := newerMajor.Upgrade(decoded); !errors.Is(err, graph.ErrUnsupportedSchema) {
+		t.Errorf("expected a reader of schema 0.9 to reject the file, got %v", err)
+	}
+}
+
+func TestDecode_RejectsNewerMajorSchema(t *testing.T) {
+	documents := map[string]struct {
+		decoder  graph.Decoder
+		document string
+	}{
+		"graphml": {
+			decoder: &GraphMLFormatter{},
+			document: `<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="schemaVersion" for="graph" attr.name="codegraph:schemaVersion" attr.type="string"></key>
+  <graph id="G" edgedefault="directed"><data key="schemaVersion">2.0</data></graph>
+</graphml>`,
+		},
+		"json": {
+			decoder:  &JSONFormatter{},
+			document: `{"schema_version": "2.0", "nodes": [], "edges": []}`,
+		},
+	}
+
+	for name, tt := range documents {
+		t.Run(name, func(t *testing.T) {
+			_, err := tt.decoder.Decode(strings.NewReader(tt.document))
+			if !errors.Is(err, graph.ErrUnsupportedSchema) {
+				t.Fatalf("expected ErrUnsupportedSchema, got %v", err)
+			}
+			if !strings.Contains(err.Error(), "upgrade codegraph") {
+				t.Errorf("expected an actionable message, got %q", err)
+			}
+		})
+	}
+}
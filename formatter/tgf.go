@@ -0,0 +1,108 @@
+package formatter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+// tgfSeparator ends the node section of a TGF document.
+const tgfSeparator = "#"
+
+// importAliasAttribute is the edge attribute holding import aliases, as
+// recorded by extract.
+const importAliasAttribute = "import_alias"
+
+// TGFFormatter writes graphs in the Trivial Graph Format read by yEd and
+// older graph tools: one "<id> <label>" line per node, a "#" line, then one
+// "<from> <to> [label]" line per edge. Node IDs are sequential integers in
+// node order and labels are the graph's node IDs, i.e. import paths. TGF has
+// no room for kinds or attributes, so only the structure survives decoding.
+type TGFFormatter struct {
+	// ShowEdgeLabels labels edges with the import aliases they are declared
+	// under, when any.
+	ShowEdgeLabels bool
+}
+
+func (f *TGFFormatter) Encode(writer io.Writer, g *graph.Graph) error {
+	bufferedWriter := bufio.NewWriter(writer)
+
+	tgfIDs := make(map[string]int, len(g.Nodes()))
+	for i, node := range g.Nodes() {
+		tgfIDs[node.ID] = i + 1
+		line := strconv.AppendInt(bufferedWriter.AvailableBuffer(), int64(i+1), 10)
+		line = append(append(line, ' '), node.ID...)
+		bufferedWriter.Write(append(line, '\n'))
+	}
+	bufferedWriter.WriteString(tgfSeparator + "\n")
+	for _, edge := range g.Edges() {
+		line := strconv.AppendInt(bufferedWriter.AvailableBuffer(), int64(tgfIDs[edge.From]), 10)
+		line = strconv.AppendInt(append(line, ' '), int64(tgfIDs[edge.To]), 10)
+		if alias := edge.Attributes[importAliasAttribute]; f.ShowEdgeLabels && alias != "" {
+			line = append(append(line, ' '), alias...)
+		}
+		bufferedWriter.Write(append(line, '\n'))
+	}
+
+	return bufferedWriter.Flush()
+}
+
+// Decode reads a TGF document into package nodes, identified by their labels
+// or, for unlabelled nodes, their TGF IDs, and import edges. Edge labels
+// become the import alias attribute. An empty line is accepted in place of
+// the "#" separator.
+func (f *TGFFormatter) Decode(reader io.Reader) (*graph.Graph, error) {
+	g := graph.New()
+	nodeIDs := make(map[string]string)
+	inEdges := false
+
+	scanner := bufio.NewScanner(reader)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if !inEdges && (line == tgfSeparator || line == "") {
+			inEdges = true
+			continue
+		}
+		if line == "" {
+			continue
+		}
+
+		if !inEdges {
+			tgfID, label, _ := strings.Cut(line, " ")
+			label = strings.TrimSpace(label)
+			if label == "" {
+				label = tgfID
+			}
+			nodeIDs[tgfID] = label
+			if err := g.AddNode(&graph.Node{ID: label, Kind: graph.KindPackage}); err != nil {
+				return nil, fmt.Errorf("tgf line %d: %w", lineNumber, err)
+			}
+			continue
+		}
+
+		fields := strings.SplitN(line, " ", 3)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("tgf line %d: edge needs a source and a target, got %q", lineNumber, line)
+		}
+		edge := &graph.Edge{From: nodeIDs[fields[0]], To: nodeIDs[fields[1]], Kind: graph.EdgeImport}
+		if edge.From == "" || edge.To == "" {
+			return nil, fmt.Errorf("tgf line %d: edge references an undeclared node: %q", lineNumber, line)
+		}
+		if len(fields) == 3 {
+			if label := strings.TrimSpace(fields[2]); label != "" {
+				edge.SetAttribute(importAliasAttribute, label)
+			}
+		}
+		if err := g.AddEdge(edge); err != nil {
+			return nil, fmt.Errorf("tgf line %d: %w", lineNumber, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
@@ -0,0 +1,95 @@
+package formatter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+func TestTGFFormatter_Encode(t *testing.T) {
+	g := newTestGraph(t)
+	g.Edges()[0].SetAttribute(importAliasAttribute, "db")
+
+	tests := []struct {
+		name      string
+		formatter *TGFFormatter
+		want      string
+	}{
+		{
+			name:      "without edge labels",
+			formatter: &TGFFormatter{},
+			want: "1 example.com/mod/api\n2 example.com/mod/cmd\n3 example.com/mod/store\n#\n" +
+				"1 3\n2 1\n2 3\n",
+		},
+		{
+			name:      "with edge labels",
+			formatter: &TGFFormatter{ShowEdgeLabels: true},
+			want: "1 example.com/mod/api\n2 example.com/mod/cmd\n3 example.com/mod/store\n#\n" +
+				"1 3 db\n2 1\n2 3\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			if err := tt.formatter.Encode(&output, g); err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			if output.String() != tt.want {
+				t.Errorf("Encode() = %q, want %q", output.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestTGFFormatter_RoundTrip(t *testing.T) {
+	original := newTestGraph(t)
+	original.Edges()[0].SetAttribute(importAliasAttribute, "db")
+
+	var output bytes.Buffer
+	tgfFormatter := &TGFFormatter{ShowEdgeLabels: true}
+	if err := tgfFormatter.Encode(&output, original); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	decoded, err := tgfFormatter.Decode(&output)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	if len(decoded.Nodes()) != len(original.Nodes()) {
+		t.Fatalf("decoded %d nodes, want %d", len(decoded.Nodes()), len(original.Nodes()))
+	}
+	for i, node := range original.Nodes() {
+		if got := decoded.Nodes()[i]; got.ID != node.ID || got.Kind != graph.KindPackage {
+			t.Errorf("node %d = %+v, want package %s", i, got, node.ID)
+		}
+	}
+	if len(decoded.Edges()) != len(original.Edges()) {
+		t.Fatalf("decoded %d edges, want %d", len(decoded.Edges()), len(original.Edges()))
+	}
+	for i, edge := range original.Edges() {
+		got := decoded.Edges()[i]
+		if got.From != edge.From || got.To != edge.To || got.Attributes[importAliasAttribute] != edge.Attributes[importAliasAttribute] {
+			t.Errorf("edge %d = %+v, want %+v", i, got, edge)
+		}
+	}
+}
+
+func TestTGFFormatter_Decode(t *testing.T) {
+	t.Run("empty line separator and unlabelled nodes", func(t *testing.T) {
+		decoded, err := (&TGFFormatter{}).Decode(strings.NewReader("1 a\n2\n\n1 2\n"))
+		if err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		if edges := decoded.Edges(); len(edges) != 1 || edges[0].From != "a" || edges[0].To != "2" {
+			t.Errorf("unexpected edges %+v", edges)
+		}
+	})
+
+	t.Run("undeclared node", func(t *testing.T) {
+		if _, err := (&TGFFormatter{}).Decode(strings.NewReader("1 a\n#\n1 2\n")); err == nil {
+			t.Error("expected an error for an edge to an undeclared node")
+		}
+	})
+}
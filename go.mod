@@ -4,6 +4,7 @@ go 1.24.5
 
 require (
 	github.com/stretchr/testify v1.11.1
+	golang.org/x/term v0.36.0
 	golang.org/x/tools v0.38.0
 )
 
@@ -12,5 +13,6 @@ require (
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	golang.org/x/mod v0.29.0 // indirect
 	golang.org/x/sync v0.17.0 // indirect
+	golang.org/x/sys v0.37.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,57 @@
+package graph
+
+import "sort"
+
+// APIBreakage kinds: which count shrank, or that the package disappeared.
+const (
+	APIBreakageExportedFuncs = "exported_funcs"
+	APIBreakageExportedTypes = "exported_types"
+	APIBreakageRemoved       = "package_removed"
+)
+
+// APIBreakage is a package whose exported API shrank between two snapshots.
+// For APIBreakageRemoved the counts are the package's exported functions plus
+// types before, and zero.
+type APIBreakage struct {
+	Package     string `json:"package"`
+	BeforeCount int    `json:"before_count"`
+	AfterCount  int    `json:"after_count"`
+	Kind        string `json:"kind"`
+}
+
+// DetectAPIBreakages compares the ExportedFuncCount and ExportedTypeCount of
+// the package nodes in before and after, and sets APIBreaking on the packages
+// of after whose counts decreased. Results are sorted by package, then kind.
+//
+// This is a rough heuristic: a decrease suggests removed symbols, but renames
+// that keep the count, changed signatures and added interface methods go
+// unnoticed. For precise detection use apidiff from golang.org/x/exp, or
+// codegraph's own api and apidiff commands.
+func DetectAPIBreakages(before, after *Graph) []APIBreakage {
+	var breakages []APIBreakage
+	for _, old := range before.Nodes() {
+		if old.Kind != KindPackage {
+			continue
+		}
+		current, found := after.Node(old.ID)
+		if !found {
+			if count := old.ExportedFuncCount + old.ExportedTypeCount; count > 0 {
+				breakages = append(breakages, APIBreakage{Package: old.ID, BeforeCount: count, Kind: APIBreakageRemoved})
+			}
+			continue
+		}
+		if current.ExportedFuncCount < old.ExportedFuncCount {
+			breakages = append(breakages, APIBreakage{Package: old.ID, BeforeCount: old.ExportedFuncCount,
+				AfterCount: current.ExportedFuncCount, Kind: APIBreakageExportedFuncs})
+			current.APIBreaking = true
+		}
+		if current.ExportedTypeCount < old.ExportedTypeCount {
+			breakages = append(breakages, APIBreakage{Package: old.ID, BeforeCount: old.ExportedTypeCount,
+				AfterCount: current.ExportedTypeCount, Kind: APIBreakageExportedTypes})
+			current.APIBreaking = true
+		}
+	}
+	// Within a package, the append order above already sorts the kinds.
+	sort.SliceStable(breakages, func(i, j int) bool { return breakages[i].Package < breakages[j].Package })
+	return breakages
+}
@@ -0,0 +1,45 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectAPIBreakages(t *testing.T) {
+	before := New()
+	after := New()
+	for _, node := range []*Node{
+		{ID: "ex/store", Kind: KindPackage, ExportedFuncCount: 5, ExportedTypeCount: 2},
+		{ID: "ex/api", Kind: KindPackage, ExportedFuncCount: 3, ExportedTypeCount: 1},
+		{ID: "ex/gone", Kind: KindPackage, ExportedFuncCount: 1, ExportedTypeCount: 1},
+		{ID: "ex/internal", Kind: KindPackage},
+		{ID: "func:ex/api::Get", Kind: KindFunction},
+	} {
+		if err := before.AddNode(node); err != nil {
+			t.Fatalf("AddNode(%q) failed: %v", node.ID, err)
+		}
+	}
+	for _, node := range []*Node{
+		{ID: "ex/store", Kind: KindPackage, ExportedFuncCount: 4, ExportedTypeCount: 1},
+		{ID: "ex/api", Kind: KindPackage, ExportedFuncCount: 4, ExportedTypeCount: 1},
+		{ID: "ex/new", Kind: KindPackage, ExportedFuncCount: 2},
+	} {
+		if err := after.AddNode(node); err != nil {
+			t.Fatalf("AddNode(%q) failed: %v", node.ID, err)
+		}
+	}
+
+	want := []APIBreakage{
+		{Package: "ex/gone", BeforeCount: 2, AfterCount: 0, Kind: APIBreakageRemoved},
+		{Package: "ex/store", BeforeCount: 5, AfterCount: 4, Kind: APIBreakageExportedFuncs},
+		{Package: "ex/store", BeforeCount: 2, AfterCount: 1, Kind: APIBreakageExportedTypes},
+	}
+	if got := DetectAPIBreakages(before, after); !reflect.DeepEqual(got, want) {
+		t.Errorf("DetectAPIBreakages() = %+v, want %+v", got, want)
+	}
+	for id, wantBreaking := range map[string]bool{"ex/store": true, "ex/api": false, "ex/new": false} {
+		if node, _ := after.Node(id); node.APIBreaking != wantBreaking {
+			t.Errorf("%s APIBreaking = %v, want %v", id, node.APIBreaking, wantBreaking)
+		}
+	}
+}
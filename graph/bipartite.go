@@ -0,0 +1,56 @@
+package graph
+
+// BipartiteCheck reports whether g, with edges of any kind taken as
+// undirected, is bipartite: whether its nodes split into two groups with no
+// edge inside either. It 2-colours each weakly connected component by
+// breadth-first search from its smallest ID, visiting neighbors in ID order,
+// so the result is deterministic.
+//
+// For a bipartite graph coloring maps every node to its group, 0 or 1. For
+// any other graph it holds only the nodes of the first odd cycle found, with
+// the colours the search gave them; two of them are adjacent and share a
+// colour, which is why no 2-colouring exists. A self-loop is an odd cycle of
+// one node. Import cycles of two packages are not odd and do not fail the
+// check.
+func BipartiteCheck(g *Graph) (isBipartite bool, coloring map[string]int) {
+	coloring = make(map[string]int, len(g.nodes))
+	parent := make(map[string]string)
+	for _, root := range g.sortedNodeIDs() {
+		if _, colored := coloring[root]; colored {
+			continue
+		}
+		coloring[root] = 0
+		queue := []string{root}
+		for len(queue) > 0 {
+			id := queue[0]
+			queue = queue[1:]
+			for _, neighbor := range g.Neighbors(id, Both, nil) {
+				color, colored := coloring[neighbor]
+				if !colored {
+					coloring[neighbor] = 1 - coloring[id]
+					parent[neighbor] = id
+					queue = append(queue, neighbor)
+					continue
+				}
+				if color == coloring[id] {
+					return false, oddCycleColoring(coloring, parent, id, neighbor)
+				}
+			}
+		}
+	}
+	return true, coloring
+}
+
+// oddCycleColoring returns the colours of the nodes on the odd cycle closed
+// by the edge between a and b, which the search coloured alike: the search
+// tree paths from a and b up to their closest common ancestor. Nodes of the
+// same colour in one search tree sit at the same depth, so the paths are
+// climbed in step.
+func oddCycleColoring(coloring map[string]int, parent map[string]string, a, b string) map[string]int {
+	cycle := map[string]int{a: coloring[a], b: coloring[b]}
+	for a != b {
+		a, b = parent[a], parent[b]
+		cycle[a], cycle[b] = coloring[a], coloring[b]
+	}
+	return cycle
+}
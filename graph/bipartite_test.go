@@ -0,0 +1,87 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBipartiteCheck(t *testing.T) {
+	tests := []struct {
+		name          string
+		nodes         []string
+		edges         [][2]string
+		wantBipartite bool
+		wantColoring  map[string]int
+	}{
+		{
+			name:          "empty",
+			wantBipartite: true,
+			wantColoring:  map[string]int{},
+		},
+		{
+			name:          "chain and isolated node",
+			nodes:         []string{"a", "b", "c", "d"},
+			edges:         [][2]string{{"a", "b"}, {"b", "c"}},
+			wantBipartite: true,
+			wantColoring:  map[string]int{"a": 0, "b": 1, "c": 0, "d": 0},
+		},
+		{
+			name:          "mutual imports are one undirected edge",
+			nodes:         []string{"a", "b", "c"},
+			edges:         [][2]string{{"a", "b"}, {"b", "a"}, {"b", "c"}},
+			wantBipartite: true,
+			wantColoring:  map[string]int{"a": 0, "b": 1, "c": 0},
+		},
+		{
+			name:          "even cycle",
+			nodes:         []string{"a", "b", "c", "d"},
+			edges:         [][2]string{{"a", "b"}, {"b", "c"}, {"c", "d"}, {"d", "a"}},
+			wantBipartite: true,
+			wantColoring:  map[string]int{"a": 0, "b": 1, "c": 0, "d": 1},
+		},
+		{
+			name:          "triangle ignoring direction",
+			nodes:         []string{"a", "b", "c", "d", "e"},
+			edges:         [][2]string{{"a", "b"}, {"a", "c"}, {"b", "c"}, {"c", "d"}},
+			wantBipartite: false,
+			wantColoring:  map[string]int{"a": 0, "b": 1, "c": 1},
+		},
+		{
+			name:          "pentagon with a tail",
+			nodes:         []string{"a", "b", "c", "d", "e", "x"},
+			edges:         [][2]string{{"a", "b"}, {"b", "c"}, {"c", "d"}, {"d", "e"}, {"e", "a"}, {"x", "a"}},
+			wantBipartite: false,
+			wantColoring:  map[string]int{"a": 0, "b": 1, "c": 0, "d": 0, "e": 1},
+		},
+		{
+			name:          "self-loop",
+			nodes:         []string{"a", "b"},
+			edges:         [][2]string{{"a", "b"}, {"a", "a"}},
+			wantBipartite: false,
+			wantColoring:  map[string]int{"a": 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := New()
+			for _, id := range tt.nodes {
+				if err := g.AddNode(&Node{ID: id, Kind: KindPackage}); err != nil {
+					t.Fatalf("AddNode() error = %v", err)
+				}
+			}
+			for _, edge := range tt.edges {
+				if err := g.AddEdge(&Edge{From: edge[0], To: edge[1], Kind: EdgeImport}); err != nil {
+					t.Fatalf("AddEdge() error = %v", err)
+				}
+			}
+
+			isBipartite, coloring := BipartiteCheck(g)
+			if isBipartite != tt.wantBipartite {
+				t.Errorf("BipartiteCheck() isBipartite = %v, want %v", isBipartite, tt.wantBipartite)
+			}
+			if !reflect.DeepEqual(coloring, tt.wantColoring) {
+				t.Errorf("BipartiteCheck() coloring = %v, want %v", coloring, tt.wantColoring)
+			}
+		})
+	}
+}
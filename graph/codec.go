@@ -0,0 +1,127 @@
+package graph
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Encoder writes a graph in a specific serialization format.
+type Encoder interface {
+	Encode(writer io.Writer, g *Graph) error
+}
+
+// Decoder reads a graph from a specific serialization format.
+type Decoder interface {
+	Decode(reader io.Reader) (*Graph, error)
+}
+
+// Format describes a serialization format available to commands via --format.
+type Format struct {
+	// Name is the value users pass to --format, e.g. "graphml".
+	Name string
+	// Extensions are the file extensions (with leading dot) that select this
+	// format when --format is not given, e.g. ".graphml".
+	Extensions []string
+	// NewEncoder returns an encoder with default options.
+	NewEncoder func() Encoder
+	// NewDecoder returns a decoder, or is nil for write-only formats.
+	NewDecoder func() Decoder
+}
+
+// CanDecode reports whether graphs can be read back from this format.
+func (f Format) CanDecode() bool {
+	return f.NewDecoder != nil
+}
+
+var formatRegistry = struct {
+	sync.RWMutex
+	byName      map[string]Format
+	byExtension map[string]string
+}{
+	byName:      make(map[string]Format),
+	byExtension: make(map[string]string),
+}
+
+// RegisterFormat makes format available by name and by file extension.
+// Returns an error if the name or any extension is already registered, so
+// plugins cannot silently replace built-in formats.
+func RegisterFormat(format Format) error {
+	if format.Name == "" {
+		return fmt.Errorf("format name must not be empty")
+	}
+	if format.NewEncoder == nil {
+		return fmt.Errorf("format %q has no encoder", format.Name)
+	}
+
+	formatRegistry.Lock()
+	defer formatRegistry.Unlock()
+
+	if _, exists := formatRegistry.byName[format.Name]; exists {
+		return fmt.Errorf("format %q is already registered", format.Name)
+	}
+	for _, extension := range format.Extensions {
+		if owner, exists := formatRegistry.byExtension[normalizeExtension(extension)]; exists {
+			return fmt.Errorf("extension %q is already registered by format %q", extension, owner)
+		}
+	}
+
+	formatRegistry.byName[format.Name] = format
+	for _, extension := range format.Extensions {
+		formatRegistry.byExtension[normalizeExtension(extension)] = format.Name
+	}
+	return nil
+}
+
+// LookupFormat returns the format registered under name.
+func LookupFormat(name string) (Format, bool) {
+	formatRegistry.RLock()
+	defer formatRegistry.RUnlock()
+
+	format, found := formatRegistry.byName[name]
+	return format, found
+}
+
+// FormatForFile returns the format registered for the extension of filePath.
+func FormatForFile(filePath string) (Format, bool) {
+	formatRegistry.RLock()
+	defer formatRegistry.RUnlock()
+
+	name, found := formatRegistry.byExtension[normalizeExtension(filepath.Ext(filePath))]
+	if !found {
+		return Format{}, false
+	}
+	return formatRegistry.byName[name], true
+}
+
+// Formats returns all registered formats sorted by name, for help text and completion.
+func Formats() []Format {
+	formatRegistry.RLock()
+	defer formatRegistry.RUnlock()
+
+	formats := make([]Format, 0, len(formatRegistry.byName))
+	for _, format := range formatRegistry.byName {
+		formats = append(formats, format)
+	}
+	sort.Slice(formats, func(i, j int) bool {
+		return formats[i].Name < formats[j].Name
+	})
+	return formats
+}
+
+// FormatNames returns the names of all registered formats sorted alphabetically.
+func FormatNames() []string {
+	formats := Formats()
+	names := make([]string, len(formats))
+	for i, format := range formats {
+		names[i] = format.Name
+	}
+	return names
+}
+
+func normalizeExtension(extension string) string {
+	return strings.ToLower(extension)
+}
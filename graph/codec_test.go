@@ -0,0 +1,78 @@
+package graph
+
+import (
+	"io"
+	"slices"
+	"testing"
+)
+
+type nopEncoder struct{}
+
+func (nopEncoder) Encode(writer io.Writer, g *Graph) error { return nil }
+
+func newNopEncoder() Encoder { return nopEncoder{} }
+
+func TestRegisterFormat(t *testing.T) {
+	err := RegisterFormat(Format{Name: "codec-test", Extensions: []string{".CodecTest"}, NewEncoder: newNopEncoder})
+	if err != nil {
+		t.Fatalf("RegisterFormat() error = %v", err)
+	}
+
+	format, found := LookupFormat("codec-test")
+	if !found {
+		t.Fatal("expected registered format to be found by name")
+	}
+	if format.CanDecode() {
+		t.Error("expected encoder-only format to report CanDecode() = false")
+	}
+
+	byFile, found := FormatForFile("/tmp/out.codectest")
+	if !found || byFile.Name != "codec-test" {
+		t.Errorf("FormatForFile() = %q, %v; want codec-test", byFile.Name, found)
+	}
+
+	if !slices.Contains(FormatNames(), "codec-test") {
+		t.Errorf("FormatNames() = %v, expected codec-test", FormatNames())
+	}
+}
+
+func TestRegisterFormat_RejectsDuplicates(t *testing.T) {
+	if err := RegisterFormat(Format{Name: "codec-dup", Extensions: []string{".dup"}, NewEncoder: newNopEncoder}); err != nil {
+		t.Fatalf("RegisterFormat() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		format Format
+	}{
+		{name: "duplicate name", format: Format{Name: "codec-dup", NewEncoder: newNopEncoder}},
+		{name: "duplicate extension", format: Format{Name: "codec-dup-2", Extensions: []string{".DUP"}, NewEncoder: newNopEncoder}},
+		{name: "empty name", format: Format{NewEncoder: newNopEncoder}},
+		{name: "missing encoder", format: Format{Name: "codec-no-encoder"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RegisterFormat(tt.format); err == nil {
+				t.Error("expected registration error")
+			}
+		})
+	}
+
+	if _, found := LookupFormat("codec-dup-2"); found {
+		t.Error("rejected registration must not be partially applied")
+	}
+}
+
+func TestFormats_SortedByName(t *testing.T) {
+	for _, name := range []string{"codec-sort-b", "codec-sort-a"} {
+		if err := RegisterFormat(Format{Name: name, NewEncoder: newNopEncoder}); err != nil {
+			t.Fatalf("RegisterFormat() error = %v", err)
+		}
+	}
+
+	names := FormatNames()
+	if !slices.IsSorted(names) {
+		t.Errorf("FormatNames() not sorted: %v", names)
+	}
+}
@@ -0,0 +1,74 @@
+package graph
+
+import (
+	"slices"
+	"strconv"
+)
+
+// KindGroup is the kind of the nodes Contract creates, each standing for a
+// group of nodes of the original graph.
+const KindGroup Kind = "group"
+
+// GroupSizeAttribute is the attribute recording how many nodes a KindGroup
+// node stands for.
+const GroupSizeAttribute = "group_size"
+
+// Contract returns a graph with one KindGroup node per distinct non-empty key
+// returned by key, e.g. func(n Node) string { return n.ModulePath } for a
+// module-level view. Each group node is identified and named by its key and
+// carries the union of its members' files and the members' module path when
+// they share one. Edges between groups are replaced by a single edge per
+// group pair and kind whose EdgeWeightAttribute and Multiplicity sum those
+// of the edges it replaces. Edges within a group are dropped, as are nodes whose key is
+// empty and their edges. Groups and edges keep the order in which they first
+// appear in g.
+func Contract(g *Graph, key func(Node) string) *Graph {
+	contracted := New()
+	contracted.Title = g.Title
+	contracted.Provenance = slices.Clone(g.Provenance)
+
+	groupOf := make(map[string]string, len(g.nodes))
+	sizes := make(map[string]int)
+	for _, node := range g.nodes {
+		group := key(*node)
+		if group == "" {
+			continue
+		}
+		groupOf[node.ID] = group
+		sizes[group]++
+
+		groupNode, found := contracted.nodeIndex[group]
+		if !found {
+			groupNode = &Node{ID: group, Kind: KindGroup, Name: group, ModulePath: node.ModulePath}
+			contracted.AddNode(groupNode)
+		}
+		if groupNode.ModulePath != node.ModulePath {
+			groupNode.ModulePath = ""
+		}
+		groupNode.Files = sortedUnion(groupNode.Files, node.Files)
+	}
+	for group, size := range sizes {
+		contracted.nodeIndex[group].SetAttribute(GroupSizeAttribute, strconv.Itoa(size))
+	}
+
+	edges := make(map[EdgeKey]*Edge)
+	for _, edge := range g.edges {
+		from, to := groupOf[edge.From], groupOf[edge.To]
+		if from == "" || to == "" || from == to {
+			continue
+		}
+		groupKey := EdgeKey{From: from, To: to, Kind: edge.Kind}
+		groupEdge, found := edges[groupKey]
+		if !found {
+			groupEdge = &Edge{From: from, To: to, Kind: edge.Kind, IsTestOnly: edge.IsTestOnly, Multiplicity: edge.Multiplicity}
+			groupEdge.SetAttribute(EdgeWeightAttribute, strconv.Itoa(edgeWeight(edge)))
+			edges[groupKey] = groupEdge
+			contracted.AddEdge(groupEdge)
+			continue
+		}
+		groupEdge.IsTestOnly = groupEdge.IsTestOnly && edge.IsTestOnly
+		groupEdge.Multiplicity += edge.Multiplicity
+		groupEdge.SetAttribute(EdgeWeightAttribute, strconv.Itoa(edgeWeight(groupEdge)+edgeWeight(edge)))
+	}
+	return contracted
+}
@@ -0,0 +1,58 @@
+package graph
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestContract(t *testing.T) {
+	g := New()
+	nodes := []*Node{
+		{ID: "example.com/a/api", Kind: KindPackage, ModulePath: "example.com/a", Files: []string{"/a/api/api.go"}},
+		{ID: "example.com/a/store", Kind: KindPackage, ModulePath: "example.com/a", Files: []string{"/a/store/store.go"}},
+		{ID: "example.com/b/util", Kind: KindPackage, ModulePath: "example.com/b"},
+		{ID: "example.com/b/log", Kind: KindPackage, ModulePath: "example.com/b"},
+		{ID: "fmt", Kind: KindPackage},
+	}
+	for _, node := range nodes {
+		if err := g.AddNode(node); err != nil {
+			t.Fatalf("AddNode() error = %v", err)
+		}
+	}
+	edges := []*Edge{
+		{From: "example.com/a/api", To: "example.com/a/store", Kind: EdgeImport},
+		{From: "example.com/a/api", To: "example.com/b/util", Kind: EdgeImport, Multiplicity: 2},
+		{From: "example.com/a/store", To: "example.com/b/log", Kind: EdgeImport, Multiplicity: 5, Attributes: map[string]string{EdgeWeightAttribute: "2"}},
+		{From: "example.com/a/store", To: "example.com/b/util", Kind: EdgeTestImport, IsTestOnly: true},
+		{From: "example.com/b/util", To: "fmt", Kind: EdgeImport},
+	}
+	for _, edge := range edges {
+		if err := g.AddEdge(edge); err != nil {
+			t.Fatalf("AddEdge() error = %v", err)
+		}
+	}
+
+	contracted := Contract(g, func(node Node) string { return node.ModulePath })
+
+	if len(contracted.Nodes()) != 2 {
+		t.Fatalf("expected 2 group nodes, got %d", len(contracted.Nodes()))
+	}
+	moduleA, found := contracted.Node("example.com/a")
+	if !found || moduleA.Kind != KindGroup || moduleA.ModulePath != "example.com/a" || moduleA.Attributes[GroupSizeAttribute] != "2" {
+		t.Errorf("unexpected group node %+v", moduleA)
+	}
+	if want := []string{"/a/api/api.go", "/a/store/store.go"}; !slices.Equal(moduleA.Files, want) {
+		t.Errorf("group files = %v, want %v", moduleA.Files, want)
+	}
+
+	got := contracted.Edges()
+	if len(got) != 2 {
+		t.Fatalf("expected an import and a test import edge between the groups, got %+v", got)
+	}
+	if got[0].Key() != (EdgeKey{From: "example.com/a", To: "example.com/b", Kind: EdgeImport}) || got[0].Attributes[EdgeWeightAttribute] != "3" || got[0].Multiplicity != 7 || got[0].IsTestOnly {
+		t.Errorf("import edge = %+v, want weight 3 and multiplicity 7", got[0])
+	}
+	if got[1].Kind != EdgeTestImport || got[1].Attributes[EdgeWeightAttribute] != "1" || !got[1].IsTestOnly {
+		t.Errorf("test import edge = %+v, want weight 1 and test-only", got[1])
+	}
+}
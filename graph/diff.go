@@ -0,0 +1,174 @@
+package graph
+
+import (
+	"slices"
+	"sort"
+)
+
+// DiffResult describes how one graph differs from another. It marshals to JSON.
+type DiffResult struct {
+	AddedNodes    []string     `json:"added_nodes"`
+	RemovedNodes  []string     `json:"removed_nodes"`
+	ModifiedNodes []NodeChange `json:"modified_nodes"`
+	AddedEdges    []EdgeKey    `json:"added_edges"`
+	RemovedEdges  []EdgeKey    `json:"removed_edges"`
+	ModifiedEdges []EdgeChange `json:"modified_edges"`
+}
+
+// EdgeKey identifies an edge by its endpoints and kind.
+type EdgeKey struct {
+	From string   `json:"from"`
+	To   string   `json:"to"`
+	Kind EdgeKind `json:"kind"`
+}
+
+// AttributeChange is a single attribute whose value differs between graphs.
+// An empty Old or New value means the attribute is absent on that side.
+type AttributeChange struct {
+	Attribute string `json:"attribute"`
+	Old       string `json:"old"`
+	New       string `json:"new"`
+}
+
+// NodeChange lists the attribute changes of a node present in both graphs.
+type NodeChange struct {
+	ID      string            `json:"id"`
+	Changes []AttributeChange `json:"changes"`
+}
+
+// EdgeChange lists the attribute changes of an edge present in both graphs.
+type EdgeChange struct {
+	EdgeKey
+	Changes []AttributeChange `json:"changes"`
+}
+
+// DiffOptions configures Diff.
+type DiffOptions struct {
+	// IgnoreAttributes names attributes excluded from modification detection,
+	// e.g. position attributes so that moving code does not count as a change.
+	IgnoreAttributes []string
+}
+
+// HasChanges reports whether the compared graphs differ.
+func (d *DiffResult) HasChanges() bool {
+	return len(d.AddedNodes)+len(d.RemovedNodes)+len(d.ModifiedNodes)+
+		len(d.AddedEdges)+len(d.RemovedEdges)+len(d.ModifiedEdges) > 0
+}
+
+// Diff compares oldGraph to newGraph using default options.
+func Diff(oldGraph, newGraph *Graph) *DiffResult {
+	return DiffWithOptions(oldGraph, newGraph, DiffOptions{})
+}
+
+// DiffWithOptions compares oldGraph to newGraph. Nodes are matched by ID and
+// edges by EdgeKey; matched elements are modified when any attribute not in
+// options.IgnoreAttributes differs. All result slices are sorted.
+func DiffWithOptions(oldGraph, newGraph *Graph, options DiffOptions) *DiffResult {
+	result := &DiffResult{}
+	diffNodes(oldGraph, newGraph, options, result)
+	diffEdges(oldGraph, newGraph, options, result)
+	return result
+}
+
+func diffNodes(oldGraph, newGraph *Graph, options DiffOptions, result *DiffResult) {
+	for _, oldNode := range oldGraph.Nodes() {
+		newNode, found := newGraph.Node(oldNode.ID)
+		if !found {
+			result.RemovedNodes = append(result.RemovedNodes, oldNode.ID)
+			continue
+		}
+		changes := diffProperties(oldNode.Properties(), newNode.Properties(), options.IgnoreAttributes)
+		if len(changes) > 0 {
+			result.ModifiedNodes = append(result.ModifiedNodes, NodeChange{ID: oldNode.ID, Changes: changes})
+		}
+	}
+	for _, newNode := range newGraph.Nodes() {
+		if _, found := oldGraph.Node(newNode.ID); !found {
+			result.AddedNodes = append(result.AddedNodes, newNode.ID)
+		}
+	}
+
+	sort.Strings(result.AddedNodes)
+	sort.Strings(result.RemovedNodes)
+	sort.Slice(result.ModifiedNodes, func(i, j int) bool {
+		return result.ModifiedNodes[i].ID < result.ModifiedNodes[j].ID
+	})
+}
+
+func diffEdges(oldGraph, newGraph *Graph, options DiffOptions, result *DiffResult) {
+	oldEdges := indexEdges(oldGraph)
+	newEdges := indexEdges(newGraph)
+
+	for key, oldEdge := range oldEdges {
+		newEdge, found := newEdges[key]
+		if !found {
+			result.RemovedEdges = append(result.RemovedEdges, key)
+			continue
+		}
+		changes := diffProperties(oldEdge.Properties(), newEdge.Properties(), options.IgnoreAttributes)
+		if len(changes) > 0 {
+			result.ModifiedEdges = append(result.ModifiedEdges, EdgeChange{EdgeKey: key, Changes: changes})
+		}
+	}
+	for key := range newEdges {
+		if _, found := oldEdges[key]; !found {
+			result.AddedEdges = append(result.AddedEdges, key)
+		}
+	}
+
+	sortEdgeKeys(result.AddedEdges)
+	sortEdgeKeys(result.RemovedEdges)
+	sort.Slice(result.ModifiedEdges, func(i, j int) bool {
+		return result.ModifiedEdges[i].EdgeKey.less(result.ModifiedEdges[j].EdgeKey)
+	})
+}
+
+// indexEdges maps each edge key to its first edge; parallel duplicates are compared once.
+func indexEdges(g *Graph) map[EdgeKey]*Edge {
+	edges := make(map[EdgeKey]*Edge, len(g.Edges()))
+	for _, edge := range g.Edges() {
+		key := edge.Key()
+		if _, exists := edges[key]; !exists {
+			edges[key] = edge
+		}
+	}
+	return edges
+}
+
+func diffProperties(oldProperties, newProperties map[string]string, ignored []string) []AttributeChange {
+	names := make(map[string]bool)
+	for name := range oldProperties {
+		names[name] = true
+	}
+	for name := range newProperties {
+		names[name] = true
+	}
+
+	var changes []AttributeChange
+	for name := range names {
+		if slices.Contains(ignored, name) || oldProperties[name] == newProperties[name] {
+			continue
+		}
+		changes = append(changes, AttributeChange{Attribute: name, Old: oldProperties[name], New: newProperties[name]})
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Attribute < changes[j].Attribute
+	})
+	return changes
+}
+
+func sortEdgeKeys(keys []EdgeKey) {
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].less(keys[j])
+	})
+}
+
+func (k EdgeKey) less(other EdgeKey) bool {
+	if k.From != other.From {
+		return k.From < other.From
+	}
+	if k.To != other.To {
+		return k.To < other.To
+	}
+	return k.Kind < other.Kind
+}
@@ -0,0 +1,114 @@
+package graph
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func newDiffTestGraph(t *testing.T, nodes []*Node, edges []Edge) *Graph {
+	t.Helper()
+
+	g := New()
+	for _, node := range nodes {
+		if err := g.AddNode(node); err != nil {
+			t.Fatalf("AddNode() error = %v", err)
+		}
+	}
+	for i := range edges {
+		if err := g.AddEdge(&edges[i]); err != nil {
+			t.Fatalf("AddEdge() error = %v", err)
+		}
+	}
+	return g
+}
+
+func TestDiff(t *testing.T) {
+	oldGraph := newDiffTestGraph(t,
+		[]*Node{
+			{ID: "a", Kind: KindPackage, Name: "a"},
+			{ID: "b", Kind: KindPackage, Name: "b", InterfaceCount: 1},
+			{ID: "c", Kind: KindPackage, Name: "c"},
+		},
+		[]Edge{{From: "a", To: "b", Kind: EdgeImport}, {From: "a", To: "c", Kind: EdgeImport}},
+	)
+	newGraph := newDiffTestGraph(t,
+		[]*Node{
+			{ID: "a", Kind: KindPackage, Name: "a"},
+			{ID: "b", Kind: KindPackage, Name: "bee", InterfaceCount: 2},
+			{ID: "d", Kind: KindPackage, Name: "d"},
+		},
+		[]Edge{{From: "a", To: "b", Kind: EdgeImport}, {From: "a", To: "d", Kind: EdgeImport}},
+	)
+
+	result := Diff(oldGraph, newGraph)
+
+	if !reflect.DeepEqual(result.AddedNodes, []string{"d"}) {
+		t.Errorf("AddedNodes = %v", result.AddedNodes)
+	}
+	if !reflect.DeepEqual(result.RemovedNodes, []string{"c"}) {
+		t.Errorf("RemovedNodes = %v", result.RemovedNodes)
+	}
+	wantModified := []NodeChange{{ID: "b", Changes: []AttributeChange{
+		{Attribute: "interfaceCount", Old: "1", New: "2"},
+		{Attribute: "name", Old: "b", New: "bee"},
+	}}}
+	if !reflect.DeepEqual(result.ModifiedNodes, wantModified) {
+		t.Errorf("ModifiedNodes = %+v, want %+v", result.ModifiedNodes, wantModified)
+	}
+	if !reflect.DeepEqual(result.AddedEdges, []EdgeKey{{From: "a", To: "d", Kind: EdgeImport}}) {
+		t.Errorf("AddedEdges = %v", result.AddedEdges)
+	}
+	if !reflect.DeepEqual(result.RemovedEdges, []EdgeKey{{From: "a", To: "c", Kind: EdgeImport}}) {
+		t.Errorf("RemovedEdges = %v", result.RemovedEdges)
+	}
+	if len(result.ModifiedEdges) != 0 {
+		t.Errorf("ModifiedEdges = %v, want none", result.ModifiedEdges)
+	}
+	if !result.HasChanges() {
+		t.Error("HasChanges() = false, want true")
+	}
+}
+
+func TestDiffWithOptions_IgnoreAttributes(t *testing.T) {
+	oldGraph := newDiffTestGraph(t, []*Node{{ID: "a", Name: "a", InterfaceCount: 1}}, nil)
+	newGraph := newDiffTestGraph(t, []*Node{{ID: "a", Name: "a", InterfaceCount: 5}}, nil)
+
+	if result := Diff(oldGraph, newGraph); len(result.ModifiedNodes) != 1 {
+		t.Fatalf("expected modification without ignore list, got %+v", result.ModifiedNodes)
+	}
+
+	result := DiffWithOptions(oldGraph, newGraph, DiffOptions{IgnoreAttributes: []string{"interfaceCount"}})
+	if result.HasChanges() {
+		t.Errorf("expected ignored attribute not to count as a change, got %+v", result)
+	}
+}
+
+func TestDiff_IdenticalGraphs(t *testing.T) {
+	g := newDiffTestGraph(t, []*Node{{ID: "a"}, {ID: "b"}}, []Edge{{From: "a", To: "b", Kind: EdgeImport}})
+
+	if result := Diff(g, g); result.HasChanges() {
+		t.Errorf("expected no changes, got %+v", result)
+	}
+}
+
+func TestDiffResult_JSON(t *testing.T) {
+	oldGraph := newDiffTestGraph(t, []*Node{{ID: "a"}}, nil)
+	newGraph := newDiffTestGraph(t, []*Node{{ID: "a"}, {ID: "b"}}, []Edge{{From: "b", To: "a", Kind: EdgeImport}})
+
+	encoded, err := json.Marshal(Diff(oldGraph, newGraph))
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	var decoded DiffResult
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(decoded.AddedNodes, []string{"b"}) {
+		t.Errorf("AddedNodes after round trip = %v", decoded.AddedNodes)
+	}
+	if !reflect.DeepEqual(decoded.AddedEdges, []EdgeKey{{From: "b", To: "a", Kind: EdgeImport}}) {
+		t.Errorf("AddedEdges after round trip = %v", decoded.AddedEdges)
+	}
+}
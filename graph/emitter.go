@@ -0,0 +1,128 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Emitter receives a graph one element at a time, so extraction can stream to
+// a sink such as an NDJSON writer without holding the whole graph in memory.
+//
+// Contract:
+//   - EmitEdge may be called before the nodes it references are emitted; an
+//     edge only needs its endpoints to exist once Close is called.
+//   - Emitters that stream (e.g. NDJSON) do not check endpoints at all, so
+//     their output may contain dangling edges if the producer is buggy; check
+//     the output afterwards by decoding it into a Graph.
+//   - Close must be called exactly once, after the last element. It reports
+//     any deferred validation failure and flushes buffered output.
+type Emitter interface {
+	EmitNode(node Node) error
+	EmitEdge(edge Edge) error
+	Close() error
+}
+
+// Builder is the in-memory Emitter: it assembles the emitted elements into a
+// Graph. Nodes are added immediately; edges are held until Close so they may
+// arrive before their endpoints and still keep their emission order.
+//
+// A Builder is safe for concurrent use. The resulting graph contains the same
+// nodes and edges whatever the interleaving of concurrent emitters, but
+// Nodes() and Edges() follow emission order, so callers that need a
+// deterministic order must emit deterministically (as extract.Run does).
+// A single mutex guards the graph: emitting is cheap compared to extraction,
+// and the graph's ordered slices could not be split across shards anyway.
+//
+// Emitted strings are interned under the same mutex, so a large graph holds
+// each distinct ID, module path and attribute key or value only once.
+type Builder struct {
+	mutex    sync.Mutex
+	graph    *Graph
+	edges    []*Edge
+	interner *interner
+}
+
+// NewBuilder returns a Builder for an empty graph.
+func NewBuilder() *Builder {
+	return &Builder{graph: New(), interner: newInterner()}
+}
+
+// EmitNode adds node to the graph. Returns an error for duplicate node IDs.
+func (b *Builder) EmitNode(node Node) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.interner.internNode(&node)
+	return b.graph.AddNode(&node)
+}
+
+// EmitEdge records edge to be added when the Builder is closed.
+func (b *Builder) EmitEdge(edge Edge) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.interner.internEdge(&edge)
+	b.edges = append(b.edges, &edge)
+	return nil
+}
+
+// DanglingEdgeError reports an edge Builder.Close skipped because one of its
+// endpoints was never emitted. Index is the edge's position among the edges
+// emitted to the Builder, so callers can tell who emitted it.
+type DanglingEdgeError struct {
+	Edge  Edge
+	Index int
+	Err   error
+}
+
+func (e *DanglingEdgeError) Error() string {
+	return fmt.Sprintf("edge %s -> %s: %v", e.Edge.From, e.Edge.To, e.Err)
+}
+
+func (e *DanglingEdgeError) Unwrap() error {
+	return e.Err
+}
+
+// Close adds the recorded edges in emission order. Edges whose endpoints were
+// never emitted are skipped and reported together in the returned error, one
+// *DanglingEdgeError each.
+func (b *Builder) Close() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	var danglingEdges []error
+	for i, edge := range b.edges {
+		if err := b.graph.AddEdge(edge); err != nil {
+			danglingEdges = append(danglingEdges, &DanglingEdgeError{Edge: *edge, Index: i, Err: err})
+		}
+	}
+	b.edges = nil
+	// The graph keeps the canonical strings; the lookup table is no longer needed.
+	b.interner = newInterner()
+	return errors.Join(danglingEdges...)
+}
+
+// Graph returns the assembled graph. It is complete only after Close.
+func (b *Builder) Graph() *Graph {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.graph
+}
+
+// EmitTo streams every node, then every edge, in insertion order to emitter
+// and closes it.
+func (g *Graph) EmitTo(emitter Emitter) error {
+	for _, node := range g.nodes {
+		if err := emitter.EmitNode(*node); err != nil {
+			return err
+		}
+	}
+	for _, edge := range g.edges {
+		if err := emitter.EmitEdge(*edge); err != nil {
+			return err
+		}
+	}
+	return emitter.Close()
+}
@@ -0,0 +1,79 @@
+package graph
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBuilder_EdgesBeforeNodes(t *testing.T) {
+	builder := NewBuilder()
+
+	steps := []func() error{
+		func() error { return builder.EmitNode(Node{ID: "a"}) },
+		func() error { return builder.EmitEdge(Edge{From: "a", To: "b", Kind: EdgeImport}) },
+		func() error { return builder.EmitEdge(Edge{From: "a", To: "c", Kind: EdgeImport}) },
+		func() error { return builder.EmitNode(Node{ID: "c"}) },
+		func() error { return builder.EmitNode(Node{ID: "b"}) },
+	}
+	for i, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("step %d failed: %v", i, err)
+		}
+	}
+	if err := builder.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	edges := builder.Graph().Edges()
+	if len(edges) != 2 || edges[0].To != "b" || edges[1].To != "c" {
+		t.Errorf("expected edges in emission order a->b, a->c, got %+v", edges)
+	}
+}
+
+func TestBuilder_Errors(t *testing.T) {
+	builder := NewBuilder()
+	if err := builder.EmitNode(Node{ID: "a"}); err != nil {
+		t.Fatalf("EmitNode() error = %v", err)
+	}
+	if err := builder.EmitNode(Node{ID: "a"}); !errors.Is(err, ErrDuplicateNode) {
+		t.Errorf("expected ErrDuplicateNode, got %v", err)
+	}
+
+	_ = builder.EmitEdge(Edge{From: "a", To: "missing"})
+	_ = builder.EmitEdge(Edge{From: "ghost", To: "a"})
+	err := builder.Close()
+	if err == nil {
+		t.Fatal("expected dangling edges to be reported on Close")
+	}
+	if !strings.Contains(err.Error(), "missing") || !strings.Contains(err.Error(), "ghost") {
+		t.Errorf("expected both dangling edges in error, got %v", err)
+	}
+	var dangling *DanglingEdgeError
+	if !errors.As(err, &dangling) || dangling.Index != 0 || dangling.Edge.To != "missing" {
+		t.Errorf("expected the first dangling edge as a *DanglingEdgeError at index 0, got %+v", dangling)
+	}
+	if len(builder.Graph().Edges()) != 0 {
+		t.Errorf("expected dangling edges to be skipped, got %+v", builder.Graph().Edges())
+	}
+}
+
+func TestGraph_EmitTo(t *testing.T) {
+	original := New()
+	_ = original.AddNode(&Node{ID: "a", Name: "a"})
+	_ = original.AddNode(&Node{ID: "b"})
+	_ = original.AddEdge(&Edge{From: "a", To: "b", Kind: EdgeImport})
+
+	builder := NewBuilder()
+	if err := original.EmitTo(builder); err != nil {
+		t.Fatalf("EmitTo() error = %v", err)
+	}
+
+	copied := builder.Graph()
+	if len(copied.Nodes()) != 2 || len(copied.Edges()) != 1 {
+		t.Fatalf("expected 2 nodes and 1 edge, got %d and %d", len(copied.Nodes()), len(copied.Edges()))
+	}
+	if node, _ := copied.Node("a"); node == original.Nodes()[0] || node.Name != "a" {
+		t.Errorf("expected an equal copy of node a, got %+v", node)
+	}
+}
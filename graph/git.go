@@ -0,0 +1,89 @@
+package graph
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// EnrichWithGitFrequency sets the ChangeFrequency of every node with files to
+// the number of commits in the git repository at repoRoot touching any of
+// them, counting only commits after since when it names a ref (e.g. a tag or
+// "HEAD~100") and the whole history when it is empty. It reads the history
+// with a single git log and fails on git errors, such as an unknown ref.
+// Files outside repoRoot count no commits.
+func EnrichWithGitFrequency(g *Graph, repoRoot string, since string) error {
+	commits, err := commitsByFile(repoRoot, since)
+	if err != nil {
+		return err
+	}
+	resolvedRoot, _ := filepath.EvalSymlinks(repoRoot)
+	for _, node := range g.nodes {
+		if len(node.Files) == 0 {
+			continue
+		}
+		touching := make(map[int]bool)
+		for _, file := range node.Files {
+			for _, commit := range commits[repoRelative(repoRoot, resolvedRoot, file)] {
+				touching[commit] = true
+			}
+		}
+		node.ChangeFrequency = len(touching)
+	}
+	return nil
+}
+
+// commitsByFile runs git log once over the repository at repoRoot and returns,
+// for every file path relative to it, the indexes of the commits touching it.
+func commitsByFile(repoRoot, since string) (map[string][]int, error) {
+	// The NUL prefix tells commit lines apart from file names.
+	args := []string{"-C", repoRoot, "-c", "core.quotePath=false", "log", "--name-only", "--format=%x00%H"}
+	if since != "" {
+		args = append(args, since+"..HEAD")
+	}
+	var stderr bytes.Buffer
+	command := exec.Command("git", args...)
+	command.Stderr = &stderr
+	output, err := command.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log in %s: %w: %s", repoRoot, err, strings.TrimSpace(stderr.String()))
+	}
+
+	commits := make(map[string][]int)
+	commit := -1
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "\x00"):
+			commit++
+		case line != "" && commit >= 0:
+			commits[line] = append(commits[line], commit)
+		}
+	}
+	return commits, scanner.Err()
+}
+
+// repoRelative returns file relative to the repository root, as git log names
+// it, trying the root with symlinks resolved when file is not under repoRoot
+// as given.
+func repoRelative(repoRoot, resolvedRoot, file string) string {
+	for _, root := range []string{repoRoot, resolvedRoot} {
+		if root == "" {
+			continue
+		}
+		if relative, err := filepath.Rel(root, file); err == nil && filepath.IsLocal(relative) {
+			return filepath.ToSlash(relative)
+		}
+	}
+	if resolvedFile, err := filepath.EvalSymlinks(file); err == nil && resolvedRoot != "" {
+		if relative, err := filepath.Rel(resolvedRoot, resolvedFile); err == nil && filepath.IsLocal(relative) {
+			return filepath.ToSlash(relative)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,86 @@
+package graph
+
+import (
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// gitCommit writes content to name in dir and commits it.
+func gitCommit(t *testing.T, dir, name, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{{"add", name}, {"commit", "-q", "-m", "change " + name}} {
+		command := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if output, err := command.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+	}
+}
+
+func TestEnrichWithGitFrequency(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	if output, err := exec.Command("git", "init", "-q", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, output)
+	}
+	gitCommit(t, dir, "a.go", "package a\n")
+	gitCommit(t, dir, "b.go", "package b\n")
+	if output, err := exec.Command("git", "-C", dir, "tag", "base").CombinedOutput(); err != nil {
+		t.Fatalf("git tag: %v\n%s", err, output)
+	}
+	gitCommit(t, dir, "a.go", "package a\n\n// Changed.\n")
+	gitCommit(t, dir, "c.go", "package a\n")
+	if err := os.Mkdir(filepath.Join(dir, "d"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	gitCommit(t, dir, "d/d.go", "package d\n")
+	gitCommit(t, dir, "d/d.go", "package d\n\n// Changed.\n")
+
+	newGraph := func() *Graph {
+		g := New()
+		g.AddNode(&Node{ID: "a", Kind: KindPackage, Files: []string{filepath.Join(dir, "a.go"), filepath.Join(dir, "c.go")}})
+		g.AddNode(&Node{ID: "b", Kind: KindPackage, Files: []string{filepath.Join(dir, "b.go")}})
+		g.AddNode(&Node{ID: "d", Kind: KindPackage, Files: []string{filepath.Join(dir, "d", "d.go")}})
+		g.AddNode(&Node{ID: "d.F", Kind: KindFunction, Files: []string{filepath.Join(dir, "d", "d.go")}})
+		g.AddNode(&Node{ID: "fmt", Kind: KindPackage})
+		g.AddNode(&Node{ID: "outside", Kind: KindPackage, Files: []string{filepath.Join(t.TempDir(), "a.go")}})
+		return g
+	}
+	frequencies := func(g *Graph) map[string]int {
+		result := make(map[string]int)
+		for _, node := range g.Nodes() {
+			result[node.ID] = node.ChangeFrequency
+		}
+		return result
+	}
+
+	g := newGraph()
+	if err := EnrichWithGitFrequency(g, dir, ""); err != nil {
+		t.Fatalf("EnrichWithGitFrequency() error = %v", err)
+	}
+	want := map[string]int{"a": 3, "b": 1, "d": 2, "d.F": 2, "fmt": 0, "outside": 0}
+	if got := frequencies(g); !maps.Equal(got, want) {
+		t.Errorf("change frequencies = %v, want %v", got, want)
+	}
+
+	g = newGraph()
+	if err := EnrichWithGitFrequency(g, dir, "base"); err != nil {
+		t.Fatalf("EnrichWithGitFrequency() error = %v", err)
+	}
+	want = map[string]int{"a": 2, "b": 0, "d": 2, "d.F": 2, "fmt": 0, "outside": 0}
+	if got := frequencies(g); !maps.Equal(got, want) {
+		t.Errorf("change frequencies since base = %v, want %v", got, want)
+	}
+
+	if err := EnrichWithGitFrequency(newGraph(), dir, "no-such-ref"); err == nil {
+		t.Error("expected an unknown ref to fail")
+	}
+}
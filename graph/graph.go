@@ -0,0 +1,504 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Kind identifies what a node represents.
+type Kind string
+
+const (
+	KindPackage  Kind = "package"
+	KindFile     Kind = "file"
+	KindFunction Kind = "function"
+)
+
+// EdgeKind identifies the relationship an edge represents.
+type EdgeKind string
+
+const (
+	EdgeImport EdgeKind = "import"
+	// EdgeTestImport is an import declared only in a package's _test.go files.
+	EdgeTestImport EdgeKind = "test_import"
+	// EdgeCall is a call from one function node to another.
+	EdgeCall EdgeKind = "call"
+	// EdgeTestCall is a call made from a test function, kept apart from
+	// EdgeCall so test coverage does not read as production coupling.
+	EdgeTestCall EdgeKind = "test_call"
+)
+
+// Node is a vertex in the dependency graph. IDs follow the scheme documented in id.go.
+type Node struct {
+	ID         string
+	Kind       Kind
+	Name       string
+	ModulePath string
+	Files      []string
+
+	// DirPath is the directory containing the package's files. Like Files it
+	// is machine-specific and left out of Properties.
+	DirPath string
+
+	// Rank is the node's position in dependency order, 0 for the deepest
+	// dependency, as computed by Ranks, or RankCyclic for nodes in an import
+	// cycle. It is only set on package nodes. Like DirPath it describes the
+	// node's place in one graph and is left out of Properties.
+	Rank int
+
+	// ModuleVersion is the version of the module providing the package, e.g.
+	// "v1.2.3". It is empty for the main module and replaced modules.
+	ModuleVersion string
+
+	// GoVersion is the minimum Go version required by the go directive of the
+	// providing module's go.mod, e.g. "1.21".
+	GoVersion string
+
+	// InterfaceCount and ConcreteTypeCount count the package-level named types,
+	// the inputs to the abstractness metric.
+	InterfaceCount    int
+	ConcreteTypeCount int
+
+	// ExportedFuncCount and ExportedTypeCount count the exported functions,
+	// methods of exported types included, and exported types of the package's
+	// non-test files: a cheap proxy for the size of its API.
+	ExportedFuncCount int
+	ExportedTypeCount int
+
+	// APIBreaking marks packages whose exported API shrank compared to an
+	// earlier snapshot, as set by DetectAPIBreakages.
+	APIBreaking bool
+
+	// HasMainFunc is true for packages declaring func main(), i.e. executables.
+	HasMainFunc bool
+
+	// Testable is true for packages with at least one TestXxx function. It is
+	// false for packages without tests or loaded without them.
+	Testable bool
+
+	// UnsafeUsage is true for packages importing unsafe, which need special
+	// review in security audits.
+	UnsafeUsage bool
+
+	// ProtobufGenerated is true for packages protoc-gen-go generated, which
+	// hold wire types that business logic should reach through adapters.
+	ProtobufGenerated bool
+
+	// EmbedCount is the number of files the package embeds with //go:embed,
+	// an implicit contribution to binary size.
+	EmbedCount int
+
+	// ChangeFrequency is the number of commits touching the node's files, as
+	// counted by EnrichWithGitFrequency.
+	ChangeFrequency int
+
+	// GoCGO is true for packages that use cgo and therefore need a C toolchain to build.
+	GoCGO bool
+
+	// TestDependencies lists the import paths, internal or external, that only
+	// the package's test files import.
+	TestDependencies []string
+
+	// TestFramework is the test framework the package's tests use: "ginkgo",
+	// "testify", "gomock" or "standard" for plain testing. It is empty for
+	// packages without tests or loaded without them.
+	TestFramework string
+
+	// BuildConstraints lists the distinct //go:build expressions guarding the
+	// package's files, e.g. "linux && amd64". Empty means always compiled.
+	BuildConstraints []string
+
+	// Attributes holds computed values such as metrics, keyed by snake_case
+	// name (e.g. "fan_in"). Values are strings so every format can carry them.
+	Attributes map[string]string
+}
+
+// SetAttribute stores value under name, allocating Attributes if needed.
+func (n *Node) SetAttribute(name, value string) {
+	if n.Attributes == nil {
+		n.Attributes = make(map[string]string)
+	}
+	n.Attributes[name] = value
+}
+
+// Label returns a short human-readable name for the node.
+// Package nodes are labelled by their path relative to the module root.
+func (n *Node) Label() string {
+	if n.ModulePath == "" || n.ID == n.ModulePath {
+		return n.ID
+	}
+	if relativePath, found := strings.CutPrefix(n.ID, n.ModulePath+"/"); found {
+		return relativePath
+	}
+	return n.ID
+}
+
+// Properties returns the node's comparable attributes as strings keyed by name,
+// including everything in Attributes. Empty values are omitted. The ID and file
+// list are not included, nor is DirPath.
+func (n *Node) Properties() map[string]string {
+	properties := map[string]string{
+		"kind":              string(n.Kind),
+		"name":              n.Name,
+		"module":            n.ModulePath,
+		"moduleVersion":     n.ModuleVersion,
+		"goVersion":         n.GoVersion,
+		"fileCount":         strconv.Itoa(len(n.Files)),
+		"interfaceCount":    strconv.Itoa(n.InterfaceCount),
+		"concreteTypeCount": strconv.Itoa(n.ConcreteTypeCount),
+		"exportedFuncCount": strconv.Itoa(n.ExportedFuncCount),
+		"exportedTypeCount": strconv.Itoa(n.ExportedTypeCount),
+		"apiBreaking":       strconv.FormatBool(n.APIBreaking),
+		"hasMainFunc":       strconv.FormatBool(n.HasMainFunc),
+		"testable":          strconv.FormatBool(n.Testable),
+		"unsafeUsage":       strconv.FormatBool(n.UnsafeUsage),
+		"protobufGenerated": strconv.FormatBool(n.ProtobufGenerated),
+		"requiresCGO":       strconv.FormatBool(n.GoCGO),
+		"embedCount":        strconv.Itoa(n.EmbedCount),
+		"changeFrequency":   strconv.Itoa(n.ChangeFrequency),
+		"testDependencies":  strings.Join(n.TestDependencies, ","),
+		"testFramework":     n.TestFramework,
+		"buildConstraints":  strings.Join(n.BuildConstraints, ","),
+	}
+	for name, value := range n.Attributes {
+		properties[name] = value
+	}
+	return withoutEmptyValues(properties)
+}
+
+// Edge is a directed relationship between two nodes identified by ID.
+type Edge struct {
+	From string
+	To   string
+	Kind EdgeKind
+
+	// IsTestOnly marks edges that exist only because of test files, such as
+	// EdgeTestImport edges, so they can be styled or removed as a group.
+	IsTestOnly bool
+
+	// Multiplicity is the number of distinct symbols of the target package
+	// the source package uses, 0 when unknown (loaded without type
+	// information). A high count means strong coupling; an import used for
+	// a single symbol may be replaceable by a narrower interface.
+	Multiplicity int
+
+	// Attributes holds computed values such as the merge weight, keyed by
+	// snake_case name like Node.Attributes.
+	Attributes map[string]string
+}
+
+// SetAttribute stores value under name, allocating Attributes if needed.
+func (e *Edge) SetAttribute(name, value string) {
+	if e.Attributes == nil {
+		e.Attributes = make(map[string]string)
+	}
+	e.Attributes[name] = value
+}
+
+// Key returns the identity of the edge used to match edges across graphs.
+func (e *Edge) Key() EdgeKey {
+	return EdgeKey{From: e.From, To: e.To, Kind: e.Kind}
+}
+
+// Properties returns the edge's comparable attributes as strings keyed by name.
+// Endpoints and kind are part of the key and not included. Empty values are omitted.
+func (e *Edge) Properties() map[string]string {
+	properties := make(map[string]string, len(e.Attributes)+1)
+	for name, value := range e.Attributes {
+		properties[name] = value
+	}
+	if e.Multiplicity > 0 {
+		properties["multiplicity"] = strconv.Itoa(e.Multiplicity)
+	}
+	return withoutEmptyValues(properties)
+}
+
+func withoutEmptyValues(properties map[string]string) map[string]string {
+	for name, value := range properties {
+		if value == "" {
+			delete(properties, name)
+		}
+	}
+	return properties
+}
+
+// ErrDuplicateNode is returned when adding a node whose ID is already in the graph.
+var ErrDuplicateNode = errors.New("duplicate node")
+
+// Graph is a directed graph of nodes and edges.
+// Nodes and edges are kept in insertion order.
+type Graph struct {
+	// Title names the graph in formats that support graph-level names.
+	Title string
+	// Provenance lists, sorted, the titles of the graphs merged into this one.
+	Provenance []string
+	// SchemaVersion is the schema version a decoded graph was read from; see
+	// Schema.Upgrade. Encoders always write the current SchemaVersion.
+	SchemaVersion string
+	// Metadata holds graph-level analysis results keyed by snake_case name,
+	// such as the import cycles found while parsing.
+	Metadata map[string]string
+
+	nodes     []*Node
+	nodeIndex map[string]*Node
+	dirIndex  map[string]*Node
+	edges     []*Edge
+	outgoing  map[string][]*Edge
+	incoming  map[string][]*Edge
+}
+
+// SetMetadata stores value under name, allocating Metadata if needed.
+func (g *Graph) SetMetadata(name, value string) {
+	if g.Metadata == nil {
+		g.Metadata = make(map[string]string)
+	}
+	g.Metadata[name] = value
+}
+
+// New returns an empty graph.
+func New() *Graph {
+	return &Graph{
+		nodeIndex: make(map[string]*Node),
+		dirIndex:  make(map[string]*Node),
+		outgoing:  make(map[string][]*Edge),
+		incoming:  make(map[string][]*Edge),
+	}
+}
+
+// AddNode adds node to the graph. Returns an error if a node with the same ID exists.
+func (g *Graph) AddNode(node *Node) error {
+	if _, exists := g.nodeIndex[node.ID]; exists {
+		return fmt.Errorf("%w %q", ErrDuplicateNode, node.ID)
+	}
+	g.nodes = append(g.nodes, node)
+	g.nodeIndex[node.ID] = node
+	g.indexDir(node)
+	return nil
+}
+
+// indexDir records node under its DirPath unless another node already
+// claimed that directory, as an external test package shares its package's.
+func (g *Graph) indexDir(node *Node) {
+	if node.DirPath == "" {
+		return
+	}
+	dir := filepath.Clean(node.DirPath)
+	if _, claimed := g.dirIndex[dir]; !claimed {
+		g.dirIndex[dir] = node
+	}
+}
+
+// AddEdge adds edge to the graph. Both endpoints must already be present.
+func (g *Graph) AddEdge(edge *Edge) error {
+	if _, exists := g.nodeIndex[edge.From]; !exists {
+		return fmt.Errorf("edge source %q is not a node in the graph", edge.From)
+	}
+	if _, exists := g.nodeIndex[edge.To]; !exists {
+		return fmt.Errorf("edge target %q is not a node in the graph", edge.To)
+	}
+	g.edges = append(g.edges, edge)
+	g.outgoing[edge.From] = append(g.outgoing[edge.From], edge)
+	g.incoming[edge.To] = append(g.incoming[edge.To], edge)
+	return nil
+}
+
+// RemoveEdges deletes every edge for which remove returns true, keeping the
+// order of the rest, and returns how many were removed.
+func (g *Graph) RemoveEdges(remove func(edge *Edge) bool) int {
+	kept := g.edges[:0]
+	for _, edge := range g.edges {
+		if !remove(edge) {
+			kept = append(kept, edge)
+		}
+	}
+	removed := len(g.edges) - len(kept)
+	clear(g.edges[len(kept):])
+	g.edges = kept
+
+	for id, edges := range g.outgoing {
+		g.outgoing[id] = slices.DeleteFunc(edges, remove)
+	}
+	for id, edges := range g.incoming {
+		g.incoming[id] = slices.DeleteFunc(edges, remove)
+	}
+	return removed
+}
+
+// Replacement is one run of elements Splice swaps: the NodeCount nodes at
+// NodeStart in insertion order for Nodes, and the EdgeCount edges at
+// EdgeStart for Edges. A count of zero inserts at the start position.
+type Replacement struct {
+	NodeStart, NodeCount int
+	Nodes                []*Node
+	EdgeStart, EdgeCount int
+	Edges                []*Edge
+}
+
+// Splice applies replacements as one change, leaving every other element
+// where it was. It lets a caller that added contiguous runs of elements swap
+// them for new versions, as extract.Incremental does when packages are
+// reloaded. Positions refer to the graph before the call, and replacements
+// must be ordered by position without overlapping.
+//
+// The result is validated as a whole, so a replacement may add an edge to a
+// node a later one adds. The graph is left unchanged, and an error returned,
+// when a new node's ID is taken by a node that stays or is added twice, or
+// when an edge would be left without an endpoint.
+func (g *Graph) Splice(replacements ...Replacement) error {
+	var removedNodes, nodes []*Node
+	var removedEdges, edges []*Edge
+	nodeEnd, edgeEnd := 0, 0
+	for _, r := range replacements {
+		if r.NodeStart < nodeEnd || r.NodeCount < 0 || r.NodeStart+r.NodeCount > len(g.nodes) ||
+			r.EdgeStart < edgeEnd || r.EdgeCount < 0 || r.EdgeStart+r.EdgeCount > len(g.edges) {
+			return fmt.Errorf("splice of nodes [%d:+%d] and edges [%d:+%d] is out of range or out of order",
+				r.NodeStart, r.NodeCount, r.EdgeStart, r.EdgeCount)
+		}
+		nodeEnd, edgeEnd = r.NodeStart+r.NodeCount, r.EdgeStart+r.EdgeCount
+		removedNodes = append(removedNodes, g.nodes[r.NodeStart:nodeEnd]...)
+		removedEdges = append(removedEdges, g.edges[r.EdgeStart:edgeEnd]...)
+		nodes = append(nodes, r.Nodes...)
+		edges = append(edges, r.Edges...)
+	}
+
+	removedIDs := make(map[string]bool, len(removedNodes))
+	for _, node := range removedNodes {
+		removedIDs[node.ID] = true
+	}
+	addedIDs := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		if _, exists := g.nodeIndex[node.ID]; addedIDs[node.ID] || exists && !removedIDs[node.ID] {
+			return fmt.Errorf("%w %q", ErrDuplicateNode, node.ID)
+		}
+		addedIDs[node.ID] = true
+	}
+	present := func(id string) bool {
+		_, exists := g.nodeIndex[id]
+		return addedIDs[id] || exists && !removedIDs[id]
+	}
+	for _, edge := range edges {
+		if !present(edge.From) || !present(edge.To) {
+			return fmt.Errorf("edge %s -> %s would have an endpoint that is not a node in the graph", edge.From, edge.To)
+		}
+	}
+	isRemovedEdge := make(map[*Edge]bool, len(removedEdges))
+	for _, edge := range removedEdges {
+		isRemovedEdge[edge] = true
+	}
+	for id := range removedIDs {
+		if addedIDs[id] {
+			continue
+		}
+		for _, edge := range slices.Concat(g.outgoing[id], g.incoming[id]) {
+			if !isRemovedEdge[edge] {
+				return fmt.Errorf("edge %s -> %s would lose its endpoint %q", edge.From, edge.To, id)
+			}
+		}
+	}
+
+	splicedNodes := make([]*Node, 0, len(g.nodes)-len(removedNodes)+len(nodes))
+	splicedEdges := make([]*Edge, 0, len(g.edges)-len(removedEdges)+len(edges))
+	nodeEnd, edgeEnd = 0, 0
+	for _, r := range replacements {
+		splicedNodes = append(append(splicedNodes, g.nodes[nodeEnd:r.NodeStart]...), r.Nodes...)
+		splicedEdges = append(append(splicedEdges, g.edges[edgeEnd:r.EdgeStart]...), r.Edges...)
+		nodeEnd, edgeEnd = r.NodeStart+r.NodeCount, r.EdgeStart+r.EdgeCount
+	}
+	g.nodes = append(splicedNodes, g.nodes[nodeEnd:]...)
+	g.edges = append(splicedEdges, g.edges[edgeEnd:]...)
+	for id := range removedIDs {
+		delete(g.nodeIndex, id)
+	}
+	for _, node := range nodes {
+		g.nodeIndex[node.ID] = node
+	}
+	g.reindexDirs(slices.Concat(removedNodes, nodes))
+	g.reindexAdjacency(slices.Concat(removedEdges, edges))
+	return nil
+}
+
+// reindexDirs rebuilds the directory index entries of the given nodes'
+// directories from the node order, so the first node added still wins.
+func (g *Graph) reindexDirs(changed []*Node) {
+	dirs := make(map[string]bool, len(changed))
+	for _, node := range changed {
+		if node.DirPath != "" {
+			dir := filepath.Clean(node.DirPath)
+			dirs[dir] = true
+			delete(g.dirIndex, dir)
+		}
+	}
+	if len(dirs) == 0 {
+		return
+	}
+	for _, node := range g.nodes {
+		if node.DirPath != "" && dirs[filepath.Clean(node.DirPath)] {
+			g.indexDir(node)
+		}
+	}
+}
+
+// reindexAdjacency rebuilds the adjacency lists of the given edges'
+// endpoints from the edge order, dropping lists left empty.
+func (g *Graph) reindexAdjacency(changed []*Edge) {
+	sources := make(map[string]bool, len(changed))
+	targets := make(map[string]bool, len(changed))
+	for _, edge := range changed {
+		sources[edge.From] = true
+		targets[edge.To] = true
+		delete(g.outgoing, edge.From)
+		delete(g.incoming, edge.To)
+	}
+	if len(changed) == 0 {
+		return
+	}
+	for _, edge := range g.edges {
+		if sources[edge.From] {
+			g.outgoing[edge.From] = append(g.outgoing[edge.From], edge)
+		}
+		if targets[edge.To] {
+			g.incoming[edge.To] = append(g.incoming[edge.To], edge)
+		}
+	}
+}
+
+// Node returns the node with the given ID.
+func (g *Graph) Node(id string) (*Node, bool) {
+	node, found := g.nodeIndex[id]
+	return node, found
+}
+
+// NodeByDir returns a copy of the node of g whose DirPath is dir, compared
+// after filepath.Clean. When several nodes share a directory the first added
+// wins. Changing the copy leaves the graph alone.
+func NodeByDir(g *Graph, dir string) (Node, bool) {
+	node, found := g.dirIndex[filepath.Clean(dir)]
+	if !found {
+		return Node{}, false
+	}
+	return *cloneNode(node), true
+}
+
+// Nodes returns all nodes in insertion order.
+func (g *Graph) Nodes() []*Node {
+	return g.nodes
+}
+
+// Edges returns all edges in insertion order.
+func (g *Graph) Edges() []*Edge {
+	return g.edges
+}
+
+// OutEdges returns the edges leaving the node with the given ID.
+func (g *Graph) OutEdges(id string) []*Edge {
+	return g.outgoing[id]
+}
+
+// InEdges returns the edges entering the node with the given ID.
+func (g *Graph) InEdges(id string) []*Edge {
+	return g.incoming[id]
+}
@@ -0,0 +1,289 @@
+package graph
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestGraph_AddNode(t *testing.T) {
+	g := New()
+
+	if err := g.AddNode(&Node{ID: "example.com/a", Kind: KindPackage}); err != nil {
+		t.Fatalf("AddNode() error = %v", err)
+	}
+	if err := g.AddNode(&Node{ID: "example.com/a", Kind: KindPackage}); !errors.Is(err, ErrDuplicateNode) {
+		t.Errorf("expected ErrDuplicateNode when adding duplicate node, got %v", err)
+	}
+
+	if len(g.Nodes()) != 1 {
+		t.Errorf("expected 1 node, got %d", len(g.Nodes()))
+	}
+	if _, found := g.Node("example.com/a"); !found {
+		t.Error("expected node to be retrievable by ID")
+	}
+}
+
+func TestNodeByDir(t *testing.T) {
+	g := New()
+	for _, node := range []*Node{
+		{ID: "example.com/a", Kind: KindPackage, DirPath: "/src/a"},
+		{ID: "example.com/a_test", Kind: KindPackage, DirPath: "/src/a"},
+		{ID: "example.com/b", Kind: KindPackage},
+	} {
+		if err := g.AddNode(node); err != nil {
+			t.Fatalf("AddNode() error = %v", err)
+		}
+	}
+
+	node, found := NodeByDir(g, "/src/a/")
+	if !found || node.ID != "example.com/a" {
+		t.Errorf("NodeByDir(/src/a/) = %v, %v, want the first node added for the directory", node, found)
+	}
+	node.Name = "changed"
+	if stored, _ := g.Node("example.com/a"); stored.Name != "" {
+		t.Errorf("changing the returned node renamed the graph's to %q", stored.Name)
+	}
+	if _, found := NodeByDir(g, ""); found {
+		t.Error("expected nodes without DirPath not to be indexed")
+	}
+}
+
+func TestGraph_AddEdge(t *testing.T) {
+	g := New()
+	for _, id := range []string{"example.com/a", "example.com/b"} {
+		if err := g.AddNode(&Node{ID: id, Kind: KindPackage}); err != nil {
+			t.Fatalf("AddNode() error = %v", err)
+		}
+	}
+
+	if err := g.AddEdge(&Edge{From: "example.com/a", To: "example.com/b", Kind: EdgeImport}); err != nil {
+		t.Fatalf("AddEdge() error = %v", err)
+	}
+	if err := g.AddEdge(&Edge{From: "example.com/a", To: "example.com/missing", Kind: EdgeImport}); err == nil {
+		t.Error("expected error for edge with unknown target")
+	}
+	if err := g.AddEdge(&Edge{From: "example.com/missing", To: "example.com/a", Kind: EdgeImport}); err == nil {
+		t.Error("expected error for edge with unknown source")
+	}
+
+	if len(g.Edges()) != 1 {
+		t.Fatalf("expected 1 edge, got %d", len(g.Edges()))
+	}
+	if len(g.OutEdges("example.com/a")) != 1 {
+		t.Errorf("expected 1 outgoing edge from a, got %d", len(g.OutEdges("example.com/a")))
+	}
+	if len(g.InEdges("example.com/b")) != 1 {
+		t.Errorf("expected 1 incoming edge to b, got %d", len(g.InEdges("example.com/b")))
+	}
+}
+
+func TestGraph_RemoveEdges(t *testing.T) {
+	g := New()
+	for _, id := range []string{"a", "b", "c"} {
+		if err := g.AddNode(&Node{ID: id, Kind: KindPackage}); err != nil {
+			t.Fatalf("AddNode() error = %v", err)
+		}
+	}
+	for _, edge := range []*Edge{
+		{From: "a", To: "b", Kind: EdgeImport},
+		{From: "b", To: "a", Kind: EdgeTestImport, IsTestOnly: true},
+		{From: "a", To: "c", Kind: EdgeImport},
+		{From: "c", To: "a", Kind: EdgeTestImport, IsTestOnly: true},
+	} {
+		if err := g.AddEdge(edge); err != nil {
+			t.Fatalf("AddEdge() error = %v", err)
+		}
+	}
+
+	if removed := g.RemoveEdges(func(edge *Edge) bool { return edge.IsTestOnly }); removed != 2 {
+		t.Errorf("RemoveEdges() = %d, want 2", removed)
+	}
+	if len(g.Edges()) != 2 || g.Edges()[0].To != "b" || g.Edges()[1].To != "c" {
+		t.Errorf("expected the production edges to remain in order, got %+v", g.Edges())
+	}
+	if len(g.OutEdges("b")) != 0 || len(g.InEdges("a")) != 0 || len(g.OutEdges("a")) != 2 {
+		t.Errorf("adjacency not updated: out(a)=%d out(b)=%d in(a)=%d", len(g.OutEdges("a")), len(g.OutEdges("b")), len(g.InEdges("a")))
+	}
+}
+
+func TestGraph_Splice(t *testing.T) {
+	build := func(t *testing.T, nodes []*Node, edges []*Edge) *Graph {
+		t.Helper()
+		g := New()
+		for _, node := range nodes {
+			if err := g.AddNode(node); err != nil {
+				t.Fatalf("AddNode() error = %v", err)
+			}
+		}
+		for _, edge := range edges {
+			if err := g.AddEdge(edge); err != nil {
+				t.Fatalf("AddEdge() error = %v", err)
+			}
+		}
+		return g
+	}
+	// Each package owns its node, a function node and its outgoing edges; b
+	// is replaced by a version that drops b.F, imports c and shares its
+	// directory with a.
+	newGraph := func(t *testing.T) *Graph {
+		return build(t, []*Node{
+			{ID: "a", Kind: KindPackage, DirPath: "/src/a"},
+			{ID: "b", Kind: KindPackage, DirPath: "/src/b"},
+			{ID: "b.F", Kind: KindFunction},
+			{ID: "c", Kind: KindPackage, DirPath: "/src/c"},
+		}, []*Edge{
+			{From: "a", To: "b", Kind: EdgeImport},
+			{From: "b", To: "a", Kind: EdgeImport},
+			{From: "b.F", To: "b", Kind: EdgeCall},
+			{From: "c", To: "b", Kind: EdgeImport},
+		})
+	}
+	replacementNodes := func() []*Node {
+		return []*Node{{ID: "b", Kind: KindPackage, DirPath: "/src/a", Name: "b2"}}
+	}
+	replacementEdges := func() []*Edge {
+		return []*Edge{{From: "b", To: "c", Kind: EdgeImport}}
+	}
+
+	g := newGraph(t)
+	if err := g.Splice(Replacement{NodeStart: 1, NodeCount: 2, Nodes: replacementNodes(), EdgeStart: 1, EdgeCount: 2, Edges: replacementEdges()}); err != nil {
+		t.Fatalf("Splice() error = %v", err)
+	}
+	want := build(t, []*Node{
+		{ID: "a", Kind: KindPackage, DirPath: "/src/a"},
+		{ID: "b", Kind: KindPackage, DirPath: "/src/a", Name: "b2"},
+		{ID: "c", Kind: KindPackage, DirPath: "/src/c"},
+	}, []*Edge{
+		{From: "a", To: "b", Kind: EdgeImport},
+		{From: "b", To: "c", Kind: EdgeImport},
+		{From: "c", To: "b", Kind: EdgeImport},
+	})
+	if !reflect.DeepEqual(g, want) {
+		t.Errorf("Splice() graph = %+v, want %+v", g, want)
+	}
+	if node, _ := NodeByDir(g, "/src/a"); node.ID != "a" {
+		t.Errorf("NodeByDir(/src/a) = %s, want the first node added, a", node.ID)
+	}
+
+	// One batch: b is replaced with an import of d, which the second
+	// replacement appends, and c drops its import of b.
+	g = newGraph(t)
+	err := g.Splice(
+		Replacement{NodeStart: 1, NodeCount: 2, Nodes: replacementNodes(), EdgeStart: 1, EdgeCount: 2,
+			Edges: []*Edge{{From: "b", To: "d", Kind: EdgeImport}}},
+		Replacement{NodeStart: 3, NodeCount: 1, Nodes: []*Node{{ID: "c", Kind: KindPackage, DirPath: "/src/c"}}, EdgeStart: 3, EdgeCount: 1},
+		Replacement{NodeStart: 4, Nodes: []*Node{{ID: "d", Kind: KindPackage}}, EdgeStart: 4,
+			Edges: []*Edge{{From: "d", To: "c", Kind: EdgeImport}}},
+	)
+	if err != nil {
+		t.Fatalf("Splice() of a batch error = %v", err)
+	}
+	want = build(t, []*Node{
+		{ID: "a", Kind: KindPackage, DirPath: "/src/a"},
+		{ID: "b", Kind: KindPackage, DirPath: "/src/a", Name: "b2"},
+		{ID: "c", Kind: KindPackage, DirPath: "/src/c"},
+		{ID: "d", Kind: KindPackage},
+	}, []*Edge{
+		{From: "a", To: "b", Kind: EdgeImport},
+		{From: "b", To: "d", Kind: EdgeImport},
+		{From: "d", To: "c", Kind: EdgeImport},
+	})
+	if !reflect.DeepEqual(g, want) {
+		t.Errorf("Splice() of a batch graph = %+v, want %+v", g, want)
+	}
+
+	replaceB := func(nodes []*Node, edges []*Edge) Replacement {
+		return Replacement{NodeStart: 1, NodeCount: 2, Nodes: nodes, EdgeStart: 1, EdgeCount: 2, Edges: edges}
+	}
+	invalid := []struct {
+		name         string
+		replacements []Replacement
+	}{
+		{name: "duplicate of a kept node", replacements: []Replacement{replaceB([]*Node{{ID: "c", Kind: KindPackage}}, nil)}},
+		{name: "dangling new edge", replacements: []Replacement{replaceB(replacementNodes(), []*Edge{{From: "b", To: "x", Kind: EdgeImport}})}},
+		{name: "kept edge losing its endpoint", replacements: []Replacement{replaceB(nil, nil)}},
+		{name: "node added twice", replacements: []Replacement{
+			replaceB(replacementNodes(), nil),
+			{NodeStart: 4, Nodes: []*Node{{ID: "d", Kind: KindPackage}}, EdgeStart: 4},
+			{NodeStart: 4, Nodes: []*Node{{ID: "d", Kind: KindPackage}}, EdgeStart: 4},
+		}},
+		{name: "out of range", replacements: []Replacement{{NodeStart: 3, NodeCount: 2}}},
+		{name: "out of order", replacements: []Replacement{{NodeStart: 3, NodeCount: 1, Nodes: []*Node{{ID: "c", Kind: KindPackage}}}, replaceB(replacementNodes(), nil)}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newGraph(t)
+			if err := g.Splice(tt.replacements...); err == nil {
+				t.Fatal("Splice() error = nil, want an error")
+			}
+			if !reflect.DeepEqual(g, newGraph(t)) {
+				t.Errorf("failed Splice() changed the graph: %+v", g)
+			}
+		})
+	}
+}
+
+func TestNode_Label(t *testing.T) {
+	tests := []struct {
+		name string
+		node Node
+		want string
+	}{
+		{
+			name: "package inside module uses relative path",
+			node: Node{ID: "example.com/mod/internal/store", ModulePath: "example.com/mod"},
+			want: "internal/store",
+		},
+		{
+			name: "module root package uses module path",
+			node: Node{ID: "example.com/mod", ModulePath: "example.com/mod"},
+			want: "example.com/mod",
+		},
+		{
+			name: "package without module uses ID",
+			node: Node{ID: "example.com/other"},
+			want: "example.com/other",
+		},
+		{
+			name: "module path prefix must end at a path boundary",
+			node: Node{ID: "example.com/modular", ModulePath: "example.com/mod"},
+			want: "example.com/modular",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.node.Label(); got != tt.want {
+				t.Errorf("Label() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNode_Properties_IncludesAttributes(t *testing.T) {
+	node := &Node{ID: "example.com/mod/api", Kind: KindPackage, TestDependencies: []string{"example.com/mod/b", "testing"}}
+	node.SetAttribute("fan_in", "2")
+	node.SetAttribute("empty", "")
+
+	properties := node.Properties()
+	if properties["fan_in"] != "2" || properties["kind"] != "package" ||
+		properties["testDependencies"] != "example.com/mod/b,testing" {
+		t.Errorf("unexpected properties: %v", properties)
+	}
+	if _, found := properties["empty"]; found {
+		t.Errorf("expected empty attribute to be omitted, got %v", properties)
+	}
+}
+
+func TestEdge_Properties_IncludesMultiplicity(t *testing.T) {
+	edge := &Edge{From: "a", To: "b", Kind: EdgeImport, Multiplicity: 3}
+	edge.SetAttribute("weight", "2")
+
+	if properties := edge.Properties(); properties["multiplicity"] != "3" || properties["weight"] != "2" {
+		t.Errorf("unexpected properties: %v", properties)
+	}
+	if _, found := (&Edge{From: "a", To: "b"}).Properties()["multiplicity"]; found {
+		t.Error("expected an unknown multiplicity to be omitted")
+	}
+}
@@ -0,0 +1,112 @@
+package graph
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Node IDs are stable strings that third-party tools can compute independently:
+//
+//	package:  <pkgPath>
+//	file:     file:<pkgPath>:<relPath>
+//	function: func:<pkgPath>:<receiver>:<name>
+//
+// Each part is escaped so that '%' becomes "%25" and ':' becomes "%3A"; valid Go
+// import paths and identifiers contain neither, so IDs normally read as written.
+// Receivers are normalized to the bare type name (no pointer, no type parameters),
+// and file paths always use forward slashes. Changing this scheme is a breaking
+// change for every consumer of exported graphs.
+
+const (
+	fileIDPrefix     = "file:"
+	functionIDPrefix = "func:"
+	idSeparator      = ":"
+)
+
+var (
+	idPartEscaper   = strings.NewReplacer("%", "%25", ":", "%3A")
+	idPartUnescaper = strings.NewReplacer("%3A", ":", "%25", "%")
+)
+
+// PackageID returns the node ID of the package with import path pkgPath.
+func PackageID(pkgPath string) string {
+	return escapeIDPart(pkgPath)
+}
+
+// FileID returns the node ID of a file. relPath is relative to the package directory.
+func FileID(pkgPath, relPath string) string {
+	return fileIDPrefix + joinIDParts(pkgPath, filepath.ToSlash(relPath))
+}
+
+// FuncID returns the node ID of a function or method. recv is empty for plain functions.
+// Pointer receivers and type parameters are dropped, so "*List[T]" and "List" yield the same ID.
+func FuncID(pkgPath, recv, name string) string {
+	return functionIDPrefix + joinIDParts(pkgPath, normalizeReceiver(recv), name)
+}
+
+// ParseID splits id into its kind and unescaped parts, reversing PackageID, FileID, and FuncID.
+func ParseID(id string) (Kind, []string, error) {
+	if id == "" {
+		return "", nil, fmt.Errorf("invalid node ID: empty")
+	}
+
+	if encoded, found := strings.CutPrefix(id, fileIDPrefix); found {
+		parts, err := splitIDParts(encoded, 2)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid file ID %q: %w", id, err)
+		}
+		return KindFile, parts, nil
+	}
+
+	if encoded, found := strings.CutPrefix(id, functionIDPrefix); found {
+		parts, err := splitIDParts(encoded, 3)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid function ID %q: %w", id, err)
+		}
+		return KindFunction, parts, nil
+	}
+
+	if strings.Contains(id, idSeparator) {
+		return "", nil, fmt.Errorf("invalid node ID %q: unknown kind prefix", id)
+	}
+	return KindPackage, []string{unescapeIDPart(id)}, nil
+}
+
+func normalizeReceiver(recv string) string {
+	recv = strings.TrimLeft(recv, "*")
+	if typeName, _, found := strings.Cut(recv, "["); found {
+		return typeName
+	}
+	return recv
+}
+
+func joinIDParts(parts ...string) string {
+	escaped := make([]string, len(parts))
+	for i, part := range parts {
+		escaped[i] = escapeIDPart(part)
+	}
+	return strings.Join(escaped, idSeparator)
+}
+
+func splitIDParts(encoded string, wantParts int) ([]string, error) {
+	parts := strings.Split(encoded, idSeparator)
+	if len(parts) != wantParts {
+		return nil, fmt.Errorf("expected %d parts, got %d", wantParts, len(parts))
+	}
+	for i, part := range parts {
+		parts[i] = unescapeIDPart(part)
+	}
+	if parts[0] == "" {
+		return nil, fmt.Errorf("missing package path")
+	}
+	return parts, nil
+}
+
+func escapeIDPart(part string) string {
+	return idPartEscaper.Replace(part)
+}
+
+func unescapeIDPart(part string) string {
+	return idPartUnescaper.Replace(part)
+}
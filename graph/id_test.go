@@ -0,0 +1,94 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIDHelpers_GoldenIDs(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		want string
+	}{
+		{name: "package", id: PackageID("example.com/mod/store"), want: "example.com/mod/store"},
+		{name: "vendored package", id: PackageID("example.com/mod/vendor/golang.org/x/text"),
+			want: "example.com/mod/vendor/golang.org/x/text"},
+		{name: "package with reserved characters", id: PackageID("weird:pkg%path"), want: "weird%3Apkg%25path"},
+		{name: "file", id: FileID("example.com/mod/store", "store.go"), want: "file:example.com/mod/store:store.go"},
+		{name: "file in subdirectory", id: FileID("example.com/mod", "testdata/a b.go"),
+			want: "file:example.com/mod:testdata/a b.go"},
+		{name: "function", id: FuncID("example.com/mod/store", "", "Open"), want: "func:example.com/mod/store::Open"},
+		{name: "pointer method", id: FuncID("example.com/mod/store", "*Store", "Get"),
+			want: "func:example.com/mod/store:Store:Get"},
+		{name: "generic receiver", id: FuncID("example.com/mod/list", "*List[T]", "Push"),
+			want: "func:example.com/mod/list:List:Push"},
+		{name: "generic receiver with multiple parameters", id: FuncID("example.com/mod/cache", "Cache[K, V]", "Get"),
+			want: "func:example.com/mod/cache:Cache:Get"},
+		{name: "unicode identifiers", id: FuncID("example.com/mod/größe", "Maß", "Länge"),
+			want: "func:example.com/mod/größe:Maß:Länge"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.id != tt.want {
+				t.Errorf("ID = %q, want %q", tt.id, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseID(t *testing.T) {
+	tests := []struct {
+		name      string
+		id        string
+		wantKind  Kind
+		wantParts []string
+	}{
+		{name: "package", id: PackageID("example.com/mod/store"),
+			wantKind: KindPackage, wantParts: []string{"example.com/mod/store"}},
+		{name: "package with reserved characters", id: PackageID("weird:pkg%path"),
+			wantKind: KindPackage, wantParts: []string{"weird:pkg%path"}},
+		{name: "file", id: FileID("example.com/mod", "sub/file.go"),
+			wantKind: KindFile, wantParts: []string{"example.com/mod", "sub/file.go"}},
+		{name: "file with colon in name", id: FileID("example.com/mod", "a:b.go"),
+			wantKind: KindFile, wantParts: []string{"example.com/mod", "a:b.go"}},
+		{name: "plain function", id: FuncID("example.com/mod", "", "main"),
+			wantKind: KindFunction, wantParts: []string{"example.com/mod", "", "main"}},
+		{name: "generic method", id: FuncID("example.com/mod/list", "*List[T]", "Push"),
+			wantKind: KindFunction, wantParts: []string{"example.com/mod/list", "List", "Push"}},
+		{name: "literal percent escape survives round trip", id: PackageID("example.com/a%3Ab"),
+			wantKind: KindPackage, wantParts: []string{"example.com/a%3Ab"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, parts, err := ParseID(tt.id)
+			if err != nil {
+				t.Fatalf("ParseID(%q) error = %v", tt.id, err)
+			}
+			if kind != tt.wantKind {
+				t.Errorf("kind = %q, want %q", kind, tt.wantKind)
+			}
+			if !reflect.DeepEqual(parts, tt.wantParts) {
+				t.Errorf("parts = %q, want %q", parts, tt.wantParts)
+			}
+		})
+	}
+}
+
+func TestParseID_Invalid(t *testing.T) {
+	invalidIDs := []string{
+		"",
+		"type:example.com/mod:T",
+		"file:example.com/mod",
+		"file::main.go",
+		"func:example.com/mod:Name",
+	}
+
+	for _, id := range invalidIDs {
+		if _, _, err := ParseID(id); err == nil {
+			t.Errorf("ParseID(%q) expected error", id)
+		}
+	}
+}
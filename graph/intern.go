@@ -0,0 +1,76 @@
+package graph
+
+import "slices"
+
+// interner maps each string to a canonical copy, so a graph built from many
+// extractors holds every distinct ID, path and attribute key once instead of
+// once per node and edge that mentions it. It is not safe for concurrent use;
+// Builder only calls it while holding its mutex.
+type interner struct {
+	strings map[string]string
+}
+
+func newInterner() *interner {
+	return &interner{strings: make(map[string]string)}
+}
+
+// intern returns the canonical copy of s.
+func (in *interner) intern(s string) string {
+	if canonical, found := in.strings[s]; found {
+		return canonical
+	}
+	in.strings[s] = s
+	return s
+}
+
+// internSlice returns a copy of values with its elements interned, leaving
+// values, which the emitter may still hold, untouched.
+func (in *interner) internSlice(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	interned := make([]string, len(values))
+	for i, value := range values {
+		interned[i] = in.intern(value)
+	}
+	return interned
+}
+
+// internAttributes returns a copy of attributes with its values interned.
+// Keys are almost always the extractors' attribute name constants, which are
+// already shared, so they are copied as they are.
+func (in *interner) internAttributes(attributes map[string]string) map[string]string {
+	if attributes == nil {
+		return nil
+	}
+	interned := make(map[string]string, len(attributes))
+	for name, value := range attributes {
+		interned[name] = in.intern(value)
+	}
+	return interned
+}
+
+// internNode interns the node's repeated strings. Slices and maps are
+// replaced by interned copies, so the graph never shares them with the
+// emitter. Files and DirPath are unique per package, so Files is copied
+// without interning and DirPath left alone.
+func (in *interner) internNode(node *Node) {
+	node.ID = in.intern(node.ID)
+	node.Name = in.intern(node.Name)
+	node.ModulePath = in.intern(node.ModulePath)
+	node.ModuleVersion = in.intern(node.ModuleVersion)
+	node.GoVersion = in.intern(node.GoVersion)
+	node.TestFramework = in.intern(node.TestFramework)
+	node.Files = slices.Clone(node.Files)
+	node.TestDependencies = in.internSlice(node.TestDependencies)
+	node.BuildConstraints = in.internSlice(node.BuildConstraints)
+	node.Attributes = in.internAttributes(node.Attributes)
+}
+
+// internEdge interns the edge's endpoints, which then share the strings of
+// the node IDs they refer to, and its attributes.
+func (in *interner) internEdge(edge *Edge) {
+	edge.From = in.intern(edge.From)
+	edge.To = in.intern(edge.To)
+	edge.Attributes = in.internAttributes(edge.Attributes)
+}
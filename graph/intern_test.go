@@ -0,0 +1,100 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"unsafe"
+)
+
+// sameString reports whether a and b share their backing bytes.
+func sameString(a, b string) bool {
+	return unsafe.StringData(a) == unsafe.StringData(b)
+}
+
+func TestBuilder_InternsConcurrently(t *testing.T) {
+	const emitters = 16
+	builder := NewBuilder()
+
+	// Every emitter builds its own copies of the strings, as extractors
+	// running on different packages do.
+	var workers sync.WaitGroup
+	for i := range emitters {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			id := fmt.Sprintf("ex/p%d", i)
+			node := Node{ID: id, Kind: KindPackage, ModulePath: strings.Clone("ex"), GoVersion: strings.Clone("1.24")}
+			node.SetAttribute("fan_in", strings.Clone("0.00"))
+			if err := builder.EmitNode(node); err != nil {
+				t.Error(err)
+			}
+			for j := range emitters {
+				edge := Edge{From: strings.Clone(id), To: fmt.Sprintf("ex/p%d", j), Kind: EdgeImport}
+				if err := builder.EmitEdge(edge); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	workers.Wait()
+	if err := builder.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	g := builder.Graph()
+	first := g.Nodes()[0]
+	for _, node := range g.Nodes() {
+		if !sameString(node.ModulePath, first.ModulePath) || !sameString(node.GoVersion, first.GoVersion) ||
+			!sameString(node.Attributes["fan_in"], first.Attributes["fan_in"]) {
+			t.Errorf("%s does not share the interned module path, Go version and attribute value", node.ID)
+		}
+	}
+	if len(g.Edges()) != emitters*emitters {
+		t.Fatalf("got %d edges, want %d", len(g.Edges()), emitters*emitters)
+	}
+	for _, edge := range g.Edges() {
+		from, _ := g.Node(edge.From)
+		to, _ := g.Node(edge.To)
+		if !sameString(edge.From, from.ID) || !sameString(edge.To, to.ID) {
+			t.Errorf("edge %s -> %s does not share its endpoints' node IDs", edge.From, edge.To)
+		}
+	}
+}
+
+func TestBuilder_LeavesEmittedValuesAlone(t *testing.T) {
+	builder := NewBuilder()
+	files := []string{"a.go"}
+	constraints := []string{strings.Clone("linux")}
+	attributes := map[string]string{"loc": strings.Clone("10")}
+	// The same value is interned first, so interning would swap in that copy.
+	if err := builder.EmitNode(Node{ID: "ex/first", Kind: KindPackage, BuildConstraints: []string{"linux"}}); err != nil {
+		t.Fatal(err)
+	}
+	node := Node{ID: "ex/p", Kind: KindPackage, Files: files, BuildConstraints: constraints, Attributes: attributes}
+	if err := builder.EmitNode(node); err != nil {
+		t.Fatal(err)
+	}
+	edgeAttributes := map[string]string{"import_alias": "x"}
+	if err := builder.EmitEdge(Edge{From: "ex/p", To: "ex/first", Kind: EdgeImport, Attributes: edgeAttributes}); err != nil {
+		t.Fatal(err)
+	}
+	if err := builder.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	first, _ := builder.Graph().Node("ex/first")
+	if sameString(constraints[0], first.BuildConstraints[0]) {
+		t.Error("the emitted BuildConstraints slice was interned in place")
+	}
+
+	// Changes to the graph do not reach the emitter's slices and maps.
+	built, _ := builder.Graph().Node("ex/p")
+	built.Files[0] = "b.go"
+	built.BuildConstraints[0] = "windows"
+	built.SetAttribute("loc", "20")
+	builder.Graph().Edges()[0].SetAttribute("import_alias", "y")
+	if files[0] != "a.go" || constraints[0] != "linux" || attributes["loc"] != "10" || edgeAttributes["import_alias"] != "x" {
+		t.Errorf("emitted values changed to %v, %v, %v and %v", files, constraints, attributes, edgeAttributes)
+	}
+}
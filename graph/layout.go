@@ -0,0 +1,136 @@
+package graph
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrCyclic is returned by LayeredLayout and TopologicalSort for graphs
+// containing a cycle, which have no topological order.
+var ErrCyclic = errors.New("graph has a cycle")
+
+// LayeredLayout assigns every node a layer for hierarchical drawing: 0 for
+// nodes with no incoming edges and otherwise one more than the highest layer
+// among its predecessors, i.e. the length of the longest path reaching it from
+// a source. Edges of every kind count, so every edge points to a higher
+// layer. It fails with ErrCyclic, naming the first cycle, when the graph has
+// no topological order.
+func LayeredLayout(g *Graph) (map[string]int, error) {
+	remaining := make(map[string]int, len(g.nodes))
+	layers := make(map[string]int, len(g.nodes))
+	var ready []string
+	for _, id := range g.sortedNodeIDs() {
+		layers[id] = 0
+		remaining[id] = len(g.incoming[id])
+		if remaining[id] == 0 {
+			ready = append(ready, id)
+		}
+	}
+
+	for len(ready) > 0 {
+		id := ready[0]
+		ready = ready[1:]
+		for _, edge := range g.outgoing[id] {
+			layers[edge.To] = max(layers[edge.To], layers[id]+1)
+			remaining[edge.To]--
+			if remaining[edge.To] == 0 {
+				ready = append(ready, edge.To)
+			}
+		}
+	}
+
+	for _, count := range remaining {
+		if count > 0 {
+			return nil, fmt.Errorf("%w: %s", ErrCyclic, strings.Join(g.FindCycles(nil)[0], ", "))
+		}
+	}
+	return layers, nil
+}
+
+// RankCyclic is the rank Ranks gives nodes in a cycle, which have no place in
+// a topological order.
+const RankCyclic = -1
+
+// TopologicalSort returns the IDs of g's nodes in dependency order: every
+// node comes after the nodes its edges of the given kinds (all kinds when
+// empty) point to, so for imports the deepest dependencies come first. Ties
+// are broken by ID, so the order is deterministic. It fails with ErrCyclic,
+// naming the first cycle, when there is no such order.
+func TopologicalSort(g *Graph, edgeKinds []EdgeKind) ([]string, error) {
+	order := g.dependencyOrder(edgeKinds, nil)
+	if len(order) < len(g.nodes) {
+		return nil, fmt.Errorf("%w: %s", ErrCyclic, strings.Join(g.FindCycles(edgeKinds)[0], ", "))
+	}
+	return order, nil
+}
+
+// Ranks numbers g's nodes 0 to N-1 in TopologicalSort order. On a cyclic
+// graph the nodes of every cycle get RankCyclic and the others are numbered
+// in the dependency order of the graph without them.
+func Ranks(g *Graph, edgeKinds []EdgeKind) map[string]int {
+	ranks := make(map[string]int, len(g.nodes))
+	cyclic := make(map[string]bool)
+	for _, cycle := range g.FindCycles(edgeKinds) {
+		for _, id := range cycle {
+			cyclic[id] = true
+			ranks[id] = RankCyclic
+		}
+	}
+	for rank, id := range g.dependencyOrder(edgeKinds, cyclic) {
+		ranks[id] = rank
+	}
+	return ranks
+}
+
+// dependencyOrder runs Kahn's algorithm over the reversed edges of the given
+// kinds, ignoring excluded nodes and their edges, and always taking the
+// smallest ready ID next. Nodes in or above a cycle are left out.
+func (g *Graph) dependencyOrder(edgeKinds []EdgeKind, excluded map[string]bool) []string {
+	remaining := make(map[string]int, len(g.nodes))
+	ready := &idHeap{}
+	for _, id := range g.sortedNodeIDs() {
+		if excluded[id] {
+			continue
+		}
+		for _, edge := range g.outgoing[id] {
+			if matchesEdgeKind(edge, edgeKinds) && !excluded[edge.To] {
+				remaining[id]++
+			}
+		}
+		if remaining[id] == 0 {
+			heap.Push(ready, id)
+		}
+	}
+
+	var order []string
+	for ready.Len() > 0 {
+		id := heap.Pop(ready).(string)
+		order = append(order, id)
+		for _, edge := range g.incoming[id] {
+			if !matchesEdgeKind(edge, edgeKinds) || excluded[edge.From] {
+				continue
+			}
+			remaining[edge.From]--
+			if remaining[edge.From] == 0 {
+				heap.Push(ready, edge.From)
+			}
+		}
+	}
+	return order
+}
+
+// idHeap is a min-heap of node IDs.
+type idHeap []string
+
+func (h idHeap) Len() int           { return len(h) }
+func (h idHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h idHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *idHeap) Push(value any)    { *h = append(*h, value.(string)) }
+func (h *idHeap) Pop() any {
+	old := *h
+	value := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return value
+}
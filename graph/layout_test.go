@@ -0,0 +1,84 @@
+package graph
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestLayeredLayout(t *testing.T) {
+	// a -> b -> d, a -> c -> d, a -> d, e isolated.
+	g := newLayoutGraph(t, []string{"a", "b", "c", "d", "e"}, [][2]string{
+		{"a", "b"}, {"a", "c"}, {"a", "d"}, {"b", "d"}, {"c", "d"},
+	})
+
+	layers, err := LayeredLayout(g)
+	if err != nil {
+		t.Fatalf("LayeredLayout() error = %v", err)
+	}
+	want := map[string]int{"a": 0, "b": 1, "c": 1, "d": 2, "e": 0}
+	if !maps.Equal(layers, want) {
+		t.Errorf("LayeredLayout() = %v, want %v", layers, want)
+	}
+	for _, edge := range g.Edges() {
+		if layers[edge.To] <= layers[edge.From] {
+			t.Errorf("edge %s -> %s goes from layer %d to %d, want increasing", edge.From, edge.To, layers[edge.From], layers[edge.To])
+		}
+	}
+}
+
+func TestLayeredLayout_Cycle(t *testing.T) {
+	g := newLayoutGraph(t, []string{"a", "b", "c"}, [][2]string{{"a", "b"}, {"b", "c"}, {"c", "b"}})
+
+	if _, err := LayeredLayout(g); !errors.Is(err, ErrCyclic) {
+		t.Errorf("expected ErrCyclic, got %v", err)
+	}
+}
+
+func TestTopologicalSort(t *testing.T) {
+	g := newLayoutGraph(t, []string{"a", "b", "c", "d", "e"}, [][2]string{
+		{"a", "b"}, {"a", "c"}, {"a", "d"}, {"b", "d"}, {"c", "d"},
+	})
+
+	order, err := TopologicalSort(g, nil)
+	if err != nil {
+		t.Fatalf("TopologicalSort() error = %v", err)
+	}
+	if want := []string{"d", "b", "c", "a", "e"}; !slices.Equal(order, want) {
+		t.Errorf("TopologicalSort() = %v, want %v", order, want)
+	}
+
+	cyclic := newLayoutGraph(t, []string{"a", "b", "c"}, [][2]string{{"a", "b"}, {"b", "c"}, {"c", "b"}})
+	if _, err := TopologicalSort(cyclic, nil); !errors.Is(err, ErrCyclic) {
+		t.Errorf("expected ErrCyclic, got %v", err)
+	}
+}
+
+func TestRanks(t *testing.T) {
+	// b and c form a cycle that a imports; d imports a.
+	g := newLayoutGraph(t, []string{"a", "b", "c", "d", "e"}, [][2]string{
+		{"a", "b"}, {"b", "c"}, {"c", "b"}, {"d", "a"},
+	})
+
+	want := map[string]int{"a": 0, "b": RankCyclic, "c": RankCyclic, "d": 1, "e": 2}
+	if ranks := Ranks(g, []EdgeKind{EdgeImport}); !maps.Equal(ranks, want) {
+		t.Errorf("Ranks() = %v, want %v", ranks, want)
+	}
+}
+
+func newLayoutGraph(t *testing.T, ids []string, edges [][2]string) *Graph {
+	t.Helper()
+	g := New()
+	for _, id := range ids {
+		if err := g.AddNode(&Node{ID: id, Kind: KindPackage}); err != nil {
+			t.Fatalf("AddNode() error = %v", err)
+		}
+	}
+	for _, edge := range edges {
+		if err := g.AddEdge(&Edge{From: edge[0], To: edge[1], Kind: EdgeImport}); err != nil {
+			t.Fatalf("AddEdge() error = %v", err)
+		}
+	}
+	return g
+}
@@ -0,0 +1,240 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
+)
+
+// AttributeConflict selects what Merge does when a node present in both graphs
+// has different non-empty values for the same field or attribute.
+type AttributeConflict string
+
+const (
+	PreferDst       AttributeConflict = "prefer-dst"
+	PreferSrc       AttributeConflict = "prefer-src"
+	ErrorOnConflict AttributeConflict = "error"
+)
+
+// DuplicateEdges selects what Merge does with an edge whose EdgeKey is
+// already in the destination graph.
+type DuplicateEdges string
+
+const (
+	// DedupeEdges keeps a single edge per EdgeKey.
+	DedupeEdges DuplicateEdges = "dedupe"
+	// WeightEdges keeps a single edge per EdgeKey and records how many merged
+	// graphs contained it in the EdgeWeightAttribute attribute.
+	WeightEdges DuplicateEdges = "weight"
+)
+
+// EdgeWeightAttribute is the edge attribute written by WeightEdges. An edge
+// without it has weight 1.
+const EdgeWeightAttribute = "weight"
+
+// ConflictPolicy configures Merge. The zero value is invalid; use
+// DefaultConflictPolicy or set both fields.
+type ConflictPolicy struct {
+	Attributes AttributeConflict
+	Edges      DuplicateEdges
+}
+
+// DefaultConflictPolicy keeps destination values and deduplicates edges.
+var DefaultConflictPolicy = ConflictPolicy{Attributes: PreferDst, Edges: DedupeEdges}
+
+// ErrMergeConflict is returned by Merge under ErrorOnConflict when the graphs
+// disagree on a node value.
+var ErrMergeConflict = errors.New("merge conflict")
+
+// Merge adds the nodes, edges and provenance of src to dst. Nodes are matched
+// by ID: empty values in dst are filled from src, file and test dependency
+// lists are unioned, and differing non-empty values are resolved by
+// policy.Attributes. Under ErrorOnConflict dst is left unchanged when an error
+// is returned.
+//
+// When the merged graphs do not conflict, merging any number of graphs into an
+// empty graph yields an equivalent graph (per Diff) whatever the order.
+func Merge(dst, src *Graph, policy ConflictPolicy) error {
+	if err := policy.validate(); err != nil {
+		return err
+	}
+
+	mergedNodes := make(map[string]*Node)
+	for _, srcNode := range src.Nodes() {
+		dstNode, found := dst.Node(srcNode.ID)
+		if !found {
+			continue
+		}
+		merged, conflicts := mergeNode(dstNode, srcNode, policy.Attributes)
+		if len(conflicts) > 0 && policy.Attributes == ErrorOnConflict {
+			return fmt.Errorf("%w on node %q: %v", ErrMergeConflict, srcNode.ID, conflicts)
+		}
+		mergedNodes[srcNode.ID] = merged
+	}
+
+	for _, srcNode := range src.Nodes() {
+		if merged, found := mergedNodes[srcNode.ID]; found {
+			*dst.nodeIndex[srcNode.ID] = *merged
+			dst.indexDir(dst.nodeIndex[srcNode.ID])
+			continue
+		}
+		if err := dst.AddNode(cloneNode(srcNode)); err != nil {
+			return err
+		}
+	}
+
+	if err := mergeEdges(dst, src, policy.Edges); err != nil {
+		return err
+	}
+	dst.Provenance = mergeProvenance(dst, src)
+	return nil
+}
+
+func (p ConflictPolicy) validate() error {
+	switch p.Attributes {
+	case PreferDst, PreferSrc, ErrorOnConflict:
+	default:
+		return fmt.Errorf("unknown attribute conflict policy %q", p.Attributes)
+	}
+	switch p.Edges {
+	case DedupeEdges, WeightEdges:
+	default:
+		return fmt.Errorf("unknown duplicate edge policy %q", p.Edges)
+	}
+	return nil
+}
+
+// mergeNode returns a merged copy of dstNode and the sorted names of the
+// values the two nodes disagree on.
+func mergeNode(dstNode, srcNode *Node, policy AttributeConflict) (*Node, []string) {
+	merged := cloneNode(dstNode)
+	preferSrc := policy == PreferSrc
+	var conflicts []string
+
+	noteConflict := func(name string, conflict bool) {
+		if conflict {
+			conflicts = append(conflicts, name)
+		}
+	}
+	noteConflict("kind", mergeValue(&merged.Kind, srcNode.Kind, preferSrc))
+	noteConflict("name", mergeValue(&merged.Name, srcNode.Name, preferSrc))
+	noteConflict("module", mergeValue(&merged.ModulePath, srcNode.ModulePath, preferSrc))
+	noteConflict("moduleVersion", mergeValue(&merged.ModuleVersion, srcNode.ModuleVersion, preferSrc))
+	noteConflict("goVersion", mergeValue(&merged.GoVersion, srcNode.GoVersion, preferSrc))
+	noteConflict("testFramework", mergeValue(&merged.TestFramework, srcNode.TestFramework, preferSrc))
+	noteConflict("interfaceCount", mergeValue(&merged.InterfaceCount, srcNode.InterfaceCount, preferSrc))
+	noteConflict("concreteTypeCount", mergeValue(&merged.ConcreteTypeCount, srcNode.ConcreteTypeCount, preferSrc))
+	noteConflict("exportedFuncCount", mergeValue(&merged.ExportedFuncCount, srcNode.ExportedFuncCount, preferSrc))
+	noteConflict("exportedTypeCount", mergeValue(&merged.ExportedTypeCount, srcNode.ExportedTypeCount, preferSrc))
+	noteConflict("apiBreaking", mergeValue(&merged.APIBreaking, srcNode.APIBreaking, preferSrc))
+	noteConflict("hasMainFunc", mergeValue(&merged.HasMainFunc, srcNode.HasMainFunc, preferSrc))
+	noteConflict("testable", mergeValue(&merged.Testable, srcNode.Testable, preferSrc))
+	noteConflict("requiresCGO", mergeValue(&merged.GoCGO, srcNode.GoCGO, preferSrc))
+	noteConflict("unsafeUsage", mergeValue(&merged.UnsafeUsage, srcNode.UnsafeUsage, preferSrc))
+	noteConflict("protobufGenerated", mergeValue(&merged.ProtobufGenerated, srcNode.ProtobufGenerated, preferSrc))
+	noteConflict("embedCount", mergeValue(&merged.EmbedCount, srcNode.EmbedCount, preferSrc))
+	noteConflict("changeFrequency", mergeValue(&merged.ChangeFrequency, srcNode.ChangeFrequency, preferSrc))
+	// Directories differ between checkouts without the packages differing.
+	mergeValue(&merged.DirPath, srcNode.DirPath, preferSrc)
+	mergeValue(&merged.Rank, srcNode.Rank, preferSrc)
+	for name, value := range srcNode.Attributes {
+		current := merged.Attributes[name]
+		noteConflict(name, mergeValue(&current, value, preferSrc))
+		merged.SetAttribute(name, current)
+	}
+
+	merged.Files = sortedUnion(merged.Files, srcNode.Files)
+	merged.TestDependencies = sortedUnion(merged.TestDependencies, srcNode.TestDependencies)
+	merged.BuildConstraints = sortedUnion(merged.BuildConstraints, srcNode.BuildConstraints)
+
+	sort.Strings(conflicts)
+	return merged, conflicts
+}
+
+// mergeValue fills *dst from src when *dst is empty and reports whether both
+// are set and differ, in which case src wins only if preferSrc is true.
+func mergeValue[T comparable](dst *T, src T, preferSrc bool) (conflict bool) {
+	var zero T
+	switch {
+	case src == zero || src == *dst:
+		return false
+	case *dst == zero:
+		*dst = src
+		return false
+	case preferSrc:
+		*dst = src
+	}
+	return true
+}
+
+// mergeEdges adds the edges of src missing from dst. Edges already in dst
+// gain the source weight under WeightEdges.
+func mergeEdges(dst, src *Graph, policy DuplicateEdges) error {
+	dstEdges := indexEdges(dst)
+	for _, srcEdge := range src.Edges() {
+		dstEdge, found := dstEdges[srcEdge.Key()]
+		if !found {
+			edge := cloneEdge(srcEdge)
+			if err := dst.AddEdge(edge); err != nil {
+				return err
+			}
+			dstEdges[edge.Key()] = edge
+			continue
+		}
+		if policy == WeightEdges {
+			weight := edgeWeight(dstEdge) + edgeWeight(srcEdge)
+			dstEdge.SetAttribute(EdgeWeightAttribute, strconv.Itoa(weight))
+		}
+	}
+	return nil
+}
+
+func edgeWeight(edge *Edge) int {
+	weight, err := strconv.Atoi(edge.Attributes[EdgeWeightAttribute])
+	if err != nil || weight < 1 {
+		return 1
+	}
+	return weight
+}
+
+// mergeProvenance returns the sorted union of the graphs' provenance. A graph
+// without provenance contributes its own title.
+func mergeProvenance(dst, src *Graph) []string {
+	return sortedUnion(provenance(dst), provenance(src))
+}
+
+func provenance(g *Graph) []string {
+	if len(g.Provenance) > 0 || g.Title == "" {
+		return g.Provenance
+	}
+	return []string{g.Title}
+}
+
+// sortedUnion returns the sorted, deduplicated values of both slices, or nil
+// when both are empty.
+func sortedUnion(first, second []string) []string {
+	union := slices.Concat(first, second)
+	if len(union) == 0 {
+		return nil
+	}
+	slices.Sort(union)
+	return slices.Compact(union)
+}
+
+func cloneNode(node *Node) *Node {
+	clone := *node
+	clone.Files = slices.Clone(node.Files)
+	clone.TestDependencies = slices.Clone(node.TestDependencies)
+	clone.BuildConstraints = slices.Clone(node.BuildConstraints)
+	clone.Attributes = maps.Clone(node.Attributes)
+	return &clone
+}
+
+func cloneEdge(edge *Edge) *Edge {
+	clone := *edge
+	clone.Attributes = maps.Clone(edge.Attributes)
+	return &clone
+}
@@ -0,0 +1,149 @@
+package graph
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// newMergeFixtures returns three graphs that overlap on nodes and edges
+// without conflicting values: a and b share store, b and c share api, and
+// every graph contains the api -> store edge.
+func newMergeFixtures(t *testing.T) []*Graph {
+	t.Helper()
+
+	build := func(title string, nodes []*Node, edges [][2]string) *Graph {
+		g := New()
+		g.Title = title
+		for _, node := range nodes {
+			if err := g.AddNode(node); err != nil {
+				t.Fatalf("AddNode() error = %v", err)
+			}
+		}
+		for _, edge := range edges {
+			if err := g.AddEdge(&Edge{From: edge[0], To: edge[1], Kind: EdgeImport}); err != nil {
+				t.Fatalf("AddEdge() error = %v", err)
+			}
+		}
+		return g
+	}
+
+	a := build("a", []*Node{
+		{ID: "api", Kind: KindPackage},
+		{ID: "store", Kind: KindPackage, Name: "store", Files: []string{"store.go"}},
+	}, [][2]string{{"api", "store"}})
+	b := build("b", []*Node{
+		{ID: "api", Kind: KindPackage, Name: "api", Attributes: map[string]string{"loc": "40"}},
+		{ID: "store", Kind: KindPackage, InterfaceCount: 2, GoCGO: true, Files: []string{"cache.go"}},
+		{ID: "cmd", Kind: KindPackage, HasMainFunc: true},
+	}, [][2]string{{"api", "store"}, {"cmd", "api"}})
+	c := build("c", []*Node{
+		{ID: "api", Kind: KindPackage, Attributes: map[string]string{"fan_in": "1"}},
+		{ID: "store", Kind: KindPackage},
+		{ID: "cmd", Kind: KindPackage},
+	}, [][2]string{{"api", "store"}, {"cmd", "api"}, {"cmd", "store"}})
+
+	return []*Graph{a, b, c}
+}
+
+func TestMerge_OrderIndependent(t *testing.T) {
+	orders := [][]int{{0, 1, 2}, {0, 2, 1}, {1, 0, 2}, {1, 2, 0}, {2, 0, 1}, {2, 1, 0}}
+
+	for _, edgePolicy := range []DuplicateEdges{DedupeEdges, WeightEdges} {
+		t.Run(string(edgePolicy), func(t *testing.T) {
+			policy := ConflictPolicy{Attributes: ErrorOnConflict, Edges: edgePolicy}
+
+			var reference *Graph
+			for _, order := range orders {
+				fixtures := newMergeFixtures(t)
+				merged := New()
+				for _, index := range order {
+					if err := Merge(merged, fixtures[index], policy); err != nil {
+						t.Fatalf("Merge() order %v error = %v", order, err)
+					}
+				}
+
+				if reference == nil {
+					reference = merged
+					continue
+				}
+				if result := Diff(reference, merged); result.HasChanges() {
+					t.Errorf("order %v differs from order %v: %+v", order, orders[0], result)
+				}
+				if !slices.Equal(merged.Provenance, reference.Provenance) {
+					t.Errorf("order %v provenance = %v, want %v", order, merged.Provenance, reference.Provenance)
+				}
+			}
+
+			if len(reference.Nodes()) != 3 || len(reference.Edges()) != 3 {
+				t.Errorf("expected 3 nodes and 3 edges, got %d and %d", len(reference.Nodes()), len(reference.Edges()))
+			}
+			if !slices.Equal(reference.Provenance, []string{"a", "b", "c"}) {
+				t.Errorf("Provenance = %v, want [a b c]", reference.Provenance)
+			}
+			store, _ := reference.Node("store")
+			// Only b's store requires cgo, which must survive every order.
+			if store.Name != "store" || store.InterfaceCount != 2 || !store.GoCGO || !slices.Equal(store.Files, []string{"cache.go", "store.go"}) {
+				t.Errorf("expected store values from every graph, got %+v", store)
+			}
+		})
+	}
+}
+
+func TestMerge_EdgeWeights(t *testing.T) {
+	fixtures := newMergeFixtures(t)
+	merged := New()
+	for _, fixture := range fixtures {
+		if err := Merge(merged, fixture, ConflictPolicy{Attributes: PreferDst, Edges: WeightEdges}); err != nil {
+			t.Fatalf("Merge() error = %v", err)
+		}
+	}
+
+	wantWeights := map[EdgeKey]string{
+		{From: "api", To: "store", Kind: EdgeImport}: "3",
+		{From: "cmd", To: "api", Kind: EdgeImport}:   "2",
+		{From: "cmd", To: "store", Kind: EdgeImport}: "",
+	}
+	for _, edge := range merged.Edges() {
+		if got := edge.Attributes[EdgeWeightAttribute]; got != wantWeights[edge.Key()] {
+			t.Errorf("edge %+v weight = %q, want %q", edge.Key(), got, wantWeights[edge.Key()])
+		}
+	}
+}
+
+func TestMerge_AttributeConflicts(t *testing.T) {
+	newGraph := func(loc string) *Graph {
+		g := New()
+		_ = g.AddNode(&Node{ID: "api", Kind: KindPackage, Attributes: map[string]string{"loc": loc}})
+		return g
+	}
+
+	tests := []struct {
+		policy  AttributeConflict
+		wantLoc string
+		wantErr error
+	}{
+		{policy: PreferDst, wantLoc: "10"},
+		{policy: PreferSrc, wantLoc: "20"},
+		{policy: ErrorOnConflict, wantLoc: "10", wantErr: ErrMergeConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			dst := newGraph("10")
+			err := Merge(dst, newGraph("20"), ConflictPolicy{Attributes: tt.policy, Edges: DedupeEdges})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Merge() error = %v, want %v", err, tt.wantErr)
+			}
+			if node, _ := dst.Node("api"); node.Attributes["loc"] != tt.wantLoc {
+				t.Errorf("loc = %q, want %q", node.Attributes["loc"], tt.wantLoc)
+			}
+		})
+	}
+}
+
+func TestMerge_InvalidPolicy(t *testing.T) {
+	if err := Merge(New(), New(), ConflictPolicy{}); err == nil {
+		t.Error("expected an error for the zero ConflictPolicy")
+	}
+}
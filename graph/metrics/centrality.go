@@ -0,0 +1,82 @@
+package metrics
+
+import "github.com/Desgue/codegraph/graph"
+
+// ClosenessCentrality returns, for every node, how close it is to all other
+// nodes, measured over shortest paths that ignore edge direction. On a connected
+// graph this is the classic closeness: the number of other nodes divided by the
+// sum of distances to them. On a disconnected graph, where some distances are
+// infinite, the harmonic variant is used instead: the mean of 1/distance over
+// all other nodes, with unreachable nodes contributing 0. Both range from 0 to 1.
+func ClosenessCentrality(g *graph.Graph) map[string]float64 {
+	adjacency := undirectedAdjacency(g)
+	nodeCount := len(g.Nodes())
+
+	distances := make(map[string]map[string]int, nodeCount)
+	connected := true
+	for _, node := range g.Nodes() {
+		distances[node.ID] = breadthFirstDistances(adjacency, node.ID)
+		if len(distances[node.ID]) < nodeCount {
+			connected = false
+		}
+	}
+
+	closeness := make(map[string]float64, nodeCount)
+	for _, node := range g.Nodes() {
+		if nodeCount < 2 {
+			closeness[node.ID] = 0
+			continue
+		}
+		if connected {
+			closeness[node.ID] = classicCloseness(distances[node.ID])
+		} else {
+			closeness[node.ID] = harmonicCloseness(distances[node.ID], nodeCount)
+		}
+	}
+	return closeness
+}
+
+func classicCloseness(distances map[string]int) float64 {
+	total := 0
+	for _, distance := range distances {
+		total += distance
+	}
+	return float64(len(distances)-1) / float64(total)
+}
+
+func harmonicCloseness(distances map[string]int, nodeCount int) float64 {
+	sum := 0.0
+	for _, distance := range distances {
+		if distance > 0 {
+			sum += 1 / float64(distance)
+		}
+	}
+	return sum / float64(nodeCount-1)
+}
+
+// undirectedAdjacency precomputes each node's neighbors in both directions so
+// repeated breadth-first searches do not re-sort edge lists.
+func undirectedAdjacency(g *graph.Graph) map[string][]string {
+	adjacency := make(map[string][]string, len(g.Nodes()))
+	for _, node := range g.Nodes() {
+		adjacency[node.ID] = g.Neighbors(node.ID, graph.Both, nil)
+	}
+	return adjacency
+}
+
+// breadthFirstDistances returns the hop count from source to every node it reaches, including itself.
+func breadthFirstDistances(adjacency map[string][]string, source string) map[string]int {
+	distances := map[string]int{source: 0}
+	queue := []string{source}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, neighbor := range adjacency[current] {
+			if _, seen := distances[neighbor]; !seen {
+				distances[neighbor] = distances[current] + 1
+				queue = append(queue, neighbor)
+			}
+		}
+	}
+	return distances
+}
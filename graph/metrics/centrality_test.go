@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+func assertScores(t *testing.T, got, want map[string]float64) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d scores, want %d: %v", len(got), len(want), got)
+	}
+	for id, wantValue := range want {
+		if math.Abs(got[id]-wantValue) > 1e-9 {
+			t.Errorf("score[%q] = %v, want %v", id, got[id], wantValue)
+		}
+	}
+}
+
+func TestClosenessCentrality_Connected(t *testing.T) {
+	// Path a -> b -> c: b is one hop from both ends; a and c are 1 + 2 hops from the others.
+	g := graph.New()
+	for _, id := range []string{"a", "b", "c"} {
+		_ = g.AddNode(&graph.Node{ID: id})
+	}
+	_ = g.AddEdge(&graph.Edge{From: "a", To: "b", Kind: graph.EdgeImport})
+	_ = g.AddEdge(&graph.Edge{From: "b", To: "c", Kind: graph.EdgeImport})
+
+	assertScores(t, ClosenessCentrality(g), map[string]float64{"a": 2.0 / 3, "b": 1, "c": 2.0 / 3})
+}
+
+func TestClosenessCentrality_DisconnectedUsesHarmonic(t *testing.T) {
+	// The fixture has an isolated node, so every score is the harmonic mean over 3 other nodes.
+	// api:   cmd 1, store 1     -> (1 + 1) / 3
+	// cmd:   api 1, store 1     -> (1 + 1) / 3
+	// store: api 1, cmd 1       -> (1 + 1) / 3
+	// tools: nothing reachable  -> 0
+	want := map[string]float64{"api": 2.0 / 3, "cmd": 2.0 / 3, "store": 2.0 / 3, "tools": 0}
+
+	assertScores(t, ClosenessCentrality(newFixtureGraph(t)), want)
+}
+
+func TestClosenessCentrality_SmallGraphs(t *testing.T) {
+	if scores := ClosenessCentrality(graph.New()); len(scores) != 0 {
+		t.Errorf("expected no scores for an empty graph, got %v", scores)
+	}
+
+	single := graph.New()
+	_ = single.AddNode(&graph.Node{ID: "only"})
+	assertScores(t, ClosenessCentrality(single), map[string]float64{"only": 0})
+}
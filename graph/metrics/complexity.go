@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// Complexity aggregates the cyclomatic complexity of the functions in a set of files.
+type Complexity struct {
+	Functions int
+	Total     int
+	Max       int
+}
+
+// PackageComplexity sums the cyclomatic complexity of every function and method
+// declared in files. Function literals count towards their enclosing declaration.
+func PackageComplexity(files []*ast.File) Complexity {
+	var complexity Complexity
+	for _, file := range files {
+		for _, declaration := range file.Decls {
+			function, isFunction := declaration.(*ast.FuncDecl)
+			if !isFunction || function.Body == nil {
+				continue
+			}
+			functionComplexity := CyclomaticComplexity(function.Body)
+			complexity.Functions++
+			complexity.Total += functionComplexity
+			complexity.Max = max(complexity.Max, functionComplexity)
+		}
+	}
+	return complexity
+}
+
+// CyclomaticComplexity returns 1 plus the number of decision points in body:
+// if, for and range statements, non-default case and select clauses, and the
+// && and || operators.
+func CyclomaticComplexity(body ast.Node) int {
+	complexity := 1
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch statement := node.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			complexity++
+		case *ast.CaseClause:
+			if statement.List != nil {
+				complexity++
+			}
+		case *ast.CommClause:
+			if statement.Comm != nil {
+				complexity++
+			}
+		case *ast.BinaryExpr:
+			if statement.Op == token.LAND || statement.Op == token.LOR {
+				complexity++
+			}
+		}
+		return true
+	})
+	return complexity
+}
@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+const complexitySource = `package fixture
+
+func straight() {}
+
+func branches(values []int, flag bool) int {
+	total := 0
+	for _, value := range values {
+		if value > 0 && flag {
+			total += value
+		}
+	}
+	switch total {
+	case 0:
+		return 0
+	case 1, 2:
+		return 1
+	default:
+		return total
+	}
+}
+
+func (s *server) loop(done chan struct{}, ready bool) {
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		if !ready || s == nil {
+			return
+		}
+	}
+}
+
+type server struct{}
+`
+
+func parseFixture(t *testing.T, fileSet *token.FileSet, source string) *ast.File {
+	t.Helper()
+
+	file, err := parser.ParseFile(fileSet, "fixture.go", source, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("failed to parse fixture: %v", err)
+	}
+	return file
+}
+
+func TestCyclomaticComplexity(t *testing.T) {
+	file := parseFixture(t, token.NewFileSet(), complexitySource)
+
+	// straight: 1
+	// branches: 1 + range + if + && + 2 non-default cases = 6
+	// loop:     1 + for + 1 non-default comm clause + if + || = 5
+	want := map[string]int{"straight": 1, "branches": 6, "loop": 5}
+
+	for _, declaration := range file.Decls {
+		function, isFunction := declaration.(*ast.FuncDecl)
+		if !isFunction {
+			continue
+		}
+		if got := CyclomaticComplexity(function.Body); got != want[function.Name.Name] {
+			t.Errorf("CyclomaticComplexity(%s) = %d, want %d", function.Name.Name, got, want[function.Name.Name])
+		}
+	}
+}
+
+func TestPackageComplexity(t *testing.T) {
+	file := parseFixture(t, token.NewFileSet(), complexitySource)
+
+	got := PackageComplexity([]*ast.File{file})
+	want := Complexity{Functions: 3, Total: 12, Max: 6}
+	if got != want {
+		t.Errorf("PackageComplexity() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,73 @@
+// Package metrics computes structural metrics over a graph.Graph and, where a
+// metric needs source detail, over parsed AST files. Graph metrics return
+// results keyed by node ID so callers can copy them into node attributes,
+// reports or threshold checks without recomputing them.
+package metrics
+
+import "github.com/Desgue/codegraph/graph"
+
+// Coupling metrics describe production architecture, so they only follow
+// import edges (or call edges, for the Call variants); test-only imports and
+// calls from tests are ignored.
+
+// FanIn returns, for every node, the number of distinct nodes importing it
+// (afferent coupling).
+func FanIn(g *graph.Graph) map[string]int {
+	return countNeighbors(g, graph.Incoming, graph.EdgeImport)
+}
+
+// FanOut returns, for every node, the number of distinct nodes it imports
+// (efferent coupling).
+func FanOut(g *graph.Graph) map[string]int {
+	return countNeighbors(g, graph.Outgoing, graph.EdgeImport)
+}
+
+// Instability returns fan-out / (fan-in + fan-out) for every node, ranging from
+// 0 (maximally stable, only depended upon) to 1 (maximally unstable, only
+// depends on others). Isolated nodes have an instability of 0.
+func Instability(g *graph.Graph) map[string]float64 {
+	fanIn := FanIn(g)
+	fanOut := FanOut(g)
+
+	instability := make(map[string]float64, len(g.Nodes()))
+	for _, node := range g.Nodes() {
+		instability[node.ID] = InstabilityOf(fanIn[node.ID], fanOut[node.ID])
+	}
+	return instability
+}
+
+// InstabilityOf returns the Instability of a node with the given fan-in and
+// fan-out, for callers that count them for a few nodes only.
+func InstabilityOf(fanIn, fanOut int) float64 {
+	if fanIn+fanOut == 0 {
+		return 0
+	}
+	return float64(fanOut) / float64(fanIn+fanOut)
+}
+
+// CallFanIn returns, for every node, the number of distinct function nodes
+// calling it. Callers outside the graph, such as the standard library, are not
+// nodes and so never count.
+func CallFanIn(g *graph.Graph) map[string]int {
+	return countNeighbors(g, graph.Incoming, graph.EdgeCall)
+}
+
+// CallFanOut returns, for every node, the number of distinct function nodes it
+// calls. Callees outside the graph never count.
+func CallFanOut(g *graph.Graph) map[string]int {
+	return countNeighbors(g, graph.Outgoing, graph.EdgeCall)
+}
+
+// TestCallFanIn returns, for every node, the number of distinct test functions
+// calling it.
+func TestCallFanIn(g *graph.Graph) map[string]int {
+	return countNeighbors(g, graph.Incoming, graph.EdgeTestCall)
+}
+
+func countNeighbors(g *graph.Graph, direction graph.Direction, kind graph.EdgeKind) map[string]int {
+	counts := make(map[string]int, len(g.Nodes()))
+	for _, node := range g.Nodes() {
+		counts[node.ID] = len(g.Neighbors(node.ID, direction, []graph.EdgeKind{kind}))
+	}
+	return counts
+}
@@ -0,0 +1,123 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+	"github.com/Desgue/codegraph/internal/testutil/repogen"
+)
+
+// newFixtureGraph builds the tiny graph used by the metric tests:
+//
+//	cmd -> api -> store
+//	cmd -> store
+//	tools (isolated)
+func newFixtureGraph(t *testing.T) *graph.Graph {
+	t.Helper()
+
+	g := graph.New()
+	for _, id := range []string{"api", "cmd", "store", "tools"} {
+		if err := g.AddNode(&graph.Node{ID: id, Kind: graph.KindPackage}); err != nil {
+			t.Fatalf("AddNode(%q) failed: %v", id, err)
+		}
+	}
+	for _, edge := range [][2]string{{"cmd", "api"}, {"api", "store"}, {"cmd", "store"}} {
+		if err := g.AddEdge(&graph.Edge{From: edge[0], To: edge[1], Kind: graph.EdgeImport}); err != nil {
+			t.Fatalf("AddEdge(%v) failed: %v", edge, err)
+		}
+	}
+	return g
+}
+
+func TestFanInFanOut(t *testing.T) {
+	g := newFixtureGraph(t)
+	// A duplicate parallel edge must not be counted twice.
+	if err := g.AddEdge(&graph.Edge{From: "cmd", To: "api", Kind: graph.EdgeImport}); err != nil {
+		t.Fatalf("AddEdge failed: %v", err)
+	}
+
+	wantFanIn := map[string]int{"api": 1, "cmd": 0, "store": 2, "tools": 0}
+	wantFanOut := map[string]int{"api": 1, "cmd": 2, "store": 0, "tools": 0}
+
+	fanIn, fanOut := FanIn(g), FanOut(g)
+	for id := range wantFanIn {
+		if fanIn[id] != wantFanIn[id] {
+			t.Errorf("FanIn[%q] = %d, want %d", id, fanIn[id], wantFanIn[id])
+		}
+		if fanOut[id] != wantFanOut[id] {
+			t.Errorf("FanOut[%q] = %d, want %d", id, fanOut[id], wantFanOut[id])
+		}
+	}
+	if len(fanIn) != 4 || len(fanOut) != 4 {
+		t.Errorf("expected a result for every node, got %d fan-in and %d fan-out", len(fanIn), len(fanOut))
+	}
+}
+
+func TestFanInFanOut_GeneratedDAG(t *testing.T) {
+	repo := repogen.Plan(repogen.Config{Packages: 300, Topology: repogen.RandomDAG, Density: 0.05, Seed: 11})
+
+	fanIn, fanOut := FanIn(repo.Graph()), FanOut(repo.Graph())
+	wantFanIn := make(map[string]int)
+	for _, path := range repo.Packages {
+		if fanOut[path] != len(repo.Imports[path]) {
+			t.Errorf("FanOut[%q] = %d, want %d", path, fanOut[path], len(repo.Imports[path]))
+		}
+		for _, imported := range repo.Imports[path] {
+			wantFanIn[imported]++
+		}
+	}
+	for _, path := range repo.Packages {
+		if fanIn[path] != wantFanIn[path] {
+			t.Errorf("FanIn[%q] = %d, want %d", path, fanIn[path], wantFanIn[path])
+		}
+	}
+}
+
+func TestInstability(t *testing.T) {
+	want := map[string]float64{"api": 0.5, "cmd": 1, "store": 0, "tools": 0}
+
+	instability := Instability(newFixtureGraph(t))
+	for id, wantValue := range want {
+		if got, found := instability[id]; !found || got != wantValue {
+			t.Errorf("Instability[%q] = %v, want %v", id, got, wantValue)
+		}
+	}
+}
+
+func TestCallFanInFanOut(t *testing.T) {
+	// main calls run twice and log once, run calls log, TestRun calls run and
+	// log; the package node's imports must not leak into call counts.
+	g := newFixtureGraph(t)
+	for _, id := range []string{"main", "run", "log", "TestRun"} {
+		if err := g.AddNode(&graph.Node{ID: id, Kind: graph.KindFunction}); err != nil {
+			t.Fatalf("AddNode(%q) failed: %v", id, err)
+		}
+	}
+	edges := []graph.Edge{
+		{From: "main", To: "run", Kind: graph.EdgeCall},
+		{From: "main", To: "run", Kind: graph.EdgeCall},
+		{From: "main", To: "log", Kind: graph.EdgeCall},
+		{From: "run", To: "log", Kind: graph.EdgeCall},
+		{From: "TestRun", To: "run", Kind: graph.EdgeTestCall},
+		{From: "TestRun", To: "log", Kind: graph.EdgeTestCall},
+	}
+	for _, edge := range edges {
+		if err := g.AddEdge(&edge); err != nil {
+			t.Fatalf("AddEdge(%v) failed: %v", edge, err)
+		}
+	}
+
+	want := map[string][3]int{
+		"main":    {0, 2, 0},
+		"run":     {1, 1, 1},
+		"log":     {2, 0, 1},
+		"TestRun": {0, 0, 0},
+		"cmd":     {0, 0, 0},
+	}
+	fanIn, fanOut, testFanIn := CallFanIn(g), CallFanOut(g), TestCallFanIn(g)
+	for id, values := range want {
+		if got := [3]int{fanIn[id], fanOut[id], testFanIn[id]}; got != values {
+			t.Errorf("%s fan-in, fan-out, test fan-in = %v, want %v", id, got, values)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package metrics
+
+import (
+	"slices"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+// DepthCyclic is the depth of a node in an import cycle, whose longest import
+// path is unbounded.
+const DepthCyclic = -1
+
+// Depth returns, for every node, the number of imports on the longest import
+// path beneath it: 0 for a node importing nothing in the graph. Cycles are
+// condensed first, so nodes in a cycle get DepthCyclic and a node importing a
+// cycle counts the whole cycle as one step.
+func Depth(g *graph.Graph) map[string]int {
+	condensed := condense(g)
+
+	depth := make(map[string]int, len(g.Nodes()))
+	for i, component := range condensed.components {
+		for _, id := range component {
+			depth[id] = condensed.depth[i]
+			if condensed.cyclic[i] {
+				depth[id] = DepthCyclic
+			}
+		}
+	}
+	return depth
+}
+
+// LongestChain returns the longest import path in g as a list of strongly
+// connected components, from the importing end down to a leaf. A component
+// with more than one ID is an import cycle traversed as a single step. Ties
+// are broken by the smallest ID, so the result is deterministic. It returns
+// nil for an empty graph.
+func LongestChain(g *graph.Graph) [][]string {
+	condensed := condense(g)
+	if len(condensed.components) == 0 {
+		return nil
+	}
+
+	// Components are sorted by first ID, so the first maximum wins ties.
+	current := 0
+	for i := range condensed.components {
+		if condensed.depth[i] > condensed.depth[current] {
+			current = i
+		}
+	}
+
+	chain := [][]string{condensed.components[current]}
+	for condensed.depth[current] > 0 {
+		for _, successor := range condensed.successors[current] {
+			if condensed.depth[successor] == condensed.depth[current]-1 {
+				current = successor
+				break
+			}
+		}
+		chain = append(chain, condensed.components[current])
+	}
+	return chain
+}
+
+// condensation is the DAG of a graph's import SCCs, with each component's
+// depth computed by a memoized DFS.
+type condensation struct {
+	components [][]string
+	cyclic     []bool
+	// successors holds the sorted, distinct components each component imports.
+	successors [][]int
+	depth      []int
+}
+
+func condense(g *graph.Graph) *condensation {
+	importKinds := []graph.EdgeKind{graph.EdgeImport}
+	components := g.StronglyConnectedComponents(importKinds)
+
+	componentOf := make(map[string]int, len(g.Nodes()))
+	for i, component := range components {
+		for _, id := range component {
+			componentOf[id] = i
+		}
+	}
+
+	condensed := &condensation{
+		components: components,
+		cyclic:     make([]bool, len(components)),
+		successors: make([][]int, len(components)),
+		depth:      make([]int, len(components)),
+	}
+	for i, component := range components {
+		condensed.cyclic[i] = len(component) > 1
+		for _, id := range component {
+			for _, neighbor := range g.Neighbors(id, graph.Outgoing, importKinds) {
+				successor := componentOf[neighbor]
+				if successor == i {
+					condensed.cyclic[i] = true
+				} else if !slices.Contains(condensed.successors[i], successor) {
+					condensed.successors[i] = append(condensed.successors[i], successor)
+				}
+			}
+		}
+		slices.Sort(condensed.successors[i])
+	}
+	condensed.computeDepths()
+	return condensed
+}
+
+// computeDepths fills depth with an iterative post-order DFS so long chains
+// cannot overflow the stack. The condensation is acyclic, so every component
+// is finished exactly once.
+func (c *condensation) computeDepths() {
+	finished := make([]bool, len(c.components))
+	for root := range c.components {
+		if finished[root] {
+			continue
+		}
+		stack := []int{root}
+		for len(stack) > 0 {
+			top := stack[len(stack)-1]
+			pending := false
+			for _, successor := range c.successors[top] {
+				if !finished[successor] {
+					stack = append(stack, successor)
+					pending = true
+				}
+			}
+			if pending {
+				continue
+			}
+
+			stack = stack[:len(stack)-1]
+			if finished[top] {
+				continue
+			}
+			for _, successor := range c.successors[top] {
+				c.depth[top] = max(c.depth[top], c.depth[successor]+1)
+			}
+			finished[top] = true
+		}
+	}
+}
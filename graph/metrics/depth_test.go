@@ -0,0 +1,121 @@
+package metrics
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+	"github.com/Desgue/codegraph/internal/testutil/repogen"
+)
+
+// newDiamondGraph builds a diamond whose bottom imports a two-node cycle:
+//
+//	top -> left -> bottom -> x <-> y
+//	top -> right -> bottom
+//	top -> bottom
+//	alone
+//
+// plus a test-only import that must not lengthen any path.
+func newDiamondGraph(t *testing.T) *graph.Graph {
+	t.Helper()
+
+	g := graph.New()
+	for _, id := range []string{"alone", "bottom", "left", "right", "top", "x", "y"} {
+		if err := g.AddNode(&graph.Node{ID: id, Kind: graph.KindPackage}); err != nil {
+			t.Fatalf("AddNode(%q) failed: %v", id, err)
+		}
+	}
+	edges := []graph.Edge{
+		{From: "top", To: "left", Kind: graph.EdgeImport},
+		{From: "top", To: "right", Kind: graph.EdgeImport},
+		{From: "top", To: "bottom", Kind: graph.EdgeImport},
+		{From: "left", To: "bottom", Kind: graph.EdgeImport},
+		{From: "right", To: "bottom", Kind: graph.EdgeImport},
+		{From: "bottom", To: "x", Kind: graph.EdgeImport},
+		{From: "x", To: "y", Kind: graph.EdgeImport},
+		{From: "y", To: "x", Kind: graph.EdgeImport},
+		{From: "y", To: "top", Kind: graph.EdgeTestImport},
+	}
+	for _, edge := range edges {
+		if err := g.AddEdge(&edge); err != nil {
+			t.Fatalf("AddEdge(%v) failed: %v", edge, err)
+		}
+	}
+	return g
+}
+
+func TestDepth(t *testing.T) {
+	want := map[string]int{
+		"alone": 0, "bottom": 1, "left": 2, "right": 2, "top": 3,
+		"x": DepthCyclic, "y": DepthCyclic,
+	}
+	if got := Depth(newDiamondGraph(t)); !reflect.DeepEqual(got, want) {
+		t.Errorf("Depth() = %v, want %v", got, want)
+	}
+}
+
+func TestDepth_GeneratedDAG(t *testing.T) {
+	repo := repogen.Plan(repogen.Config{Packages: 300, Topology: repogen.RandomDAG, Density: 0.05, Seed: 5})
+
+	// Packages only import earlier ones, so depths fill in generation order.
+	want := make(map[string]int)
+	for _, path := range repo.Packages {
+		want[path] = 0
+		for _, imported := range repo.Imports[path] {
+			want[path] = max(want[path], want[imported]+1)
+		}
+	}
+	if got := Depth(repo.Graph()); !reflect.DeepEqual(got, want) {
+		t.Errorf("Depth() differs from the depths of the generated imports")
+	}
+
+	chain := repogen.Plan(repogen.Config{Packages: 300, Topology: repogen.Chain})
+	if got := len(LongestChain(chain.Graph())); got != 300 {
+		t.Errorf("len(LongestChain(chain)) = %d, want 300", got)
+	}
+}
+
+func TestDepth_SelfLoopIsCyclic(t *testing.T) {
+	g := graph.New()
+	if err := g.AddNode(&graph.Node{ID: "a", Kind: graph.KindPackage}); err != nil {
+		t.Fatalf("AddNode failed: %v", err)
+	}
+	if err := g.AddEdge(&graph.Edge{From: "a", To: "a", Kind: graph.EdgeImport}); err != nil {
+		t.Fatalf("AddEdge failed: %v", err)
+	}
+	if got := Depth(g)["a"]; got != DepthCyclic {
+		t.Errorf("Depth()[a] = %d, want DepthCyclic", got)
+	}
+}
+
+func TestLongestChain(t *testing.T) {
+	// left and right tie; left wins as the smaller ID.
+	want := [][]string{{"top"}, {"left"}, {"bottom"}, {"x", "y"}}
+	if got := LongestChain(newDiamondGraph(t)); !reflect.DeepEqual(got, want) {
+		t.Errorf("LongestChain() = %v, want %v", got, want)
+	}
+	if got := LongestChain(graph.New()); got != nil {
+		t.Errorf("LongestChain(empty) = %v, want nil", got)
+	}
+}
+
+func TestDepth_DeepChainIsIterative(t *testing.T) {
+	const length = 100000
+	g := graph.New()
+	for i := range length {
+		if err := g.AddNode(&graph.Node{ID: fmt.Sprintf("n%06d", i), Kind: graph.KindPackage}); err != nil {
+			t.Fatalf("AddNode failed: %v", err)
+		}
+		if i > 0 {
+			edge := &graph.Edge{From: fmt.Sprintf("n%06d", i-1), To: fmt.Sprintf("n%06d", i), Kind: graph.EdgeImport}
+			if err := g.AddEdge(edge); err != nil {
+				t.Fatalf("AddEdge failed: %v", err)
+			}
+		}
+	}
+
+	if got := Depth(g)["n000000"]; got != length-1 {
+		t.Errorf("Depth()[n000000] = %d, want %d", got, length-1)
+	}
+}
@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"math"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+// DefaultHITSIterations is the number of update rounds HITS callers use
+// unless they need another trade-off between precision and time.
+const DefaultHITSIterations = 100
+
+// HITS returns Kleinberg's hub and authority scores of every node after the
+// given number of update rounds. A node's authority is the sum of the hub
+// scores of the nodes with edges to it, and its hub score the sum of the
+// authorities it has edges to: hubs import many important packages,
+// authorities are imported by many hubs. Both vectors are scaled to unit
+// length each round, so scores range from 0 to 1; on a graph without edges
+// every score is 0.
+func HITS(g *graph.Graph, iterations int) (hub, authority map[string]float64) {
+	nodes := g.Nodes()
+	outgoing := make(map[string][]string, len(nodes))
+	incoming := make(map[string][]string, len(nodes))
+	hub = make(map[string]float64, len(nodes))
+	authority = make(map[string]float64, len(nodes))
+	for _, node := range nodes {
+		outgoing[node.ID] = g.Neighbors(node.ID, graph.Outgoing, nil)
+		incoming[node.ID] = g.Neighbors(node.ID, graph.Incoming, nil)
+		hub[node.ID] = 1
+		authority[node.ID] = 0
+	}
+
+	for range iterations {
+		for _, node := range nodes {
+			sum := 0.0
+			for _, importer := range incoming[node.ID] {
+				sum += hub[importer]
+			}
+			authority[node.ID] = sum
+		}
+		normalizeScores(authority)
+		for _, node := range nodes {
+			sum := 0.0
+			for _, imported := range outgoing[node.ID] {
+				sum += authority[imported]
+			}
+			hub[node.ID] = sum
+		}
+		normalizeScores(hub)
+	}
+	return hub, authority
+}
+
+// normalizeScores scales scores to unit Euclidean length, leaving an all-zero
+// vector as it is.
+func normalizeScores(scores map[string]float64) {
+	sumOfSquares := 0.0
+	for _, score := range scores {
+		sumOfSquares += score * score
+	}
+	if sumOfSquares == 0 {
+		return
+	}
+	norm := math.Sqrt(sumOfSquares)
+	for id := range scores {
+		scores[id] /= norm
+	}
+}
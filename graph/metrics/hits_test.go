@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+func TestHITS(t *testing.T) {
+	// cmd imports api and store, api imports store. The authorities converge to
+	// the principal eigenvector of [[1 1] [1 2]] over (api, store), (1, φ)
+	// scaled to unit length, and the hubs follow: cmd imports both authorities.
+	phi := (1 + math.Sqrt(5)) / 2
+	norm := math.Sqrt(1 + phi*phi)
+
+	hub, authority := HITS(newFixtureGraph(t), DefaultHITSIterations)
+
+	assertScores(t, authority, map[string]float64{"api": 1 / norm, "cmd": 0, "store": phi / norm, "tools": 0})
+	assertScores(t, hub, map[string]float64{"api": 1 / norm, "cmd": phi / norm, "store": 0, "tools": 0})
+}
+
+func TestHITS_NoEdges(t *testing.T) {
+	g := graph.New()
+	_ = g.AddNode(&graph.Node{ID: "only"})
+
+	hub, authority := HITS(g, DefaultHITSIterations)
+	assertScores(t, hub, map[string]float64{"only": 0})
+	assertScores(t, authority, map[string]float64{"only": 0})
+}
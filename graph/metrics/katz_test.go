@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+func TestKatzCentrality_StarHubScoresHighest(t *testing.T) {
+	// Four leaves import the hub; the hub imports nothing.
+	g := graph.New()
+	_ = g.AddNode(&graph.Node{ID: "hub"})
+	for _, id := range []string{"a", "b", "c", "d"} {
+		_ = g.AddNode(&graph.Node{ID: id})
+		_ = g.AddEdge(&graph.Edge{From: id, To: "hub", Kind: graph.EdgeImport})
+	}
+
+	scores := KatzCentrality(g, DefaultKatzAlpha)
+
+	assertScores(t, scores, map[string]float64{"hub": 1.04, "a": 1, "b": 1, "c": 1, "d": 1})
+	for _, leaf := range []string{"a", "b", "c", "d"} {
+		if scores["hub"] <= scores[leaf] {
+			t.Errorf("hub score %v is not above leaf %s score %v", scores["hub"], leaf, scores[leaf])
+		}
+	}
+}
+
+func TestKatzCentrality_CountsIndirectPaths(t *testing.T) {
+	// cmd imports api and store, api imports store: store is reached by the
+	// paths api, cmd and cmd -> api.
+	alpha := 0.1
+	assertScores(t, KatzCentrality(newFixtureGraph(t), alpha), map[string]float64{
+		"cmd":   1,
+		"tools": 1,
+		"api":   1 + alpha,
+		"store": 1 + 2*alpha + alpha*alpha,
+	})
+}
+
+func TestKatzCentrality_Cycle(t *testing.T) {
+	// On a two-node cycle each score is 1 + alpha·(the other), 1 / (1 - alpha).
+	g := graph.New()
+	_ = g.AddNode(&graph.Node{ID: "a"})
+	_ = g.AddNode(&graph.Node{ID: "b"})
+	_ = g.AddEdge(&graph.Edge{From: "a", To: "b", Kind: graph.EdgeImport})
+	_ = g.AddEdge(&graph.Edge{From: "b", To: "a", Kind: graph.EdgeImport})
+
+	assertScores(t, KatzCentrality(g, 0.5), map[string]float64{"a": 2, "b": 2})
+}
@@ -0,0 +1,21 @@
+package metrics
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// LinesOfCode rolls up the physical line counts of files, including comments
+// and blank lines. Files not recorded in fileSet are skipped.
+func LinesOfCode(fileSet *token.FileSet, files []*ast.File) int {
+	if fileSet == nil {
+		return 0
+	}
+	lines := 0
+	for _, file := range files {
+		if tokenFile := fileSet.File(file.Pos()); tokenFile != nil {
+			lines += tokenFile.LineCount()
+		}
+	}
+	return lines
+}
@@ -0,0 +1,20 @@
+package metrics
+
+import (
+	"go/ast"
+	"go/token"
+	"testing"
+)
+
+func TestLinesOfCode(t *testing.T) {
+	fileSet := token.NewFileSet()
+	first := parseFixture(t, fileSet, "package fixture\n\n// Answer is documented.\nconst Answer = 42\n")
+	second := parseFixture(t, fileSet, "package fixture\n\nfunc f() {\n}\n")
+
+	if got := LinesOfCode(fileSet, []*ast.File{first, second}); got != 8 {
+		t.Errorf("LinesOfCode() = %d, want 8", got)
+	}
+	if got := LinesOfCode(nil, []*ast.File{first}); got != 0 {
+		t.Errorf("LinesOfCode(nil file set) = %d, want 0", got)
+	}
+}
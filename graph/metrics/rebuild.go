@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"math/bits"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+// RebuildImpact estimates how much rebuilds when a package changes: the
+// packages transitively importing it and their total lines of code.
+type RebuildImpact struct {
+	Packages    int
+	LinesOfCode int
+}
+
+// RebuildImpacts returns the RebuildImpact of every node of g over import
+// edges, summing linesOfCode, keyed by node ID, over the reverse import
+// closure. A node never counts itself, but the other members of an import
+// cycle it belongs to do. Closures are memoized per strongly connected
+// component of the condensation, and each dependent is counted once however
+// many import paths lead to it.
+func RebuildImpacts(g *graph.Graph, linesOfCode map[string]int) map[string]RebuildImpact {
+	condensed := condense(g)
+	count := len(condensed.components)
+
+	predecessors := make([][]int, count)
+	for i, successors := range condensed.successors {
+		for _, successor := range successors {
+			predecessors[successor] = append(predecessors[successor], i)
+		}
+	}
+
+	// dependents[i] is the set of components transitively importing i, as a
+	// bitset so diamonds are never counted twice.
+	words := (count + 63) / 64
+	dependents := make([][]uint64, count)
+	finished := make([]bool, count)
+	for root := range count {
+		if finished[root] {
+			continue
+		}
+		stack := []int{root}
+		for len(stack) > 0 {
+			top := stack[len(stack)-1]
+			pending := false
+			for _, predecessor := range predecessors[top] {
+				if !finished[predecessor] {
+					stack = append(stack, predecessor)
+					pending = true
+				}
+			}
+			if pending {
+				continue
+			}
+
+			stack = stack[:len(stack)-1]
+			if finished[top] {
+				continue
+			}
+			set := make([]uint64, words)
+			for _, predecessor := range predecessors[top] {
+				set[predecessor/64] |= 1 << (predecessor % 64)
+				for word, value := range dependents[predecessor] {
+					set[word] |= value
+				}
+			}
+			dependents[top] = set
+			finished[top] = true
+		}
+	}
+
+	componentLines := make([]int, count)
+	for i, component := range condensed.components {
+		for _, id := range component {
+			componentLines[i] += linesOfCode[id]
+		}
+	}
+
+	impacts := make(map[string]RebuildImpact, len(g.Nodes()))
+	for i, component := range condensed.components {
+		var closure RebuildImpact
+		for word, value := range dependents[i] {
+			for value != 0 {
+				dependent := word*64 + bits.TrailingZeros64(value)
+				closure.Packages += len(condensed.components[dependent])
+				closure.LinesOfCode += componentLines[dependent]
+				value &= value - 1
+			}
+		}
+		for _, id := range component {
+			impacts[id] = RebuildImpact{
+				Packages:    closure.Packages + len(component) - 1,
+				LinesOfCode: closure.LinesOfCode + componentLines[i] - linesOfCode[id],
+			}
+		}
+	}
+	return impacts
+}
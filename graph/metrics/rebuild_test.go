@@ -0,0 +1,25 @@
+package metrics
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRebuildImpacts(t *testing.T) {
+	linesOfCode := map[string]int{"alone": 10, "bottom": 10, "left": 10, "right": 10, "top": 100, "x": 10, "y": 10}
+
+	want := map[string]RebuildImpact{
+		"alone":  {},
+		"top":    {},
+		"left":   {Packages: 1, LinesOfCode: 100},
+		"right":  {Packages: 1, LinesOfCode: 100},
+		"bottom": {Packages: 3, LinesOfCode: 120},
+		// Cycle members rebuild each other, and top is counted once despite
+		// reaching x through three paths.
+		"x": {Packages: 5, LinesOfCode: 140},
+		"y": {Packages: 5, LinesOfCode: 140},
+	}
+	if got := RebuildImpacts(newDiamondGraph(t), linesOfCode); !reflect.DeepEqual(got, want) {
+		t.Errorf("RebuildImpacts() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,378 @@
+package graph
+
+import (
+	"slices"
+	"sort"
+)
+
+// Direction selects which edges a traversal follows.
+type Direction int
+
+const (
+	// Outgoing follows edges from source to target (what a node depends on).
+	Outgoing Direction = iota
+	// Incoming follows edges from target to source (what depends on a node).
+	Incoming
+	// Both ignores edge direction.
+	Both
+)
+
+// Traversals in this file are iterative so deep graphs cannot overflow the
+// stack, and deterministic: neighbors are always visited in sorted ID order.
+// A nil or empty edgeKinds slice matches every edge kind.
+
+// Degree returns the number of edges entering and leaving the node with the
+// given ID, counting parallel edges individually. It does not scan edges.
+func (g *Graph) Degree(id string) (in, out int) {
+	return len(g.incoming[id]), len(g.outgoing[id])
+}
+
+// SourceNodes returns the sorted IDs of nodes with no incoming edges: entry
+// points such as main and test packages, or dead code.
+func SourceNodes(g *Graph) []string {
+	return g.nodesWhere(func(id string) bool {
+		in, _ := g.Degree(id)
+		return in == 0
+	})
+}
+
+// SinkNodes returns the sorted IDs of nodes with no outgoing edges: leaf
+// packages, typically utilities, that import nothing else in the graph.
+func SinkNodes(g *Graph) []string {
+	return g.nodesWhere(func(id string) bool {
+		_, out := g.Degree(id)
+		return out == 0
+	})
+}
+
+func (g *Graph) nodesWhere(matches func(id string) bool) []string {
+	var ids []string
+	for _, id := range g.sortedNodeIDs() {
+		if matches(id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// Neighbors returns the sorted, de-duplicated IDs adjacent to id in the given direction.
+func (g *Graph) Neighbors(id string, direction Direction, edgeKinds []EdgeKind) []string {
+	seen := make(map[string]bool)
+	var neighbors []string
+
+	collect := func(edges []*Edge, neighborOf func(edge *Edge) string) {
+		for _, edge := range edges {
+			if !matchesEdgeKind(edge, edgeKinds) {
+				continue
+			}
+			neighbor := neighborOf(edge)
+			if !seen[neighbor] {
+				seen[neighbor] = true
+				neighbors = append(neighbors, neighbor)
+			}
+		}
+	}
+
+	if direction == Outgoing || direction == Both {
+		collect(g.outgoing[id], func(edge *Edge) string { return edge.To })
+	}
+	if direction == Incoming || direction == Both {
+		collect(g.incoming[id], func(edge *Edge) string { return edge.From })
+	}
+
+	sort.Strings(neighbors)
+	return neighbors
+}
+
+// Reachable returns the sorted IDs reachable from roots within maxDepth hops,
+// including the roots themselves. A negative maxDepth means unlimited depth.
+// Roots that are not nodes in the graph are ignored.
+func (g *Graph) Reachable(roots []string, direction Direction, maxDepth int) []string {
+	depths := make(map[string]int)
+	var queue []string
+
+	for _, root := range roots {
+		if _, exists := g.nodeIndex[root]; !exists {
+			continue
+		}
+		if _, seen := depths[root]; !seen {
+			depths[root] = 0
+			queue = append(queue, root)
+		}
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if maxDepth >= 0 && depths[current] >= maxDepth {
+			continue
+		}
+		for _, neighbor := range g.Neighbors(current, direction, nil) {
+			if _, seen := depths[neighbor]; !seen {
+				depths[neighbor] = depths[current] + 1
+				queue = append(queue, neighbor)
+			}
+		}
+	}
+
+	reachable := make([]string, 0, len(depths))
+	for id := range depths {
+		reachable = append(reachable, id)
+	}
+	sort.Strings(reachable)
+	return reachable
+}
+
+// ShortestPath returns the node IDs on a shortest path from one node to another,
+// following outgoing edges of the given kinds. Ties between equally short paths
+// are broken by visiting neighbors in sorted order. Returns false when no path exists.
+func (g *Graph) ShortestPath(from, to string, edgeKinds []EdgeKind) ([]string, bool) {
+	if _, exists := g.nodeIndex[from]; !exists {
+		return nil, false
+	}
+	if _, exists := g.nodeIndex[to]; !exists {
+		return nil, false
+	}
+
+	previous := map[string]string{from: ""}
+	queue := []string{from}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == to {
+			return buildPath(previous, from, to), true
+		}
+		for _, neighbor := range g.Neighbors(current, Outgoing, edgeKinds) {
+			if _, seen := previous[neighbor]; !seen {
+				previous[neighbor] = current
+				queue = append(queue, neighbor)
+			}
+		}
+	}
+
+	return nil, false
+}
+
+func buildPath(previous map[string]string, from, to string) []string {
+	path := []string{to}
+	for current := to; current != from; {
+		current = previous[current]
+		path = append(path, current)
+	}
+	slices.Reverse(path)
+	return path
+}
+
+// tarjanFrame is one level of the explicit DFS stack used by StronglyConnectedComponents.
+type tarjanFrame struct {
+	id         string
+	successors []string
+	next       int
+}
+
+// StronglyConnectedComponents returns every strongly connected component over
+// edges of the given kinds, including single-node components. IDs within a
+// component are sorted and components are ordered by their first ID.
+func (g *Graph) StronglyConnectedComponents(edgeKinds []EdgeKind) [][]string {
+	search := &tarjanSearch{
+		graph:     g,
+		edgeKinds: edgeKinds,
+		index:     make(map[string]int),
+		lowLink:   make(map[string]int),
+		onStack:   make(map[string]bool),
+	}
+
+	for _, id := range g.sortedNodeIDs() {
+		if _, visited := search.index[id]; !visited {
+			search.run(id)
+		}
+	}
+
+	sort.Slice(search.components, func(i, j int) bool {
+		return search.components[i][0] < search.components[j][0]
+	})
+	return search.components
+}
+
+// tarjanSearch holds the state of Tarjan's algorithm, run with an explicit stack.
+type tarjanSearch struct {
+	graph      *Graph
+	edgeKinds  []EdgeKind
+	counter    int
+	index      map[string]int
+	lowLink    map[string]int
+	onStack    map[string]bool
+	stack      []string
+	components [][]string
+}
+
+func (s *tarjanSearch) run(root string) {
+	callStack := []tarjanFrame{s.visit(root)}
+
+	for len(callStack) > 0 {
+		top := &callStack[len(callStack)-1]
+
+		if top.next < len(top.successors) {
+			successor := top.successors[top.next]
+			top.next++
+			if _, visited := s.index[successor]; !visited {
+				callStack = append(callStack, s.visit(successor))
+			} else if s.onStack[successor] {
+				s.lowLink[top.id] = min(s.lowLink[top.id], s.index[successor])
+			}
+			continue
+		}
+
+		finished := top.id
+		if s.lowLink[finished] == s.index[finished] {
+			s.popComponent(finished)
+		}
+		callStack = callStack[:len(callStack)-1]
+		if len(callStack) > 0 {
+			parent := callStack[len(callStack)-1].id
+			s.lowLink[parent] = min(s.lowLink[parent], s.lowLink[finished])
+		}
+	}
+}
+
+func (s *tarjanSearch) visit(id string) tarjanFrame {
+	s.index[id] = s.counter
+	s.lowLink[id] = s.counter
+	s.counter++
+	s.stack = append(s.stack, id)
+	s.onStack[id] = true
+	return tarjanFrame{id: id, successors: s.graph.Neighbors(id, Outgoing, s.edgeKinds)}
+}
+
+func (s *tarjanSearch) popComponent(root string) {
+	var component []string
+	for {
+		member := s.stack[len(s.stack)-1]
+		s.stack = s.stack[:len(s.stack)-1]
+		s.onStack[member] = false
+		component = append(component, member)
+		if member == root {
+			break
+		}
+	}
+	sort.Strings(component)
+	s.components = append(s.components, component)
+}
+
+func (g *Graph) sortedNodeIDs() []string {
+	ids := make([]string, len(g.nodes))
+	for i, node := range g.nodes {
+		ids[i] = node.ID
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func matchesEdgeKind(edge *Edge, edgeKinds []EdgeKind) bool {
+	return len(edgeKinds) == 0 || slices.Contains(edgeKinds, edge.Kind)
+}
+
+// FindCycles returns the strongly connected components over edges of the given
+// kinds that contain a cycle: components with more than one node, and single
+// nodes with an edge to themselves. Ordering follows StronglyConnectedComponents.
+func (g *Graph) FindCycles(edgeKinds []EdgeKind) [][]string {
+	var cycles [][]string
+	for _, component := range g.StronglyConnectedComponents(edgeKinds) {
+		if len(component) > 1 || g.hasSelfLoop(component[0], edgeKinds) {
+			cycles = append(cycles, component)
+		}
+	}
+	return cycles
+}
+
+func (g *Graph) hasSelfLoop(id string, edgeKinds []EdgeKind) bool {
+	for _, edge := range g.outgoing[id] {
+		if edge.To == id && matchesEdgeKind(edge, edgeKinds) {
+			return true
+		}
+	}
+	return false
+}
+
+// NearCycles returns the pairs [a, b] where a imports b and b's test files
+// import a: not a build error, but a sign the two packages are entangled.
+// Pairs are sorted by a, then b.
+func (g *Graph) NearCycles() [][2]string {
+	var pairs [][2]string
+	for _, id := range g.sortedNodeIDs() {
+		for _, imported := range g.Neighbors(id, Outgoing, []EdgeKind{EdgeImport}) {
+			if slices.Contains(g.Neighbors(imported, Outgoing, []EdgeKind{EdgeTestImport}), id) {
+				pairs = append(pairs, [2]string{id, imported})
+			}
+		}
+	}
+	return pairs
+}
+
+// WeakComponents returns the weakly connected components of g: the groups of
+// nodes connected by edges of any kind when direction is ignored. Unlike
+// strongly connected components, a package that only imports, or is only
+// imported, shares a component with its neighbors, so single-node components
+// are exactly the nodes with no edges to other nodes. IDs within a component
+// are sorted and components are ordered by their first ID.
+func WeakComponents(g *Graph) [][]string {
+	components := newUnionFind(g.sortedNodeIDs())
+	for _, edge := range g.edges {
+		components.union(edge.From, edge.To)
+	}
+
+	members := make(map[string][]string)
+	var roots []string
+	for _, id := range g.sortedNodeIDs() {
+		root := components.find(id)
+		if _, found := members[root]; !found {
+			roots = append(roots, root)
+		}
+		members[root] = append(members[root], id)
+	}
+
+	result := make([][]string, len(roots))
+	for i, root := range roots {
+		result[i] = members[root]
+	}
+	return result
+}
+
+// unionFind is a disjoint-set forest with path compression and union by size.
+type unionFind struct {
+	parent map[string]string
+	size   map[string]int
+}
+
+func newUnionFind(ids []string) *unionFind {
+	sets := &unionFind{parent: make(map[string]string, len(ids)), size: make(map[string]int, len(ids))}
+	for _, id := range ids {
+		sets.parent[id] = id
+		sets.size[id] = 1
+	}
+	return sets
+}
+
+func (u *unionFind) find(id string) string {
+	root := id
+	for u.parent[root] != root {
+		root = u.parent[root]
+	}
+	for u.parent[id] != root {
+		id, u.parent[id] = u.parent[id], root
+	}
+	return root
+}
+
+func (u *unionFind) union(a, b string) {
+	rootA, rootB := u.find(a), u.find(b)
+	if rootA == rootB {
+		return
+	}
+	if u.size[rootA] < u.size[rootB] {
+		rootA, rootB = rootB, rootA
+	}
+	u.parent[rootB] = rootA
+	u.size[rootA] += u.size[rootB]
+}
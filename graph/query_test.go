@@ -0,0 +1,305 @@
+package graph
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// newQueryTestGraph builds:
+//
+//	a -> b -> c -> a   (cycle)
+//	c -> d
+//	d -> e (call edge)
+//	f                  (isolated)
+func newQueryTestGraph(t *testing.T) *Graph {
+	t.Helper()
+
+	g := New()
+	for _, id := range []string{"a", "b", "c", "d", "e", "f"} {
+		if err := g.AddNode(&Node{ID: id, Kind: KindPackage}); err != nil {
+			t.Fatalf("AddNode() error = %v", err)
+		}
+	}
+	edges := []Edge{
+		{From: "a", To: "b", Kind: EdgeImport},
+		{From: "b", To: "c", Kind: EdgeImport},
+		{From: "c", To: "a", Kind: EdgeImport},
+		{From: "c", To: "d", Kind: EdgeImport},
+		{From: "d", To: "e", Kind: "call"},
+	}
+	for i := range edges {
+		if err := g.AddEdge(&edges[i]); err != nil {
+			t.Fatalf("AddEdge() error = %v", err)
+		}
+	}
+	return g
+}
+
+func TestGraph_Neighbors(t *testing.T) {
+	g := newQueryTestGraph(t)
+
+	tests := []struct {
+		name      string
+		id        string
+		direction Direction
+		edgeKinds []EdgeKind
+		want      []string
+	}{
+		{name: "outgoing", id: "c", direction: Outgoing, want: []string{"a", "d"}},
+		{name: "incoming", id: "a", direction: Incoming, want: []string{"c"}},
+		{name: "both directions", id: "c", direction: Both, want: []string{"a", "b", "d"}},
+		{name: "filtered by kind", id: "d", direction: Outgoing, edgeKinds: []EdgeKind{EdgeImport}, want: nil},
+		{name: "matching kind", id: "d", direction: Outgoing, edgeKinds: []EdgeKind{"call"}, want: []string{"e"}},
+		{name: "isolated node", id: "f", direction: Both, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := g.Neighbors(tt.id, tt.direction, tt.edgeKinds)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Neighbors() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGraph_Degree(t *testing.T) {
+	g := newQueryTestGraph(t)
+
+	tests := []struct {
+		id      string
+		wantIn  int
+		wantOut int
+	}{
+		{id: "a", wantIn: 1, wantOut: 1},
+		{id: "c", wantIn: 1, wantOut: 2},
+		{id: "e", wantIn: 1, wantOut: 0},
+		{id: "f", wantIn: 0, wantOut: 0},
+		{id: "missing", wantIn: 0, wantOut: 0},
+	}
+	for _, tt := range tests {
+		if in, out := g.Degree(tt.id); in != tt.wantIn || out != tt.wantOut {
+			t.Errorf("Degree(%q) = (%d, %d), want (%d, %d)", tt.id, in, out, tt.wantIn, tt.wantOut)
+		}
+	}
+}
+
+func TestSourceAndSinkNodes(t *testing.T) {
+	g := newQueryTestGraph(t)
+	if err := g.AddNode(&Node{ID: "main", Kind: KindPackage}); err != nil {
+		t.Fatalf("AddNode() error = %v", err)
+	}
+	if err := g.AddEdge(&Edge{From: "main", To: "a", Kind: EdgeImport}); err != nil {
+		t.Fatalf("AddEdge() error = %v", err)
+	}
+
+	if got, want := SourceNodes(g), []string{"f", "main"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SourceNodes() = %v, want %v", got, want)
+	}
+	if got, want := SinkNodes(g), []string{"e", "f"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SinkNodes() = %v, want %v", got, want)
+	}
+}
+
+func TestGraph_Reachable(t *testing.T) {
+	g := newQueryTestGraph(t)
+
+	tests := []struct {
+		name      string
+		roots     []string
+		direction Direction
+		maxDepth  int
+		want      []string
+	}{
+		{name: "unlimited outgoing", roots: []string{"a"}, direction: Outgoing, maxDepth: -1,
+			want: []string{"a", "b", "c", "d", "e"}},
+		{name: "depth limited", roots: []string{"a"}, direction: Outgoing, maxDepth: 2, want: []string{"a", "b", "c"}},
+		{name: "depth zero returns roots", roots: []string{"d"}, direction: Outgoing, maxDepth: 0, want: []string{"d"}},
+		{name: "incoming", roots: []string{"d"}, direction: Incoming, maxDepth: -1, want: []string{"a", "b", "c", "d"}},
+		{name: "multiple roots with unknown root", roots: []string{"e", "f", "missing"}, direction: Outgoing,
+			maxDepth: -1, want: []string{"e", "f"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := g.Reachable(tt.roots, tt.direction, tt.maxDepth)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Reachable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGraph_ShortestPath(t *testing.T) {
+	g := newQueryTestGraph(t)
+
+	path, found := g.ShortestPath("b", "e", nil)
+	if !found || !reflect.DeepEqual(path, []string{"b", "c", "d", "e"}) {
+		t.Errorf("ShortestPath(b, e) = %v, %v", path, found)
+	}
+
+	if _, found := g.ShortestPath("b", "e", []EdgeKind{EdgeImport}); found {
+		t.Error("expected no path when the call edge is filtered out")
+	}
+	if path, found := g.ShortestPath("a", "a", nil); !found || !reflect.DeepEqual(path, []string{"a"}) {
+		t.Errorf("ShortestPath(a, a) = %v, %v", path, found)
+	}
+	if _, found := g.ShortestPath("e", "a", nil); found {
+		t.Error("expected no path against edge direction")
+	}
+	if _, found := g.ShortestPath("a", "missing", nil); found {
+		t.Error("expected no path to unknown node")
+	}
+}
+
+func TestGraph_ShortestPath_DeterministicTieBreak(t *testing.T) {
+	g := New()
+	for _, id := range []string{"start", "x", "y", "end"} {
+		g.AddNode(&Node{ID: id})
+	}
+	// Insert the lexicographically larger branch first.
+	for _, edge := range [][2]string{{"start", "y"}, {"y", "end"}, {"start", "x"}, {"x", "end"}} {
+		g.AddEdge(&Edge{From: edge[0], To: edge[1], Kind: EdgeImport})
+	}
+
+	for range 5 {
+		path, _ := g.ShortestPath("start", "end", nil)
+		if !reflect.DeepEqual(path, []string{"start", "x", "end"}) {
+			t.Fatalf("ShortestPath() = %v, want [start x end]", path)
+		}
+	}
+}
+
+func TestGraph_StronglyConnectedComponents(t *testing.T) {
+	g := newQueryTestGraph(t)
+
+	got := g.StronglyConnectedComponents(nil)
+	want := [][]string{{"a", "b", "c"}, {"d"}, {"e"}, {"f"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("StronglyConnectedComponents() = %v, want %v", got, want)
+	}
+}
+
+func TestWeakComponents(t *testing.T) {
+	g := newQueryTestGraph(t)
+	// x only imports e, which imports nothing: not strongly connected to
+	// anything, but weakly connected to the rest. f's self-loop keeps it alone.
+	if err := g.AddNode(&Node{ID: "x", Kind: KindPackage}); err != nil {
+		t.Fatalf("AddNode() error = %v", err)
+	}
+	for _, edge := range []*Edge{{From: "x", To: "e", Kind: EdgeImport}, {From: "f", To: "f", Kind: EdgeImport}} {
+		if err := g.AddEdge(edge); err != nil {
+			t.Fatalf("AddEdge() error = %v", err)
+		}
+	}
+
+	want := [][]string{{"a", "b", "c", "d", "e", "x"}, {"f"}}
+	if got := WeakComponents(g); !reflect.DeepEqual(got, want) {
+		t.Errorf("WeakComponents() = %v, want %v", got, want)
+	}
+	if got := WeakComponents(New()); len(got) != 0 {
+		t.Errorf("WeakComponents(empty) = %v, want none", got)
+	}
+}
+
+func TestGraph_FindCycles(t *testing.T) {
+	g := newQueryTestGraph(t)
+	if err := g.AddEdge(&Edge{From: "f", To: "f", Kind: EdgeImport}); err != nil {
+		t.Fatalf("AddEdge() error = %v", err)
+	}
+
+	got := g.FindCycles([]EdgeKind{EdgeImport})
+	want := [][]string{{"a", "b", "c"}, {"f"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindCycles() = %v, want %v", got, want)
+	}
+
+	if cycles := g.FindCycles([]EdgeKind{"call"}); len(cycles) != 0 {
+		t.Errorf("expected no call cycles, got %v", cycles)
+	}
+}
+
+func TestGraph_NearCycles(t *testing.T) {
+	g := newQueryTestGraph(t)
+	for _, edge := range []*Edge{
+		{From: "e", To: "d", Kind: EdgeTestImport},
+		{From: "d", To: "c", Kind: EdgeTestImport},
+		{From: "f", To: "a", Kind: EdgeTestImport},
+	} {
+		if err := g.AddEdge(edge); err != nil {
+			t.Fatalf("AddEdge() error = %v", err)
+		}
+	}
+
+	// Only c -> d is an import answered by a test import; d -> e is a call edge
+	// and nothing imports f.
+	want := [][2]string{{"c", "d"}}
+	if got := g.NearCycles(); !reflect.DeepEqual(got, want) {
+		t.Errorf("NearCycles() = %v, want %v", got, want)
+	}
+}
+
+func TestGraph_StronglyConnectedComponents_DeepChainIsIterative(t *testing.T) {
+	const depth = 200_000
+	g := New()
+	for i := range depth {
+		g.AddNode(&Node{ID: fmt.Sprintf("n%06d", i)})
+	}
+	for i := range depth - 1 {
+		g.AddEdge(&Edge{From: fmt.Sprintf("n%06d", i), To: fmt.Sprintf("n%06d", i+1), Kind: EdgeImport})
+	}
+	// Close the chain into one giant cycle.
+	g.AddEdge(&Edge{From: fmt.Sprintf("n%06d", depth-1), To: "n000000", Kind: EdgeImport})
+
+	components := g.StronglyConnectedComponents(nil)
+	if len(components) != 1 || len(components[0]) != depth {
+		t.Fatalf("expected a single component of %d nodes, got %d components", depth, len(components))
+	}
+}
+
+// generateBenchmarkGraph builds a deterministic random DAG where every node
+// imports up to fanOut nodes with a higher index.
+func generateBenchmarkGraph(nodeCount, fanOut int) *Graph {
+	random := rand.New(rand.NewSource(42))
+	g := New()
+	for i := range nodeCount {
+		g.AddNode(&Node{ID: fmt.Sprintf("example.com/pkg%05d", i), Kind: KindPackage})
+	}
+	for i := range nodeCount - 1 {
+		for range fanOut {
+			target := i + 1 + random.Intn(nodeCount-i-1)
+			g.AddEdge(&Edge{
+				From: fmt.Sprintf("example.com/pkg%05d", i),
+				To:   fmt.Sprintf("example.com/pkg%05d", target),
+				Kind: EdgeImport,
+			})
+		}
+	}
+	return g
+}
+
+func BenchmarkReachable50k(b *testing.B) {
+	g := generateBenchmarkGraph(50_000, 3)
+	b.ResetTimer()
+	for range b.N {
+		g.Reachable([]string{"example.com/pkg00000"}, Outgoing, -1)
+	}
+}
+
+func BenchmarkShortestPath50k(b *testing.B) {
+	g := generateBenchmarkGraph(50_000, 3)
+	b.ResetTimer()
+	for range b.N {
+		g.ShortestPath("example.com/pkg00000", "example.com/pkg49999", nil)
+	}
+}
+
+func BenchmarkStronglyConnectedComponents50k(b *testing.B) {
+	g := generateBenchmarkGraph(50_000, 3)
+	b.ResetTimer()
+	for range b.N {
+		g.StronglyConnectedComponents(nil)
+	}
+}
@@ -0,0 +1,106 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SchemaVersion is the graph schema version written into every export, as
+// "major.minor". Bump the minor version for changes older binaries can ignore,
+// registering a migration when old files need rewriting, and the major version
+// for incompatible changes.
+const SchemaVersion = "1.0"
+
+// legacySchemaVersion is assumed for files written before versioning.
+const legacySchemaVersion = "1.0"
+
+// ErrUnsupportedSchema is returned when a decoded graph uses a schema version
+// this build cannot read.
+var ErrUnsupportedSchema = errors.New("unsupported graph schema version")
+
+// Version is a parsed major.minor schema version.
+type Version struct {
+	Major int
+	Minor int
+}
+
+// ParseVersion parses a "major.minor" schema version.
+func ParseVersion(version string) (Version, error) {
+	majorText, minorText, found := strings.Cut(version, ".")
+	major, majorErr := strconv.Atoi(majorText)
+	minor, minorErr := strconv.Atoi(minorText)
+	if !found || majorErr != nil || minorErr != nil || major < 0 || minor < 0 {
+		return Version{}, fmt.Errorf("%w: invalid version %q, expected major.minor", ErrUnsupportedSchema, version)
+	}
+	return Version{Major: major, Minor: minor}, nil
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// Migration rewrites a graph decoded at one minor version into the next minor version.
+type Migration func(g *Graph) error
+
+// Schema is a schema version together with the migrations that upgrade older
+// minor versions of the same major version to it.
+type Schema struct {
+	Version Version
+	// Migrations maps a version to the migration upgrading it to the next
+	// minor version. Versions without a migration need no rewriting.
+	Migrations map[Version]Migration
+}
+
+// schemaMigrations holds the migrations of the current schema, keyed by the
+// version they upgrade from.
+var schemaMigrations = map[Version]Migration{}
+
+// CurrentSchema returns the schema written and read by this build.
+func CurrentSchema() Schema {
+	version, err := ParseVersion(SchemaVersion)
+	if err != nil {
+		panic(err)
+	}
+	return Schema{Version: version, Migrations: schemaMigrations}
+}
+
+// Upgrade checks that g, as decoded, can be read under s and migrates it to
+// s.Version. Graphs without a version are treated as 1.0. A newer minor
+// version of the same major is accepted as is, since it only adds data this
+// build ignores; any other major version is rejected with ErrUnsupportedSchema.
+func (s Schema) Upgrade(g *Graph) error {
+	declared := g.SchemaVersion
+	if declared == "" {
+		declared = legacySchemaVersion
+	}
+	version, err := ParseVersion(declared)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case version.Major > s.Version.Major:
+		return fmt.Errorf("%w: graph uses schema %s but this codegraph reads up to %d.x; upgrade codegraph to read it",
+			ErrUnsupportedSchema, version, s.Version.Major)
+	case version.Major < s.Version.Major:
+		return fmt.Errorf("%w: graph uses schema %s but this codegraph reads %d.x; re-export it with a codegraph release supporting schema %s",
+			ErrUnsupportedSchema, version, s.Version.Major, version)
+	case version.Minor >= s.Version.Minor:
+		g.SchemaVersion = version.String()
+		return nil
+	}
+
+	for ; version.Minor < s.Version.Minor; version.Minor++ {
+		migrate, found := s.Migrations[version]
+		if !found {
+			continue
+		}
+		if err := migrate(g); err != nil {
+			return fmt.Errorf("failed to migrate graph from schema %s: %w", version, err)
+		}
+	}
+	g.SchemaVersion = s.Version.String()
+	return nil
+}
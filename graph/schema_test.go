@@ -0,0 +1,99 @@
+package graph
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Version
+		wantErr bool
+	}{
+		{input: "1.0", want: Version{Major: 1, Minor: 0}},
+		{input: "2.13", want: Version{Major: 2, Minor: 13}},
+		{input: "1", wantErr: true},
+		{input: "1.x", wantErr: true},
+		{input: "-1.0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseVersion(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCurrentSchema_MatchesSchemaVersion(t *testing.T) {
+	if got := CurrentSchema().Version.String(); got != SchemaVersion {
+		t.Errorf("CurrentSchema().Version = %s, want %s", got, SchemaVersion)
+	}
+}
+
+func TestSchema_Upgrade(t *testing.T) {
+	var migrated []string
+	schema := Schema{
+		Version: Version{Major: 1, Minor: 3},
+		Migrations: map[Version]Migration{
+			{Major: 1, Minor: 0}: func(g *Graph) error { migrated = append(migrated, "1.0"); return nil },
+			{Major: 1, Minor: 2}: func(g *Graph) error { migrated = append(migrated, "1.2"); return nil },
+		},
+	}
+
+	tests := []struct {
+		declared     string
+		wantVersion  string
+		wantMigrated []string
+		wantErr      error
+	}{
+		{declared: "", wantVersion: "1.3", wantMigrated: []string{"1.0", "1.2"}},
+		{declared: "1.1", wantVersion: "1.3", wantMigrated: []string{"1.2"}},
+		{declared: "1.3", wantVersion: "1.3"},
+		{declared: "1.7", wantVersion: "1.7"},
+		{declared: "2.0", wantErr: ErrUnsupportedSchema},
+		{declared: "0.9", wantErr: ErrUnsupportedSchema},
+		{declared: "latest", wantErr: ErrUnsupportedSchema},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.declared, func(t *testing.T) {
+			migrated = nil
+			g := New()
+			g.SchemaVersion = tt.declared
+
+			err := schema.Upgrade(g)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Upgrade() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if g.SchemaVersion != tt.wantVersion {
+				t.Errorf("SchemaVersion = %q, want %q", g.SchemaVersion, tt.wantVersion)
+			}
+			if !slices.Equal(migrated, tt.wantMigrated) {
+				t.Errorf("migrations run = %v, want %v", migrated, tt.wantMigrated)
+			}
+		})
+	}
+}
+
+func TestSchema_UpgradeMigrationError(t *testing.T) {
+	schema := Schema{
+		Version: Version{Major: 1, Minor: 1},
+		Migrations: map[Version]Migration{
+			{Major: 1, Minor: 0}: func(g *Graph) error { return errors.New("boom") },
+		},
+	}
+	if err := schema.Upgrade(New()); err == nil {
+		t.Error("expected the migration error to be returned")
+	}
+}
@@ -0,0 +1,221 @@
+// Package repogen generates Go modules on disk for tests and benchmarks, so
+// fixtures can reach realistic sizes without being written out by hand.
+//
+// Package i of a generated module is named p0000, p0001, ... and only ever
+// imports packages with a smaller index, so every topology is acyclic. The
+// returned Repo describes the import graph extraction is expected to find.
+package repogen
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+// Topology selects which packages import which.
+type Topology int
+
+const (
+	// Chain makes each package import the one before it.
+	Chain Topology = iota
+	// Star makes every package import the first one.
+	Star
+	// RandomDAG makes each package import each earlier package with
+	// probability Config.Density.
+	RandomDAG
+)
+
+// DefaultModule is the module path used when Config.Module is empty.
+const DefaultModule = "genmod"
+
+// Config describes the module to generate.
+type Config struct {
+	// Module is the module path, DefaultModule when empty.
+	Module   string
+	Packages int
+	Topology Topology
+	// Density is the RandomDAG edge probability, from 0 to 1.
+	Density float64
+	// Seed makes RandomDAG reproducible; equal configs generate equal modules.
+	Seed uint64
+	// FunctionsPerPackage is the number of exported functions in each
+	// package, at least one.
+	FunctionsPerPackage int
+	// SyntaxErrors is the number of packages, the last ones, that get an
+	// extra file that does not parse. Their imports are unaffected.
+	SyntaxErrors int
+	// TestFiles adds an in-package _test.go file to every package.
+	TestFiles bool
+	// Generics adds a generic type and function to every package and
+	// instantiates the function.
+	Generics bool
+}
+
+// Repo is a generated module and the graph it is expected to produce.
+type Repo struct {
+	// Root is the module directory. It is empty for a Plan that was not
+	// written to disk.
+	Root   string
+	Module string
+	// Packages lists the import paths in generation order.
+	Packages []string
+	// Imports maps each import path to the sorted import paths it imports.
+	Imports map[string][]string
+	// Broken lists the import paths of packages with a syntax error.
+	Broken []string
+	// Files maps slash-separated paths relative to Root to their content.
+	Files map[string]string
+}
+
+// Plan computes the module cfg describes without writing it.
+func Plan(cfg Config) *Repo {
+	if cfg.Module == "" {
+		cfg.Module = DefaultModule
+	}
+	cfg.FunctionsPerPackage = max(cfg.FunctionsPerPackage, 1)
+
+	repo := &Repo{
+		Module:  cfg.Module,
+		Imports: make(map[string][]string, cfg.Packages),
+		Files:   map[string]string{"go.mod": "module " + cfg.Module + "\n\ngo 1.24\n"},
+	}
+	random := rand.New(rand.NewPCG(cfg.Seed, 0))
+	for i := range cfg.Packages {
+		var imported []int
+		switch {
+		case i == 0:
+		case cfg.Topology == Chain:
+			imported = []int{i - 1}
+		case cfg.Topology == Star:
+			imported = []int{0}
+		case cfg.Topology == RandomDAG:
+			for j := range i {
+				if random.Float64() < cfg.Density {
+					imported = append(imported, j)
+				}
+			}
+		}
+
+		path := cfg.Module + "/" + packageName(i)
+		repo.Packages = append(repo.Packages, path)
+		repo.Imports[path] = []string{}
+		for _, j := range imported {
+			repo.Imports[path] = append(repo.Imports[path], cfg.Module+"/"+packageName(j))
+		}
+		slices.Sort(repo.Imports[path])
+
+		dir := packageName(i) + "/"
+		repo.Files[dir+"p.go"] = packageSource(cfg, i, imported)
+		if cfg.Generics {
+			repo.Files[dir+"generic.go"] = genericSource(i)
+		}
+		if cfg.TestFiles {
+			repo.Files[dir+"p_test.go"] = testSource(i)
+		}
+		if i >= cfg.Packages-cfg.SyntaxErrors {
+			repo.Files[dir+"broken.go"] = fmt.Sprintf("package %s\n\nfunc broken( {\n", packageName(i))
+			repo.Broken = append(repo.Broken, path)
+		}
+	}
+	return repo
+}
+
+// Generate writes the module cfg describes into a temporary directory of tb
+// and returns it with Root set.
+func Generate(tb testing.TB, cfg Config) *Repo {
+	tb.Helper()
+
+	repo := Plan(cfg)
+	repo.Root = tb.TempDir()
+	for name, content := range repo.Files {
+		path := filepath.Join(repo.Root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			tb.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	return repo
+}
+
+// EdgeCount returns the number of import edges in the module.
+func (r *Repo) EdgeCount() int {
+	count := 0
+	for _, imports := range r.Imports {
+		count += len(imports)
+	}
+	return count
+}
+
+// Graph returns the expected import graph: a package node per package and an
+// EdgeImport edge per import, without any extracted attributes.
+func (r *Repo) Graph() *graph.Graph {
+	g := graph.New()
+	for _, path := range r.Packages {
+		name := path[strings.LastIndex(path, "/")+1:]
+		// Package paths are unique, so AddNode and AddEdge cannot fail.
+		_ = g.AddNode(&graph.Node{ID: graph.PackageID(path), Kind: graph.KindPackage, Name: name, ModulePath: r.Module})
+	}
+	for _, path := range r.Packages {
+		for _, imported := range r.Imports[path] {
+			_ = g.AddEdge(&graph.Edge{From: graph.PackageID(path), To: graph.PackageID(imported), Kind: graph.EdgeImport})
+		}
+	}
+	return g
+}
+
+func packageName(i int) string {
+	return fmt.Sprintf("p%04d", i)
+}
+
+// packageSource renders package i: F0 calls F0 of every imported package
+// and the package's other functions, each of which has one branch.
+func packageSource(cfg Config, i int, imported []int) string {
+	var source strings.Builder
+	fmt.Fprintf(&source, "package %s\n\n", packageName(i))
+	if len(imported) > 0 {
+		source.WriteString("import (\n")
+		for _, j := range imported {
+			fmt.Fprintf(&source, "\t%q\n", cfg.Module+"/"+packageName(j))
+		}
+		source.WriteString(")\n\n")
+	}
+
+	source.WriteString("// F0 combines the values of the imported packages.\nfunc F0(n int) int {\n\ttotal := n\n")
+	for _, j := range imported {
+		fmt.Fprintf(&source, "\ttotal += %s.F0(n)\n", packageName(j))
+	}
+	for k := 1; k < cfg.FunctionsPerPackage; k++ {
+		fmt.Fprintf(&source, "\ttotal += F%d(n)\n", k)
+	}
+	if cfg.Generics {
+		source.WriteString("\ttotal += len(Map([]int{n}, func(v int) int { return v }))\n")
+	}
+	source.WriteString("\treturn total\n}\n")
+
+	for k := 1; k < cfg.FunctionsPerPackage; k++ {
+		fmt.Fprintf(&source, "\n// F%d offsets even inputs.\nfunc F%d(n int) int {\n\tif n%%2 == 0 {\n\t\treturn n + %d\n\t}\n\treturn n\n}\n", k, k, k)
+	}
+	return source.String()
+}
+
+func genericSource(i int) string {
+	return fmt.Sprintf("package %s\n\n"+
+		"// Box holds a value of any type.\ntype Box[T any] struct{ Value T }\n\n"+
+		"// Map applies f to each element of values.\nfunc Map[T, U any](values []T, f func(T) U) []U {\n"+
+		"\tresult := make([]U, 0, len(values))\n\tfor _, value := range values {\n\t\tresult = append(result, f(value))\n\t}\n\treturn result\n}\n",
+		packageName(i))
+}
+
+func testSource(i int) string {
+	return fmt.Sprintf("package %s\n\nimport \"testing\"\n\n"+
+		"func TestF0(t *testing.T) {\n\tif F0(1) < 1 {\n\t\tt.Fatal(\"F0(1) < 1\")\n\t}\n}\n",
+		packageName(i))
+}
@@ -0,0 +1,99 @@
+package repogen
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPlan_Topologies(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    Config
+		want   map[string][]string
+		broken []string
+	}{
+		{
+			name: "chain",
+			cfg:  Config{Packages: 3, Topology: Chain},
+			want: map[string][]string{
+				"genmod/p0000": {}, "genmod/p0001": {"genmod/p0000"}, "genmod/p0002": {"genmod/p0001"},
+			},
+		},
+		{
+			name: "star",
+			cfg:  Config{Module: "example.com/star", Packages: 3, Topology: Star, SyntaxErrors: 1},
+			want: map[string][]string{
+				"example.com/star/p0000": {}, "example.com/star/p0001": {"example.com/star/p0000"},
+				"example.com/star/p0002": {"example.com/star/p0000"},
+			},
+			broken: []string{"example.com/star/p0002"},
+		},
+		{
+			name: "complete DAG",
+			cfg:  Config{Packages: 3, Topology: RandomDAG, Density: 1},
+			want: map[string][]string{
+				"genmod/p0000": {}, "genmod/p0001": {"genmod/p0000"}, "genmod/p0002": {"genmod/p0000", "genmod/p0001"},
+			},
+		},
+		{
+			name: "empty DAG",
+			cfg:  Config{Packages: 2, Topology: RandomDAG},
+			want: map[string][]string{"genmod/p0000": {}, "genmod/p0001": {}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := Plan(tt.cfg)
+			if !reflect.DeepEqual(repo.Imports, tt.want) {
+				t.Errorf("Imports = %v, want %v", repo.Imports, tt.want)
+			}
+			if !reflect.DeepEqual(repo.Broken, tt.broken) {
+				t.Errorf("Broken = %v, want %v", repo.Broken, tt.broken)
+			}
+			if got := len(repo.Graph().Edges()); got != repo.EdgeCount() {
+				t.Errorf("Graph() has %d edges, want %d", got, repo.EdgeCount())
+			}
+		})
+	}
+}
+
+func TestPlan_RandomDAGIsReproducible(t *testing.T) {
+	cfg := Config{Packages: 40, Topology: RandomDAG, Density: 0.2, Seed: 7}
+	first, second := Plan(cfg), Plan(cfg)
+	if !reflect.DeepEqual(first.Files, second.Files) {
+		t.Error("equal configs generated different modules")
+	}
+	if count := first.EdgeCount(); count == 0 || count == 40*39/2 {
+		t.Errorf("EdgeCount() = %d, want a partial DAG", count)
+	}
+}
+
+func TestGenerate_WritesParsableFiles(t *testing.T) {
+	repo := Generate(t, Config{Packages: 3, Topology: Chain, FunctionsPerPackage: 3, SyntaxErrors: 1, TestFiles: true, Generics: true})
+
+	fileSet := token.NewFileSet()
+	for name := range repo.Files {
+		content, err := os.ReadFile(filepath.Join(repo.Root, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatalf("reading %s: %v", name, err)
+		}
+		if !strings.HasSuffix(name, ".go") {
+			continue
+		}
+		_, err = parser.ParseFile(fileSet, name, content, 0)
+		if broken := strings.HasSuffix(name, "broken.go"); broken != (err != nil) {
+			t.Errorf("ParseFile(%s) error = %v, want an error only for broken.go", name, err)
+		}
+	}
+	if _, found := repo.Files["p0001/generic.go"]; !found {
+		t.Error("expected a generic.go file per package")
+	}
+	if _, found := repo.Files["p0001/p_test.go"]; !found {
+		t.Error("expected a p_test.go file per package")
+	}
+}
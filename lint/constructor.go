@@ -0,0 +1,36 @@
+package lint
+
+import (
+	"fmt"
+
+	"github.com/Desgue/codegraph/analyzer"
+	"github.com/Desgue/codegraph/graph"
+)
+
+// ConstructorRuleName identifies violations of ConstructorRule.
+const ConstructorRuleName = "missing-constructor"
+
+// ConstructorRule warns about exported struct types with unexported fields
+// but no New or Must constructor. Types come from the source, not the graph,
+// so the rule reports a precomputed analyzer.FindMissingConstructors result.
+type ConstructorRule struct {
+	Missing []analyzer.MissingConstructor
+}
+
+func (r *ConstructorRule) Name() string {
+	return ConstructorRuleName
+}
+
+func (r *ConstructorRule) Check(g *graph.Graph) []Violation {
+	var violations []Violation
+	for _, finding := range r.Missing {
+		violations = append(violations, Violation{
+			Rule:     r.Name(),
+			Severity: SeverityWarning,
+			Message: fmt.Sprintf("%s:%d: %s.%s has unexported fields but no New or Must constructor",
+				finding.File, finding.Line, finding.Package, finding.Type),
+			Nodes: []string{finding.Package},
+		})
+	}
+	return violations
+}
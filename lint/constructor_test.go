@@ -0,0 +1,22 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/Desgue/codegraph/analyzer"
+)
+
+func TestConstructorRule(t *testing.T) {
+	rule := &ConstructorRule{Missing: []analyzer.MissingConstructor{
+		{Package: "mod/store", Type: "Store", File: "store/store.go", Line: 3},
+	}}
+
+	violations := rule.Check(newLintTestGraph(t, nil, nil))
+	if len(violations) != 1 {
+		t.Fatalf("expected 1 violation, got %+v", violations)
+	}
+	want := "store/store.go:3: mod/store.Store has unexported fields but no New or Must constructor"
+	if violations[0].Message != want || violations[0].Rule != ConstructorRuleName || violations[0].Severity != SeverityWarning {
+		t.Errorf("unexpected violation: %+v", violations[0])
+	}
+}
@@ -0,0 +1,34 @@
+package lint
+
+import (
+	"fmt"
+
+	"github.com/Desgue/codegraph/analyzer"
+	"github.com/Desgue/codegraph/graph"
+)
+
+// DependencyInversionRuleName identifies violations of DependencyInversionRule.
+const DependencyInversionRuleName = "dependency-inversion"
+
+// DependencyInversionRule reports imports that break the Dependency Inversion
+// Principle between Layers; see analyzer.CheckDependencyInversion.
+type DependencyInversionRule struct {
+	Layers []analyzer.LayerDef
+}
+
+func (r *DependencyInversionRule) Name() string {
+	return DependencyInversionRuleName
+}
+
+func (r *DependencyInversionRule) Check(g *graph.Graph) []Violation {
+	var violations []Violation
+	for _, violation := range analyzer.CheckDependencyInversion(g, r.Layers) {
+		violations = append(violations, Violation{
+			Rule:     r.Name(),
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("%s -> %s: %s", violation.From, violation.To, violation.Rule),
+			Nodes:    []string{violation.From, violation.To},
+		})
+	}
+	return violations
+}
@@ -0,0 +1,24 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/Desgue/codegraph/analyzer"
+)
+
+func TestDependencyInversionRule(t *testing.T) {
+	g := newLintTestGraph(t, []string{"app", "store"}, [][2]string{{"app", "store"}})
+	rule := &DependencyInversionRule{Layers: []analyzer.LayerDef{
+		{Name: "app", Packages: []string{"app"}},
+		{Name: "infra", Packages: []string{"store"}},
+	}}
+
+	violations := rule.Check(g)
+	if len(violations) != 1 {
+		t.Fatalf("expected 1 violation, got %+v", violations)
+	}
+	want := "app -> store: " + analyzer.RuleConcreteDependency
+	if violations[0].Message != want || violations[0].Rule != DependencyInversionRuleName {
+		t.Errorf("unexpected violation: %+v", violations[0])
+	}
+}
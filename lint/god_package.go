@@ -0,0 +1,43 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Desgue/codegraph/analyzer"
+	"github.com/Desgue/codegraph/graph"
+)
+
+// GodPackageRuleName identifies violations of GodPackageRule.
+const GodPackageRuleName = "god-package"
+
+// GodPackageRule reports packages exceeding any of Thresholds, listing the
+// limits that tripped and the packages most connected to them; see
+// analyzer.FindGodPackages.
+type GodPackageRule struct {
+	Thresholds analyzer.GodPackageThresholds
+}
+
+func (r *GodPackageRule) Name() string {
+	return GodPackageRuleName
+}
+
+func (r *GodPackageRule) Check(g *graph.Graph) []Violation {
+	var violations []Violation
+	for _, godPackage := range analyzer.FindGodPackages(g, r.Thresholds) {
+		message := fmt.Sprintf("%s is a god package (%s)", godPackage.Package, strings.Join(godPackage.Exceeded, ", "))
+		if len(godPackage.TopImporters) > 0 {
+			message += "; top importers: " + strings.Join(godPackage.TopImporters, ", ")
+		}
+		if len(godPackage.TopImports) > 0 {
+			message += "; top imports: " + strings.Join(godPackage.TopImports, ", ")
+		}
+		violations = append(violations, Violation{
+			Rule:     r.Name(),
+			Severity: SeverityError,
+			Message:  message,
+			Nodes:    []string{godPackage.Package},
+		})
+	}
+	return violations
+}
@@ -0,0 +1,22 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/Desgue/codegraph/analyzer"
+)
+
+func TestGodPackageRule(t *testing.T) {
+	g := newLintTestGraph(t, []string{"app", "cli", "core", "log"},
+		[][2]string{{"app", "core"}, {"cli", "core"}, {"core", "log"}})
+	rule := &GodPackageRule{Thresholds: analyzer.GodPackageThresholds{FanIn: 1}}
+
+	violations := rule.Check(g)
+	if len(violations) != 1 {
+		t.Fatalf("expected 1 violation, got %+v", violations)
+	}
+	want := "core is a god package (fan-in 2 > 1); top importers: app, cli; top imports: log"
+	if violations[0].Message != want || violations[0].Rule != GodPackageRuleName || violations[0].Severity != SeverityError {
+		t.Errorf("unexpected violation: %+v", violations[0])
+	}
+}
@@ -0,0 +1,48 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Desgue/codegraph/analyzer"
+	"github.com/Desgue/codegraph/graph"
+)
+
+// ImportAliasRuleName identifies violations of ImportAliasRule.
+const ImportAliasRuleName = "import-alias"
+
+// ImportAliasRule warns about import paths used under several names and
+// aliases shadowing another imported package's name. Aliases come from the
+// source, not the graph, so the rule reports a precomputed
+// analyzer.CheckImportAliases result.
+type ImportAliasRule struct {
+	Report analyzer.ImportAliasReport
+}
+
+func (r *ImportAliasRule) Name() string {
+	return ImportAliasRuleName
+}
+
+func (r *ImportAliasRule) Check(g *graph.Graph) []Violation {
+	var violations []Violation
+	for _, inconsistent := range r.Report.Inconsistent {
+		uses := make([]string, 0, len(inconsistent.Names))
+		for _, name := range inconsistent.Names {
+			uses = append(uses, fmt.Sprintf("%s (%s)", name.Name, strings.Join(name.Positions, ", ")))
+		}
+		violations = append(violations, Violation{
+			Rule:     r.Name(),
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("%s is imported under %d names: %s", inconsistent.Path, len(inconsistent.Names), strings.Join(uses, "; ")),
+		})
+	}
+	for _, shadowing := range r.Report.Shadowing {
+		violations = append(violations, Violation{
+			Rule:     r.Name(),
+			Severity: SeverityWarning,
+			Message: fmt.Sprintf("%s: alias %s for %s shadows the name of %s",
+				shadowing.Position, shadowing.Alias, shadowing.Path, shadowing.Shadows),
+		})
+	}
+	return violations
+}
@@ -0,0 +1,33 @@
+package lint
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Desgue/codegraph/analyzer"
+)
+
+func TestImportAliasRule(t *testing.T) {
+	rule := &ImportAliasRule{Report: analyzer.ImportAliasReport{
+		Inconsistent: []analyzer.InconsistentImport{{Path: "mod/ctxutil", Names: []analyzer.ImportName{
+			{Name: "ctxutil", Positions: []string{"b.go:4"}},
+			{Name: "cu", Renamed: true, Positions: []string{"a.go:3", "c.go:3"}},
+		}}},
+		Shadowing: []analyzer.ShadowingAlias{{Path: "mod/web", Alias: "http", Shadows: "net/http", Position: "c.go:5"}},
+	}}
+
+	var messages []string
+	for _, violation := range rule.Check(newLintTestGraph(t, nil, nil)) {
+		if violation.Rule != ImportAliasRuleName || violation.Severity != SeverityWarning {
+			t.Errorf("unexpected violation: %+v", violation)
+		}
+		messages = append(messages, violation.Message)
+	}
+	want := []string{
+		"mod/ctxutil is imported under 2 names: ctxutil (b.go:4); cu (a.go:3, c.go:3)",
+		"c.go:5: alias http for mod/web shadows the name of net/http",
+	}
+	if !reflect.DeepEqual(messages, want) {
+		t.Errorf("messages = %q, want %q", messages, want)
+	}
+}
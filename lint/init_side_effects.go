@@ -0,0 +1,44 @@
+package lint
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Desgue/codegraph/analyzer"
+	"github.com/Desgue/codegraph/graph"
+)
+
+// InitSideEffectRuleName identifies violations of InitSideEffectRule.
+const InitSideEffectRuleName = "init-side-effects"
+
+// InitSideEffectRule reports side effects of package initialization, as errors
+// for the Banned kinds and warnings otherwise. Effects come from the source,
+// not the graph, so the rule reports a precomputed
+// analyzer.FindInitSideEffects result.
+type InitSideEffectRule struct {
+	Effects []analyzer.InitSideEffect
+	Banned  []string
+}
+
+func (r *InitSideEffectRule) Name() string {
+	return InitSideEffectRuleName
+}
+
+func (r *InitSideEffectRule) Check(g *graph.Graph) []Violation {
+	var violations []Violation
+	for _, effect := range r.Effects {
+		severity := SeverityWarning
+		if slices.Contains(r.Banned, effect.Kind) {
+			severity = SeverityError
+		}
+		violations = append(violations, Violation{
+			Rule:     r.Name(),
+			Severity: severity,
+			Message: fmt.Sprintf("%s: %s initialization has %s side effect: %s",
+				effect.Position, effect.Package, effect.Kind, strings.Join(effect.Chain, " -> ")),
+			Nodes: []string{effect.Package},
+		})
+	}
+	return violations
+}
@@ -0,0 +1,39 @@
+package lint
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Desgue/codegraph/analyzer"
+)
+
+func TestInitSideEffectRule(t *testing.T) {
+	rule := &InitSideEffectRule{
+		Effects: []analyzer.InitSideEffect{
+			{Package: "mod/config", Kind: analyzer.InitEffectEnv, Chain: []string{"mod/config.home", "os.Getenv"}, Position: "config.go:5"},
+			{Package: "mod/client", Kind: analyzer.InitEffectNetwork,
+				Chain: []string{"mod/client.init", "mod/client.warmUp", "net/http.Get"}, Position: "client.go:12"},
+		},
+		Banned: []string{analyzer.InitEffectNetwork},
+	}
+
+	var severities []Severity
+	var messages []string
+	for _, violation := range rule.Check(newLintTestGraph(t, nil, nil)) {
+		if violation.Rule != InitSideEffectRuleName {
+			t.Errorf("unexpected violation: %+v", violation)
+		}
+		severities = append(severities, violation.Severity)
+		messages = append(messages, violation.Message)
+	}
+	if want := []Severity{SeverityWarning, SeverityError}; !reflect.DeepEqual(severities, want) {
+		t.Errorf("severities = %v, want %v", severities, want)
+	}
+	want := []string{
+		"config.go:5: mod/config initialization has env side effect: mod/config.home -> os.Getenv",
+		"client.go:12: mod/client initialization has network side effect: mod/client.init -> mod/client.warmUp -> net/http.Get",
+	}
+	if !reflect.DeepEqual(messages, want) {
+		t.Errorf("messages = %q, want %q", messages, want)
+	}
+}
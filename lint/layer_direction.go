@@ -0,0 +1,53 @@
+package lint
+
+import (
+	"fmt"
+
+	"github.com/Desgue/codegraph/analyzer"
+	"github.com/Desgue/codegraph/graph"
+)
+
+// LayerDirectionRuleName identifies violations of LayerDirectionRule.
+const LayerDirectionRuleName = "layer-direction"
+
+// LayerDirectionRule reports imports that point upward through Layers, which
+// are ordered from highest to lowest; see analyzer.CheckLayerDirection.
+// Packages outside every layer are reported as warnings, or as errors when
+// Strict is set.
+type LayerDirectionRule struct {
+	Layers []analyzer.LayerDef
+	Strict bool
+}
+
+func (r *LayerDirectionRule) Name() string {
+	return LayerDirectionRuleName
+}
+
+func (r *LayerDirectionRule) Check(g *graph.Graph) []Violation {
+	layerViolations, unmapped := analyzer.CheckLayerDirection(g, r.Layers)
+
+	var violations []Violation
+	for _, violation := range layerViolations {
+		violations = append(violations, Violation{
+			Rule:     r.Name(),
+			Severity: SeverityError,
+			Message: fmt.Sprintf("%s -> %s: layer %s imports higher layer %s (imports must point down)",
+				violation.From, violation.To, violation.FromLayer, violation.ToLayer),
+			Nodes: []string{violation.From, violation.To},
+		})
+	}
+
+	severity := SeverityWarning
+	if r.Strict {
+		severity = SeverityError
+	}
+	for _, id := range unmapped {
+		violations = append(violations, Violation{
+			Rule:     r.Name(),
+			Severity: severity,
+			Message:  fmt.Sprintf("%s is not in any layer", id),
+			Nodes:    []string{id},
+		})
+	}
+	return violations
+}
@@ -0,0 +1,42 @@
+package lint
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Desgue/codegraph/analyzer"
+)
+
+func TestLayerDirectionRule(t *testing.T) {
+	g := newLintTestGraph(t, []string{"api", "store", "tools"}, [][2]string{{"store", "api"}, {"api", "store"}})
+	layers := []analyzer.LayerDef{
+		{Name: "transport", Packages: []string{"api"}},
+		{Name: "domain", Packages: []string{"store"}},
+	}
+
+	tests := []struct {
+		name             string
+		strict           bool
+		unmappedSeverity Severity
+	}{
+		{name: "unmapped packages warn", unmappedSeverity: SeverityWarning},
+		{name: "strict mode makes them errors", strict: true, unmappedSeverity: SeverityError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := (&LayerDirectionRule{Layers: layers, Strict: tt.strict}).Check(g)
+
+			want := []Violation{
+				{Rule: LayerDirectionRuleName, Severity: SeverityError,
+					Message: "store -> api: layer domain imports higher layer transport (imports must point down)",
+					Nodes:   []string{"store", "api"}},
+				{Rule: LayerDirectionRuleName, Severity: tt.unmappedSeverity,
+					Message: "tools is not in any layer", Nodes: []string{"tools"}},
+			}
+			if !reflect.DeepEqual(violations, want) {
+				t.Errorf("Check() = %+v, want %+v", violations, want)
+			}
+		})
+	}
+}
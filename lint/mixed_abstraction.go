@@ -0,0 +1,42 @@
+package lint
+
+import (
+	"fmt"
+
+	"github.com/Desgue/codegraph/analyzer"
+	"github.com/Desgue/codegraph/graph"
+)
+
+// MixedAbstractionRuleName identifies violations of MixedAbstractionRule.
+const MixedAbstractionRuleName = "mixed-abstraction"
+
+// MixedAbstractionRule warns about packages whose imports span more than
+// Threshold layers, a sign they mix abstraction levels; see
+// analyzer.DetectMixedAbstractionLevels. Graphs with import cycles have no
+// layers and are left to NoCircularDependencyRule.
+type MixedAbstractionRule struct {
+	Threshold int
+}
+
+func (r *MixedAbstractionRule) Name() string {
+	return MixedAbstractionRuleName
+}
+
+func (r *MixedAbstractionRule) Check(g *graph.Graph) []Violation {
+	findings, err := analyzer.DetectMixedAbstractionLevels(g, r.Threshold)
+	if err != nil {
+		return nil
+	}
+	var violations []Violation
+	for _, finding := range findings {
+		violations = append(violations, Violation{
+			Rule:     r.Name(),
+			Severity: SeverityWarning,
+			Message: fmt.Sprintf("%s imports span %d layers (%s at layer %d to %s at layer %d), more than %d",
+				finding.Package, finding.Span, finding.HighestImport, finding.HighestLayer,
+				finding.LowestImport, finding.LowestLayer, r.Threshold),
+			Nodes: []string{finding.Package, finding.HighestImport, finding.LowestImport},
+		})
+	}
+	return violations
+}
@@ -0,0 +1,23 @@
+package lint
+
+import "testing"
+
+func TestMixedAbstractionRule(t *testing.T) {
+	g := newLintTestGraph(t, []string{"cmd", "app", "service", "db", "handler"},
+		[][2]string{{"cmd", "app"}, {"app", "service"}, {"service", "db"}, {"cmd", "handler"}, {"handler", "app"}, {"handler", "db"}})
+	rule := &MixedAbstractionRule{Threshold: 1}
+
+	violations := rule.Check(g)
+	if len(violations) != 1 {
+		t.Fatalf("expected 1 violation, got %+v", violations)
+	}
+	want := "handler imports span 2 layers (app at layer 2 to db at layer 4), more than 1"
+	if violations[0].Message != want || violations[0].Rule != MixedAbstractionRuleName || violations[0].Severity != SeverityWarning {
+		t.Errorf("unexpected violation: %+v", violations[0])
+	}
+
+	cyclic := newLintTestGraph(t, []string{"a", "b"}, [][2]string{{"a", "b"}, {"b", "a"}})
+	if violations := rule.Check(cyclic); len(violations) != 0 {
+		t.Errorf("expected cyclic graphs to be skipped, got %+v", violations)
+	}
+}
@@ -0,0 +1,33 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+// NoCircularDependencyRuleName identifies violations of NoCircularDependencyRule.
+const NoCircularDependencyRuleName = "no-circular-dependency"
+
+// NoCircularDependencyRule reports every import cycle as an error. Import
+// cycles do not compile in Go, so any cycle indicates a structural problem.
+type NoCircularDependencyRule struct{}
+
+func (r *NoCircularDependencyRule) Name() string {
+	return NoCircularDependencyRuleName
+}
+
+// Check reports one violation per cycle, listing the packages in the cycle.
+func (r *NoCircularDependencyRule) Check(g *graph.Graph) []Violation {
+	var violations []Violation
+	for _, cycle := range g.FindCycles([]graph.EdgeKind{graph.EdgeImport}) {
+		violations = append(violations, Violation{
+			Rule:     r.Name(),
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("import cycle between %s", strings.Join(cycle, ", ")),
+			Nodes:    cycle,
+		})
+	}
+	return violations
+}
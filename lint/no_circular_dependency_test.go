@@ -0,0 +1,33 @@
+package lint
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNoCircularDependencyRule(t *testing.T) {
+	g := newLintTestGraph(t,
+		[]string{"api", "cmd", "store", "util", "x", "y"},
+		[][2]string{{"cmd", "api"}, {"api", "store"}, {"store", "api"}, {"store", "util"}, {"x", "y"}, {"y", "x"}},
+	)
+
+	violations := (&NoCircularDependencyRule{}).Check(g)
+
+	want := []Violation{
+		{Rule: NoCircularDependencyRuleName, Severity: SeverityError,
+			Message: "import cycle between api, store", Nodes: []string{"api", "store"}},
+		{Rule: NoCircularDependencyRuleName, Severity: SeverityError,
+			Message: "import cycle between x, y", Nodes: []string{"x", "y"}},
+	}
+	if !reflect.DeepEqual(violations, want) {
+		t.Errorf("Check() = %+v, want %+v", violations, want)
+	}
+}
+
+func TestNoCircularDependencyRule_Acyclic(t *testing.T) {
+	g := newLintTestGraph(t, []string{"a", "b", "c"}, [][2]string{{"a", "b"}, {"b", "c"}, {"a", "c"}})
+
+	if violations := (&NoCircularDependencyRule{}).Check(g); len(violations) != 0 {
+		t.Errorf("expected no violations, got %+v", violations)
+	}
+}
@@ -0,0 +1,62 @@
+package lint
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Desgue/codegraph/analyzer"
+	"github.com/Desgue/codegraph/graph"
+)
+
+// NoDirectProtoImportRuleName identifies violations of NoDirectProtoImportRule.
+const NoDirectProtoImportRuleName = "no-direct-proto-import"
+
+// NoDirectProtoImportRule warns about packages importing a protoc-gen-go
+// package (Node.ProtobufGenerated) directly, which ties business logic to the
+// wire format. Imports between generated packages are not reported, nor
+// imports by the adapter packages the Allowed patterns (pkg or pkg/...)
+// match, such as gRPC handlers converting messages to domain types. Only
+// loaded packages are graph nodes, so generated packages from other modules
+// are not checked.
+type NoDirectProtoImportRule struct {
+	Allowed []string
+}
+
+func (r *NoDirectProtoImportRule) Name() string {
+	return NoDirectProtoImportRuleName
+}
+
+func (r *NoDirectProtoImportRule) Check(g *graph.Graph) []Violation {
+	var imports []*graph.Edge
+	for _, edge := range g.Edges() {
+		if edge.Kind != graph.EdgeImport {
+			continue
+		}
+		from, fromFound := g.Node(edge.From)
+		to, toFound := g.Node(edge.To)
+		if !fromFound || !toFound || from.ProtobufGenerated || !to.ProtobufGenerated {
+			continue
+		}
+		if analyzer.MatchesAnyPackagePattern(r.Allowed, from.ID) {
+			continue
+		}
+		imports = append(imports, edge)
+	}
+	sort.Slice(imports, func(i, j int) bool {
+		if imports[i].From != imports[j].From {
+			return imports[i].From < imports[j].From
+		}
+		return imports[i].To < imports[j].To
+	})
+
+	var violations []Violation
+	for _, edge := range imports {
+		violations = append(violations, Violation{
+			Rule:     r.Name(),
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("%s imports generated protobuf package %s directly; convert messages in an adapter package", edge.From, edge.To),
+			Nodes:    []string{edge.From, edge.To},
+		})
+	}
+	return violations
+}
@@ -0,0 +1,64 @@
+package lint
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+func TestNoDirectProtoImportRule(t *testing.T) {
+	g := graph.New()
+	for _, node := range []*graph.Node{
+		{ID: "mod/gen/userpb", Kind: graph.KindPackage, ProtobufGenerated: true},
+		{ID: "mod/gen/orderpb", Kind: graph.KindPackage, ProtobufGenerated: true},
+		{ID: "mod/billing", Kind: graph.KindPackage},
+		{ID: "mod/orders", Kind: graph.KindPackage},
+		{ID: "mod/transport/grpc", Kind: graph.KindPackage},
+		{ID: "mod/domain", Kind: graph.KindPackage},
+	} {
+		if err := g.AddNode(node); err != nil {
+			t.Fatalf("AddNode() error = %v", err)
+		}
+	}
+	for _, edge := range []*graph.Edge{
+		{From: "mod/orders", To: "mod/gen/orderpb", Kind: graph.EdgeImport},
+		{From: "mod/billing", To: "mod/gen/userpb", Kind: graph.EdgeImport},
+		{From: "mod/billing", To: "mod/gen/orderpb", Kind: graph.EdgeImport},
+		{From: "mod/billing", To: "mod/domain", Kind: graph.EdgeImport},
+		// Generated packages import each other, adapters convert messages
+		// and tests build fixtures from them.
+		{From: "mod/gen/orderpb", To: "mod/gen/userpb", Kind: graph.EdgeImport},
+		{From: "mod/transport/grpc", To: "mod/gen/userpb", Kind: graph.EdgeImport},
+		{From: "mod/domain", To: "mod/gen/userpb", Kind: graph.EdgeTestImport},
+	} {
+		if err := g.AddEdge(edge); err != nil {
+			t.Fatalf("AddEdge() error = %v", err)
+		}
+	}
+
+	violations := (&NoDirectProtoImportRule{Allowed: []string{"mod/transport/..."}}).Check(g)
+	var got [][]string
+	for _, violation := range violations {
+		if violation.Rule != NoDirectProtoImportRuleName || violation.Severity != SeverityWarning {
+			t.Errorf("unexpected violation: %+v", violation)
+		}
+		got = append(got, violation.Nodes)
+	}
+	want := [][]string{
+		{"mod/billing", "mod/gen/orderpb"},
+		{"mod/billing", "mod/gen/userpb"},
+		{"mod/orders", "mod/gen/orderpb"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("violation nodes = %v, want %v", got, want)
+	}
+	wantMessage := "mod/billing imports generated protobuf package mod/gen/orderpb directly; convert messages in an adapter package"
+	if violations[0].Message != wantMessage {
+		t.Errorf("message = %q, want %q", violations[0].Message, wantMessage)
+	}
+
+	if violations := (&NoDirectProtoImportRule{}).Check(g); len(violations) != 4 {
+		t.Errorf("without allowed adapters got %d violations, want 4", len(violations))
+	}
+}
@@ -0,0 +1,57 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Desgue/codegraph/analyzer"
+	"github.com/Desgue/codegraph/graph"
+)
+
+// PackageNameRuleName identifies violations of PackageNameRule.
+const PackageNameRuleName = "package-name"
+
+// PackageNameRule warns about packages named like a standard library package
+// or another loaded package, which importers keep having to alias. Names come
+// from the source, not the graph, so the rule reports a precomputed
+// analyzer.FindCollidingPackageNames result.
+type PackageNameRule struct {
+	Collisions []analyzer.CollidingPackageName
+}
+
+func (r *PackageNameRule) Name() string {
+	return PackageNameRuleName
+}
+
+func (r *PackageNameRule) Check(g *graph.Graph) []Violation {
+	var violations []Violation
+	for _, collision := range r.Collisions {
+		var others []string
+		for _, stdlibPath := range collision.Stdlib {
+			others = append(others, stdlibPath+" (stdlib)")
+		}
+		others = append(others, collision.Loaded...)
+
+		imports := "not imported"
+		if len(collision.Imports) > 0 {
+			uses := make([]string, 0, len(collision.Imports))
+			for _, use := range collision.Imports {
+				if use.Alias == "" {
+					uses = append(uses, use.Importer+" unrenamed")
+				} else {
+					uses = append(uses, use.Importer+" as "+use.Alias)
+				}
+			}
+			imports = "imported by " + strings.Join(uses, ", ")
+		}
+
+		violations = append(violations, Violation{
+			Rule:     r.Name(),
+			Severity: SeverityWarning,
+			Message: fmt.Sprintf("%s is named %s like %s; %s",
+				collision.Package, collision.Name, strings.Join(others, ", "), imports),
+			Nodes: append([]string{collision.Package}, collision.Loaded...),
+		})
+	}
+	return violations
+}
@@ -0,0 +1,33 @@
+package lint
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Desgue/codegraph/analyzer"
+)
+
+func TestPackageNameRule(t *testing.T) {
+	rule := &PackageNameRule{Collisions: []analyzer.CollidingPackageName{
+		{Package: "mod/errors", Name: "errors", Stdlib: []string{"errors"}, Imports: []analyzer.PackageNameImport{
+			{Importer: "mod/api", Alias: "apperrors"},
+			{Importer: "mod/store"},
+		}},
+		{Package: "mod/db/store", Name: "store", Loaded: []string{"mod/cache/store"}},
+	}}
+
+	var messages []string
+	for _, violation := range rule.Check(newLintTestGraph(t, nil, nil)) {
+		if violation.Rule != PackageNameRuleName || violation.Severity != SeverityWarning {
+			t.Errorf("unexpected violation: %+v", violation)
+		}
+		messages = append(messages, violation.Message)
+	}
+	want := []string{
+		"mod/errors is named errors like errors (stdlib); imported by mod/api as apperrors, mod/store unrenamed",
+		"mod/db/store is named store like mod/cache/store; not imported",
+	}
+	if !reflect.DeepEqual(messages, want) {
+		t.Errorf("messages = %q, want %q", messages, want)
+	}
+}
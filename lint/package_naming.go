@@ -0,0 +1,44 @@
+package lint
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+// PackageNamingRuleName identifies violations of PackageNamingRule.
+const PackageNamingRuleName = "package-naming"
+
+// DefaultBlockedPackageNames are the catch-all package names that say nothing
+// about what a package provides.
+var DefaultBlockedPackageNames = []string{"utils", "helpers", "common", "misc", "shared"}
+
+// PackageNamingRule warns about package nodes whose Name is in Blocked.
+type PackageNamingRule struct {
+	Blocked []string
+}
+
+func (r *PackageNamingRule) Name() string {
+	return PackageNamingRuleName
+}
+
+func (r *PackageNamingRule) Check(g *graph.Graph) []Violation {
+	nodes := append([]*graph.Node(nil), g.Nodes()...)
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+
+	var violations []Violation
+	for _, node := range nodes {
+		if node.Kind != graph.KindPackage || !slices.Contains(r.Blocked, node.Name) {
+			continue
+		}
+		violations = append(violations, Violation{
+			Rule:     r.Name(),
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("%s uses the blocked package name %q; name it after what it provides", node.ID, node.Name),
+			Nodes:    []string{node.ID},
+		})
+	}
+	return violations
+}
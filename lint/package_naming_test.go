@@ -0,0 +1,36 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+func TestPackageNamingRule(t *testing.T) {
+	g := graph.New()
+	for _, node := range []*graph.Node{
+		{ID: "mod/internal/utils", Kind: graph.KindPackage, Name: "utils"},
+		{ID: "mod/store", Kind: graph.KindPackage, Name: "store"},
+		{ID: "mod/common", Kind: graph.KindPackage, Name: "common"},
+	} {
+		if err := g.AddNode(node); err != nil {
+			t.Fatalf("AddNode() error = %v", err)
+		}
+	}
+
+	violations := (&PackageNamingRule{Blocked: DefaultBlockedPackageNames}).Check(g)
+	if len(violations) != 2 {
+		t.Fatalf("expected 2 violations, got %+v", violations)
+	}
+	want := `mod/common uses the blocked package name "common"; name it after what it provides`
+	if violations[0].Message != want || violations[0].Rule != PackageNamingRuleName || violations[0].Severity != SeverityWarning {
+		t.Errorf("unexpected violation: %+v", violations[0])
+	}
+	if violations[1].Nodes[0] != "mod/internal/utils" {
+		t.Errorf("second violation = %+v, want mod/internal/utils", violations[1])
+	}
+
+	if violations := (&PackageNamingRule{Blocked: []string{"store"}}).Check(g); len(violations) != 1 {
+		t.Errorf("expected a custom blocklist to flag only store, got %+v", violations)
+	}
+}
@@ -0,0 +1,42 @@
+// Package lint checks a dependency graph against architectural rules and
+// reports each breach as a Violation.
+package lint
+
+import "github.com/Desgue/codegraph/graph"
+
+// Severity ranks how serious a violation is.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Violation is one breach of a rule.
+type Violation struct {
+	Rule     string
+	Severity Severity
+	Message  string
+	// Nodes lists the IDs of the nodes involved, e.g. the members of a cycle.
+	Nodes []string
+}
+
+// Rule checks a graph and reports the violations it finds.
+type Rule interface {
+	Name() string
+	Check(g *graph.Graph) []Violation
+}
+
+// DefaultRuleSet returns the rules enabled when no configuration says otherwise.
+func DefaultRuleSet() []Rule {
+	return []Rule{&NoCircularDependencyRule{}}
+}
+
+// Check runs rules in order and returns their violations concatenated.
+func Check(g *graph.Graph, rules []Rule) []Violation {
+	var violations []Violation
+	for _, rule := range rules {
+		violations = append(violations, rule.Check(g)...)
+	}
+	return violations
+}
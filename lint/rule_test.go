@@ -0,0 +1,40 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+func newLintTestGraph(t *testing.T, ids []string, edges [][2]string) *graph.Graph {
+	t.Helper()
+
+	g := graph.New()
+	for _, id := range ids {
+		if err := g.AddNode(&graph.Node{ID: id, Kind: graph.KindPackage}); err != nil {
+			t.Fatalf("AddNode() error = %v", err)
+		}
+	}
+	for _, edge := range edges {
+		if err := g.AddEdge(&graph.Edge{From: edge[0], To: edge[1], Kind: graph.EdgeImport}); err != nil {
+			t.Fatalf("AddEdge() error = %v", err)
+		}
+	}
+	return g
+}
+
+func TestDefaultRuleSet(t *testing.T) {
+	rules := DefaultRuleSet()
+	if len(rules) != 1 || rules[0].Name() != NoCircularDependencyRuleName {
+		t.Errorf("expected only the no-circular-dependency rule by default, got %v", rules)
+	}
+}
+
+func TestCheck_ConcatenatesRulesInOrder(t *testing.T) {
+	g := newLintTestGraph(t, []string{"a", "b"}, [][2]string{{"a", "b"}, {"b", "a"}})
+	rules := []Rule{&NoCircularDependencyRule{}, &NoCircularDependencyRule{}}
+
+	if violations := Check(g, rules); len(violations) != 2 {
+		t.Errorf("expected one violation per rule, got %v", violations)
+	}
+}
@@ -0,0 +1,36 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Desgue/codegraph/analyzer"
+	"github.com/Desgue/codegraph/graph"
+)
+
+// SignatureRuleName identifies violations of SignatureRule.
+const SignatureRuleName = "signature"
+
+// SignatureRule reports exported functions whose signatures exceed any of
+// Thresholds; see analyzer.FindSignatureSmells. The graph needs the function
+// nodes of extract.SymbolExtractor, without which the rule finds nothing.
+type SignatureRule struct {
+	Thresholds analyzer.SignatureThresholds
+}
+
+func (r *SignatureRule) Name() string {
+	return SignatureRuleName
+}
+
+func (r *SignatureRule) Check(g *graph.Graph) []Violation {
+	var violations []Violation
+	for _, smell := range analyzer.FindSignatureSmells(g, r.Thresholds) {
+		violations = append(violations, Violation{
+			Rule:     r.Name(),
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("%s has an unwieldy signature (%s)", smell.Function, strings.Join(smell.Exceeded, ", ")),
+			Nodes:    []string{smell.Function},
+		})
+	}
+	return violations
+}
@@ -0,0 +1,35 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/Desgue/codegraph/analyzer"
+	"github.com/Desgue/codegraph/extract"
+	"github.com/Desgue/codegraph/graph"
+)
+
+func TestSignatureRule(t *testing.T) {
+	g := newLintTestGraph(t, []string{"app"}, nil)
+	for id, counts := range map[string][3]string{
+		graph.FuncID("app", "", "Open"):  {"7", "1", "0"},
+		graph.FuncID("app", "", "Close"): {"1", "1", "0"},
+	} {
+		node := &graph.Node{ID: id, Kind: graph.KindFunction}
+		node.SetAttribute(extract.AttributeParamCount, counts[0])
+		node.SetAttribute(extract.AttributeResultCount, counts[1])
+		node.SetAttribute(extract.AttributeBoolParamCount, counts[2])
+		if err := g.AddNode(node); err != nil {
+			t.Fatalf("AddNode() error = %v", err)
+		}
+	}
+	rule := &SignatureRule{Thresholds: analyzer.DefaultSignatureThresholds()}
+
+	violations := rule.Check(g)
+	if len(violations) != 1 {
+		t.Fatalf("expected 1 violation, got %+v", violations)
+	}
+	want := "app.Open has an unwieldy signature (params 7 > 5)"
+	if violations[0].Message != want || violations[0].Rule != SignatureRuleName || violations[0].Severity != SeverityError {
+		t.Errorf("unexpected violation: %+v", violations[0])
+	}
+}
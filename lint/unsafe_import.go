@@ -0,0 +1,38 @@
+package lint
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+// UnsafeImportRuleName identifies violations of UnsafeImportRule.
+const UnsafeImportRuleName = "unsafe-import"
+
+// UnsafeImportRule warns about packages importing unsafe (Node.UnsafeUsage),
+// listing them for security review.
+type UnsafeImportRule struct{}
+
+func (r *UnsafeImportRule) Name() string {
+	return UnsafeImportRuleName
+}
+
+func (r *UnsafeImportRule) Check(g *graph.Graph) []Violation {
+	nodes := append([]*graph.Node(nil), g.Nodes()...)
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+
+	var violations []Violation
+	for _, node := range nodes {
+		if node.Kind != graph.KindPackage || !node.UnsafeUsage {
+			continue
+		}
+		violations = append(violations, Violation{
+			Rule:     r.Name(),
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("%s imports unsafe; review it for memory safety", node.ID),
+			Nodes:    []string{node.ID},
+		})
+	}
+	return violations
+}
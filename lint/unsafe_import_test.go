@@ -0,0 +1,32 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/Desgue/codegraph/graph"
+)
+
+func TestUnsafeImportRule(t *testing.T) {
+	g := graph.New()
+	for _, node := range []*graph.Node{
+		{ID: "mod/store", Kind: graph.KindPackage, UnsafeUsage: true},
+		{ID: "mod/api", Kind: graph.KindPackage},
+		{ID: "mod/internal/bytesconv", Kind: graph.KindPackage, UnsafeUsage: true},
+	} {
+		if err := g.AddNode(node); err != nil {
+			t.Fatalf("AddNode() error = %v", err)
+		}
+	}
+
+	violations := (&UnsafeImportRule{}).Check(g)
+	if len(violations) != 2 {
+		t.Fatalf("expected 2 violations, got %+v", violations)
+	}
+	want := "mod/internal/bytesconv imports unsafe; review it for memory safety"
+	if violations[0].Message != want || violations[0].Rule != UnsafeImportRuleName || violations[0].Severity != SeverityWarning {
+		t.Errorf("unexpected violation: %+v", violations[0])
+	}
+	if violations[1].Nodes[0] != "mod/store" {
+		t.Errorf("second violation = %+v, want mod/store", violations[1])
+	}
+}
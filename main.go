@@ -1,32 +1,63 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/Desgue/codegraph/cli"
 )
 
+type command interface {
+	Execute() error
+}
+
+var commands = map[string]func(args []string) (command, error){
+	"parse":      func(args []string) (command, error) { return cli.NewParseCommand(args) },
+	"lint":       func(args []string) (command, error) { return cli.NewLintCommand(args) },
+	"diff":       func(args []string) (command, error) { return cli.NewDiffCommand(args) },
+	"analyze":    func(args []string) (command, error) { return cli.NewAnalyzeCommand(args) },
+	"merge":      func(args []string) (command, error) { return cli.NewMergeCommand(args) },
+	"deadcode":   func(args []string) (command, error) { return cli.NewDeadCodeCommand(args) },
+	"coverage":   func(args []string) (command, error) { return cli.NewCoverageCommand(args) },
+	"boundaries": func(args []string) (command, error) { return cli.NewBoundariesCommand(args) },
+	"api":        func(args []string) (command, error) { return cli.NewAPICommand(args) },
+	"apidiff":    func(args []string) (command, error) { return cli.NewAPIDiffCommand(args) },
+	"matrix":     func(args []string) (command, error) { return cli.NewMatrixCommand(args) },
+	"cycles":     func(args []string) (command, error) { return cli.NewCyclesCommand(args) },
+	"todos":      func(args []string) (command, error) { return cli.NewTodosCommand(args) },
+	"reach":      func(args []string) (command, error) { return cli.NewReachCommand(args) },
+	"errors":     func(args []string) (command, error) { return cli.NewErrorsCommand(args) },
+	"cache":      func(args []string) (command, error) { return cli.NewCacheCommand(args) },
+}
+
 func main() {
 	if len(os.Args) < 2 {
 		fmt.Fprintf(os.Stderr, "Usage: codegraph <command> [options]\n")
 		os.Exit(1)
 	}
 
-	switch os.Args[1] {
-	case "parse":
-		parseCommand, err := cli.NewParseCommand(os.Args[2:])
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
-		}
-
-		if err := parseCommand.Execute(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
-		}
-	default:
+	newCommand, found := commands[os.Args[1]]
+	if !found {
 		fmt.Fprintf(os.Stderr, "Unknown command: %s\nUsage: codegraph <command> [options]\n", os.Args[1])
 		os.Exit(1)
 	}
+
+	selectedCommand, err := newCommand(os.Args[2:])
+	if err != nil {
+		exitWithError(err)
+	}
+
+	if err := selectedCommand.Execute(); err != nil {
+		exitWithError(err)
+	}
+}
+
+// exitWithError reports err and exits with the code mapped by cli.ExitCode.
+func exitWithError(err error) {
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	if errors.Is(err, cli.ErrUsage) {
+		fmt.Fprintf(os.Stderr, "Usage: codegraph %s [options]\n", os.Args[1])
+	}
+	os.Exit(cli.ExitCode(err))
 }
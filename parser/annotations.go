@@ -0,0 +1,88 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// DefaultAnnotationMarkers are the comment markers ExtractAnnotations looks
+// for when given none.
+var DefaultAnnotationMarkers = []string{"TODO", "FIXME", "HACK", "XXX"}
+
+// Annotation is a marker comment such as "// TODO(alice): retry on timeout".
+type Annotation struct {
+	Marker string `json:"marker"`
+	// Author is the name in the "TODO(name):" form, or empty.
+	Author string `json:"author,omitempty"`
+	Text   string `json:"text"`
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+}
+
+// ExtractAnnotations returns the marker comments in pkg's files, in file and
+// line order, one per comment line. Markers only match as whole words, so
+// "TODOS" or "XXXL" do not count, and only inside comments, never in string
+// literals. Markers are case-sensitive; nil means DefaultAnnotationMarkers.
+// Requires NeedSyntax, which parses comments.
+func ExtractAnnotations(pkg *packages.Package, markers []string) []Annotation {
+	if len(markers) == 0 {
+		markers = DefaultAnnotationMarkers
+	}
+	pattern := annotationPattern(markers)
+
+	var annotations []Annotation
+	for _, file := range pkg.Syntax {
+		for _, group := range file.Comments {
+			for _, comment := range group.List {
+				position := pkg.Fset.Position(comment.Slash)
+				for offset, line := range strings.Split(commentBody(comment.Text), "\n") {
+					match := pattern.FindStringSubmatch(line)
+					if match == nil {
+						continue
+					}
+					annotations = append(annotations, Annotation{
+						Marker: match[1],
+						Author: strings.TrimSpace(match[2]),
+						Text:   strings.TrimSpace(match[3]),
+						File:   position.Filename,
+						Line:   position.Line + offset,
+					})
+				}
+			}
+		}
+	}
+	return annotations
+}
+
+// annotationPatterns caches the compiled pattern per marker list, keyed by
+// the markers joined with NUL: extraction calls ExtractAnnotations for every
+// package, concurrently, and compiling anew each time dominated its
+// allocations.
+var annotationPatterns sync.Map
+
+// annotationPattern matches a marker as a whole word, an optional
+// parenthesized author, an optional colon and the rest of the line.
+func annotationPattern(markers []string) *regexp.Regexp {
+	key := strings.Join(markers, "\x00")
+	if pattern, found := annotationPatterns.Load(key); found {
+		return pattern.(*regexp.Regexp)
+	}
+	quoted := make([]string, len(markers))
+	for i, marker := range markers {
+		quoted[i] = regexp.QuoteMeta(marker)
+	}
+	pattern, _ := annotationPatterns.LoadOrStore(key,
+		regexp.MustCompile(`\b(`+strings.Join(quoted, "|")+`)\b(?:\(([^)]*)\))?:?(.*)`))
+	return pattern.(*regexp.Regexp)
+}
+
+// commentBody strips the comment delimiters of a // or /* */ comment.
+func commentBody(text string) string {
+	if body, found := strings.CutPrefix(text, "//"); found {
+		return body
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(text, "/*"), "*/")
+}
@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExtractAnnotations(t *testing.T) {
+	testDir := t.TempDir()
+
+	goMod := filepath.Join(testDir, "go.mod")
+	if err := os.WriteFile(goMod, []byte("module testmod\n\ngo 1.24\n"), 0644); err != nil {
+		t.Fatalf("Failed to create go.mod: %v", err)
+	}
+
+	content := `package jobs
+
+// TODO(alice): retry on timeout
+func Run() {}
+
+// Queue holds jobs. FIXME drop the global lock
+var Queue []string
+
+/*
+HACK: sleep until the cache warms
+XXX
+*/
+var message = "TODO: not a comment"
+
+// TODOS and XXXL are not markers, nor is todo.
+const Limit = 10 // NOTE: not a default marker
+`
+	if err := os.WriteFile(filepath.Join(testDir, "jobs.go"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create jobs.go: %v", err)
+	}
+
+	pkgs, _, err := Load(testDir, false)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(pkgs) != 1 {
+		t.Fatalf("Expected 1 package, got %d", len(pkgs))
+	}
+	file := pkgs[0].GoFiles[0]
+
+	want := []Annotation{
+		{Marker: "TODO", Author: "alice", Text: "retry on timeout", File: file, Line: 3},
+		{Marker: "FIXME", Text: "drop the global lock", File: file, Line: 6},
+		{Marker: "HACK", Text: "sleep until the cache warms", File: file, Line: 10},
+		{Marker: "XXX", File: file, Line: 11},
+	}
+	if got := ExtractAnnotations(pkgs[0], nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractAnnotations() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	want = []Annotation{{Marker: "NOTE", Text: "not a default marker", File: file, Line: 16}}
+	if got := ExtractAnnotations(pkgs[0], []string{"NOTE"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractAnnotations(NOTE) =\n%+v\nwant\n%+v", got, want)
+	}
+}
@@ -0,0 +1,80 @@
+package parser
+
+import (
+	"go/ast"
+	"go/build/constraint"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// ExtractBuildConstraints returns the sorted, distinct build constraint
+// expressions of the package's non-test files, in //go:build syntax. Files
+// with only legacy // +build lines have them combined and rewritten, so
+// "// +build linux,amd64" becomes "linux && amd64". Lines that do not parse
+// are kept verbatim. Only constraints in the file header, before the package
+// clause, count. Requires NeedSyntax.
+func ExtractBuildConstraints(pkg *packages.Package) []string {
+	if pkg.Fset == nil {
+		return nil
+	}
+
+	expressions := make(map[string]bool)
+	for _, file := range pkg.Syntax {
+		if strings.HasSuffix(pkg.Fset.Position(file.Pos()).Filename, "_test.go") {
+			continue
+		}
+		if expression := fileBuildConstraint(file); expression != "" {
+			expressions[expression] = true
+		}
+	}
+
+	var constraints []string
+	for expression := range expressions {
+		constraints = append(constraints, expression)
+	}
+	sort.Strings(constraints)
+	return constraints
+}
+
+// fileBuildConstraint returns the file's constraint expression, preferring
+// the //go:build line over // +build lines as the go command does.
+func fileBuildConstraint(file *ast.File) string {
+	var plusBuild constraint.Expr
+	var unparsed []string
+	for _, group := range file.Comments {
+		if group.Pos() >= file.Package {
+			break
+		}
+		for _, comment := range group.List {
+			switch {
+			case constraint.IsGoBuild(comment.Text):
+				expression, err := constraint.Parse(comment.Text)
+				if err != nil {
+					return strings.TrimSpace(strings.TrimPrefix(comment.Text, "//go:build"))
+				}
+				return expression.String()
+			case constraint.IsPlusBuild(comment.Text):
+				expression, err := constraint.Parse(comment.Text)
+				if err != nil {
+					unparsed = append(unparsed, strings.TrimSpace(strings.TrimPrefix(comment.Text, "// +build")))
+					continue
+				}
+				if plusBuild == nil {
+					plusBuild = expression
+				} else {
+					plusBuild = &constraint.AndExpr{X: plusBuild, Y: expression}
+				}
+			}
+		}
+	}
+
+	if len(unparsed) > 0 {
+		return strings.Join(unparsed, " ")
+	}
+	if plusBuild == nil {
+		return ""
+	}
+	return plusBuild.String()
+}
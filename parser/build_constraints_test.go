@@ -0,0 +1,47 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractBuildConstraints(t *testing.T) {
+	tests := []struct {
+		name    string
+		sources []string
+		want    []string
+	}{
+		{
+			name:    "go:build lines, deduplicated and sorted",
+			sources: []string{"//go:build linux\n\npackage a\n", "//go:build darwin || freebsd\n\npackage a\n", "//go:build linux\n\npackage a\n"},
+			want:    []string{"darwin || freebsd", "linux"},
+		},
+		{
+			name:    "legacy +build lines are combined and rewritten",
+			sources: []string{"// +build linux,amd64 darwin\n// +build cgo\n\npackage a\n"},
+			want:    []string{"((linux && amd64) || darwin) && cgo"},
+		},
+		{
+			name:    "go:build wins over +build",
+			sources: []string{"// Copyright notice.\n\n//go:build ignore\n// +build ignore\n\npackage a\n"},
+			want:    []string{"ignore"},
+		},
+		{
+			name:    "comments after the package clause do not count",
+			sources: []string{"package a\n\n//go:build linux\n"},
+		},
+		{
+			name:    "unconstrained",
+			sources: []string{"// Package a does things.\npackage a\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := parseTestPackage(t, "a", tt.sources...)
+			if got := ExtractBuildConstraints(pkg); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractBuildConstraints() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
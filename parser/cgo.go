@@ -0,0 +1,28 @@
+package parser
+
+import (
+	"os"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// RequiresCGO reports whether any of pkg.GoFiles contains a #cgo directive or
+// an import "C" statement. It is a plain text scan, so a match inside an
+// unrelated comment or string also counts. Unreadable files are skipped.
+func RequiresCGO(pkg *packages.Package) bool {
+	for _, filename := range pkg.GoFiles {
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			continue
+		}
+		if containsCGOMarker(string(content)) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsCGOMarker(source string) bool {
+	return strings.Contains(source, "// #cgo") || strings.Contains(source, `import "C"`)
+}
@@ -0,0 +1,63 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestRequiresCGO(t *testing.T) {
+	tests := []struct {
+		name    string
+		sources []string
+		want    bool
+	}{
+		{
+			name:    "import C",
+			sources: []string{"package a\n", "package a\n\n// #include <stdio.h>\nimport \"C\"\n"},
+			want:    true,
+		},
+		{
+			name:    "cgo directive",
+			sources: []string{"package a\n\n// #cgo LDFLAGS: -lm\n"},
+			want:    true,
+		},
+		{
+			name:    "pure Go",
+			sources: []string{"package a\n\nimport \"fmt\"\n\nvar _ = fmt.Sprint\n"},
+			want:    false,
+		},
+		{
+			name: "no files",
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := &packages.Package{PkgPath: "example.com/a"}
+			directory := t.TempDir()
+			for i, source := range tt.sources {
+				filename := filepath.Join(directory, fmt.Sprintf("file%d.go", i))
+				if err := os.WriteFile(filename, []byte(source), 0644); err != nil {
+					t.Fatalf("failed to write %s: %v", filename, err)
+				}
+				pkg.GoFiles = append(pkg.GoFiles, filename)
+			}
+
+			if got := RequiresCGO(pkg); got != tt.want {
+				t.Errorf("RequiresCGO() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequiresCGO_SkipsUnreadableFiles(t *testing.T) {
+	pkg := &packages.Package{GoFiles: []string{filepath.Join(t.TempDir(), "missing.go")}}
+	if RequiresCGO(pkg) {
+		t.Error("expected a missing file not to count as CGO")
+	}
+}
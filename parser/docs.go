@@ -0,0 +1,116 @@
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// ExportedDecl is an exported package-level declaration: a function, a method
+// of an exported type, a type, a constant or a variable.
+type ExportedDecl struct {
+	Name string
+	// Receiver is the receiver type name of methods, without pointer or type
+	// parameters, and empty otherwise.
+	Receiver string
+	// Kind is the declaring keyword, "func", "type", "const" or "var".
+	Kind       string
+	Documented bool
+	// Type is the signature of functions and methods, and nil otherwise.
+	Type *ast.FuncType
+}
+
+// ExportedDecls returns the exported declarations of pkg's non-test files in
+// source order, each with whether it has a doc comment. A spec inside a
+// grouped const, var or type block is documented by its own comment or by the
+// comment on the whole block, as godoc shows it.
+func ExportedDecls(pkg *packages.Package) []ExportedDecl {
+	var decls []ExportedDecl
+	for _, file := range pkg.Syntax {
+		if strings.HasSuffix(pkg.Fset.Position(file.Pos()).Filename, "_test.go") {
+			continue
+		}
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				receiver := receiverTypeName(decl)
+				if !decl.Name.IsExported() || (decl.Recv != nil && !ast.IsExported(receiver)) {
+					continue
+				}
+				decls = append(decls, ExportedDecl{Name: decl.Name.Name, Receiver: receiver, Kind: "func", Documented: decl.Doc != nil, Type: decl.Type})
+			case *ast.GenDecl:
+				if decl.Tok == token.IMPORT {
+					continue
+				}
+				for _, spec := range decl.Specs {
+					for _, name := range specNames(spec) {
+						if name.IsExported() {
+							decls = append(decls, ExportedDecl{Name: name.Name, Kind: decl.Tok.String(),
+								Documented: decl.Doc != nil || specDoc(spec) != nil})
+						}
+					}
+				}
+			}
+		}
+	}
+	return decls
+}
+
+// DocCoverage counts pkg's exported declarations and how many of them are
+// documented (see ExportedDecls).
+func DocCoverage(pkg *packages.Package) (documented, total int) {
+	for _, decl := range ExportedDecls(pkg) {
+		total++
+		if decl.Documented {
+			documented++
+		}
+	}
+	return documented, total
+}
+
+// receiverTypeName returns the base type name of a method's receiver, or ""
+// for plain functions.
+func receiverTypeName(decl *ast.FuncDecl) string {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return ""
+	}
+	receiverType := decl.Recv.List[0].Type
+	for {
+		switch expression := receiverType.(type) {
+		case *ast.StarExpr:
+			receiverType = expression.X
+		case *ast.IndexExpr:
+			receiverType = expression.X
+		case *ast.IndexListExpr:
+			receiverType = expression.X
+		case *ast.ParenExpr:
+			receiverType = expression.X
+		case *ast.Ident:
+			return expression.Name
+		default:
+			return ""
+		}
+	}
+}
+
+func specNames(spec ast.Spec) []*ast.Ident {
+	switch spec := spec.(type) {
+	case *ast.TypeSpec:
+		return []*ast.Ident{spec.Name}
+	case *ast.ValueSpec:
+		return spec.Names
+	}
+	return nil
+}
+
+func specDoc(spec ast.Spec) *ast.CommentGroup {
+	switch spec := spec.(type) {
+	case *ast.TypeSpec:
+		return spec.Doc
+	case *ast.ValueSpec:
+		return spec.Doc
+	}
+	return nil
+}
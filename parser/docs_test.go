@@ -0,0 +1,87 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExportedDecls(t *testing.T) {
+	testDir := t.TempDir()
+
+	goMod := filepath.Join(testDir, "go.mod")
+	if err := os.WriteFile(goMod, []byte("module testmod\n\ngo 1.24\n"), 0644); err != nil {
+		t.Fatalf("Failed to create go.mod: %v", err)
+	}
+
+	content := `package shapes
+
+// Colors of a shape.
+const (
+	Red = iota
+	Green
+)
+
+const (
+	// Small is documented on its own.
+	Small = 1
+	Large = 2
+	hidden = 3
+)
+
+// Square is documented.
+type Square struct{ Side float64 }
+
+func (s *Square) Area() float64 { return s.Side * s.Side }
+
+type circle struct{}
+
+func (circle) Area() float64 { return 0 }
+
+// New is documented.
+func New() Square { return Square{} }
+
+var Default, other Square
+`
+	if err := os.WriteFile(filepath.Join(testDir, "shapes.go"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create shapes.go: %v", err)
+	}
+	testContent := "package shapes\n\nfunc Helper() {}\n"
+	if err := os.WriteFile(filepath.Join(testDir, "shapes_test.go"), []byte(testContent), 0644); err != nil {
+		t.Fatalf("Failed to create shapes_test.go: %v", err)
+	}
+
+	pkgs, _, err := Load(testDir, true)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(pkgs) != 1 {
+		t.Fatalf("Expected 1 package, got %d", len(pkgs))
+	}
+
+	want := []ExportedDecl{
+		{Name: "Red", Kind: "const", Documented: true},
+		{Name: "Green", Kind: "const", Documented: true},
+		{Name: "Small", Kind: "const", Documented: true},
+		{Name: "Large", Kind: "const"},
+		{Name: "Square", Kind: "type", Documented: true},
+		{Name: "Area", Receiver: "Square", Kind: "func"},
+		{Name: "New", Kind: "func", Documented: true},
+		{Name: "Default", Kind: "var"},
+	}
+	got := ExportedDecls(pkgs[0])
+	for i := range got {
+		if (got[i].Kind == "func") != (got[i].Type != nil) {
+			t.Errorf("%s: Type = %v, want a signature for funcs only", got[i].Name, got[i].Type)
+		}
+		got[i].Type = nil
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExportedDecls() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if documented, total := DocCoverage(pkgs[0]); documented != 5 || total != 8 {
+		t.Errorf("DocCoverage() = %d, %d, want 5, 8", documented, total)
+	}
+}
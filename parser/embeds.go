@@ -0,0 +1,19 @@
+package parser
+
+import (
+	"slices"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// ExtractEmbeds returns the files embedded into pkg by //go:embed directives,
+// sorted. A directory pattern contributes every file it embeds, so the length
+// is the package's embedded file count. Requires NeedEmbedFiles.
+func ExtractEmbeds(pkg *packages.Package) []string {
+	if len(pkg.EmbedFiles) == 0 {
+		return nil
+	}
+	embedded := slices.Clone(pkg.EmbedFiles)
+	slices.Sort(embedded)
+	return slices.Compact(embedded)
+}
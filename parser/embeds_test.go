@@ -0,0 +1,41 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtractEmbeds(t *testing.T) {
+	testDir := t.TempDir()
+	files := map[string]string{
+		"go.mod":              "module testembed\n\ngo 1.24\n",
+		"assets/assets.go":    "package assets\n\nimport \"embed\"\n\n//go:embed static version.txt\nvar Files embed.FS\n",
+		"assets/version.txt":  "1.0\n",
+		"assets/static/a.css": "a{}\n",
+		"assets/static/b.js":  "b()\n",
+		"plain/plain.go":      "package plain\n",
+	}
+	for name, content := range files {
+		filePath := filepath.Join(testDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	pkgs, errorCount, err := Load(testDir, false)
+	if err != nil || errorCount > 0 {
+		t.Fatalf("Load() = %d errors, %v", errorCount, err)
+	}
+
+	counts := make(map[string]int)
+	for _, pkg := range pkgs {
+		counts[pkg.PkgPath] = len(ExtractEmbeds(pkg))
+	}
+	if counts["testembed/assets"] != 3 || counts["testembed/plain"] != 0 {
+		t.Errorf("embedded file counts = %v, want assets 3 and plain 0", counts)
+	}
+}
@@ -0,0 +1,80 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// PackageError is a packages.Error with its position split into parts and
+// the package it was reported on.
+type PackageError struct {
+	Package string
+	// File is empty, and Line and Col zero, for errors without a position.
+	File      string
+	Line, Col int
+	Msg       string
+	Kind      packages.ErrorKind
+}
+
+// String renders the error as packages.Error does, "file:line:col: msg" or
+// "-: msg" without a position, which is what packages.PrintErrors prints.
+func (e PackageError) String() string {
+	if e.File == "" {
+		return "-: " + e.Msg
+	}
+	position := e.File
+	if e.Line > 0 {
+		position += ":" + strconv.Itoa(e.Line)
+		if e.Col > 0 {
+			position += ":" + strconv.Itoa(e.Col)
+		}
+	}
+	return fmt.Sprintf("%s: %s", position, e.Msg)
+}
+
+// ExtractErrors returns the errors of pkgs and of the packages they import,
+// each package visited once, in the order packages.PrintErrors prints them:
+// dependencies before the packages importing them.
+func ExtractErrors(pkgs []*packages.Package) []PackageError {
+	var extracted []PackageError
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		for _, packageError := range pkg.Errors {
+			file, line, column := splitErrorPosition(packageError.Pos)
+			extracted = append(extracted, PackageError{Package: pkg.PkgPath, File: file, Line: line, Col: column,
+				Msg: packageError.Msg, Kind: packageError.Kind})
+		}
+	})
+	return extracted
+}
+
+// splitErrorPosition parses a packages.Error position, "file:line:col",
+// "file:line", "file" or empty or "-" when unknown.
+func splitErrorPosition(position string) (file string, line, column int) {
+	if position == "" || position == "-" {
+		return "", 0, 0
+	}
+	file = position
+	numbers := make([]int, 0, 2)
+	for range 2 {
+		separator := strings.LastIndex(file, ":")
+		if separator < 0 {
+			break
+		}
+		number, err := strconv.Atoi(file[separator+1:])
+		if err != nil {
+			break
+		}
+		numbers = append([]int{number}, numbers...)
+		file = file[:separator]
+	}
+	switch len(numbers) {
+	case 2:
+		return file, numbers[0], numbers[1]
+	case 1:
+		return file, numbers[0], 0
+	}
+	return file, 0, 0
+}
@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestExtractErrors(t *testing.T) {
+	dependency := &packages.Package{
+		PkgPath: "example.com/mod/dep",
+		Errors:  []packages.Error{{Pos: "-", Msg: "no Go files", Kind: packages.ListError}},
+	}
+	main := &packages.Package{
+		PkgPath: "example.com/mod/app",
+		Errors:  []packages.Error{{Pos: "/src/app/main.go:3:14", Msg: "expected ')'", Kind: packages.ParseError}},
+		Imports: map[string]*packages.Package{dependency.PkgPath: dependency},
+	}
+
+	got := ExtractErrors([]*packages.Package{main, dependency})
+	want := []PackageError{
+		{Package: "example.com/mod/dep", Msg: "no Go files", Kind: packages.ListError},
+		{Package: "example.com/mod/app", File: "/src/app/main.go", Line: 3, Col: 14, Msg: "expected ')'", Kind: packages.ParseError},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ExtractErrors() = %+v, want %+v", got, want)
+	}
+	for i, packageError := range []packages.Error{dependency.Errors[0], main.Errors[0]} {
+		if got[i].String() != packageError.Error() {
+			t.Errorf("String() = %q, want packages.Error's %q", got[i].String(), packageError.Error())
+		}
+	}
+}
+
+func TestSplitErrorPosition(t *testing.T) {
+	tests := []struct {
+		position     string
+		file         string
+		line, column int
+	}{
+		{position: "", file: ""},
+		{position: "-", file: ""},
+		{position: "a.go", file: "a.go"},
+		{position: "a.go:7", file: "a.go", line: 7},
+		{position: "C:/src/a.go:7:2", file: "C:/src/a.go", line: 7, column: 2},
+	}
+	for _, tt := range tests {
+		file, line, column := splitErrorPosition(tt.position)
+		if file != tt.file || line != tt.line || column != tt.column {
+			t.Errorf("splitErrorPosition(%q) = %q, %d, %d, want %q, %d, %d",
+				tt.position, file, line, column, tt.file, tt.line, tt.column)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package parser
+
+import (
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// GenericCounts summarizes a package's generics: the package-level generic
+// functions and types it declares, the constraint interfaces it defines and
+// the distinct instantiations in its files.
+type GenericCounts struct {
+	Functions int
+	Types     int
+	// ConstraintInterfaces counts interfaces that can only be used as
+	// constraints because they contain type elements, e.g. ~int | ~string.
+	ConstraintInterfaces int
+	// Instantiations counts distinct instantiations with concrete type
+	// arguments, DeferredInstantiations those inside generic code whose
+	// arguments involve type parameters.
+	Instantiations         int
+	DeferredInstantiations int
+}
+
+// GenericInstance is a distinct instantiation of a generic function or type.
+type GenericInstance struct {
+	// Symbol is the instantiated declaration, "path/to/pkg.Name".
+	Symbol string
+	// TypeArgs are the type arguments with package-qualified names.
+	TypeArgs []string
+	// Deferred is true when a type argument involves a type parameter, so
+	// the final instantiation depends on the enclosing generic code.
+	Deferred bool
+}
+
+// CountGenerics counts pkg's generic declarations and instantiations. Returns
+// zero counts when type information is unavailable.
+func CountGenerics(pkg *packages.Package) GenericCounts {
+	var counts GenericCounts
+	if pkg.Types == nil {
+		return counts
+	}
+
+	scope := pkg.Types.Scope()
+	for _, name := range scope.Names() {
+		switch object := scope.Lookup(name).(type) {
+		case *types.Func:
+			if object.Type().(*types.Signature).TypeParams().Len() > 0 {
+				counts.Functions++
+			}
+		case *types.TypeName:
+			if object.IsAlias() {
+				continue
+			}
+			if named, isNamed := object.Type().(*types.Named); isNamed && named.TypeParams().Len() > 0 {
+				counts.Types++
+			}
+			if iface, isInterface := object.Type().Underlying().(*types.Interface); isInterface && !iface.IsMethodSet() {
+				counts.ConstraintInterfaces++
+			}
+		}
+	}
+
+	for _, instance := range GenericInstances(pkg) {
+		if instance.Deferred {
+			counts.DeferredInstantiations++
+		} else {
+			counts.Instantiations++
+		}
+	}
+	return counts
+}
+
+// GenericInstances returns the distinct instantiations in pkg's files, from
+// TypesInfo.Instances, sorted by symbol and then type arguments. The same
+// symbol with the same type arguments counts once however often it appears.
+// Requires NeedTypesInfo.
+func GenericInstances(pkg *packages.Package) []GenericInstance {
+	if pkg.TypesInfo == nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var instances []GenericInstance
+	for ident, instance := range pkg.TypesInfo.Instances {
+		object := pkg.TypesInfo.Uses[ident]
+		if object == nil || object.Pkg() == nil {
+			continue
+		}
+		generic := GenericInstance{Symbol: object.Pkg().Path() + "." + object.Name()}
+		for i := range instance.TypeArgs.Len() {
+			typeArg := instance.TypeArgs.At(i)
+			generic.TypeArgs = append(generic.TypeArgs, types.TypeString(typeArg, nil))
+			generic.Deferred = generic.Deferred || containsTypeParam(typeArg, make(map[types.Type]bool))
+		}
+		key := generic.Symbol + "[" + strings.Join(generic.TypeArgs, ", ") + "]"
+		if !seen[key] {
+			seen[key] = true
+			instances = append(instances, generic)
+		}
+	}
+
+	sort.Slice(instances, func(i, j int) bool {
+		if instances[i].Symbol != instances[j].Symbol {
+			return instances[i].Symbol < instances[j].Symbol
+		}
+		return strings.Join(instances[i].TypeArgs, ",") < strings.Join(instances[j].TypeArgs, ",")
+	})
+	return instances
+}
+
+// containsTypeParam reports whether t mentions a type parameter anywhere in
+// its structure. visited stops recursion through self-referencing types.
+func containsTypeParam(t types.Type, visited map[types.Type]bool) bool {
+	if visited[t] {
+		return false
+	}
+	visited[t] = true
+
+	switch t := t.(type) {
+	case *types.TypeParam:
+		return true
+	case *types.Named:
+		for i := range t.TypeArgs().Len() {
+			if containsTypeParam(t.TypeArgs().At(i), visited) {
+				return true
+			}
+		}
+	case *types.Pointer:
+		return containsTypeParam(t.Elem(), visited)
+	case *types.Slice:
+		return containsTypeParam(t.Elem(), visited)
+	case *types.Array:
+		return containsTypeParam(t.Elem(), visited)
+	case *types.Chan:
+		return containsTypeParam(t.Elem(), visited)
+	case *types.Map:
+		return containsTypeParam(t.Key(), visited) || containsTypeParam(t.Elem(), visited)
+	case *types.Signature:
+		return containsTypeParam(t.Params(), visited) || containsTypeParam(t.Results(), visited)
+	case *types.Tuple:
+		for i := range t.Len() {
+			if containsTypeParam(t.At(i).Type(), visited) {
+				return true
+			}
+		}
+	case *types.Struct:
+		for i := range t.NumFields() {
+			if containsTypeParam(t.Field(i).Type(), visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
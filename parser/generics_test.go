@@ -0,0 +1,69 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCountGenerics(t *testing.T) {
+	testDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(testDir, "go.mod"), []byte("module testmod\n\ngo 1.24\n"), 0644); err != nil {
+		t.Fatalf("Failed to create go.mod: %v", err)
+	}
+
+	content := `package coll
+
+import "slices"
+
+type Number interface{ ~int | ~float64 }
+
+type Stringer interface{ String() string }
+
+type List[T any] []T
+
+type IntList = List[int]
+
+func Map[T, U any](items []T, f func(T) U) []U { return nil }
+
+func Sum[T Number](items []T) T {
+	Map(items, func(item T) T { return item })
+	return 0
+}
+
+func Use() {
+	_ = Sum([]int{1})
+	_ = Sum([]int{2})
+	_ = Sum([]float64{1})
+	_ = slices.Contains([]string{"a"}, "a")
+	var _ List[string]
+}
+`
+	if err := os.WriteFile(filepath.Join(testDir, "coll.go"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create coll.go: %v", err)
+	}
+
+	pkgs, _, err := Load(testDir, false)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	// IntList's List[int] is an instantiation too; Sum[int] is counted once.
+	want := GenericCounts{Functions: 2, Types: 1, ConstraintInterfaces: 1, Instantiations: 5, DeferredInstantiations: 1}
+	if got := CountGenerics(pkgs[0]); got != want {
+		t.Errorf("CountGenerics() = %+v, want %+v", got, want)
+	}
+
+	wantInstances := []GenericInstance{
+		{Symbol: "slices.Contains", TypeArgs: []string{"[]string", "string"}},
+		{Symbol: "testmod.List", TypeArgs: []string{"int"}},
+		{Symbol: "testmod.List", TypeArgs: []string{"string"}},
+		{Symbol: "testmod.Map", TypeArgs: []string{"T", "T"}, Deferred: true},
+		{Symbol: "testmod.Sum", TypeArgs: []string{"float64"}},
+		{Symbol: "testmod.Sum", TypeArgs: []string{"int"}},
+	}
+	if got := GenericInstances(pkgs[0]); !reflect.DeepEqual(got, wantInstances) {
+		t.Errorf("GenericInstances() = %+v, want %+v", got, wantInstances)
+	}
+}
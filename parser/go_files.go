@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ListGoFiles returns the absolute paths of the .go files under dir without
+// loading any package, in lexical order. It skips what the go command's
+// "./..." pattern skips: directories named testdata or vendor, files and
+// directories starting with "." or "_", and nested modules (subdirectories
+// with their own go.mod). Build constraints are not evaluated, so files for
+// other platforms are included.
+func ListGoFiles(dir string) ([]string, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var goFiles []string
+	err = filepath.WalkDir(root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := entry.Name()
+		if entry.IsDir() {
+			if filePath == root {
+				return nil
+			}
+			if ignoredName(name) || name == "testdata" || name == "vendor" || isModuleRoot(filePath) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !ignoredName(name) && strings.HasSuffix(name, ".go") {
+			goFiles = append(goFiles, filePath)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return goFiles, nil
+}
+
+func ignoredName(name string) bool {
+	return strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
+
+func isModuleRoot(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, "go.mod"))
+	return err == nil
+}
@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestListGoFiles(t *testing.T) {
+	testDir := t.TempDir()
+	files := []string{
+		"go.mod",
+		"main.go",
+		"main_test.go",
+		"notes.txt",
+		"api/api.go",
+		"api/api_linux.go",
+		"api/.hidden.go",
+		"api/_draft.go",
+		"api/testdata/fixture.go",
+		"vendor/dep/dep.go",
+		"_tools/gen.go",
+		".cache/x.go",
+		"nested/go.mod",
+		"nested/nested.go",
+	}
+	for _, name := range files {
+		filePath := filepath.Join(testDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(filePath, []byte("package x\n"), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	got, err := ListGoFiles(testDir)
+	if err != nil {
+		t.Fatalf("ListGoFiles() error = %v", err)
+	}
+	want := []string{
+		filepath.Join(testDir, "api", "api.go"),
+		filepath.Join(testDir, "api", "api_linux.go"),
+		filepath.Join(testDir, "main.go"),
+		filepath.Join(testDir, "main_test.go"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListGoFiles() = %v, want %v", got, want)
+	}
+
+	if _, err := ListGoFiles(filepath.Join(testDir, "missing")); err == nil {
+		t.Error("expected an error for a missing directory")
+	}
+}
@@ -0,0 +1,38 @@
+package parser
+
+import (
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// CountImportUses maps every import path of pkg to the number of distinct
+// objects of the imported package that pkg refers to, as resolved in
+// pkg.TypesInfo.Uses. Imports whose symbols are never referenced, such as
+// blank imports, map to 0. Returns nil without type information. Requires
+// NeedTypesInfo and NeedImports.
+func CountImportUses(pkg *packages.Package) map[string]int {
+	if pkg.TypesInfo == nil {
+		return nil
+	}
+
+	importPaths := make(map[string][]string, len(pkg.Imports))
+	for importPath, imported := range pkg.Imports {
+		importPaths[imported.PkgPath] = append(importPaths[imported.PkgPath], importPath)
+	}
+	counts := make(map[string]int, len(pkg.Imports))
+	for importPath := range pkg.Imports {
+		counts[importPath] = 0
+	}
+	seen := make(map[types.Object]bool)
+	for _, object := range pkg.TypesInfo.Uses {
+		if object.Pkg() == nil || seen[object] {
+			continue
+		}
+		seen[object] = true
+		for _, importPath := range importPaths[object.Pkg().Path()] {
+			counts[importPath]++
+		}
+	}
+	return counts
+}
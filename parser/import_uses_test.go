@@ -0,0 +1,50 @@
+package parser
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCountImportUses(t *testing.T) {
+	testDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(testDir, "go.mod"), []byte("module testmod\n\ngo 1.24\n"), 0644); err != nil {
+		t.Fatalf("Failed to create go.mod: %v", err)
+	}
+
+	content := `package text
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+)
+
+func Shout(s string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToUpper(s))
+	b.WriteString(strings.ToUpper("!"))
+	return fmt.Sprint(b.String())
+}
+`
+	if err := os.WriteFile(filepath.Join(testDir, "text.go"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create text.go: %v", err)
+	}
+
+	pkgs, _, err := Load(testDir, false)
+	if err != nil || len(pkgs) != 1 {
+		t.Fatalf("Load() = %d packages, %v", len(pkgs), err)
+	}
+	// strings.Builder, its WriteString and String methods and strings.ToUpper,
+	// used twice but counted once.
+	want := map[string]int{"embed": 0, "fmt": 1, "strings": 4}
+	if got := CountImportUses(pkgs[0]); !maps.Equal(got, want) {
+		t.Errorf("CountImportUses() = %v, want %v", got, want)
+	}
+
+	pkgs[0].TypesInfo = nil
+	if got := CountImportUses(pkgs[0]); got != nil {
+		t.Errorf("CountImportUses() without type information = %v, want nil", got)
+	}
+}
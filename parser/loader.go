@@ -1,18 +1,55 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"sort"
 	"strings"
 
 	"golang.org/x/tools/go/packages"
 )
 
+// ErrLoadFailed is returned by Load when go/packages cannot load the packages at all.
+var ErrLoadFailed = errors.New("failed to load packages")
+
+// Features selects what the loaded packages must support beyond names, files,
+// modules and imports, which are always loaded and suffice for a package
+// import graph. Parsing and type-checking dominate load time, so callers that
+// only need import edges should leave both unset.
+type Features struct {
+	// Syntax loads the parsed files with comments, needed by docs, source
+	// metrics, annotations, import aliases and test-only import detection.
+	Syntax bool
+	// Types loads type information, needed by function granularity and the
+	// analyses that resolve identifiers. It implies Syntax.
+	Types bool
+	// Concurrency, when positive, bounds how many packages go list builds at
+	// a time by passing it -p through GOFLAGS. Type-checking runs in this
+	// process and is left to go/packages.
+	Concurrency int
+}
+
+// AllFeatures is what Load and LoadQuiet request.
+var AllFeatures = Features{Syntax: true, Types: true}
+
+// LoadMode returns the packages.LoadMode providing features.
+func LoadMode(features Features) packages.LoadMode {
+	mode := packages.NeedName | packages.NeedFiles | packages.NeedEmbedFiles | packages.NeedModule | packages.NeedImports
+	if features.Syntax || features.Types {
+		mode |= packages.NeedSyntax
+	}
+	if features.Types {
+		mode |= packages.NeedTypes | packages.NeedTypesInfo
+	}
+	return mode
+}
+
 // Load parses all Go packages in targetDir and returns them with error count.
 // Returns error only for catastrophic failures (pattern parsing, driver issues).
-// Package-level parse errors are printed to stderr via packages.PrintErrors().
-// Each returned package contains Errors field with parse failures.
-// The error count returned is the number of packages with errors (from packages.PrintErrors).
+// Package-level parse errors are printed to stderr as packages.PrintErrors()
+// would, from ExtractErrors. Each returned package contains Errors field with
+// parse failures. The error count returned is the number of errors printed.
 //
 // Deduplication: When includeTests is true, go/packages returns both regular and test
 // variants of each package. This function deduplicates by keeping only the variant
@@ -24,30 +61,86 @@ import (
 // - All comment nodes in file: pkg.Syntax[i].Comments
 // - Function/type comments: Access via ast.Walk on pkg.Syntax[i]
 // Comments are preserved with NeedSyntax flag for future documentation analysis.
+// TypesInfo is loaded so analyses can resolve identifiers across packages.
 func Load(targetDir string, includeTests bool) ([]*packages.Package, int, error) {
+	pkgs, err := load(targetDir, includeTests, AllFeatures)
+	if err != nil {
+		return nil, 0, err
+	}
+	packageErrors := ExtractErrors(pkgs)
+	for _, packageError := range packageErrors {
+		fmt.Fprintln(os.Stderr, packageError)
+	}
+	return sortedPackages(pkgs), len(packageErrors), nil
+}
+
+// LoadQuiet is Load for callers that report package errors themselves: it
+// prints nothing and leaves the errors in each package's Errors field.
+// Patterns, resolved against targetDir, select the packages to load instead
+// of every package under it ("./...").
+func LoadQuiet(targetDir string, includeTests bool, patterns ...string) ([]*packages.Package, error) {
+	return LoadFeatures(targetDir, includeTests, AllFeatures, patterns...)
+}
+
+// LoadFeatures is LoadQuiet loading only what features need; packages loaded
+// without Syntax have nil Syntax, and without Types nil Types and TypesInfo.
+func LoadFeatures(targetDir string, includeTests bool, features Features, patterns ...string) ([]*packages.Package, error) {
+	pkgs, err := load(targetDir, includeTests, features, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	return sortedPackages(pkgs), nil
+}
+
+func load(targetDir string, includeTests bool, features Features, patterns ...string) ([]*packages.Package, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
 	cfg := &packages.Config{
-		Mode: packages.NeedName | packages.NeedFiles |
-			packages.NeedSyntax | packages.NeedImports | packages.NeedTypes,
+		Mode:  LoadMode(features),
 		Dir:   targetDir,
 		Tests: includeTests,
 	}
+	if features.Concurrency > 0 {
+		cfg.Env = goFlagsEnv(os.Environ(), features.Concurrency)
+	}
 
-	pkgs, err := packages.Load(cfg, "./...")
+	pkgs, err := packages.Load(cfg, patterns...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to load packages: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrLoadFailed, err)
 	}
+	return pkgs, nil
+}
 
-	errorCount := packages.PrintErrors(pkgs)
+// goFlagsEnv returns environ with GOFLAGS bounding go list to concurrency
+// parallel build actions, replacing any -p flag already set there.
+func goFlagsEnv(environ []string, concurrency int) []string {
+	var flags []string
+	env := make([]string, 0, len(environ)+1)
+	for _, variable := range environ {
+		value, isFlags := strings.CutPrefix(variable, "GOFLAGS=")
+		if !isFlags {
+			env = append(env, variable)
+			continue
+		}
+		for _, flag := range strings.Fields(value) {
+			if !strings.HasPrefix(flag, "-p=") && !strings.HasPrefix(flag, "--p=") {
+				flags = append(flags, flag)
+			}
+		}
+	}
+	flags = append(flags, fmt.Sprintf("-p=%d", concurrency))
+	return append(env, "GOFLAGS="+strings.Join(flags, " "))
+}
 
-	// Deduplicate packages and filter synthetic test packages
+// sortedPackages deduplicates pkgs and sorts them by import path for
+// deterministic output.
+func sortedPackages(pkgs []*packages.Package) []*packages.Package {
 	deduplicated := deduplicatePackages(pkgs)
-
-	// Sort packages by import path for deterministic output
 	sort.Slice(deduplicated, func(i, j int) bool {
 		return deduplicated[i].PkgPath < deduplicated[j].PkgPath
 	})
-
-	return deduplicated, errorCount, nil
+	return deduplicated
 }
 
 // deduplicatePackages removes duplicate package variants and synthetic test packages.
@@ -1,9 +1,14 @@
 package parser
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
+
+	"github.com/Desgue/codegraph/internal/testutil/repogen"
+	"golang.org/x/tools/go/packages"
 )
 
 func TestLoad_PreservesComments(t *testing.T) {
@@ -88,37 +93,9 @@ func TestLoad_EmptyDirectory(t *testing.T) {
 }
 
 func TestLoad_MultiplePackages(t *testing.T) {
-	testDir := t.TempDir()
-
-	goMod := filepath.Join(testDir, "go.mod")
-	modContent := "module testmod\n\ngo 1.24\n"
-	if err := os.WriteFile(goMod, []byte(modContent), 0644); err != nil {
-		t.Fatalf("Failed to create go.mod: %v", err)
-	}
+	repo := repogen.Generate(t, repogen.Config{Packages: 30, Topology: repogen.Chain, Generics: true})
 
-	// Create package 1
-	pkg1Dir := filepath.Join(testDir, "pkg1")
-	if err := os.Mkdir(pkg1Dir, 0755); err != nil {
-		t.Fatalf("Failed to create pkg1 directory: %v", err)
-	}
-	pkg1File := filepath.Join(pkg1Dir, "main.go")
-	pkg1Content := "package pkg1\n\nconst Name = \"pkg1\"\n"
-	if err := os.WriteFile(pkg1File, []byte(pkg1Content), 0644); err != nil {
-		t.Fatalf("Failed to create pkg1/main.go: %v", err)
-	}
-
-	// Create package 2
-	pkg2Dir := filepath.Join(testDir, "pkg2")
-	if err := os.Mkdir(pkg2Dir, 0755); err != nil {
-		t.Fatalf("Failed to create pkg2 directory: %v", err)
-	}
-	pkg2File := filepath.Join(pkg2Dir, "main.go")
-	pkg2Content := "package pkg2\n\nconst Name = \"pkg2\"\n"
-	if err := os.WriteFile(pkg2File, []byte(pkg2Content), 0644); err != nil {
-		t.Fatalf("Failed to create pkg2/main.go: %v", err)
-	}
-
-	pkgs, errorCount, err := Load(testDir, true)
+	pkgs, errorCount, err := Load(repo.Root, true)
 	if err != nil {
 		t.Fatalf("Load() error = %v", err)
 	}
@@ -127,13 +104,36 @@ func TestLoad_MultiplePackages(t *testing.T) {
 		t.Errorf("Expected 0 errors, got %d", errorCount)
 	}
 
-	if len(pkgs) != 2 {
-		t.Fatalf("Expected 2 packages, got %d", len(pkgs))
+	if len(pkgs) != len(repo.Packages) {
+		t.Fatalf("Expected %d packages, got %d", len(repo.Packages), len(pkgs))
 	}
 
 	// Verify packages are sorted by import path
-	if pkgs[0].PkgPath > pkgs[1].PkgPath {
-		t.Errorf("Packages not sorted: %s should come before %s", pkgs[0].PkgPath, pkgs[1].PkgPath)
+	for i := 1; i < len(pkgs); i++ {
+		if pkgs[i-1].PkgPath > pkgs[i].PkgPath {
+			t.Errorf("Packages not sorted: %s should come before %s", pkgs[i-1].PkgPath, pkgs[i].PkgPath)
+		}
+	}
+}
+
+func TestLoad_GeneratedSyntaxErrors(t *testing.T) {
+	repo := repogen.Generate(t, repogen.Config{Packages: 20, Topology: repogen.Star, SyntaxErrors: 3, TestFiles: true})
+
+	pkgs, errorCount, err := Load(repo.Root, true)
+	if err != nil {
+		t.Fatalf("Load() should not error on syntax errors, got %v", err)
+	}
+	if errorCount < len(repo.Broken) {
+		t.Errorf("Expected at least %d errors, got %d", len(repo.Broken), errorCount)
+	}
+	// Test packages are folded into the packages they test.
+	if len(pkgs) != len(repo.Packages) {
+		t.Fatalf("Expected %d packages, got %d", len(repo.Packages), len(pkgs))
+	}
+	for _, pkg := range pkgs {
+		if hasErrors, broken := len(pkg.Errors) > 0, slices.Contains(repo.Broken, pkg.PkgPath); hasErrors != broken {
+			t.Errorf("%s has errors %v, want errors only in %v", pkg.PkgPath, pkg.Errors, repo.Broken)
+		}
 	}
 }
 
@@ -169,6 +169,24 @@ func TestLoad_WithSyntaxErrors(t *testing.T) {
 	}
 }
 
+func TestLoadQuiet_KeepsErrorsOnPackages(t *testing.T) {
+	testDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(testDir, "go.mod"), []byte("module testmod\n\ngo 1.24\n"), 0644); err != nil {
+		t.Fatalf("Failed to create go.mod: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(testDir, "invalid.go"), []byte("package main\n\nfunc broken( {\n"), 0644); err != nil {
+		t.Fatalf("Failed to create invalid.go: %v", err)
+	}
+
+	pkgs, err := LoadQuiet(testDir, false)
+	if err != nil {
+		t.Fatalf("LoadQuiet() error = %v", err)
+	}
+	if len(pkgs) != 1 || len(pkgs[0].Errors) == 0 {
+		t.Errorf("expected one package carrying its errors, got %+v", pkgs)
+	}
+}
+
 func TestLoad_DeduplicationWithTests(t *testing.T) {
 	testDir := t.TempDir()
 
@@ -299,3 +317,94 @@ func TestLoad_IncludeTestsFalse(t *testing.T) {
 		t.Error("Test files should not be included when includeTests is false")
 	}
 }
+
+func TestLoad_ErrLoadFailed(t *testing.T) {
+	_, _, err := Load(filepath.Join(t.TempDir(), "missing"), false)
+	if !errors.Is(err, ErrLoadFailed) {
+		t.Fatalf("expected ErrLoadFailed, got %v", err)
+	}
+}
+
+func TestLoadMode(t *testing.T) {
+	base := packages.NeedName | packages.NeedFiles | packages.NeedEmbedFiles | packages.NeedModule | packages.NeedImports
+	tests := []struct {
+		name     string
+		features Features
+		want     packages.LoadMode
+	}{
+		{name: "imports only", features: Features{}, want: base},
+		{name: "syntax", features: Features{Syntax: true}, want: base | packages.NeedSyntax},
+		{name: "types imply syntax", features: Features{Types: true},
+			want: base | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo},
+		{name: "all", features: AllFeatures, want: base | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LoadMode(tt.features); got != tt.want {
+				t.Errorf("LoadMode(%+v) = %v, want %v", tt.features, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadFeatures_ImportsOnly(t *testing.T) {
+	repo := repogen.Generate(t, repogen.Config{Packages: 5, Topology: repogen.Star})
+
+	pkgs, err := LoadFeatures(repo.Root, false, Features{})
+	if err != nil {
+		t.Fatalf("LoadFeatures() error = %v", err)
+	}
+	if len(pkgs) != len(repo.Packages) {
+		t.Fatalf("Expected %d packages, got %d", len(repo.Packages), len(pkgs))
+	}
+	for _, pkg := range pkgs {
+		if pkg.Syntax != nil || pkg.Types != nil || pkg.TypesInfo != nil {
+			t.Errorf("%s was parsed or type-checked", pkg.PkgPath)
+		}
+		if got := len(pkg.Imports); got != len(repo.Imports[pkg.PkgPath]) {
+			t.Errorf("%s has %d imports, want %d", pkg.PkgPath, got, len(repo.Imports[pkg.PkgPath]))
+		}
+	}
+}
+
+// BenchmarkLoadFeatures compares an imports-only load with a full one.
+func BenchmarkLoadFeatures(b *testing.B) {
+	root := repogen.Generate(b, repogen.Config{
+		Packages: 300, Topology: repogen.RandomDAG, Density: 0.02, Seed: 1, FunctionsPerPackage: 5, Generics: true,
+	}).Root
+	for _, bench := range []struct {
+		name     string
+		features Features
+	}{
+		{name: "imports-only", features: Features{}},
+		{name: "all", features: AllFeatures},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			for b.Loop() {
+				if _, err := LoadFeatures(root, false, bench.features); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestGoFlagsEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		environ []string
+		want    []string
+	}{
+		{name: "no GOFLAGS", environ: []string{"HOME=/home/a"}, want: []string{"HOME=/home/a", "GOFLAGS=-p=2"}},
+		{name: "other flags kept", environ: []string{"GOFLAGS=-mod=mod -trimpath", "HOME=/home/a"},
+			want: []string{"HOME=/home/a", "GOFLAGS=-mod=mod -trimpath -p=2"}},
+		{name: "existing -p replaced", environ: []string{"GOFLAGS=-p=8 -mod=mod --p=4"}, want: []string{"GOFLAGS=-mod=mod -p=2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := goFlagsEnv(tt.environ, 2); !slices.Equal(got, tt.want) {
+				t.Errorf("goFlagsEnv() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
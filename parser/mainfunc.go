@@ -0,0 +1,25 @@
+package parser
+
+import (
+	"go/ast"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// HasMainFunc reports whether pkg declares a top-level func main(), which is
+// more precise than pkg.Name == "main" for identifying executable entry points.
+// Methods named main are ignored. Requires pkg.Syntax (NeedSyntax).
+func HasMainFunc(pkg *packages.Package) bool {
+	if pkg.Name != "main" {
+		return false
+	}
+	for _, file := range pkg.Syntax {
+		for _, declaration := range file.Decls {
+			function, isFunction := declaration.(*ast.FuncDecl)
+			if isFunction && function.Recv == nil && function.Name.Name == "main" {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package parser
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func parseTestPackage(t *testing.T, name string, sources ...string) *packages.Package {
+	t.Helper()
+
+	fileSet := token.NewFileSet()
+	pkg := &packages.Package{Name: name, PkgPath: "example.com/" + name, Fset: fileSet}
+	for _, source := range sources {
+		file, err := parser.ParseFile(fileSet, "", source, parser.ParseComments)
+		if err != nil {
+			t.Fatalf("failed to parse source: %v", err)
+		}
+		pkg.Syntax = append(pkg.Syntax, file)
+	}
+	return pkg
+}
+
+func TestHasMainFunc(t *testing.T) {
+	tests := []struct {
+		name    string
+		pkgName string
+		sources []string
+		want    bool
+	}{
+		{
+			name:    "main package with func main",
+			pkgName: "main",
+			sources: []string{"package main\n\nfunc helper() {}\n", "package main\n\nfunc main() {}\n"},
+			want:    true,
+		},
+		{
+			name:    "main package with only init",
+			pkgName: "main",
+			sources: []string{"package main\n\nfunc init() {}\n"},
+			want:    false,
+		},
+		{
+			name:    "method named main does not count",
+			pkgName: "main",
+			sources: []string{"package main\n\ntype app struct{}\n\nfunc (app) main() {}\n"},
+			want:    false,
+		},
+		{
+			name:    "library package with func main",
+			pkgName: "tools",
+			sources: []string{"package tools\n\nfunc main() {}\n"},
+			want:    false,
+		},
+		{
+			name:    "no syntax loaded",
+			pkgName: "main",
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := parseTestPackage(t, tt.pkgName, tt.sources...)
+			if got := HasMainFunc(pkg); got != tt.want {
+				t.Errorf("HasMainFunc() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package parser
+
+import (
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// protocGenGoHeader starts the header protoc-gen-go, and plugins named after
+// it such as protoc-gen-go-grpc, write at the top of generated files.
+const protocGenGoHeader = "// Code generated by protoc-gen-go"
+
+// protoreflectPath is the package defining protoreflect.Message, the result
+// of ProtoReflect on generated messages.
+const protoreflectPath = "google.golang.org/protobuf/reflect/protoreflect"
+
+// IsProtobufGenerated reports whether pkg is protoc-gen-go output: one of its
+// files starts with the protoc-gen-go header and one of its exported types
+// implements proto.Message, through ProtoReflect (APIv2) or ProtoMessage,
+// Reset and String (APIv1). Requiring both keeps packages that only hold gRPC
+// stubs, or hand-written wrappers around messages, out. Requires pkg.Syntax
+// and pkg.Types; without them the result is false.
+func IsProtobufGenerated(pkg *packages.Package) bool {
+	return hasProtocGenGoHeader(pkg) && definesProtoMessage(pkg)
+}
+
+// hasProtocGenGoHeader reports whether a comment above the package clause of
+// one of pkg.Syntax starts with protocGenGoHeader.
+func hasProtocGenGoHeader(pkg *packages.Package) bool {
+	for _, file := range pkg.Syntax {
+		for _, group := range file.Comments {
+			if group.Pos() >= file.Package {
+				break
+			}
+			for _, comment := range group.List {
+				if strings.HasPrefix(comment.Text, protocGenGoHeader) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// definesProtoMessage reports whether a pointer to one of pkg's exported
+// named types implements proto.Message, as generated messages do.
+func definesProtoMessage(pkg *packages.Package) bool {
+	if pkg.Types == nil {
+		return false
+	}
+	scope := pkg.Types.Scope()
+	for _, name := range scope.Names() {
+		typeName, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || !typeName.Exported() || typeName.IsAlias() {
+			continue
+		}
+		if implementsProtoMessage(types.NewMethodSet(types.NewPointer(typeName.Type()))) {
+			return true
+		}
+	}
+	return false
+}
+
+func implementsProtoMessage(methods *types.MethodSet) bool {
+	if result, ok := singleResult(methods, "ProtoReflect"); ok {
+		named, isNamed := types.Unalias(result).(*types.Named)
+		if isNamed && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == protoreflectPath && named.Obj().Name() == "Message" {
+			return true
+		}
+	}
+	stringResult, hasString := singleResult(methods, "String")
+	return hasString && types.Identical(stringResult, types.Typ[types.String]) &&
+		hasNiladicMethod(methods, "ProtoMessage") && hasNiladicMethod(methods, "Reset")
+}
+
+// singleResult returns the result type of the method name in methods when it
+// takes no parameters and returns one value.
+func singleResult(methods *types.MethodSet, name string) (types.Type, bool) {
+	signature, ok := niladicSignature(methods, name)
+	if !ok || signature.Results().Len() != 1 {
+		return nil, false
+	}
+	return signature.Results().At(0).Type(), true
+}
+
+// hasNiladicMethod reports whether methods has a method name that takes and
+// returns nothing.
+func hasNiladicMethod(methods *types.MethodSet, name string) bool {
+	signature, ok := niladicSignature(methods, name)
+	return ok && signature.Results().Len() == 0
+}
+
+func niladicSignature(methods *types.MethodSet, name string) (*types.Signature, bool) {
+	selection := methods.Lookup(nil, name)
+	if selection == nil {
+		return nil, false
+	}
+	signature, ok := selection.Type().(*types.Signature)
+	if !ok || signature.Params().Len() != 0 {
+		return nil, false
+	}
+	return signature, true
+}
@@ -0,0 +1,80 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestIsProtobufGenerated(t *testing.T) {
+	testDir := t.TempDir()
+
+	// The module stands in for google.golang.org/protobuf so the test runs
+	// offline; only protoreflect.Message's package path and name matter.
+	files := map[string]string{
+		"go.mod":                      "module google.golang.org/protobuf\n\ngo 1.24\n",
+		"reflect/protoreflect/pr.go":  "package protoreflect\n\ntype Message interface{ Interface() any }\n",
+		"gen/userpb/user.pb.go":       "// Code generated by protoc-gen-go. DO NOT EDIT.\n// source: user.proto\n\npackage userpb\n\nimport \"google.golang.org/protobuf/reflect/protoreflect\"\n\ntype User struct{}\n\nfunc (*User) ProtoReflect() protoreflect.Message { return nil }\n",
+		"gen/userpb/user_grpc.pb.go":  "// Code generated by protoc-gen-go-grpc. DO NOT EDIT.\n\npackage userpb\n\ntype UserServiceClient interface{}\n",
+		"gen/grpconly/svc_grpc.pb.go": "// Code generated by protoc-gen-go-grpc. DO NOT EDIT.\n\npackage grpconly\n\ntype ServiceClient interface{}\n",
+		"gen/legacypb/legacy.pb.go":   "// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage legacypb\n\ntype Legacy struct{}\n\nfunc (*Legacy) Reset()         {}\nfunc (*Legacy) String() string { return \"\" }\nfunc (*Legacy) ProtoMessage()  {}\n",
+		"gen/otherpb/other.go":        "// Code generated by stringer. DO NOT EDIT.\n\npackage otherpb\n\nimport \"google.golang.org/protobuf/reflect/protoreflect\"\n\ntype Other struct{}\n\nfunc (*Other) ProtoReflect() protoreflect.Message { return nil }\n",
+		"wrapper/wrapper.go":          "package wrapper\n\nimport \"google.golang.org/protobuf/reflect/protoreflect\"\n\ntype Wrapper struct{}\n\nfunc (Wrapper) ProtoReflect() protoreflect.Message { return nil }\n",
+		"gen/unexportedpb/x.pb.go":    "// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage unexportedpb\n\nimport \"google.golang.org/protobuf/reflect/protoreflect\"\n\ntype message struct{}\n\nfunc (*message) ProtoReflect() protoreflect.Message { return nil }\n",
+		"gen/lateheaderpb/late.pb.go": "package lateheaderpb\n\n// Code generated by protoc-gen-go. DO NOT EDIT.\n\nimport \"google.golang.org/protobuf/reflect/protoreflect\"\n\ntype Late struct{}\n\nfunc (*Late) ProtoReflect() protoreflect.Message { return nil }\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(testDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	pkgs, errorCount, err := Load(testDir, false)
+	if err != nil || errorCount > 0 {
+		t.Fatalf("Load() = %d errors, %v", errorCount, err)
+	}
+	byPath := make(map[string]*packages.Package, len(pkgs))
+	for _, pkg := range pkgs {
+		byPath[pkg.PkgPath] = pkg
+	}
+
+	tests := []struct {
+		name    string
+		pkgPath string
+		want    bool
+	}{
+		{name: "messages and gRPC stubs", pkgPath: "gen/userpb", want: true},
+		{name: "APIv1 messages", pkgPath: "gen/legacypb", want: true},
+		{name: "gRPC stubs only", pkgPath: "gen/grpconly", want: false},
+		{name: "another generator", pkgPath: "gen/otherpb", want: false},
+		{name: "hand-written message", pkgPath: "wrapper", want: false},
+		{name: "unexported message", pkgPath: "gen/unexportedpb", want: false},
+		{name: "header after the package clause", pkgPath: "gen/lateheaderpb", want: false},
+		{name: "protoreflect itself", pkgPath: "reflect/protoreflect", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := byPath["google.golang.org/protobuf/"+tt.pkgPath]
+			if pkg == nil {
+				t.Fatalf("package %s not loaded", tt.pkgPath)
+			}
+			if got := IsProtobufGenerated(pkg); got != tt.want {
+				t.Errorf("IsProtobufGenerated() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("without types", func(t *testing.T) {
+		pkg := *byPath["google.golang.org/protobuf/gen/userpb"]
+		pkg.Types = nil
+		if IsProtobufGenerated(&pkg) {
+			t.Error("IsProtobufGenerated() = true without type information, want false")
+		}
+	})
+}
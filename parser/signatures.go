@@ -0,0 +1,45 @@
+package parser
+
+import (
+	"go/ast"
+	"go/types"
+	"slices"
+)
+
+// SignatureCounts counts the parameters and results of a function signature.
+type SignatureCounts struct {
+	Params     int
+	Results    int
+	BoolParams int
+}
+
+// CountSignature counts the parameters, results and bool parameters of
+// funcType from its syntax, so it works without type information. A
+// variadic parameter counts as one, and parameters or results whose type is
+// written as one of exclude, e.g. "context.Context" or "error", are not
+// counted at all. Bool parameters are those declared as plain bool.
+func CountSignature(funcType *ast.FuncType, exclude []string) SignatureCounts {
+	var counts SignatureCounts
+	count := func(fields *ast.FieldList, each func(field *ast.Field, names int)) {
+		if fields == nil {
+			return
+		}
+		for _, field := range fields.List {
+			if slices.Contains(exclude, types.ExprString(field.Type)) {
+				continue
+			}
+			// Unnamed parameters and results are one per field.
+			each(field, max(len(field.Names), 1))
+		}
+	}
+	count(funcType.Params, func(field *ast.Field, names int) {
+		counts.Params += names
+		if ident, isIdent := field.Type.(*ast.Ident); isIdent && ident.Name == "bool" {
+			counts.BoolParams += names
+		}
+	})
+	count(funcType.Results, func(field *ast.Field, names int) {
+		counts.Results += names
+	})
+	return counts
+}
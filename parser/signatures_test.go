@@ -0,0 +1,39 @@
+package parser
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+func TestCountSignature(t *testing.T) {
+	tests := []struct {
+		name      string
+		signature string
+		exclude   []string
+		want      SignatureCounts
+	}{
+		{name: "named", signature: "func(a, b int, verbose, force bool) (n int, err error)",
+			want: SignatureCounts{Params: 4, Results: 2, BoolParams: 2}},
+		{name: "unnamed", signature: "func(int, bool) (int, string, error)",
+			want: SignatureCounts{Params: 2, Results: 3, BoolParams: 1}},
+		{name: "variadic counts once", signature: "func(format string, args ...any)",
+			want: SignatureCounts{Params: 2}},
+		{name: "excluded types", signature: "func(ctx context.Context, id string) (int, error)", exclude: []string{"context.Context", "error"},
+			want: SignatureCounts{Params: 1, Results: 1}},
+		{name: "empty", signature: "func()"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expression, err := parser.ParseExprFrom(token.NewFileSet(), "", tt.signature, 0)
+			if err != nil {
+				t.Fatalf("failed to parse %q: %v", tt.signature, err)
+			}
+			if got := CountSignature(expression.(*ast.FuncType), tt.exclude); got != tt.want {
+				t.Errorf("CountSignature(%q) = %+v, want %+v", tt.signature, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package parser
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var stdlib struct {
+	once  sync.Once
+	names map[string][]string
+	err   error
+}
+
+// StdlibPackageNames maps the package names of the running toolchain's
+// standard library to their import paths, e.g. "rand" to "crypto/rand",
+// "math/rand" and "math/rand/v2". Internal and vendored packages, which
+// callers cannot import, are left out. The list comes from `go list std`,
+// run on the first call and cached for the process, so it always matches the
+// Go version in use. The returned map is shared and must not be modified.
+func StdlibPackageNames() (map[string][]string, error) {
+	stdlib.once.Do(func() {
+		output, err := exec.Command("go", "list", "-f", "{{.ImportPath}} {{.Name}}", "std").Output()
+		if err != nil {
+			stdlib.err = fmt.Errorf("failed to list standard library packages: %w", err)
+			return
+		}
+		stdlib.names = parseStdlibList(string(output))
+	})
+	return stdlib.names, stdlib.err
+}
+
+// parseStdlibList reads "import/path name" lines as written by go list.
+func parseStdlibList(output string) map[string][]string {
+	names := make(map[string][]string)
+	for _, line := range strings.Split(output, "\n") {
+		importPath, name, found := strings.Cut(strings.TrimSpace(line), " ")
+		if !found || isHiddenStdlibPath(importPath) {
+			continue
+		}
+		names[name] = append(names[name], importPath)
+	}
+	for _, paths := range names {
+		sort.Strings(paths)
+	}
+	return names
+}
+
+func isHiddenStdlibPath(importPath string) bool {
+	for _, element := range strings.Split(importPath, "/") {
+		if element == "internal" || element == "vendor" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+package parser
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestParseStdlibList(t *testing.T) {
+	output := "crypto/rand rand\nerrors errors\ninternal/abi abi\nmath/rand rand\n" +
+		"net/http/internal/ascii ascii\nvendor/golang.org/x/net/idna idna\n\n"
+
+	want := map[string][]string{
+		"errors": {"errors"},
+		"rand":   {"crypto/rand", "math/rand"},
+	}
+	if got := parseStdlibList(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseStdlibList() = %v, want %v", got, want)
+	}
+}
+
+func TestStdlibPackageNames(t *testing.T) {
+	names, err := StdlibPackageNames()
+	if err != nil {
+		t.Fatalf("StdlibPackageNames() error = %v", err)
+	}
+	if !slices.Contains(names["errors"], "errors") || !slices.Contains(names["types"], "go/types") {
+		t.Errorf("expected errors and go/types, got errors=%v types=%v", names["errors"], names["types"])
+	}
+	if _, found := names["abi"]; found {
+		t.Errorf("internal packages must be left out, got abi=%v", names["abi"])
+	}
+}
@@ -0,0 +1,138 @@
+package parser
+
+import (
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Symbol kinds recorded in SymbolInfo.Kind.
+const (
+	SymbolFunc   = "func"
+	SymbolMethod = "method"
+	SymbolType   = "type"
+	SymbolVar    = "var"
+	SymbolConst  = "const"
+	SymbolField  = "field"
+)
+
+// SymbolInfo is where a symbol is declared.
+type SymbolInfo struct {
+	Package string `json:"package"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Col     int    `json:"col"`
+	Kind    string `json:"kind"`
+}
+
+// SymbolIndex maps the fully qualified name of every symbol the packages
+// declare, from pkg.TypesInfo.Defs, to its declarations: "import/path.Name"
+// for package-level functions, types, variables and constants and
+// "import/path.Type.Name" for methods and the fields of package-level struct
+// types. Local variables, parameters and the fields of anonymous structs have
+// no stable name and are left out. A name maps to several declarations when
+// a package declares it more than once, as with init functions. Declarations
+// are in package, then source, order. Requires NeedTypes and NeedTypesInfo.
+func SymbolIndex(pkgs []*packages.Package) map[string][]SymbolInfo {
+	fieldOwners := make(map[*types.Var]string)
+	for _, pkg := range pkgs {
+		if pkg.Types == nil {
+			continue
+		}
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			typeName, isTypeName := scope.Lookup(name).(*types.TypeName)
+			if !isTypeName {
+				continue
+			}
+			if structType, isStruct := typeName.Type().Underlying().(*types.Struct); isStruct {
+				for field := range structType.Fields() {
+					fieldOwners[field] = name
+				}
+			}
+		}
+	}
+
+	index := make(map[string][]SymbolInfo)
+	for _, pkg := range pkgs {
+		if pkg.TypesInfo == nil {
+			continue
+		}
+		var definitions []symbolDefinition
+		for ident, object := range pkg.TypesInfo.Defs {
+			if object == nil || object.Pkg() == nil || ident.Name == "_" {
+				continue
+			}
+			name, kind := symbolName(object, fieldOwners)
+			if name == "" {
+				continue
+			}
+			position := pkg.Fset.Position(ident.Pos())
+			definitions = append(definitions, symbolDefinition{name: name,
+				info: SymbolInfo{Package: pkg.PkgPath, File: position.Filename, Line: position.Line, Col: position.Column, Kind: kind}})
+		}
+		// Defs is a map, so restore source order.
+		sort.Slice(definitions, func(i, j int) bool {
+			a, b := definitions[i].info, definitions[j].info
+			if a.File != b.File {
+				return a.File < b.File
+			}
+			if a.Line != b.Line {
+				return a.Line < b.Line
+			}
+			return a.Col < b.Col
+		})
+		for _, definition := range definitions {
+			index[definition.name] = append(index[definition.name], definition.info)
+		}
+	}
+	return index
+}
+
+type symbolDefinition struct {
+	name string
+	info SymbolInfo
+}
+
+// symbolName returns the qualified name and kind of a declared object, or ""
+// for objects without a stable name.
+func symbolName(object types.Object, fieldOwners map[*types.Var]string) (string, string) {
+	pkgPath := object.Pkg().Path()
+	packageLevel := object.Parent() == object.Pkg().Scope()
+	switch object := object.(type) {
+	case *types.Func:
+		signature := object.Type().(*types.Signature)
+		if signature.Recv() == nil {
+			return pkgPath + "." + object.Name(), SymbolFunc
+		}
+		receiverType := signature.Recv().Type()
+		if pointer, isPointer := receiverType.(*types.Pointer); isPointer {
+			receiverType = pointer.Elem()
+		}
+		named, isNamed := receiverType.(*types.Named)
+		if !isNamed {
+			return "", ""
+		}
+		return pkgPath + "." + named.Obj().Name() + "." + object.Name(), SymbolMethod
+	case *types.TypeName:
+		if packageLevel {
+			return pkgPath + "." + object.Name(), SymbolType
+		}
+	case *types.Const:
+		if packageLevel {
+			return pkgPath + "." + object.Name(), SymbolConst
+		}
+	case *types.Var:
+		if object.IsField() {
+			if owner, found := fieldOwners[object]; found {
+				return pkgPath + "." + owner + "." + object.Name(), SymbolField
+			}
+			return "", ""
+		}
+		if packageLevel {
+			return pkgPath + "." + object.Name(), SymbolVar
+		}
+	}
+	return "", ""
+}
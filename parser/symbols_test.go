@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestSymbolIndex(t *testing.T) {
+	testDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(testDir, "go.mod"), []byte("module testmod\n\ngo 1.24\n"), 0644); err != nil {
+		t.Fatalf("Failed to create go.mod: %v", err)
+	}
+
+	content := `package store
+
+const Limit = 10
+
+var cache = map[string]int{}
+
+type Store struct {
+	Name string
+	opts struct{ verbose bool }
+}
+
+type List[T any] []T
+
+func (l *List[T]) Push(item T) {}
+
+func (s *Store) Get(key string) int {
+	value := cache[key]
+	return value
+}
+
+func init() {}
+
+func init() {}
+`
+	if err := os.WriteFile(filepath.Join(testDir, "store.go"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create store.go: %v", err)
+	}
+
+	pkgs, _, err := Load(testDir, false)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	index := SymbolIndex(pkgs)
+
+	wantKinds := map[string]string{
+		"testmod.Limit":      SymbolConst,
+		"testmod.cache":      SymbolVar,
+		"testmod.Store":      SymbolType,
+		"testmod.Store.Name": SymbolField,
+		"testmod.Store.opts": SymbolField,
+		"testmod.List":       SymbolType,
+		"testmod.List.Push":  SymbolMethod,
+		"testmod.Store.Get":  SymbolMethod,
+		"testmod.init":       SymbolFunc,
+	}
+	var names []string
+	for name := range index {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	if len(index) != len(wantKinds) {
+		t.Errorf("SymbolIndex() names = %v, want only package-level symbols, methods and fields", names)
+	}
+	for name, kind := range wantKinds {
+		if infos := index[name]; len(infos) == 0 || infos[0].Kind != kind {
+			t.Errorf("index[%q] = %+v, want kind %s", name, infos, kind)
+		}
+	}
+
+	get := index["testmod.Store.Get"][0]
+	if get.Package != "testmod" || filepath.Base(get.File) != "store.go" || get.Line != 16 || get.Col != 17 {
+		t.Errorf("Store.Get declared at %+v, want testmod store.go:16:17", get)
+	}
+	if inits := index["testmod.init"]; len(inits) != 2 || inits[0].Line != 21 || inits[1].Line != 23 {
+		t.Errorf("init declarations = %+v, want lines 21 and 23", inits)
+	}
+}
@@ -0,0 +1,47 @@
+package parser
+
+import (
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Test frameworks reported by DetectTestFramework.
+const (
+	TestFrameworkGinkgo   = "ginkgo"
+	TestFrameworkTestify  = "testify"
+	TestFrameworkGomock   = "gomock"
+	TestFrameworkStandard = "standard"
+)
+
+// testFrameworkImports maps import path prefixes to the framework they
+// belong to, in detection order: a ginkgo suite usually also imports gomega
+// or testify matchers, and gomock is mostly used next to another framework.
+var testFrameworkImports = []struct {
+	prefix    string
+	framework string
+}{
+	{"github.com/onsi/ginkgo", TestFrameworkGinkgo},
+	{"github.com/stretchr/testify", TestFrameworkTestify},
+	{"github.com/golang/mock", TestFrameworkGomock},
+	{"go.uber.org/mock", TestFrameworkGomock},
+}
+
+// DetectTestFramework returns the test framework pkg's imports point to:
+// "ginkgo", "testify" or "gomock" when one of their import paths (including
+// major versions such as ginkgo/v2) is imported, checked in that order,
+// "standard" when only "testing" is, and "" for packages without tests. Test
+// imports are only visible when the package was loaded with its tests.
+func DetectTestFramework(pkg *packages.Package) string {
+	for _, candidate := range testFrameworkImports {
+		for importPath := range pkg.Imports {
+			if importPath == candidate.prefix || strings.HasPrefix(importPath, candidate.prefix+"/") {
+				return candidate.framework
+			}
+		}
+	}
+	if _, found := pkg.Imports["testing"]; found {
+		return TestFrameworkStandard
+	}
+	return ""
+}
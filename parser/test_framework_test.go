@@ -0,0 +1,35 @@
+package parser
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestDetectTestFramework(t *testing.T) {
+	tests := []struct {
+		name    string
+		imports []string
+		want    string
+	}{
+		{name: "no tests", imports: []string{"fmt"}, want: ""},
+		{name: "standard", imports: []string{"fmt", "testing"}, want: TestFrameworkStandard},
+		{name: "testify subpackage", imports: []string{"testing", "github.com/stretchr/testify/require"}, want: TestFrameworkTestify},
+		{name: "gomock", imports: []string{"testing", "github.com/golang/mock/gomock"}, want: TestFrameworkGomock},
+		{name: "uber gomock fork", imports: []string{"testing", "go.uber.org/mock/gomock"}, want: TestFrameworkGomock},
+		{name: "ginkgo major version wins over testify", imports: []string{"testing", "github.com/onsi/ginkgo/v2", "github.com/stretchr/testify/assert"}, want: TestFrameworkGinkgo},
+		{name: "lookalike path", imports: []string{"testing", "github.com/stretchr/testifyish"}, want: TestFrameworkStandard},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := &packages.Package{Imports: make(map[string]*packages.Package)}
+			for _, importPath := range tt.imports {
+				pkg.Imports[importPath] = &packages.Package{PkgPath: importPath}
+			}
+			if got := DetectTestFramework(pkg); got != tt.want {
+				t.Errorf("DetectTestFramework() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
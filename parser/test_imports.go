@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// How a package uses an import, by which of its files declare it.
+const (
+	ImportProduction = "production"
+	ImportTestOnly   = "test_only"
+	ImportMixed      = "mixed"
+)
+
+// ClassifyImports maps every import path of pkg to ImportProduction when only
+// non-test files import it, ImportTestOnly when only _test.go files do and
+// ImportMixed when both do. Load keeps just the test variant of each package,
+// so the classification is computed from the syntax of its files. Returns nil
+// without syntax. Requires NeedSyntax.
+func ClassifyImports(pkg *packages.Package) map[string]string {
+	if pkg.Fset == nil {
+		return nil
+	}
+
+	usage := make(map[string]string)
+	for _, file := range pkg.Syntax {
+		fileUsage := ImportProduction
+		if strings.HasSuffix(pkg.Fset.Position(file.Pos()).Filename, "_test.go") {
+			fileUsage = ImportTestOnly
+		}
+		for _, importSpec := range file.Imports {
+			importPath, err := strconv.Unquote(importSpec.Path.Value)
+			if err != nil {
+				continue
+			}
+			if current, found := usage[importPath]; found && current != fileUsage {
+				usage[importPath] = ImportMixed
+			} else {
+				usage[importPath] = fileUsage
+			}
+		}
+	}
+	return usage
+}
+
+// TestOnlyImports returns the sorted import paths declared only in the
+// package's _test.go files, i.e. what the test variant imports beyond the
+// production variant (see ClassifyImports). Requires NeedSyntax.
+func TestOnlyImports(pkg *packages.Package) []string {
+	var testOnly []string
+	for importPath, usage := range ClassifyImports(pkg) {
+		if usage == ImportTestOnly {
+			testOnly = append(testOnly, importPath)
+		}
+	}
+	sort.Strings(testOnly)
+	return testOnly
+}
@@ -0,0 +1,48 @@
+package parser
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestTestOnlyImports(t *testing.T) {
+	sources := map[string]string{
+		"store.go":      "package store\n\nimport (\n\t\"fmt\"\n\t\"strings\"\n)\n",
+		"store_test.go": "package store\n\nimport (\n\t\"fmt\"\n\t\"testing\"\n\t\"example.com/mod/mocks\"\n)\n",
+		"cache_test.go": "package store\n\nimport \"testing\"\n",
+	}
+
+	fileSet := token.NewFileSet()
+	pkg := &packages.Package{PkgPath: "example.com/mod/store", Fset: fileSet}
+	for name, source := range sources {
+		file, err := parser.ParseFile(fileSet, name, source, parser.ImportsOnly)
+		if err != nil {
+			t.Fatalf("failed to parse %s: %v", name, err)
+		}
+		pkg.Syntax = append(pkg.Syntax, file)
+	}
+
+	wantUsage := map[string]string{
+		"fmt":                   ImportMixed,
+		"strings":               ImportProduction,
+		"testing":               ImportTestOnly,
+		"example.com/mod/mocks": ImportTestOnly,
+	}
+	if got := ClassifyImports(pkg); !reflect.DeepEqual(got, wantUsage) {
+		t.Errorf("ClassifyImports() = %v, want %v", got, wantUsage)
+	}
+
+	want := []string{"example.com/mod/mocks", "testing"}
+	if got := TestOnlyImports(pkg); !reflect.DeepEqual(got, want) {
+		t.Errorf("TestOnlyImports() = %v, want %v", got, want)
+	}
+
+	if got := TestOnlyImports(&packages.Package{Syntax: []*ast.File{}}); got != nil {
+		t.Errorf("expected nil without a file set, got %v", got)
+	}
+}
@@ -0,0 +1,52 @@
+package parser
+
+import (
+	"go/ast"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// HasTests reports whether pkg declares a test function in one of its
+// _test.go files: a top-level func named Test or TestXxx, where Xxx does not
+// start with a lowercase letter, as go test requires. Packages without a
+// _test.go file in pkg.GoFiles, including packages loaded without their
+// tests, have none. Requires pkg.Syntax (NeedSyntax).
+func HasTests(pkg *packages.Package) bool {
+	hasTestFile := false
+	for _, file := range pkg.GoFiles {
+		hasTestFile = hasTestFile || strings.HasSuffix(file, "_test.go")
+	}
+	if !hasTestFile || pkg.Fset == nil {
+		return false
+	}
+
+	found := false
+	for _, file := range pkg.Syntax {
+		if found || !strings.HasSuffix(pkg.Fset.Position(file.Pos()).Filename, "_test.go") {
+			continue
+		}
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.File:
+				return true
+			case *ast.FuncDecl:
+				found = found || node.Recv == nil && isTestFuncName(node.Name.Name)
+			}
+			// Test functions are top-level declarations.
+			return false
+		})
+	}
+	return found
+}
+
+func isTestFuncName(name string) bool {
+	suffix, found := strings.CutPrefix(name, "Test")
+	if !found {
+		return false
+	}
+	first, _ := utf8.DecodeRuneInString(suffix)
+	return suffix == "" || !unicode.IsLower(first)
+}
@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestHasTests(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  bool
+	}{
+		{
+			name:  "no test files",
+			files: map[string]string{"store.go": "package store\n\nfunc TestLike() {}\n"},
+			want:  false,
+		},
+		{
+			name: "test function",
+			files: map[string]string{
+				"store.go":      "package store\n",
+				"store_test.go": "package store\n\nimport \"testing\"\n\nfunc TestGet(t *testing.T) {}\n",
+			},
+			want: true,
+		},
+		{
+			name: "bare Test",
+			files: map[string]string{
+				"store_test.go": "package store\n\nimport \"testing\"\n\nfunc Test(t *testing.T) {}\n",
+			},
+			want: true,
+		},
+		{
+			name: "only helpers, benchmarks and lowercase suffixes",
+			files: map[string]string{
+				"store_test.go": "package store\n\nimport \"testing\"\n\n" +
+					"func Testing() {}\n\nfunc Testhelper(t *testing.T) {}\n\nfunc BenchmarkGet(b *testing.B) {}\n\n" +
+					"type suite struct{}\n\nfunc (suite) TestMethod(t *testing.T) {}\n",
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileSet := token.NewFileSet()
+			pkg := &packages.Package{Name: "store", Fset: fileSet}
+			for name, source := range tt.files {
+				file, err := parser.ParseFile(fileSet, "/src/store/"+name, source, 0)
+				if err != nil {
+					t.Fatalf("failed to parse %s: %v", name, err)
+				}
+				pkg.GoFiles = append(pkg.GoFiles, "/src/store/"+name)
+				pkg.Syntax = append(pkg.Syntax, file)
+			}
+			if got := HasTests(pkg); got != tt.want {
+				t.Errorf("HasTests() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package parser
+
+import (
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// CountTypes counts the package-level named types declared in pkg, split into
+// interfaces and concrete types. It is the input to the abstractness metric.
+// Returns zero counts when type information is unavailable.
+func CountTypes(pkg *packages.Package) (interfaces, concrete int) {
+	if pkg.Types == nil {
+		return 0, 0
+	}
+
+	scope := pkg.Types.Scope()
+	for _, name := range scope.Names() {
+		typeName, isTypeName := scope.Lookup(name).(*types.TypeName)
+		if !isTypeName {
+			continue
+		}
+		if _, isInterface := typeName.Type().Underlying().(*types.Interface); isInterface {
+			interfaces++
+		} else {
+			concrete++
+		}
+	}
+
+	return interfaces, concrete
+}
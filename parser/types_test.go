@@ -0,0 +1,73 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestCountTypes(t *testing.T) {
+	testDir := t.TempDir()
+
+	goMod := filepath.Join(testDir, "go.mod")
+	if err := os.WriteFile(goMod, []byte("module testmod\n\ngo 1.24\n"), 0644); err != nil {
+		t.Fatalf("Failed to create go.mod: %v", err)
+	}
+
+	content := `package shapes
+
+type Shape interface {
+	Area() float64
+}
+
+type Named interface{ Name() string }
+
+type Constraint interface{ ~int | ~float64 }
+
+type Square struct{ Side float64 }
+
+type Meters float64
+
+type List[T any] []T
+
+type ShapeAlias = Shape
+
+const Pi = 3.14
+
+var Default Square
+
+func New() Square { return Square{} }
+`
+	if err := os.WriteFile(filepath.Join(testDir, "shapes.go"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create shapes.go: %v", err)
+	}
+
+	pkgs, _, err := Load(testDir, false)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(pkgs) != 1 {
+		t.Fatalf("Expected 1 package, got %d", len(pkgs))
+	}
+
+	interfaces, concrete := CountTypes(pkgs[0])
+
+	// Shape, Named, Constraint and the alias to Shape
+	if interfaces != 4 {
+		t.Errorf("interfaces = %d, want 4", interfaces)
+	}
+	// Square, Meters, List
+	if concrete != 3 {
+		t.Errorf("concrete = %d, want 3", concrete)
+	}
+}
+
+func TestCountTypes_NoTypeInformation(t *testing.T) {
+	interfaces, concrete := CountTypes(&packages.Package{PkgPath: "example.com/empty"})
+
+	if interfaces != 0 || concrete != 0 {
+		t.Errorf("CountTypes() = (%d, %d), want (0, 0)", interfaces, concrete)
+	}
+}
@@ -0,0 +1,10 @@
+package parser
+
+import "golang.org/x/tools/go/packages"
+
+// UsesUnsafe reports whether pkg imports the unsafe package, which bypasses
+// Go's type and memory safety and warrants review in security audits.
+// Requires pkg.Imports (NeedImports).
+func UsesUnsafe(pkg *packages.Package) bool {
+	return pkg.Imports["unsafe"] != nil
+}
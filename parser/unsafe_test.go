@@ -0,0 +1,27 @@
+package parser
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestUsesUnsafe(t *testing.T) {
+	tests := []struct {
+		name    string
+		imports map[string]*packages.Package
+		want    bool
+	}{
+		{name: "no imports", want: false},
+		{name: "imports unsafe", imports: map[string]*packages.Package{"fmt": {PkgPath: "fmt"}, "unsafe": {PkgPath: "unsafe"}}, want: true},
+		{name: "path ending in unsafe", imports: map[string]*packages.Package{"example.com/unsafe": {PkgPath: "example.com/unsafe"}}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := &packages.Package{PkgPath: "example.com/p", Imports: tt.imports}
+			if got := UsesUnsafe(pkg); got != tt.want {
+				t.Errorf("UsesUnsafe() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
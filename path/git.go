@@ -0,0 +1,23 @@
+package path
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// IsInGitRepo reports whether the directory is inside a git work tree and
+// returns the repository root. It runs `git rev-parse --show-toplevel` once and
+// caches the result; when git is missing or the directory is not in a
+// repository, ok is false.
+func (td *TargetDirectory) IsInGitRepo() (repoRoot string, ok bool) {
+	td.gitRepoOnce.Do(func() {
+		command := exec.Command("git", "rev-parse", "--show-toplevel")
+		command.Dir = td.Path
+		output, err := command.Output()
+		if err != nil {
+			return
+		}
+		td.gitRepoRoot = strings.TrimSpace(string(output))
+	})
+	return td.gitRepoRoot, td.gitRepoRoot != ""
+}
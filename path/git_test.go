@@ -0,0 +1,44 @@
+package path
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsInGitRepo_InsideRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	workingDirectory, err := os.Getwd()
+	require.NoError(t, err)
+	if err := exec.Command("git", "-C", workingDirectory, "rev-parse").Run(); err != nil {
+		t.Skip("tests are not running inside a git checkout")
+	}
+
+	targetDirectory, err := NewTargetDirectory(workingDirectory)
+	require.NoError(t, err)
+
+	repoRoot, ok := targetDirectory.IsInGitRepo()
+
+	require.True(t, ok)
+	expectedRoot, err := filepath.EvalSymlinks(filepath.Dir(workingDirectory))
+	require.NoError(t, err)
+	assert.Equal(t, expectedRoot, repoRoot)
+}
+
+func TestIsInGitRepo_OutsideRepository(t *testing.T) {
+	targetDirectory, err := NewTargetDirectory(t.TempDir())
+	require.NoError(t, err)
+	// Stop git from discovering a repository above the temp directory.
+	t.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(targetDirectory.Path))
+
+	repoRoot, ok := targetDirectory.IsInGitRepo()
+
+	assert.False(t, ok)
+	assert.Empty(t, repoRoot)
+}
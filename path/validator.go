@@ -1,13 +1,24 @@
 package path
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+)
+
+// Errors returned by NewTargetDirectory and Validate, for use with errors.Is.
+var (
+	ErrNotExist     = errors.New("directory does not exist")
+	ErrNotDirectory = errors.New("not a directory")
 )
 
 type TargetDirectory struct {
 	Path string
+
+	gitRepoOnce sync.Once
+	gitRepoRoot string
 }
 
 func NewTargetDirectory(inputPath string) (*TargetDirectory, error) {
@@ -29,6 +40,9 @@ func NewTargetDirectory(inputPath string) (*TargetDirectory, error) {
 
 	canonicalPath, err := filepath.EvalSymlinks(resolvedPath)
 	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: failed to resolve symlinks for '%s': %w", ErrNotExist, resolvedPath, err)
+		}
 		return nil, fmt.Errorf("failed to resolve symlinks for '%s': %w", resolvedPath, err)
 	}
 
@@ -47,7 +61,7 @@ func (td *TargetDirectory) Validate() error {
 	fileInfo, err := os.Stat(td.Path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return fmt.Errorf("directory does not exist: %s", td.Path)
+			return fmt.Errorf("%w: %s", ErrNotExist, td.Path)
 		}
 		if os.IsPermission(err) {
 			return fmt.Errorf("permission denied accessing '%s'", td.Path)
@@ -56,7 +70,7 @@ func (td *TargetDirectory) Validate() error {
 	}
 
 	if !fileInfo.IsDir() {
-		return fmt.Errorf("'%s' is a file, not a directory", td.Path)
+		return fmt.Errorf("'%s' is a file, %w", td.Path, ErrNotDirectory)
 	}
 
 	return nil
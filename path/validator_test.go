@@ -57,9 +57,8 @@ func TestNewTargetDirectory_NonexistentDirectory(t *testing.T) {
 	targetDirectory, err := NewTargetDirectory(nonexistentPath)
 
 	assert.Nil(t, targetDirectory)
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "failed to resolve symlinks")
-	assert.Contains(t, err.Error(), "no such file or directory")
+	require.ErrorIs(t, err, ErrNotExist)
+	assert.Contains(t, err.Error(), nonexistentPath)
 }
 
 func TestNewTargetDirectory_FileInsteadOfDirectory(t *testing.T) {
@@ -71,8 +70,7 @@ func TestNewTargetDirectory_FileInsteadOfDirectory(t *testing.T) {
 	targetDirectory, err := NewTargetDirectory(tempFile)
 
 	assert.Nil(t, targetDirectory)
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "is a file, not a directory")
+	require.ErrorIs(t, err, ErrNotDirectory)
 	assert.Contains(t, err.Error(), tempFile)
 }
 
@@ -107,8 +105,7 @@ func TestNewTargetDirectory_SymbolicLinkToFile(t *testing.T) {
 	targetDirectory, err := NewTargetDirectory(symlinkPath)
 
 	assert.Nil(t, targetDirectory)
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "is a file, not a directory")
+	require.ErrorIs(t, err, ErrNotDirectory)
 }
 
 func TestNewTargetDirectory_PathWithSpaces(t *testing.T) {